@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// client calls the API through the gateway, as the user whose access token it holds
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newClient(baseURL string) *client {
+	return &client{baseURL: baseURL, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// as returns a client calling with token
+func (c *client) as(token string) *client {
+	return &client{baseURL: c.baseURL, token: token, http: c.http}
+}
+
+// statusError is a response with an unexpected status; body holds the error the service returned
+type statusError struct {
+	method, path string
+	status       int
+	body         string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: status %d: %s", e.method, e.path, e.status, e.body)
+}
+
+// call sends body as JSON and decodes the response into out, the data of it from the services
+// answering in an envelope. A status other than want, or 200 when want is empty, is a *statusError.
+func (c *client) call(ctx context.Context, method, path string, body, out any, want ...int) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if len(want) == 0 {
+		want = []int{http.StatusOK}
+	}
+	if !slices.Contains(want, res.StatusCode) {
+		return &statusError{method: method, path: path, status: res.StatusCode, body: string(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta struct {
+			Version int `json:"version"`
+		} `json:"meta"`
+	}
+	if json.Unmarshal(data, &envelope) == nil && envelope.Meta.Version > 0 {
+		data = envelope.Data
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *client) get(ctx context.Context, path string, out any, want ...int) error {
+	return c.call(ctx, http.MethodGet, path, nil, out, want...)
+}
+
+func (c *client) post(ctx context.Context, path string, body, out any, want ...int) error {
+	return c.call(ctx, http.MethodPost, path, body, out, want...)
+}
+
+func (c *client) put(ctx context.Context, path string, body, out any, want ...int) error {
+	return c.call(ctx, http.MethodPut, path, body, out, want...)
+}
+
+// waitReady polls the readiness of the gateway and of the services behind it until every probe
+// answers without a gateway error or timeout passes
+func (c *client) waitReady(ctx context.Context, timeout time.Duration, probes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, path := range probes {
+		for {
+			err := c.get(ctx, path, nil, http.StatusOK, http.StatusUnauthorized, http.StatusForbidden)
+			if err == nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("waiting for %s: %w", path, err)
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}
+	return nil
+}
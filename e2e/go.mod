@@ -0,0 +1,3 @@
+module ecommerce-microservice-go/e2e
+
+go 1.24.2
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// scenario is one scripted flow through the API; run returns the first failed assertion
+type scenario struct {
+	name string
+	run  func(ctx context.Context, e *env) error
+}
+
+// env is what every scenario starts from: the gateway client, a signed in admin and a suffix
+// that keeps the users, SKUs and tracking numbers of a run apart from earlier runs
+type env struct {
+	api   *client
+	admin *client
+	run   string
+}
+
+var scenarios = []scenario{
+	{name: "register, login, create product, checkout, pay and ship", run: checkoutAndShip},
+	{name: "permissions are enforced through the gateway", run: permissions},
+}
+
+type user struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type loginResponse struct {
+	User     user `json:"user"`
+	Security struct {
+		JWTAccessToken string `json:"jwtAccessToken"`
+	} `json:"security"`
+}
+
+type category struct {
+	ID int `json:"id"`
+}
+
+type product struct {
+	ID       int     `json:"id"`
+	SKU      string  `json:"sku"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	IsActive bool    `json:"isActive"`
+}
+
+type order struct {
+	ID          int     `json:"id"`
+	UserID      int     `json:"userId"`
+	Status      string  `json:"status"`
+	TotalAmount float64 `json:"totalAmount"`
+	Currency    string  `json:"currency"`
+}
+
+type saga struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	State  struct {
+		OrderID int `json:"orderId"`
+	} `json:"state"`
+	LastError string `json:"lastError"`
+}
+
+type payment struct {
+	OrderID  int     `json:"orderId"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Status   string  `json:"status"`
+}
+
+type shipment struct {
+	OrderID        int    `json:"orderId"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"trackingNumber"`
+}
+
+// login signs in and returns a client calling as that user
+func login(ctx context.Context, api *client, email, password string) (*client, user, error) {
+	var res loginResponse
+	if err := api.post(ctx, "/v1/auth/login", map[string]string{"email": email, "password": password}, &res); err != nil {
+		return nil, user{}, err
+	}
+	if res.Security.JWTAccessToken == "" {
+		return nil, user{}, errors.New("login returned no access token")
+	}
+	return api.as(res.Security.JWTAccessToken), res.User, nil
+}
+
+// registerCustomer signs up a new customer and signs them in
+func registerCustomer(ctx context.Context, e *env, name string) (*client, user, error) {
+	email := fmt.Sprintf("%s-%s@e2e.example.com", name, e.run)
+	const password = "e2e-password-1"
+	var registered user
+	body := map[string]string{"userName": name + e.run, "email": email, "password": password, "firstName": name}
+	if err := e.api.post(ctx, "/v1/auth/register", body, &registered); err != nil {
+		return nil, user{}, fmt.Errorf("register: %w", err)
+	}
+	customer, me, err := login(ctx, e.api, email, password)
+	if err != nil {
+		return nil, user{}, fmt.Errorf("login: %w", err)
+	}
+	if me.ID != registered.ID || me.Role != "customer" {
+		return nil, user{}, fmt.Errorf("login: signed in as user %d with role %q, want user %d as customer", me.ID, me.Role, registered.ID)
+	}
+	return customer, me, nil
+}
+
+// createProduct adds an active product in a new category as the admin
+func createProduct(ctx context.Context, e *env, price float64, stock int) (product, error) {
+	var cat category
+	err := e.admin.post(ctx, "/v1/category/", map[string]string{"name": "E2E " + e.run, "slug": "e2e-" + e.run}, &cat)
+	if err != nil {
+		return product{}, fmt.Errorf("create category: %w", err)
+	}
+	var p product
+	err = e.admin.post(ctx, "/v1/product/", map[string]any{
+		"name": "E2E product " + e.run, "sku": "E2E-" + e.run, "price": price, "stock": stock, "categoryId": cat.ID, "isActive": true,
+	}, &p)
+	if err != nil {
+		return product{}, fmt.Errorf("create product: %w", err)
+	}
+	return p, nil
+}
+
+func checkoutAndShip(ctx context.Context, e *env) error {
+	customer, me, err := registerCustomer(ctx, e, "buyer")
+	if err != nil {
+		return err
+	}
+	created, err := createProduct(ctx, e, 25.5, 10)
+	if err != nil {
+		return err
+	}
+	var p product
+	if err := customer.get(ctx, "/v1/product/"+strconv.Itoa(created.ID), &p); err != nil {
+		return fmt.Errorf("get product: %w", err)
+	}
+	if !p.IsActive || p.Price != 25.5 || p.Stock != 10 {
+		return fmt.Errorf("get product: got %+v, want an active product at 25.5 with 10 in stock", p)
+	}
+
+	address := map[string]string{"name": "E2E Buyer", "line1": "1 Test Street", "city": "Jakarta", "postalCode": "10110", "country": "ID"}
+	o, err := checkout(ctx, customer, map[string]any{
+		"items":           []map[string]any{{"productId": p.ID, "quantity": 2, "price": p.Price}},
+		"shippingAddress": address,
+		"billingAddress":  address,
+	})
+	if err != nil {
+		return err
+	}
+	if o.UserID != me.ID || o.Status != "paid" || !equalAmount(o.TotalAmount, 51) {
+		return fmt.Errorf("checkout: got order %+v, want a paid order of 51 for user %d", o, me.ID)
+	}
+
+	var payments []payment
+	if err := customer.get(ctx, "/v1/payment/order/"+strconv.Itoa(o.ID), &payments); err != nil {
+		return fmt.Errorf("get payments: %w", err)
+	}
+	if len(payments) != 1 || payments[0].Status != "succeeded" || !equalAmount(payments[0].Amount, o.TotalAmount) {
+		return fmt.Errorf("get payments: got %+v, want one succeeded payment of %.2f", payments, o.TotalAmount)
+	}
+	if err := customer.get(ctx, "/v1/product/"+strconv.Itoa(p.ID), &p); err != nil {
+		return fmt.Errorf("get product after checkout: %w", err)
+	}
+	if p.Stock != 8 {
+		return fmt.Errorf("get product after checkout: stock is %d, want 8", p.Stock)
+	}
+
+	var s shipment
+	tracking := "E2E" + e.run
+	if err := e.admin.put(ctx, fmt.Sprintf("/v1/order/%d/shipment", o.ID), map[string]string{"carrier": "e2epost", "trackingNumber": tracking}, &s); err != nil {
+		return fmt.Errorf("ship order: %w", err)
+	}
+	if s.OrderID != o.ID || s.TrackingNumber != tracking {
+		return fmt.Errorf("ship order: got %+v, want tracking number %s for order %d", s, tracking, o.ID)
+	}
+	var shipped order
+	if err := customer.get(ctx, "/v1/order/"+strconv.Itoa(o.ID), &shipped); err != nil {
+		return fmt.Errorf("get order: %w", err)
+	}
+	if shipped.Status != "shipped" {
+		return fmt.Errorf("get order: status is %q, want shipped", shipped.Status)
+	}
+	return nil
+}
+
+// checkout starts a checkout saga and, when a step is retried in the background, polls it until
+// it finishes
+func checkout(ctx context.Context, customer *client, body map[string]any) (order, error) {
+	var s saga
+	if err := customer.post(ctx, "/v1/sagas/checkout", body, &s, http.StatusOK, http.StatusAccepted); err != nil {
+		return order{}, fmt.Errorf("checkout: %w", err)
+	}
+	for deadline := time.Now().Add(time.Minute); s.Status == "running" || s.Status == "compensating"; {
+		if time.Now().After(deadline) {
+			return order{}, fmt.Errorf("checkout: saga %d is still %s: %s", s.ID, s.Status, s.LastError)
+		}
+		time.Sleep(time.Second)
+		if err := customer.get(ctx, "/v1/sagas/"+strconv.Itoa(s.ID), &s); err != nil {
+			return order{}, fmt.Errorf("get saga: %w", err)
+		}
+	}
+	if s.Status != "completed" || s.State.OrderID == 0 {
+		return order{}, fmt.Errorf("checkout: saga %d ended %s: %s", s.ID, s.Status, s.LastError)
+	}
+	var o order
+	if err := customer.get(ctx, "/v1/order/"+strconv.Itoa(s.State.OrderID), &o); err != nil {
+		return order{}, fmt.Errorf("get order: %w", err)
+	}
+	return o, nil
+}
+
+func permissions(ctx context.Context, e *env) error {
+	if err := e.api.get(ctx, "/v1/order/", nil, http.StatusUnauthorized); err != nil {
+		return fmt.Errorf("list orders without a token: %w", err)
+	}
+	customer, _, err := registerCustomer(ctx, e, "visitor")
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"name": "Not allowed", "sku": "E2E-DENIED-" + e.run, "price": 1, "categoryId": 1}
+	if err := customer.post(ctx, "/v1/product/", body, nil, http.StatusForbidden); err != nil {
+		return fmt.Errorf("create product as a customer: %w", err)
+	}
+	if err := customer.get(ctx, "/v1/user/", nil, http.StatusForbidden); err != nil {
+		return fmt.Errorf("list users as a customer: %w", err)
+	}
+	return nil
+}
+
+// equalAmount compares money amounts, which the services round to cents
+func equalAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
@@ -0,0 +1,40 @@
+// Checkout: customers buying one item, which reserves stock in the catalog, charges the
+// payment and places the order through the checkout saga.
+//
+// Baseline: p95 under 1500ms at 10 virtual users, with under 1% errors.
+import { check, sleep } from 'k6';
+import { ADMIN_EMAIL, ADMIN_PASSWORD, createProduct, login, post, registerCustomer } from './lib.js';
+
+export const options = {
+  scenarios: {
+    checkout: {
+      executor: 'constant-vus',
+      vus: 10,
+      duration: '1m',
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:checkout}': ['p(95)<1500'],
+    checks: ['rate>0.99'],
+  },
+};
+
+const address = { name: 'Load Test', line1: '1 Test Street', city: 'Jakarta', postalCode: '10110', country: 'ID' };
+
+// setup creates a product with enough stock for the whole run and a customer buying it
+export function setup() {
+  const productId = createProduct(login(ADMIN_EMAIL, ADMIN_PASSWORD), 100000);
+  const customer = registerCustomer('loadtest');
+  return { productId, token: login(customer.email, customer.password) };
+}
+
+export default function (data) {
+  const res = post('/v1/sagas/checkout', {
+    items: [{ productId: data.productId, quantity: 1, price: 25.5 }],
+    shippingAddress: address,
+    billingAddress: address,
+  }, data.token, { name: 'checkout' });
+  check(res, { 'checkout: accepted': (r) => r.status === 200 || r.status === 202 });
+  sleep(1);
+}
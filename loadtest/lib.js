@@ -0,0 +1,55 @@
+// Helpers shared by the load-test scenarios. They run against a stack started with
+// "make e2e" or "make up", through the gateway at BASE_URL.
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+export const BASE_URL = (__ENV.BASE_URL || 'http://localhost:9090').replace(/\/$/, '');
+export const ADMIN_EMAIL = __ENV.START_USER_EMAIL || 'admin@example.com';
+export const ADMIN_PASSWORD = __ENV.START_USER_PW || 'admin123';
+
+const json = { headers: { 'Content-Type': 'application/json' } };
+
+export function post(path, body, token, tags) {
+  const params = { headers: { ...json.headers }, tags };
+  if (token) {
+    params.headers.Authorization = `Bearer ${token}`;
+  }
+  return http.post(`${BASE_URL}${path}`, JSON.stringify(body), params);
+}
+
+// login signs in and returns the access token, failing the scenario when it cannot
+export function login(email, password) {
+  const res = post('/v1/auth/login', { email, password }, null, { name: 'login' });
+  if (!check(res, { 'login: 200': (r) => r.status === 200 })) {
+    fail(`login as ${email}: status ${res.status}: ${res.body}`);
+  }
+  return res.json('data.security.jwtAccessToken');
+}
+
+// registerCustomer signs up a customer unique to this run and returns their credentials
+export function registerCustomer(name) {
+  const run = Date.now().toString(36);
+  const email = `${name}-${run}@loadtest.example.com`;
+  const password = 'loadtest-password-1';
+  const res = post('/v1/auth/register', { userName: name + run, email, password, firstName: name });
+  if (res.status !== 200 && res.status !== 201) {
+    fail(`register ${email}: status ${res.status}: ${res.body}`);
+  }
+  return { email, password };
+}
+
+// createProduct adds an active product in a new category as the admin and returns its ID
+export function createProduct(adminToken, stock) {
+  const run = Date.now().toString(36);
+  const category = post('/v1/category/', { name: `Load test ${run}`, slug: `loadtest-${run}` }, adminToken);
+  if (category.status !== 200 && category.status !== 201) {
+    fail(`create category: status ${category.status}: ${category.body}`);
+  }
+  const product = post('/v1/product/', {
+    name: `Load test product ${run}`, sku: `LOAD-${run}`, price: 25.5, stock, categoryId: category.json('data.id'), isActive: true,
+  }, adminToken);
+  if (product.status !== 200 && product.status !== 201) {
+    fail(`create product: status ${product.status}: ${product.body}`);
+  }
+  return product.json('data.id');
+}
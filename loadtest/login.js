@@ -0,0 +1,29 @@
+// Login: customers signing in. Passwords are hashed with bcrypt, so this is CPU bound in the
+// user service.
+//
+// Baseline: p95 under 500ms at 20 virtual users, with under 1% errors.
+import { sleep } from 'k6';
+import { login, registerCustomer } from './lib.js';
+
+export const options = {
+  scenarios: {
+    login: {
+      executor: 'constant-vus',
+      vus: 20,
+      duration: '1m',
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:login}': ['p(95)<500'],
+  },
+};
+
+export function setup() {
+  return registerCustomer('loadtest');
+}
+
+export default function (customer) {
+  login(customer.email, customer.password);
+  sleep(1);
+}
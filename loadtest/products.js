@@ -0,0 +1,30 @@
+// Product listing: customers browsing the catalog, the most frequent request.
+//
+// Baseline: p95 under 300ms and p99 under 600ms at 50 virtual users, with under 1% errors.
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+import { BASE_URL } from './lib.js';
+
+export const options = {
+  scenarios: {
+    browse: {
+      executor: 'ramping-vus',
+      stages: [
+        { duration: '30s', target: 50 },
+        { duration: '1m', target: 50 },
+        { duration: '15s', target: 0 },
+      ],
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:list products}': ['p(95)<300', 'p(99)<600'],
+  },
+};
+
+export default function () {
+  const page = Math.floor(Math.random() * 5) + 1;
+  const res = http.get(`${BASE_URL}/v1/product/?page=${page}&limit=20`, { tags: { name: 'list products' } });
+  check(res, { 'list products: 200': (r) => r.status === 200 });
+  sleep(1);
+}
@@ -0,0 +1,179 @@
+// Package app runs a service as a go.uber.org/fx application. Services provide their
+// constructors to fx and register lifecycle hooks through the helpers here, so every service
+// starts its database, broker, workers and servers in dependency order and stops them in the
+// reverse order: servers drain first, then workers, then the connections they used.
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+// Service names the running service in metrics, logs and health probes
+type Service string
+
+// New builds the application of a service. The logger, service name and server settings are
+// supplied to the graph; hooks get server.ShutdownTimeout to stop.
+func New(service Service, log *logger.Logger, server config.Server, opts ...fx.Option) *fx.App {
+	return fx.New(
+		fx.WithLogger(func() fxevent.Logger {
+			l := &fxevent.ZapLogger{Logger: log.Log}
+			l.UseLogLevel(zapcore.DebugLevel)
+			return l
+		}),
+		fx.StopTimeout(server.ShutdownTimeout),
+		fx.Supply(service, log, server),
+		fx.Options(opts...),
+	)
+}
+
+// Run starts the application, blocks until SIGINT or SIGTERM and then stops it
+func Run(a *fx.App, log *logger.Logger) {
+	if err := a.Err(); err != nil {
+		log.Fatal("Failed to build application", zap.Error(err))
+	}
+	startCtx, cancel := context.WithTimeout(context.Background(), a.StartTimeout())
+	defer cancel()
+	if err := a.Start(startCtx); err != nil {
+		log.Fatal("Failed to start application", zap.Error(err))
+	}
+	signal := <-a.Wait()
+	log.Info("Shutting down", zap.String("signal", signal.Signal.String()))
+	stopCtx, cancel := context.WithTimeout(context.Background(), a.StopTimeout())
+	defer cancel()
+	if err := a.Stop(stopCtx); err != nil {
+		log.Error("Failed to stop application cleanly", zap.Error(err))
+	}
+	if signal.ExitCode != 0 {
+		log.Fatal("Application stopped after a failure", zap.Int("exitCode", signal.ExitCode))
+	}
+}
+
+// RunCommand builds the application with command as its only invocation, so just the
+// dependencies of the command are constructed, and closes them once it returned. command is
+// an fx invoke function whose error fails the command.
+func RunCommand(service Service, log *logger.Logger, server config.Server, opts fx.Option, command any) error {
+	a := New(service, log, server, opts, fx.Invoke(command))
+	if err := a.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.StartTimeout())
+	defer cancel()
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+	return a.Stop(ctx)
+}
+
+// OnStop closes a dependency when the application stops
+func OnStop(lc fx.Lifecycle, close func() error) {
+	lc.Append(fx.StopHook(close))
+}
+
+// Go runs a background worker from start until stop. run must return once its context is
+// cancelled; stopping waits for it.
+func Go(lc fx.Lifecycle, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+// Router returns an empty gin engine that takes the client address from X-Forwarded-For and
+// X-Real-IP only on requests from cfg.TrustedProxies, so clients cannot pick the address rate
+// limits and risk checks see
+func Router(cfg config.Server) *gin.Engine {
+	router := gin.New()
+	// LoadServer has checked every entry, the only cause of an error
+	_ = router.SetTrustedProxies(cfg.TrustedProxies)
+	return router
+}
+
+// ServeHTTP listens on the server port when the application starts and drains in-flight
+// requests when it stops. A server that fails after starting shuts the application down.
+func ServeHTTP(lc fx.Lifecycle, shutdowner fx.Shutdowner, log *logger.Logger, name string, cfg config.Server, handler http.Handler) {
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			log.Info(name+" starting", zap.String("port", cfg.Port))
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("Server failed", zap.Error(err))
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			return nil
+		},
+		OnStop: server.Shutdown,
+	})
+}
+
+// ServeGRPC serves the gRPC server on port from start until stop, letting running calls finish
+func ServeGRPC(lc fx.Lifecycle, shutdowner fx.Shutdowner, log *logger.Logger, name, port string, server *grpc.Server) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			listener, err := net.Listen("tcp", ":"+port)
+			if err != nil {
+				return err
+			}
+			log.Info(name+" starting", zap.String("port", port))
+			go func() {
+				if err := server.Serve(listener); err != nil {
+					log.Error("gRPC server failed", zap.Error(err))
+					_ = shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				server.Stop()
+			}
+			return nil
+		},
+	})
+}
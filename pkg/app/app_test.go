@@ -0,0 +1,39 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRouterClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		remote  string
+		want    string
+	}{
+		{name: "no proxy is trusted by default", remote: "203.0.113.7:4000", want: "203.0.113.7"},
+		{name: "untrusted peers cannot name the client", trusted: []string{"10.0.0.0/8"}, remote: "203.0.113.7:4000", want: "203.0.113.7"},
+		{name: "trusted proxies name the client", trusted: []string{"10.0.0.0/8"}, remote: "10.1.2.3:4000", want: "198.51.100.9"},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := Router(config.Server{TrustedProxies: tt.trusted})
+			var got string
+			router.GET("/", func(c *gin.Context) { got = c.ClientIP() })
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "192.0.2.1, 198.51.100.9")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("ClientIP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Telemetry sets up tracing and flushes it when the application stops
+func Telemetry(lc fx.Lifecycle, cfg otel.Config, log *logger.Logger) (*otel.Telemetry, error) {
+	telemetry, err := otel.Setup(context.Background(), cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("setting up OpenTelemetry: %w", err)
+	}
+	lc.Append(fx.StopHook(telemetry.Shutdown))
+	return telemetry, nil
+}
+
+// Database connects to the service database with metrics and tracing, and closes the pool
+// when the application stops
+func Database(lc fx.Lifecycle, service Service, cfg config.Database, telemetry *otel.Telemetry, log *logger.Logger) (*gorm.DB, error) {
+	db, err := psql.ConnectDB(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	OnStop(lc, sqlDB.Close)
+	if err := metrics.InstrumentDB(db, string(service), cfg.SlowQueryThreshold, log); err != nil {
+		return nil, fmt.Errorf("registering database metrics: %w", err)
+	}
+	if err := telemetry.InstrumentDB(db); err != nil {
+		return nil, fmt.Errorf("instrumenting database tracing: %w", err)
+	}
+	return db, nil
+}
+
+// Migrations provides the migrator of the service's embedded migrations
+func Migrations(source fs.FS) func(db *gorm.DB, log *logger.Logger) (*psql.Migrator, error) {
+	return func(db *gorm.DB, log *logger.Logger) (*psql.Migrator, error) {
+		migrator, err := psql.NewMigrator(db, source, log)
+		if err != nil {
+			return nil, fmt.Errorf("loading migrations: %w", err)
+		}
+		return migrator, nil
+	}
+}
+
+// EnsureSchema refuses to start on pending migrations in production, where deploys run
+// "migrate up" first; elsewhere it applies them
+func EnsureSchema(migrator *psql.Migrator, server config.Server) error {
+	if err := migrator.EnsureSchema(!server.IsProduction()); err != nil {
+		return fmt.Errorf("database schema is not up to date: %w", err)
+	}
+	return nil
+}
+
+// MigrateCommand is the RunCommand invocation of "migrate <args>"
+func MigrateCommand(args []string) func(*psql.Migrator) error {
+	return func(migrator *psql.Migrator) error {
+		return psql.RunMigrateCommand(migrator, args, os.Stdout)
+	}
+}
+
+// Snapshots provides the snapshotter of the service's tables, listed parents first
+func Snapshots(tables []string) func(service Service, db *gorm.DB, migrator *psql.Migrator, log *logger.Logger) *backup.Snapshotter {
+	return func(service Service, db *gorm.DB, migrator *psql.Migrator, log *logger.Logger) *backup.Snapshotter {
+		return backup.New(string(service), tables, db, migrator, log)
+	}
+}
+
+// SnapshotCommand is the RunCommand invocation of "snapshot <args>"
+func SnapshotCommand(args []string) func(*backup.Snapshotter) error {
+	return func(snapshots *backup.Snapshotter) error {
+		return backup.RunCommand(context.Background(), snapshots, args, os.Stdout)
+	}
+}
+
+// Cache connects the cache store and closes it when the application stops
+func Cache(lc fx.Lifecycle, cfg cache.Config, log *logger.Logger) (cache.Store, error) {
+	store, err := cache.NewStore(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to cache: %w", err)
+	}
+	OnStop(lc, store.Close)
+	return store, nil
+}
+
+// Audit provides the audit log of the service, read back by its audit endpoint
+func Audit(service Service, db *gorm.DB, log *logger.Logger) audit.Store {
+	return audit.NewDBStore(db, string(service), log)
+}
+
+// Auditor provides the audit log to the usecases that record changes
+func Auditor(store audit.Store) audit.Auditor {
+	return store
+}
+
+// AuthzStore provides the authorization policies of the service, changed through its policy
+// endpoint
+func AuthzStore(db *gorm.DB, log *logger.Logger) authz.Store {
+	return authz.NewDBStore(db, log)
+}
+
+// AuthzEnforcer provides the enforcer of the service's policies; the policy endpoint
+// invalidates its cache
+func AuthzEnforcer(store authz.Store, cfg authz.Config, log *logger.Logger) *authz.PolicyEnforcer {
+	return authz.NewEnforcer(store, cfg, log)
+}
+
+// Enforcer provides the policy enforcer to the usecases that check object-level access
+func Enforcer(e *authz.PolicyEnforcer) authz.Enforcer {
+	return e
+}
+
+// ExchangeRates provides the exchange rates, fetched once up front. A failed fetch only warns:
+// amounts in currencies with fixed rates still convert, and the service's refresh job retries.
+func ExchangeRates(cfg currency.Config, store cache.Store, log *logger.Logger) *currency.Rates {
+	rates := currency.NewFromConfig(cfg, store, log)
+	if _, err := rates.Refresh(context.Background()); err != nil {
+		log.Warn("Failed to fetch exchange rates", zap.Error(err))
+	}
+	return rates
+}
+
+// RateProvider provides the exchange rates to the usecases that convert amounts
+func RateProvider(rates *currency.Rates) currency.RateProvider {
+	return rates
+}
+
+// Publisher connects to the broker configured by BROKER_URL and closes the connection when
+// the application stops
+func Publisher(lc fx.Lifecycle, log *logger.Logger) (events.Publisher, error) {
+	publisher, err := events.NewPublisherFromEnv(log)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to message broker: %w", err)
+	}
+	OnStop(lc, publisher.Close)
+	return publisher, nil
+}
+
+// BrokerPublisher connects to the broker at url, or only logs events when url is empty, and
+// closes the connection when the application stops
+func BrokerPublisher(lc fx.Lifecycle, url, exchange string, log *logger.Logger) (events.Publisher, error) {
+	if url == "" {
+		log.Warn("BROKER_URL not set, events will only be logged")
+		return events.NewLogPublisher(log), nil
+	}
+	publisher, err := events.NewAMQPPublisher(url, exchange, log)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to message broker: %w", err)
+	}
+	OnStop(lc, publisher.Close)
+	return publisher, nil
+}
+
+// Subscriber connects a broker subscriber and closes it when the application stops
+func Subscriber(lc fx.Lifecycle, url, exchange string, log *logger.Logger) (events.Subscriber, error) {
+	subscriber, err := events.NewAMQPSubscriber(url, exchange, log)
+	if err != nil {
+		return nil, fmt.Errorf("connecting subscriber to message broker: %w", err)
+	}
+	OnStop(lc, subscriber.Close)
+	return subscriber, nil
+}
+
+// Subscribe starts consuming queue when the application starts
+func Subscribe(lc fx.Lifecycle, subscriber events.Subscriber, queue string, routingKeys []string, handler events.Handler) {
+	lc.Append(fx.StartHook(func() error {
+		return subscriber.Subscribe(queue, routingKeys, handler)
+	}))
+}
@@ -0,0 +1,224 @@
+// Package audit records who changed what: the actor, the action, the entity with its state
+// before and after the change and the request that caused it. Usecases call an Auditor after
+// each write; admins read the trail back through Handler.
+package audit
+
+//go:generate mockgen -source=audit.go -destination=mocks/audit.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+
+	// EventRecorded is the event type published by the event stream sink
+	EventRecorded = "audit.recorded"
+
+	redacted = "[REDACTED]"
+)
+
+// Actor is the caller behind a change; a zero ID means the system itself, e.g. a scheduled job
+type Actor struct {
+	ID    int
+	Roles []string
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying the actor, set by the auth middleware
+func ContextWithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFromContext returns the actor stored in ctx, or the system actor when there is none
+func ActorFromContext(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	return a
+}
+
+// Change is the old and new value of one field
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Entry is one recorded change. Before and After are JSON snapshots of the entity with secrets
+// redacted; Changes lists the fields that differ between them.
+type Entry struct {
+	ID         int64           `json:"id"`
+	Service    string          `json:"service"`
+	ActorID    int             `json:"actorId"`
+	ActorRoles []string        `json:"actorRoles"`
+	Action     string          `json:"action"`
+	Entity     string          `json:"entity"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Changes    json.RawMessage `json:"changes,omitempty" swaggertype:"object"`
+	RequestID  string          `json:"requestId,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// Auditor records changes. before is nil for creations and after is nil for deletions.
+// Failures are logged rather than returned so a broken audit sink does not undo a write that
+// already happened.
+type Auditor interface {
+	Record(ctx context.Context, action, entity string, entityID any, before, after any)
+}
+
+// Store is an Auditor whose entries can be read back
+type Store interface {
+	Auditor
+	List(ctx context.Context, p pagination.Params) (*[]Entry, int64, error)
+}
+
+// NewEntry builds the entry for a change, taking the actor and request ID from ctx
+func NewEntry(ctx context.Context, service, action, entity string, entityID any, before, after any) (*Entry, error) {
+	actor := ActorFromContext(ctx)
+	e := &Entry{
+		Service:    service,
+		ActorID:    actor.ID,
+		ActorRoles: actor.Roles,
+		Action:     action,
+		Entity:     entity,
+		EntityID:   fmt.Sprint(entityID),
+		RequestID:  logger.RequestIDFromContext(ctx),
+		CreatedAt:  time.Now(),
+	}
+	beforeMap, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	if e.Before, err = marshalMap(beforeMap); err != nil {
+		return nil, err
+	}
+	if e.After, err = marshalMap(afterMap); err != nil {
+		return nil, err
+	}
+	if beforeMap != nil && afterMap != nil {
+		if changes := Diff(beforeMap, afterMap); len(changes) > 0 {
+			if e.Changes, err = json.Marshal(changes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e, nil
+}
+
+// snapshot turns an entity into its JSON fields with secrets redacted; nil stays nil
+func snapshot(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("audit snapshot of %T is not a JSON object: %w", v, err)
+	}
+	for k := range m {
+		if sensitive(k) {
+			m[k] = redacted
+		}
+	}
+	return m, nil
+}
+
+func sensitive(field string) bool {
+	field = strings.ToLower(field)
+	return strings.Contains(field, "password") || strings.Contains(field, "secret") || strings.Contains(field, "token")
+}
+
+func marshalMap(m map[string]any) (json.RawMessage, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Diff returns the fields whose values differ between two snapshots
+func Diff(before, after map[string]any) map[string]Change {
+	changes := map[string]Change{}
+	for k, from := range before {
+		if to, ok := after[k]; !ok || !reflect.DeepEqual(from, to) {
+			changes[k] = Change{From: from, To: after[k]}
+		}
+	}
+	for k, to := range after {
+		if _, ok := before[k]; !ok {
+			changes[k] = Change{To: to}
+		}
+	}
+	return changes
+}
+
+// EventAuditor publishes every entry as an audit.recorded event, for a central audit
+// service or a SIEM to consume
+type EventAuditor struct {
+	publisher events.Publisher
+	service   string
+	Logger    *logger.Logger
+}
+
+func NewEventAuditor(p events.Publisher, service string, l *logger.Logger) Auditor {
+	return &EventAuditor{publisher: p, service: service, Logger: l}
+}
+
+func (a *EventAuditor) Record(ctx context.Context, action, entity string, entityID any, before, after any) {
+	entry, err := NewEntry(ctx, a.service, action, entity, entityID, before, after)
+	if err == nil {
+		var event *events.Event
+		if event, err = events.NewEvent(ctx, EventRecorded, a.service, entry); err == nil {
+			err = a.publisher.Publish(event)
+		}
+	}
+	if err != nil {
+		a.Logger.WithContext(ctx).Error("Failed to publish audit entry",
+			zap.String("action", action), zap.String("entity", entity), zap.Any("entityID", entityID), zap.Error(err))
+	}
+}
+
+// Multi records every change with each of the auditors, e.g. the database and the event stream
+func Multi(auditors ...Auditor) Auditor {
+	return multiAuditor(auditors)
+}
+
+type multiAuditor []Auditor
+
+func (m multiAuditor) Record(ctx context.Context, action, entity string, entityID any, before, after any) {
+	for _, a := range m {
+		a.Record(ctx, action, entity, entityID, before, after)
+	}
+}
+
+// Nop discards every change, for code paths that have no auditor wired
+func Nop() Auditor {
+	return nopAuditor{}
+}
+
+type nopAuditor struct{}
+
+func (nopAuditor) Record(context.Context, string, string, any, any, any) {}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+var listSpec = pagination.Spec{
+	Sortable: map[string]string{"id": "id", "createdAt": "created_at"},
+	Filterable: map[string]string{
+		"entity": "entity", "entityId": "entity_id", "action": "action", "actorId": "actor_id", "requestId": "request_id",
+	},
+	DefaultSort: "-id",
+}
+
+// Handler serves the audit trail of a service to admins
+type Handler struct {
+	store  Store
+	Logger *logger.Logger
+}
+
+func NewHandler(s Store, l *logger.Logger) *Handler {
+	return &Handler{store: s, Logger: l}
+}
+
+// List answers GET ?entity=user&entityId=42&action=update&actorId=1&requestId=...&page=1&limit=20&sort=-createdAt
+// with the matching entries, newest first by default. Routes must restrict it to admins.
+func (h *Handler) List(c *gin.Context) {
+	p, err := pagination.Parse(c, listSpec)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	entries, total, err := h.store.List(c.Request.Context(), p)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	controllers.RespondPage(c, *entries, total, p)
+}
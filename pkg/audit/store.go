@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AuditLog is the audit_logs row; each service keeps the table in its own database. Entries
+// belong to the tenant the change was made in, and List only returns the caller's.
+type AuditLog struct {
+	ID         int64     `gorm:"primaryKey"`
+	Service    string    `gorm:"column:service;not null"`
+	ActorID    int       `gorm:"column:actor_id;not null;default:0"`
+	ActorRoles string    `gorm:"column:actor_roles"`
+	Action     string    `gorm:"column:action;not null"`
+	Entity     string    `gorm:"column:entity;not null"`
+	EntityID   string    `gorm:"column:entity_id;not null"`
+	Before     []byte    `gorm:"column:before;type:jsonb"`
+	After      []byte    `gorm:"column:after;type:jsonb"`
+	Changes    []byte    `gorm:"column:changes;type:jsonb"`
+	RequestID  string    `gorm:"column:request_id"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+	psql.TenantModel
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// DBStore writes entries to the audit_logs table of the service database
+type DBStore struct {
+	DB      *gorm.DB
+	service string
+	Logger  *logger.Logger
+}
+
+func NewDBStore(db *gorm.DB, service string, l *logger.Logger) Store {
+	return &DBStore{DB: db, service: service, Logger: l}
+}
+
+func (s *DBStore) Record(ctx context.Context, action, entity string, entityID any, before, after any) {
+	entry, err := NewEntry(ctx, s.service, action, entity, entityID, before, after)
+	if err == nil {
+		// the write already happened, so keep its entry even when the client has gone away
+		err = s.DB.WithContext(context.WithoutCancel(ctx)).Create(&AuditLog{
+			Service:    entry.Service,
+			ActorID:    entry.ActorID,
+			ActorRoles: strings.Join(entry.ActorRoles, ","),
+			Action:     entry.Action,
+			Entity:     entry.Entity,
+			EntityID:   entry.EntityID,
+			Before:     entry.Before,
+			After:      entry.After,
+			Changes:    entry.Changes,
+			RequestID:  entry.RequestID,
+			CreatedAt:  entry.CreatedAt,
+		}).Error
+	}
+	if err != nil {
+		s.Logger.WithContext(ctx).Error("Failed to record audit entry",
+			zap.String("action", action), zap.String("entity", entity), zap.Any("entityID", entityID), zap.Error(err))
+	}
+}
+
+func (s *DBStore) List(ctx context.Context, p pagination.Params) (*[]Entry, int64, error) {
+	var rows []AuditLog
+	total, err := pagination.Find(s.DB.WithContext(ctx).Model(&AuditLog{}), p, &rows)
+	if err != nil {
+		s.Logger.Error("Error listing audit entries", zap.Error(err))
+		return nil, 0, psql.TranslateError(err)
+	}
+	entries := make([]Entry, len(rows))
+	for i, r := range rows {
+		entries[i] = r.toEntry()
+	}
+	return &entries, total, nil
+}
+
+func (r *AuditLog) toEntry() Entry {
+	var roles []string
+	if r.ActorRoles != "" {
+		roles = strings.Split(r.ActorRoles, ",")
+	}
+	return Entry{
+		ID:         r.ID,
+		Service:    r.Service,
+		ActorID:    r.ActorID,
+		ActorRoles: roles,
+		Action:     r.Action,
+		Entity:     r.Entity,
+		EntityID:   r.EntityID,
+		Before:     r.Before,
+		After:      r.After,
+		Changes:    r.Changes,
+		RequestID:  r.RequestID,
+		CreatedAt:  r.CreatedAt,
+	}
+}
@@ -0,0 +1,203 @@
+// Package authz decides object-level access from policies kept in the service database, in the
+// manner of Casbin's RBAC with deny-override: a policy grants or denies a role an action on a
+// resource, optionally only on objects the caller owns. Usecases ask an Enforcer before acting;
+// admins change the policies at runtime through Handler.
+package authz
+
+//go:generate mockgen -source=authz.go -destination=mocks/authz.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+
+	// ConditionOwner limits a policy to objects whose owner is the caller
+	ConditionOwner = "owner"
+
+	// Any matches every role, resource or action
+	Any = "*"
+
+	ActionRead   = "read"
+	ActionWrite  = "write"
+	ActionCancel = "cancel"
+)
+
+// Policy is one rule: Effect applies when a caller with Role does Action on Resource and, if
+// set, Condition holds for the object
+type Policy struct {
+	ID        int64  `json:"id"`
+	Role      string `json:"role"`
+	Resource  string `json:"resource"`
+	Action    string `json:"action"`
+	Condition string `json:"condition,omitempty"`
+	Effect    string `json:"effect"`
+}
+
+func (p Policy) matches(s Subject, obj Object, action string) bool {
+	if !matchAny(p.Resource, obj.Resource) || !matchAny(p.Action, action) {
+		return false
+	}
+	if p.Role != Any && !slices.Contains(s.Roles, p.Role) {
+		return false
+	}
+	switch p.Condition {
+	case "":
+		return true
+	case ConditionOwner:
+		return obj.OwnerID != 0 && obj.OwnerID == s.ID
+	default:
+		// a condition this version does not know never holds
+		return false
+	}
+}
+
+func matchAny(pattern, value string) bool {
+	return pattern == Any || pattern == value
+}
+
+// Subject is the caller; the zero Subject is the system itself, e.g. a scheduled job, and may
+// do anything
+type Subject struct {
+	ID    int
+	Roles []string
+}
+
+func (s Subject) IsSystem() bool {
+	return s.ID == 0 && len(s.Roles) == 0
+}
+
+// SubjectFromContext returns the caller set by the auth middleware, the system when there is none
+func SubjectFromContext(ctx context.Context) Subject {
+	actor := audit.ActorFromContext(ctx)
+	return Subject{ID: actor.ID, Roles: actor.Roles}
+}
+
+// Object is what the caller acts on; OwnerID is zero for objects nobody owns, such as a product
+type Object struct {
+	Resource string
+	OwnerID  int
+}
+
+func (o Object) String() string {
+	if o.OwnerID == 0 {
+		return o.Resource
+	}
+	return fmt.Sprintf("%s of user %d", o.Resource, o.OwnerID)
+}
+
+// Decide reports whether the policies let s do action on obj: some policy must allow it and
+// none may deny it
+func Decide(policies []Policy, s Subject, obj Object, action string) bool {
+	if s.IsSystem() {
+		return true
+	}
+	allowed := false
+	for _, p := range policies {
+		if !p.matches(s, obj, action) {
+			continue
+		}
+		if p.Effect == EffectDeny {
+			return false
+		}
+		allowed = allowed || p.Effect == EffectAllow
+	}
+	return allowed
+}
+
+// Enforcer answers access questions from the current policies
+type Enforcer interface {
+	// Allowed reports whether s may do action on obj
+	Allowed(ctx context.Context, s Subject, obj Object, action string) bool
+	// Enforce checks the caller of ctx and fails with a NotAuthorized error when it may not do
+	// action on obj
+	Enforce(ctx context.Context, obj Object, action string) error
+}
+
+// Store keeps the policies of a service
+type Store interface {
+	List(ctx context.Context) ([]Policy, error)
+	Create(ctx context.Context, p *Policy) error
+	// Delete fails with a NotFound error when there is no policy with the ID
+	Delete(ctx context.Context, id int64) error
+}
+
+// Config sets how long a replica reuses the policies it read; changes made on another replica
+// apply after Refresh
+type Config struct {
+	Refresh time.Duration
+}
+
+// LoadConfig reads AUTHZ_REFRESH_SECONDS
+func LoadConfig(src *config.Source) Config {
+	return Config{Refresh: src.Duration("AUTHZ_REFRESH_SECONDS", 30, time.Second)}
+}
+
+// PolicyEnforcer is the Enforcer of a Store. Policies are cached for Config.Refresh; when the
+// store fails the last known policies are used, and until they are first read every caller
+// but the system is denied.
+type PolicyEnforcer struct {
+	store     Store
+	refresh   time.Duration
+	mu        sync.RWMutex
+	policies  []Policy
+	fetchedAt time.Time
+	Logger    *logger.Logger
+}
+
+func NewEnforcer(store Store, cfg Config, l *logger.Logger) *PolicyEnforcer {
+	return &PolicyEnforcer{store: store, refresh: cfg.Refresh, Logger: l}
+}
+
+func (e *PolicyEnforcer) Allowed(ctx context.Context, s Subject, obj Object, action string) bool {
+	return Decide(e.Policies(ctx), s, obj, action)
+}
+
+func (e *PolicyEnforcer) Enforce(ctx context.Context, obj Object, action string) error {
+	s := SubjectFromContext(ctx)
+	if e.Allowed(ctx, s, obj, action) {
+		return nil
+	}
+	e.Logger.WithContext(ctx).Warn("Access denied by policy", zap.Int("userID", s.ID), zap.Strings("roles", s.Roles),
+		zap.String("resource", obj.Resource), zap.Int("ownerID", obj.OwnerID), zap.String("action", action))
+	return domainErrors.NewAppError(fmt.Errorf("not allowed to %s %s", action, obj), domainErrors.NotAuthorized)
+}
+
+// Policies returns the cached policies, reading them again once they are older than Refresh
+func (e *PolicyEnforcer) Policies(ctx context.Context) []Policy {
+	e.mu.RLock()
+	policies, fetchedAt := e.policies, e.fetchedAt
+	e.mu.RUnlock()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < e.refresh {
+		return policies
+	}
+	fresh, err := e.store.List(ctx)
+	if err != nil {
+		e.Logger.WithContext(ctx).Error("Failed to read authorization policies", zap.Error(err))
+		return policies
+	}
+	e.mu.Lock()
+	e.policies, e.fetchedAt = fresh, time.Now()
+	e.mu.Unlock()
+	return fresh
+}
+
+// Invalidate makes the next check read the policies again, after they were changed
+func (e *PolicyEnforcer) Invalidate() {
+	e.mu.Lock()
+	e.fetchedAt = time.Time{}
+	e.mu.Unlock()
+}
@@ -0,0 +1,109 @@
+package authz
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CreatePolicyRequest is the body of POST on the policies route. Role, resource and action may
+// be * to match anything.
+type CreatePolicyRequest struct {
+	Role      string `json:"role" binding:"required"`
+	Resource  string `json:"resource" binding:"required"`
+	Action    string `json:"action" binding:"required"`
+	Condition string `json:"condition" binding:"omitempty,oneof=owner"`
+	Effect    string `json:"effect" binding:"required,oneof=allow deny"`
+}
+
+// Handler lets admins manage the policies of a service. Changes apply at once on the replica
+// serving them and after Config.Refresh on the others. Policies apply to every tenant, so only
+// callers of the default tenant may use it.
+type Handler struct {
+	store    Store
+	enforcer *PolicyEnforcer
+	Logger   *logger.Logger
+}
+
+func NewHandler(s Store, e *PolicyEnforcer, l *logger.Logger) *Handler {
+	return &Handler{store: s, enforcer: e, Logger: l}
+}
+
+// List answers GET with every policy, oldest first. Routes must restrict it to admins.
+func (h *Handler) List(c *gin.Context) {
+	if err := operatorOnly(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	policies, err := h.store.List(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	controllers.Respond(c, http.StatusOK, policies)
+}
+
+// Create answers POST with the stored policy
+func (h *Handler) Create(c *gin.Context) {
+	if err := operatorOnly(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var req CreatePolicyRequest
+	if err := controllers.BindJSONWithOptions(c, &req, controllers.BindOptions{DisallowUnknownFields: true}); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	p := Policy{Role: req.Role, Resource: req.Resource, Action: req.Action, Condition: req.Condition, Effect: req.Effect}
+	if err := h.store.Create(c.Request.Context(), &p); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.enforcer.Invalidate()
+	h.Logger.WithContext(c.Request.Context()).Warn("Authorization policy created", zap.Int64("id", p.ID), zap.String("role", p.Role),
+		zap.String("resource", p.Resource), zap.String("action", p.Action), zap.String("condition", p.Condition), zap.String("effect", p.Effect))
+	controllers.Respond(c, http.StatusCreated, p)
+}
+
+// Delete answers DELETE on a route with an :id parameter
+func (h *Handler) Delete(c *gin.Context) {
+	if err := operatorOnly(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		return
+	}
+	if err := h.store.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.enforcer.Invalidate()
+	h.Logger.WithContext(c.Request.Context()).Warn("Authorization policy deleted", zap.Int64("id", id))
+	controllers.RespondMessage(c, http.StatusOK, "resource deleted successfully")
+}
+
+// RegisterRoutes mounts List, Create and Delete on group, which must already require
+// security.PermAuthzManage
+func (h *Handler) RegisterRoutes(group gin.IRoutes) {
+	group.GET("", h.List)
+	group.POST("", h.Create)
+	group.DELETE("/:id", h.Delete)
+}
+
+func operatorOnly(c *gin.Context) error {
+	if tenant.FromContext(c.Request.Context()) != tenant.Default {
+		return domainErrors.NewAppError(errors.New("policies are managed from the default tenant"), domainErrors.NotAuthorized)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package authz_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/authz/mocks"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestHandlerOnlyServesTheDefaultTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenant     string
+		method     string
+		path       string
+		setup      func(store *mocks.MockStore)
+		wantStatus int
+	}{
+		{
+			name:   "default tenant lists the policies",
+			tenant: tenant.Default,
+			method: http.MethodGet,
+			path:   "/policies",
+			setup: func(store *mocks.MockStore) {
+				store.EXPECT().List(gomock.Any()).Return([]authz.Policy{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{name: "other tenants cannot list", tenant: "acme", method: http.MethodGet, path: "/policies", wantStatus: http.StatusForbidden},
+		{name: "other tenants cannot create", tenant: "acme", method: http.MethodPost, path: "/policies", wantStatus: http.StatusForbidden},
+		{name: "other tenants cannot delete", tenant: "acme", method: http.MethodDelete, path: "/policies/1", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mocks.NewMockStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(store)
+			}
+			l := &logger.Logger{Log: zap.NewNop()}
+			h := authz.NewHandler(store, nil, l)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.ErrorHandler(l), func(c *gin.Context) {
+				c.Request = c.Request.WithContext(tenant.ContextWithTenant(c.Request.Context(), tt.tenant))
+			})
+			router.GET("/policies", h.List)
+			router.POST("/policies", h.Create)
+			router.DELETE("/policies/:id", h.Delete)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
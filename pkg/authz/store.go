@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"context"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AuthzPolicy is the authz_policies row; each service keeps the table, seeded with its
+// default policies, in its own database
+type AuthzPolicy struct {
+	ID        int64  `gorm:"primaryKey"`
+	Role      string `gorm:"column:role;not null"`
+	Resource  string `gorm:"column:resource;not null"`
+	Action    string `gorm:"column:action;not null"`
+	Condition string `gorm:"column:condition;not null;default:''"`
+	Effect    string `gorm:"column:effect;not null"`
+}
+
+func (AuthzPolicy) TableName() string { return "authz_policies" }
+
+// DBStore keeps policies in the authz_policies table of the service database
+type DBStore struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewDBStore(db *gorm.DB, l *logger.Logger) Store {
+	return &DBStore{DB: db, Logger: l}
+}
+
+func (s *DBStore) List(ctx context.Context) ([]Policy, error) {
+	var rows []AuthzPolicy
+	if err := s.DB.WithContext(ctx).Order("id").Find(&rows).Error; err != nil {
+		s.Logger.Error("Error listing authorization policies", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	policies := make([]Policy, len(rows))
+	for i, r := range rows {
+		policies[i] = Policy(r)
+	}
+	return policies, nil
+}
+
+func (s *DBStore) Create(ctx context.Context, p *Policy) error {
+	row := AuthzPolicy(*p)
+	if err := s.DB.WithContext(ctx).Create(&row).Error; err != nil {
+		s.Logger.Error("Error creating authorization policy", zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	p.ID = row.ID
+	return nil
+}
+
+func (s *DBStore) Delete(ctx context.Context, id int64) error {
+	result := s.DB.WithContext(ctx).Delete(&AuthzPolicy{}, id)
+	if result.Error != nil {
+		s.Logger.Error("Error deleting authorization policy", zap.Int64("id", id), zap.Error(result.Error))
+		return psql.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
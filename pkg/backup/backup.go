@@ -0,0 +1,200 @@
+// Package backup exports the data of a service to a versioned snapshot and restores it. A
+// snapshot holds every row of the tables the service lists, across all tenants, together with
+// the schema version they were read at, and only restores into a database at that version.
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Format is the version of the snapshot layout; snapshots of another format are refused
+const Format = 1
+
+// Snapshot is the data of a service at one point in time
+type Snapshot struct {
+	Format        int       `json:"format"`
+	Service       string    `json:"service"`
+	SchemaVersion uint      `json:"schemaVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Tables        []Table   `json:"tables"`
+}
+
+// Table holds the rows of a table as a JSON array of objects keyed by column
+type Table struct {
+	Name string          `json:"name"`
+	Rows json.RawMessage `json:"rows"`
+}
+
+// TableCount is how many rows a restore wrote to a table
+type TableCount struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+type Config struct {
+	// MaxRestoreBytes caps the snapshots the restore endpoint accepts; the CLI reads any size
+	MaxRestoreBytes int64
+}
+
+func LoadConfig(src *config.Source) Config {
+	return Config{MaxRestoreBytes: int64(src.Int("BACKUP_MAX_RESTORE_MB", 256)) << 20}
+}
+
+// Snapshotter exports and restores the tables of a service. Tables are listed parents first:
+// restores insert in that order so foreign keys hold.
+type Snapshotter struct {
+	service  string
+	tables   []string
+	db       *gorm.DB
+	migrator *psql.Migrator
+	Logger   *logger.Logger
+}
+
+func New(service string, tables []string, db *gorm.DB, migrator *psql.Migrator, l *logger.Logger) *Snapshotter {
+	return &Snapshotter{service: service, tables: tables, db: db, migrator: migrator, Logger: l}
+}
+
+// Export reads every table in one read-only transaction, so the snapshot is consistent even
+// while the service keeps writing
+func (s *Snapshotter) Export(ctx context.Context) (*Snapshot, error) {
+	version, err := s.schemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{Format: Format, Service: s.service, SchemaVersion: version, CreatedAt: time.Now().UTC()}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range s.tables {
+			var rows []byte
+			if err := tx.Raw(fmt.Sprintf("SELECT COALESCE(json_agg(t), '[]') FROM %s t", quoteIdentifier(table))).Row().Scan(&rows); err != nil {
+				return fmt.Errorf("exporting %s: %w", table, err)
+			}
+			snapshot.Tables = append(snapshot.Tables, Table{Name: table, Rows: rows})
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Restore replaces the rows of every table with those of the snapshot in one transaction and
+// moves the ID sequences past the restored rows. Snapshots of another format, service or
+// schema version, or with other tables, fail with a ValidationError and change nothing.
+func (s *Snapshotter) Restore(ctx context.Context, snapshot *Snapshot) ([]TableCount, error) {
+	rows, err := s.check(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	quoted := make([]string, len(s.tables))
+	for i, table := range s.tables {
+		quoted[i] = quoteIdentifier(table)
+	}
+	counts := make([]TableCount, 0, len(s.tables))
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// without CASCADE, a table outside the list that references one of them fails the
+		// restore instead of being emptied with it
+		if err := tx.Exec("TRUNCATE " + strings.Join(quoted, ", ")).Error; err != nil {
+			return fmt.Errorf("emptying tables: %w", err)
+		}
+		for i, table := range s.tables {
+			result := tx.Exec(fmt.Sprintf("INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, CAST(? AS json))", quoted[i]), string(rows[table]))
+			if result.Error != nil {
+				return fmt.Errorf("restoring %s: %w", table, result.Error)
+			}
+			if err := resetSequences(tx, table); err != nil {
+				return err
+			}
+			counts = append(counts, TableCount{Name: table, Rows: result.RowsAffected})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.WithContext(ctx).Warn("Snapshot restored", zap.String("service", s.service), zap.Uint("schemaVersion", snapshot.SchemaVersion),
+		zap.Time("createdAt", snapshot.CreatedAt), zap.Any("tables", counts))
+	return counts, nil
+}
+
+// check returns the rows of each table when the snapshot fits the database
+func (s *Snapshotter) check(snapshot *Snapshot) (map[string]json.RawMessage, error) {
+	invalid := func(format string, args ...any) error {
+		return domainErrors.NewAppError(fmt.Errorf(format, args...), domainErrors.ValidationError)
+	}
+	if snapshot.Format != Format {
+		return nil, invalid("snapshot format %d is not supported, expected %d", snapshot.Format, Format)
+	}
+	if snapshot.Service != s.service {
+		return nil, invalid("snapshot is of the %s service, not %s", snapshot.Service, s.service)
+	}
+	version, err := s.schemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.SchemaVersion != version {
+		return nil, invalid("snapshot is of schema version %d but the database is at %d; migrate to %d first", snapshot.SchemaVersion, version, snapshot.SchemaVersion)
+	}
+	rows := make(map[string]json.RawMessage, len(snapshot.Tables))
+	for _, t := range snapshot.Tables {
+		if _, ok := rows[t.Name]; ok {
+			return nil, invalid("snapshot lists table %s twice", t.Name)
+		}
+		rows[t.Name] = t.Rows
+	}
+	for _, table := range s.tables {
+		if _, ok := rows[table]; !ok {
+			return nil, invalid("snapshot has no table %s", table)
+		}
+	}
+	if len(rows) != len(s.tables) {
+		return nil, invalid("snapshot has tables the %s service does not restore", s.service)
+	}
+	return rows, nil
+}
+
+func (s *Snapshotter) schemaVersion() (uint, error) {
+	status, err := s.migrator.Status()
+	if err != nil {
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+	if status.Dirty {
+		return 0, psql.ErrDirtyDatabase
+	}
+	return status.Version, nil
+}
+
+// resetSequences makes the serial columns of table continue after the highest restored value
+func resetSequences(tx *gorm.DB, table string) error {
+	var columns []string
+	err := tx.Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND column_default LIKE 'nextval(%'`, table).Scan(&columns).Error
+	if err != nil {
+		return fmt.Errorf("reading sequences of %s: %w", table, err)
+	}
+	for _, column := range columns {
+		err := tx.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+			quoteIdentifier(column), quoteIdentifier(table)), table, column).Error
+		if err != nil {
+			return fmt.Errorf("resetting sequence of %s.%s: %w", table, column, err)
+		}
+	}
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RunCommand handles `<service> snapshot export [file] | restore <file>`; export writes to out
+// without a file
+func RunCommand(ctx context.Context, s *Snapshotter, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: snapshot export [file] | restore <file>")
+	}
+	switch args[0] {
+	case "export":
+		snapshot, err := s.Export(ctx)
+		if err != nil {
+			return err
+		}
+		if len(args) < 2 {
+			return json.NewEncoder(out).Encode(snapshot)
+		}
+		if err := writeSnapshot(args[1], snapshot); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "exported %d tables at schema version %d to %s\n", len(snapshot.Tables), snapshot.SchemaVersion, args[1])
+		return err
+	case "restore":
+		if len(args) < 2 {
+			return errors.New("usage: snapshot restore <file>")
+		}
+		snapshot, err := readSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+		counts, err := s.Restore(ctx, snapshot)
+		if err != nil {
+			return err
+		}
+		for _, c := range counts {
+			if _, err := fmt.Fprintf(out, "%s: %d rows\n", c.Name, c.Rows); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown snapshot command %q", args[0])
+}
+
+func writeSnapshot(path string, snapshot *Snapshot) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(snapshot); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	var snapshot Snapshot
+	if err := json.NewDecoder(f).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RestoreResponse lists the rows written per table
+type RestoreResponse struct {
+	SchemaVersion uint         `json:"schemaVersion"`
+	Tables        []TableCount `json:"tables"`
+}
+
+// Handler exports and restores the snapshot of a service over HTTP. Snapshots span every
+// tenant, so only callers of the default tenant may use it.
+type Handler struct {
+	snapshots *Snapshotter
+	cfg       Config
+	Logger    *logger.Logger
+}
+
+func NewHandler(s *Snapshotter, cfg Config, l *logger.Logger) *Handler {
+	return &Handler{snapshots: s, cfg: cfg, Logger: l}
+}
+
+// Export answers GET with the snapshot as a JSON attachment
+func (h *Handler) Export(c *gin.Context) {
+	if err := operatorOnly(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	snapshot, err := h.snapshots.Export(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	filename := fmt.Sprintf("%s-snapshot-%s.json", snapshot.Service, snapshot.CreatedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	h.Logger.WithContext(c.Request.Context()).Info("Snapshot exported", zap.Uint("schemaVersion", snapshot.SchemaVersion))
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// Restore answers POST with a snapshot in the body by replacing the data of the service
+func (h *Handler) Restore(c *gin.Context) {
+	if err := operatorOnly(c); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var snapshot Snapshot
+	if err := controllers.BindJSONWithOptions(c, &snapshot, controllers.BindOptions{MaxBytes: h.cfg.MaxRestoreBytes, DisallowUnknownFields: true}); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	counts, err := h.snapshots.Restore(c.Request.Context(), &snapshot)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	controllers.Respond(c, http.StatusOK, RestoreResponse{SchemaVersion: snapshot.SchemaVersion, Tables: counts})
+}
+
+// RegisterRoutes mounts Export and Restore on group, which must already require
+// security.PermBackupManage
+func (h *Handler) RegisterRoutes(group gin.IRoutes) {
+	group.GET("", h.Export)
+	group.POST("/restore", h.Restore)
+}
+
+func operatorOnly(c *gin.Context) error {
+	if tenant.FromContext(c.Request.Context()) != tenant.Default {
+		return domainErrors.NewAppError(errors.New("snapshots are managed from the default tenant"), domainErrors.NotAuthorized)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package cache provides a namespaced cache-aside layer over a Redis or in-memory Store, with
+// typed helpers, singleflight loading and Prometheus metrics.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Cache lookups by namespace and result (hit, miss, error).",
+	}, []string{"namespace", "result"})
+
+	cacheLoads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_loads_total",
+		Help: "Loads from the source of truth after a miss; shared=true when the load was joined by a concurrent caller.",
+	}, []string{"namespace", "shared"})
+
+	cacheLoadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cache_load_duration_seconds",
+		Help:    "Duration of loads from the source of truth after a miss.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"namespace"})
+)
+
+// Cache prefixes every key with its namespace, e.g. "catalog:category:42", so services can
+// share one Redis without clashing
+type Cache struct {
+	store      Store
+	namespace  string
+	defaultTTL time.Duration
+	group      singleflight.Group
+	Logger     *logger.Logger
+}
+
+func New(store Store, namespace string, defaultTTL time.Duration, l *logger.Logger) *Cache {
+	return &Cache{store: store, namespace: namespace, defaultTTL: defaultTTL, Logger: l}
+}
+
+// Key joins the parts under the cache namespace and the tenant of ctx, e.g.
+// "catalog:tenant:acme:category:42", so one tenant never reads another's entries. The system,
+// acting across tenants, has keys of its own without the tenant.
+func (c *Cache) Key(ctx context.Context, parts ...string) string {
+	key := strings.Join(parts, ":")
+	if id := tenant.FromContext(ctx); id != "" {
+		key = "tenant:" + id + ":" + key
+	}
+	return c.namespace + ":" + key
+}
+
+// Store returns the backend, for rate limiters and idempotency stores that need its
+// counters and SetNX
+func (c *Cache) Store() Store {
+	return c.store
+}
+
+func (c *Cache) ttl(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return c.defaultTTL
+	}
+	return ttl
+}
+
+// Delete removes keys built with Key
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	return c.store.Delete(ctx, keys...)
+}
+
+// StoreCheck is a readiness check that pings the store
+func StoreCheck(store Store) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		return nil, store.Ping(ctx)
+	}
+}
+
+// Get reads and decodes a value. found is false on a miss.
+func Get[T any](ctx context.Context, c *Cache, key string) (value T, found bool, err error) {
+	data, err := c.store.Get(ctx, key)
+	switch {
+	case errors.Is(err, ErrMiss):
+		cacheRequests.WithLabelValues(c.namespace, "miss").Inc()
+		return value, false, nil
+	case err != nil:
+		cacheRequests.WithLabelValues(c.namespace, "error").Inc()
+		return value, false, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		cacheRequests.WithLabelValues(c.namespace, "error").Inc()
+		return value, false, err
+	}
+	cacheRequests.WithLabelValues(c.namespace, "hit").Inc()
+	return value, true, nil
+}
+
+// Set encodes and stores a value; a zero ttl uses the cache default
+func Set[T any](ctx context.Context, c *Cache, key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.store.Set(ctx, key, data, c.ttl(ttl))
+}
+
+// GetOrLoad returns the cached value or calls load and caches its result. Concurrent misses
+// for the same key share one load, so an expired hot key does not stampede the database.
+// Cache failures are logged and fall through to load; load errors are returned and not cached.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	value, found, err := Get[T](ctx, c, key)
+	if err != nil {
+		c.Logger.Warn("Cache read failed", zap.String("key", key), zap.Error(err))
+	}
+	if found {
+		return value, nil
+	}
+
+	start := time.Now()
+	result, err, shared := c.group.Do(key, func() (any, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return loaded, err
+		}
+		if err := Set(ctx, c, key, loaded, ttl); err != nil {
+			c.Logger.Warn("Cache write failed", zap.String("key", key), zap.Error(err))
+		}
+		return loaded, nil
+	})
+	cacheLoads.WithLabelValues(c.namespace, boolLabel(shared)).Inc()
+	cacheLoadDuration.WithLabelValues(c.namespace).Observe(time.Since(start).Seconds())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result.(T), nil
+}
+
+func boolLabel(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
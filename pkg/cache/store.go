@@ -0,0 +1,317 @@
+package cache
+
+//go:generate mockgen -source=store.go -destination=mocks/store.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrMiss is returned by Store.Get when the key does not exist or has expired
+var ErrMiss = errors.New("cache miss")
+
+// Store is the key/value backend shared by caches, rate limiters and idempotency stores.
+// Keys are used as given; namespacing is the job of Cache.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX stores the value only when the key is absent and reports whether it did
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Incr increments a counter, starting its ttl when the counter is created
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Delete(ctx context.Context, keys ...string) error
+	// TakeToken removes one token from the bucket at key, which refills at rate tokens per
+	// second up to burst
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (TokenResult, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// TokenResult is the outcome of TakeToken
+type TokenResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next token, zero when Allowed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+func tokenResult(allowed bool, tokens, rate float64, burst int) TokenResult {
+	res := TokenResult{Allowed: allowed, Remaining: int(tokens), ResetAfter: secondsDuration((float64(burst) - tokens) / rate)}
+	if !allowed {
+		res.RetryAfter = secondsDuration((1 - tokens) / rate)
+	}
+	return res
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+type Config struct {
+	RedisURL   string
+	DefaultTTL time.Duration
+}
+
+func LoadConfig(src *config.Source) Config {
+	return Config{
+		RedisURL:   src.String("REDIS_URL", ""),
+		DefaultTTL: src.Duration("CACHE_DEFAULT_TTL_SECONDS", 300, time.Second),
+	}
+}
+
+// NewStore connects to REDIS_URL when set, otherwise it falls back to a per-process memory
+// store, which is only correct while a single replica runs.
+func NewStore(cfg Config, l *logger.Logger) (Store, error) {
+	if cfg.RedisURL == "" {
+		l.Warn("REDIS_URL not set, using an in-memory cache store")
+		return NewMemoryStore(), nil
+	}
+	return NewRedisStore(cfg.RedisURL, l)
+}
+
+// RedisStore is a Store on a Redis server
+type RedisStore struct {
+	client *redis.Client
+	Logger *logger.Logger
+}
+
+func NewRedisStore(url string, l *logger.Logger) (Store, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	l.Info("Connected to redis", zap.String("addr", opts.Addr), zap.Int("db", opts.DB))
+	return &RedisStore{client: client, Logger: l}, nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *RedisStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// takeTokenScript refills and takes from a bucket kept as a hash of tokens and the last
+// refill time in milliseconds, so concurrent replicas share one bucket
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1]) / 1000
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+func (s *RedisStore) TakeToken(ctx context.Context, key string, rate float64, burst int) (TokenResult, error) {
+	reply, err := takeTokenScript.Run(ctx, s.client, []string{key}, rate, burst, time.Now().UnixMilli()).Slice()
+	if err != nil {
+		return TokenResult{}, err
+	}
+	if len(reply) != 2 {
+		return TokenResult{}, fmt.Errorf("unexpected token bucket reply %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	raw, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return TokenResult{}, fmt.Errorf("unexpected token count %q: %w", raw, err)
+	}
+	return tokenResult(allowed == 1, tokens, rate, burst), nil
+}
+
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// MemoryStore is a Store kept in process memory. Expired entries are dropped when read.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	rate   float64
+	burst  int
+	last   time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+type memoryEntry struct {
+	value     []byte
+	counter   int64
+	expiresAt time.Time
+}
+
+func NewMemoryStore() Store {
+	return &MemoryStore{entries: map[string]memoryEntry{}, buckets: map[string]*bucket{}}
+}
+
+// live returns the entry for key when it exists and has not expired; the caller holds mu
+func (s *MemoryStore) live(key string) (memoryEntry, bool) {
+	e, ok := s.entries[key]
+	if ok && !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		delete(s.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, ok
+}
+
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.live(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+	return e.value, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryEntry{value: value, expiresAt: expiry(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(key); ok {
+		return false, nil
+	}
+	s.entries[key] = memoryEntry{value: value, expiresAt: expiry(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.live(key)
+	if !ok {
+		e = memoryEntry{expiresAt: expiry(ttl)}
+	}
+	e.counter++
+	e.value = []byte(fmt.Sprint(e.counter))
+	s.entries[key] = e
+	return e.counter, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) TakeToken(_ context.Context, key string, rate float64, burst int) (TokenResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweepBuckets(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	b.rate, b.burst = rate, burst
+	b.refill(now)
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return tokenResult(allowed, b.tokens, rate, burst), nil
+}
+
+// sweepBuckets drops refilled buckets once a minute; a full bucket is the same as none, so
+// idle clients do not pile up. The caller holds mu.
+func (s *MemoryStore) sweepBuckets(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (s *MemoryStore) Ping(context.Context) error { return nil }
+
+func (s *MemoryStore) Close() error { return nil }
@@ -0,0 +1,242 @@
+// Package config loads service settings from the environment, an optional .env file,
+// secret files, HashiCorp Vault and command line flags into typed structs. Getters record
+// every missing or malformed setting so a service reports all of them at once when it starts.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// Source reads settings. Flag values take precedence over the environment, which takes
+// precedence over the .env file.
+type Source struct {
+	overrides map[string]string
+	args      []string
+	missing   []string
+	invalid   []string
+}
+
+// Load parses the command line flags in args, then loads the .env file they name, the
+// *_FILE secrets and, when configured, the Vault secret. Arguments after the flags, such as
+// "migrate up", are kept for Args.
+//
+//	-env-file  file with KEY=VALUE lines, default .env; a missing file is ignored
+//	-env       overrides GO_ENV
+//	-port      overrides SERVER_PORT
+func Load(args []string) (*Source, error) {
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	envFile := fs.String("env-file", ".env", "file with KEY=VALUE settings")
+	env := fs.String("env", "", "environment (development, staging, production)")
+	port := fs.String("port", "", "HTTP port")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	process.mu.Lock()
+	process.envFile, process.mustExist = *envFile, *envFile != ".env"
+	err := loadEnvFile(*envFile, process.mustExist)
+	process.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := loadSecretFiles(); err != nil {
+		return nil, err
+	}
+	if err := loadVaultSecrets(); err != nil {
+		return nil, err
+	}
+	s := &Source{overrides: map[string]string{}, args: fs.Args()}
+	if *env != "" {
+		s.overrides["GO_ENV"] = *env
+	}
+	if *port != "" {
+		s.overrides["SERVER_PORT"] = *port
+	}
+	process.overrides = s.overrides
+	return s, nil
+}
+
+// NewSource reads the process environment only, e.g. for tools and tests
+func NewSource() *Source {
+	return &Source{overrides: map[string]string{}}
+}
+
+// Args returns the positional arguments left after the flags
+func (s *Source) Args() []string {
+	return s.args
+}
+
+// Err lists every required setting that was missing and every value that did not parse
+func (s *Source) Err() error {
+	var errs []error
+	if len(s.missing) > 0 {
+		errs = append(errs, fmt.Errorf("missing required settings: %s", strings.Join(s.missing, ", ")))
+	}
+	if len(s.invalid) > 0 {
+		errs = append(errs, fmt.Errorf("invalid settings: %s", strings.Join(s.invalid, "; ")))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Source) lookup(key string) string {
+	if v, ok := s.overrides[key]; ok {
+		return v
+	}
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+func (s *Source) fail(key, reason string) {
+	s.invalid = append(s.invalid, key+" "+reason)
+}
+
+// Fail records an invalid value of key, for settings parsed outside this package
+func (s *Source) Fail(key, reason string) {
+	s.fail(key, reason)
+}
+
+func (s *Source) String(key, def string) string {
+	if v := s.lookup(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// Required returns the value of key and records it as missing when unset
+func (s *Source) Required(key string) string {
+	v := s.lookup(key)
+	if v == "" {
+		s.missing = append(s.missing, key)
+	}
+	return v
+}
+
+// OneOf returns the value of key, which has to be one of allowed
+func (s *Source) OneOf(key, def string, allowed ...string) string {
+	v := s.String(key, def)
+	for _, a := range allowed {
+		if v == a {
+			return v
+		}
+	}
+	s.fail(key, "must be one of "+strings.Join(allowed, ", "))
+	return def
+}
+
+// Int returns a non-negative integer setting
+func (s *Source) Int(key string, def int) int {
+	v := s.lookup(key)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		s.fail(key, "must be a non-negative integer")
+		return def
+	}
+	return i
+}
+
+func (s *Source) Float(key string, def float64) float64 {
+	v := s.lookup(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		s.fail(key, "must be a number")
+		return def
+	}
+	return f
+}
+
+func (s *Source) Bool(key string, def bool) bool {
+	v := s.lookup(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		s.fail(key, "must be true or false")
+		return def
+	}
+	return b
+}
+
+// Duration reads an integer count of unit, matching the *_SECONDS and *_MINUTES names
+// the services use. def is given as a count of unit as well.
+func (s *Source) Duration(key string, def int, unit time.Duration) time.Duration {
+	return time.Duration(s.Int(key, def)) * unit
+}
+
+// List splits a comma separated setting, dropping empty entries
+func (s *Source) List(key string, def []string) []string {
+	v := s.lookup(key)
+	if v == "" {
+		return def
+	}
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// loadEnvFile sets the KEY=VALUE lines of path that are not already in the environment.
+// A missing file is only an error when it was asked for explicitly.
+func loadEnvFile(path string, mustExist bool) error {
+	values, err := readEnvFile(path, mustExist)
+	if err != nil {
+		return err
+	}
+	_, err = process.applyEnvFile(values)
+	return err
+}
+
+func readEnvFile(path string, mustExist bool) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !mustExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return parseEnvFile(path, f)
+}
+
+func parseEnvFile(path string, r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Live is a setting that can change while the service runs. Watch loads it at startup and
+// Reload loads it again, handing a changed value to the subscribers. A nil Live holds the zero
+// value and never changes, e.g. in tools and tests that build configs by hand.
+type Live[T any] struct {
+	name        string
+	load        func(*Source) T
+	value       atomic.Pointer[T]
+	subscribers []func(T)
+}
+
+// Watch loads a setting from src and reloads it with the same load function on every Reload.
+// name identifies the setting in reload reports, e.g. "CORS".
+func Watch[T any](src *Source, name string, load func(*Source) T) *Live[T] {
+	l := &Live[T]{name: name, load: load}
+	v := load(src)
+	l.value.Store(&v)
+	process.mu.Lock()
+	process.settings = append(process.settings, l)
+	process.mu.Unlock()
+	return l
+}
+
+// Fixed returns a Live holding v that reloads never change, e.g. for tools and tests
+func Fixed[T any](v T) *Live[T] {
+	l := &Live[T]{}
+	l.value.Store(&v)
+	return l
+}
+
+// Get returns the current value
+func (l *Live[T]) Get() T {
+	if l == nil {
+		var zero T
+		return zero
+	}
+	return *l.value.Load()
+}
+
+// Subscribe calls fn with the new value after each reload that changed it. fn runs during
+// Reload and must not call Reload, Watch or Subscribe.
+func (l *Live[T]) Subscribe(fn func(T)) {
+	if l == nil {
+		return
+	}
+	process.mu.Lock()
+	l.subscribers = append(l.subscribers, fn)
+	process.mu.Unlock()
+}
+
+func (l *Live[T]) settingName() string {
+	return l.name
+}
+
+func (l *Live[T]) reload(src *Source) func() bool {
+	next := l.load(src)
+	return func() bool {
+		if reflect.DeepEqual(next, *l.value.Load()) {
+			return false
+		}
+		l.value.Store(&next)
+		for _, fn := range l.subscribers {
+			fn(next)
+		}
+		return true
+	}
+}
+
+type reloadable interface {
+	settingName() string
+	// reload loads the setting and returns the function storing it, which reports whether
+	// the value changed
+	reload(src *Source) func() bool
+}
+
+// reloader is the state Reload needs from Load: the settings are those of the process, as
+// is the environment they are read from
+type reloader struct {
+	mu        sync.Mutex
+	overrides map[string]string
+	envFile   string
+	mustExist bool
+	// fileKeys are the variables the env file set, which a reload may change or unset;
+	// variables of the real environment always win
+	fileKeys map[string]bool
+	settings []reloadable
+}
+
+var process = &reloader{overrides: map[string]string{}, fileKeys: map[string]bool{}}
+
+// Reload re-reads the env file Load read and loads every setting registered with Watch again.
+// Secret files and Vault are not read again. When any setting is missing or invalid nothing
+// changes and the error lists them; otherwise the names of the settings that changed are
+// returned after their subscribers ran.
+func Reload() ([]string, error) {
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	restore := func() {}
+	if process.envFile != "" {
+		values, err := readEnvFile(process.envFile, process.mustExist)
+		if err != nil {
+			return nil, err
+		}
+		if restore, err = process.applyEnvFile(values); err != nil {
+			restore()
+			return nil, err
+		}
+	}
+	src := &Source{overrides: process.overrides}
+	stores := make([]func() bool, len(process.settings))
+	for i, s := range process.settings {
+		stores[i] = s.reload(src)
+	}
+	if err := src.Err(); err != nil {
+		restore()
+		return nil, err
+	}
+	var changed []string
+	for i, store := range stores {
+		if store() {
+			changed = append(changed, process.settings[i].settingName())
+		}
+	}
+	return changed, nil
+}
+
+// ReloadOnSIGHUP calls Reload on every SIGHUP and passes its outcome to report. The returned
+// function stops listening.
+func ReloadOnSIGHUP(report func(changed []string, err error)) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				report(Reload())
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// applyEnvFile sets the variables of the env file that the real environment does not set, and
+// unsets those an earlier read of the file set but it no longer lists. The returned function
+// puts the environment back as it was.
+func (r *reloader) applyEnvFile(values map[string]string) (func(), error) {
+	type previous struct {
+		value string
+		set   bool
+		owned bool
+	}
+	saved := map[string]previous{}
+	save := func(key string) {
+		if _, ok := saved[key]; !ok {
+			v, set := os.LookupEnv(key)
+			saved[key] = previous{value: v, set: set, owned: r.fileKeys[key]}
+		}
+	}
+	restore := func() {
+		for key, p := range saved {
+			if p.set {
+				_ = os.Setenv(key, p.value)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+			if p.owned {
+				r.fileKeys[key] = true
+			} else {
+				delete(r.fileKeys, key)
+			}
+		}
+	}
+	for key := range r.fileKeys {
+		if _, ok := values[key]; !ok {
+			save(key)
+			if err := os.Unsetenv(key); err != nil {
+				return restore, err
+			}
+			delete(r.fileKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !r.fileKeys[key] {
+			continue
+		}
+		save(key)
+		if err := os.Setenv(key, value); err != nil {
+			return restore, err
+		}
+		r.fileKeys[key] = true
+	}
+	return restore, nil
+}
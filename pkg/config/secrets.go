@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileSuffix marks settings read from a file, e.g. JWT_ACCESS_SECRET_KEY_FILE=/run/secrets/jwt_access,
+// the way Docker and Kubernetes mount secrets
+const FileSuffix = "_FILE"
+
+// insecureSecrets are the example values shipped in this repository; production refuses them
+var insecureSecrets = map[string]bool{
+	"default_access_secret":          true,
+	"default_refresh_secret":         true,
+	"super-secret-access-key":        true,
+	"super-secret-refresh-key":       true,
+	"super-secret-internal-token":    true,
+	"super-secret-media-signing-key": true,
+	"devAccessSecretKey123456789":    true,
+	"devRefreshSecretKey123456789":   true,
+	"devPassword123":                 true,
+	"secretKey":                      true,
+}
+
+// Secret is Required for credentials: outside development it also refuses the example
+// values from .env.example and docker-compose.yml
+func (s *Source) Secret(key string) string {
+	v := s.Required(key)
+	if v != "" && insecureSecrets[v] && s.String("GO_ENV", EnvDevelopment) != EnvDevelopment {
+		s.fail(key, "uses an example value; set a real secret, e.g. through "+key+FileSuffix)
+	}
+	return v
+}
+
+// loadSecretFiles sets KEY from the file named by KEY_FILE, so code reading the environment
+// directly, such as the auth middleware, sees the secret too. Setting both is an error.
+func loadSecretFiles() error {
+	for _, kv := range os.Environ() {
+		name, path, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutSuffix(name, FileSuffix)
+		if !ok || key == "" || path == "" {
+			continue
+		}
+		if os.Getenv(key) != "" {
+			return fmt.Errorf("set only one of %s and %s", key, name)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := os.Setenv(key, strings.TrimRight(string(data), "\r\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVaultSecrets fills settings that are still unset from a HashiCorp Vault KV secret when
+// VAULT_ADDR and VAULT_SECRET_PATH are set. The path is read as given, so KV v2 paths include
+// "data/", e.g. secret/data/ecommerce/user. VAULT_TOKEN may come from VAULT_TOKEN_FILE.
+func loadVaultSecrets() error {
+	addr, path := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_SECRET_PATH")
+	if addr == "" || path == "" {
+		return nil
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return fmt.Errorf("VAULT_TOKEN is required with VAULT_ADDR")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return fmt.Errorf("invalid VAULT_ADDR: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reading vault secret %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("reading vault secret %s: status %d", path, resp.StatusCode)
+	}
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decoding vault secret %s: %w", path, err)
+	}
+	values := body.Data
+	// KV v2 nests the values under data.data next to data.metadata
+	if nested, ok := values["data"].(map[string]any); ok {
+		if _, hasMetadata := values["metadata"]; hasMetadata {
+			values = nested
+		}
+	}
+	for key, value := range values {
+		if os.Getenv(key) != "" {
+			continue
+		}
+		if err := os.Setenv(key, fmt.Sprint(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
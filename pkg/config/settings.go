@@ -0,0 +1,275 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Server holds the HTTP listener settings every service shares
+type Server struct {
+	Env          string
+	Port         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// ShutdownTimeout bounds how long in-flight requests and workers may take to finish on stop
+	ShutdownTimeout time.Duration
+	// TrustedProxies are the addresses and CIDRs whose X-Forwarded-For and X-Real-IP name the
+	// client; requests from anywhere else are taken from their own address
+	TrustedProxies []string
+}
+
+func (s Server) IsDevelopment() bool {
+	return s.Env == EnvDevelopment
+}
+
+func (s Server) IsProduction() bool {
+	return s.Env == EnvProduction
+}
+
+// LoadServer reads GO_ENV, SERVER_PORT, the HTTP timeouts and TRUSTED_PROXIES, which trusts no
+// proxy by default; timeout is the default for the read and write timeouts
+func LoadServer(src *Source, defaultPort string, timeout time.Duration) Server {
+	s := Server{
+		Env:             src.OneOf("GO_ENV", EnvDevelopment, EnvDevelopment, EnvStaging, EnvProduction),
+		Port:            src.String("SERVER_PORT", defaultPort),
+		ReadTimeout:     src.Duration("SERVER_READ_TIMEOUT_SECONDS", int(timeout/time.Second), time.Second),
+		WriteTimeout:    src.Duration("SERVER_WRITE_TIMEOUT_SECONDS", int(timeout/time.Second), time.Second),
+		ShutdownTimeout: src.Duration("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 10, time.Second),
+		TrustedProxies:  src.List("TRUSTED_PROXIES", nil),
+	}
+	for _, p := range s.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			src.fail("TRUSTED_PROXIES", fmt.Sprintf("%q is not an address or CIDR", p))
+		}
+	}
+	return s
+}
+
+type CORS struct {
+	AllowOrigins     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func LoadCORS(src *Source) CORS {
+	cfg := CORS{
+		AllowOrigins:     src.List("CORS_ALLOW_ORIGINS", []string{"*"}),
+		AllowCredentials: src.Bool("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           src.Duration("CORS_MAX_AGE_HOURS", 12, time.Hour),
+	}
+	if cfg.AllowCredentials && cfg.AllowsAllOrigins() {
+		src.fail("CORS_ALLOW_CREDENTIALS", "cannot be used with CORS_ALLOW_ORIGINS=*")
+	}
+	return cfg
+}
+
+func (c CORS) AllowsAllOrigins() bool {
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityHeaders configures the browser security headers. HSTSMaxAge of 0 leaves out
+// Strict-Transport-Security, e.g. for plain HTTP development setups.
+type SecurityHeaders struct {
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	FrameOptions          string
+	ReferrerPolicy        string
+	ContentSecurityPolicy string
+	// DocsContentSecurityPolicy applies to the swagger UI, which needs its own scripts and styles
+	DocsContentSecurityPolicy string
+}
+
+func LoadSecurityHeaders(src *Source) SecurityHeaders {
+	return SecurityHeaders{
+		HSTSMaxAge:            src.Duration("SECURITY_HSTS_MAX_AGE_DAYS", 365, 24*time.Hour),
+		HSTSIncludeSubdomains: src.Bool("SECURITY_HSTS_INCLUDE_SUBDOMAINS", true),
+		FrameOptions:          src.OneOf("SECURITY_FRAME_OPTIONS", "DENY", "DENY", "SAMEORIGIN"),
+		ReferrerPolicy:        src.String("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+		ContentSecurityPolicy: src.String("SECURITY_CSP", "default-src 'none'; frame-ancestors 'none'"),
+		DocsContentSecurityPolicy: src.String("SECURITY_DOCS_CSP",
+			"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"),
+	}
+}
+
+// CSRF configures the double-submit cookie check for cookie-based sessions. ExemptPaths are
+// path prefixes, e.g. those only pure-token API clients call, that skip the check.
+type CSRF struct {
+	Enabled      bool
+	CookieName   string
+	HeaderName   string
+	CookieDomain string
+	CookieSecure bool
+	CookieMaxAge time.Duration
+	ExemptPaths  []string
+}
+
+func LoadCSRF(src *Source) CSRF {
+	cfg := CSRF{
+		Enabled:      src.Bool("CSRF_ENABLED", false),
+		CookieName:   src.String("CSRF_COOKIE_NAME", "csrf_token"),
+		HeaderName:   src.String("CSRF_HEADER_NAME", "X-CSRF-Token"),
+		CookieDomain: src.String("CSRF_COOKIE_DOMAIN", ""),
+		CookieSecure: src.Bool("CSRF_COOKIE_SECURE", true),
+		CookieMaxAge: src.Duration("CSRF_COOKIE_MAX_AGE_HOURS", 12, time.Hour),
+		ExemptPaths:  src.List("CSRF_EXEMPT_PATHS", []string{"/v1/internal/", "/v1/payment/webhooks/"}),
+	}
+	if cfg.Enabled && cfg.CookieMaxAge <= 0 {
+		src.fail("CSRF_COOKIE_MAX_AGE_HOURS", "must be positive")
+	}
+	return cfg
+}
+
+// RateLimit allows Requests per Window on average with bursts of up to Burst requests.
+// Requests of 0 disables limiting.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+func LoadRateLimit(src *Source) RateLimit {
+	return LoadRateLimitPrefix(src, "RATE_LIMIT")
+}
+
+// LoadRateLimitPrefix reads <prefix>_REQUESTS, <prefix>_WINDOW_SECONDS and <prefix>_BURST, for
+// services that apply more than one limit
+func LoadRateLimitPrefix(src *Source, prefix string) RateLimit {
+	cfg := RateLimit{
+		Requests: src.Int(prefix+"_REQUESTS", 0),
+		Window:   src.Duration(prefix+"_WINDOW_SECONDS", 60, time.Second),
+	}
+	cfg.Burst = src.Int(prefix+"_BURST", cfg.Requests)
+	if cfg.Requests > 0 && cfg.Window <= 0 {
+		src.fail(prefix+"_WINDOW_SECONDS", "must be positive")
+	}
+	if cfg.Requests > 0 && cfg.Burst < 1 {
+		src.fail(prefix+"_BURST", "must be at least 1")
+	}
+	return cfg
+}
+
+func (r RateLimit) Enabled() bool {
+	return r.Requests > 0
+}
+
+// IDs selects the forms of record IDs accepted in paths. UUIDs always are; integer IDs only
+// while AllowIntegers is set, which it is until clients have moved to UUIDs, since sequential
+// integers let anyone enumerate records.
+type IDs struct {
+	AllowIntegers bool
+}
+
+func LoadIDs(src *Source) IDs {
+	return IDs{AllowIntegers: src.Bool("ID_ALLOW_INTEGER", true)}
+}
+
+// Database holds the Postgres connection and pool settings
+type Database struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectRetries is how many extra pings are made at startup, doubling ConnectBackoff
+	// between them, while the database is still coming up
+	ConnectRetries int
+	ConnectBackoff time.Duration
+
+	// ReplicaHosts are read replicas as host or host:port, reached with the primary's
+	// credentials. Reads go to them, writes and transactions to the primary.
+	ReplicaHosts  []string
+	ReplicaPolicy string
+
+	// SlowQueryThreshold is the duration from which statements are logged as slow; 0 disables
+	SlowQueryThreshold time.Duration
+}
+
+const (
+	ReplicaPolicyRandom     = "random"
+	ReplicaPolicyRoundRobin = "round-robin"
+)
+
+func LoadDatabase(src *Source) Database {
+	cfg := Database{
+		Host:               src.Required("DB_HOST"),
+		Port:               src.Required("DB_PORT"),
+		User:               src.Required("DB_USER"),
+		Password:           src.Secret("DB_PASSWORD"),
+		DBName:             src.Required("DB_NAME"),
+		SSLMode:            src.Required("DB_SSLMODE"),
+		MaxOpenConns:       src.Int("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:       src.Int("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime:    src.Duration("DB_CONN_MAX_LIFETIME", 300, time.Second),
+		ConnMaxIdleTime:    src.Duration("DB_CONN_MAX_IDLE_TIME", 60, time.Second),
+		ConnectRetries:     src.Int("DB_CONNECT_RETRIES", 5),
+		ConnectBackoff:     src.Duration("DB_CONNECT_BACKOFF_MS", 500, time.Millisecond),
+		ReplicaHosts:       src.List("DB_REPLICA_HOSTS", nil),
+		ReplicaPolicy:      src.OneOf("DB_REPLICA_POLICY", ReplicaPolicyRandom, ReplicaPolicyRandom, ReplicaPolicyRoundRobin),
+		SlowQueryThreshold: src.Duration("DB_SLOW_QUERY_MS", 200, time.Millisecond),
+	}
+	if cfg.MaxOpenConns > 0 && cfg.MaxIdleConns > cfg.MaxOpenConns {
+		cfg.MaxIdleConns = cfg.MaxOpenConns
+	}
+	return cfg
+}
+
+// ReplicaDSN is the DSN of a replica given as host or host:port
+func (c Database) ReplicaDSN(host string) string {
+	replica := c
+	replica.Host = host
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		replica.Host, replica.Port = h, port
+	}
+	return replica.GetDSN()
+}
+
+func (c Database) GetDSN() string {
+	return "host=" + c.Host +
+		" port=" + c.Port +
+		" user=" + c.User +
+		" password=" + c.Password +
+		" dbname=" + c.DBName +
+		" sslmode=" + c.SSLMode +
+		" TimeZone=UTC"
+}
+
+// JWT holds the token secrets and lifetimes and the iss and aud claims. Audience names the
+// verifying service; tokens whose aud does not list it are rejected. Services that only verify
+// access tokens leave the refresh settings and AccessAudiences unset.
+type JWT struct {
+	AccessSecret  string
+	RefreshSecret string
+	AccessTime    time.Duration
+	RefreshTime   time.Duration
+	Issuer        string
+	Audience      string
+	// AccessAudiences are the services an issued access token is valid for
+	AccessAudiences []string
+}
+
+func LoadJWT(src *Source, audience string, issuesTokens bool) JWT {
+	cfg := JWT{
+		AccessSecret: src.Secret("JWT_ACCESS_SECRET_KEY"),
+		Issuer:       src.String("JWT_ISSUER", "ecommerce-user-service"),
+		Audience:     src.String("JWT_AUDIENCE", audience),
+	}
+	if issuesTokens {
+		cfg.RefreshSecret = src.Secret("JWT_REFRESH_SECRET_KEY")
+		cfg.AccessTime = src.Duration("JWT_ACCESS_TIME_MINUTE", 60, time.Minute)
+		cfg.RefreshTime = src.Duration("JWT_REFRESH_TIME_HOUR", 24, time.Hour)
+		cfg.AccessAudiences = src.List("JWT_ACCESS_AUDIENCES", []string{"user", "catalog", "order", "payment", "review", "webhook", "saga", "media", "promotion", "tax"})
+	}
+	return cfg
+}
@@ -0,0 +1,46 @@
+package contracts
+
+import "time"
+
+// Category is the payload of category events
+type Category struct {
+	ID   int    `json:"id"`
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Product is the payload of product events. Upserts carry the whole product, so consumers can
+// apply the latest event and ignore those that occurred before it; catalog.product_deleted only
+// sets ID and UUID.
+type Product struct {
+	ID            int       `json:"id"`
+	UUID          string    `json:"uuid"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	SKU           string    `json:"sku"`
+	Price         float64   `json:"price"`
+	Stock         int       `json:"stock"`
+	CategoryID    int       `json:"categoryId"`
+	ImageURL      string    `json:"imageUrl"`
+	IsActive      bool      `json:"isActive"`
+	RatingAverage float64   `json:"ratingAverage"`
+	RatingCount   int       `json:"ratingCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+var (
+	CategoryUpserted = register(Contract[Category]{
+		Type: "catalog.category_upserted", Version: 1, Producer: "catalog-service", Description: "A category was created or changed",
+	})
+	CategoryDeleted = register(Contract[Category]{
+		Type: "catalog.category_deleted", Version: 1, Producer: "catalog-service", Description: "A category was deleted",
+	})
+	ProductUpserted = register(Contract[Product]{
+		Type: "catalog.product_upserted", Version: 1, Producer: "catalog-service", Description: "A product was created or changed, including its stock and rating",
+	})
+	ProductDeleted = register(Contract[Product]{
+		Type: "catalog.product_deleted", Version: 1, Producer: "catalog-service", Description: "A product was deleted",
+	})
+)
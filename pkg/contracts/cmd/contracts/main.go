@@ -0,0 +1,82 @@
+// Command contracts writes and checks the event schema snapshots of pkg/contracts.
+//
+//	contracts generate [dir]   write schemas/ under dir (default .), refusing breaking changes
+//	contracts check            compare the payloads with the committed snapshots
+//	contracts list             print every event type with its version and producer
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ecommerce-microservice-go/pkg/contracts"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: contracts generate [dir] | check | list")
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		dir := "."
+		if len(os.Args) > 2 {
+			dir = os.Args[2]
+		}
+		err = generate(dir)
+	case "check":
+		err = errors.Join(contracts.Check(contracts.Snapshots)...)
+	case "list":
+		for _, d := range contracts.All() {
+			fmt.Printf("%-28s v%d  %-16s %s\n", d.EventType(), d.SchemaVersion(), d.ProducedBy(), d.Summary())
+		}
+	default:
+		err = fmt.Errorf("unknown command %q", os.Args[1])
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate writes the snapshot of every contract. An existing snapshot is only replaced by a
+// compatible schema; a breaking change has to become a new contract version.
+func generate(dir string) error {
+	fsys := os.DirFS(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		return err
+	}
+	var errs []error
+	for _, d := range contracts.All() {
+		current, err := contracts.SchemaOf(d)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		previous, err := contracts.ReadSnapshot(fsys, d)
+		switch {
+		case errors.Is(err, contracts.ErrSnapshotMissing):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", d.EventType(), err))
+			continue
+		default:
+			if problems := contracts.Breaking(previous, current); len(problems) > 0 {
+				errs = append(errs, fmt.Errorf("%s: breaking change to version %d, add version %d instead: %v",
+					d.EventType(), d.SchemaVersion(), d.SchemaVersion()+1, problems))
+				continue
+			}
+		}
+		data, err := contracts.MarshalSchema(current)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, "schemas", contracts.SchemaFile(d)), data, 0o644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
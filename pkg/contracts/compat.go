@@ -0,0 +1,122 @@
+package contracts
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+)
+
+// Snapshots are the committed schemas, one per contract version
+//
+//go:embed schemas/*.json
+var Snapshots embed.FS
+
+// Breaking lists what in next breaks consumers written against previous: a field removed, its
+// type or format changed, or a field that was always present becoming optional. Adding fields,
+// required or not, is compatible since consumers ignore what they do not know.
+func Breaking(previous, next *Schema) []string {
+	var problems []string
+	breaking("", previous, next, &problems)
+	slices.Sort(problems)
+	return problems
+}
+
+func breaking(path string, previous, next *Schema, problems *[]string) {
+	at := path
+	if at == "" {
+		at = "payload"
+	}
+	if previous.Type != next.Type || previous.Format != next.Format {
+		*problems = append(*problems, fmt.Sprintf("%s changed from %s to %s", at, typeName(previous), typeName(next)))
+		return
+	}
+	if previous.Items != nil && next.Items != nil {
+		breaking(path+"[]", previous.Items, next.Items, problems)
+	}
+	for name, prop := range previous.Properties {
+		field := name
+		if path != "" {
+			field = path + "." + name
+		}
+		nextProp, ok := next.Properties[name]
+		if !ok {
+			*problems = append(*problems, field+" was removed")
+			continue
+		}
+		if slices.Contains(previous.Required, name) && !slices.Contains(next.Required, name) {
+			*problems = append(*problems, field+" is no longer always present")
+		}
+		breaking(field, prop, nextProp, problems)
+	}
+}
+
+func typeName(s *Schema) string {
+	if s.Format != "" {
+		return s.Type + " (" + s.Format + ")"
+	}
+	return s.Type
+}
+
+// ErrSnapshotMissing is reported for contracts whose schema was never generated
+var ErrSnapshotMissing = errors.New("schema snapshot is missing")
+
+// Check compares every contract with its snapshot in fsys, Snapshots unless testing another
+// set. A breaking change needs a new contract version; a compatible one an updated snapshot,
+// written by "go generate" in pkg/contracts.
+func Check(fsys fs.FS) []error {
+	var errs []error
+	for _, d := range All() {
+		if err := checkOne(fsys, d); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.EventType(), err))
+		}
+	}
+	return errs
+}
+
+func checkOne(fsys fs.FS, d Descriptor) error {
+	current, err := SchemaOf(d)
+	if err != nil {
+		return err
+	}
+	snapshot, err := ReadSnapshot(fsys, d)
+	if err != nil {
+		return err
+	}
+	if problems := Breaking(snapshot, current); len(problems) > 0 {
+		return fmt.Errorf("breaking change to version %d: %v", d.SchemaVersion(), problems)
+	}
+	want, _ := MarshalSchema(current)
+	have, _ := fs.ReadFile(fsys, "schemas/"+SchemaFile(d))
+	if string(want) != string(have) {
+		return errors.New("schema snapshot is out of date")
+	}
+	return nil
+}
+
+// ReadSnapshot returns the committed schema of the contract's version
+func ReadSnapshot(fsys fs.FS, d Descriptor) (*Schema, error) {
+	data, err := fs.ReadFile(fsys, "schemas/"+SchemaFile(d))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrSnapshotMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", SchemaFile(d), err)
+	}
+	return &s, nil
+}
+
+// MarshalSchema formats a schema the way snapshots are stored
+func MarshalSchema(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,131 @@
+// Package contracts defines the payloads of the events services exchange through the broker.
+// Producers build events and consumers decode them with the same Contract, so a field renamed on
+// one side fails to compile on the other instead of silently decoding to a zero value. Every
+// contract has a JSON Schema snapshot under schemas/; Check compares the Go payloads with the
+// snapshots and rejects changes that would break consumers still reading the old shape.
+package contracts
+
+//go:generate go run ./cmd/contracts generate
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"ecommerce-microservice-go/pkg/events"
+)
+
+// Descriptor is a contract without its payload type, for listing and schema generation
+type Descriptor interface {
+	EventType() string
+	SchemaVersion() int
+	ProducedBy() string
+	Summary() string
+	PayloadType() reflect.Type
+}
+
+// Contract is an event type with its payload. Version 1 is published under Type; a breaking
+// change is a new contract with the next version, published as Type + ".v2" and so on next to
+// the old one until every consumer has moved.
+type Contract[T any] struct {
+	Type    string
+	Version int
+	// Producer is the only service publishing the event, also its envelope source
+	Producer    string
+	Description string
+}
+
+func (c Contract[T]) EventType() string {
+	if c.Version <= 1 {
+		return c.Type
+	}
+	return c.Type + ".v" + strconv.Itoa(c.Version)
+}
+
+func (c Contract[T]) SchemaVersion() int        { return max(c.Version, 1) }
+func (c Contract[T]) ProducedBy() string        { return c.Producer }
+func (c Contract[T]) Summary() string           { return c.Description }
+func (c Contract[T]) PayloadType() reflect.Type { return reflect.TypeFor[T]() }
+
+// NewEvent wraps the payload in an envelope from the contract's producer and the tenant of ctx
+func (c Contract[T]) NewEvent(ctx context.Context, payload T) (*events.Event, error) {
+	return events.NewEvent(ctx, c.EventType(), c.Producer, payload)
+}
+
+// Decode reads the payload of an event of the contract's type. Fields the consumer's version
+// of the payload does not know are ignored, so producers may add fields.
+func (c Contract[T]) Decode(event *events.Event) (T, error) {
+	var payload T
+	if event.Type != c.EventType() {
+		return payload, fmt.Errorf("event %s has type %s, not %s", event.ID, event.Type, c.EventType())
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return payload, fmt.Errorf("decoding %s payload: %w", event.Type, err)
+	}
+	return payload, nil
+}
+
+// DecodeAny reads the payload of an event of any of the contracts, for consumers handling
+// several event types with the same payload
+func DecodeAny[T any](event *events.Event, contracts ...Contract[T]) (T, error) {
+	for _, c := range contracts {
+		if c.EventType() == event.Type {
+			return c.Decode(event)
+		}
+	}
+	var payload T
+	return payload, fmt.Errorf("event %s has unexpected type %s", event.ID, event.Type)
+}
+
+var registry []Descriptor
+
+func register[T any](c Contract[T]) Contract[T] {
+	for _, d := range registry {
+		if d.EventType() == c.EventType() {
+			panic("contracts: duplicate event type " + c.EventType())
+		}
+	}
+	registry = append(registry, c)
+	return c
+}
+
+// All returns every contract, sorted by event type
+func All() []Descriptor {
+	all := slices.Clone(registry)
+	slices.SortFunc(all, func(a, b Descriptor) int { return cmp.Compare(a.EventType(), b.EventType()) })
+	return all
+}
+
+// Lookup returns the contract of an event type
+func Lookup(eventType string) (Descriptor, bool) {
+	for _, d := range registry {
+		if d.EventType() == eventType {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Types returns the event types of the contracts, e.g. for a subscription
+func Types[D Descriptor](contracts ...D) []string {
+	types := make([]string, len(contracts))
+	for i, c := range contracts {
+		types[i] = c.EventType()
+	}
+	return types
+}
+
+// Produced returns the contracts the service publishes, sorted by event type
+func Produced(producer string) []Descriptor {
+	var result []Descriptor
+	for _, d := range All() {
+		if d.ProducedBy() == producer {
+			result = append(result, d)
+		}
+	}
+	return result
+}
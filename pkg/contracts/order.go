@@ -0,0 +1,51 @@
+package contracts
+
+// Order is the payload of order events. Payments have no events of their own: a captured
+// payment moves the order to paid, which publishes order.paid.
+type Order struct {
+	OrderID     int    `json:"orderId"`
+	OrderNumber string `json:"orderNumber"`
+	UserID      int    `json:"userId"`
+	// Status is the order's status after the event: pending, paid, shipped, delivered or cancelled
+	Status      string      `json:"status"`
+	TotalAmount float64     `json:"totalAmount"`
+	Currency    string      `json:"currency"`
+	Items       []OrderItem `json:"items"`
+	// DiscountAmount is already deducted from TotalAmount
+	DiscountAmount float64 `json:"discountAmount,omitempty"`
+	// set on order.shipped and order.delivered when a shipment exists
+	Carrier        string `json:"carrier,omitempty"`
+	TrackingNumber string `json:"trackingNumber,omitempty"`
+	TrackingURL    string `json:"trackingUrl,omitempty"`
+	// set on order.cancelled
+	CancellationReason string `json:"cancellationReason,omitempty"`
+	CancelledBy        string `json:"cancelledBy,omitempty"`
+}
+
+type OrderItem struct {
+	ID        int     `json:"id"`
+	ProductID int     `json:"productId"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+	Subtotal  float64 `json:"subtotal"`
+	// Subtotal is before discounts; DiscountAmount is what the item-level discounts take off it
+	DiscountAmount float64 `json:"discountAmount,omitempty"`
+}
+
+var (
+	OrderCreated = register(Contract[Order]{
+		Type: "order.created", Version: 1, Producer: "order-service", Description: "An order was placed",
+	})
+	OrderPaid = register(Contract[Order]{
+		Type: "order.paid", Version: 1, Producer: "order-service", Description: "An order was paid in full",
+	})
+	OrderShipped = register(Contract[Order]{
+		Type: "order.shipped", Version: 1, Producer: "order-service", Description: "An order was handed to the carrier",
+	})
+	OrderDelivered = register(Contract[Order]{
+		Type: "order.delivered", Version: 1, Producer: "order-service", Description: "An order was delivered",
+	})
+	OrderCancelled = register(Contract[Order]{
+		Type: "order.cancelled", Version: 1, Producer: "order-service", Description: "An order was cancelled, with the reason and who cancelled it",
+	})
+)
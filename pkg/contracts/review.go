@@ -0,0 +1,16 @@
+package contracts
+
+import "time"
+
+// ProductRating is the aggregate of a product's approved reviews; consumers keep the one with
+// the latest UpdatedAt
+type ProductRating struct {
+	ProductID int       `json:"productId"`
+	Average   float64   `json:"average"`
+	Count     int       `json:"count"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var RatingUpdated = register(Contract[ProductRating]{
+	Type: "review.rating_updated", Version: 1, Producer: "review-service", Description: "The approved reviews of a product changed",
+})
@@ -0,0 +1,100 @@
+package contracts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is the part of JSON Schema (draft 2020-12) the payloads need
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type"`
+	Format      string             `json:"format,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	// Producer is the service publishing the event, an extension keyword
+	Producer string `json:"x-producer,omitempty"`
+}
+
+// SchemaOf describes the contract's payload. A field is required unless it is a pointer or
+// tagged omitempty, since only those may be left out of a payload.
+func SchemaOf(d Descriptor) (*Schema, error) {
+	s, err := schemaOf(d.PayloadType())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.EventType(), err)
+	}
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	s.ID = SchemaFile(d)
+	s.Title = d.EventType()
+	s.Description = d.Summary()
+	s.Producer = d.ProducedBy()
+	return s, nil
+}
+
+// SchemaFile is the name of the contract's snapshot under schemas/, e.g. "order.paid.v1.json"
+func SchemaFile(d Descriptor) string {
+	return fmt.Sprintf("%s.v%d.json", d.EventType(), d.SchemaVersion())
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func schemaOf(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return nil, fmt.Errorf("unsupported payload type %s", t)
+}
+
+func structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop, err := schemaOf(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		s.Properties[name] = prop
+		if f.Type.Kind() != reflect.Pointer && !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
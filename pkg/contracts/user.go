@@ -0,0 +1,30 @@
+package contracts
+
+import "time"
+
+// User is the profile of a user event. It never carries the password hash; user.deleted only
+// sets ID and UUID.
+type User struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	UserName  string    `json:"userName,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	FirstName string    `json:"firstName,omitempty"`
+	LastName  string    `json:"lastName,omitempty"`
+	Status    bool      `json:"status"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var (
+	UserRegistered = register(Contract[User]{
+		Type: "user.registered", Version: 1, Producer: "user-service", Description: "A user signed up or was created by an admin",
+	})
+	UserUpdated = register(Contract[User]{
+		Type: "user.updated", Version: 1, Producer: "user-service", Description: "A user's profile, role or status changed",
+	})
+	UserDeleted = register(Contract[User]{
+		Type: "user.deleted", Version: 1, Producer: "user-service", Description: "A user was deleted; consumers drop what they keep of them",
+	})
+)
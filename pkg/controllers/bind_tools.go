@@ -3,42 +3,126 @@ package controllers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
+// DefaultMaxBodyBytes caps request bodies read by BindJSON and BindPatch
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// BindOptions tunes how a request body is read and decoded
+type BindOptions struct {
+	// MaxBytes is the largest accepted body; zero means DefaultMaxBodyBytes
+	MaxBytes int64
+	// DisallowUnknownFields rejects bodies with fields the request struct does not declare
+	DisallowUnknownFields bool
+}
+
+// BindJSON decodes and validates the JSON body into request with the default options
 func BindJSON(c *gin.Context, request any) error {
-	buf := make([]byte, 5120)
-	num, _ := c.Request.Body.Read(buf)
-	reqBody := string(buf[0:num])
-	c.Request.Body = io.NopCloser(bytes.NewBuffer([]byte(reqBody)))
-	err := c.ShouldBindJSON(request)
-	c.Request.Body = io.NopCloser(bytes.NewBuffer([]byte(reqBody)))
-	return err
+	return BindJSONWithOptions(c, request, BindOptions{})
 }
 
-func BindJSONMap(c *gin.Context, request *map[string]any) error {
-	buf := make([]byte, 5120)
-	num, _ := c.Request.Body.Read(buf)
-	reqBody := buf[0:num]
-	c.Request.Body = io.NopCloser(bytes.NewBuffer(reqBody))
-	err := json.Unmarshal(reqBody, &request)
-	c.Request.Body = io.NopCloser(bytes.NewBuffer(reqBody))
-	return err
+// BindJSONWithOptions decodes the JSON body into request and runs the binding validator on it.
+// The body is rewound afterwards so it can be bound again. A body over
+// the limit yields a PayloadTooLarge error, malformed JSON a ValidationError.
+func BindJSONWithOptions(c *gin.Context, request any, opts BindOptions) error {
+	body, err := readBody(c, opts.MaxBytes)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := decodeSingle(dec, request); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(request)
 }
 
-type MessageResponse struct {
-	Message string `json:"message"`
+// BindJSONItems decodes a JSON array body into its raw items, for bulk requests whose items are
+// validated one by one with DecodeItem. An empty array or one over maxItems is a ValidationError.
+func BindJSONItems(c *gin.Context, maxItems int, opts BindOptions) ([]json.RawMessage, error) {
+	body, err := readBody(c, opts.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := decodeSingle(json.NewDecoder(bytes.NewReader(body)), &items); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, domainErrors.NewAppError(errors.New("request body must be a JSON array"), domainErrors.ValidationError)
+		}
+		return nil, err
+	}
+	if len(items) == 0 || len(items) > maxItems {
+		return nil, domainErrors.NewAppError(fmt.Errorf("request body must hold between 1 and %d items", maxItems), domainErrors.ValidationError)
+	}
+	return items, nil
+}
+
+// DecodeItem decodes and validates one item of a bulk request into request, as BindJSONWithOptions
+// does for a whole body
+func DecodeItem(item json.RawMessage, request any, opts BindOptions) error {
+	dec := json.NewDecoder(bytes.NewReader(item))
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := decodeSingle(dec, request); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(request)
 }
 
-func PaginationValues(limit int64, page int64, total int64) (numPages int64, nextCursor int64, prevCursor int64) {
-	numPages = (total + limit - 1) / limit
-	if page < numPages {
-		nextCursor = page + 1
+func readBody(c *gin.Context, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, domainErrors.NewAppError(errors.New("request body is empty"), domainErrors.ValidationError)
 	}
-	if page > 1 {
-		prevCursor = page - 1
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+	_ = c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, domainErrors.NewAppError(fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit), domainErrors.PayloadTooLarge)
+		}
+		return nil, domainErrors.NewAppError(fmt.Errorf("reading request body: %w", err), domainErrors.ValidationError)
 	}
-	return
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("request body is empty"), domainErrors.ValidationError)
+	}
+	return body, nil
+}
+
+func decodeSingle(dec *json.Decoder, v any) error {
+	if err := dec.Decode(v); err != nil {
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return domainErrors.NewAppError(fmt.Errorf("request body is not valid JSON at offset %d", syntaxErr.Offset), domainErrors.ValidationError)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return domainErrors.NewAppError(errors.New("request body is truncated JSON"), domainErrors.ValidationError)
+		}
+		// type and unknown-field errors are returned as is so they can be mapped to field details
+		return err
+	}
+	if dec.More() {
+		return domainErrors.NewAppError(errors.New("request body must contain a single JSON value"), domainErrors.ValidationError)
+	}
+	return nil
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
 }
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// IDResolver returns the integer ID of the record with the UUID, NotFound when there is none
+type IDResolver func(ctx context.Context, uuid string) (int, error)
+
+// ParamID reads the path parameter name as a record UUID, looked up with resolve, or as an
+// integer ID while cfg allows them. Anything else is a ValidationError for ctx.Error.
+func ParamID(c *gin.Context, name string, cfg config.IDs, resolve IDResolver) (int, error) {
+	return ResolveID(c.Request.Context(), name, c.Param(name), cfg, resolve)
+}
+
+// ResolveID reads raw, the value of the field name, as ParamID reads a path parameter
+func ResolveID(ctx context.Context, name, raw string, cfg config.IDs, resolve IDResolver) (int, error) {
+	if publicID, err := uuid.Parse(raw); err == nil {
+		return resolve(ctx, publicID.String())
+	}
+	if cfg.AllowIntegers {
+		if id, err := strconv.Atoi(raw); err == nil {
+			return id, nil
+		}
+	}
+	return 0, domainErrors.NewAppError(fmt.Errorf("invalid %s", name), domainErrors.ValidationError)
+}
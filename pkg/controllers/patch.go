@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"strings"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Partial update media types understood by BindPatch. Plain application/json is read as a
+// merge patch.
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// jsonPatchOp is one operation of an RFC 6902 document
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// BindPatch reads a partial update and returns the column values it changes. The body is an
+// RFC 7386 merge patch, or an RFC 6902 JSON Patch when sent as application/json-patch+json.
+//
+// patch is a pointer to a struct of pointer fields listing everything a client may change:
+// the json tag names the field and the patch tag its column, e.g. `patch:"image_url"`. A
+// field tagged `patch:"name,nullable"` is reset to its zero value by null; null on any other
+// field is rejected. Fields tagged `patch:"-"`, such as a version, are decoded into patch but
+// left out of the columns. Fields the struct does not list and values failing its binding
+// tags are ValidationErrors with field details, as is a patch that changes nothing.
+func BindPatch(c *gin.Context, patch any) (map[string]any, error) {
+	body, err := readBody(c, DefaultMaxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]json.RawMessage
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	switch mediaType {
+	case JSONPatchContentType:
+		if doc, err = decodeJSONPatch(body); err != nil {
+			return nil, err
+		}
+	case MergePatchContentType, binding.MIMEJSON, "":
+		if err := decodeSingle(json.NewDecoder(bytes.NewReader(body)), &doc); err != nil {
+			return nil, objectError(err)
+		}
+	default:
+		return nil, domainErrors.NewAppError(fmt.Errorf("content type %q is not supported, send %s or %s", mediaType, MergePatchContentType, JSONPatchContentType), domainErrors.ValidationError)
+	}
+	return DecodePatch(doc, patch)
+}
+
+// DecodePatch applies a merge patch document, already split into its members, to patch and
+// returns the changed columns, with the rules of BindPatch. Bulk handlers use it after taking
+// the item's own keys out of doc.
+func DecodePatch(doc map[string]json.RawMessage, patch any) (map[string]any, error) {
+	if len(doc) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("request body changes no fields"), domainErrors.ValidationError)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(patch); err != nil {
+		return nil, err
+	}
+	if err := binding.Validator.ValidateStruct(patch); err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(patch).Elem()
+	columns := make(map[string]any, len(doc))
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		value, sent := doc[name]
+		column, nullable := patchColumn(field)
+		if !sent || column == "-" {
+			continue
+		}
+		if string(bytes.TrimSpace(value)) == "null" {
+			if !nullable {
+				return nil, domainErrors.NewValidationError(fmt.Errorf("%s cannot be removed", name),
+					domainErrors.FieldError{Field: name, Rule: "required", Message: name + " cannot be null"})
+			}
+			columns[column] = reflect.Zero(field.Type.Elem()).Interface()
+			continue
+		}
+		columns[column] = v.Field(i).Elem().Interface()
+	}
+	if len(columns) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("request body changes no fields"), domainErrors.ValidationError)
+	}
+	return columns, nil
+}
+
+// patchColumn reads a field's patch tag; untagged fields use their json name as column
+func patchColumn(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("patch")
+	if !ok {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0], false
+	}
+	column, opts, _ := strings.Cut(tag, ",")
+	return column, opts == "nullable"
+}
+
+// decodeJSONPatch folds the add, replace and remove operations of an RFC 6902 document into
+// the equivalent merge patch. Only top-level paths such as /name are addressable.
+func decodeJSONPatch(body []byte) (map[string]json.RawMessage, error) {
+	var ops []jsonPatchOp
+	if err := decodeSingle(json.NewDecoder(bytes.NewReader(body)), &ops); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, domainErrors.NewAppError(errors.New("a JSON Patch body must be an array of operations"), domainErrors.ValidationError)
+		}
+		return nil, err
+	}
+	doc := make(map[string]json.RawMessage, len(ops))
+	for i, op := range ops {
+		field := fmt.Sprintf("[%d].path", i)
+		name, ok := strings.CutPrefix(op.Path, "/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return nil, domainErrors.NewValidationError(fmt.Errorf("path %q is not a top-level field", op.Path),
+				domainErrors.FieldError{Field: field, Rule: "path", Message: "path must address a top-level field such as /name"})
+		}
+		name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return nil, domainErrors.NewValidationError(fmt.Errorf("operation %d has no value", i),
+					domainErrors.FieldError{Field: fmt.Sprintf("[%d].value", i), Rule: "required", Message: "value is required for " + op.Op})
+			}
+			doc[name] = op.Value
+		case "remove":
+			doc[name] = json.RawMessage("null")
+		default:
+			return nil, domainErrors.NewValidationError(fmt.Errorf("operation %q is not supported", op.Op),
+				domainErrors.FieldError{Field: fmt.Sprintf("[%d].op", i), Rule: "oneof", Message: "op must be one of add, replace or remove"})
+		}
+	}
+	return doc, nil
+}
+
+// objectError reports a body that is valid JSON but not an object
+func objectError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field == "" {
+		return domainErrors.NewAppError(errors.New("request body must be a JSON object"), domainErrors.ValidationError)
+	}
+	return err
+}
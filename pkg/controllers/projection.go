@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query parameters of sparse fieldsets and expansion
+const (
+	FieldsParam = "fields"
+	ExpandParam = "expand"
+)
+
+// Expansion is the set of related resources a read request asked to inline with ?expand=
+type Expansion map[string]bool
+
+// Has reports whether the request asked to inline name
+func (e Expansion) Has(name string) bool {
+	return e[name]
+}
+
+// ParseExpand reads ?expand=, a comma separated list of the related resources to inline, such
+// as category or items.product. A name the endpoint does not offer in allowed is a
+// ValidationError.
+func ParseExpand(c *gin.Context, allowed ...string) (Expansion, error) {
+	e := Expansion{}
+	var details []domainErrors.FieldError
+	for _, name := range splitList(c.Query(ExpandParam)) {
+		if !slices.Contains(allowed, name) {
+			message := fmt.Sprintf("cannot expand %s", name)
+			if len(allowed) > 0 {
+				message += "; allowed: " + strings.Join(allowed, ", ")
+			}
+			details = append(details, domainErrors.FieldError{Field: ExpandParam, Rule: "oneof", Message: message})
+			continue
+		}
+		e[name] = true
+	}
+	if len(details) > 0 {
+		return nil, domainErrors.NewValidationError(errors.New("invalid expand parameter"), details...)
+	}
+	return e, nil
+}
+
+// Project trims data, a T or a []T, to ?fields=, a comma separated list of JSON field names
+// where a dotted name such as items.quantity keeps part of a nested object. Expanded resources
+// are kept as well. A name that is not a field of T is a ValidationError; without the parameter
+// data is returned as is. The Respond functions call it; handlers only need it for bodies that
+// wrap the resources in fields of their own.
+func Project[T any](c *gin.Context, data any) (any, error) {
+	raw := c.Query(FieldsParam)
+	if raw == "" {
+		return data, nil
+	}
+	t := reflect.TypeFor[T]()
+	tree := fieldTree{}
+	var details []domainErrors.FieldError
+	for _, path := range splitList(raw) {
+		if !hasField(t, path) {
+			details = append(details, domainErrors.FieldError{Field: FieldsParam, Rule: "oneof", Message: path + " is not a field of this resource"})
+			continue
+		}
+		tree.add(path)
+	}
+	if len(details) > 0 {
+		return nil, domainErrors.NewValidationError(errors.New("invalid fields parameter"), details...)
+	}
+	if len(tree) == 0 {
+		return data, nil
+	}
+	for _, name := range splitList(c.Query(ExpandParam)) {
+		tree.add(name)
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return tree.prune(v), nil
+}
+
+// fieldTree holds the requested fields by name; a nil subtree keeps the whole value
+type fieldTree map[string]fieldTree
+
+func (t fieldTree) add(path string) {
+	head, rest, nested := strings.Cut(path, ".")
+	sub, seen := t[head]
+	switch {
+	case !nested:
+		t[head] = nil
+	case seen && sub == nil:
+		// the whole value is already kept
+	default:
+		if sub == nil {
+			sub = fieldTree{}
+			t[head] = sub
+		}
+		sub.add(rest)
+	}
+}
+
+func (t fieldTree) prune(v any) any {
+	switch v := v.(type) {
+	case []any:
+		for i := range v {
+			v[i] = t.prune(v[i])
+		}
+	case map[string]any:
+		for k, value := range v {
+			sub, ok := t[k]
+			switch {
+			case !ok:
+				delete(v, k)
+			case sub != nil:
+				v[k] = sub.prune(value)
+			}
+		}
+	}
+	return v
+}
+
+// hasField reports whether the dotted JSON path names a field of t, looking through pointers,
+// slices and maps
+func hasField(t reflect.Type, path string) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if path == "" {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Struct:
+	default:
+		return false
+	}
+	head, rest, _ := strings.Cut(path, ".")
+	f, ok := jsonField(t, head)
+	return ok && hasField(f.Type, rest)
+}
+
+// jsonField finds the field encoding/json writes under name, including promoted fields
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if promoted, ok := jsonField(ft, name); ok {
+					return promoted, true
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testItem struct {
+	ID    int            `json:"id"`
+	Name  string         `json:"name"`
+	Price float64        `json:"price"`
+	Owner *testOwner     `json:"owner,omitempty"`
+	Lines []testLine     `json:"lines"`
+	Attrs map[string]int `json:"attrs,omitempty"`
+}
+
+type testOwner struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
+
+type testLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+var item = testItem{ID: 1, Name: "Mug", Price: 9.5, Owner: &testOwner{ID: 2, Email: "a@example.com"},
+	Lines: []testLine{{SKU: "M-1", Quantity: 3}}}
+
+// answer runs respond for a GET of target and returns the status and body
+func answer(t *testing.T, target string, respond func(c *gin.Context)) (int, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", respond, func(c *gin.Context) {
+		// stands in for the ErrorHandler middleware
+		if len(c.Errors) > 0 {
+			c.Status(http.StatusBadRequest)
+		}
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w.Code, w.Body.String()
+}
+
+func TestRespondBare(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "without fields the whole resource is returned",
+			target:     "/",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1,"name":"Mug","price":9.5,"owner":{"id":2,"email":"a@example.com"},"lines":[{"sku":"M-1","quantity":3}]}`,
+		},
+		{
+			name:       "fields trim the resource",
+			target:     "/?fields=id,name",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1,"name":"Mug"}`,
+		},
+		{
+			name:       "dotted fields select within nested objects and lists",
+			target:     "/?fields=id,owner.email,lines.quantity",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":1,"lines":[{"quantity":3}],"owner":{"email":"a@example.com"}}`,
+		},
+		{
+			name:       "fields within a map are not checked",
+			target:     "/?fields=attrs.color",
+			wantStatus: http.StatusOK,
+			wantBody:   `{}`,
+		},
+		{
+			name:       "unknown field is rejected",
+			target:     "/?fields=id,cost",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown nested field is rejected",
+			target:     "/?fields=owner.name",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := answer(t, tt.target, func(c *gin.Context) { RespondBare(c, http.StatusOK, item) })
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantBody != "" {
+				assertJSON(t, body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRespondPageBare(t *testing.T) {
+	items := []testItem{item, {ID: 3, Name: "Plate"}}
+	p := pagination.Params{Page: 1, Limit: 2}
+	_, body := answer(t, "/?fields=name", func(c *gin.Context) { RespondPageBare(c, items, 3, p) })
+	assertJSON(t, body, `{"data":[{"name":"Mug"},{"name":"Plate"}],"meta":{"page":1,"limit":2,"total":3,"totalPages":2}}`)
+}
+
+func TestRespondEnvelope(t *testing.T) {
+	_, body := answer(t, "/?fields=id", func(c *gin.Context) { Respond(c, http.StatusOK, item) })
+	assertJSON(t, body, `{"data":{"id":1},"meta":{"version":1}}`)
+}
+
+func TestParseExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "nothing to expand", target: "/"},
+		{name: "offered expansions", target: "/?expand=category,+items.product", want: []string{"category", "items.product"}},
+		{name: "expansion not offered", target: "/?expand=category,owner", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			got, err := ParseExpand(c, "category", "items.product")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			for _, name := range tt.want {
+				if !got.Has(name) {
+					t.Errorf("expansion %s missing from %v", name, got)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("expansions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// assertJSON compares JSON documents regardless of key order
+func assertJSON(t *testing.T, got, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal([]byte(got), &g); err != nil {
+		t.Fatalf("body %q: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("body = %s, want %s", gb, wb)
+	}
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeVersion is the meta.version of every envelope. It changes only when the shape of the
+// envelope itself does, so clients can tell a body they cannot read from one they can.
+const EnvelopeVersion = 1
+
+const envelopeKey = "controllers.envelope"
+
+// Envelope is the body of every successful answer: the result in data, the envelope version
+// and, for listings, the page in meta
+type Envelope[T any] struct {
+	Data T    `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+// ErrorEnvelope is the body of every failed answer of a router that UseEnvelope
+type ErrorEnvelope struct {
+	Meta   Meta                         `json:"meta"`
+	Errors []domainErrors.ErrorResponse `json:"errors"`
+}
+
+// Meta describes an envelope; the page fields are only set on listings
+type Meta struct {
+	Version int `json:"version"`
+	*pagination.Meta
+}
+
+func newMeta(page *pagination.Meta) Meta {
+	return Meta{Version: EnvelopeVersion, Meta: page}
+}
+
+// Respond answers with data in an Envelope, trimmed to the fields asked for with ?fields=
+func Respond[T any](c *gin.Context, status int, data T) {
+	projected, err := Project[T](c, data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(status, Envelope[any]{Data: projected, Meta: newMeta(nil)})
+}
+
+// RespondPage answers with one page of a listing, total being the number of matches on all
+// pages. Like Respond it honours ?fields=, applied to every item.
+func RespondPage[T any](c *gin.Context, data []T, total int64, p pagination.Params) {
+	page := pagination.NewResponse(data, total, p)
+	projected, err := Project[T](c, page.Data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, Envelope[any]{Data: projected, Meta: newMeta(&page.Meta)})
+}
+
+// RespondBare answers with data trimmed to ?fields= like Respond, but without the Envelope,
+// for the services whose clients read bare bodies
+func RespondBare[T any](c *gin.Context, status int, data T) {
+	projected, err := Project[T](c, data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(status, projected)
+}
+
+// RespondPageBare answers with a pagination.Response, every item trimmed to ?fields=, for the
+// services whose clients read bare bodies
+func RespondPageBare[T any](c *gin.Context, data []T, total int64, p pagination.Params) {
+	page := pagination.NewResponse(data, total, p)
+	projected, err := Project[T](c, page.Data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, barePage{Data: projected, Meta: page.Meta})
+}
+
+// barePage is a pagination.Response whose items may have been trimmed
+type barePage struct {
+	Data any             `json:"data"`
+	Meta pagination.Meta `json:"meta"`
+}
+
+// RespondMessage answers with a MessageResponse, for actions with no resource to return
+func RespondMessage(c *gin.Context, status int, message string) {
+	Respond(c, status, MessageResponse{Message: message})
+}
+
+// UseEnvelope makes the errors pkg/middleware renders ErrorEnvelopes, like the answers of the
+// handlers. It goes first on the router so errors of every later middleware are covered.
+func UseEnvelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, true)
+	}
+}
+
+// ErrorBody is the body answering with e: an ErrorEnvelope on routers that UseEnvelope, e as
+// is on the others
+func ErrorBody(c *gin.Context, e domainErrors.ErrorResponse) any {
+	if !c.GetBool(envelopeKey) {
+		return e
+	}
+	return NewErrorEnvelope(e)
+}
+
+func NewErrorEnvelope(errs ...domainErrors.ErrorResponse) ErrorEnvelope {
+	return ErrorEnvelope{Meta: newMeta(nil), Errors: errs}
+}
+
+// DecodeData reads the data of an Envelope body into v, for clients of the services that
+// UseEnvelope
+func DecodeData(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(&Envelope[any]{Data: v})
+}
+
+// DecodeError reads the first error of an error body, an ErrorEnvelope or, from services that
+// do not UseEnvelope, a bare ErrorResponse. An unreadable body gives an empty ErrorResponse.
+func DecodeError(r io.Reader) domainErrors.ErrorResponse {
+	var body struct {
+		domainErrors.ErrorResponse
+		Errors []domainErrors.ErrorResponse `json:"errors"`
+	}
+	_ = json.NewDecoder(r).Decode(&body)
+	if len(body.Errors) > 0 {
+		return body.Errors[0]
+	}
+	return body.ErrorResponse
+}
@@ -0,0 +1,291 @@
+// Package currency converts amounts between the store's base currency and the currencies
+// customers pay and browse in. Rates come from a provider refreshed on a schedule, shared between
+// replicas through the cache store, with fixed rates from the environment as the fallback.
+package currency
+
+//go:generate mockgen -source=currency.go -destination=mocks/currency.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrStaleRates is returned for a currency only the provider knows while its rates are older
+	// than Config.MaxAge
+	ErrStaleRates = errors.New("exchange rates are out of date")
+)
+
+// RateProvider converts currencies into the store's base currency
+type RateProvider interface {
+	BaseCurrency() string
+	// Rate returns how many units of the base currency one unit of currency is worth
+	Rate(currency string) (float64, error)
+	// Table returns every rate Rate currently answers with
+	Table() Table
+}
+
+// Table is a set of rates, in base units per unit of each currency
+type Table struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+	// FetchedAt is when the provider's rates were fetched; zero with fixed rates only
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Config selects the base currency and where rates come from
+type Config struct {
+	Base string
+	// Fixed rates, from EXCHANGE_RATES, apply when no provider is set and to currencies the
+	// provider's rates lack or while they are stale
+	Fixed map[string]float64
+	// URL of the provider's latest rates; empty uses the fixed rates only
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+	// Refresh is how often the provider is asked; replicas sharing a cache store ask once between them
+	Refresh time.Duration
+	// MaxAge is how old the provider's rates may get before they are no longer used
+	MaxAge time.Duration
+}
+
+// LoadConfig reads BASE_CURRENCY, EXCHANGE_RATES, a comma separated list like
+// "USD=16250,EUR=17600" of base units per unit of each currency, and the EXCHANGE_RATES_*
+// provider settings
+func LoadConfig(src *config.Source) Config {
+	cfg := Config{
+		Base:    strings.ToUpper(src.String("BASE_CURRENCY", "IDR")),
+		Fixed:   map[string]float64{},
+		URL:     src.String("EXCHANGE_RATES_URL", ""),
+		APIKey:  src.String("EXCHANGE_RATES_API_KEY", ""),
+		Timeout: src.Duration("EXCHANGE_RATES_TIMEOUT_SECONDS", 10, time.Second),
+		Refresh: src.Duration("EXCHANGE_RATES_REFRESH_MINUTES", 60, time.Minute),
+		MaxAge:  src.Duration("EXCHANGE_RATES_MAX_AGE_HOURS", 48, time.Hour),
+	}
+	if !validCode(cfg.Base) {
+		src.Fail("BASE_CURRENCY", "must be a three letter currency code")
+	}
+	for _, pair := range src.List("EXCHANGE_RATES", nil) {
+		code, value, ok := strings.Cut(pair, "=")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || !validCode(code) || err != nil || rate <= 0 {
+			src.Fail("EXCHANGE_RATES", fmt.Sprintf("invalid entry %q", pair))
+			continue
+		}
+		cfg.Fixed[code] = rate
+	}
+	if strings.Contains(cfg.URL, "{key}") && cfg.APIKey == "" {
+		src.Fail("EXCHANGE_RATES_API_KEY", "is required by EXCHANGE_RATES_URL")
+	}
+	return cfg
+}
+
+func validCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// storeKey holds the latest provider rates in the shared cache store
+const storeKey = "currency:rates"
+
+// Rates is the RateProvider of a service. Until the first refresh, and without a provider,
+// only the fixed rates are known.
+type Rates struct {
+	cfg    Config
+	source Source
+	store  cache.Store
+	mu     sync.RWMutex
+	latest *Table
+	Logger *logger.Logger
+}
+
+// New builds the rates; source and store may be nil, leaving the fixed rates and a
+// per-replica provider fetch respectively
+func New(cfg Config, source Source, store cache.Store, l *logger.Logger) *Rates {
+	cfg.Base = strings.ToUpper(cfg.Base)
+	return &Rates{cfg: cfg, source: source, store: store, Logger: l}
+}
+
+// NewFromConfig builds the rates with an HTTP source when EXCHANGE_RATES_URL is set
+func NewFromConfig(cfg Config, store cache.Store, l *logger.Logger) *Rates {
+	var source Source
+	if cfg.URL != "" {
+		source = NewHTTPSource(cfg.URL, cfg.APIKey, cfg.Timeout)
+	}
+	return New(cfg, source, store, l)
+}
+
+func (r *Rates) BaseCurrency() string { return r.cfg.Base }
+
+func (r *Rates) Rate(currency string) (float64, error) {
+	currency = strings.ToUpper(currency)
+	if currency == r.cfg.Base {
+		return 1, nil
+	}
+	latest, fresh := r.current()
+	if latest != nil && fresh {
+		if rate, ok := latest.Rates[currency]; ok {
+			return rate, nil
+		}
+	}
+	if rate, ok := r.cfg.Fixed[currency]; ok {
+		return rate, nil
+	}
+	if latest != nil && !fresh {
+		if _, ok := latest.Rates[currency]; ok {
+			return 0, ErrStaleRates
+		}
+	}
+	return 0, ErrUnsupportedCurrency
+}
+
+func (r *Rates) Table() Table {
+	t := Table{Base: r.cfg.Base, Rates: maps.Clone(r.cfg.Fixed)}
+	if latest, fresh := r.current(); latest != nil && fresh {
+		maps.Copy(t.Rates, latest.Rates)
+		t.FetchedAt = latest.FetchedAt
+	}
+	t.Rates[r.cfg.Base] = 1
+	return t
+}
+
+func (r *Rates) current() (*Table, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.latest == nil {
+		return nil, false
+	}
+	return r.latest, r.cfg.MaxAge <= 0 || time.Since(r.latest.FetchedAt) <= r.cfg.MaxAge
+}
+
+// Refresh takes the provider's rates from the cache store when another replica fetched them
+// within Config.Refresh, and from the provider otherwise. It returns how many currencies are
+// known and runs as a scheduler job; on failure the previous rates stay in use.
+func (r *Rates) Refresh(ctx context.Context) (int, error) {
+	if r.source == nil {
+		return 0, nil
+	}
+	if shared := r.shared(ctx); shared != nil && time.Since(shared.FetchedAt) < r.cfg.Refresh {
+		r.set(shared)
+		return len(shared.Rates), nil
+	}
+	base, rates, err := r.source.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	converted, err := rebase(r.cfg.Base, base, rates)
+	if err != nil {
+		return 0, err
+	}
+	t := &Table{Base: r.cfg.Base, Rates: converted, FetchedAt: time.Now().UTC()}
+	r.set(t)
+	if r.store != nil {
+		if data, err := json.Marshal(t); err == nil {
+			if err := r.store.Set(ctx, storeKey, data, r.cfg.MaxAge); err != nil {
+				r.Logger.Warn("Error sharing exchange rates", zap.Error(err))
+			}
+		}
+	}
+	r.Logger.Info("Refreshed exchange rates", zap.String("base", t.Base), zap.Int("currencies", len(t.Rates)))
+	return len(t.Rates), nil
+}
+
+// shared returns the rates another replica stored for the same base currency, if any
+func (r *Rates) shared(ctx context.Context) *Table {
+	if r.store == nil {
+		return nil
+	}
+	data, err := r.store.Get(ctx, storeKey)
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			r.Logger.Warn("Error reading shared exchange rates", zap.Error(err))
+		}
+		return nil
+	}
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil || t.Base != r.cfg.Base {
+		return nil
+	}
+	return &t
+}
+
+func (r *Rates) set(t *Table) {
+	r.mu.Lock()
+	r.latest = t
+	r.mu.Unlock()
+}
+
+// rebase turns the provider's rates, units of each currency per unit of providerBase, into
+// base units per unit of each currency
+func rebase(base, providerBase string, rates map[string]float64) (map[string]float64, error) {
+	upper := map[string]float64{strings.ToUpper(providerBase): 1}
+	for code, rate := range rates {
+		upper[strings.ToUpper(code)] = rate
+	}
+	perProviderBase, ok := upper[base]
+	if !ok || perProviderBase <= 0 {
+		return nil, fmt.Errorf("exchange rate provider has no rate for %s", base)
+	}
+	result := make(map[string]float64, len(upper))
+	for code, rate := range upper {
+		if rate > 0 && validCode(code) {
+			result[code] = perProviderBase / rate
+		}
+	}
+	result[base] = 1
+	return result, nil
+}
+
+// Convert converts amount from one currency into another through the base currency
+func Convert(p RateProvider, amount float64, from, to string) (float64, error) {
+	fromRate, err := p.Rate(from)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", err, strings.ToUpper(from))
+	}
+	toRate, err := p.Rate(to)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", err, strings.ToUpper(to))
+	}
+	return amount * fromRate / toRate, nil
+}
+
+// Check reports the age of the provider's rates for the readiness probe. Stale rates do not
+// fail it: the fixed rates and the base currency keep working.
+func Check(r *Rates) health.CheckFunc {
+	return func(context.Context) (any, error) {
+		if r.source == nil {
+			return map[string]any{"source": "fixed", "currencies": len(r.cfg.Fixed) + 1}, nil
+		}
+		r.mu.RLock()
+		latest := r.latest
+		r.mu.RUnlock()
+		if latest == nil {
+			return map[string]any{"source": "provider", "fetchedAt": nil}, nil
+		}
+		_, fresh := r.current()
+		return map[string]any{"source": "provider", "fetchedAt": latest.FetchedAt, "currencies": len(latest.Rates), "stale": !fresh}, nil
+	}
+}
@@ -0,0 +1,68 @@
+package currency
+
+//go:generate mockgen -source=source.go -destination=mocks/source.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+// Source fetches the latest exchange rates from a provider
+type Source interface {
+	// Fetch returns the provider's base currency and how many units of each currency one unit
+	// of that base is worth
+	Fetch(ctx context.Context) (base string, rates map[string]float64, err error)
+}
+
+// HTTPSource reads rates from a JSON API answering {"base": "USD", "rates": {"IDR": 16250, ...}},
+// the format of Open Exchange Rates, Frankfurter and ExchangeRate-API ("base_code")
+type HTTPSource struct {
+	url    string
+	apiKey string
+	http   *http.Client
+}
+
+// NewHTTPSource fetches url, with {key} replaced by apiKey so the key stays out of logged URLs
+func NewHTTPSource(url, apiKey string, timeout time.Duration) Source {
+	return &HTTPSource{url: url, apiKey: apiKey, http: &http.Client{Timeout: timeout, Transport: otel.Transport(nil)}}
+}
+
+func (s *HTTPSource) Fetch(ctx context.Context) (string, map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(s.url, "{key}", s.apiKey), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := s.http.Do(req)
+	if err != nil {
+		// the error names the URL, which may hold the key
+		return "", nil, errors.New("exchange rate provider request failed")
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("exchange rate provider returned %d", res.StatusCode)
+	}
+	var payload struct {
+		Base     string             `json:"base"`
+		BaseCode string             `json:"base_code"`
+		Rates    map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return "", nil, fmt.Errorf("decoding exchange rates: %w", err)
+	}
+	base := payload.Base
+	if base == "" {
+		base = payload.BaseCode
+	}
+	if base == "" || len(payload.Rates) == 0 {
+		return "", nil, errors.New("exchange rate provider returned no rates")
+	}
+	return base, payload.Rates, nil
+}
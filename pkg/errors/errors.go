@@ -8,6 +8,49 @@ import (
 type ErrorType string
 type ErrorMessage string
 
+// ErrorCode is the machine-readable code clients branch on. Every ErrorType has a generic
+// code; use cases attach a specific one with WithCode where clients need to tell cases apart.
+type ErrorCode string
+
+const (
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeValidation       ErrorCode = "VALIDATION_FAILED"
+	CodeAlreadyExists    ErrorCode = "ALREADY_EXISTS"
+	CodeNotAuthenticated ErrorCode = "NOT_AUTHENTICATED"
+	CodeNotAuthorized    ErrorCode = "NOT_AUTHORIZED"
+	CodeExpired          ErrorCode = "EXPIRED"
+	// CodePaymentDeclined means the payment provider refused the charge; retrying will not help
+	CodePaymentDeclined ErrorCode = "PAYMENT_DECLINED"
+	// CodeCSRFTokenInvalid means the CSRF header is missing or does not match the CSRF cookie
+	CodeCSRFTokenInvalid ErrorCode = "CSRF_TOKEN_INVALID"
+	// CodeVersionConflict means the resource changed since the client read the version it sent
+	CodeVersionConflict ErrorCode = "VERSION_CONFLICT"
+	CodeInternal        ErrorCode = "INTERNAL_ERROR"
+	// CodeServiceUnavailable is returned by the gateway when a service cannot be reached
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	// CodeServiceTimeout is returned by the gateway when a service does not answer in time
+	CodeServiceTimeout  ErrorCode = "SERVICE_TIMEOUT"
+	CodePayloadTooLarge ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited     ErrorCode = "RATE_LIMITED"
+	// CodeRequestInProgress means a request with the same Idempotency-Key is still running
+	CodeRequestInProgress ErrorCode = "REQUEST_IN_PROGRESS"
+	// CodeIdempotencyKeyReused means the Idempotency-Key was used for a different request
+	CodeIdempotencyKeyReused ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+
+	CodeUserNotFound      ErrorCode = "USER_NOT_FOUND"
+	CodeEmailConflict     ErrorCode = "EMAIL_CONFLICT"
+	CodeSKUConflict       ErrorCode = "SKU_CONFLICT"
+	CodeInvalidTransition ErrorCode = "INVALID_TRANSITION"
+	// CodeReviewExists means the user already reviewed the product; they can edit that review
+	CodeReviewExists ErrorCode = "REVIEW_EXISTS"
+	// CodePurchaseRequired means only customers who bought the product may review it
+	CodePurchaseRequired ErrorCode = "PURCHASE_REQUIRED"
+	// CodeTenantNotFound means the host or X-Tenant-ID names no active store
+	CodeTenantNotFound ErrorCode = "TENANT_NOT_FOUND"
+	// CodeTenantMismatch means the token was issued for another store than the request's
+	CodeTenantMismatch ErrorCode = "TENANT_MISMATCH"
+)
+
 const (
 	NotFound        ErrorType    = "NotFound"
 	notFoundMessage ErrorMessage = "record not found"
@@ -18,6 +61,9 @@ const (
 	ResourceAlreadyExists     ErrorType    = "ResourceAlreadyExists"
 	alreadyExistsErrorMessage ErrorMessage = "resource already exists"
 
+	Conflict             ErrorType    = "Conflict"
+	conflictErrorMessage ErrorMessage = "resource was modified by another request"
+
 	RepositoryError        ErrorType    = "RepositoryError"
 	repositoryErrorMessage ErrorMessage = "error in repository operation"
 
@@ -30,17 +76,31 @@ const (
 	NotAuthorized             ErrorType    = "NotAuthorized"
 	notAuthorizedErrorMessage ErrorMessage = "not authorized"
 
+	Expired             ErrorType    = "Expired"
+	expiredErrorMessage ErrorMessage = "resource expired"
+
+	PayloadTooLarge             ErrorType    = "PayloadTooLarge"
+	payloadTooLargeErrorMessage ErrorMessage = "request body too large"
+
+	PaymentDeclined             ErrorType    = "PaymentDeclined"
+	paymentDeclinedErrorMessage ErrorMessage = "payment declined"
+
 	UnknownError        ErrorType    = "UnknownError"
 	unknownErrorMessage ErrorMessage = "something went wrong"
 )
 
+// AppError is an error with a type that decides the response status. Err is the cause; the
+// constructors record the stack where the error was created for logging with Field.
 type AppError struct {
-	Err  error
-	Type ErrorType
+	Err     error
+	Type    ErrorType
+	Code    ErrorCode
+	Details []FieldError
+	stack   []uintptr
 }
 
 func NewAppError(err error, errType ErrorType) *AppError {
-	return &AppError{Err: err, Type: errType}
+	return &AppError{Err: err, Type: errType, stack: callers()}
 }
 
 func NewAppErrorWithType(errType ErrorType) *AppError {
@@ -52,6 +112,8 @@ func NewAppErrorWithType(errType ErrorType) *AppError {
 		err = errors.New(string(validationErrorMessage))
 	case ResourceAlreadyExists:
 		err = errors.New(string(alreadyExistsErrorMessage))
+	case Conflict:
+		err = errors.New(string(conflictErrorMessage))
 	case RepositoryError:
 		err = errors.New(string(repositoryErrorMessage))
 	case NotAuthenticated:
@@ -60,36 +122,79 @@ func NewAppErrorWithType(errType ErrorType) *AppError {
 		err = errors.New(string(notAuthorizedErrorMessage))
 	case TokenGeneratorError:
 		err = errors.New(string(tokenGeneratorErrorMessage))
+	case Expired:
+		err = errors.New(string(expiredErrorMessage))
+	case PayloadTooLarge:
+		err = errors.New(string(payloadTooLargeErrorMessage))
+	case PaymentDeclined:
+		err = errors.New(string(paymentDeclinedErrorMessage))
 	default:
 		err = errors.New(string(unknownErrorMessage))
 	}
-	return &AppError{Err: err, Type: errType}
+	return &AppError{Err: err, Type: errType, stack: callers()}
 }
 
 func (appErr *AppError) Error() string {
 	return appErr.Err.Error()
 }
 
+// WithCode sets a specific error code and returns the error for chaining
+func (appErr *AppError) WithCode(code ErrorCode) *AppError {
+	appErr.Code = code
+	return appErr
+}
+
+// ErrorCode returns the specific code when one was set, otherwise the code of the type
+func (appErr *AppError) ErrorCode() ErrorCode {
+	if appErr.Code != "" {
+		return appErr.Code
+	}
+	switch appErr.Type {
+	case NotFound:
+		return CodeNotFound
+	case ValidationError:
+		return CodeValidation
+	case ResourceAlreadyExists:
+		return CodeAlreadyExists
+	case Conflict:
+		return CodeVersionConflict
+	case NotAuthenticated:
+		return CodeNotAuthenticated
+	case NotAuthorized:
+		return CodeNotAuthorized
+	case Expired:
+		return CodeExpired
+	case PayloadTooLarge:
+		return CodePayloadTooLarge
+	case PaymentDeclined:
+		return CodePaymentDeclined
+	default:
+		return CodeInternal
+	}
+}
+
 func AppErrorToHTTP(appErr *AppError) (int, string) {
 	switch appErr.Type {
 	case NotFound:
 		return http.StatusNotFound, appErr.Error()
 	case ValidationError:
 		return http.StatusBadRequest, appErr.Error()
-	case ResourceAlreadyExists:
+	case ResourceAlreadyExists, Conflict:
 		return http.StatusConflict, appErr.Error()
 	case RepositoryError:
-		return http.StatusInternalServerError, appErr.Error()
+		// the cause may hold SQL or connection details; Field logs it instead
+		return http.StatusInternalServerError, "Internal Server Error"
 	case NotAuthenticated:
 		return http.StatusUnauthorized, appErr.Error()
 	case NotAuthorized:
 		return http.StatusForbidden, appErr.Error()
+	case Expired:
+		return http.StatusGone, appErr.Error()
+	case PayloadTooLarge:
+		return http.StatusRequestEntityTooLarge, appErr.Error()
+	case PaymentDeclined:
+		return http.StatusPaymentRequired, appErr.Error()
 	default:
 		return http.StatusInternalServerError, "Internal Server Error"
 	}
 }
-
-type GormErr struct {
-	Number  int    `json:"Number"`
-	Message string `json:"Message"`
-}
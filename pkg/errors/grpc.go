@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AppErrorToGRPC converts an error returned by a use case into a gRPC status error,
+// mirroring AppErrorToHTTP.
+func AppErrorToGRPC(err error) error {
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, string(unknownErrorMessage))
+	}
+	switch appErr.Type {
+	case NotFound:
+		return status.Error(codes.NotFound, appErr.Error())
+	case ValidationError:
+		return status.Error(codes.InvalidArgument, appErr.Error())
+	case ResourceAlreadyExists:
+		return status.Error(codes.AlreadyExists, appErr.Error())
+	case NotAuthenticated:
+		return status.Error(codes.Unauthenticated, appErr.Error())
+	case NotAuthorized:
+		return status.Error(codes.PermissionDenied, appErr.Error())
+	case Expired:
+		return status.Error(codes.FailedPrecondition, appErr.Error())
+	default:
+		return status.Error(codes.Internal, string(unknownErrorMessage))
+	}
+}
+
+// GRPCToHTTP is the HTTP status, code and message answering a gRPC call that failed with err,
+// mirroring AppErrorToGRPC and AppErrorToHTTP. Errors without a gRPC status, and internal
+// ones, keep their details out of the message.
+func GRPCToHTTP(err error) (int, ErrorCode, string) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError, CodeInternal, string(unknownErrorMessage)
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return http.StatusNotFound, CodeNotFound, st.Message()
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest, CodeValidation, st.Message()
+	case codes.AlreadyExists:
+		return http.StatusConflict, CodeAlreadyExists, st.Message()
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, CodeNotAuthenticated, st.Message()
+	case codes.PermissionDenied:
+		return http.StatusForbidden, CodeNotAuthorized, st.Message()
+	case codes.FailedPrecondition:
+		return http.StatusGone, CodeExpired, st.Message()
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests, CodeRateLimited, st.Message()
+	case codes.Unavailable:
+		return http.StatusBadGateway, CodeServiceUnavailable, "service unavailable"
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout, CodeServiceTimeout, "service timed out"
+	default:
+		return http.StatusInternalServerError, CodeInternal, string(unknownErrorMessage)
+	}
+}
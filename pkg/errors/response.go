@@ -0,0 +1,42 @@
+package errors
+
+import "errors"
+
+// ErrorResponse is the body of every error answer
+type ErrorResponse struct {
+	Code      ErrorCode    `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+	// ErrorID identifies a recovered panic in the logs, for users to quote when reporting it
+	ErrorID string `json:"errorId,omitempty"`
+}
+
+// FieldError describes one invalid field of a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ResponseFor is the ErrorResponse the error handler middleware answers err with, for errors
+// reported inside a successful answer such as the items of a bulk write
+func ResponseFor(err error) ErrorResponse {
+	var appErr *AppError
+	if !errors.As(err, &appErr) {
+		return ErrorResponse{Code: CodeInternal, Message: "Internal Server Error"}
+	}
+	_, message := AppErrorToHTTP(appErr)
+	return ErrorResponse{Code: appErr.ErrorCode(), Message: message, Details: appErr.Details}
+}
+
+// NewValidationError wraps a binding error as a ValidationError. When details are given the
+// message is replaced by a generic one and the details say which fields are invalid.
+func NewValidationError(err error, details ...FieldError) *AppError {
+	appErr := NewAppError(err, ValidationError)
+	if len(details) > 0 {
+		appErr.Details = details
+		appErr.Err = errors.New("request validation failed")
+	}
+	return appErr
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const maxStackDepth = 32
+
+// Sentinels for errors.Is: errors.Is(err, ErrNotFound) holds for every AppError of type
+// NotFound anywhere in err's chain
+var (
+	ErrNotFound         = &AppError{Err: errors.New(string(notFoundMessage)), Type: NotFound}
+	ErrValidation       = &AppError{Err: errors.New(string(validationErrorMessage)), Type: ValidationError}
+	ErrAlreadyExists    = &AppError{Err: errors.New(string(alreadyExistsErrorMessage)), Type: ResourceAlreadyExists}
+	ErrConflict         = &AppError{Err: errors.New(string(conflictErrorMessage)), Type: Conflict}
+	ErrRepository       = &AppError{Err: errors.New(string(repositoryErrorMessage)), Type: RepositoryError}
+	ErrNotAuthenticated = &AppError{Err: errors.New(string(notAuthenticatedErrorMessage)), Type: NotAuthenticated}
+	ErrNotAuthorized    = &AppError{Err: errors.New(string(notAuthorizedErrorMessage)), Type: NotAuthorized}
+	ErrExpired          = &AppError{Err: errors.New(string(expiredErrorMessage)), Type: Expired}
+	ErrPayloadTooLarge  = &AppError{Err: errors.New(string(payloadTooLargeErrorMessage)), Type: PayloadTooLarge}
+	ErrPaymentDeclined  = &AppError{Err: errors.New(string(paymentDeclinedErrorMessage)), Type: PaymentDeclined}
+)
+
+// Wrap returns an AppError whose message is "message: cause", keeping cause in the chain for
+// errors.Is and errors.As
+func Wrap(cause error, errType ErrorType, message string) *AppError {
+	return &AppError{Err: fmt.Errorf("%s: %w", message, cause), Type: errType, stack: callers()}
+}
+
+// Unwrap returns the cause, so errors.Is and errors.As see through an AppError
+func (appErr *AppError) Unwrap() error {
+	return appErr.Err
+}
+
+// Is matches AppErrors of the same type and, when target sets one, the same code
+func (appErr *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	if !ok {
+		return false
+	}
+	return t.Type == appErr.Type && (t.Code == "" || t.Code == appErr.ErrorCode())
+}
+
+// StackTrace returns the frames where the error was created, innermost first
+func (appErr *AppError) StackTrace() []runtime.Frame {
+	if len(appErr.stack) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(appErr.stack)
+	var out []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		out = append(out, frame)
+		if !more {
+			return out
+		}
+	}
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// skip runtime.Callers, callers and the constructor
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// Field logs err with its type, code, cause chain and, for AppErrors, the stack where it was
+// created; use it instead of zap.Error for errors that reach a handler
+func Field(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object("error", errorObject{err})
+}
+
+type errorObject struct{ err error }
+
+func (o errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", o.err.Error())
+	var causes []string
+	for cause := errors.Unwrap(o.err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, fmt.Sprintf("%T: %s", cause, cause.Error()))
+	}
+	if len(causes) > 0 {
+		_ = enc.AddArray("causes", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+			for _, c := range causes {
+				arr.AppendString(c)
+			}
+			return nil
+		}))
+	}
+	var appErr *AppError
+	if !errors.As(o.err, &appErr) {
+		return nil
+	}
+	enc.AddString("type", string(appErr.Type))
+	enc.AddString("code", string(appErr.ErrorCode()))
+	if frames := appErr.StackTrace(); len(frames) > 0 {
+		var b strings.Builder
+		for _, f := range frames {
+			b.WriteString(f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line) + "\n")
+		}
+		enc.AddString("stack", b.String())
+	}
+	return nil
+}
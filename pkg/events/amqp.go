@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+const DefaultExchange = "ecommerce.events"
+
+// AMQPPublisher publishes events to a durable topic exchange, using the event type as routing key.
+type AMQPPublisher struct {
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	exchange string
+	mu       sync.Mutex
+	Logger   *logger.Logger
+}
+
+func NewAMQPPublisher(url, exchange string, l *logger.Logger) (Publisher, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open broker channel: %w", err)
+	}
+	if err := ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+	}
+	// publisher confirms let callers know the broker has taken ownership of the message
+	if err := ch.Confirm(false); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	l.Info("Connected to message broker", zap.String("exchange", exchange))
+	return &AMQPPublisher{conn: conn, channel: ch, exchange: exchange, Logger: l}, nil
+}
+
+func (p *AMQPPublisher) Publish(event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// amqp channels are not safe for concurrent publishing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, event.Type, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID,
+		Timestamp:    event.OccurredAt,
+		Type:         event.Type,
+		AppId:        event.Source,
+		Body:         body,
+	})
+	if err != nil {
+		p.Logger.Error("Error publishing event", zap.String("type", event.Type), zap.Error(err))
+		return err
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for broker confirm: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("broker rejected event %s", event.ID)
+	}
+	return nil
+}
+
+func (p *AMQPPublisher) Close() error {
+	_ = p.channel.Close()
+	return p.conn.Close()
+}
@@ -0,0 +1,95 @@
+package events
+
+//go:generate mockgen -source=events.go -destination=mocks/events.go -package=mocks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"go.uber.org/zap"
+)
+
+// Event is the envelope published to the broker for every domain event. TenantID is the
+// tenant the change happened in; it is empty for changes the system made across tenants.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Source     string          `json:"source"`
+	TenantID   string          `json:"tenantId,omitempty"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+type Publisher interface {
+	Publish(event *Event) error
+	Close() error
+}
+
+// NewEvent builds an event envelope with a random ID, the tenant of ctx and the payload
+// marshalled as JSON.
+func NewEvent(ctx context.Context, eventType, source string, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		Source:     source,
+		TenantID:   tenant.FromContext(ctx),
+		OccurredAt: time.Now().UTC(),
+		Payload:    data,
+	}, nil
+}
+
+// Context returns a copy of ctx scoped to the event's tenant, so consumers read and write
+// the tenant's rows the way its requests do. Events without a tenant act across tenants.
+func (e *Event) Context(ctx context.Context) context.Context {
+	if e.TenantID == "" {
+		return ctx
+	}
+	return logger.ContextWithFields(tenant.ContextWithTenant(ctx, e.TenantID), zap.String("tenant", e.TenantID))
+}
+
+// NewPublisherFromEnv connects to BROKER_URL when set, otherwise events are only logged.
+func NewPublisherFromEnv(loggerInstance *logger.Logger) (Publisher, error) {
+	url := os.Getenv("BROKER_URL")
+	if url == "" {
+		loggerInstance.Warn("BROKER_URL not set, events will only be logged")
+		return NewLogPublisher(loggerInstance), nil
+	}
+	exchange := os.Getenv("BROKER_EXCHANGE")
+	if exchange == "" {
+		exchange = DefaultExchange
+	}
+	return NewAMQPPublisher(url, exchange, loggerInstance)
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// LogPublisher writes events to the logger instead of a broker.
+type LogPublisher struct {
+	Logger *logger.Logger
+}
+
+func NewLogPublisher(l *logger.Logger) Publisher {
+	return &LogPublisher{Logger: l}
+}
+
+func (p *LogPublisher) Publish(event *Event) error {
+	p.Logger.Info("Event published", zap.String("type", event.Type), zap.String("id", event.ID), zap.String("tenant", event.TenantID), zap.ByteString("payload", event.Payload))
+	return nil
+}
+
+func (p *LogPublisher) Close() error { return nil }
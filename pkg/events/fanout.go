@@ -0,0 +1,31 @@
+package events
+
+import "errors"
+
+// FanoutPublisher hands every event to several publishers. It stops at the first
+// failure, so publishers behind it must tolerate receiving the same event again
+// when the outbox relay retries.
+type FanoutPublisher struct {
+	publishers []Publisher
+}
+
+func NewFanoutPublisher(publishers ...Publisher) Publisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+func (f *FanoutPublisher) Publish(event *Event) error {
+	for _, p := range f.publishers {
+		if err := p.Publish(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FanoutPublisher) Close() error {
+	var errs []error
+	for _, p := range f.publishers {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
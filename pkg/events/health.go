@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"ecommerce-microservice-go/pkg/health"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// HealthChecker is implemented by publishers and subscribers that hold a broker connection
+type HealthChecker interface {
+	CheckHealth() error
+}
+
+// BrokerCheck is a readiness check for a publisher or subscriber. Ones without a broker
+// connection, like LogPublisher, always pass.
+func BrokerCheck(client any) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		if hc, ok := client.(HealthChecker); ok {
+			return nil, hc.CheckHealth()
+		}
+		return nil, nil
+	}
+}
+
+func checkConnection(conn *amqp.Connection, channel *amqp.Channel) error {
+	if conn.IsClosed() {
+		return errors.New("broker connection is closed")
+	}
+	if channel.IsClosed() {
+		return errors.New("broker channel is closed")
+	}
+	return nil
+}
+
+func (p *AMQPPublisher) CheckHealth() error { return checkConnection(p.conn, p.channel) }
+
+func (s *AMQPSubscriber) CheckHealth() error { return checkConnection(s.conn, s.channel) }
+
+// CheckHealth fails when any publisher behind the fanout reports a broken broker connection
+func (f *FanoutPublisher) CheckHealth() error {
+	var errs []error
+	for _, p := range f.publishers {
+		if hc, ok := p.(HealthChecker); ok {
+			errs = append(errs, hc.CheckHealth())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxMessage is an event persisted in the same transaction as the write that produced it.
+type OutboxMessage struct {
+	ID          int        `gorm:"primaryKey"`
+	EventID     string     `gorm:"column:event_id;uniqueIndex;not null"`
+	EventType   string     `gorm:"column:event_type;not null"`
+	Source      string     `gorm:"column:source;not null"`
+	TenantID    string     `gorm:"column:tenant_id;not null;default:''"`
+	Payload     []byte     `gorm:"column:payload;type:jsonb;not null"`
+	OccurredAt  time.Time  `gorm:"column:occurred_at;not null"`
+	PublishedAt *time.Time `gorm:"column:published_at;index"`
+	Attempts    int        `gorm:"column:attempts;default:0"`
+	LastError   string     `gorm:"column:last_error"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime:mili"`
+}
+
+func (OutboxMessage) TableName() string { return "outbox_messages" }
+
+// SaveToOutbox stores the event using the given transaction handle.
+func SaveToOutbox(tx *gorm.DB, event *Event) error {
+	return tx.Create(&OutboxMessage{
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Source:     event.Source,
+		TenantID:   event.TenantID,
+		Payload:    event.Payload,
+		OccurredAt: event.OccurredAt,
+	}).Error
+}
+
+type OutboxRelayConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// OutboxRelay polls unpublished outbox rows, publishes them and marks them delivered.
+// Delivery is at-least-once: a row is only marked after the broker confirmed it.
+type OutboxRelay struct {
+	DB        *gorm.DB
+	Publisher Publisher
+	Logger    *logger.Logger
+	config    OutboxRelayConfig
+}
+
+func NewOutboxRelay(db *gorm.DB, p Publisher, l *logger.Logger, cfg OutboxRelayConfig) *OutboxRelay {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 2 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &OutboxRelay{DB: db, Publisher: p, Logger: l, config: cfg}
+}
+
+// Run blocks until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.Logger.Info("Outbox relay started", zap.Duration("interval", r.config.Interval))
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.Logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			if err := r.RelayBatch(); err != nil {
+				r.Logger.Error("Error relaying outbox batch", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RelayBatch publishes one batch in insertion order. Rows are locked with SKIP LOCKED
+// so several replicas can run the relay without sending the same event twice.
+func (r *OutboxRelay) RelayBatch() error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var messages []OutboxMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(r.config.BatchSize).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+		for _, m := range messages {
+			event := &Event{ID: m.EventID, Type: m.EventType, Source: m.Source, TenantID: m.TenantID, OccurredAt: m.OccurredAt, Payload: m.Payload}
+			if err := r.Publisher.Publish(event); err != nil {
+				// keep ordering: stop at the first failure and retry on the next tick
+				return tx.Model(&OutboxMessage{}).Where("id = ?", m.ID).
+					Updates(map[string]interface{}{"attempts": m.Attempts + 1, "last_error": err.Error()}).Error
+			}
+			now := time.Now().UTC()
+			if err := tx.Model(&OutboxMessage{}).Where("id = ?", m.ID).
+				Updates(map[string]interface{}{"published_at": now, "attempts": m.Attempts + 1, "last_error": ""}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
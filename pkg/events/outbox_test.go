@@ -0,0 +1,196 @@
+package events_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/events/mocks"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+var outboxColumns = []string{"id", "event_id", "event_type", "source", "tenant_id", "payload", "occurred_at", "published_at", "attempts", "last_error", "created_at"}
+
+// fakeOutbox understands the statements the relay sends and keeps the outbox rows in order
+type fakeOutbox struct {
+	rows []map[string]driver.Value
+	// queries are the SELECTs the relay sent
+	queries []string
+}
+
+func newFakeOutbox(n int) *fakeOutbox {
+	o := &fakeOutbox{}
+	for i := 1; i <= n; i++ {
+		o.rows = append(o.rows, map[string]driver.Value{
+			"id": int64(i), "event_id": "evt-" + strconv.Itoa(i), "event_type": "order.created", "source": "order",
+			"tenant_id": "acme", "payload": []byte(`{}`), "occurred_at": time.Unix(int64(i), 0).UTC(),
+			"published_at": nil, "attempts": int64(0), "last_error": "", "created_at": time.Unix(int64(i), 0).UTC(),
+		})
+	}
+	return o
+}
+
+func (o *fakeOutbox) Connect(context.Context) (driver.Conn, error) { return o, nil }
+func (o *fakeOutbox) Driver() driver.Driver                        { return nil }
+
+var setColumn = regexp.MustCompile(`"(\w+)"=\$(\d+)`)
+
+// ExecContext applies `UPDATE "outbox_messages" SET "attempts"=$1,... WHERE id = $n`
+func (o *fakeOutbox) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, `UPDATE "outbox_messages" SET`) || !strings.HasSuffix(query, "WHERE id = $"+strconv.Itoa(len(args))) {
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	id := args[len(args)-1].Value
+	for _, row := range o.rows {
+		if row["id"] != id {
+			continue
+		}
+		for _, m := range setColumn.FindAllStringSubmatch(query, -1) {
+			n, _ := strconv.Atoi(m[2])
+			row[m[1]] = args[n-1].Value
+		}
+		return driver.RowsAffected(1), nil
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// QueryContext answers the relay's SELECT of unpublished rows
+func (o *fakeOutbox) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, `SELECT * FROM "outbox_messages" WHERE published_at IS NULL ORDER BY id LIMIT $1`) {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	o.queries = append(o.queries, query)
+	limit := int(args[0].Value.(int64))
+	rows := &fakeOutboxRows{}
+	for _, row := range o.rows {
+		if row["published_at"] != nil || len(rows.values) == limit {
+			continue
+		}
+		values := make([]driver.Value, len(outboxColumns))
+		for i, c := range outboxColumns {
+			values[i] = row[c]
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows, nil
+}
+
+func (o *fakeOutbox) Begin() (driver.Tx, error) { return o, nil }
+func (o *fakeOutbox) Commit() error             { return nil }
+func (o *fakeOutbox) Rollback() error           { return nil }
+func (o *fakeOutbox) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (o *fakeOutbox) Close() error { return nil }
+
+type fakeOutboxRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeOutboxRows) Columns() []string { return outboxColumns }
+func (r *fakeOutboxRows) Close() error      { return nil }
+
+func (r *fakeOutboxRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func (o *fakeOutbox) relay(t *testing.T, p events.Publisher, batchSize int) *events.OutboxRelay {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(o)}), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events.NewOutboxRelay(db, p, testLogger, events.OutboxRelayConfig{BatchSize: batchSize})
+}
+
+// eventID matches the event with the ID
+type eventID string
+
+func (id eventID) Matches(x any) bool {
+	e, ok := x.(*events.Event)
+	return ok && e.ID == string(id) && e.TenantID == "acme"
+}
+
+func (id eventID) String() string { return "is event " + string(id) + " of tenant acme" }
+
+func TestOutboxRelayBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	publisher := mocks.NewMockPublisher(ctrl)
+	gomock.InOrder(
+		publisher.EXPECT().Publish(eventID("evt-1")).Return(nil),
+		publisher.EXPECT().Publish(eventID("evt-2")).Return(nil),
+	)
+	outbox := newFakeOutbox(3)
+
+	if err := outbox.relay(t, publisher, 2).RelayBatch(); err != nil {
+		t.Fatal(err)
+	}
+	for i, wantPublished := range []bool{true, true, false} {
+		row := outbox.rows[i]
+		if published := row["published_at"] != nil; published != wantPublished {
+			t.Errorf("row %d published = %v, want %v", i+1, published, wantPublished)
+		}
+	}
+	if !strings.Contains(outbox.queries[0], "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("query %q does not skip rows other relays hold", outbox.queries[0])
+	}
+}
+
+// TestOutboxRelayStopsAtFailure checks that a failed event is retried before the ones after
+// it are sent, so consumers see events in order
+func TestOutboxRelayStopsAtFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	publisher := mocks.NewMockPublisher(ctrl)
+	gomock.InOrder(
+		publisher.EXPECT().Publish(eventID("evt-1")).Return(nil),
+		publisher.EXPECT().Publish(eventID("evt-2")).Return(errors.New("broker unavailable")),
+		publisher.EXPECT().Publish(eventID("evt-2")).Return(nil),
+		publisher.EXPECT().Publish(eventID("evt-3")).Return(nil),
+	)
+	outbox := newFakeOutbox(3)
+	relay := outbox.relay(t, publisher, 10)
+
+	if err := relay.RelayBatch(); err != nil {
+		t.Fatal(err)
+	}
+	failed := outbox.rows[1]
+	if failed["published_at"] != nil || failed["attempts"] != int64(1) || failed["last_error"] != "broker unavailable" {
+		t.Errorf("failed row = %v, want unpublished after 1 attempt with the error", failed)
+	}
+	if outbox.rows[2]["attempts"] != int64(0) {
+		t.Errorf("row after the failure was attempted")
+	}
+
+	if err := relay.RelayBatch(); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range outbox.rows {
+		if row["published_at"] == nil {
+			t.Errorf("row %d not published on retry", i+1)
+		}
+	}
+	if outbox.rows[1]["attempts"] != int64(2) || outbox.rows[1]["last_error"] != "" {
+		t.Errorf("retried row = %v, want 2 attempts and the error cleared", outbox.rows[1])
+	}
+}
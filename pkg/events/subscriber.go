@@ -0,0 +1,98 @@
+package events
+
+//go:generate mockgen -source=subscriber.go -destination=mocks/subscriber.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// Handler processes one event; ctx is scoped to the event's tenant
+type Handler func(ctx context.Context, event *Event) error
+
+type Subscriber interface {
+	// Subscribe binds a durable queue to the routing keys and dispatches deliveries to the handler
+	Subscribe(queue string, routingKeys []string, handler Handler) error
+	Close() error
+}
+
+type AMQPSubscriber struct {
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	exchange string
+	Logger   *logger.Logger
+}
+
+func NewAMQPSubscriber(url, exchange string, l *logger.Logger) (Subscriber, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open broker channel: %w", err)
+	}
+	if err := ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+	}
+	if err := ch.Qos(10, 0, false); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	return &AMQPSubscriber{conn: conn, channel: ch, exchange: exchange, Logger: l}, nil
+}
+
+func (s *AMQPSubscriber) Subscribe(queue string, routingKeys []string, handler Handler) error {
+	if _, err := s.channel.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", queue, err)
+	}
+	for _, key := range routingKeys {
+		if err := s.channel.QueueBind(queue, key, s.exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s: %w", queue, key, err)
+		}
+	}
+	deliveries, err := s.channel.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume queue %s: %w", queue, err)
+	}
+	s.Logger.Info("Subscribed to events", zap.String("queue", queue), zap.Strings("routingKeys", routingKeys))
+
+	go func() {
+		for d := range deliveries {
+			var event Event
+			if err := json.Unmarshal(d.Body, &event); err != nil {
+				s.Logger.Error("Discarding malformed event", zap.String("queue", queue), zap.Error(err))
+				_ = d.Nack(false, false)
+				continue
+			}
+			if event.TenantID != "" && !tenant.Valid(event.TenantID) {
+				s.Logger.Error("Discarding event with an invalid tenant", zap.String("queue", queue), zap.String("id", event.ID), zap.String("tenant", event.TenantID))
+				_ = d.Nack(false, false)
+				continue
+			}
+			if err := handler(event.Context(context.Background()), &event); err != nil {
+				// requeue once, then drop so a poison message cannot block the queue
+				s.Logger.Error("Error handling event", zap.String("type", event.Type), zap.String("id", event.ID), zap.String("tenant", event.TenantID), zap.Bool("redelivered", d.Redelivered), zap.Error(err))
+				_ = d.Nack(false, !d.Redelivered)
+				continue
+			}
+			_ = d.Ack(false)
+		}
+		s.Logger.Warn("Event delivery channel closed", zap.String("queue", queue))
+	}()
+	return nil
+}
+
+func (s *AMQPSubscriber) Close() error {
+	_ = s.channel.Close()
+	return s.conn.Close()
+}
@@ -0,0 +1,195 @@
+// Package featureflags gates new behaviour, e.g. a new checkout flow, behind flags that can be
+// switched, rolled out to a percentage of users or targeted at single users without a redeploy.
+package featureflags
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	BackendEnv   = "env"
+	BackendRedis = "redis"
+	BackendDB    = "db"
+)
+
+// Config selects where flags live. Flags from FEATURE_FLAGS are served by the env backend and
+// are the defaults for flags the redis and db backends do not define; they follow reloads.
+type Config struct {
+	Backend string
+	Flags   *config.Live[EnvProvider]
+	// Refresh is how long a replica reuses a flag read from redis or the database
+	Refresh time.Duration
+}
+
+// LoadConfig reads FEATURE_FLAGS_BACKEND, FEATURE_FLAGS_REFRESH_SECONDS and FEATURE_FLAGS, a
+// comma separated list like "reorder,guest-checkout=off,new-checkout=10%;user:7"
+func LoadConfig(src *config.Source) Config {
+	return Config{
+		Backend: src.OneOf("FEATURE_FLAGS_BACKEND", BackendEnv, BackendEnv, BackendRedis, BackendDB),
+		Flags:   config.Watch(src, "FEATURE_FLAGS", loadFlags),
+		Refresh: src.Duration("FEATURE_FLAGS_REFRESH_SECONDS", 30, time.Second),
+	}
+}
+
+func loadFlags(src *config.Source) EnvProvider {
+	flags := EnvProvider{}
+	for _, item := range src.List("FEATURE_FLAGS", nil) {
+		f, problem := parseFlag(item)
+		if problem != "" {
+			src.Fail("FEATURE_FLAGS", problem)
+			continue
+		}
+		flags[f.Key] = f
+	}
+	return flags
+}
+
+type cachedFlag struct {
+	flag      *Flag
+	fetchedAt time.Time
+}
+
+// Client evaluates flags. Lookups are cached for Config.Refresh; when the backend fails the last
+// known value is used, and a flag never read successfully is off.
+type Client struct {
+	provider Provider
+	refresh  time.Duration
+	mu       sync.RWMutex
+	flags    map[string]cachedFlag
+	Logger   *logger.Logger
+}
+
+func New(provider Provider, refresh time.Duration, l *logger.Logger) *Client {
+	return &Client{provider: provider, refresh: refresh, flags: map[string]cachedFlag{}, Logger: l}
+}
+
+// NewFromConfig builds the client for cfg.Backend; store and db are only used by their backend
+func NewFromConfig(cfg Config, store cache.Store, db *gorm.DB, l *logger.Logger) *Client {
+	defaults := liveEnv{flags: cfg.Flags}
+	var c *Client
+	switch cfg.Backend {
+	case BackendRedis:
+		c = New(layered{Writer: NewStoreProvider(store), defaults: defaults}, cfg.Refresh, l)
+	case BackendDB:
+		c = New(layered{Writer: NewDBProvider(db), defaults: defaults}, cfg.Refresh, l)
+	default:
+		c = New(defaults, 0, l)
+	}
+	// cached lookups may have fallen back to the old defaults
+	cfg.Flags.Subscribe(func(EnvProvider) {
+		c.mu.Lock()
+		c.flags = map[string]cachedFlag{}
+		c.mu.Unlock()
+	})
+	return c
+}
+
+// Flag returns the definition of key, nil when it is not defined
+func (c *Client) Flag(ctx context.Context, key string) *Flag {
+	c.mu.RLock()
+	cached, ok := c.flags[key]
+	c.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < c.refresh {
+		return cached.flag
+	}
+	f, err := c.provider.Flag(ctx, key)
+	if err != nil {
+		c.Logger.WithContext(ctx).Warn("Failed to read feature flag", zap.String("flag", key), zap.Error(err))
+		return cached.flag
+	}
+	c.mu.Lock()
+	c.flags[key] = cachedFlag{flag: f, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return f
+}
+
+// EnabledFor reports whether key applies to the user, "" for anonymous callers
+func (c *Client) EnabledFor(ctx context.Context, key, userID string) bool {
+	return c.Flag(ctx, key).EnabledFor(userID)
+}
+
+// Enabled reports whether key applies to the caller of ctx, the actor set by the auth middleware
+func (c *Client) Enabled(ctx context.Context, key string) bool {
+	return c.EnabledFor(ctx, key, userID(ctx))
+}
+
+func userID(ctx context.Context) string {
+	if actor := audit.ActorFromContext(ctx); actor.ID != 0 {
+		return strconv.Itoa(actor.ID)
+	}
+	return ""
+}
+
+// Save changes a flag in a redis or db backend; the other replicas see it after Config.Refresh
+func (c *Client) Save(ctx context.Context, f Flag) error {
+	writer, ok := c.provider.(Writer)
+	if !ok {
+		return domainErrors.NewAppError(errNotWritable, domainErrors.ValidationError)
+	}
+	if f.Users == nil {
+		f.Users = []string{}
+	}
+	if err := writer.Save(ctx, f); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	delete(c.flags, f.Key)
+	c.mu.Unlock()
+	c.Logger.WithContext(ctx).Warn("Feature flag changed", zap.String("flag", f.Key), zap.Bool("enabled", f.Enabled),
+		zap.Int("rollout", f.Rollout), zap.Strings("users", f.Users))
+	return nil
+}
+
+type clientKey struct{}
+
+// ContextWithClient returns a copy of ctx carrying the client for Enabled
+func ContextWithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// Enabled reports whether key applies to the caller of ctx using the client stored by
+// Middleware, so usecases can check flags without a dependency on the client. Without a
+// client every flag is off.
+func Enabled(ctx context.Context, key string) bool {
+	c, _ := ctx.Value(clientKey{}).(*Client)
+	if c == nil {
+		return false
+	}
+	return c.Enabled(ctx, key)
+}
+
+// Middleware stores the client in the request context for Enabled and Require. The caller is
+// resolved when a flag is checked, so it may run before AuthJWTMiddleware.
+func Middleware(c *Client) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(ContextWithClient(ctx.Request.Context(), c))
+		ctx.Next()
+	}
+}
+
+// Require answers 404 for routes behind a flag that is off for the caller, as if they did not
+// exist yet. Mount it after Middleware and, for per-user flags, AuthJWTMiddleware.
+func Require(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if Enabled(c.Request.Context(), key) {
+			c.Next()
+			return
+		}
+		middleware.AbortWithError(c, http.StatusNotFound, domainErrors.CodeNotFound, "Not found")
+	}
+}
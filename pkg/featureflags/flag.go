@@ -0,0 +1,81 @@
+package featureflags
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Flag gates one behaviour. Enabled is the kill switch; when it is on the flag applies to the
+// users listed in Users and to Rollout percent of all other users, picked by a stable hash of
+// the key and user ID so a user keeps their variant between requests.
+type Flag struct {
+	Key     string   `json:"key"`
+	Enabled bool     `json:"enabled"`
+	Rollout int      `json:"rollout"`
+	Users   []string `json:"users,omitempty"`
+}
+
+// EnabledFor reports whether the flag applies to the user; anonymous callers, an empty userID,
+// only get flags rolled out to everyone
+func (f *Flag) EnabledFor(userID string) bool {
+	if f == nil || !f.Enabled {
+		return false
+	}
+	if f.Rollout >= 100 {
+		return true
+	}
+	if userID == "" {
+		return false
+	}
+	for _, u := range f.Users {
+		if u == userID {
+			return true
+		}
+	}
+	return bucket(f.Key, userID) < f.Rollout
+}
+
+// bucket maps the user to 0-99, independently per flag so the same users are not always first
+func bucket(key, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
+
+// parseFlag reads one FEATURE_FLAGS item: "name" or "name=" followed by ";"-separated parts,
+// each on/off (true/false, 1/0), a rollout like "25%" or a target like "user:42", e.g.
+// "new-checkout=10%;user:7;user:9". Targets and rollouts switch the flag on.
+func parseFlag(item string) (Flag, string) {
+	name, value, hasValue := strings.Cut(item, "=")
+	f := Flag{Key: strings.TrimSpace(name), Enabled: true, Rollout: 100}
+	if !hasValue {
+		return f, ""
+	}
+	targeted := false
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "true" || part == "1" || part == "on":
+		case part == "false" || part == "0" || part == "off":
+			f.Enabled = false
+		case strings.HasSuffix(part, "%"):
+			pct, err := strconv.Atoi(strings.TrimSuffix(part, "%"))
+			if err != nil || pct < 0 || pct > 100 {
+				return f, f.Key + " rollout must be between 0% and 100%"
+			}
+			f.Rollout, targeted = pct, true
+		case strings.HasPrefix(part, "user:"):
+			f.Users = append(f.Users, strings.TrimPrefix(part, "user:"))
+			if !targeted {
+				// users alone target only them
+				f.Rollout, targeted = 0, true
+			}
+		default:
+			return f, f.Key + " must be on, off, a percentage or user:<id>"
+		}
+	}
+	return f, ""
+}
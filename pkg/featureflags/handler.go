@@ -0,0 +1,51 @@
+package featureflags
+
+import (
+	"errors"
+	"net/http"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errNotWritable = errors.New("flags of the env backend are changed through FEATURE_FLAGS")
+
+// SaveFlagRequest is the body of PUT /internal/feature-flags/:key
+type SaveFlagRequest struct {
+	Enabled bool     `json:"enabled"`
+	Rollout *int     `json:"rollout" binding:"omitempty,min=0,max=100"`
+	Users   []string `json:"users"`
+}
+
+// Handler serves GET and PUT on a route with a :key parameter. Mount it behind the internal
+// token; flags of the env backend are read-only.
+func (c *Client) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.Param("key")
+		if ctx.Request.Method == http.MethodGet {
+			f := c.Flag(ctx.Request.Context(), key)
+			if f == nil {
+				_ = ctx.Error(domainErrors.NewAppError(errors.New("feature flag not found"), domainErrors.NotFound))
+				return
+			}
+			ctx.JSON(http.StatusOK, f)
+			return
+		}
+
+		var req SaveFlagRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+			return
+		}
+		f := Flag{Key: key, Enabled: req.Enabled, Rollout: 100, Users: req.Users}
+		if req.Rollout != nil {
+			f.Rollout = *req.Rollout
+		}
+		if err := c.Save(ctx.Request.Context(), f); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, f)
+	}
+}
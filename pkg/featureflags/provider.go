@@ -0,0 +1,138 @@
+package featureflags
+
+//go:generate mockgen -source=provider.go -destination=mocks/provider.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Provider looks flags up by key
+type Provider interface {
+	// Flag returns nil when the flag is not defined
+	Flag(ctx context.Context, key string) (*Flag, error)
+}
+
+// Writer is a Provider whose flags can be changed at runtime
+type Writer interface {
+	Provider
+	Save(ctx context.Context, f Flag) error
+}
+
+// EnvProvider serves the flags parsed from FEATURE_FLAGS
+type EnvProvider map[string]Flag
+
+func (p EnvProvider) Flag(_ context.Context, key string) (*Flag, error) {
+	f, ok := p[key]
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// liveEnv serves FEATURE_FLAGS as last reloaded
+type liveEnv struct {
+	flags *config.Live[EnvProvider]
+}
+
+func (p liveEnv) Flag(ctx context.Context, key string) (*Flag, error) {
+	return p.flags.Get().Flag(ctx, key)
+}
+
+// StoreProvider keeps flags as JSON under "featureflags:<key>" in the shared cache store, so a
+// flag set in Redis applies to every service reading it
+type StoreProvider struct {
+	store cache.Store
+}
+
+func NewStoreProvider(store cache.Store) Writer {
+	return &StoreProvider{store: store}
+}
+
+func storeKey(key string) string {
+	return "featureflags:" + key
+}
+
+func (p *StoreProvider) Flag(ctx context.Context, key string) (*Flag, error) {
+	data, err := p.store.Get(ctx, storeKey(key))
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// a flag written by hand without a rollout applies to everyone
+	f := Flag{Key: key, Rollout: 100}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	f.Key = key
+	return &f, nil
+}
+
+func (p *StoreProvider) Save(ctx context.Context, f Flag) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	// flags do not expire
+	return p.store.Set(ctx, storeKey(f.Key), data, 0)
+}
+
+// FeatureFlag is the feature_flags row
+type FeatureFlag struct {
+	Key     string   `gorm:"column:key;primaryKey"`
+	Enabled bool     `gorm:"column:enabled;not null"`
+	Rollout int      `gorm:"column:rollout;not null"`
+	Users   []string `gorm:"column:users;type:jsonb;serializer:json;not null"`
+}
+
+func (FeatureFlag) TableName() string { return "feature_flags" }
+
+// DBProvider keeps flags in the service's feature_flags table
+type DBProvider struct {
+	DB *gorm.DB
+}
+
+func NewDBProvider(db *gorm.DB) Writer {
+	return &DBProvider{DB: db}
+}
+
+func (p *DBProvider) Flag(ctx context.Context, key string) (*Flag, error) {
+	var row FeatureFlag
+	err := p.DB.WithContext(ctx).Where("key = ?", key).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Flag{Key: row.Key, Enabled: row.Enabled, Rollout: row.Rollout, Users: row.Users}, nil
+}
+
+func (p *DBProvider) Save(ctx context.Context, f Flag) error {
+	return p.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&FeatureFlag{Key: f.Key, Enabled: f.Enabled, Rollout: f.Rollout, Users: f.Users}).Error
+}
+
+// layered answers from primary and falls back to the FEATURE_FLAGS defaults for flags it does
+// not define
+type layered struct {
+	Writer
+	defaults Provider
+}
+
+func (p layered) Flag(ctx context.Context, key string) (*Flag, error) {
+	f, err := p.Writer.Flag(ctx, key)
+	if err != nil || f != nil {
+		return f, err
+	}
+	return p.defaults.Flag(ctx, key)
+}
@@ -0,0 +1,197 @@
+// Package fixtures loads demo datasets of users, categories, products and orders so demo and
+// staging environments and end-to-end tests start from known data. A set is written in YAML or
+// JSON; every service applies the records it owns with its own Seeder, matching them by natural
+// key so seeding twice changes nothing.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set is a dataset of demo records. Records refer to each other by natural keys, never by
+// database IDs: products name their category's slug, orders their customer's email and their
+// products' SKUs.
+type Set struct {
+	Users      []User     `json:"users" yaml:"users"`
+	Categories []Category `json:"categories" yaml:"categories"`
+	Products   []Product  `json:"products" yaml:"products"`
+	Orders     []Order    `json:"orders" yaml:"orders"`
+}
+
+// User is matched by email
+type User struct {
+	Email string `json:"email" yaml:"email"`
+	// Password is only set when the user is created, so changed passwords survive reseeding
+	Password  string `json:"password" yaml:"password"`
+	UserName  string `json:"userName" yaml:"userName"`
+	FirstName string `json:"firstName" yaml:"firstName"`
+	LastName  string `json:"lastName" yaml:"lastName"`
+	// Role defaults to customer
+	Role string `json:"role" yaml:"role"`
+	// Active defaults to true
+	Active *bool `json:"active" yaml:"active"`
+}
+
+// Category is matched by slug
+type Category struct {
+	Slug        string `json:"slug" yaml:"slug"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Product is matched by SKU
+type Product struct {
+	SKU         string  `json:"sku" yaml:"sku"`
+	Name        string  `json:"name" yaml:"name"`
+	Description string  `json:"description" yaml:"description"`
+	Price       float64 `json:"price" yaml:"price"`
+	Stock       int     `json:"stock" yaml:"stock"`
+	// Category is the slug of the product's category
+	Category string `json:"category" yaml:"category"`
+	ImageURL string `json:"imageUrl" yaml:"imageUrl"`
+	// Active defaults to true
+	Active *bool `json:"active" yaml:"active"`
+}
+
+// Order is matched by Key; its number is assigned by the order service like any other
+type Order struct {
+	Key string `json:"key" yaml:"key"`
+	// User is the customer's email
+	User string `json:"user" yaml:"user"`
+	// Status defaults to pending
+	Status string `json:"status" yaml:"status"`
+	// Currency defaults to the order service's base currency
+	Currency string      `json:"currency" yaml:"currency"`
+	Items    []OrderItem `json:"items" yaml:"items"`
+}
+
+type OrderItem struct {
+	// Product is the product's SKU
+	Product  string `json:"product" yaml:"product"`
+	Quantity int    `json:"quantity" yaml:"quantity"`
+	// Price defaults to the product's catalog price
+	Price float64 `json:"price" yaml:"price"`
+}
+
+// Load reads a fixture file, or every .yaml, .yml and .json file of a directory in name order,
+// and checks the records
+func Load(path string) (*Set, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = nil
+		for _, e := range entries {
+			if !e.IsDir() && isFixtureFile(e.Name()) {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no fixture files in %s", path)
+		}
+	}
+	set := &Set{}
+	for _, file := range files {
+		part, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		set.Users = append(set.Users, part.Users...)
+		set.Categories = append(set.Categories, part.Categories...)
+		set.Products = append(set.Products, part.Products...)
+		set.Orders = append(set.Orders, part.Orders...)
+	}
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func isFixtureFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func readFile(file string) (*Set, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var set Set
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&set)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&set); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Validate checks that every record has its key and required fields and that no key repeats
+func (s *Set) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	emails, slugs, skus, keys := map[string]bool{}, map[string]bool{}, map[string]bool{}, map[string]bool{}
+	unique := func(seen map[string]bool, key, kind string) {
+		check(!seen[strings.ToLower(key)], "%s %q is defined twice", kind, key)
+		seen[strings.ToLower(key)] = true
+	}
+	for i, u := range s.Users {
+		check(u.Email != "", "users[%d]: email is required", i)
+		check(u.Password != "", "users[%d]: password is required", i)
+		unique(emails, u.Email, "user")
+	}
+	for i, c := range s.Categories {
+		check(c.Slug != "" && c.Name != "", "categories[%d]: slug and name are required", i)
+		unique(slugs, c.Slug, "category")
+	}
+	for i, p := range s.Products {
+		check(p.SKU != "" && p.Name != "", "products[%d]: sku and name are required", i)
+		check(p.Category != "", "products[%d]: category is required", i)
+		check(p.Price >= 0 && p.Stock >= 0, "products[%d]: price and stock must not be negative", i)
+		unique(skus, p.SKU, "product")
+	}
+	for i, o := range s.Orders {
+		check(o.Key != "" && o.User != "", "orders[%d]: key and user are required", i)
+		check(len(o.Items) > 0, "orders[%d]: at least one item is required", i)
+		for j, item := range o.Items {
+			check(item.Product != "" && item.Quantity > 0, "orders[%d].items[%d]: product and a positive quantity are required", i, j)
+			check(item.Price >= 0, "orders[%d].items[%d]: price must not be negative", i, j)
+		}
+		unique(keys, o.Key, "order")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid fixtures: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
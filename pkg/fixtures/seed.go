@@ -0,0 +1,86 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Config selects the fixtures a service seeds
+type Config struct {
+	// Path is a fixture file or a directory of them
+	Path string
+	// OnStart seeds Path every time the service starts
+	OnStart bool
+}
+
+// LoadConfig reads FIXTURES_PATH and SEED_FIXTURES, which needs the path
+func LoadConfig(src *config.Source) Config {
+	cfg := Config{Path: src.String("FIXTURES_PATH", ""), OnStart: src.Bool("SEED_FIXTURES", false)}
+	if cfg.OnStart && cfg.Path == "" {
+		src.Fail("SEED_FIXTURES", "needs FIXTURES_PATH")
+	}
+	return cfg
+}
+
+// Result counts the records a seeder wrote
+type Result struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("created %d, updated %d, unchanged %d", r.Created, r.Updated, r.Unchanged)
+}
+
+// Seeder applies the records of a set its service owns and ignores the others. Records are
+// matched by natural key and only written when they differ, so seeding is idempotent.
+type Seeder interface {
+	Seed(ctx context.Context, set *Set) (Result, error)
+}
+
+// RunSeedCommand runs "seed [path]", seeding path or else the configured FIXTURES_PATH
+func RunSeedCommand(ctx context.Context, cfg Config, args []string, seeder Seeder, out io.Writer) error {
+	path := cfg.Path
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		return errors.New("usage: seed <fixture file or directory>, or set FIXTURES_PATH")
+	}
+	set, err := Load(path)
+	if err != nil {
+		return err
+	}
+	result, err := seeder.Seed(ctx, set)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "seeded %s: %s\n", path, result)
+	return err
+}
+
+// SeedOnStart seeds the configured fixtures when SEED_FIXTURES is on
+func SeedOnStart(ctx context.Context, cfg Config, seeder Seeder, log *logger.Logger) error {
+	if !cfg.OnStart {
+		return nil
+	}
+	set, err := Load(cfg.Path)
+	if err != nil {
+		return err
+	}
+	result, err := seeder.Seed(ctx, set)
+	if err != nil {
+		return err
+	}
+	log.Info("Seeded fixtures", zap.String("path", cfg.Path), zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated), zap.Int("unchanged", result.Unchanged))
+	return nil
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	// DefaultTimeout bounds each readiness check
+	DefaultTimeout = 2 * time.Second
+)
+
+// CheckFunc reports whether a dependency is usable. Details, when not nil, are included in
+// the readiness answer, e.g. connection pool statistics.
+type CheckFunc func(ctx context.Context) (details any, err error)
+
+// DependencyStatus is the outcome of one readiness check
+type DependencyStatus struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
+
+// Report is the body of the liveness and readiness answers
+type Report struct {
+	Status       string                      `json:"status"`
+	Service      string                      `json:"service"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Registry holds the readiness checks of a service
+type Registry struct {
+	service string
+	timeout time.Duration
+	checks  []check
+}
+
+func NewRegistry(service string) *Registry {
+	return &Registry{service: service, timeout: DefaultTimeout}
+}
+
+// Add registers a readiness check under the dependency name and returns the registry for chaining
+func (r *Registry) Add(name string, fn CheckFunc) *Registry {
+	r.checks = append(r.checks, check{name: name, fn: fn})
+	return r
+}
+
+// Check runs every readiness check concurrently, each bounded by the registry timeout. The
+// service is ready when all of them pass.
+func (r *Registry) Check(ctx context.Context) (Report, bool) {
+	report := Report{Status: "ok", Service: r.service, Dependencies: make(map[string]DependencyStatus, len(r.checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range r.checks {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			start := time.Now()
+			details, err := c.fn(checkCtx)
+			status := DependencyStatus{Status: StatusUp, Latency: time.Since(start).String(), Details: details}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+			mu.Lock()
+			report.Dependencies[c.name] = status
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	ready := true
+	for _, status := range report.Dependencies {
+		if status.Status != StatusUp {
+			ready = false
+		}
+	}
+	if !ready {
+		report.Status = "unavailable"
+	}
+	return report, ready
+}
+
+// LivenessHandler answers 200 while the process can serve HTTP. It checks no dependencies so
+// that an outage of one does not get the service restarted.
+func (r *Registry) LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{Status: "ok", Service: r.service})
+	}
+}
+
+// ReadinessHandler answers 200 with the status of every dependency when all checks pass and
+// 503 otherwise, so the service is taken out of load balancing until it recovers
+func (r *Registry) ReadinessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, ready := r.Check(c.Request.Context())
+		code := http.StatusOK
+		if !ready {
+			code = http.StatusServiceUnavailable
+		}
+		c.JSON(code, report)
+	}
+}
+
+// RegisterRoutes serves the probes under /health: /health/live for liveness, and /health/ready
+// and /health itself for readiness
+func (r *Registry) RegisterRoutes(g gin.IRoutes) {
+	g.GET("/health", r.ReadinessHandler())
+	g.GET("/health/live", r.LivenessHandler())
+	g.GET("/health/ready", r.ReadinessHandler())
+}
@@ -0,0 +1,194 @@
+// Package idempotency makes unsafe requests safe to retry. A request carrying an
+// Idempotency-Key header runs once per key, caller and route; retries get the stored response
+// of the first run back instead of repeating its side effects.
+package idempotency
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/idempotency.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on responses served from the store
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with the body
+var replayedHeaders = []string{"Content-Type", "Location", "Content-Disposition"}
+
+// Record is the stored outcome of a request; Completed is false while the first run is in progress
+type Record struct {
+	Fingerprint string              `json:"fingerprint"`
+	Completed   bool                `json:"completed"`
+	Status      int                 `json:"status,omitempty"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+}
+
+// Store keeps records by key
+type Store interface {
+	// Lock stores r under key for ttl unless the key is taken; false means it was
+	Lock(ctx context.Context, key string, r *Record, ttl time.Duration) (bool, error)
+	// Get returns nil when there is no record
+	Get(ctx context.Context, key string) (*Record, error)
+	Save(ctx context.Context, key string, r *Record, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+// Config sets how long responses are replayed and how long a run may hold its key before a
+// retry may take over, e.g. after the replica running it crashed
+type Config struct {
+	TTL     time.Duration
+	LockTTL time.Duration
+}
+
+func LoadConfig(src *config.Source) Config {
+	return Config{
+		TTL:     src.Duration("IDEMPOTENCY_TTL_HOURS", 24, time.Hour),
+		LockTTL: src.Duration("IDEMPOTENCY_LOCK_SECONDS", 60, time.Second),
+	}
+}
+
+// Middleware runs requests with an Idempotency-Key once per key, caller and route, scope
+// naming the service. Responses below 500 are stored and replayed for cfg.TTL; errors and
+// failed runs release the key so the request can be retried. A retry while the first run is
+// still going fails with 409, reusing a key for a different body with 422. Requests without
+// the header pass through. Mount it after AuthJWTMiddleware to key by user rather than address.
+func Middleware(store Store, scope string, cfg Config, l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(Header)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxKeyLength {
+			middleware.AbortWithError(c, http.StatusBadRequest, domainErrors.CodeValidation, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			middleware.AbortWithError(c, http.StatusBadRequest, domainErrors.CodeValidation, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key := storeKey(scope, middleware.KeyByUser(c), c.Request.Method+" "+c.FullPath(), idempotencyKey)
+		fingerprint := hash(c.Request.Method, c.Request.URL.RequestURI(), string(body))
+
+		locked, err := store.Lock(ctx, key, &Record{Fingerprint: fingerprint}, cfg.LockTTL)
+		if err != nil {
+			// without the store the request cannot be deduplicated; refuse rather than risk running it twice
+			l.WithContext(ctx).Error("Failed to lock idempotency key", zap.Error(err))
+			middleware.AbortWithError(c, http.StatusServiceUnavailable, domainErrors.CodeServiceUnavailable, "Idempotency store unavailable")
+			return
+		}
+		if !locked {
+			replay(c, store, key, fingerprint, l)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// the error handler writes the response of c.Errors after this middleware returns
+		if len(c.Errors) > 0 || recorder.Status() >= http.StatusInternalServerError {
+			if err := store.Release(context.WithoutCancel(ctx), key); err != nil {
+				l.WithContext(ctx).Error("Failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+		record := &Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      recorder.Status(),
+			Header:      map[string][]string{},
+			Body:        recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if v := recorder.Header().Values(name); len(v) > 0 {
+				record.Header[name] = v
+			}
+		}
+		if err := store.Save(context.WithoutCancel(ctx), key, record, cfg.TTL); err != nil {
+			l.WithContext(ctx).Error("Failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+func replay(c *gin.Context, store Store, key, fingerprint string, l *logger.Logger) {
+	record, err := store.Get(c.Request.Context(), key)
+	if err != nil {
+		l.WithContext(c.Request.Context()).Error("Failed to read idempotency record", zap.Error(err))
+		middleware.AbortWithError(c, http.StatusServiceUnavailable, domainErrors.CodeServiceUnavailable, "Idempotency store unavailable")
+		return
+	}
+	switch {
+	case record == nil:
+		// the first run released or lost the key in between; the client may retry
+		middleware.AbortWithError(c, http.StatusConflict, domainErrors.CodeRequestInProgress, "A request with this Idempotency-Key is already being processed")
+	case record.Fingerprint != fingerprint:
+		middleware.AbortWithError(c, http.StatusUnprocessableEntity, domainErrors.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+	case !record.Completed:
+		middleware.AbortWithError(c, http.StatusConflict, domainErrors.CodeRequestInProgress, "A request with this Idempotency-Key is already being processed")
+	default:
+		for name, values := range record.Header {
+			for _, v := range values {
+				c.Writer.Header().Add(name, v)
+			}
+		}
+		c.Header(ReplayedHeader, "true")
+		c.Status(record.Status)
+		_, _ = c.Writer.Write(record.Body)
+		c.Abort()
+	}
+}
+
+func storeKey(parts ...string) string {
+	return "idempotency:" + hash(parts...)
+}
+
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the body written by the handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
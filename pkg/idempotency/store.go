@@ -0,0 +1,117 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CacheStore keeps records in the cache store, Redis in production
+type CacheStore struct {
+	store cache.Store
+}
+
+func NewCacheStore(s cache.Store) Store {
+	return &CacheStore{store: s}
+}
+
+func (s *CacheStore) Lock(ctx context.Context, key string, r *Record, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return false, err
+	}
+	return s.store.SetNX(ctx, key, data, ttl)
+}
+
+func (s *CacheStore) Get(ctx context.Context, key string) (*Record, error) {
+	data, err := s.store.Get(ctx, key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *CacheStore) Save(ctx context.Context, key string, r *Record, ttl time.Duration) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.store.Set(ctx, key, data, ttl)
+}
+
+func (s *CacheStore) Release(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+// IdempotencyRecord is the idempotency_records row. Expired rows are taken over by the next
+// request with their key.
+type IdempotencyRecord struct {
+	Key       string    `gorm:"column:key;primaryKey"`
+	Record    []byte    `gorm:"column:record;type:jsonb;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+}
+
+func (IdempotencyRecord) TableName() string { return "idempotency_records" }
+
+// DBStore keeps records in the idempotency_records table, for services whose responses must
+// survive a cache flush, e.g. payments
+type DBStore struct {
+	DB *gorm.DB
+}
+
+func NewDBStore(db *gorm.DB) Store {
+	return &DBStore{DB: db}
+}
+
+func (s *DBStore) Lock(ctx context.Context, key string, r *Record, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	tx := s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"record", "expires_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "idempotency_records.expires_at < ?", Vars: []any{now}}}},
+	}).Create(&IdempotencyRecord{Key: key, Record: data, ExpiresAt: now.Add(ttl)})
+	return tx.RowsAffected == 1, tx.Error
+}
+
+func (s *DBStore) Get(ctx context.Context, key string) (*Record, error) {
+	var row IdempotencyRecord
+	err := s.DB.WithContext(ctx).Where("key = ? AND expires_at >= ?", key, time.Now()).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Record
+	if err := json.Unmarshal(row.Record, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *DBStore) Save(ctx context.Context, key string, r *Record, ttl time.Duration) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.DB.WithContext(ctx).Model(&IdempotencyRecord{}).Where("key = ?", key).
+		Updates(map[string]any{"record": data, "expires_at": time.Now().Add(ttl)}).Error
+}
+
+func (s *DBStore) Release(ctx context.Context, key string) error {
+	return s.DB.WithContext(ctx).Where("key = ?", key).Delete(&IdempotencyRecord{}).Error
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" when there is none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx whose loggers from WithContext add fields, e.g. the
+// authenticated user ID. Fields already in ctx are kept.
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	existing, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return context.WithValue(ctx, fieldsKey{}, append(existing[:len(existing):len(existing)], fields...))
+}
+
+// WithContext returns a child logger whose lines carry the request ID and the fields in ctx.
+// Without any the logger itself is returned.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields = append([]zap.Field{zap.String("request_id", id)}, fields...)
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"ecommerce-microservice-go/pkg/config"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileConfig is the optional rotating log file. A file is rotated when it reaches MaxSizeMB;
+// rotated files older than MaxAgeDays or beyond the newest MaxBackups are removed (0 keeps them).
+type FileConfig struct {
+	Path       string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
+}
+
+func loadFileConfig(src *config.Source) FileConfig {
+	return FileConfig{
+		Path:       src.String("LOG_FILE", ""),
+		MaxSizeMB:  src.Int("LOG_FILE_MAX_SIZE_MB", 100),
+		MaxAgeDays: src.Int("LOG_FILE_MAX_AGE_DAYS", 28),
+		MaxBackups: src.Int("LOG_FILE_MAX_BACKUPS", 5),
+		Compress:   src.Bool("LOG_FILE_COMPRESS", true),
+	}
+}
+
+func (c FileConfig) writer() *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   c.Path,
+		MaxSize:    c.MaxSizeMB,
+		MaxAge:     c.MaxAgeDays,
+		MaxBackups: c.MaxBackups,
+		Compress:   c.Compress,
+		LocalTime:  true,
+	}
+}
+
+// Close flushes buffered lines and closes the log file
+func (l *Logger) Close() error {
+	_ = l.Log.Sync()
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
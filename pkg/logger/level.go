@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config selects the level and sampling of a service logger
+type Config struct {
+	Level zapcore.Level
+	// LiveLevel, when set, changes the level whenever a reload changes LOG_LEVEL or LOG_LEVEL_FILE
+	LiveLevel   *config.Live[zapcore.Level]
+	Development bool
+	// DisableStdout leaves File as the only output
+	DisableStdout bool
+	File          FileConfig
+	// Info and debug lines with the same message are logged SampleInitial times a second,
+	// then every SampleThereafter-th; 0 disables sampling
+	SampleInitial    int
+	SampleThereafter int
+}
+
+// LoadConfig reads LOG_LEVEL (debug in development, info otherwise) or the level in the file
+// named by LOG_LEVEL_FILE, LOG_SAMPLING_INITIAL and LOG_SAMPLING_THEREAFTER, which default to
+// sampling outside development only, LOG_STDOUT and the LOG_FILE settings. The level follows
+// reloads.
+func LoadConfig(src *config.Source, env string) Config {
+	dev := env == config.EnvDevelopment
+	defaultThereafter := 100
+	if dev {
+		defaultThereafter = 0
+	}
+	cfg := Config{
+		Development:      dev,
+		SampleInitial:    src.Int("LOG_SAMPLING_INITIAL", 100),
+		SampleThereafter: src.Int("LOG_SAMPLING_THEREAFTER", defaultThereafter),
+		DisableStdout:    !src.Bool("LOG_STDOUT", true),
+		File:             loadFileConfig(src),
+		LiveLevel: config.Watch(src, "LOG_LEVEL", func(src *config.Source) zapcore.Level {
+			return loadLevel(src, dev)
+		}),
+	}
+	cfg.Level = cfg.LiveLevel.Get()
+	return cfg
+}
+
+func loadLevel(src *config.Source, dev bool) zapcore.Level {
+	defaultLevel := "info"
+	if dev {
+		defaultLevel = "debug"
+	}
+	if path := src.String("LOG_LEVEL_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			src.Fail("LOG_LEVEL_FILE", "cannot be read: "+err.Error())
+			return zapcore.InfoLevel
+		}
+		level, err := zapcore.ParseLevel(strings.TrimSpace(string(data)))
+		if err != nil {
+			src.Fail("LOG_LEVEL_FILE", "must contain debug, info, warn or error")
+			return zapcore.InfoLevel
+		}
+		return level
+	}
+	level, err := zapcore.ParseLevel(src.OneOf("LOG_LEVEL", defaultLevel, "debug", "info", "warn", "error"))
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return level
+}
+
+// Level returns the current minimum level
+func (l *Logger) Level() zapcore.Level {
+	return l.level.Level()
+}
+
+// SetLevel changes the minimum level of the logger and all of its children at runtime
+func (l *Logger) SetLevel(level zapcore.Level) {
+	l.level.SetLevel(level)
+}
+
+// LevelHandler answers GET with {"level":"info"} and changes the level on PUT with the same
+// body. Mount it behind authentication, e.g. on the internal routes.
+func (l *Logger) LevelHandler() gin.HandlerFunc {
+	return gin.WrapH(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		before := l.Level()
+		l.level.ServeHTTP(w, r)
+		if after := l.Level(); after != before {
+			l.Warn("Log level changed", zap.Stringer("from", before), zap.Stringer("to", after), zap.String("request_id", RequestIDFromContext(r.Context())))
+		}
+	}))
+}
+
+// ReloadOnSIGHUP reloads the settings on SIGHUP, see config.Reload, and logs the outcome. The
+// returned function stops listening.
+func (l *Logger) ReloadOnSIGHUP() func() {
+	return config.ReloadOnSIGHUP(func(changed []string, err error) {
+		l.reportReload(changed, err, zap.String("trigger", "SIGHUP"))
+	})
+}
+
+// ReloadHandler reloads the settings on POST and answers {"changed":["CORS"]} with the settings
+// that changed, or 400 with {"error":...} when the new settings are invalid and none was
+// applied. Mount it behind authentication, e.g. on the internal routes.
+func (l *Logger) ReloadHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		changed, err := config.Reload()
+		l.reportReload(changed, err, zap.String("trigger", "request"), zap.String("request_id", RequestIDFromContext(c.Request.Context())))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if changed == nil {
+			changed = []string{}
+		}
+		c.JSON(http.StatusOK, gin.H{"changed": changed})
+	}
+}
+
+func (l *Logger) reportReload(changed []string, err error, fields ...zap.Field) {
+	if err != nil {
+		l.Error("Settings reload rejected", append(fields, zap.Error(err))...)
+		return
+	}
+	l.Warn("Settings reloaded", append(fields, zap.Strings("changed", changed))...)
+}
@@ -9,14 +9,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 	gormlogger "gorm.io/gorm/logger"
 )
 
 type Logger struct {
 	Log *zap.Logger
+	// level is shared by every child logger, so a runtime change applies to all of them
+	level zap.AtomicLevel
+	file  *lumberjack.Logger
 }
 
-func NewLogger() (*Logger, error) {
+// New builds the JSON logger described by cfg, writing to stdout, the rotating cfg.File or both.
+// Info and lower levels are sampled per message when cfg.SampleThereafter is set; warnings and
+// errors are never dropped.
+func New(cfg Config) (*Logger, error) {
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
@@ -31,35 +38,57 @@ func NewLogger() (*Logger, error) {
 		EncodeCaller:   zapcore.FullCallerEncoder,
 		EncodeName:     zapcore.FullNameEncoder,
 	}
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zap.NewAtomicLevelAt(zap.InfoLevel),
-	)
-	return &Logger{Log: zap.New(core)}, nil
+	level := zap.NewAtomicLevelAt(cfg.Level)
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	var outputs []zapcore.WriteSyncer
+	if !cfg.DisableStdout {
+		outputs = append(outputs, zapcore.AddSync(os.Stdout))
+	}
+	var file *lumberjack.Logger
+	if cfg.File.Path != "" {
+		file = cfg.File.writer()
+		outputs = append(outputs, zapcore.AddSync(file))
+	}
+	if len(outputs) == 0 {
+		return nil, errors.New("logger has no output: set LOG_FILE or enable LOG_STDOUT")
+	}
+	out := zapcore.NewMultiWriteSyncer(outputs...)
+
+	var core zapcore.Core = zapcore.NewCore(encoder, out, level)
+	if cfg.SampleThereafter > 0 {
+		low := zapcore.NewCore(encoder, out, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return level.Enabled(l) && l <= zapcore.InfoLevel
+		}))
+		high := zapcore.NewCore(encoder, out, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return level.Enabled(l) && l > zapcore.InfoLevel
+		}))
+		core = zapcore.NewTee(zapcore.NewSamplerWithOptions(low, time.Second, cfg.SampleInitial, cfg.SampleThereafter), high)
+	}
+
+	var opts []zap.Option
+	if cfg.Development {
+		opts = append(opts, zap.AddStacktrace(zap.ErrorLevel))
+	}
+	l := &Logger{Log: zap.New(core, opts...), level: level, file: file}
+	cfg.LiveLevel.Subscribe(func(next zapcore.Level) {
+		before := l.Level()
+		l.SetLevel(next)
+		l.Warn("Log level reloaded", zap.Stringer("from", before), zap.Stringer("to", next))
+	})
+	return l, nil
+}
+
+func NewLogger() (*Logger, error) {
+	return New(Config{Level: zap.InfoLevel})
 }
 
 func NewDevelopmentLogger() (*Logger, error) {
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "timestamp",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.FullCallerEncoder,
-		EncodeName:     zapcore.FullNameEncoder,
-	}
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zap.NewAtomicLevelAt(zap.DebugLevel),
-	)
-	return &Logger{Log: zap.New(core, zap.AddStacktrace(zap.ErrorLevel))}, nil
+	return New(Config{Level: zap.DebugLevel, Development: true})
+}
+
+// With returns a child logger that adds fields to every line, e.g. the order being processed
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	return &Logger{Log: l.Log.With(fields...), level: l.level, file: l.file}
 }
 
 func (l *Logger) Info(msg string, fields ...zap.Field)  { l.Log.Info(msg, fields...) }
@@ -100,7 +129,7 @@ func (l *Logger) GinZapLogger() gin.HandlerFunc {
 		start := time.Now()
 		c.Next()
 		latency := time.Since(start)
-		l.Log.Info("HTTP request",
+		l.WithContext(c.Request.Context()).Info("HTTP request",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.Int("status", c.Writer.Status()),
@@ -119,7 +148,8 @@ func NewGormLogger(base *zap.Logger) *GormZapLogger {
 	return &GormZapLogger{
 		zap: base.Sugar(),
 		config: gormlogger.Config{
-			SlowThreshold:             time.Second,
+			// slow statements are logged by the metrics plugin, see DB_SLOW_QUERY_MS
+			SlowThreshold:             0,
 			LogLevel:                  gormlogger.Error,
 			IgnoreRecordNotFoundError: true,
 			Colorful:                  false,
@@ -152,6 +182,15 @@ func (l *GormZapLogger) Error(ctx context.Context, msg string, data ...interface
 	}
 }
 
+// ParamsFilter keeps bound values, e.g. password hashes or emails, out of logged statements
+func (l *GormZapLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	redacted := make([]interface{}, len(params))
+	for i := range redacted {
+		redacted[i] = "[REDACTED]"
+	}
+	return sql, redacted
+}
+
 func (l *GormZapLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
 	elapsed := time.Since(begin)
 	if err != nil {
@@ -164,7 +203,7 @@ func (l *GormZapLogger) Trace(ctx context.Context, begin time.Time, fc func() (s
 		}
 		return
 	}
-	if elapsed > l.config.SlowThreshold && l.config.LogLevel >= gormlogger.Warn {
+	if l.config.SlowThreshold > 0 && elapsed > l.config.SlowThreshold && l.config.LogLevel >= gormlogger.Warn {
 		sql, rows := fc()
 		l.zap.Warnf("SLOW ≥ %s | %.3fms | rows:%d | %s", l.config.SlowThreshold, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 	}
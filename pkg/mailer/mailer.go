@@ -0,0 +1,100 @@
+package mailer
+
+//go:generate mockgen -source=mailer.go -destination=mocks/mailer.go -package=mocks
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	To       string
+	Subject  string
+	HTMLBody string
+}
+
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewMailerFromEnv uses SMTP when SMTP_HOST is set, otherwise mails are only logged.
+func NewMailerFromEnv(loggerInstance *logger.Logger) Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		loggerInstance.Warn("SMTP_HOST not set, emails will only be logged")
+		return NewLogMailer(loggerInstance)
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "no-reply@example.com"
+	}
+	return NewSMTPMailer(SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	})
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) Mailer {
+	return &SMTPMailer{config: cfg}
+}
+
+func (m *SMTPMailer) Send(msg *Message) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	headers := []string{
+		"From: " + m.config.From,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		`Content-Type: text/html; charset="utf-8"`,
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.HTMLBody
+	addr := m.config.Host + ":" + m.config.Port
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+type LogMailer struct {
+	Logger *logger.Logger
+}
+
+func NewLogMailer(l *logger.Logger) Mailer {
+	return &LogMailer{Logger: l}
+}
+
+func (m *LogMailer) Send(msg *Message) error {
+	m.Logger.Info("Email sent", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+	return nil
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+var (
+	dbQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_queries_total",
+		Help: "Database statements by operation and result (success, not_found, error).",
+	}, []string{"db", "operation", "result"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database statements by operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"db", "operation"})
+
+	dbRowsAffected = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_rows_affected",
+		Help:    "Rows returned or changed by database statements by operation.",
+		Buckets: []float64{0, 1, 5, 10, 50, 100, 500, 1000, 5000},
+	}, []string{"db", "operation"})
+
+	dbSlowQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "Database statements slower than the slow query threshold by operation.",
+	}, []string{"db", "operation"})
+)
+
+// GormPlugin records metrics for every statement a database runs, exports its connection
+// pool statistics as go_sql_* metrics, both labelled with the database name, and logs
+// statements slower than SlowThreshold. Logged statements keep their placeholders; bound
+// values are never logged.
+type GormPlugin struct {
+	name          string
+	SlowThreshold time.Duration
+	Logger        *logger.Logger
+}
+
+// NewGormPlugin returns the plugin for db.Use; a SlowThreshold of 0 turns off slow query logging
+func NewGormPlugin(name string, slowThreshold time.Duration, l *logger.Logger) *GormPlugin {
+	return &GormPlugin{name: name, SlowThreshold: slowThreshold, Logger: l}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics:" + p.name
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := prometheus.Register(collectors.NewDBStatsCollector(sqlDB, p.name)); err != nil {
+		return err
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:start_create", startQuery),
+		cb.Create().After("*").Register("metrics:end_create", p.endQuery("create")),
+		cb.Query().Before("*").Register("metrics:start_query", startQuery),
+		cb.Query().After("*").Register("metrics:end_query", p.endQuery("query")),
+		cb.Update().Before("*").Register("metrics:start_update", startQuery),
+		cb.Update().After("*").Register("metrics:end_update", p.endQuery("update")),
+		cb.Delete().Before("*").Register("metrics:start_delete", startQuery),
+		cb.Delete().After("*").Register("metrics:end_delete", p.endQuery("delete")),
+		cb.Row().Before("*").Register("metrics:start_row", startQuery),
+		cb.Row().After("*").Register("metrics:end_row", p.endQuery("row")),
+		cb.Raw().Before("*").Register("metrics:start_raw", startQuery),
+		cb.Raw().After("*").Register("metrics:end_raw", p.endQuery("raw")),
+	)
+}
+
+// InstrumentDB registers a GormPlugin named name on db
+func InstrumentDB(db *gorm.DB, name string, slowThreshold time.Duration, l *logger.Logger) error {
+	return db.Use(NewGormPlugin(name, slowThreshold, l))
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *GormPlugin) endQuery(operation string) func(*gorm.DB) {
+	duration := dbQueryDuration.WithLabelValues(p.name, operation)
+	rows := dbRowsAffected.WithLabelValues(p.name, operation)
+	slow := dbSlowQueries.WithLabelValues(p.name, operation)
+	return func(db *gorm.DB) {
+		result := "success"
+		switch {
+		case errors.Is(db.Error, gorm.ErrRecordNotFound):
+			result = "not_found"
+		case db.Error != nil:
+			result = "error"
+		}
+		dbQueries.WithLabelValues(p.name, operation, result).Inc()
+		if db.RowsAffected >= 0 {
+			rows.Observe(float64(db.RowsAffected))
+		}
+
+		start, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start.(time.Time))
+		duration.Observe(elapsed.Seconds())
+		if p.SlowThreshold > 0 && elapsed >= p.SlowThreshold {
+			slow.Inc()
+			p.Logger.WithContext(db.Statement.Context).Warn("Slow database query",
+				zap.String("db", p.name),
+				zap.String("operation", operation),
+				zap.String("table", db.Statement.Table),
+				zap.String("sql", db.Statement.SQL.String()),
+				zap.Int("params", len(db.Statement.Vars)),
+				zap.Int64("rows", db.RowsAffected),
+				zap.Duration("elapsed", elapsed),
+				zap.Duration("threshold", p.SlowThreshold),
+			)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package metrics holds the Prometheus instrumentation every service shares: RED metrics
+// for HTTP routes, GORM query metrics and the /metrics handler.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests no route matched, so random paths cannot grow the label set
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route and status code.",
+	}, []string{"service", "method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "method", "route"})
+
+	httpInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	}, []string{"service"})
+
+	httpPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_panics_total",
+		Help: "Panics recovered while serving HTTP requests, by route.",
+	}, []string{"service", "route"})
+)
+
+// Middleware records the rate, errors (by status) and duration of every request, labelled
+// with the route template rather than the raw path
+func Middleware(service string) gin.HandlerFunc {
+	inFlight := httpInFlight.WithLabelValues(service)
+	return func(c *gin.Context) {
+		start := time.Now()
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		httpRequests.WithLabelValues(service, method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(service, method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RecordPanic counts a panic recovered while serving route
+func RecordPanic(service, route string) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpPanics.WithLabelValues(service, route).Inc()
+}
+
+// Handler serves the default registry, which also carries the Go runtime and process collectors
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
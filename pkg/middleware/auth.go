@@ -1,70 +1,123 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/security"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func AuthJWTMiddleware() gin.HandlerFunc {
+// AuthJWTMiddleware accepts access tokens that security.VerifyToken accepts for cfg, i.e.
+// issued by cfg.Issuer for this service's cfg.Audience, and, behind Tenant, issued for the
+// request's tenant
+func AuthJWTMiddleware(cfg config.JWT) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := c.GetHeader("Authorization")
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token not provided"})
-			c.Abort()
+		if id, roles, ok := forwardedIdentity(c); ok {
+			setIdentity(c, id, roles)
+			c.Next()
 			return
 		}
 
-		accessSecret := os.Getenv("JWT_ACCESS_SECRET_KEY")
-		if accessSecret == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT_ACCESS_SECRET_KEY not configured"})
-			c.Abort()
+		claims, ok := verifyAccessToken(c, cfg)
+		if !ok {
 			return
 		}
+		id, _ := claims["id"].(float64)
+		setIdentity(c, id, claimRoles(claims))
 
-		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-		claims := jwt.MapClaims{}
-		_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-			return []byte(accessSecret), nil
-		})
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
-		}
+		c.Next()
+	}
+}
 
-		if exp, ok := claims["exp"].(float64); ok {
-			if int64(exp) < jwt.TimeFunc().Unix() {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-				c.Abort()
-				return
-			}
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
+// ForwardIdentity is the gateway side of AuthJWTMiddleware. It verifies the access token of
+// requests for a service that accepts audience and passes the caller on in UserIDHeader and
+// UserRolesHeader, next to internalToken so the service trusts them without parsing the token
+// again. Identity headers sent by the client are always removed. Requests without a token go
+// on anonymously and invalid tokens are rejected as AuthJWTMiddleware rejects them. Without
+// an audience, for services that take no tokens, or without internalToken, the token is left
+// to the service.
+func ForwardIdentity(cfg config.JWT, audience, internalToken string) gin.HandlerFunc {
+	cfg.Audience = audience
+	return func(c *gin.Context) {
+		h := c.Request.Header
+		h.Del(UserIDHeader)
+		h.Del(UserRolesHeader)
+		h.Del(InternalTokenHeader)
+		if audience == "" || h.Get("Authorization") == "" {
+			c.Next()
 			return
 		}
 
-		if t, ok := claims["type"].(string); ok {
-			if t != "access" {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Token type mismatch"})
-				c.Abort()
-				return
-			}
-		} else {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Missing token type"})
-			c.Abort()
+		claims, ok := verifyAccessToken(c, cfg)
+		if !ok {
 			return
 		}
-
-		// Set userId in context for downstream handlers
-		if id, ok := claims["id"].(float64); ok {
-			c.Set("userId", id)
+		if internalToken != "" {
+			id, _ := claims["id"].(float64)
+			h.Set(UserIDHeader, strconv.FormatInt(int64(id), 10))
+			h.Set(UserRolesHeader, strings.Join(claimRoles(claims), ","))
+			h.Set(InternalTokenHeader, internalToken)
 		}
 
 		c.Next()
 	}
 }
+
+// verifyAccessToken returns the claims of the request's bearer token, or aborts the request
+// and reports false
+func verifyAccessToken(c *gin.Context, cfg config.JWT) (jwt.MapClaims, bool) {
+	tokenString := c.GetHeader("Authorization")
+	if tokenString == "" {
+		AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeNotAuthenticated, "Token not provided")
+		return nil, false
+	}
+
+	if cfg.AccessSecret == "" {
+		AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeNotAuthenticated, "JWT_ACCESS_SECRET_KEY not configured")
+		return nil, false
+	}
+
+	claims, err := security.VerifyToken(cfg, strings.TrimPrefix(tokenString, "Bearer "), security.Access)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainErrors.ErrExpired):
+			AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeExpired, "Token expired")
+		case errors.Is(err, domainErrors.ErrNotAuthorized):
+			AbortWithError(c, http.StatusForbidden, domainErrors.CodeNotAuthorized, "Token type mismatch")
+		default:
+			AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeNotAuthenticated, "Invalid token")
+		}
+		return nil, false
+	}
+
+	// a user of one store cannot use their token in another
+	if requested := GetTenant(c); requested != "" && security.ClaimTenant(claims) != requested {
+		AbortWithError(c, http.StatusForbidden, domainErrors.CodeTenantMismatch, "Token belongs to another tenant")
+		return nil, false
+	}
+	return claims, true
+}
+
+// claimRoles reads the single "role" claim issued by the user service and, when present, a
+// "roles" list
+func claimRoles(claims jwt.MapClaims) []string {
+	var roles []string
+	if role, ok := claims["role"].(string); ok && role != "" {
+		roles = append(roles, role)
+	}
+	if list, ok := claims["roles"].([]any); ok {
+		for _, r := range list {
+			if role, ok := r.(string); ok && role != "" {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testInternalToken = "internal-token"
+
+var testJWT = config.JWT{AccessSecret: "test-access-secret", AccessTime: time.Hour, Issuer: "user",
+	AccessAudiences: []string{"order"}}
+
+func accessToken(t *testing.T, userID int, role string) string {
+	t.Helper()
+	token, err := security.NewJWTService(testJWT).GenerateJWTToken(userID, role, "", security.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token.Token
+}
+
+// forwarded is what a service behind ForwardIdentity receives
+type forwarded struct {
+	status                  int
+	userID, roles, internal string
+}
+
+func forward(t *testing.T, audience, internalToken string, header http.Header) forwarded {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var got forwarded
+	router.GET("/", ForwardIdentity(testJWT, audience, internalToken), func(c *gin.Context) {
+		got.userID = c.GetHeader(UserIDHeader)
+		got.roles = c.GetHeader(UserRolesHeader)
+		got.internal = c.GetHeader(InternalTokenHeader)
+		c.Status(http.StatusNoContent)
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header = header
+	router.ServeHTTP(w, req)
+	got.status = w.Code
+	return got
+}
+
+func TestForwardIdentity(t *testing.T) {
+	forged := func(h http.Header) http.Header {
+		h.Set(UserIDHeader, "1")
+		h.Set(UserRolesHeader, security.RoleAdmin)
+		h.Set(InternalTokenHeader, "guessed")
+		return h
+	}
+	tests := []struct {
+		name          string
+		audience      string
+		internalToken string
+		header        http.Header
+		want          forwarded
+	}{
+		{
+			name:          "forged headers without a token are stripped",
+			audience:      "order",
+			internalToken: testInternalToken,
+			header:        forged(headers()),
+			want:          forwarded{status: http.StatusNoContent},
+		},
+		{
+			name:          "forged headers next to a valid token are replaced",
+			audience:      "order",
+			internalToken: testInternalToken,
+			header:        forged(headers("Authorization", accessToken(t, 7, security.RoleCustomer))),
+			want:          forwarded{status: http.StatusNoContent, userID: "7", roles: security.RoleCustomer, internal: testInternalToken},
+		},
+		{
+			name:          "valid token propagates the identity",
+			audience:      "order",
+			internalToken: testInternalToken,
+			header:        headers("Authorization", accessToken(t, 9, security.RoleStaff)),
+			want:          forwarded{status: http.StatusNoContent, userID: "9", roles: security.RoleStaff, internal: testInternalToken},
+		},
+		{
+			name:     "without an internal token the token is left to the service",
+			audience: "order",
+			header:   forged(headers("Authorization", accessToken(t, 9, security.RoleStaff))),
+			want:     forwarded{status: http.StatusNoContent},
+		},
+		{
+			name:          "routes without an audience are not checked",
+			internalToken: testInternalToken,
+			header:        forged(headers("Authorization", "Bearer not-a-token")),
+			want:          forwarded{status: http.StatusNoContent},
+		},
+		{
+			name:          "invalid token is rejected",
+			audience:      "order",
+			internalToken: testInternalToken,
+			header:        forged(headers("Authorization", "Bearer not-a-token")),
+			want:          forwarded{status: http.StatusUnauthorized},
+		},
+		{
+			name:          "token for another audience is rejected",
+			audience:      "payment",
+			internalToken: testInternalToken,
+			header:        headers("Authorization", accessToken(t, 9, security.RoleStaff)),
+			want:          forwarded{status: http.StatusUnauthorized},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := forward(t, tt.audience, tt.internalToken, tt.header); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthJWTMiddlewareForwardedIdentity(t *testing.T) {
+	t.Setenv("INTERNAL_API_TOKEN", testInternalToken)
+	verify := testJWT
+	verify.Audience = "order"
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+		wantID     int
+		wantRoles  []string
+	}{
+		{
+			name:       "identity next to the internal token is trusted",
+			header:     headers(UserIDHeader, "7", UserRolesHeader, "staff, fulfillment", InternalTokenHeader, testInternalToken),
+			wantStatus: http.StatusNoContent,
+			wantID:     7,
+			wantRoles:  []string{security.RoleStaff, security.RoleFulfillment},
+		},
+		{
+			name:       "identity without roles is a customer",
+			header:     headers(UserIDHeader, "7", InternalTokenHeader, testInternalToken),
+			wantStatus: http.StatusNoContent,
+			wantID:     7,
+			wantRoles:  []string{security.RoleCustomer},
+		},
+		{
+			name:       "forged identity with a wrong internal token is ignored",
+			header:     headers(UserIDHeader, "1", UserRolesHeader, security.RoleAdmin, InternalTokenHeader, "guessed"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "forged identity without an internal token is ignored",
+			header:     headers(UserIDHeader, "1", UserRolesHeader, security.RoleAdmin),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "internal token without an identity falls back to the bearer token",
+			header:     headers(InternalTokenHeader, testInternalToken, "Authorization", accessToken(t, 5, security.RoleCustomer)),
+			wantStatus: http.StatusNoContent,
+			wantID:     5,
+			wantRoles:  []string{security.RoleCustomer},
+		},
+		{
+			name:       "no identity and no token is rejected",
+			header:     headers(InternalTokenHeader, testInternalToken),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "malformed user id is not trusted",
+			header:     headers(UserIDHeader, "seven", InternalTokenHeader, testInternalToken),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			var gotID int
+			var gotRoles []string
+			router.GET("/", AuthJWTMiddleware(verify), func(c *gin.Context) {
+				gotID, _ = UserID(c)
+				gotRoles = Roles(c)
+				c.Status(http.StatusNoContent)
+			})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.header
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotID != tt.wantID || !equalRoles(gotRoles, tt.wantRoles) {
+				t.Errorf("identity = %d %v, want %d %v", gotID, gotRoles, tt.wantID, tt.wantRoles)
+			}
+		})
+	}
+}
+
+// TestForwardIdentityRoundTrip checks that a service trusts what the gateway forwards and
+// nothing a client adds on the way
+func TestForwardIdentityRoundTrip(t *testing.T) {
+	t.Setenv("INTERNAL_API_TOKEN", testInternalToken)
+	verify := testJWT
+	verify.Audience = "order"
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var gotID int
+	router.GET("/", ForwardIdentity(testJWT, "order", testInternalToken), AuthJWTMiddleware(verify), func(c *gin.Context) {
+		gotID, _ = UserID(c)
+		c.Status(http.StatusNoContent)
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", accessToken(t, 11, security.RoleCustomer))
+	req.Header.Set(UserIDHeader, "1")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent || gotID != 11 {
+		t.Errorf("status %d, user %d; want %d, user 11", w.Code, gotID, http.StatusNoContent)
+	}
+}
+
+// headers builds a request header from name, value pairs, canonicalizing the names as a
+// client's would be
+func headers(kv ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return h
+}
+
+func equalRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
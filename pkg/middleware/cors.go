@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS answers preflight requests and sets the Access-Control headers for the configured
+// origins, following reloads of the CORS settings
+func CORS(cfg *config.Live[config.CORS]) gin.HandlerFunc {
+	var current atomic.Pointer[gin.HandlerFunc]
+	apply := func(cfg config.CORS) {
+		h := newCORS(cfg)
+		current.Store(&h)
+	}
+	apply(cfg.Get())
+	cfg.Subscribe(apply)
+	return func(c *gin.Context) {
+		(*current.Load())(c)
+	}
+}
+
+func newCORS(cfg config.CORS) gin.HandlerFunc {
+	c := cors.Config{
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "Cache-Control", RequestIDHeader, "Idempotency-Key", "X-CSRF-Token"},
+		ExposeHeaders: []string{"Content-Length", "Content-Disposition", RequestIDHeader,
+			RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, "Retry-After", "Idempotent-Replayed"},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if cfg.AllowsAllOrigins() {
+		c.AllowAllOrigins = true
+	} else {
+		c.AllowOrigins = cfg.AllowOrigins
+	}
+	return cors.New(c)
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testCSRF = config.CSRF{Enabled: true, CookieName: "csrf_token", HeaderName: "X-CSRF-Token",
+	CookieMaxAge: time.Hour, ExemptPaths: []string{"/v1/internal/"}}
+
+func csrfRequest(cfg config.CSRF, method, path string, header http.Header) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CSRF(cfg))
+	router.Handle(method, path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header = header
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCSRF(t *testing.T) {
+	cookie := "csrf_token=token-a"
+	disabled := testCSRF
+	disabled.Enabled = false
+	tests := []struct {
+		name       string
+		cfg        config.CSRF
+		method     string
+		path       string
+		header     http.Header
+		wantStatus int
+		wantCookie bool
+	}{
+		{
+			name:       "safe request without a cookie is issued one",
+			cfg:        testCSRF,
+			method:     http.MethodGet,
+			path:       "/v1/orders",
+			header:     headers(),
+			wantStatus: http.StatusNoContent,
+			wantCookie: true,
+		},
+		{
+			name:       "safe request with a cookie is left alone",
+			cfg:        testCSRF,
+			method:     http.MethodGet,
+			path:       "/v1/orders",
+			header:     headers("Cookie", cookie),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "unsafe request echoing the cookie passes",
+			cfg:        testCSRF,
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			header:     headers("Cookie", cookie, "X-CSRF-Token", "token-a"),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "unsafe request without a cookie is rejected and issued one",
+			cfg:        testCSRF,
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			header:     headers("X-CSRF-Token", "token-a"),
+			wantStatus: http.StatusForbidden,
+			wantCookie: true,
+		},
+		{
+			name:       "unsafe request without the header is rejected",
+			cfg:        testCSRF,
+			method:     http.MethodDelete,
+			path:       "/v1/orders",
+			header:     headers("Cookie", cookie),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unsafe request with another token is rejected",
+			cfg:        testCSRF,
+			method:     http.MethodPut,
+			path:       "/v1/orders",
+			header:     headers("Cookie", cookie, "X-CSRF-Token", "token-b"),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "exempt paths are not checked",
+			cfg:        testCSRF,
+			method:     http.MethodPost,
+			path:       "/v1/internal/orders",
+			header:     headers(),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "disabled middleware checks nothing",
+			cfg:        disabled,
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			header:     headers(),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := csrfRequest(tt.cfg, tt.method, tt.path, tt.header)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var issued *http.Cookie
+			for _, c := range w.Result().Cookies() {
+				if c.Name == tt.cfg.CookieName {
+					issued = c
+				}
+			}
+			if (issued != nil) != tt.wantCookie {
+				t.Fatalf("cookie issued = %v, want %v", issued != nil, tt.wantCookie)
+			}
+			if issued != nil && (issued.Value == "" || issued.HttpOnly) {
+				t.Errorf("cookie = %+v, want a token scripts can read", issued)
+			}
+		})
+	}
+}
+
+// TestCSRFIssuedTokenIsAccepted checks that echoing the issued cookie is all a client needs
+func TestCSRFIssuedTokenIsAccepted(t *testing.T) {
+	w := csrfRequest(testCSRF, http.MethodGet, "/", headers())
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	token := cookies[0].Value
+	w = csrfRequest(testCSRF, http.MethodPost, "/", headers("Cookie", "csrf_token="+token, "X-CSRF-Token", token))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"ecommerce-microservice-go/pkg/psql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadYourWrites makes requests that change data read from the primary database, so a handler
+// that writes and reads back, e.g. create then load, does not see a lagging replica. Reads of
+// GET requests still go to the replicas.
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(psql.ContextWithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
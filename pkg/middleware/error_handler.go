@@ -4,12 +4,19 @@ import (
 	"errors"
 	"net/http"
 
+	"ecommerce-microservice-go/pkg/controllers"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-func ErrorHandler() gin.HandlerFunc {
+// ErrorHandler renders the last error a handler recorded with ctx.Error as an ErrorResponse,
+// in an ErrorEnvelope on routers that use controllers.UseEnvelope.
+// Server errors are logged with their cause chain and stack and answered with a generic
+// message; client errors are logged at debug level.
+func ErrorHandler(l *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 		if len(c.Errors) > 0 {
@@ -17,10 +24,33 @@ func ErrorHandler() gin.HandlerFunc {
 			var appErr *domainErrors.AppError
 			if errors.As(err, &appErr) {
 				status, message := domainErrors.AppErrorToHTTP(appErr)
-				c.JSON(status, gin.H{"error": message})
+				logError(c, l, status, err)
+				c.JSON(status, controllers.ErrorBody(c, domainErrors.ErrorResponse{
+					Code:      appErr.ErrorCode(),
+					Message:   message,
+					Details:   appErr.Details,
+					RequestID: GetRequestID(c),
+				}))
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+				logError(c, l, http.StatusInternalServerError, err)
+				AbortWithError(c, http.StatusInternalServerError, domainErrors.CodeInternal, "Internal Server Error")
 			}
 		}
 	}
 }
+
+func logError(c *gin.Context, l *logger.Logger, status int, err error) {
+	log := l.WithContext(c.Request.Context())
+	fields := []zap.Field{zap.Int("status", status), zap.String("method", c.Request.Method), zap.String("route", c.FullPath()), domainErrors.Field(err)}
+	if status >= http.StatusInternalServerError {
+		log.Error("Request failed", fields...)
+	} else {
+		log.Debug("Request rejected", fields...)
+	}
+}
+
+// AbortWithError stops the chain and answers with an ErrorResponse, for middleware that
+// rejects a request before any handler runs
+func AbortWithError(c *gin.Context, status int, code domainErrors.ErrorCode, message string) {
+	c.AbortWithStatusJSON(status, controllers.ErrorBody(c, domainErrors.ErrorResponse{Code: code, Message: message, RequestID: GetRequestID(c)}))
+}
@@ -1,16 +1,55 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"strconv"
+	"strings"
 
-func CommonHeaders(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Credentials", "true")
-	c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, DELETE, GET, PUT")
-	c.Header("Access-Control-Allow-Headers",
-		"Content-Type, Depth, User-Agent, X-File-Size, X-Requested-With, If-Modified-Since, X-File-CompanyName, Cache-Control, Authorization")
-	c.Header("X-Frame-Options", "SAMEORIGIN")
-	c.Header("Cache-Control", "no-cache, no-store")
-	c.Header("Pragma", "no-cache")
-	c.Header("Expires", "0")
-	c.Next()
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// securityHeaderNames are the headers set by SecurityHeaders
+var securityHeaderNames = []string{
+	"Strict-Transport-Security", "X-Content-Type-Options", "X-Frame-Options", "Referrer-Policy",
+	"Content-Security-Policy", "Cache-Control", "Pragma", "Expires",
+}
+
+// SecurityHeaders sets HSTS, nosniff, framing, referrer and content security policies and
+// disables caching. Paths under /docs/ get the swagger UI policy instead of the API one.
+func SecurityHeaders(cfg config.SecurityHeaders) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", cfg.FrameOptions)
+		h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		if strings.Contains(c.Request.URL.Path, "/docs/") {
+			h.Set("Content-Security-Policy", cfg.DocsContentSecurityPolicy)
+		} else {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		h.Set("Cache-Control", "no-cache, no-store")
+		h.Set("Pragma", "no-cache")
+		h.Set("Expires", "0")
+		c.Next()
+	}
+}
+
+// DelSecurityHeaders removes the SecurityHeaders headers from h, e.g. from a proxied response
+// the gateway sets them on itself
+func DelSecurityHeaders(h http.Header) {
+	for _, name := range securityHeaderNames {
+		h.Del(name)
+	}
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalTokenHeader carries INTERNAL_API_TOKEN on service-to-service requests
+const InternalTokenHeader = "X-Internal-Token"
+
+// InternalTokenMiddleware guards service-to-service endpoints that are not routed by the gateway.
+func InternalTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if os.Getenv("INTERNAL_API_TOKEN") == "" {
+			AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeNotAuthenticated, "INTERNAL_API_TOKEN not configured")
+			return
+		}
+		if !validInternalToken(c) {
+			AbortWithError(c, http.StatusUnauthorized, domainErrors.CodeNotAuthenticated, "Invalid internal token")
+			return
+		}
+		c.Next()
+	}
+}
+
+func validInternalToken(c *gin.Context) bool {
+	expected := os.Getenv("INTERNAL_API_TOKEN")
+	token := c.GetHeader(InternalTokenHeader)
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"os"
+	"strconv"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// InternalTokenMetadataKey carries INTERNAL_API_TOKEN on internal gRPC calls
+const InternalTokenMetadataKey = "x-internal-token"
+
+// TenantMetadataKey carries the tenant on gRPC calls, as tenant.Header does over HTTP
+var TenantMetadataKey = strings.ToLower(tenant.Header)
+
+// UserIDMetadataKey and UserRolesMetadataKey carry the caller of a gRPC call made on behalf of
+// a user, as UserIDHeader and UserRolesHeader do over HTTP
+var (
+	UserIDMetadataKey    = strings.ToLower(UserIDHeader)
+	UserRolesMetadataKey = strings.ToLower(UserRolesHeader)
+)
+
+// GRPCIdentity is the user a gRPC call is made for
+type GRPCIdentity struct {
+	UserID int
+	Roles  []string
+}
+
+type grpcIdentityKey struct{}
+
+// InternalTokenUnaryInterceptor is the gRPC counterpart of InternalTokenMiddleware.
+func InternalTokenUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		expected := os.Getenv("INTERNAL_API_TOKEN")
+		if expected == "" {
+			return nil, status.Error(codes.Unauthenticated, "INTERNAL_API_TOKEN not configured")
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(InternalTokenMetadataKey)
+		if len(values) == 0 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(expected)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid internal token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// TenantUnaryInterceptor is the gRPC counterpart of Tenant for internal servers: a call with
+// TenantMetadataKey is scoped to that tenant, one without it acts across tenants.
+func TenantUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(TenantMetadataKey); len(values) > 0 {
+			if !tenant.Valid(values[0]) {
+				return nil, status.Error(codes.InvalidArgument, "invalid "+TenantMetadataKey)
+			}
+			ctx = logger.ContextWithFields(tenant.ContextWithTenant(ctx, values[0]), zap.String("tenant", values[0]))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// IdentityUnaryInterceptor is the gRPC counterpart of AuthJWTMiddleware trusting the identity
+// forwarded by the gateway: a call with UserIDMetadataKey is made for that user, with the roles
+// of UserRolesMetadataKey, and one without it by the system. It must run after
+// InternalTokenUnaryInterceptor, which is what makes the metadata trustworthy.
+func IdentityUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ids := md.Get(UserIDMetadataKey)
+		if len(ids) == 0 {
+			return handler(ctx, req)
+		}
+		id, err := strconv.Atoi(ids[0])
+		if err != nil || id <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid "+UserIDMetadataKey)
+		}
+		identity := GRPCIdentity{UserID: id}
+		for _, values := range md.Get(UserRolesMetadataKey) {
+			for _, r := range strings.Split(values, ",") {
+				if r = strings.TrimSpace(r); r != "" {
+					identity.Roles = append(identity.Roles, r)
+				}
+			}
+		}
+		if len(identity.Roles) == 0 {
+			identity.Roles = []string{security.RoleCustomer}
+		}
+		ctx = context.WithValue(ctx, grpcIdentityKey{}, identity)
+		ctx = audit.ContextWithActor(ctx, audit.Actor{ID: id, Roles: identity.Roles})
+		ctx = psql.ContextWithActor(ctx, id)
+		return handler(logger.ContextWithFields(ctx, zap.Int("user_id", id)), req)
+	}
+}
+
+// IdentityFromGRPC returns the user IdentityUnaryInterceptor read for the call, false for
+// calls made by the system
+func IdentityFromGRPC(ctx context.Context) (GRPCIdentity, bool) {
+	identity, ok := ctx.Value(grpcIdentityKey{}).(GRPCIdentity)
+	return identity, ok
+}
+
+// InternalTokenClientInterceptor attaches INTERNAL_API_TOKEN to outgoing calls of internal clients.
+func InternalTokenClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, InternalTokenMetadataKey, os.Getenv("INTERNAL_API_TOKEN"))
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/cache/mocks"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+func rateLimitRouter(store cache.Store, limits config.RateLimit, key RateLimitKeyFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(store, "test", config.Fixed(limits), key, testLogger))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/a", ok)
+	router.GET("/b", ok)
+	return router
+}
+
+func limitedRequest(router *gin.Engine, path string, kv ...string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header = headers(kv...)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	limits := config.RateLimit{Requests: 2, Window: time.Hour, Burst: 2}
+	router := rateLimitRouter(cache.NewMemoryStore(), limits, KeyByIP)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := limitedRequest(router, "/a")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want %d", i+1, w.Code, http.StatusNoContent)
+		}
+		if got := w.Header().Get(RateLimitLimitHeader); got != "2" {
+			t.Errorf("request %d: %s = %q, want 2", i+1, RateLimitLimitHeader, got)
+		}
+		if got := w.Header().Get(RateLimitRemainingHeader); got != wantRemaining {
+			t.Errorf("request %d: %s = %q, want %s", i+1, RateLimitRemainingHeader, got, wantRemaining)
+		}
+	}
+
+	w := limitedRequest(router, "/a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	// one token comes back every half hour
+	if got := w.Header().Get("Retry-After"); got != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", got)
+	}
+	if got := w.Header().Get(RateLimitResetHeader); got != "3600" {
+		t.Errorf("%s = %q, want 3600", RateLimitResetHeader, got)
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	limits := config.RateLimit{Requests: 1, Window: time.Hour, Burst: 1}
+	tests := []struct {
+		name       string
+		key        RateLimitKeyFunc
+		first      []string
+		second     []string
+		path       string
+		wantStatus int
+	}{
+		{
+			name:       "same address shares a bucket",
+			key:        KeyByIP,
+			path:       "/a",
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "other api key has its own bucket",
+			key:        KeyByAPIKey("X-API-Key"),
+			first:      []string{"X-API-Key", "one"},
+			second:     []string{"X-API-Key", "two"},
+			path:       "/a",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "same api key shares a bucket",
+			key:        KeyByAPIKey("X-API-Key"),
+			first:      []string{"X-API-Key", "one"},
+			second:     []string{"X-API-Key", "one"},
+			path:       "/a",
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "other bearer token has its own bucket",
+			key:        KeyByToken,
+			first:      []string{"Authorization", "Bearer one"},
+			second:     []string{"Authorization", "Bearer two"},
+			path:       "/a",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "anonymous user falls back to the address",
+			key:        KeyByUser,
+			path:       "/a",
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "other route has its own bucket",
+			key:        KeyByRoute(KeyByIP),
+			path:       "/b",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "same route shares a bucket",
+			key:        KeyByRoute(KeyByIP),
+			path:       "/a",
+			wantStatus: http.StatusTooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := rateLimitRouter(cache.NewMemoryStore(), limits, tt.key)
+			if w := limitedRequest(router, "/a", tt.first...); w.Code != http.StatusNoContent {
+				t.Fatalf("first request: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if w := limitedRequest(router, tt.path, tt.second...); w.Code != tt.wantStatus {
+				t.Errorf("second request: status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRateLimitLetsRequestsThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits config.RateLimit
+		store  func(ctrl *gomock.Controller) cache.Store
+	}{
+		{
+			name:   "disabled limit does not touch the store",
+			limits: config.RateLimit{},
+			store:  func(ctrl *gomock.Controller) cache.Store { return mocks.NewMockStore(ctrl) },
+		},
+		{
+			name:   "store failure is not the client's problem",
+			limits: config.RateLimit{Requests: 1, Window: time.Second, Burst: 1},
+			store: func(ctrl *gomock.Controller) cache.Store {
+				store := mocks.NewMockStore(ctrl)
+				store.EXPECT().TakeToken(gomock.Any(), "ratelimit:test:ip:192.0.2.1", 1.0, 1).
+					Return(cache.TokenResult{}, errors.New("connection refused"))
+				return store
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			router := rateLimitRouter(tt.store(ctrl), tt.limits, KeyByIP)
+			w := limitedRequest(router, "/a")
+			if w.Code != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if got := w.Header().Get(RateLimitLimitHeader); got != "" {
+				t.Errorf("%s = %q, want none", RateLimitLimitHeader, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Recovery turns a panic in a later handler into a 500 ErrorResponse carrying a new error ID.
+// The panic is logged with its stack, the request and the same ID, and counted in
+// http_panics_total. It goes after RequestID so the log and response carry the request ID.
+func Recovery(service string, l *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			errorID := uuid.NewString()
+			log := RequestLogger(c, l).With(
+				zap.String("error_id", errorID),
+				zap.String("panic", fmt.Sprint(rec)),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.String("client_ip", c.ClientIP()),
+			)
+			if brokenConnection(rec) {
+				// the client is gone, so there is no one to answer
+				log.Warn("Connection closed while serving request")
+				c.Abort()
+				return
+			}
+			metrics.RecordPanic(service, c.FullPath())
+			log.Error("Panic recovered", zap.ByteString("stack", debug.Stack()))
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, controllers.ErrorBody(c, domainErrors.ErrorResponse{
+				Code:      domainErrors.CodeInternal,
+				Message:   "Internal Server Error",
+				RequestID: GetRequestID(c),
+				ErrorID:   errorID,
+			}))
+		}()
+		c.Next()
+	}
+}
+
+// brokenConnection reports whether the panic came from writing to a client that hung up
+func brokenConnection(rec any) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the gin context key holding the request ID
+	RequestIDKey = "requestID"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID keeps the caller's X-Request-ID, or generates one, and makes it available to
+// handlers, the response, proxied requests and loggers derived with logger.WithContext.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+			c.Request.Header.Set(RequestIDHeader, id)
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID RequestID stored for this request
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// RequestLogger returns l bound to the current request's ID
+func RequestLogger(c *gin.Context, l *logger.Logger) *logger.Logger {
+	return l.WithContext(c.Request.Context())
+}
+
+// RequestIDTransport wraps base, or http.DefaultTransport when nil, so calls to other services
+// carry the request ID of their context and log under the same ID
+func RequestIDTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return requestIDRoundTripper{base: base}
+}
+
+type requestIDRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (t requestIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := logger.RequestIDFromContext(req.Context())
+	if id == "" || req.Header.Get(RequestIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// a RoundTripper must not change the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(RequestIDHeader, id)
+	return t.base.RoundTrip(req)
+}
+
+// validRequestID accepts IDs of printable ASCII without spaces, so a client cannot inject
+// log fields or header values
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TenantKey is the gin context key holding the tenant of the request
+const TenantKey = "tenant"
+
+// Tenant scopes the request to the tenant in tenant.Header, which the gateway sets. Requests
+// without the header belong to tenant.Default, except service-to-service calls with a valid
+// internal token, which act across tenants. An ID that is not a valid tenant is rejected.
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(tenant.Header)
+		switch {
+		case id == "" && validInternalToken(c):
+			c.Next()
+			return
+		case id == "":
+			id = tenant.Default
+		case !tenant.Valid(id):
+			AbortWithError(c, http.StatusBadRequest, domainErrors.CodeValidation, "Invalid "+tenant.Header)
+			return
+		}
+		c.Set(TenantKey, id)
+		ctx := tenant.ContextWithTenant(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(logger.ContextWithFields(ctx, zap.String("tenant", id)))
+		c.Next()
+	}
+}
+
+// GetTenant returns the tenant Tenant stored for this request, "" for calls across tenants
+func GetTenant(c *gin.Context) string {
+	return c.GetString(TenantKey)
+}
@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"errors"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName = "ecommerce-microservice-go/pkg/otel"
+	spanKey    = "otel:span"
+)
+
+// InstrumentDB records a client span for every statement db runs, as a child of the span
+// in the statement's context (set with WithContext)
+func (t *Telemetry) InstrumentDB(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("otel:start_create", startSpan("create")),
+		cb.Create().After("*").Register("otel:end_create", endSpan),
+		cb.Query().Before("*").Register("otel:start_query", startSpan("select")),
+		cb.Query().After("*").Register("otel:end_query", endSpan),
+		cb.Update().Before("*").Register("otel:start_update", startSpan("update")),
+		cb.Update().After("*").Register("otel:end_update", endSpan),
+		cb.Delete().Before("*").Register("otel:start_delete", startSpan("delete")),
+		cb.Delete().After("*").Register("otel:end_delete", endSpan),
+		cb.Row().Before("*").Register("otel:start_row", startSpan("row")),
+		cb.Row().After("*").Register("otel:end_row", endSpan),
+		cb.Raw().Before("*").Register("otel:start_raw", startSpan("raw")),
+		cb.Raw().After("*").Register("otel:end_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			// statements outside a traced request would only produce orphan spans
+			return
+		}
+		_, span := otelapi.Tracer(tracerName).Start(ctx, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(operation)))
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := v.(trace.Span)
+	defer span.End()
+	if table := db.Statement.Table; table != "" {
+		span.SetAttributes(semconv.DBCollectionName(table))
+	}
+	span.SetAttributes(semconv.DBQueryText(db.Statement.SQL.String()), attribute.Int64("db.rows_affected", db.RowsAffected))
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,143 @@
+// Package otel sets up OpenTelemetry tracing and metrics for a service and instruments
+// gin, GORM and outgoing HTTP calls. Without an OTLP endpoint everything stays a no-op
+// except trace context propagation, so traces started upstream still pass through.
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/zap"
+)
+
+// Config enables export when Endpoint is set. The exporters also honour the standard
+// OTEL_EXPORTER_OTLP_* variables, e.g. OTEL_EXPORTER_OTLP_HEADERS for collector auth.
+type Config struct {
+	ServiceName    string
+	Environment    string
+	Endpoint       string
+	Insecure       bool
+	SampleRatio    float64
+	MetricInterval time.Duration
+}
+
+func LoadConfig(src *config.Source, service, env string) Config {
+	cfg := Config{
+		ServiceName:    src.String("OTEL_SERVICE_NAME", service),
+		Environment:    env,
+		Endpoint:       src.String("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		Insecure:       src.Bool("OTEL_EXPORTER_OTLP_INSECURE", env != config.EnvProduction),
+		SampleRatio:    src.Float("OTEL_TRACES_SAMPLER_ARG", 1),
+		MetricInterval: src.Duration("OTEL_METRIC_EXPORT_INTERVAL_SECONDS", 60, time.Second),
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		cfg.SampleRatio = 1
+	}
+	return cfg
+}
+
+// Telemetry owns the providers Setup installed
+type Telemetry struct {
+	service        string
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	Logger         *logger.Logger
+}
+
+// Setup installs the global tracer and meter providers and the W3C trace context propagator
+func Setup(ctx context.Context, cfg Config, l *logger.Logger) (*Telemetry, error) {
+	otelapi.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t := &Telemetry{service: cfg.ServiceName, Logger: l}
+	if cfg.Endpoint == "" {
+		l.Info("OTEL_EXPORTER_OTLP_ENDPOINT not set, traces are not exported")
+		return t, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName), semconv.DeploymentEnvironmentName(cfg.Environment)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	traceOpts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(cfg.Endpoint)}
+	metricOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpointURL(cfg.Endpoint)}
+	if cfg.Insecure {
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+	}
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, err
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	t.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	t.meterProvider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(cfg.MetricInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otelapi.SetTracerProvider(t.tracerProvider)
+	otelapi.SetMeterProvider(t.meterProvider)
+	otelapi.SetErrorHandler(otelapi.ErrorHandlerFunc(func(err error) {
+		l.Warn("OpenTelemetry export failed", zap.Error(err))
+	}))
+	l.Info("OpenTelemetry enabled", zap.String("endpoint", cfg.Endpoint), zap.Float64("sampleRatio", cfg.SampleRatio))
+	return t, nil
+}
+
+// Shutdown flushes pending spans and metrics
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	var errs []error
+	if t.tracerProvider != nil {
+		errs = append(errs, t.tracerProvider.Shutdown(ctx))
+	}
+	if t.meterProvider != nil {
+		errs = append(errs, t.meterProvider.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Middleware starts a server span per request, continuing the caller's trace. Health and
+// metrics scrapes are left out.
+func (t *Telemetry) Middleware() gin.HandlerFunc {
+	return otelgin.Middleware(t.service, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		path := c.FullPath()
+		return !strings.HasPrefix(path, "/v1/health") && path != "/metrics"
+	}))
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so outgoing requests get a
+// client span and carry the trace context
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
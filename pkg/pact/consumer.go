@@ -0,0 +1,119 @@
+package pact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+// Check exercises the consumer's client against baseURL and fails when the client does not
+// handle the interaction's response as expected
+type Check func(ctx context.Context, baseURL string) error
+
+// Consume runs the check of every interaction against a server answering only that
+// interaction, and fails an interaction whose request the check did not send. Every interaction
+// needs a check, so a pact cannot promise more than the consumer's client was tried with.
+func Consume(ctx context.Context, p Pact, checks map[string]Check) error {
+	var errs []error
+	for _, desc := range slices.Sorted(maps.Keys(checks)) {
+		if !slices.ContainsFunc(p.Interactions, func(i Interaction) bool { return i.Description == desc }) {
+			errs = append(errs, fmt.Errorf("%s: check %q has no interaction", p.FileName(), desc))
+		}
+	}
+	for _, i := range p.Interactions {
+		check, ok := checks[i.Description]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %q has no check", p.FileName(), i.Description))
+			continue
+		}
+		if err := consume(ctx, i, check); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", p.FileName(), i.Description, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func consume(ctx context.Context, i Interaction, check Check) error {
+	body, err := normalize(i.Response.Body)
+	if err != nil {
+		return err
+	}
+	var (
+		mu       sync.Mutex
+		received int
+		problems []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if p := requestProblems(i.Request, r); len(p) > 0 {
+			problems = append(problems, p...)
+			http.Error(w, "request does not match the pact", http.StatusInternalServerError)
+			return
+		}
+		received++
+		if body != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+		w.WriteHeader(i.Response.Status)
+		if body != nil {
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	}))
+	defer server.Close()
+
+	checkErr := check(ctx, server.URL)
+	mu.Lock()
+	defer mu.Unlock()
+	var errs []error
+	for _, p := range problems {
+		errs = append(errs, errors.New(p))
+	}
+	if received == 0 && len(problems) == 0 {
+		errs = append(errs, errors.New("the client did not send the request"))
+	}
+	if checkErr != nil {
+		errs = append(errs, checkErr)
+	}
+	return errors.Join(errs...)
+}
+
+// requestProblems compares a request of the consumer with the one in the pact
+func requestProblems(want Request, r *http.Request) []string {
+	var problems []string
+	if r.Method != want.Method || r.URL.Path != want.Path {
+		problems = append(problems, fmt.Sprintf("got %s %s, want %s %s", r.Method, r.URL.Path, want.Method, want.Path))
+	}
+	wantQuery, _ := url.ParseQuery(want.Query)
+	if !maps.EqualFunc(r.URL.Query(), wantQuery, slices.Equal) {
+		problems = append(problems, fmt.Sprintf("got query %q, want %q", r.URL.RawQuery, want.Query))
+	}
+	for name, value := range want.Headers {
+		if got := r.Header.Get(name); got != value {
+			problems = append(problems, fmt.Sprintf("got header %s %q, want %q", name, got, value))
+		}
+	}
+	if want.Body != nil {
+		expected, err := normalize(want.Body)
+		if err != nil {
+			return append(problems, err.Error())
+		}
+		data, _ := io.ReadAll(r.Body)
+		var actual any
+		if err := json.Unmarshal(data, &actual); err != nil {
+			return append(problems, "request body is not JSON: "+err.Error())
+		}
+		for _, p := range Match(expected, actual) {
+			problems = append(problems, "request "+p)
+		}
+	}
+	return problems
+}
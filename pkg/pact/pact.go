@@ -0,0 +1,219 @@
+// Package pact checks the HTTP APIs services call on each other with consumer-driven contracts.
+// A consumer, such as the order service calling the user service, lists the requests it sends
+// and the parts of the responses it reads as a Pact. It checks its client against a server
+// answering from the pact (Consume) and commits the pact under pacts/ (Publish); the provider
+// replays every interaction against its own router (VerifyProvider), so changing an API a
+// consumer relies on fails the provider's verification instead of a call in production. Both
+// sides run in the services' TestPacts, so "go test" checks them and no binary carries them.
+package pact
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// InternalToken is the X-Internal-Token of interactions with internal routes; providers accept
+// it while verifying
+const InternalToken = "pact-internal-token"
+
+// Files are the committed pacts, one file per consumer and provider
+//
+//go:embed pacts/*.json
+var Files embed.FS
+
+// Pact is what a consumer expects of a provider
+type Pact struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response the consumer relies on. State names the data the
+// provider must hold for the response, e.g. "product 7 is in stock".
+type Interaction struct {
+	Description string   `json:"description"`
+	State       string   `json:"providerState,omitempty"`
+	Request     Request  `json:"request"`
+	Response    Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query is the raw query string, e.g. "sku=TEE-01&limit=1"
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    any               `json:"body,omitempty"`
+}
+
+// Response holds the status and the part of the body the consumer reads. Body values are
+// examples: the provider's response must have every field with the same JSON type, and may
+// have more.
+type Response struct {
+	Status int `json:"status"`
+	Body   any `json:"body,omitempty"`
+}
+
+// FileName is the name of the pact under pacts/, e.g. "order-user.json"
+func (p Pact) FileName() string {
+	return p.Consumer + "-" + p.Provider + ".json"
+}
+
+// Marshal formats the pact the way committed pacts are stored
+func (p Pact) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write stores the pact in dir
+func (p Pact) Write(dir string) error {
+	data, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, p.FileName()), data, 0o644)
+}
+
+// ErrOutOfDate is reported for a pact that differs from its committed file
+var ErrOutOfDate = errors.New("committed pact is out of date, run make pacts")
+
+// CheckCommitted reports whether the committed file of the pact matches it
+func (p Pact) CheckCommitted() error {
+	want, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	have, err := fs.ReadFile(Files, "pacts/"+p.FileName())
+	if err != nil || !bytes.Equal(want, have) {
+		return fmt.Errorf("%s: %w", p.FileName(), ErrOutOfDate)
+	}
+	return nil
+}
+
+// Publish compares the consumer's pacts with the committed files or, with dir, writes them
+// there. The consumer's tests call it once its clients passed Consume.
+func Publish(pacts []Pact, dir string) error {
+	var errs []error
+	for _, p := range pacts {
+		if dir != "" {
+			errs = append(errs, p.Write(dir))
+			continue
+		}
+		errs = append(errs, p.CheckCommitted())
+	}
+	return errors.Join(errs...)
+}
+
+// ForProvider returns the committed pacts of every consumer of provider
+func ForProvider(provider string) ([]Pact, error) {
+	names, err := fs.Glob(Files, "pacts/*-"+provider+".json")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	var pacts []Pact
+	for _, name := range names {
+		data, err := fs.ReadFile(Files, name)
+		if err != nil {
+			return nil, err
+		}
+		var p Pact
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if p.Provider == provider {
+			pacts = append(pacts, p)
+		}
+	}
+	return pacts, nil
+}
+
+// normalize turns a Go value into the form encoding/json decodes into any, so bodies written
+// in code compare like bodies read from a file
+func normalize(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// Match lists where actual lacks a field of expected or has it with another JSON type. Every
+// element of an actual array is matched against the first expected element.
+func Match(expected, actual any) []string {
+	var problems []string
+	match("body", expected, actual, &problems)
+	return problems
+}
+
+func match(path string, expected, actual any, problems *[]string) {
+	if kind(expected) != kind(actual) {
+		*problems = append(*problems, fmt.Sprintf("%s is %s, want %s", path, kind(actual), kind(expected)))
+		return
+	}
+	switch e := expected.(type) {
+	case map[string]any:
+		a := actual.(map[string]any)
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			v, ok := a[k]
+			if !ok {
+				*problems = append(*problems, path+"."+k+" is missing")
+				continue
+			}
+			match(path+"."+k, e[k], v, problems)
+		}
+	case []any:
+		if len(e) == 0 {
+			return
+		}
+		for i, v := range actual.([]any) {
+			match(fmt.Sprintf("%s[%d]", path, i), e[0], v, problems)
+		}
+	}
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", v), "*")
+	}
+}
@@ -0,0 +1,165 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Provider builds the handler serving one interaction, with its dependencies in the
+// interaction's provider state, typically use case mocks with the calls the state implies.
+// A state it does not know is an error.
+type Provider func(t *T, state string) (http.Handler, error)
+
+// T collects the failures of one interaction. It is a gomock.TestReporter, so mocks created
+// with it report unexpected calls and, through Cleanup, calls that never happened.
+type T struct {
+	failures []string
+	cleanups []func()
+}
+
+// fatal stops the handler of an interaction after a Fatalf, like t.FailNow in a test
+type fatal struct{}
+
+func (t *T) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *T) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	panic(fatal{})
+}
+
+func (t *T) Helper() {}
+
+// Cleanup runs f once the interaction was replayed
+func (t *T) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+// run calls f, turning a Fatalf into a recorded failure
+func (t *T) run(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(fatal); !ok {
+				panic(r)
+			}
+		}
+	}()
+	f()
+}
+
+// VerifyProvider replays every committed pact naming provider against the handlers built by
+// build. Interactions with internal routes send InternalToken, so the test sets
+// INTERNAL_API_TOKEN to it first.
+func VerifyProvider(provider string, build Provider) error {
+	pacts, err := ForProvider(provider)
+	if err != nil {
+		return err
+	}
+	if len(pacts) == 0 {
+		return fmt.Errorf("no pacts name %s as their provider", provider)
+	}
+	var errs []error
+	for _, p := range pacts {
+		errs = append(errs, Verify(p, build))
+	}
+	return errors.Join(errs...)
+}
+
+// Verify replays every interaction of the pact against the provider and fails those whose
+// status differs or whose body lacks what the consumer reads
+func Verify(p Pact, provider Provider) error {
+	var errs []error
+	for _, i := range p.Interactions {
+		if err := verify(i, provider); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", p.FileName(), i.Description, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func verify(i Interaction, provider Provider) error {
+	t := &T{}
+	handler, err := provider(t, i.State)
+	if err != nil {
+		return fmt.Errorf("provider state %q: %w", i.State, err)
+	}
+	var replayErr error
+	t.run(func() { replayErr = Replay(handler, i) })
+	for _, f := range t.cleanups {
+		t.run(f)
+	}
+	problems := t.failures
+	if replayErr != nil {
+		problems = append(problems, replayErr.Error())
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Replay sends the interaction's request to handler and fails when the status differs or the
+// body lacks what the consumer reads
+func Replay(handler http.Handler, i Interaction) error {
+	req, err := i.Request.httpRequest()
+	if err != nil {
+		return err
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(recorder{rec}, req)
+	if rec.Code != i.Response.Status {
+		return fmt.Errorf("status is %d, want %d: %s", rec.Code, i.Response.Status, strings.TrimSpace(rec.Body.String()))
+	}
+	if i.Response.Body == nil {
+		return nil
+	}
+	expected, err := normalize(i.Response.Body)
+	if err != nil {
+		return err
+	}
+	var actual any
+	if err := json.Unmarshal(rec.Body.Bytes(), &actual); err != nil {
+		return fmt.Errorf("response body is not JSON: %w", err)
+	}
+	if problems := Match(expected, actual); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// recorder is a response recorder httputil.ReverseProxy accepts, for replaying through the gateway
+type recorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (recorder) CloseNotify() <-chan bool { return nil }
+
+func (r Request) httpRequest() (*http.Request, error) {
+	var body io.Reader
+	if r.Body != nil {
+		data, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	target := r.Path
+	if r.Query != "" {
+		target += "?" + r.Query
+	}
+	req := httptest.NewRequest(r.Method, target, body)
+	if r.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
@@ -0,0 +1,188 @@
+// Package pagination parses page, limit, sort and filter query parameters against a
+// per-listing Spec, or domain.DataFilters searches against a SearchSpec, applies them to GORM
+// queries and builds the {data, meta} envelope.
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Spec is what a listing accepts. Sortable and Filterable map the query names clients use to
+// database columns; only those names are accepted, so user input never reaches SQL unchecked.
+type Spec struct {
+	Sortable   map[string]string
+	Filterable map[string]string
+	// DefaultSort is used without a sort parameter, e.g. "-createdAt"
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// SortField orders by a column, descending when Desc is set
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Params is a validated listing request
+type Params struct {
+	Page  int
+	Limit int
+	Sort  []SortField
+	// Filters maps columns to the value they must equal
+	Filters map[string]string
+}
+
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Parse reads ?page=2&limit=20&sort=-createdAt,name plus the Filterable parameters from the
+// request. Invalid values yield a ValidationError listing every bad parameter.
+func Parse(c *gin.Context, spec Spec) (Params, error) {
+	return ParseQuery(c.Request.URL.Query(), spec)
+}
+
+func ParseQuery(query url.Values, spec Spec) (Params, error) {
+	defaultLimit, maxLimit := spec.DefaultLimit, spec.MaxLimit
+	if defaultLimit <= 0 {
+		defaultLimit = DefaultLimit
+	}
+	if maxLimit <= 0 {
+		maxLimit = MaxLimit
+	}
+	p := Params{Page: 1, Limit: defaultLimit, Filters: map[string]string{}}
+	var details []domainErrors.FieldError
+
+	if v := query.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			details = append(details, domainErrors.FieldError{Field: "page", Rule: "min", Message: "page must be a whole number of at least 1"})
+		} else {
+			p.Page = page
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			details = append(details, domainErrors.FieldError{Field: "limit", Rule: "range", Message: fmt.Sprintf("limit must be between 1 and %d", maxLimit)})
+		} else {
+			p.Limit = limit
+		}
+	}
+
+	sortParam := query.Get("sort")
+	if sortParam == "" {
+		sortParam = spec.DefaultSort
+	}
+	for _, name := range strings.Split(sortParam, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		desc := strings.HasPrefix(name, "-")
+		column, ok := spec.Sortable[strings.TrimPrefix(name, "-")]
+		if !ok {
+			details = append(details, domainErrors.FieldError{Field: "sort", Rule: "oneof", Message: fmt.Sprintf("cannot sort by %s; allowed: %s", strings.TrimPrefix(name, "-"), names(spec.Sortable))})
+			continue
+		}
+		p.Sort = append(p.Sort, SortField{Column: column, Desc: desc})
+	}
+
+	for name, column := range spec.Filterable {
+		if v := query.Get(name); v != "" {
+			p.Filters[column] = v
+		}
+	}
+
+	if len(details) > 0 {
+		return Params{}, domainErrors.NewValidationError(errors.New("invalid pagination parameters"), details...)
+	}
+	return p, nil
+}
+
+func names(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// Filter is a GORM scope applying the equality filters
+func (p Params) Filter() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for column, value := range p.Filters {
+			db = db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+		}
+		return db
+	}
+}
+
+// Paginate is a GORM scope applying the sort order, offset and limit
+func (p Params) Paginate() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, s := range p.Sort {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+		}
+		return db.Offset(p.Offset()).Limit(p.Limit)
+	}
+}
+
+// Find counts the rows matching query and the filters, then loads the requested page into
+// dest. query should carry the model, conditions and preloads but no order or limit.
+func Find[T any](query *gorm.DB, p Params, dest *[]T) (int64, error) {
+	var total int64
+	count := query.Session(&gorm.Session{}).Scopes(p.Filter())
+	// preloads only make sense for the rows, not for a count
+	count.Statement.Preloads = nil
+	if err := count.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if err := query.Scopes(p.Filter(), p.Paginate()).Find(dest).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Meta describes the page returned in a Response
+type Meta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"totalPages"`
+}
+
+// Response is the envelope of every paginated listing
+type Response[T any] struct {
+	Data []T  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+func NewResponse[T any](data []T, total int64, p Params) Response[T] {
+	if data == nil {
+		data = []T{}
+	}
+	limit := int64(p.Limit)
+	return Response[T]{
+		Data: data,
+		Meta: Meta{Page: p.Page, Limit: p.Limit, Total: total, TotalPages: (total + limit - 1) / limit},
+	}
+}
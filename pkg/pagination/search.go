@@ -0,0 +1,190 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/domain"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchSpec is what a search accepts: the listing's Spec, whose Filterable fields take the
+// exact values of matches, plus the fields matched with LIKE and those dateRanges may bound.
+// Like Spec, every map goes from the names clients use to database columns.
+type SearchSpec struct {
+	Spec
+	Searchable map[string]string
+	Dates      map[string]string
+}
+
+// DateRange bounds a column; a nil Start or End leaves that side open
+type DateRange struct {
+	Column string
+	Start  *time.Time
+	End    *time.Time
+}
+
+// Search is a validated domain.DataFilters request
+type Search struct {
+	Params
+	// Like maps columns to the substrings they must contain, any of them
+	Like map[string][]string
+	// Matches maps columns to the values they must equal, any of them
+	Matches    map[string][]string
+	DateRanges []DateRange
+}
+
+// ParseSearch checks the filters against spec. Invalid values yield a ValidationError
+// listing every bad field.
+func ParseSearch(f domain.DataFilters, spec SearchSpec) (Search, error) {
+	defaultLimit, maxLimit := spec.DefaultLimit, spec.MaxLimit
+	if defaultLimit <= 0 {
+		defaultLimit = DefaultLimit
+	}
+	if maxLimit <= 0 {
+		maxLimit = MaxLimit
+	}
+	s := Search{
+		Params:  Params{Page: 1, Limit: defaultLimit, Filters: map[string]string{}},
+		Like:    map[string][]string{},
+		Matches: map[string][]string{},
+	}
+	var details []domainErrors.FieldError
+
+	if f.Page < 0 {
+		details = append(details, domainErrors.FieldError{Field: "page", Rule: "min", Message: "page must be at least 1"})
+	} else if f.Page > 0 {
+		s.Page = f.Page
+	}
+	if f.PageSize < 0 || f.PageSize > maxLimit {
+		details = append(details, domainErrors.FieldError{Field: "pageSize", Rule: "range", Message: fmt.Sprintf("pageSize must be between 1 and %d", maxLimit)})
+	} else if f.PageSize > 0 {
+		s.Limit = f.PageSize
+	}
+
+	desc := false
+	switch f.SortDirection {
+	case "", domain.SortAsc:
+	case domain.SortDesc:
+		desc = true
+	default:
+		details = append(details, domainErrors.FieldError{Field: "sortDirection", Rule: "oneof", Message: "sortDirection must be asc or desc"})
+	}
+	for _, name := range f.SortBy {
+		column, ok := spec.Sortable[name]
+		if !ok {
+			details = append(details, domainErrors.FieldError{Field: "sortBy", Rule: "oneof", Message: fmt.Sprintf("cannot sort by %s; allowed: %s", name, names(spec.Sortable))})
+			continue
+		}
+		s.Sort = append(s.Sort, SortField{Column: column, Desc: desc})
+	}
+	if len(f.SortBy) == 0 {
+		for _, name := range strings.Split(spec.DefaultSort, ",") {
+			if column, ok := spec.Sortable[strings.TrimPrefix(name, "-")]; ok {
+				s.Sort = append(s.Sort, SortField{Column: column, Desc: strings.HasPrefix(name, "-")})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(f.LikeFilters) {
+		column, ok := spec.Searchable[name]
+		if !ok {
+			details = append(details, domainErrors.FieldError{Field: "likeFilters." + name, Rule: "oneof", Message: fmt.Sprintf("cannot search %s; allowed: %s", name, names(spec.Searchable))})
+			continue
+		}
+		if values := nonEmpty(f.LikeFilters[name]); len(values) > 0 {
+			s.Like[column] = values
+		}
+	}
+	for _, name := range sortedKeys(f.Matches) {
+		column, ok := spec.Filterable[name]
+		if !ok {
+			details = append(details, domainErrors.FieldError{Field: "matches." + name, Rule: "oneof", Message: fmt.Sprintf("cannot match %s; allowed: %s", name, names(spec.Filterable))})
+			continue
+		}
+		if values := nonEmpty(f.Matches[name]); len(values) > 0 {
+			s.Matches[column] = values
+		}
+	}
+	for i, r := range f.DateRangeFilters {
+		column, ok := spec.Dates[r.Field]
+		switch {
+		case !ok:
+			details = append(details, domainErrors.FieldError{Field: fmt.Sprintf("dateRanges[%d].field", i), Rule: "oneof", Message: fmt.Sprintf("cannot bound %s; allowed: %s", r.Field, names(spec.Dates))})
+		case r.Start != nil && r.End != nil && r.End.Before(*r.Start):
+			details = append(details, domainErrors.FieldError{Field: fmt.Sprintf("dateRanges[%d].end", i), Rule: "gtefield", Message: "end must not be before start"})
+		case r.Start != nil || r.End != nil:
+			s.DateRanges = append(s.DateRanges, DateRange{Column: column, Start: r.Start, End: r.End})
+		}
+	}
+
+	if len(details) > 0 {
+		return Search{}, domainErrors.NewValidationError(errors.New("invalid search filters"), details...)
+	}
+	return s, nil
+}
+
+// Where is a GORM scope applying the LIKE matches, exact matches and date ranges. Pass the
+// query through it before Find, which adds the sort order and page.
+func (s Search) Where() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, column := range sortedKeys(s.Like) {
+			exprs := make([]clause.Expression, len(s.Like[column]))
+			for i, v := range s.Like[column] {
+				exprs[i] = clause.Expr{SQL: "? ILIKE ?", Vars: []any{clause.Column{Name: column}, "%" + escapeLike(v) + "%"}}
+			}
+			db = db.Where(clause.Or(exprs...))
+		}
+		for _, column := range sortedKeys(s.Matches) {
+			values := make([]any, len(s.Matches[column]))
+			for i, v := range s.Matches[column] {
+				values[i] = v
+			}
+			db = db.Where(clause.IN{Column: clause.Column{Name: column}, Values: values})
+		}
+		for _, r := range s.DateRanges {
+			if r.Start != nil {
+				db = db.Where(clause.Gte{Column: clause.Column{Name: r.Column}, Value: *r.Start})
+			}
+			if r.End != nil {
+				db = db.Where(clause.Lte{Column: clause.Column{Name: r.Column}, Value: *r.End})
+			}
+		}
+		return db
+	}
+}
+
+// FindSearch is Find for a search: it counts the rows matching query and the search, then
+// loads the requested page into dest
+func FindSearch[T any](query *gorm.DB, s Search, dest *[]T) (int64, error) {
+	return Find(query.Scopes(s.Where()), s.Params, dest)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
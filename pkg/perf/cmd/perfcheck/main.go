@@ -0,0 +1,30 @@
+// Command perfcheck holds "go test -bench . -benchmem" output read from stdin to the baselines
+// of pkg/perf, echoing it as it goes.
+//
+//	go test -run '^$' -bench . -benchmem ./pkg/security | go run ./pkg/perf/cmd/perfcheck
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"ecommerce-microservice-go/pkg/perf"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	baselines, err := perf.Baselines()
+	if err != nil {
+		return err
+	}
+	results, parseErr := perf.Parse(io.TeeReader(os.Stdin, os.Stdout))
+	return errors.Join(parseErr, perf.Check(results, baselines))
+}
@@ -0,0 +1,122 @@
+// Package perf holds the benchmarks of the services' hot paths to the baselines committed in
+// baselines.json. The benchmarks are ordinary BenchmarkXxx functions in _test.go files next to
+// the code they measure; "make perf" runs them with "go test -bench" and pipes the output through
+// cmd/perfcheck, which fails when one is missing, has no baseline or exceeds it.
+package perf
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// module is the prefix of every package path go test prints
+const module = "ecommerce-microservice-go/"
+
+// Baseline is the most a benchmark may take per operation. NsPerOp leaves about three times the
+// time measured when it was set, for slower machines; AllocsPerOp leaves little, since
+// allocations do not depend on the machine.
+type Baseline struct {
+	NsPerOp     float64 `json:"nsPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+}
+
+//go:embed baselines.json
+var baselinesJSON []byte
+
+// Baselines returns the committed baselines by benchmark name
+func Baselines() (map[string]Baseline, error) {
+	var baselines map[string]Baseline
+	if err := json.Unmarshal(baselinesJSON, &baselines); err != nil {
+		return nil, fmt.Errorf("reading baselines.json: %w", err)
+	}
+	return baselines, nil
+}
+
+// Result is one benchmark line of "go test -bench . -benchmem". Name is the package path without
+// the module and the pkg/ or services/ directory, then the benchmark without its Benchmark prefix
+// and GOMAXPROCS suffix, e.g. "order/usecase/ApplyDiscounts".
+type Result struct {
+	Name        string
+	NsPerOp     float64
+	AllocsPerOp int64
+}
+
+// Parse reads the results out of "go test -bench" output, failing on a failed package
+func Parse(r io.Reader) ([]Result, error) {
+	var (
+		results []Result
+		errs    []error
+		pkg     string
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(line, "pkg: "):
+			pkg = strings.TrimPrefix(strings.TrimPrefix(line, "pkg: "), module)
+			pkg = strings.TrimPrefix(strings.TrimPrefix(pkg, "pkg/"), "services/")
+		case strings.HasPrefix(line, "FAIL"), strings.HasPrefix(line, "--- FAIL"):
+			errs = append(errs, errors.New(line))
+		case len(fields) > 2 && strings.HasPrefix(fields[0], "Benchmark"):
+			name := strings.TrimPrefix(fields[0], "Benchmark")
+			if i := strings.LastIndexByte(name, '-'); i > 0 {
+				name = name[:i]
+			}
+			result := Result{Name: pkg + "/" + name, AllocsPerOp: -1}
+			// after the iteration count come value, unit pairs
+			for i := 2; i+1 < len(fields); i += 2 {
+				switch fields[i+1] {
+				case "ns/op":
+					result.NsPerOp, _ = strconv.ParseFloat(fields[i], 64)
+				case "allocs/op":
+					result.AllocsPerOp, _ = strconv.ParseInt(fields[i], 10, 64)
+				}
+			}
+			if result.AllocsPerOp < 0 {
+				errs = append(errs, fmt.Errorf("%s: no allocs/op, run with -benchmem", result.Name))
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return results, errors.Join(errs...)
+}
+
+// Check compares the results with the baselines: a result without a baseline or above it fails,
+// and so does a baseline whose benchmark did not run
+func Check(results []Result, baselines map[string]Baseline) error {
+	var errs []error
+	ran := make(map[string]bool, len(results))
+	for _, r := range results {
+		ran[r.Name] = true
+		baseline, ok := baselines[r.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: no baseline in pkg/perf/baselines.json", r.Name))
+			continue
+		}
+		if r.NsPerOp > baseline.NsPerOp {
+			errs = append(errs, fmt.Errorf("%s: %.0f ns/op exceeds the baseline of %.0f", r.Name, r.NsPerOp, baseline.NsPerOp))
+		}
+		if r.AllocsPerOp > baseline.AllocsPerOp {
+			errs = append(errs, fmt.Errorf("%s: %d allocs/op exceeds the baseline of %d", r.Name, r.AllocsPerOp, baseline.AllocsPerOp))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(baselines)) {
+		if !ran[name] {
+			errs = append(errs, fmt.Errorf("%s: has a baseline but did not run", name))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,10 +1,11 @@
 package psql
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"strings"
+	"time"
 
+	"ecommerce-microservice-go/pkg/config"
 	"ecommerce-microservice-go/pkg/logger"
 
 	"go.uber.org/zap"
@@ -13,75 +14,8 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 )
 
-type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
-func LoadDatabaseConfig() (DatabaseConfig, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	sslMode := os.Getenv("DB_SSLMODE")
-
-	var missingVars []string
-	if host == "" {
-		missingVars = append(missingVars, "DB_HOST")
-	}
-	if port == "" {
-		missingVars = append(missingVars, "DB_PORT")
-	}
-	if user == "" {
-		missingVars = append(missingVars, "DB_USER")
-	}
-	if password == "" {
-		missingVars = append(missingVars, "DB_PASSWORD")
-	}
-	if dbName == "" {
-		missingVars = append(missingVars, "DB_NAME")
-	}
-	if sslMode == "" {
-		missingVars = append(missingVars, "DB_SSLMODE")
-	}
-
-	if len(missingVars) > 0 {
-		return DatabaseConfig{}, fmt.Errorf("missing required database environment variables: %s", strings.Join(missingVars, ", "))
-	}
-
-	return DatabaseConfig{
-		Host:     host,
-		Port:     port,
-		User:     user,
-		Password: password,
-		DBName:   dbName,
-		SSLMode:  sslMode,
-	}, nil
-}
-
-func (c DatabaseConfig) GetDSN() string {
-	return "host=" + c.Host +
-		" port=" + c.Port +
-		" user=" + c.User +
-		" password=" + c.Password +
-		" dbname=" + c.DBName +
-		" sslmode=" + c.SSLMode +
-		" TimeZone=UTC"
-}
-
 // ConnectDB creates a new GORM database connection
-func ConnectDB(loggerInstance *logger.Logger) (*gorm.DB, error) {
-	cfg, err := LoadDatabaseConfig()
-	if err != nil {
-		loggerInstance.Error("Failed to load database configuration", zap.Error(err))
-		return nil, fmt.Errorf("failed to load database configuration: %w", err)
-	}
-
+func ConnectDB(cfg config.Database, loggerInstance *logger.Logger) (*gorm.DB, error) {
 	gormZap := logger.NewGormLogger(loggerInstance.Log).
 		LogMode(gormlogger.Warn)
 
@@ -93,17 +27,51 @@ func ConnectDB(loggerInstance *logger.Logger) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	loggerInstance.Info("Database connection successful")
-	return db, nil
-}
-
-// AutoMigrate runs GORM AutoMigrate for the given models
-func AutoMigrate(db *gorm.DB, loggerInstance *logger.Logger, models ...interface{}) error {
-	err := db.AutoMigrate(models...)
+	sqlDB, err := db.DB()
 	if err != nil {
-		loggerInstance.Error("Error migrating database entities", zap.Error(err))
-		return err
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	backoff := cfg.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = sqlDB.PingContext(ctx)
+		cancel()
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.ConnectRetries {
+			loggerInstance.Error("Database is not reachable", zap.Int("attempts", attempt+1), zap.Error(err))
+			_ = sqlDB.Close()
+			return nil, fmt.Errorf("database is not reachable: %w", err)
+		}
+		loggerInstance.Warn("Database is not reachable yet, retrying", zap.Int("attempt", attempt+1), zap.Duration("backoff", backoff), zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
 	}
-	loggerInstance.Info("Database entities migration completed successfully")
-	return nil
+
+	if err := registerAuditColumns(db); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	if err := registerTenantScope(db); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+
+	if len(cfg.ReplicaHosts) > 0 {
+		if err := useReplicas(db, cfg); err != nil {
+			loggerInstance.Error("Error configuring read replicas", zap.Error(err))
+			_ = sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	loggerInstance.Info("Database connection successful",
+		zap.Int("maxOpenConns", cfg.MaxOpenConns), zap.Int("maxIdleConns", cfg.MaxIdleConns), zap.Int("replicas", len(cfg.ReplicaHosts)))
+	return db, nil
 }
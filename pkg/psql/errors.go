@@ -0,0 +1,56 @@
+package psql
+
+import (
+	"errors"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres SQLSTATE codes of constraint violations callers can act on
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// TranslateError converts a database error into the AppError repositories return. Unique
+// violations become ResourceAlreadyExists; foreign key, check and not-null violations a
+// ValidationError; a missing record NotFound. AppErrors pass through and anything else is
+// an UnknownError, so driver details never reach clients.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *domainErrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return domainErrors.NewAppErrorWithType(domainErrors.ResourceAlreadyExists)
+	case pgForeignKeyViolation:
+		return domainErrors.NewAppError(errors.New("referenced record does not exist or is still in use"), domainErrors.ValidationError)
+	case pgCheckViolation:
+		return domainErrors.NewAppError(errors.New("value is out of the allowed range"), domainErrors.ValidationError)
+	case pgNotNullViolation:
+		return domainErrors.NewAppError(errors.New("a required value is missing"), domainErrors.ValidationError)
+	}
+	return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+}
+
+// IsUniqueViolation reports whether err is a Postgres unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,91 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ecommerce-microservice-go/pkg/health"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PoolStats is the connection pool state reported by the health endpoint
+type PoolStats struct {
+	MaxOpenConnections int    `json:"maxOpenConnections"`
+	OpenConnections    int    `json:"openConnections"`
+	InUse              int    `json:"inUse"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"waitCount"`
+	WaitDuration       string `json:"waitDuration"`
+	MaxIdleClosed      int64  `json:"maxIdleClosed"`
+	MaxLifetimeClosed  int64  `json:"maxLifetimeClosed"`
+}
+
+// DatabaseHealth is the result of pinging the database together with its pool statistics
+type DatabaseHealth struct {
+	Status  string    `json:"status"`
+	Latency string    `json:"latency"`
+	Error   string    `json:"error,omitempty"`
+	Pool    PoolStats `json:"pool"`
+}
+
+// CheckHealth pings the database and reads the pool statistics. The returned error is the
+// ping failure, if any; the health value is filled in either way.
+func CheckHealth(ctx context.Context, db *gorm.DB) (DatabaseHealth, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return DatabaseHealth{Status: "down", Error: err.Error()}, err
+	}
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	stats := sqlDB.Stats()
+	health := DatabaseHealth{
+		Status:  "up",
+		Latency: time.Since(start).String(),
+		Pool: PoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration.String(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		},
+	}
+	if err != nil {
+		health.Status = "down"
+		health.Error = err.Error()
+	}
+	return health, err
+}
+
+// DatabaseCheck is a readiness check that pings the database and reports the pool statistics
+func DatabaseCheck(db *gorm.DB) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		dbHealth, err := CheckHealth(ctx, db)
+		return dbHealth.Pool, err
+	}
+}
+
+// MigrationStateCheck is a readiness check that fails while the schema is dirty or behind the
+// migrations the service was built with. It reads the version without taking the migration lock.
+func MigrationStateCheck(m *Migrator) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		version, dirty, err := readMigrationVersion(m.db.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		latest := m.latestVersion()
+		details := gin.H{"version": version, "latest": latest}
+		switch {
+		case dirty:
+			return details, fmt.Errorf("schema is dirty at version %d", version)
+		case version < latest:
+			return details, fmt.Errorf("schema is at version %d, expected %d", version, latest)
+		}
+		return details, nil
+	}
+}
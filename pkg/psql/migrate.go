@@ -0,0 +1,341 @@
+package psql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// migrationLockID is the advisory lock held while migrating so replicas starting together
+// do not apply the same migration twice
+const migrationLockID = 72_614_001
+
+// NoTransactionDirective as the first line of a migration runs it outside a transaction,
+// which statements such as CREATE INDEX CONCURRENTLY require
+const NoTransactionDirective = "-- migrate:no-transaction"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([\w-]+)\.(up|down)\.sql$`)
+
+// ErrDirtyDatabase means a migration failed halfway; the schema has to be repaired by hand
+// and the version set with `migrate force` before anything else runs
+var ErrDirtyDatabase = errors.New("database is dirty after a failed migration")
+
+type migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is the applied version, 0 when none, and the versions still to apply
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Pending []uint
+}
+
+// Migrator applies a service's numbered SQL files (000001_name.up.sql, 000001_name.down.sql)
+// in order. The applied version is kept in schema_migrations together with a dirty flag,
+// the same layout golang-migrate uses.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []migration
+	Logger     *logger.Logger
+}
+
+func NewMigrator(db *gorm.DB, source fs.FS, l *logger.Logger) (*Migrator, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+	byVersion := map[uint]*migration{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must look like 000001_description.up.sql", e.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive number", e.Name())
+		}
+		content, err := fs.ReadFile(source, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", e.Name(), err)
+		}
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	// migrations hold the advisory lock on one connection and must not read from a replica
+	primary, err := primaryOnly(db)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: primary, migrations: migrations, Logger: l}, nil
+}
+
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	var status *MigrationStatus
+	err := m.locked(func(conn *gorm.DB) error {
+		version, dirty, err := readMigrationVersion(conn)
+		if err != nil {
+			return err
+		}
+		status = &MigrationStatus{Version: version, Dirty: dirty, Pending: []uint{}}
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				status.Pending = append(status.Pending, mg.Version)
+			}
+		}
+		return nil
+	})
+	return status, err
+}
+
+// Up applies every pending migration and returns how many ran
+func (m *Migrator) Up() (int, error) {
+	applied := 0
+	err := m.locked(func(conn *gorm.DB) error {
+		version, dirty, err := readMigrationVersion(conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirtyDatabase, version)
+		}
+		for _, mg := range m.migrations {
+			if mg.Version <= version {
+				continue
+			}
+			m.Logger.Info("Applying migration", zap.Uint("version", mg.Version), zap.String("name", mg.Name))
+			if err := applyMigration(conn, mg.Up, mg.Version); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns how many ran
+func (m *Migrator) Down(steps int) (int, error) {
+	reverted := 0
+	err := m.locked(func(conn *gorm.DB) error {
+		version, dirty, err := readMigrationVersion(conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirtyDatabase, version)
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			mg := m.migrations[i]
+			if mg.Version > version {
+				continue
+			}
+			if mg.Version != version {
+				return fmt.Errorf("applied version %d has no migration file", version)
+			}
+			if strings.TrimSpace(mg.Down) == "" {
+				return fmt.Errorf("migration %d_%s cannot be reverted: no down file", mg.Version, mg.Name)
+			}
+			previous := uint(0)
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			m.Logger.Info("Reverting migration", zap.Uint("version", mg.Version), zap.String("name", mg.Name))
+			if err := applyMigration(conn, mg.Down, previous); err != nil {
+				return fmt.Errorf("reverting %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			version = previous
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records version as applied and clears the dirty flag without running anything.
+// It is the way out of a dirty database once the schema has been fixed by hand.
+func (m *Migrator) Force(version uint) error {
+	return m.locked(func(conn *gorm.DB) error {
+		m.Logger.Warn("Forcing migration version", zap.Uint("version", version))
+		return writeMigrationVersion(conn, version, false)
+	})
+}
+
+// EnsureSchema runs at startup. A dirty database always stops the service. Pending migrations
+// are applied when applyPending is set and stop the service otherwise, so production deploys
+// migrate as a separate step.
+func (m *Migrator) EnsureSchema(applyPending bool) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w at version %d", ErrDirtyDatabase, status.Version)
+	}
+	if len(status.Pending) == 0 {
+		m.Logger.Info("Database schema is up to date", zap.Uint("version", status.Version))
+		return nil
+	}
+	if !applyPending {
+		return fmt.Errorf("%d migrations pending (next %d); run the migrate up command first", len(status.Pending), status.Pending[0])
+	}
+	_, err = m.Up()
+	return err
+}
+
+func (m *Migrator) latestVersion() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// locked runs fn on a single connection holding the migration advisory lock
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		// a new session keeps a failed statement's error from sticking to the connection,
+		// which would skip the unlock and return the connection to the pool still locked
+		conn = conn.Session(&gorm.Session{NewDB: true})
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return fmt.Errorf("acquiring migration lock: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID).Error; err != nil {
+				m.Logger.Error("Error releasing migration lock", zap.Error(err))
+			}
+		}()
+		if err := conn.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error; err != nil {
+			return fmt.Errorf("creating schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+// applyMigration runs one migration and records the version it leaves the schema at. Inside
+// a transaction a failure changes nothing; without one the version is marked dirty first
+// and stays dirty if the statements fail.
+func applyMigration(conn *gorm.DB, sql string, version uint) error {
+	if strings.HasPrefix(strings.TrimSpace(sql), NoTransactionDirective) {
+		if err := writeMigrationVersion(conn, version, true); err != nil {
+			return err
+		}
+		if err := conn.Exec(sql).Error; err != nil {
+			return err
+		}
+		return writeMigrationVersion(conn, version, false)
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+		return writeMigrationVersion(tx, version, false)
+	})
+}
+
+func readMigrationVersion(conn *gorm.DB) (uint, bool, error) {
+	var rows []struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := conn.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("reading schema version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
+
+func writeMigrationVersion(conn *gorm.DB, version uint, dirty bool) error {
+	if err := conn.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		return fmt.Errorf("writing schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if err := conn.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty).Error; err != nil {
+		return fmt.Errorf("writing schema version: %w", err)
+	}
+	return nil
+}
+
+// RunMigrateCommand handles `<service> migrate up|down [n]|status|force <version>`
+func RunMigrateCommand(m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up | down [steps] | status | force <version>")
+	}
+	switch args[0] {
+	case "up":
+		n, err := m.Up()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "applied %d migrations\n", n)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New("down steps must be a positive number")
+			}
+		}
+		n, err := m.Down(steps)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "reverted %d migrations\n", n)
+		return err
+	case "status":
+		status, err := m.Status()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "version: %d\ndirty: %t\npending: %v\n", status.Version, status.Dirty, status.Pending)
+		return err
+	case "force":
+		if len(args) < 2 {
+			return errors.New("usage: migrate force <version>")
+		}
+		version, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return errors.New("version must be a number")
+		}
+		return m.Force(uint(version))
+	}
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
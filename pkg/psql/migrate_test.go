@@ -0,0 +1,328 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+// fakePostgres understands the statements the migrator sends. Migrations are recorded by
+// their SQL; those containing "FAIL" fail. The advisory lock is shared by all connections.
+type fakePostgres struct {
+	lock chan struct{}
+	// delay slows every migration down so concurrent migrators overlap
+	delay time.Duration
+
+	mu      sync.Mutex
+	version *[2]any // version, dirty
+	applied []string
+	holders int
+	// maxHolders is the most connections that ran migrations at once
+	maxHolders int
+}
+
+func newFakePostgres() *fakePostgres {
+	return &fakePostgres{lock: make(chan struct{}, 1)}
+}
+
+func (p *fakePostgres) Connect(context.Context) (driver.Conn, error) { return &fakeConn{pg: p}, nil }
+func (p *fakePostgres) Driver() driver.Driver                        { return nil }
+
+func (p *fakePostgres) gorm(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(p)}), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func (p *fakePostgres) state() (version any, dirty any, applied []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.version == nil {
+		return nil, nil, slices.Clone(p.applied)
+	}
+	return p.version[0], p.version[1], slices.Clone(p.applied)
+}
+
+type fakeConn struct {
+	pg     *fakePostgres
+	locked bool
+	// snapshot is what a rollback restores
+	snapshot *struct {
+		version *[2]any
+		applied int
+	}
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	pg := c.pg
+	switch {
+	case strings.HasPrefix(query, "SELECT pg_advisory_lock"):
+		select {
+		case pg.lock <- struct{}{}:
+		case <-time.After(time.Second):
+			return nil, errors.New("timed out waiting for the advisory lock")
+		}
+		c.locked = true
+		pg.mu.Lock()
+		pg.holders++
+		pg.maxHolders = max(pg.maxHolders, pg.holders)
+		pg.mu.Unlock()
+	case strings.HasPrefix(query, "SELECT pg_advisory_unlock"):
+		if c.locked {
+			c.locked = false
+			pg.mu.Lock()
+			pg.holders--
+			pg.mu.Unlock()
+			<-pg.lock
+		}
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+	case query == "DELETE FROM schema_migrations":
+		pg.mu.Lock()
+		pg.version = nil
+		pg.mu.Unlock()
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		pg.mu.Lock()
+		pg.version = &[2]any{args[0].Value, args[1].Value}
+		pg.mu.Unlock()
+	default:
+		if !c.locked {
+			return nil, errors.New("migration ran without the advisory lock")
+		}
+		time.Sleep(pg.delay)
+		if strings.Contains(query, "FAIL") {
+			return nil, errors.New(`syntax error at or near "FAIL"`)
+		}
+		pg.mu.Lock()
+		pg.applied = append(pg.applied, strings.TrimSpace(strings.TrimPrefix(query, NoTransactionDirective)))
+		pg.mu.Unlock()
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if query != "SELECT version, dirty FROM schema_migrations LIMIT 1" {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	c.pg.mu.Lock()
+	defer c.pg.mu.Unlock()
+	rows := &fakeRows{}
+	if c.pg.version != nil {
+		rows.values = [][]driver.Value{{c.pg.version[0], c.pg.version[1]}}
+	}
+	return rows, nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.pg.mu.Lock()
+	defer c.pg.mu.Unlock()
+	c.snapshot = &struct {
+		version *[2]any
+		applied int
+	}{c.pg.version, len(c.pg.applied)}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.pg.mu.Lock()
+	defer c.pg.mu.Unlock()
+	c.pg.version, c.pg.applied = c.snapshot.version, c.pg.applied[:c.snapshot.applied]
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *fakeConn) Close() error { return nil }
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version", "dirty"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newTestMigrator(t *testing.T, pg *fakePostgres, files fstest.MapFS) *Migrator {
+	t.Helper()
+	m, err := NewMigrator(pg.gorm(t), files, testLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+var testMigrations = fstest.MapFS{
+	"000001_products.up.sql":   {Data: []byte("CREATE TABLE products ()")},
+	"000001_products.down.sql": {Data: []byte("DROP TABLE products")},
+	"000002_orders.up.sql":     {Data: []byte("CREATE TABLE orders ()")},
+	"000002_orders.down.sql":   {Data: []byte("DROP TABLE orders")},
+}
+
+// withMigration returns the test migrations and the file
+func withMigration(name, sql string) fstest.MapFS {
+	files := fstest.MapFS{name: {Data: []byte(sql)}}
+	for k, v := range testMigrations {
+		files[k] = v
+	}
+	return files
+}
+
+func TestMigratorUp(t *testing.T) {
+	pg := newFakePostgres()
+	m := newTestMigrator(t, pg, testMigrations)
+	if n, err := m.Up(); err != nil || n != 2 {
+		t.Fatalf("Up() = %d, %v, want 2 migrations", n, err)
+	}
+	if n, err := m.Up(); err != nil || n != 0 {
+		t.Fatalf("second Up() = %d, %v, want nothing to apply", n, err)
+	}
+	version, dirty, applied := pg.state()
+	if version != int64(2) || dirty != false || !slices.Equal(applied, []string{"CREATE TABLE products ()", "CREATE TABLE orders ()"}) {
+		t.Errorf("version %v dirty %v applied %q", version, dirty, applied)
+	}
+	if n, err := m.Down(2); err != nil || n != 2 {
+		t.Fatalf("Down(2) = %d, %v", n, err)
+	}
+	if version, _, _ := pg.state(); version != nil {
+		t.Errorf("version %v after reverting everything, want none", version)
+	}
+}
+
+func TestMigratorFailedMigration(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		wantDirty bool
+	}{
+		{name: "in a transaction nothing changes", sql: "CREATE TABLE payments (); FAIL"},
+		{name: "without a transaction the version is dirty", sql: NoTransactionDirective + "\nCREATE INDEX CONCURRENTLY FAIL", wantDirty: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := newFakePostgres()
+			m := newTestMigrator(t, pg, withMigration("000003_payments.up.sql", tt.sql))
+			if _, err := m.Up(); err == nil {
+				t.Fatal("Up() succeeded, want the migration's error")
+			}
+			version, dirty, _ := pg.state()
+			if tt.wantDirty {
+				if version != int64(3) || dirty != true {
+					t.Fatalf("version %v dirty %v, want 3 dirty", version, dirty)
+				}
+				return
+			}
+			if version != int64(2) || dirty != false {
+				t.Fatalf("version %v dirty %v, want 2 clean", version, dirty)
+			}
+			// the lock was released, so the next run gets it and tries the migration again
+			if _, err := m.Up(); err == nil || errors.Is(err, ErrDirtyDatabase) {
+				t.Fatalf("Up() = %v, want the migration's error again", err)
+			}
+		})
+	}
+}
+
+func TestMigratorDirtyRecovery(t *testing.T) {
+	pg := newFakePostgres()
+	broken := withMigration("000003_index.up.sql", NoTransactionDirective+"\nCREATE INDEX CONCURRENTLY FAIL")
+	if _, err := newTestMigrator(t, pg, broken).Up(); err == nil {
+		t.Fatal("Up() succeeded, want the migration's error")
+	}
+
+	fixed := withMigration("000003_index.up.sql", NoTransactionDirective+"\nCREATE INDEX CONCURRENTLY orders_user_id")
+	fixed["000004_refunds.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE refunds ()")}
+	m := newTestMigrator(t, pg, fixed)
+	if _, err := m.Up(); !errors.Is(err, ErrDirtyDatabase) {
+		t.Fatalf("Up() = %v, want ErrDirtyDatabase", err)
+	}
+	if _, err := m.Down(1); !errors.Is(err, ErrDirtyDatabase) {
+		t.Fatalf("Down() = %v, want ErrDirtyDatabase", err)
+	}
+	if err := m.EnsureSchema(true); !errors.Is(err, ErrDirtyDatabase) {
+		t.Fatalf("EnsureSchema() = %v, want ErrDirtyDatabase", err)
+	}
+	status, err := m.Status()
+	if err != nil || !status.Dirty || status.Version != 3 || !slices.Equal(status.Pending, []uint{4}) {
+		t.Fatalf("Status() = %+v, %v, want dirty at 3 with 4 pending", status, err)
+	}
+
+	// the operator finished the index by hand and records it as applied
+	if err := m.Force(3); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := m.Up(); err != nil || n != 1 {
+		t.Fatalf("Up() after force = %d, %v, want only 4 applied", n, err)
+	}
+	version, dirty, applied := pg.state()
+	if version != int64(4) || dirty != false || applied[len(applied)-1] != "CREATE TABLE refunds ()" {
+		t.Errorf("version %v dirty %v applied %q", version, dirty, applied)
+	}
+}
+
+func TestMigratorLock(t *testing.T) {
+	pg := newFakePostgres()
+	pg.delay = 5 * time.Millisecond
+	// replicas starting together each run the migrations of the same build
+	var wg sync.WaitGroup
+	counts := make([]int, 4)
+	errs := make([]error, len(counts))
+	for i := range counts {
+		m := newTestMigrator(t, pg, testMigrations)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[i], errs[i] = m.Up()
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if _, _, applied := pg.state(); total != 2 || len(applied) != 2 {
+		t.Errorf("migrators applied %v, statements %q, want each migration once", counts, applied)
+	}
+	if pg.maxHolders != 1 {
+		t.Errorf("%d migrators held the lock at once", pg.maxHolders)
+	}
+	if len(pg.lock) != 0 {
+		t.Error("advisory lock was not released")
+	}
+}
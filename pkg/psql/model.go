@@ -0,0 +1,90 @@
+package psql
+
+import (
+	"context"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BaseModel holds the public ID, timestamp, soft delete and audit columns of the services' main
+// tables. Embed it in GORM models; Delete then sets deleted_at and queries skip deleted rows
+// unless they use Unscoped. UUID is the identifier for clients, generated on create and never
+// updated, while the integer ID stays the key of joins. CreatedBy and UpdatedBy are filled from
+// the actor in the context, nil for writes of the system itself, e.g. scheduled jobs.
+type BaseModel struct {
+	UUID      string         `gorm:"column:uuid;type:uuid;not null;uniqueIndex;<-:create"`
+	CreatedAt time.Time      `gorm:"autoCreateTime:mili"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime:mili"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	CreatedBy *int           `gorm:"column:created_by"`
+	UpdatedBy *int           `gorm:"column:updated_by"`
+}
+
+// BeforeCreate assigns a UUIDv7, which sorts by creation time and keeps the index compact
+func (m *BaseModel) BeforeCreate(*gorm.DB) error {
+	if m.UUID != "" {
+		return nil
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	m.UUID = id.String()
+	return nil
+}
+
+// IDByUUID returns the integer ID of the model's row with the UUID, NotFound when there is none
+func IDByUUID(db *gorm.DB, model any, publicID string) (int, error) {
+	var ids []int
+	if err := db.Model(model).Where("uuid = ?", publicID).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, TranslateError(err)
+	}
+	if len(ids) == 0 {
+		return 0, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return ids[0], nil
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx whose writes are attributed to the user, set by the
+// auth middleware
+func ContextWithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext returns the user set by ContextWithActor
+func ActorFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(actorKey{}).(int)
+	return id, ok && id != 0
+}
+
+// registerAuditColumns fills created_by and updated_by of models with those fields
+func registerAuditColumns(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("psql:audit_columns", setActor("CreatedBy", "UpdatedBy")); err != nil {
+		return err
+	}
+	return cb.Update().Before("gorm:update").Register("psql:audit_columns", setActor("UpdatedBy"))
+}
+
+func setActor(fields ...string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.Schema == nil {
+			return
+		}
+		id, ok := ActorFromContext(db.Statement.Context)
+		if !ok {
+			return
+		}
+		for _, name := range fields {
+			if db.Statement.Schema.LookUpField(name) != nil {
+				db.Statement.SetColumn(name, &id, true)
+			}
+		}
+	}
+}
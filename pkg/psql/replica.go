@@ -0,0 +1,78 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// useReplicas sends reads to cfg.ReplicaHosts and keeps writes, locking reads and transactions
+// on the primary. Queries run with a context from ContextWithPrimary read from the primary too.
+func useReplicas(db *gorm.DB, cfg config.Database) error {
+	replicas := make([]gorm.Dialector, len(cfg.ReplicaHosts))
+	for i, host := range cfg.ReplicaHosts {
+		replicas[i] = postgres.Open(cfg.ReplicaDSN(host))
+	}
+	var policy dbresolver.Policy = dbresolver.RandomPolicy{}
+	if cfg.ReplicaPolicy == config.ReplicaPolicyRoundRobin {
+		policy = dbresolver.RoundRobinPolicy()
+	}
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas, Policy: policy}).
+		SetMaxOpenConns(cfg.MaxOpenConns).
+		SetMaxIdleConns(cfg.MaxIdleConns).
+		SetConnMaxLifetime(cfg.ConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("registering read replicas: %w", err)
+	}
+	// runs before the resolver picks a connection, see dbresolver's registerCallbacks
+	pinPrimary := func(tx *gorm.DB) {
+		if readsFromPrimary(tx.Statement.Context) {
+			dbresolver.Write.ModifyStatement(tx.Statement)
+		}
+	}
+	if err := db.Callback().Query().Before("gorm:db_resolver").Register("psql:pin_primary", pinPrimary); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:db_resolver").Register("psql:pin_primary", pinPrimary); err != nil {
+		return err
+	}
+	return db.Callback().Raw().Before("gorm:db_resolver").Register("psql:pin_primary", pinPrimary)
+}
+
+type primaryKey struct{}
+
+// ContextWithPrimary makes queries run with the returned context read from the primary, for
+// reads that must see a write made just before, e.g. in the same request
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func readsFromPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	pinned, _ := ctx.Value(primaryKey{}).(bool)
+	return pinned
+}
+
+// Primary returns a session whose reads go to the primary, the per-query form of
+// ContextWithPrimary
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write).Session(&gorm.Session{})
+}
+
+// primaryOnly returns a handle on the primary pool without the replica resolver, for code
+// that pins a single connection, which the resolver would swap for a pooled one
+func primaryOnly(db *gorm.DB) (*gorm.DB, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: db.Logger})
+}
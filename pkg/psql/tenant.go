@@ -0,0 +1,71 @@
+package psql
+
+import (
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tenantColumn = "tenant_id"
+
+// tenantScopeClause marks statements already scoped, as GORM marks soft delete with
+// soft_delete_enabled, so a statement run twice, e.g. Count then Find, gets one condition
+const tenantScopeClause = "psql:tenant_scope"
+
+// TenantModel holds the tenant of the rows of a multi-tenant table. Embed it next to
+// BaseModel: creates take the tenant of the context, and queries, updates and deletes run with
+// a tenant in the context only see that tenant's rows. Without a tenant, e.g. in scheduled jobs
+// and service-to-service calls, every row is visible and new rows belong to tenant.Default.
+type TenantModel struct {
+	TenantID string `gorm:"column:tenant_id;not null;default:default;index;<-:create"`
+}
+
+// registerTenantScope fills and filters tenant_id for models embedding TenantModel. The
+// condition also counts as a WHERE clause, so an update or delete without conditions is
+// allowed within a tenant and touches all of its rows.
+func registerTenantScope(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("psql:tenant_column", setTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register(tenantScopeClause, scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register(tenantScopeClause, scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register(tenantScopeClause, scopeTenant); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register(tenantScopeClause, scopeTenant)
+}
+
+// tenantOf returns the tenant of the statement when its model is multi-tenant
+func tenantOf(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.LookUpField("TenantID") == nil {
+		return "", false
+	}
+	id := tenant.FromContext(db.Statement.Context)
+	return id, id != ""
+}
+
+func setTenant(db *gorm.DB) {
+	if id, ok := tenantOf(db); ok {
+		db.Statement.SetColumn("TenantID", id, true)
+	}
+}
+
+func scopeTenant(db *gorm.DB) {
+	id, ok := tenantOf(db)
+	if !ok {
+		return
+	}
+	if _, scoped := db.Statement.Clauses[tenantScopeClause]; scoped {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: id},
+	}})
+	db.Statement.Clauses[tenantScopeClause] = clause.Clause{}
+}
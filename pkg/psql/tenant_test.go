@@ -0,0 +1,145 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type tenantRow struct {
+	ID   int
+	Name string
+	TenantModel
+}
+
+type sharedRow struct {
+	ID   int
+	Name string
+}
+
+// dryRun returns a database that builds statements with the tenant scope without running them
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(newFakePostgres())}),
+		&gorm.Config{Logger: gormlogger.Discard, DryRun: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registerTenantScope(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTenantScope(t *testing.T) {
+	acme := tenant.ContextWithTenant(context.Background(), "acme")
+	tests := []struct {
+		name     string
+		run      func(db *gorm.DB) *gorm.DB
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "query is scoped",
+			run:      func(db *gorm.DB) *gorm.DB { return db.WithContext(acme).Where("name = ?", "a").Find(&[]tenantRow{}) },
+			wantSQL:  `SELECT * FROM "tenant_rows" WHERE name = $1 AND "tenant_rows"."tenant_id" = $2`,
+			wantVars: []any{"a", "acme"},
+		},
+		{
+			name: "update is scoped",
+			run: func(db *gorm.DB) *gorm.DB {
+				return db.WithContext(acme).Model(&tenantRow{}).Where("id = ?", 1).Update("name", "b")
+			},
+			wantSQL:  `UPDATE "tenant_rows" SET "name"=$1 WHERE id = $2 AND "tenant_rows"."tenant_id" = $3`,
+			wantVars: []any{"b", 1, "acme"},
+		},
+		{
+			name:     "update of the whole table stays within the tenant",
+			run:      func(db *gorm.DB) *gorm.DB { return db.WithContext(acme).Model(&tenantRow{}).Update("name", "b") },
+			wantSQL:  `UPDATE "tenant_rows" SET "name"=$1 WHERE "tenant_rows"."tenant_id" = $2`,
+			wantVars: []any{"b", "acme"},
+		},
+		{
+			name:     "delete is scoped",
+			run:      func(db *gorm.DB) *gorm.DB { return db.WithContext(acme).Where("id = ?", 1).Delete(&tenantRow{}) },
+			wantSQL:  `DELETE FROM "tenant_rows" WHERE id = $1 AND "tenant_rows"."tenant_id" = $2`,
+			wantVars: []any{1, "acme"},
+		},
+		{
+			name:     "create takes the tenant of the context",
+			run:      func(db *gorm.DB) *gorm.DB { return db.WithContext(acme).Create(&tenantRow{Name: "a"}) },
+			wantSQL:  `INSERT INTO "tenant_rows" ("name","tenant_id") VALUES ($1,$2) RETURNING "id"`,
+			wantVars: []any{"a", "acme"},
+		},
+		{
+			name:     "query without a tenant sees every row",
+			run:      func(db *gorm.DB) *gorm.DB { return db.Where("name = ?", "a").Find(&[]tenantRow{}) },
+			wantSQL:  `SELECT * FROM "tenant_rows" WHERE name = $1`,
+			wantVars: []any{"a"},
+		},
+		{
+			name:     "create without a tenant belongs to the default tenant",
+			run:      func(db *gorm.DB) *gorm.DB { return db.Create(&tenantRow{Name: "a"}) },
+			wantSQL:  `INSERT INTO "tenant_rows" ("name","tenant_id") VALUES ($1,$2) RETURNING "id"`,
+			wantVars: []any{"a", tenant.Default},
+		},
+		{
+			name:     "tables without tenants are not scoped",
+			run:      func(db *gorm.DB) *gorm.DB { return db.WithContext(acme).Where("name = ?", "a").Find(&[]sharedRow{}) },
+			wantSQL:  `SELECT * FROM "shared_rows" WHERE name = $1`,
+			wantVars: []any{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := tt.run(dryRun(t))
+			if stmt.Error != nil {
+				t.Fatal(stmt.Error)
+			}
+			if got := stmt.Statement.SQL.String(); got != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			if !slices.Equal(stmt.Statement.Vars, tt.wantVars) {
+				t.Errorf("vars = %v, want %v", stmt.Statement.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+// TestTenantScopeOnce checks that a statement run twice, e.g. Count then Find, gets one
+// tenant condition
+func TestTenantScopeOnce(t *testing.T) {
+	ctx := tenant.ContextWithTenant(context.Background(), "acme")
+	var n int64
+	q := dryRun(t).WithContext(ctx).Model(&tenantRow{})
+	q.Count(&n)
+	q.Find(&[]tenantRow{})
+	if q.Error != nil {
+		t.Fatal(q.Error)
+	}
+	where, _ := q.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	if len(where.Exprs) != 1 {
+		t.Errorf("WHERE has %d conditions, want the tenant once: %v", len(where.Exprs), where.Exprs)
+	}
+}
+
+// TestTenantScopeCreateSetsModel checks that the created row carries its tenant, e.g. for
+// the response
+func TestTenantScopeCreateSetsModel(t *testing.T) {
+	row := tenantRow{Name: "a"}
+	ctx := tenant.ContextWithTenant(context.Background(), "acme")
+	if err := dryRun(t).WithContext(ctx).Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+	if row.TenantID != "acme" {
+		t.Errorf("tenant = %q, want acme", row.TenantID)
+	}
+}
@@ -0,0 +1,68 @@
+package psql
+
+//go:generate mockgen -source=tx.go -destination=mocks/tx.go -package=mocks
+
+import (
+	"context"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TxManager runs a unit of work in one database transaction. Repositories join it through
+// their WithTx(tx) constructor, or by reading their connection with Conn(ctx, db), so every
+// write commits or rolls back together.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx *gorm.DB) error) error
+}
+
+type GormTxManager struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewTxManager(db *gorm.DB, l *logger.Logger) TxManager {
+	return &GormTxManager{DB: db, Logger: l}
+}
+
+// WithinTransaction commits when fn returns nil and rolls back on error or panic.
+// The error returned by fn is passed through unchanged. fn gets a context carrying the
+// transaction: a WithinTransaction call made with it, e.g. by another use case, runs in a
+// savepoint of the outer transaction, so its failure rolls back only its own writes.
+func (m *GormTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx *gorm.DB) error) error {
+	db, nested := TxFromContext(ctx)
+	if !nested {
+		db = m.DB
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ContextWithTx(ctx, tx), tx)
+	})
+	if err != nil {
+		m.Logger.WithContext(ctx).Debug("Transaction rolled back", zap.Bool("savepoint", nested), zap.Error(err))
+	}
+	return err
+}
+
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction started by WithinTransaction, if any
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// Conn returns the transaction carried by ctx, or db when there is none, bound to ctx.
+// Repositories use it to join a running unit of work without a WithTx copy.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,56 @@
+package psql
+
+import (
+	"fmt"
+	"maps"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// ConflictError is the cause of the Conflict AppError UpdateVersioned returns when the row
+// was changed after the caller read it. errors.As finds it through the AppError.
+type ConflictError struct {
+	Table    string
+	ID       int
+	Expected int
+	Current  int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %d was modified by another request: version is %d, not %d", e.Table, e.ID, e.Current, e.Expected)
+}
+
+// UpdateVersioned applies updates to the T row with id only while its version column still
+// equals expected, and increments the version in the same statement, so a writer holding a
+// stale copy cannot overwrite a newer one. A missing row is NotFound; a changed row a
+// Conflict AppError wrapping *ConflictError.
+func UpdateVersioned[T any](db *gorm.DB, id, expected int, updates map[string]any) error {
+	values := make(map[string]any, len(updates)+1)
+	maps.Copy(values, updates)
+	values["version"] = gorm.Expr("version + 1")
+
+	tx := db.Model(new(T)).Where("id = ? AND version = ?", id, expected).Updates(values)
+	if tx.Error != nil {
+		return TranslateError(tx.Error)
+	}
+	if tx.RowsAffected > 0 {
+		return nil
+	}
+
+	var versions []int
+	if err := db.Model(new(T)).Where("id = ?", id).Limit(1).Pluck("version", &versions).Error; err != nil {
+		return TranslateError(err)
+	}
+	if len(versions) == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return NewConflictError(tx.Statement.Table, id, expected, versions[0])
+}
+
+// NewConflictError returns the Conflict AppError for a row of table at current that the
+// caller expected at another version, for use cases that compare versions themselves
+func NewConflictError(table string, id, expected, current int) error {
+	return domainErrors.NewAppError(&ConflictError{Table: table, ID: id, Expected: expected, Current: current}, domainErrors.Conflict)
+}
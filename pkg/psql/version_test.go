@@ -0,0 +1,142 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type versionedRow struct {
+	ID      int
+	Name    string
+	Version int
+}
+
+// fakeVersionedTable understands the statements UpdateVersioned sends for versionedRow and
+// keeps the version of each row by id
+type fakeVersionedTable struct {
+	mu       sync.Mutex
+	versions map[int64]int64
+	names    map[int64]string
+}
+
+func (f *fakeVersionedTable) Connect(context.Context) (driver.Conn, error) {
+	return &fakeVersionedConn{table: f}, nil
+}
+func (f *fakeVersionedTable) Driver() driver.Driver { return nil }
+
+type fakeVersionedConn struct {
+	table *fakeVersionedTable
+}
+
+// ExecContext applies `UPDATE "versioned_rows" SET "name"=$1,"version"=version + 1 WHERE id =
+// $2 AND version = $3`
+func (c *fakeVersionedConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, `UPDATE "versioned_rows" SET`) || !strings.HasSuffix(query, "WHERE id = $2 AND version = $3") {
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	id, expected := args[1].Value.(int64), args[2].Value.(int64)
+	f := c.table
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if v, ok := f.versions[id]; !ok || v != expected {
+		return driver.RowsAffected(0), nil
+	}
+	f.versions[id]++
+	f.names[id] = args[0].Value.(string)
+	return driver.RowsAffected(1), nil
+}
+
+// QueryContext answers `SELECT "version" FROM "versioned_rows" WHERE id = $1 LIMIT $2`
+func (c *fakeVersionedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, `SELECT "version" FROM "versioned_rows" WHERE id = $1`) {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	f := c.table
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rows := &fakeVersionRows{}
+	if v, ok := f.versions[args[0].Value.(int64)]; ok {
+		rows.values = [][]driver.Value{{v}}
+	}
+	return rows, nil
+}
+
+func (c *fakeVersionedConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeVersionedConn) Commit() error             { return nil }
+func (c *fakeVersionedConn) Rollback() error           { return nil }
+func (c *fakeVersionedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *fakeVersionedConn) Close() error { return nil }
+
+type fakeVersionRows struct {
+	fakeRows
+}
+
+func (r *fakeVersionRows) Columns() []string { return []string{"version"} }
+
+func TestUpdateVersioned(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		expected    int
+		wantErr     error
+		wantVersion int64
+		wantName    string
+	}{
+		{name: "current version is updated", id: 1, expected: 3, wantVersion: 4, wantName: "new"},
+		{name: "stale version conflicts", id: 1, expected: 2, wantErr: domainErrors.ErrConflict, wantVersion: 3, wantName: "old"},
+		{name: "missing row is not found", id: 2, expected: 1, wantErr: domainErrors.ErrNotFound, wantVersion: 3, wantName: "old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &fakeVersionedTable{versions: map[int64]int64{1: 3}, names: map[int64]string{1: "old"}}
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(table)}), &gorm.Config{Logger: gormlogger.Discard})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = UpdateVersioned[versionedRow](db, tt.id, tt.expected, map[string]any{"name": "new"})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if table.versions[1] != tt.wantVersion || table.names[1] != tt.wantName {
+				t.Errorf("row = %q at version %d, want %q at version %d", table.names[1], table.versions[1], tt.wantName, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// TestUpdateVersionedConflictError checks that callers can tell the client which version is
+// current
+func TestUpdateVersionedConflictError(t *testing.T) {
+	table := &fakeVersionedTable{versions: map[int64]int64{1: 5}, names: map[int64]string{1: "old"}}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(table)}), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateVersioned[versionedRow](db, 1, 4, map[string]any{"name": "new"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("error = %v, want a *ConflictError", err)
+	}
+	want := ConflictError{Table: "versioned_rows", ID: 1, Expected: 4, Current: 5}
+	if *conflict != want {
+		t.Errorf("conflict = %+v, want %+v", *conflict, want)
+	}
+}
@@ -0,0 +1,151 @@
+// Package retention purges or anonymizes rows once they are older than a configured number of
+// days, e.g. expired idempotency keys, released reservations and abandoned orders. Each
+// service lists its rules and runs them as scheduled jobs.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/scheduler"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Rule selects the rows of Table whose Column is older than the retention of the rule and
+// deletes them, or with Anonymize overwrites the listed columns and keeps the rows
+type Rule struct {
+	// Name names the job, e.g. "purge-idempotency-records"
+	Name string
+	// Setting is the variable holding the retention in days, 0 turning the rule off
+	Setting     string
+	DefaultDays int
+	Table       string
+	// Key identifies a row; "id" when empty
+	Key string
+	// Column is the timestamp the age is measured from
+	Column string
+	// Where narrows the rows further, e.g. "status = 'released'"
+	Where string
+	// Anonymize maps columns to the SQL expressions that replace them, e.g. "NULL"; Where must
+	// then exclude rows already anonymized so they are not selected again
+	Anonymize map[string]string
+	// Children are the rows referencing a purged row, deleted with it
+	Children []Child
+}
+
+// Child is a table whose Column references the key of the rule's table
+type Child struct {
+	Table  string
+	Column string
+}
+
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+	// Days holds the retention of each rule by name; rules at 0 do not run
+	Days map[string]int
+}
+
+func LoadConfig(src *config.Source, rules []Rule) Config {
+	cfg := Config{
+		Interval:  src.Duration("RETENTION_INTERVAL_MINUTES", 60, time.Minute),
+		BatchSize: src.Int("RETENTION_BATCH_SIZE", 500),
+		Days:      make(map[string]int, len(rules)),
+	}
+	for _, r := range rules {
+		days := src.Int(r.Setting, r.DefaultDays)
+		if days < 0 {
+			src.Fail(r.Setting, "must not be negative")
+		}
+		cfg.Days[r.Name] = days
+	}
+	if cfg.BatchSize <= 0 {
+		src.Fail("RETENTION_BATCH_SIZE", "must be positive")
+	}
+	return cfg
+}
+
+// Jobs returns a scheduler job for every rule with a retention
+func Jobs(db *gorm.DB, rules []Rule, cfg Config, l *logger.Logger) []scheduler.Job {
+	var jobs []scheduler.Job
+	for _, r := range rules {
+		days := cfg.Days[r.Name]
+		if days <= 0 {
+			continue
+		}
+		r := r
+		jobs = append(jobs, scheduler.Job{
+			Name:     r.Name,
+			Interval: cfg.Interval,
+			Run: func(ctx context.Context) (int, error) {
+				return Apply(ctx, db, r, time.Now().AddDate(0, 0, -days), cfg.BatchSize, l)
+			},
+		})
+	}
+	return jobs
+}
+
+// Apply purges or anonymizes the rows of the rule older than cutoff, batchSize rows per
+// statement so long runs hold few locks at a time, and returns how many it changed
+func Apply(ctx context.Context, db *gorm.DB, r Rule, cutoff time.Time, batchSize int, l *logger.Logger) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := applyBatch(ctx, db, r, cutoff, batchSize)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		if n < batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		l.Info("Retention rule applied", zap.String("rule", r.Name), zap.Int("rows", total), zap.Time("cutoff", cutoff))
+	}
+	return total, nil
+}
+
+// applyBatch changes one batch in a single statement. Foreign keys are checked at the end of
+// the statement, so children and their rows go together.
+func applyBatch(ctx context.Context, db *gorm.DB, r Rule, cutoff time.Time, batchSize int) (int, error) {
+	key := r.Key
+	if key == "" {
+		key = "id"
+	}
+	where := r.Column + " < ?"
+	if r.Where != "" {
+		where += " AND (" + r.Where + ")"
+	}
+	query := fmt.Sprintf("WITH batch AS (SELECT %[1]s FROM %[2]s WHERE %[3]s ORDER BY %[4]s LIMIT ? FOR UPDATE SKIP LOCKED)",
+		key, r.Table, where, r.Column)
+	if r.Anonymize != nil {
+		query += fmt.Sprintf(" UPDATE %s SET %s WHERE %s IN (SELECT %s FROM batch)", r.Table, assignments(r.Anonymize), key, key)
+	} else {
+		for i, child := range r.Children {
+			query += fmt.Sprintf(", child%d AS (DELETE FROM %s WHERE %s IN (SELECT %s FROM batch))", i, child.Table, child.Column, key)
+		}
+		query += fmt.Sprintf(" DELETE FROM %s WHERE %s IN (SELECT %s FROM batch)", r.Table, key, key)
+	}
+	result := db.WithContext(ctx).Exec(query, cutoff, batchSize)
+	return int(result.RowsAffected), result.Error
+}
+
+func assignments(columns map[string]string) string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	set := make([]string, len(names))
+	for i, name := range names {
+		set[i] = name + " = " + columns[name]
+	}
+	return strings.Join(set, ", ")
+}
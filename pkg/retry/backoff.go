@@ -0,0 +1,18 @@
+// Package retry holds the backoff shared by everything that retries on a schedule: webhook
+// deliveries, saga steps and proxied requests
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff is the wait before the attempt after the given failed one. It doubles base after
+// every failed attempt, is capped at maxWait and adds up to 20% jitter so retries spread out.
+func Backoff(attempt int, base, maxWait time.Duration) time.Duration {
+	wait := base << (attempt - 1)
+	if wait <= 0 || wait > maxWait {
+		wait = maxWait
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/5+1))
+}
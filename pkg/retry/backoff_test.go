@@ -0,0 +1,29 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first retry waits the base", attempt: 1, want: time.Second},
+		{name: "doubles per attempt", attempt: 3, want: 4 * time.Second},
+		{name: "capped at max", attempt: 10, want: time.Minute},
+		{name: "overflow is capped", attempt: 80, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				got := Backoff(tt.attempt, time.Second, time.Minute)
+				if got < tt.want || got > tt.want+tt.want/5 {
+					t.Fatalf("Backoff(%d) = %v, want %v plus at most 20%%", tt.attempt, got, tt.want)
+				}
+			}
+		})
+	}
+}
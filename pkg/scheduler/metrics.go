@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	jobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_job_runs_total",
+		Help: "Scheduled job runs by result (success, error, panic).",
+	}, []string{"job", "result"})
+
+	jobItems = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_job_items_total",
+		Help: "Items processed by scheduled jobs, e.g. orders swept.",
+	}, []string{"job"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduler_job_duration_seconds",
+		Help:    "Duration of scheduled job runs.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"job"})
+)
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Job is a periodic task. Run returns how many items it processed, which is
+// exported as the scheduler_job_items_total metric.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (int, error)
+}
+
+// Scheduler runs every job on its own ticker. Runs of the same job never overlap;
+// a tick that fires while the previous run is still busy is skipped.
+type Scheduler struct {
+	jobs   []Job
+	wg     sync.WaitGroup
+	Logger *logger.Logger
+}
+
+func New(l *logger.Logger) *Scheduler {
+	return &Scheduler{Logger: l}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches the jobs and returns immediately; they stop when ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until every job loop has returned after ctx was cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Run starts the jobs and blocks until they have all returned after ctx was cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	s.Start(ctx)
+	<-ctx.Done()
+	s.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+	s.Logger.Info("Scheduled job started", zap.String("job", job.Name), zap.Duration("interval", job.Interval))
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			jobRuns.WithLabelValues(job.Name, "panic").Inc()
+			s.Logger.Error("Scheduled job panicked", zap.String("job", job.Name), zap.Any("panic", r))
+		}
+	}()
+	n, err := job.Run(ctx)
+	jobDuration.WithLabelValues(job.Name).Observe(time.Since(start).Seconds())
+	jobItems.WithLabelValues(job.Name).Add(float64(n))
+	if err != nil {
+		jobRuns.WithLabelValues(job.Name, "error").Inc()
+		s.Logger.Error("Scheduled job failed", zap.String("job", job.Name), zap.Int("items", n), zap.Error(err))
+		return
+	}
+	jobRuns.WithLabelValues(job.Name, "success").Inc()
+	if n > 0 {
+		s.Logger.Info("Scheduled job finished", zap.String("job", job.Name), zap.Int("items", n), zap.Duration("took", time.Since(start)))
+	}
+}
@@ -1,15 +1,18 @@
 package security
 
+//go:generate mockgen -source=jwt_service.go -destination=mocks/jwt_service.go -package=mocks
+
 import (
 	"errors"
 	"fmt"
-	"os"
-	"strconv"
 	"time"
 
+	"ecommerce-microservice-go/pkg/config"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/tenant"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 const (
@@ -17,6 +20,23 @@ const (
 	Refresh = "refresh"
 )
 
+// clockSkew is the difference between the clocks of the issuing and verifying services
+// tolerated when checking exp, iat and nbf
+const clockSkew = 30 * time.Second
+
+const (
+	RoleCustomer = "customer"
+	RoleStaff    = "staff"
+	RoleAdmin    = "admin"
+	// RoleFulfillment ships and delivers orders without other staff rights
+	RoleFulfillment = "fulfillment"
+)
+
+// IsStaffRole reports whether the role may act on other users' resources
+func IsStaffRole(role string) bool {
+	return role == RoleAdmin || role == RoleStaff
+}
+
 type AppToken struct {
 	Token          string    `json:"token"`
 	TokenType      string    `json:"type"`
@@ -25,54 +45,42 @@ type AppToken struct {
 
 type Claims struct {
 	ID   int    `json:"id"`
+	Role string `json:"role"`
 	Type string `json:"type"`
+	// Tenant is the store the user belongs to; tokens without it belong to tenant.Default
+	Tenant string `json:"tenant,omitempty"`
 	jwt.RegisteredClaims
 }
 
-type JWTConfig struct {
-	AccessSecret  string
-	RefreshSecret string
-	AccessTime    int64
-	RefreshTime   int64
-}
-
 type IJWTService interface {
-	GenerateJWTToken(userID int, tokenType string) (*AppToken, error)
+	// GenerateJWTToken issues a token for a user of tenantID, "" for tenant.Default
+	GenerateJWTToken(userID int, role, tenantID, tokenType string) (*AppToken, error)
 	GetClaimsAndVerifyToken(tokenString string, tokenType string) (jwt.MapClaims, error)
 }
 
 type JWTService struct {
-	config JWTConfig
+	config config.JWT
 }
 
-func NewJWTService() IJWTService {
-	return &JWTService{config: loadJWTConfig()}
+func NewJWTService(cfg config.JWT) IJWTService {
+	return &JWTService{config: cfg}
 }
 
-func NewJWTServiceWithConfig(config JWTConfig) IJWTService {
-	return &JWTService{config: config}
-}
-
-func loadJWTConfig() JWTConfig {
-	return JWTConfig{
-		AccessSecret:  getEnvOrDefault("JWT_ACCESS_SECRET_KEY", "default_access_secret"),
-		RefreshSecret: getEnvOrDefault("JWT_REFRESH_SECRET_KEY", "default_refresh_secret"),
-		AccessTime:    getEnvAsInt64OrDefault("JWT_ACCESS_TIME_MINUTE", 60),
-		RefreshTime:   getEnvAsInt64OrDefault("JWT_REFRESH_TIME_HOUR", 24),
-	}
-}
-
-func (s *JWTService) GenerateJWTToken(userID int, tokenType string) (*AppToken, error) {
+func (s *JWTService) GenerateJWTToken(userID int, role, tenantID, tokenType string) (*AppToken, error) {
 	var secretKey string
 	var duration time.Duration
+	var audience []string
 
 	switch tokenType {
 	case Access:
 		secretKey = s.config.AccessSecret
-		duration = time.Duration(s.config.AccessTime) * time.Minute
+		duration = s.config.AccessTime
+		audience = s.config.AccessAudiences
 	case Refresh:
 		secretKey = s.config.RefreshSecret
-		duration = time.Duration(s.config.RefreshTime) * time.Hour
+		duration = s.config.RefreshTime
+		// only the issuing service accepts refresh tokens
+		audience = []string{s.config.Audience}
 	default:
 		return nil, errors.New("invalid token type")
 	}
@@ -81,9 +89,16 @@ func (s *JWTService) GenerateJWTToken(userID int, tokenType string) (*AppToken,
 	exp := now.Add(duration)
 
 	tokenClaims := &Claims{
-		ID:   userID,
-		Type: tokenType,
+		ID:     userID,
+		Role:   role,
+		Type:   tokenType,
+		Tenant: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.config.Issuer,
+			Audience:  audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
 		},
 	}
@@ -96,46 +111,53 @@ func (s *JWTService) GenerateJWTToken(userID int, tokenType string) (*AppToken,
 }
 
 func (s *JWTService) GetClaimsAndVerifyToken(tokenString string, tokenType string) (jwt.MapClaims, error) {
-	var secretKey string
+	return VerifyToken(s.config, tokenString, tokenType)
+}
+
+// VerifyToken checks the signature of a token and that it is a tokenType token issued by
+// cfg.Issuer for cfg.Audience that is currently valid. Tokens missing exp, iat, nbf, jti, iss
+// or aud are rejected, so a token minted for another service or by another issuer fails here.
+// Expired tokens fail with an Expired error, tokens of the wrong type with NotAuthorized.
+func VerifyToken(cfg config.JWT, tokenString string, tokenType string) (jwt.MapClaims, error) {
+	secretKey := cfg.AccessSecret
 	if tokenType == Refresh {
-		secretKey = s.config.RefreshSecret
-	} else {
-		secretKey = s.config.AccessSecret
+		secretKey = cfg.RefreshSecret
 	}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-			return nil, domainErrors.NewAppError(
-				fmt.Errorf("unexpected signing method: %v", token.Header["alg"]),
-				domainErrors.NotAuthenticated,
-			)
-		}
+	claims := jwt.MapClaims{}
+	// time claims are checked below with clockSkew, the parser would check them without
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
 		return []byte(secretKey), nil
-	})
-
-	if err != nil {
+	}); err != nil {
 		return nil, domainErrors.NewAppError(err, domainErrors.NotAuthenticated)
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return nil, domainErrors.NewAppError(errors.New("invalid claims type or token not valid"), domainErrors.NotAuthenticated)
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		if _, ok := claims["exp"].(float64); ok {
+			return nil, domainErrors.NewAppError(errors.New("token expired"), domainErrors.Expired)
+		}
+		return nil, invalidClaim("exp")
 	}
-
-	if claims["type"] != tokenType {
-		return nil, domainErrors.NewAppError(errors.New("invalid token type"), domainErrors.NotAuthenticated)
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), true) {
+		return nil, invalidClaim("iat")
 	}
-
-	expVal, ok := claims["exp"]
-	if !ok || expVal == nil {
-		return nil, domainErrors.NewAppError(errors.New("token missing expiration (exp) claim"), domainErrors.NotAuthenticated)
+	if !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), true) {
+		return nil, invalidClaim("nbf")
+	}
+	if jti, _ := claims["jti"].(string); jti == "" {
+		return nil, invalidClaim("jti")
+	}
+	if cfg.Issuer == "" || !claims.VerifyIssuer(cfg.Issuer, true) {
+		return nil, invalidClaim("iss")
 	}
-	timeExpire, ok := expVal.(float64)
-	if !ok {
-		return nil, domainErrors.NewAppError(errors.New("token exp claim is not a float64"), domainErrors.NotAuthenticated)
+	if cfg.Audience == "" || !claims.VerifyAudience(cfg.Audience, true) {
+		return nil, invalidClaim("aud")
 	}
-	if time.Now().Unix() > int64(timeExpire) {
-		return nil, domainErrors.NewAppError(errors.New("token expired"), domainErrors.NotAuthenticated)
+
+	if claims["type"] != tokenType {
+		return nil, domainErrors.NewAppError(errors.New("invalid token type"), domainErrors.NotAuthorized)
 	}
 
 	idVal, ok := claims["id"]
@@ -151,18 +173,15 @@ func (s *JWTService) GetClaimsAndVerifyToken(tokenString string, tokenType strin
 	return claims, nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// ClaimTenant returns the tenant of verified claims, tenant.Default for tokens issued before
+// tenants existed
+func ClaimTenant(claims jwt.MapClaims) string {
+	if id, ok := claims["tenant"].(string); ok && id != "" {
+		return id
 	}
-	return defaultValue
+	return tenant.Default
 }
 
-func getEnvAsInt64OrDefault(key string, defaultValue int64) int64 {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
+func invalidClaim(name string) error {
+	return domainErrors.NewAppError(fmt.Errorf("token %s claim is missing or invalid", name), domainErrors.NotAuthenticated)
 }
@@ -0,0 +1,133 @@
+package security
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signed builds an access token for benchmarkJWT from the claims of a valid one, with
+// change applied
+func signed(t *testing.T, method jwt.SigningMethod, change func(jwt.MapClaims)) string {
+	t.Helper()
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"id":   7,
+		"role": RoleCustomer,
+		"type": Access,
+		"jti":  "5d0c1f3e-4a1b-4c55-9d38-1b0e2f7c9a10",
+		"iss":  benchmarkJWT.Issuer,
+		"aud":  []string{"order"},
+		"iat":  now.Unix(),
+		"nbf":  now.Unix(),
+		"exp":  now.Add(time.Hour).Unix(),
+	}
+	if change != nil {
+		change(claims)
+	}
+	var key any = []byte(benchmarkJWT.AccessSecret)
+	if method == jwt.SigningMethodNone {
+		key = jwt.UnsafeAllowNoneSignatureType
+	}
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func without(name string) func(jwt.MapClaims) {
+	return func(c jwt.MapClaims) { delete(c, name) }
+}
+
+func with(name string, value any) func(jwt.MapClaims) {
+	return func(c jwt.MapClaims) { c[name] = value }
+}
+
+func TestVerifyToken(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		token   string
+		tokType string
+		wantErr error
+	}{
+		{name: "valid token", token: signed(t, jwt.SigningMethodHS256, nil), tokType: Access},
+		{name: "clock skew is tolerated", token: signed(t, jwt.SigningMethodHS256, with("iat", now.Add(10*time.Second).Unix())), tokType: Access},
+		{name: "expired", token: signed(t, jwt.SigningMethodHS256, with("exp", now.Add(-time.Minute).Unix())), tokType: Access, wantErr: domainErrors.ErrExpired},
+		{name: "missing exp", token: signed(t, jwt.SigningMethodHS256, without("exp")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "missing iat", token: signed(t, jwt.SigningMethodHS256, without("iat")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "issued in the future", token: signed(t, jwt.SigningMethodHS256, with("iat", now.Add(time.Hour).Unix())), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "missing nbf", token: signed(t, jwt.SigningMethodHS256, without("nbf")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "not yet valid", token: signed(t, jwt.SigningMethodHS256, with("nbf", now.Add(time.Hour).Unix())), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "missing jti", token: signed(t, jwt.SigningMethodHS256, without("jti")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "missing iss", token: signed(t, jwt.SigningMethodHS256, without("iss")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "other issuer", token: signed(t, jwt.SigningMethodHS256, with("iss", "someone-else")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "missing aud", token: signed(t, jwt.SigningMethodHS256, without("aud")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "other audience", token: signed(t, jwt.SigningMethodHS256, with("aud", []string{"payment"})), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "wrong type", token: signed(t, jwt.SigningMethodHS256, nil), tokType: Refresh, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "refresh token used for access", token: signed(t, jwt.SigningMethodHS256, with("type", Refresh)), tokType: Access, wantErr: domainErrors.ErrNotAuthorized},
+		{name: "missing id", token: signed(t, jwt.SigningMethodHS256, without("id")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "non-numeric id", token: signed(t, jwt.SigningMethodHS256, with("id", "7")), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "unsigned token", token: signed(t, jwt.SigningMethodNone, nil), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+		{name: "other algorithm", token: signed(t, jwt.SigningMethodHS512, nil), tokType: Access, wantErr: domainErrors.ErrNotAuthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyToken(benchmarkJWT, tt.token, tt.tokType)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateJWTTokenClaims(t *testing.T) {
+	s := NewJWTService(benchmarkJWT)
+	first, err := s.GenerateJWTToken(7, RoleCustomer, "acme", Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.GenerateJWTToken(7, RoleCustomer, "acme", Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := VerifyToken(benchmarkJWT, first.Token, Access)
+	if err != nil {
+		t.Fatalf("issued token does not verify: %v", err)
+	}
+	again, err := VerifyToken(benchmarkJWT, second.Token, Access)
+	if err != nil {
+		t.Fatalf("issued token does not verify: %v", err)
+	}
+	if claims["jti"] == again["jti"] {
+		t.Errorf("two tokens share jti %v", claims["jti"])
+	}
+	if got := ClaimTenant(claims); got != "acme" {
+		t.Errorf("tenant = %q, want acme", got)
+	}
+
+	// a refresh token is only for the issuing service
+	refresh, err := s.GenerateJWTToken(7, RoleCustomer, "", Refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := benchmarkJWT
+	other.Audience = "user"
+	if _, err := VerifyToken(other, refresh.Token, Refresh); !errors.Is(err, domainErrors.ErrNotAuthenticated) {
+		t.Errorf("refresh token verified for %q: %v", other.Audience, err)
+	}
+	if _, err := VerifyToken(benchmarkJWT, refresh.Token, Refresh); err != nil {
+		t.Errorf("refresh token does not verify for the issuing service: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+)
+
+var benchmarkJWT = config.JWT{AccessSecret: "benchmark-access-secret", RefreshSecret: "benchmark-refresh-secret",
+	AccessTime: time.Hour, RefreshTime: 24 * time.Hour, Issuer: "user", Audience: "order", AccessAudiences: []string{"user", "order"}}
+
+// BenchmarkGenerateJWTToken issues the access token every sign-in and refresh returns
+func BenchmarkGenerateJWTToken(b *testing.B) {
+	b.ReportAllocs()
+	s := NewJWTService(benchmarkJWT)
+	for b.Loop() {
+		if _, err := s.GenerateJWTToken(7, RoleCustomer, "", Access); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkVerifyToken verifies the token every authenticated request carries
+func BenchmarkVerifyToken(b *testing.B) {
+	b.ReportAllocs()
+	token, err := NewJWTService(benchmarkJWT).GenerateJWTToken(7, RoleCustomer, "", Access)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, err := VerifyToken(benchmarkJWT, token.Token, Access); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package security
+
+import "slices"
+
+// Permission names an action a role may perform. Services check permissions rather than
+// role names so new roles only need an entry in rolePermissions.
+type Permission string
+
+const (
+	PermOrderReadAny   Permission = "order:read_any"
+	PermOrderCancelOwn Permission = "order:cancel_own"
+	PermOrderCancelAny Permission = "order:cancel_any"
+	PermOrderMarkPaid  Permission = "order:mark_paid"
+	PermOrderShip      Permission = "order:ship"
+	PermOrderDeliver   Permission = "order:deliver"
+	// PermOrderReview approves or rejects orders held by the risk checks and manages the blocklist
+	PermOrderReview Permission = "order:review"
+	// PermOrderUpdateAny covers status changes no narrower permission describes
+	PermOrderUpdateAny Permission = "order:update_any"
+
+	// PermCatalogWrite creates, updates and deletes categories and products
+	PermCatalogWrite Permission = "catalog:write"
+	PermUserReadAny  Permission = "user:read_any"
+	// PermUserManage creates, updates and deletes any user account, including roles
+	PermUserManage Permission = "user:manage"
+	// PermAuditRead reads the audit trail of every service
+	PermAuditRead Permission = "audit:read"
+	// PermAuthzManage reads and changes the authorization policies of every service
+	PermAuthzManage Permission = "authz:manage"
+	// PermTenantManage provisions and suspends the stores of the deployment; only admins of the
+	// default tenant may use it
+	PermTenantManage Permission = "tenant:manage"
+	// PermBackupManage exports and restores the data of every service; only admins of the
+	// default tenant may use it
+	PermBackupManage Permission = "backup:manage"
+
+	// PermPaymentReadAny reads the payments and refunds of every user
+	PermPaymentReadAny Permission = "payment:read_any"
+	PermPaymentRefund  Permission = "payment:refund"
+
+	// PermReviewModerate works the review moderation queue and removes any review
+	PermReviewModerate Permission = "review:moderate"
+
+	// PermWebhookManage subscribes webhooks and manages their deliveries
+	PermWebhookManage Permission = "webhook:manage"
+	// PermWebhookManageAny sees and manages every user's webhook subscriptions
+	PermWebhookManageAny Permission = "webhook:manage_any"
+
+	// PermSagaOperate lists every saga with its step log and retries stuck ones
+	PermSagaOperate Permission = "saga:operate"
+
+	// PermMediaManage sees and deletes every user's uploaded files
+	PermMediaManage Permission = "media:manage"
+
+	// PermPromotionManage creates and changes promotions and customer segments
+	PermPromotionManage Permission = "promotion:manage"
+
+	// PermTaxManage maintains jurisdictions and tax rates
+	PermTaxManage Permission = "tax:manage"
+)
+
+var orderStaffPermissions = []Permission{
+	PermOrderReadAny, PermOrderCancelOwn, PermOrderCancelAny, PermOrderMarkPaid, PermOrderShip, PermOrderDeliver, PermOrderUpdateAny, PermOrderReview,
+}
+
+var rolePermissions = map[string][]Permission{
+	RoleCustomer:    {PermOrderCancelOwn},
+	RoleFulfillment: {PermOrderReadAny, PermOrderShip, PermOrderDeliver},
+	RoleStaff:       slices.Concat(orderStaffPermissions, []Permission{PermCatalogWrite, PermUserReadAny, PermPaymentReadAny, PermReviewModerate, PermWebhookManage, PermSagaOperate, PermMediaManage, PermPromotionManage}),
+	RoleAdmin: slices.Concat(orderStaffPermissions, []Permission{PermCatalogWrite, PermUserReadAny, PermUserManage, PermAuditRead, PermAuthzManage, PermTenantManage, PermBackupManage,
+		PermPaymentReadAny, PermPaymentRefund, PermReviewModerate, PermWebhookManage, PermWebhookManageAny, PermSagaOperate, PermMediaManage, PermPromotionManage, PermTaxManage}),
+}
+
+// HasPermission reports whether the role grants the permission
+func HasPermission(role string, p Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRole reports whether the role is one rolePermissions knows
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
@@ -0,0 +1,64 @@
+// Package tenant carries the store a request belongs to. The gateway resolves it from the host
+// or the X-Tenant-ID header and forwards it in the header; services put it in the request
+// context, where repositories, caches and outgoing clients read it.
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+const (
+	// Header carries the tenant from the gateway to the services and between services
+	Header = "X-Tenant-ID"
+	// Default is the tenant of single-store deployments and of every row created before
+	// tenants existed
+	Default = "default"
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Valid reports whether id is a tenant ID: a lowercase slug of at most 63 characters, so it is
+// safe in hosts, headers, cache keys and log fields
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx scoped to the tenant
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of ctx; "" is the system acting across tenants, e.g. a
+// scheduled job or a service-to-service call that sent no tenant
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so outgoing requests carry the
+// tenant of their context in Header
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripper{base: base}
+}
+
+type roundTripper struct {
+	base http.RoundTripper
+}
+
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// a RoundTripper must not change the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return t.base.RoundTrip(req)
+}
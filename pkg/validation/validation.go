@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+)
+
+var (
+	skuPattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	currencyPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+type rule struct {
+	tag     string
+	pattern *regexp.Regexp
+	message string
+}
+
+// rules are the project-specific tags usable in binding struct tags
+var rules = []rule{
+	{tag: "sku", pattern: skuPattern, message: "{0} must be a valid SKU (letters, digits, '-' or '_', up to 64 characters)"},
+	{tag: "slug", pattern: slugPattern, message: "{0} must be a lower-case slug such as 'running-shoes'"},
+	{tag: "currency", pattern: currencyPattern, message: "{0} must be a three-letter ISO 4217 currency code"},
+	{tag: "phone", pattern: phonePattern, message: "{0} must be a phone number in E.164 format, e.g. +6281234567890"},
+}
+
+var (
+	once        sync.Once
+	registerErr error
+	translator  ut.Translator
+)
+
+// Register installs the custom rules, JSON field names and English messages on the validator
+// gin uses for binding. It is safe to call more than once.
+func Register() error {
+	once.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			registerErr = errors.New("gin binding validator is not go-playground/validator")
+			return
+		}
+		registerErr = register(v)
+	})
+	return registerErr
+}
+
+func register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(jsonName)
+
+	locale := en.New()
+	translator, _ = ut.New(locale, locale).GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(v, translator); err != nil {
+		return fmt.Errorf("registering validation translations: %w", err)
+	}
+
+	for _, r := range rules {
+		pattern := r.pattern
+		if err := v.RegisterValidation(r.tag, func(fl validator.FieldLevel) bool {
+			return pattern.MatchString(fl.Field().String())
+		}); err != nil {
+			return fmt.Errorf("registering %s rule: %w", r.tag, err)
+		}
+		if err := v.RegisterTranslation(r.tag, translator, addTranslation(r.tag, r.message), translate); err != nil {
+			return fmt.Errorf("registering %s translation: %w", r.tag, err)
+		}
+	}
+	return nil
+}
+
+func jsonName(fld reflect.StructField) string {
+	name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return fld.Name
+	}
+	return name
+}
+
+func addTranslation(tag, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translate(trans ut.Translator, fe validator.FieldError) string {
+	msg, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return msg
+}
+
+// BindingError wraps an error from controllers.BindJSON and friends as a ValidationError carrying
+// one detail entry per invalid field. Errors that already are an AppError, such as an oversized
+// body, are returned unchanged.
+func BindingError(err error) *domainErrors.AppError {
+	var appErr *domainErrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return domainErrors.NewValidationError(err, FieldErrors(err)...)
+}
+
+// FieldErrors converts validator and JSON type errors into the field errors of the error
+// envelope. Other errors yield no details.
+func FieldErrors(err error) []domainErrors.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]domainErrors.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, domainErrors.FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Message: message(fe)})
+		}
+		return details
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []domainErrors.FieldError{{Field: typeErr.Field, Rule: "type", Message: typeErr.Field + " must be a " + typeErr.Type.String()}}
+	}
+	// encoding/json has no typed error for DisallowUnknownFields
+	if field, ok := strings.CutPrefix(err.Error(), `json: unknown field "`); ok {
+		field = strings.TrimSuffix(field, `"`)
+		return []domainErrors.FieldError{{Field: field, Rule: "unknown", Message: field + " is not a known field"}}
+	}
+	return nil
+}
+
+// fieldPath drops the request struct name, e.g. NewOrderRequest.items[0].quantity becomes
+// items[0].quantity
+func fieldPath(fe validator.FieldError) string {
+	path := fe.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	if translator == nil {
+		return fmt.Sprintf("%s failed the %s rule", fe.Field(), fe.Tag())
+	}
+	return fe.Translate(translator)
+}
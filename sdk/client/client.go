@@ -0,0 +1,271 @@
+// Package client is the HTTP runtime of the generated API clients: one package per service
+// (catalog, order, ...) and the gateway package combining them. They are generated from the
+// services' OpenAPI specs by cmd/sdkgen ("make sdk"); this package is written by hand.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API at BaseURL, the gateway or a single service, e.g. http://localhost:9090
+type Client struct {
+	baseURL string
+	token   string
+	header  http.Header
+	http    *http.Client
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with hc instead of a client with a 30 second timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithToken sends token as the bearer access token of every request
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHeader sends the header with every request, e.g. X-Internal-Token between services
+func WithHeader(name, value string) Option {
+	return func(c *Client) { c.header.Set(name, value) }
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{baseURL: strings.TrimRight(baseURL, "/"), header: http.Header{}, http: &http.Client{Timeout: 30 * time.Second}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithToken returns a copy of the client calling as the user token belongs to
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.header = c.header.Clone()
+	clone.token = token
+	return &clone
+}
+
+// Request is one API call; generated clients build it from their method's arguments
+type Request struct {
+	Method string
+	// Path is the path under the base URL, with its parameters escaped by Path
+	Path   string
+	Query  Query
+	Header http.Header
+	// Body is sent as JSON, unless Form is set
+	Body any
+	Form *Form
+}
+
+func NewRequest(method, path string) *Request {
+	return &Request{Method: method, Path: path, Query: Query{url.Values{}}, Header: http.Header{}}
+}
+
+// SetHeader sets an optional header, leaving it out when value is empty
+func (r *Request) SetHeader(name, value string) {
+	if value != "" {
+		r.Header.Set(name, value)
+	}
+}
+
+// Form is a multipart/form-data body with one file
+type Form struct {
+	Fields    map[string]string
+	FileField string
+	FileName  string
+	File      io.Reader
+}
+
+// Path fills the {parameters} of template with args, in order, escaping each
+func Path(template string, args ...any) string {
+	var b strings.Builder
+	for _, arg := range args {
+		start := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template, '}')
+		if start < 0 || end < start {
+			break
+		}
+		b.WriteString(template[:start])
+		b.WriteString(url.PathEscape(fmt.Sprint(arg)))
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// Query builds a query string. Optional parameters are left out at their zero value, or when
+// nil for booleans and numbers where zero is a meaningful filter.
+type Query struct {
+	url.Values
+}
+
+func (q Query) String(name, value string) {
+	if value != "" {
+		q.Set(name, value)
+	}
+}
+
+func (q Query) Int(name string, value int) {
+	if value != 0 {
+		q.Set(name, strconv.Itoa(value))
+	}
+}
+
+func (q Query) Float(name string, value *float64) {
+	if value != nil {
+		q.Set(name, strconv.FormatFloat(*value, 'f', -1, 64))
+	}
+}
+
+func (q Query) Bool(name string, value *bool) {
+	if value != nil {
+		q.Set(name, strconv.FormatBool(*value))
+	}
+}
+
+// Do sends the request and decodes a JSON response into out, unless out is nil. Responses
+// outside 2xx are returned as an *Error.
+func (c *Client) Do(ctx context.Context, r *Request, out any) error {
+	res, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: decoding the response: %w", r.Method, r.Path, err)
+	}
+	return nil
+}
+
+// Stream sends the request and returns the response body unread, for downloads and exports;
+// the caller closes it
+func (c *Client) Stream(ctx context.Context, r *Request) (io.ReadCloser, error) {
+	res, err := c.send(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (c *Client) send(ctx context.Context, r *Request) (*http.Response, error) {
+	body, contentType, err := r.encode()
+	if err != nil {
+		return nil, err
+	}
+	target := c.baseURL + r.Path
+	if len(r.Query.Values) > 0 {
+		target += "?" + r.Query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	for name, values := range r.Header {
+		req.Header[name] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+	defer func() { _ = res.Body.Close() }()
+	apiErr := &Error{Method: r.Method, Path: r.Path, Status: res.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	var errBody struct {
+		ErrorResponse
+		Errors []ErrorResponse `json:"errors"`
+	}
+	switch {
+	case json.Unmarshal(data, &errBody) != nil:
+		apiErr.Message = strings.TrimSpace(string(data))
+	case len(errBody.Errors) > 0:
+		apiErr.ErrorResponse = errBody.Errors[0]
+	default:
+		apiErr.ErrorResponse = errBody.ErrorResponse
+	}
+	return nil, apiErr
+}
+
+func (r *Request) encode() (io.Reader, string, error) {
+	if r.Form != nil {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for name, value := range r.Form.Fields {
+			if err := w.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+		}
+		if r.Form.File != nil {
+			part, err := w.CreateFormFile(r.Form.FileField, r.Form.FileName)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := io.Copy(part, r.Form.File); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return &buf, w.FormDataContentType(), nil
+	}
+	if r.Body == nil {
+		return nil, "", nil
+	}
+	data, err := json.Marshal(r.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(data), "application/json", nil
+}
+
+// Error is a response outside 2xx, with the error of its body: the first one of an
+// ErrorEnvelope
+type Error struct {
+	Method string
+	Path   string
+	Status int
+	ErrorResponse
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s %s: status %d: %s: %s", e.Method, e.Path, e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.Path, e.Status, e.Message)
+}
+
+// IsCode reports whether err is an API error with the code
+func IsCode(err error, code ErrorCode) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
@@ -0,0 +1,52 @@
+package client
+
+// The response shapes shared by every service, from pkg/errors, pkg/pagination and
+// pkg/controllers. The generated clients refer to these instead of declaring their own.
+
+type ErrorResponse struct {
+	Code    ErrorCode    `json:"code,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+	// ErrorID identifies a recovered panic in the logs, for users to quote when reporting it
+	ErrorID   string `json:"errorId,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Meta describes a response: the envelope version of the services answering in an Envelope, and
+// the page of a listing
+type Meta struct {
+	Version    int `json:"version,omitempty"`
+	Page       int `json:"page,omitempty"`
+	Limit      int `json:"limit,omitempty"`
+	Total      int `json:"total,omitempty"`
+	TotalPages int `json:"totalPages,omitempty"`
+}
+
+// Envelope is the body of the services answering with their result in data; the generated
+// methods return the data
+type Envelope[T any] struct {
+	Data T    `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+// ErrorEnvelope is the error body of the services answering in an Envelope
+type ErrorEnvelope struct {
+	Meta   Meta            `json:"meta"`
+	Errors []ErrorResponse `json:"errors"`
+}
+
+// Page is one page of a listing
+type Page[T any] struct {
+	Data []T  `json:"data"`
+	Meta Meta `json:"meta"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message,omitempty"`
+}
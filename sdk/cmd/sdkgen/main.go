@@ -0,0 +1,89 @@
+// Command sdkgen writes the API clients under sdk/ from the OpenAPI specs swag generates for
+// every service (services/<service>/docs/swagger.json, "make swagger"): a Go package and a
+// TypeScript module per service, plus the gateway client combining them.
+//
+//	sdkgen generate <services dir>   write the clients into the current directory (sdk/)
+//	sdkgen check <services dir>      fail when a committed client differs from its spec
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: sdkgen generate <services dir> | check <services dir>")
+		os.Exit(2)
+	}
+	files, err := generate(os.Args[2])
+	if err == nil {
+		switch os.Args[1] {
+		case "generate":
+			err = write(files)
+		case "check":
+			err = check(files)
+		default:
+			err = fmt.Errorf("unknown command %q", os.Args[1])
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate renders every client, by path under sdk/
+func generate(servicesDir string) (map[string][]byte, error) {
+	apis, err := loadAPIs(servicesDir)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	for _, a := range apis {
+		src, err := goClient(a)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Service, err)
+		}
+		files[filepath.Join(a.Service, a.Service+".go")] = src
+		files[filepath.Join("ts", "src", a.Service+".ts")] = tsClient(a)
+	}
+	src, err := goGateway(apis)
+	if err != nil {
+		return nil, err
+	}
+	files[filepath.Join("gateway", "gateway.go")] = src
+	files[filepath.Join("ts", "src", "index.ts")] = tsIndex(apis)
+	codes := errorCodes(apis)
+	if files[filepath.Join("client", "codes.go")], err = goCodes(codes); err != nil {
+		return nil, err
+	}
+	files[filepath.Join("ts", "src", "codes.ts")] = tsCodes(codes)
+	return files, nil
+}
+
+func write(files map[string][]byte) error {
+	for _, name := range sortedKeys(files) {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, files[name], 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func check(files map[string][]byte) error {
+	var errs []error
+	for _, name := range sortedKeys(files) {
+		committed, err := os.ReadFile(name)
+		if err != nil || !bytes.Equal(committed, files[name]) {
+			errs = append(errs, fmt.Errorf("%s is out of date, run make sdk", name))
+		}
+	}
+	return errors.Join(errs...)
+}
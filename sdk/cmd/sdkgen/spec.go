@@ -0,0 +1,553 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// swagger is the part of a Swagger 2.0 document swag writes that the clients are built from
+type swagger struct {
+	BasePath    string                           `json:"basePath"`
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+type operation struct {
+	OperationID string                         `json:"operationId"`
+	Summary     string                         `json:"summary"`
+	Description string                         `json:"description"`
+	Produces    []string                       `json:"produces"`
+	Consumes    []string                       `json:"consumes"`
+	Parameters  []parameter                    `json:"parameters"`
+	Responses   map[string]response            `json:"responses"`
+	Security    []map[string][]json.RawMessage `json:"security"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Type        string  `json:"type"`
+	Format      string  `json:"format"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AllOf                []*schema          `json:"allOf"`
+	Enum                 []string           `json:"enum"`
+	EnumVarNames         []string           `json:"x-enum-varnames"`
+	// Nullable is set by the x-nullable extension on fields a client must be able to send
+	// with their zero value, such as those of a partial update
+	Nullable bool `json:"x-nullable"`
+}
+
+// api is one service's spec in the form both generators render
+type api struct {
+	Service string
+	// Codes are the values of errors.ErrorCode
+	Codes      []enumValue
+	Types      []typeDef
+	Enums      []enumDef
+	Operations []op
+}
+
+type typeDef struct {
+	Name   string
+	Doc    string
+	Fields []field
+}
+
+type field struct {
+	JSON     string
+	Name     string
+	Doc      string
+	Type     *typ
+	Required bool
+}
+
+type enumDef struct {
+	Name   string
+	Values []enumValue
+}
+
+type enumValue struct {
+	Name  string
+	Value string
+}
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindInt64
+	kindFloat
+	kindBool
+	kindAny    // an object without declared properties
+	kindNamed  // a definition of the service
+	kindShared // a type of the client runtime, e.g. MessageResponse
+	kindArray
+	kindMap
+	kindPage
+	kindEnvelope // a result the service answers with in the data of a controllers.Envelope
+)
+
+type typ struct {
+	Kind kind
+	Name string
+	Elem *typ
+	// Optional marks a nested object or nullable field that may be absent, a pointer in Go
+	Optional bool
+}
+
+type op struct {
+	Name string
+	// TSName is Name in lower camel case
+	TSName      string
+	Summary     string
+	Description string
+	Method      string
+	// Path is the full path with basePath, e.g. /v1/product/{id}
+	Path      string
+	Auth      bool
+	PathArgs  []param
+	QueryArgs []param
+	FormArgs  []param
+	Body      *typ
+	// Params are the optional query parameters, headers and form fields
+	Params []param
+	File   *param
+	Result *typ
+	// Stream marks responses that are not JSON, such as downloads and exports
+	Stream bool
+}
+
+type param struct {
+	Wire string
+	Name string
+	// TSName is the wire name, in lower camel case when that is not an identifier
+	TSName string
+	In     string
+	Doc    string
+	Type   *typ
+}
+
+// shared are the definitions every service emits from pkg/errors, pkg/pagination and
+// pkg/controllers, with the properties the client runtime declares for them
+var shared = map[string][]string{
+	"errors.ErrorResponse":        {"code", "details", "errorId", "message", "requestId"},
+	"errors.FieldError":           {"field", "message", "rule"},
+	"errors.ErrorCode":            nil,
+	"pagination.Meta":             {"limit", "page", "total", "totalPages"},
+	"controllers.Meta":            {"limit", "page", "total", "totalPages", "version"},
+	"controllers.ErrorEnvelope":   {"errors", "meta"},
+	"controllers.MessageResponse": {"message"},
+}
+
+const (
+	pagePrefix     = "pagination.Response-"
+	envelopePrefix = "controllers.Envelope-"
+)
+
+// loadAPIs reads the spec of every service with one, sorted by service
+func loadAPIs(servicesDir string) ([]api, error) {
+	paths, err := filepath.Glob(filepath.Join(servicesDir, "*", "docs", "swagger.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no services/<service>/docs/swagger.json under %s", servicesDir)
+	}
+	slices.Sort(paths)
+	apis := make([]api, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var doc swagger
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		service := filepath.Base(filepath.Dir(filepath.Dir(path)))
+		a, err := convert(service, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		apis = append(apis, a)
+	}
+	return apis, nil
+}
+
+func convert(service string, doc *swagger) (api, error) {
+	a := api{Service: service}
+	names := map[string]string{}
+	for _, ref := range sortedKeys(doc.Definitions) {
+		def := doc.Definitions[ref]
+		if fields, ok := shared[ref]; ok {
+			if err := checkShared(ref, def, fields); err != nil {
+				return a, err
+			}
+			if len(def.Enum) > 0 {
+				a.Codes = enumOf(typeName(ref), def).Values
+			}
+			continue
+		}
+		if strings.HasPrefix(ref, pagePrefix) || strings.HasPrefix(ref, envelopePrefix) {
+			continue
+		}
+		name := typeName(ref)
+		if other, ok := names[name]; ok {
+			return a, fmt.Errorf("definitions %s and %s are both named %s", other, ref, name)
+		}
+		names[name] = ref
+		if len(def.Enum) > 0 {
+			a.Enums = append(a.Enums, enumOf(name, def))
+			continue
+		}
+		t := typeDef{Name: name, Doc: def.Description}
+		for _, prop := range sortedKeys(def.Properties) {
+			s := def.Properties[prop]
+			required := slices.Contains(def.Required, prop)
+			ft, err := typeOf(s, doc)
+			if err != nil {
+				return a, fmt.Errorf("%s.%s: %w", ref, prop, err)
+			}
+			if !required && (s.Nullable || ft.Kind == kindNamed && !isEnum(ft.Name, doc)) {
+				ft.Optional = true
+			}
+			t.Fields = append(t.Fields, field{JSON: prop, Name: exported(prop), Doc: s.Description, Type: ft, Required: required})
+		}
+		a.Types = append(a.Types, t)
+	}
+
+	methods := map[string]string{}
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			o, err := convertOperation(doc, doc.BasePath+path, method, doc.Paths[path][method])
+			if err != nil {
+				return a, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if other, ok := methods[o.Name]; ok {
+				return a, fmt.Errorf("%s and %s %s are both named %s; give one an @ID", other, strings.ToUpper(method), path, o.Name)
+			}
+			methods[o.Name] = strings.ToUpper(method) + " " + path
+			a.Operations = append(a.Operations, o)
+		}
+	}
+	slices.SortFunc(a.Operations, func(x, y op) int { return cmp.Compare(x.Name, y.Name) })
+	return a, nil
+}
+
+// checkShared fails when a service's error or pagination body gained a property the client
+// runtime does not declare, so sdk/client/types.go is updated with it
+func checkShared(ref string, def *schema, fields []string) error {
+	for prop := range def.Properties {
+		if !slices.Contains(fields, prop) {
+			return fmt.Errorf("%s has the property %q, add it to sdk/client/types.go", ref, prop)
+		}
+	}
+	return nil
+}
+
+func convertOperation(doc *swagger, path, method string, o *operation) (op, error) {
+	name := o.OperationID
+	if name == "" {
+		name = o.Summary
+	}
+	words := methodWords(name)
+	result := op{Name: exported(strings.Join(words, " ")), TSName: lowerCamel(words), Summary: o.Summary, Description: o.Description, Method: strings.ToUpper(method), Path: path,
+		Auth: len(o.Security) > 0}
+	if result.Name == "" {
+		return result, fmt.Errorf("no summary or @ID to name the method after")
+	}
+	for _, p := range o.Parameters {
+		pt, err := paramType(p, doc)
+		if err != nil {
+			return result, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		arg := param{Wire: p.Name, Name: exported(p.Name), TSName: tsIdent(p.Name), In: p.In, Doc: p.Description, Type: pt}
+		switch {
+		case p.In == "path":
+			result.PathArgs = append(result.PathArgs, arg)
+		case p.In == "body":
+			result.Body = pt
+		case p.In == "formData" && p.Type == "file":
+			result.File = &arg
+		case p.In == "query" && p.Required:
+			result.QueryArgs = append(result.QueryArgs, arg)
+		case p.In == "formData" && p.Required:
+			result.FormArgs = append(result.FormArgs, arg)
+		case p.In == "query" || p.In == "header" || p.In == "formData":
+			if p.In == "query" && (pt.Kind == kindBool || pt.Kind == kindFloat) {
+				pt.Optional = true
+			}
+			result.Params = append(result.Params, arg)
+		default:
+			return result, fmt.Errorf("parameters in %s are not supported", p.In)
+		}
+	}
+	// path parameters are passed in the order they appear in the path
+	slices.SortFunc(result.PathArgs, func(x, y param) int {
+		return cmp.Compare(strings.Index(path, "{"+x.Wire+"}"), strings.Index(path, "{"+y.Wire+"}"))
+	})
+
+	for _, code := range sortedKeys(o.Responses) {
+		s := o.Responses[code].Schema
+		if code[0] != '2' || s == nil {
+			continue
+		}
+		if s.Type == "file" || (len(o.Produces) > 0 && !slices.Contains(o.Produces, "application/json")) {
+			result.Stream = true
+			break
+		}
+		rt, err := typeOf(s, doc)
+		if err != nil {
+			return result, fmt.Errorf("response %s: %w", code, err)
+		}
+		if result.Result != nil && !sameType(result.Result, rt) {
+			return result, fmt.Errorf("responses return different types")
+		}
+		result.Result = rt
+	}
+	return result, nil
+}
+
+func paramType(p parameter, doc *swagger) (*typ, error) {
+	if p.Schema != nil {
+		return typeOf(p.Schema, doc)
+	}
+	if p.Type == "file" {
+		return &typ{Kind: kindString}, nil
+	}
+	return typeOf(&schema{Type: p.Type, Format: p.Format}, doc)
+}
+
+func typeOf(s *schema, doc *swagger) (*typ, error) {
+	if len(s.AllOf) == 1 {
+		t, err := typeOf(s.AllOf[0], doc)
+		if err != nil {
+			return nil, err
+		}
+		t.Optional = t.Kind == kindNamed
+		return t, nil
+	}
+	if s.Ref != "" {
+		ref := strings.TrimPrefix(s.Ref, "#/definitions/")
+		if _, ok := doc.Definitions[ref]; !ok {
+			return nil, fmt.Errorf("undefined %s", s.Ref)
+		}
+		if _, ok := shared[ref]; ok {
+			return &typ{Kind: kindShared, Name: typeName(ref)}, nil
+		}
+		if inner, ok := strings.CutPrefix(ref, pagePrefix); ok {
+			elem, err := pageElem(inner, doc)
+			if err != nil {
+				return nil, err
+			}
+			return &typ{Kind: kindPage, Elem: elem}, nil
+		}
+		if strings.HasPrefix(ref, envelopePrefix) {
+			return envelopeOf(ref, doc)
+		}
+		return &typ{Kind: kindNamed, Name: typeName(ref)}, nil
+	}
+	switch s.Type {
+	case "string":
+		return &typ{Kind: kindString}, nil
+	case "integer":
+		if s.Format == "int64" {
+			return &typ{Kind: kindInt64}, nil
+		}
+		return &typ{Kind: kindInt}, nil
+	case "number":
+		return &typ{Kind: kindFloat}, nil
+	case "boolean":
+		return &typ{Kind: kindBool}, nil
+	case "array":
+		if s.Items == nil {
+			return nil, fmt.Errorf("array without items")
+		}
+		elem, err := typeOf(s.Items, doc)
+		if err != nil {
+			return nil, err
+		}
+		return &typ{Kind: kindArray, Elem: elem}, nil
+	case "object", "":
+		var values schema
+		if len(s.AdditionalProperties) > 0 && json.Unmarshal(s.AdditionalProperties, &values) == nil && values.Type != "" {
+			elem, err := typeOf(&values, doc)
+			if err != nil {
+				return nil, err
+			}
+			return &typ{Kind: kindMap, Elem: elem}, nil
+		}
+		if len(s.Properties) > 0 {
+			return nil, fmt.Errorf("inline objects are not supported, declare a type")
+		}
+		return &typ{Kind: kindAny}, nil
+	}
+	return nil, fmt.Errorf("type %q is not supported", s.Type)
+}
+
+// pageElem is the element type of a pagination.Response-<type> definition, where swag writes
+// handler.ResponseOrder as handler_ResponseOrder
+func pageElem(inner string, doc *swagger) (*typ, error) {
+	if _, ok := doc.Definitions[strings.Replace(inner, "_", ".", 1)]; ok {
+		return typeOf(&schema{Ref: "#/definitions/" + strings.Replace(inner, "_", ".", 1)}, doc)
+	}
+	switch inner {
+	case "int":
+		return &typ{Kind: kindInt}, nil
+	case "string":
+		return &typ{Kind: kindString}, nil
+	}
+	return nil, fmt.Errorf("page of unknown type %s", inner)
+}
+
+// envelopeOf is the type of a controllers.Envelope-<type> definition: a page when its data is
+// a list, which comes with the page in meta, else the data
+func envelopeOf(ref string, doc *swagger) (*typ, error) {
+	data, ok := doc.Definitions[ref].Properties["data"]
+	if !ok {
+		return nil, fmt.Errorf("%s has no data", ref)
+	}
+	elem, err := typeOf(data, doc)
+	if err != nil {
+		return nil, err
+	}
+	if elem.Kind == kindArray {
+		return &typ{Kind: kindPage, Elem: elem.Elem}, nil
+	}
+	return &typ{Kind: kindEnvelope, Elem: elem}, nil
+}
+
+func enumOf(name string, def *schema) enumDef {
+	e := enumDef{Name: name}
+	for i, v := range def.Enum {
+		n := name + exported(strings.ToLower(v))
+		if i < len(def.EnumVarNames) {
+			n = def.EnumVarNames[i]
+		}
+		e.Values = append(e.Values, enumValue{Name: n, Value: v})
+	}
+	return e
+}
+
+func isEnum(name string, doc *swagger) bool {
+	for ref, def := range doc.Definitions {
+		if typeName(ref) == name && len(def.Enum) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sameType(x, y *typ) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Kind == y.Kind && x.Name == y.Name && sameType(x.Elem, y.Elem)
+}
+
+// errorCodes merges the errors.ErrorCode enums of every spec
+func errorCodes(apis []api) []enumValue {
+	var codes []enumValue
+	for _, a := range apis {
+		for _, c := range a.Codes {
+			if !slices.ContainsFunc(codes, func(v enumValue) bool { return v.Value == c.Value }) {
+				codes = append(codes, c)
+			}
+		}
+	}
+	return codes
+}
+
+// typeName drops the Go package of a definition: handler.ResponseOrder is ResponseOrder
+func typeName(ref string) string {
+	return ref[strings.LastIndexByte(ref, '.')+1:]
+}
+
+var initialisms = map[string]string{"Id": "ID", "Ids": "IDs", "Url": "URL", "Uuid": "UUID", "Sku": "SKU", "Ip": "IP", "Api": "API",
+	"Jwt": "JWT", "Http": "HTTP", "Csv": "CSV", "Json": "JSON"}
+
+var words = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+
+// exported turns a JSON name or summary into a Go identifier: categoryId is CategoryID
+func exported(s string) string {
+	var b strings.Builder
+	for _, w := range words.FindAllString(s, -1) {
+		w = string(unicode.ToUpper(rune(w[0]))) + w[1:]
+		if i, ok := initialisms[w]; ok {
+			w = i
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+var possessive = regexp.MustCompile(`'s\b`)
+
+// methodWords are the words an operation is named after, from its summary without articles and
+// possessives: "Get user's avatar" is GetUserAvatar
+func methodWords(summary string) []string {
+	var kept []string
+	for _, w := range strings.FieldsFunc(possessive.ReplaceAllString(summary, ""), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		switch strings.ToLower(w) {
+		case "a", "an", "the":
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return kept
+}
+
+// lowerCamel joins words into a TypeScript identifier: "Get product by ID" is getProductByID
+func lowerCamel(ws []string) string {
+	if len(ws) == 0 {
+		return ""
+	}
+	return strings.ToLower(ws[0]) + exported(strings.Join(ws[1:], " "))
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsIdent is name if it is an identifier, e.g. categoryId, or else its lower camel case form,
+// e.g. idempotencyKey for Idempotency-Key
+func tsIdent(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return lowerCamel(words.FindAllString(name, -1))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
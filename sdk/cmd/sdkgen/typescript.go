@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tsReserved are words a TypeScript parameter cannot be named, and the names methods use
+var tsReserved = []string{"body", "params", "file", "form", "break", "case", "catch", "class", "const", "continue", "debugger", "default",
+	"delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new",
+	"null", "return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with"}
+
+func tsClient(a api) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by sdkgen from services/%s/docs/swagger.json. DO NOT EDIT.\n\n", a.Service)
+	b.WriteString("import { ApiClient, path } from './client.js';\n")
+	if names := tsSharedNames(a); len(names) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from './client.js';\n", strings.Join(names, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, e := range a.Enums {
+		values := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			values[i] = tsString(v.Value)
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n\n", e.Name, strings.Join(values, " | "))
+	}
+	for _, t := range a.Types {
+		tsDoc(&b, "", t.Doc)
+		fmt.Fprintf(&b, "export interface %s {\n", t.Name)
+		for _, f := range t.Fields {
+			tsDoc(&b, "  ", f.Doc)
+			optional := "?"
+			if f.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(f.JSON), optional, tsType(f.Type))
+		}
+		b.WriteString("}\n\n")
+	}
+	for _, o := range a.Operations {
+		if len(o.Params) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "/** The optional parameters of %s */\nexport interface %sParams {\n", o.TSName, o.Name)
+		for _, p := range o.Params {
+			tsDoc(&b, "  ", p.Doc)
+			fmt.Fprintf(&b, "  %s?: %s;\n", p.TSName, tsType(p.Type))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&b, "/** Calls the %s service, directly or through the gateway */\n", a.Service)
+	fmt.Fprintf(&b, "export class %sClient {\n  constructor(private readonly api: ApiClient) {}\n", exported(a.Service))
+	for _, o := range a.Operations {
+		b.WriteString("\n")
+		tsOperation(&b, o)
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+func tsOperation(b *strings.Builder, o op) {
+	b.WriteString("  /**\n")
+	fmt.Fprintf(b, "   * %s: %s %s\n", strings.TrimSuffix(o.Summary, "."), o.Method, o.Path)
+	if o.Description != "" {
+		b.WriteString("   *\n")
+		for _, line := range strings.Split(strings.TrimSpace(o.Description), "\n") {
+			fmt.Fprintf(b, "   * %s\n", strings.TrimSpace(line))
+		}
+	}
+	if o.Auth {
+		b.WriteString("   *\n   * Needs an access token.\n")
+	}
+	b.WriteString("   */\n")
+
+	var args []string
+	for _, p := range append(slices.Clone(o.PathArgs), o.QueryArgs...) {
+		args = append(args, tsArg(p)+": "+tsType(p.Type))
+	}
+	if o.Body != nil {
+		args = append(args, "body: "+tsType(o.Body))
+	}
+	for _, p := range o.FormArgs {
+		args = append(args, tsArg(p)+": "+tsType(p.Type))
+	}
+	if o.File != nil {
+		args = append(args, "file: Blob")
+	}
+	if len(o.Params) > 0 {
+		args = append(args, "params: "+o.Name+"Params = {}")
+	}
+	result, body := "void", "void"
+	switch {
+	case o.Stream:
+		result = "Response"
+	case o.Result != nil && o.Result.Kind == kindEnvelope:
+		result, body = tsType(o.Result.Elem), tsType(o.Result)
+	case o.Result != nil:
+		result = tsType(o.Result)
+		body = result
+	}
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", o.TSName, strings.Join(args, ", "), result)
+
+	hasForm := o.File != nil || len(o.FormArgs) > 0 || slices.ContainsFunc(o.Params, func(p param) bool { return p.In == "formData" })
+	if hasForm {
+		b.WriteString("    const form = new FormData();\n")
+		if o.File != nil {
+			fmt.Fprintf(b, "    form.append(%s, file);\n", tsString(o.File.Wire))
+		}
+		for _, p := range o.FormArgs {
+			fmt.Fprintf(b, "    form.append(%s, %s);\n", tsString(p.Wire), tsArg(p))
+		}
+		for _, p := range o.Params {
+			if p.In == "formData" {
+				fmt.Fprintf(b, "    if (params.%s !== undefined) {\n      form.append(%s, params.%s);\n    }\n", p.TSName, tsString(p.Wire), p.TSName)
+			}
+		}
+	}
+
+	call := "request<" + body + ">"
+	if o.Stream {
+		call = "raw"
+	}
+	fmt.Fprintf(b, "    return this.api.%s({\n      method: '%s',\n", call, o.Method)
+	if len(o.PathArgs) > 0 {
+		names := make([]string, len(o.PathArgs))
+		for i, p := range o.PathArgs {
+			names[i] = tsArg(p)
+		}
+		fmt.Fprintf(b, "      path: path(%s, %s),\n", tsString(o.Path), strings.Join(names, ", "))
+	} else {
+		fmt.Fprintf(b, "      path: %s,\n", tsString(o.Path))
+	}
+	var query, headers []string
+	for _, p := range o.QueryArgs {
+		query = append(query, tsKey(p.Wire)+": "+tsArg(p))
+	}
+	for _, p := range o.Params {
+		switch p.In {
+		case "query":
+			query = append(query, tsKey(p.Wire)+": params."+p.TSName)
+		case "header":
+			headers = append(headers, tsKey(p.Wire)+": params."+p.TSName)
+		}
+	}
+	if len(query) > 0 {
+		fmt.Fprintf(b, "      query: { %s },\n", strings.Join(query, ", "))
+	}
+	if len(headers) > 0 {
+		fmt.Fprintf(b, "      headers: { %s },\n", strings.Join(headers, ", "))
+	}
+	if o.Body != nil {
+		b.WriteString("      body,\n")
+	}
+	if hasForm {
+		b.WriteString("      form,\n")
+	}
+	if o.Result != nil && o.Result.Kind == kindEnvelope {
+		b.WriteString("    }).then((res) => res.data);\n  }\n")
+		return
+	}
+	b.WriteString("    });\n  }\n")
+}
+
+func tsType(t *typ) string {
+	switch t.Kind {
+	case kindString:
+		return "string"
+	case kindInt, kindInt64, kindFloat:
+		return "number"
+	case kindBool:
+		return "boolean"
+	case kindAny:
+		return "Record<string, unknown>"
+	case kindNamed, kindShared:
+		return t.Name
+	case kindArray:
+		return tsType(t.Elem) + "[]"
+	case kindMap:
+		return "Record<string, " + tsType(t.Elem) + ">"
+	case kindPage:
+		return "Page<" + tsType(t.Elem) + ">"
+	case kindEnvelope:
+		return "Envelope<" + tsType(t.Elem) + ">"
+	}
+	panic(fmt.Sprintf("unknown kind %d", t.Kind))
+}
+
+// tsSharedNames are the client runtime types a service module refers to
+func tsSharedNames(a api) []string {
+	var names []string
+	var visit func(t *typ)
+	visit = func(t *typ) {
+		if t == nil {
+			return
+		}
+		switch t.Kind {
+		case kindShared:
+			names = append(names, t.Name)
+		case kindPage:
+			names = append(names, "Page")
+		case kindEnvelope:
+			names = append(names, "Envelope")
+		}
+		visit(t.Elem)
+	}
+	for _, t := range a.Types {
+		for _, f := range t.Fields {
+			visit(f.Type)
+		}
+	}
+	for _, o := range a.Operations {
+		visit(o.Body)
+		visit(o.Result)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func tsArg(p param) string {
+	if slices.Contains(tsReserved, p.TSName) {
+		return p.TSName + "Value"
+	}
+	return p.TSName
+}
+
+// tsKey quotes an object key that is not an identifier, e.g. 'Idempotency-Key'
+func tsKey(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+func tsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+func tsDoc(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, strings.TrimSpace(doc))
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, strings.TrimSpace(line))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+func tsIndex(apis []api) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by sdkgen from services/*/docs/swagger.json. DO NOT EDIT.\n\n")
+	b.WriteString("import { ApiClient } from './client.js';\nimport type { ClientOptions } from './client.js';\n")
+	for _, a := range apis {
+		fmt.Fprintf(&b, "import { %sClient } from './%s.js';\n", exported(a.Service), a.Service)
+	}
+	b.WriteString("\nexport * from './client.js';\nexport * from './codes.js';\n")
+	for _, a := range apis {
+		fmt.Fprintf(&b, "export * as %s from './%s.js';\n", a.Service, a.Service)
+	}
+	b.WriteString("\n/** Calls the whole API through the gateway: one client per service, sharing the base URL, access token and fetch */\n")
+	b.WriteString("export class GatewayClient {\n")
+	for _, a := range apis {
+		fmt.Fprintf(&b, "  readonly %s: %sClient;\n", a.Service, exported(a.Service))
+	}
+	b.WriteString("\n  constructor(readonly api: ApiClient) {\n")
+	for _, a := range apis {
+		fmt.Fprintf(&b, "    this.%s = new %sClient(api);\n", a.Service, exported(a.Service))
+	}
+	b.WriteString("  }\n\n")
+	b.WriteString("  /** Returns a client of the gateway at options.baseUrl, e.g. http://localhost:9090 */\n")
+	b.WriteString("  static create(options: ClientOptions): GatewayClient {\n    return new GatewayClient(new ApiClient(options));\n  }\n\n")
+	b.WriteString("  /** Returns a copy calling as the user token belongs to */\n")
+	b.WriteString("  withToken(token: string): GatewayClient {\n    return new GatewayClient(this.api.withToken(token));\n  }\n}\n")
+	return []byte(b.String())
+}
+
+func tsCodes(codes []enumValue) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by sdkgen from services/*/docs/swagger.json. DO NOT EDIT.\n\n")
+	b.WriteString("/** Identifies an error for clients, e.g. to tell an unknown email from a wrong password */\n")
+	b.WriteString("export type ErrorCode =\n")
+	for i, c := range codes {
+		end := ""
+		if i == len(codes)-1 {
+			end = ";"
+		}
+		fmt.Fprintf(&b, "  | %s%s\n", tsString(c.Value), end)
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,3 @@
+module ecommerce-microservice-go/sdk
+
+go 1.24.2
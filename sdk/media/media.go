@@ -0,0 +1,209 @@
+// Code generated by sdkgen from services/media/docs/swagger.json. DO NOT EDIT.
+
+// Package media is the client of the media service's API, called directly or through the gateway.
+package media
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"ecommerce-microservice-go/sdk/client"
+)
+
+// Client calls the media service
+type Client struct {
+	api *client.Client
+}
+
+func New(api *client.Client) *Client {
+	return &Client{api: api}
+}
+
+type ResponseFile struct {
+	Checksum    string `json:"checksum,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	ID          string `json:"id,omitempty"`
+	// OrphanedSince is set while nothing references the file; it is deleted after the grace period
+	OrphanedSince string   `json:"orphanedSince,omitempty"`
+	OwnerID       int      `json:"ownerId,omitempty"`
+	Purpose       string   `json:"purpose,omitempty"`
+	References    []string `json:"references,omitempty"`
+	Size          int      `json:"size,omitempty"`
+	// URL is set for public files; private ones are downloaded through a signed URL
+	URL      string            `json:"url,omitempty"`
+	Variants []ResponseVariant `json:"variants,omitempty"`
+	Width    int               `json:"width,omitempty"`
+}
+
+type ResponseSignedURL struct {
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type ResponseVariant struct {
+	ContentType string `json:"contentType,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Width       int    `json:"width,omitempty"`
+}
+
+// DeleteFile calls DELETE /v1/media/files/{id}: Delete a file.
+//
+// Deletes a file nothing references; files in use answer 409 with the references
+//
+// It needs an access token.
+func (c *Client) DeleteFile(ctx context.Context, id string) error {
+	req := client.NewRequest(http.MethodDelete, client.Path("/v1/media/files/{id}", id))
+	return c.api.Do(ctx, req, nil)
+}
+
+// DownloadFileParams are the optional parameters of DownloadFile
+type DownloadFileParams struct {
+	// Variant name, e.g. thumb
+	Variant string
+	// Expiry of a signed URL
+	Expires int
+	// Signature of a signed URL
+	Signature string
+}
+
+// DownloadFile calls GET /v1/media/files/{id}/content: Download a file.
+//
+// Public files (product images, avatars) are served to anyone and may be cached forever. Private files
+// need the expires and signature parameters of a signed URL.
+func (c *Client) DownloadFile(ctx context.Context, id string, params *DownloadFileParams) (io.ReadCloser, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/media/files/{id}/content", id))
+	if params != nil {
+		req.Query.String("variant", params.Variant)
+		req.Query.Int("expires", params.Expires)
+		req.Query.String("signature", params.Signature)
+	}
+	return c.api.Stream(ctx, req)
+}
+
+// GetFileDetailsParams are the optional parameters of GetFileDetails
+type GetFileDetailsParams struct {
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// GetFileDetails calls GET /v1/media/files/{id}: Get a file's details.
+//
+// It needs an access token.
+func (c *Client) GetFileDetails(ctx context.Context, id string, params *GetFileDetailsParams) (*ResponseFile, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/media/files/{id}", id))
+	if params != nil {
+		req.Query.String("fields", params.Fields)
+	}
+	var out ResponseFile
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSignedDownloadURLParams are the optional parameters of GetSignedDownloadURL
+type GetSignedDownloadURLParams struct {
+	// Variant name, e.g. thumb
+	Variant string
+	// Validity in seconds
+	Ttl int
+}
+
+// GetSignedDownloadURL calls GET /v1/media/files/{id}/signed-url: Get a signed download URL.
+//
+// A URL anyone holding it can download the file or variant from until it expires, for private files
+// such as invoices. With S3 storage the URL points at the bucket.
+//
+// It needs an access token.
+func (c *Client) GetSignedDownloadURL(ctx context.Context, id string, params *GetSignedDownloadURLParams) (*ResponseSignedURL, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/media/files/{id}/signed-url", id))
+	if params != nil {
+		req.Query.String("variant", params.Variant)
+		req.Query.Int("ttl", params.Ttl)
+	}
+	var out ResponseSignedURL
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserAvatarParams are the optional parameters of GetUserAvatar
+type GetUserAvatarParams struct {
+	// thumb or medium
+	Variant string
+}
+
+// GetUserAvatar calls GET /v1/media/avatars/{userId}: Get a user's avatar.
+//
+// Redirects to the user's current avatar
+func (c *Client) GetUserAvatar(ctx context.Context, userID int, params *GetUserAvatarParams) error {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/media/avatars/{userId}", userID))
+	if params != nil {
+		req.Query.String("variant", params.Variant)
+	}
+	return c.api.Do(ctx, req, nil)
+}
+
+// ListFilesParams are the optional parameters of ListFiles
+type ListFilesParams struct {
+	// product_image, avatar or invoice
+	Purpose string
+	// Owner, for media:manage
+	OwnerID int
+	// Page number
+	Page int
+	// Page size
+	Limit int
+	// Comma separated fields, prefix - for descending (createdAt, size)
+	Sort string
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// ListFiles calls GET /v1/media/files: List files.
+//
+// The caller's files, newest first; media:manage lists every user's
+//
+// It needs an access token.
+func (c *Client) ListFiles(ctx context.Context, params *ListFilesParams) (*client.Page[ResponseFile], error) {
+	req := client.NewRequest(http.MethodGet, "/v1/media/files")
+	if params != nil {
+		req.Query.String("purpose", params.Purpose)
+		req.Query.Int("ownerId", params.OwnerID)
+		req.Query.Int("page", params.Page)
+		req.Query.Int("limit", params.Limit)
+		req.Query.String("sort", params.Sort)
+		req.Query.String("fields", params.Fields)
+	}
+	var out client.Page[ResponseFile]
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadFile calls POST /v1/media/files: Upload a file.
+//
+// Stores a product image (needs catalog:write) or the caller's avatar, which replaces the previous one.
+// Images must be JPEG, PNG or GIF; they are re-encoded without metadata, turned upright and resized
+// into the purpose's variants. Product images are deleted unless a product uses their URL within the
+// grace period.
+//
+// It needs an access token.
+func (c *Client) UploadFile(ctx context.Context, purpose string, file io.Reader, fileName string) (*ResponseFile, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/media/files")
+	req.Form = &client.Form{Fields: map[string]string{"purpose": purpose}, FileField: "file", FileName: fileName, File: file}
+	var out ResponseFile
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,305 @@
+// Code generated by sdkgen from services/promotion/docs/swagger.json. DO NOT EDIT.
+
+// Package promotion is the client of the promotion service's API, called directly or through the gateway.
+package promotion
+
+import (
+	"context"
+	"net/http"
+
+	"ecommerce-microservice-go/sdk/client"
+)
+
+// Client calls the promotion service
+type Client struct {
+	api *client.Client
+}
+
+func New(api *client.Client) *Client {
+	return &Client{api: api}
+}
+
+type CartItemRequest struct {
+	ProductID int `json:"productId"`
+	Quantity  int `json:"quantity"`
+}
+
+type EvaluateRequest struct {
+	Currency    string            `json:"currency,omitempty"`
+	Items       []CartItemRequest `json:"items"`
+	ShippingFee float64           `json:"shippingFee,omitempty"`
+}
+
+type PromotionRequest struct {
+	Active      bool     `json:"active,omitempty"`
+	BuyQuantity int      `json:"buyQuantity,omitempty"`
+	CategoryIDs []int    `json:"categoryIds,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	Description string   `json:"description,omitempty"`
+	EndsAt      string   `json:"endsAt,omitempty"`
+	GetQuantity int      `json:"getQuantity,omitempty"`
+	MinSubtotal float64  `json:"minSubtotal,omitempty"`
+	Name        string   `json:"name"`
+	Percent     float64  `json:"percent,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	ProductIDs  []int    `json:"productIds,omitempty"`
+	Segments    []string `json:"segments,omitempty"`
+	Stacking    string   `json:"stacking,omitempty"`
+	StartsAt    string   `json:"startsAt,omitempty"`
+	Type        string   `json:"type"`
+}
+
+type ResponseApplied struct {
+	Amount      float64 `json:"amount,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	PromotionID string  `json:"promotionId,omitempty"`
+	Type        string  `json:"type,omitempty"`
+}
+
+type ResponseEvaluation struct {
+	Applied          []ResponseApplied `json:"applied,omitempty"`
+	Currency         string            `json:"currency,omitempty"`
+	DiscountAmount   float64           `json:"discountAmount,omitempty"`
+	Items            []ResponseLine    `json:"items,omitempty"`
+	ShippingDiscount float64           `json:"shippingDiscount,omitempty"`
+	ShippingFee      float64           `json:"shippingFee,omitempty"`
+	Subtotal         float64           `json:"subtotal,omitempty"`
+	Total            float64           `json:"total,omitempty"`
+}
+
+type ResponseLine struct {
+	DiscountAmount float64                `json:"discountAmount,omitempty"`
+	Discounts      []ResponseLineDiscount `json:"discounts,omitempty"`
+	Price          float64                `json:"price,omitempty"`
+	ProductID      int                    `json:"productId,omitempty"`
+	Quantity       int                    `json:"quantity,omitempty"`
+	Subtotal       float64                `json:"subtotal,omitempty"`
+}
+
+type ResponseLineDiscount struct {
+	Amount      float64 `json:"amount,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	PromotionID string  `json:"promotionId,omitempty"`
+}
+
+type ResponsePromotion struct {
+	Active      bool     `json:"active,omitempty"`
+	BuyQuantity int      `json:"buyQuantity,omitempty"`
+	CategoryIDs []int    `json:"categoryIds,omitempty"`
+	CreatedAt   string   `json:"createdAt,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	Description string   `json:"description,omitempty"`
+	EndsAt      string   `json:"endsAt,omitempty"`
+	GetQuantity int      `json:"getQuantity,omitempty"`
+	ID          string   `json:"id,omitempty"`
+	MinSubtotal float64  `json:"minSubtotal,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Percent     float64  `json:"percent,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	ProductIDs  []int    `json:"productIds,omitempty"`
+	Segments    []string `json:"segments,omitempty"`
+	Stacking    string   `json:"stacking,omitempty"`
+	StartsAt    string   `json:"startsAt,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	UpdatedAt   string   `json:"updatedAt,omitempty"`
+}
+
+type ResponseSegment struct {
+	Members int    `json:"members,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+type ResponseSegmentMembers struct {
+	Added int `json:"added,omitempty"`
+}
+
+type SegmentMembersRequest struct {
+	UserIDs []int `json:"userIds"`
+}
+
+// AddUsersToSegment calls POST /v1/promotions/segments/{segment}/members: Add users to a segment.
+//
+// Creates the segment when it has no members yet; users already in it are skipped
+//
+// It needs an access token.
+func (c *Client) AddUsersToSegment(ctx context.Context, segment string, body SegmentMembersRequest) (*ResponseSegmentMembers, error) {
+	req := client.NewRequest(http.MethodPost, client.Path("/v1/promotions/segments/{segment}/members", segment))
+	req.Body = body
+	var out ResponseSegmentMembers
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePromotion calls POST /v1/promotions: Create a promotion.
+//
+// category_discount takes percent off the items in categoryIds (or productIds). buy_x_get_y discounts
+// the cheapest getQuantity of every buyQuantity+getQuantity eligible units by percent (100 when
+// omitted). free_shipping takes percent (100 when omitted) off the shipping fee. minSubtotal, in
+// currency, and segments restrict who gets the promotion; startsAt and endsAt when.
+//
+// It needs an access token.
+func (c *Client) CreatePromotion(ctx context.Context, body PromotionRequest) (*ResponsePromotion, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/promotions")
+	req.Body = body
+	var out ResponsePromotion
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePromotion calls DELETE /v1/promotions/{id}: Delete a promotion.
+//
+// It needs an access token.
+func (c *Client) DeletePromotion(ctx context.Context, id string) error {
+	req := client.NewRequest(http.MethodDelete, client.Path("/v1/promotions/{id}", id))
+	return c.api.Do(ctx, req, nil)
+}
+
+// EvaluateCartPromotions calls POST /v1/promotions/evaluate: Evaluate the cart's promotions.
+//
+// Applies the live promotions the caller may use to a cart priced from the catalog and returns the
+// discounted lines and totals. Stackable promotions apply one after another by priority; an exclusive
+// promotion replaces them when it saves more. Checkout applies the same promotions again, so the quote
+// changes when prices or promotions do.
+//
+// It needs an access token.
+func (c *Client) EvaluateCartPromotions(ctx context.Context, body EvaluateRequest) (*ResponseEvaluation, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/promotions/evaluate")
+	req.Body = body
+	var out ResponseEvaluation
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPromotionParams are the optional parameters of GetPromotion
+type GetPromotionParams struct {
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// GetPromotion calls GET /v1/promotions/{id}: Get a promotion.
+//
+// It needs an access token.
+func (c *Client) GetPromotion(ctx context.Context, id string, params *GetPromotionParams) (*ResponsePromotion, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/promotions/{id}", id))
+	if params != nil {
+		req.Query.String("fields", params.Fields)
+	}
+	var out ResponsePromotion
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCustomerSegmentsParams are the optional parameters of ListCustomerSegments
+type ListCustomerSegmentsParams struct {
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// ListCustomerSegments calls GET /v1/promotions/segments: List customer segments.
+//
+// # Segments exist while they have members
+//
+// It needs an access token.
+func (c *Client) ListCustomerSegments(ctx context.Context, params *ListCustomerSegmentsParams) ([]ResponseSegment, error) {
+	req := client.NewRequest(http.MethodGet, "/v1/promotions/segments")
+	if params != nil {
+		req.Query.String("fields", params.Fields)
+	}
+	var out []ResponseSegment
+	err := c.api.Do(ctx, req, &out)
+	return out, err
+}
+
+// ListPromotionsParams are the optional parameters of ListPromotions
+type ListPromotionsParams struct {
+	// buy_x_get_y, category_discount or free_shipping
+	Type string
+	// Active or not
+	Active *bool
+	// stackable or exclusive
+	Stacking string
+	// Page number
+	Page int
+	// Page size
+	Limit int
+	// Comma separated fields, prefix - for descending (priority, createdAt, name)
+	Sort string
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// ListPromotions calls GET /v1/promotions: List promotions.
+//
+// It needs an access token.
+func (c *Client) ListPromotions(ctx context.Context, params *ListPromotionsParams) (*client.Page[ResponsePromotion], error) {
+	req := client.NewRequest(http.MethodGet, "/v1/promotions")
+	if params != nil {
+		req.Query.String("type", params.Type)
+		req.Query.Bool("active", params.Active)
+		req.Query.String("stacking", params.Stacking)
+		req.Query.Int("page", params.Page)
+		req.Query.Int("limit", params.Limit)
+		req.Query.String("sort", params.Sort)
+		req.Query.String("fields", params.Fields)
+	}
+	var out client.Page[ResponsePromotion]
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSegmentMembersParams are the optional parameters of ListSegmentMembers
+type ListSegmentMembersParams struct {
+	// Page number
+	Page int
+	// Page size
+	Limit int
+}
+
+// ListSegmentMembers calls GET /v1/promotions/segments/{segment}/members: List a segment's members.
+//
+// It needs an access token.
+func (c *Client) ListSegmentMembers(ctx context.Context, segment string, params *ListSegmentMembersParams) (*client.Page[int], error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/promotions/segments/{segment}/members", segment))
+	if params != nil {
+		req.Query.Int("page", params.Page)
+		req.Query.Int("limit", params.Limit)
+	}
+	var out client.Page[int]
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveUserFromSegment calls DELETE /v1/promotions/segments/{segment}/members/{userId}: Remove a user from a segment.
+//
+// It needs an access token.
+func (c *Client) RemoveUserFromSegment(ctx context.Context, segment string, userID int) error {
+	req := client.NewRequest(http.MethodDelete, client.Path("/v1/promotions/segments/{segment}/members/{userId}", segment, userID))
+	return c.api.Do(ctx, req, nil)
+}
+
+// ReplacePromotion calls PUT /v1/promotions/{id}: Replace a promotion.
+//
+// Takes the same body as creating one; carts evaluated afterwards see the change
+//
+// It needs an access token.
+func (c *Client) ReplacePromotion(ctx context.Context, id string, body PromotionRequest) (*ResponsePromotion, error) {
+	req := client.NewRequest(http.MethodPut, client.Path("/v1/promotions/{id}", id))
+	req.Body = body
+	var out ResponsePromotion
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
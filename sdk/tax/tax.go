@@ -0,0 +1,317 @@
+// Code generated by sdkgen from services/tax/docs/swagger.json. DO NOT EDIT.
+
+// Package tax is the client of the tax service's API, called directly or through the gateway.
+package tax
+
+import (
+	"context"
+	"net/http"
+
+	"ecommerce-microservice-go/sdk/client"
+)
+
+// Client calls the tax service
+type Client struct {
+	api *client.Client
+}
+
+func New(api *client.Client) *Client {
+	return &Client{api: api}
+}
+
+type CalculateRequest struct {
+	// At recalculates with the rates in force then, e.g. for a past order; now when omitted
+	At               string             `json:"at,omitempty"`
+	Currency         string             `json:"currency,omitempty"`
+	Destination      DestinationRequest `json:"destination"`
+	Lines            []LineRequest      `json:"lines"`
+	PricesIncludeTax bool               `json:"pricesIncludeTax,omitempty"`
+}
+
+type DestinationRequest struct {
+	Country    string `json:"country"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Region     string `json:"region,omitempty"`
+}
+
+type JurisdictionRequest struct {
+	Country string `json:"country"`
+	Name    string `json:"name"`
+	Region  string `json:"region,omitempty"`
+}
+
+type LineRequest struct {
+	Discount  float64 `json:"discount,omitempty"`
+	Quantity  int     `json:"quantity"`
+	Reference string  `json:"reference,omitempty"`
+	TaxClass  string  `json:"taxClass,omitempty"`
+	UnitPrice float64 `json:"unitPrice,omitempty"`
+}
+
+type RateRequest struct {
+	JurisdictionID string  `json:"jurisdictionId"`
+	Name           string  `json:"name"`
+	Rate           float64 `json:"rate,omitempty"`
+	TaxClass       string  `json:"taxClass,omitempty"`
+	// ValidFrom may be in the future to schedule a change; now when omitted
+	ValidFrom string `json:"validFrom,omitempty"`
+}
+
+type RenameJurisdictionRequest struct {
+	Name string `json:"name"`
+}
+
+type ResponseAppliedTax struct {
+	Amount       float64 `json:"amount,omitempty"`
+	Jurisdiction string  `json:"jurisdiction,omitempty"`
+	Name         string  `json:"name,omitempty"`
+	Rate         float64 `json:"rate,omitempty"`
+	RateID       string  `json:"rateId,omitempty"`
+}
+
+type ResponseCalculation struct {
+	At       string            `json:"at,omitempty"`
+	Currency string            `json:"currency,omitempty"`
+	Lines    []ResponseLineTax `json:"lines,omitempty"`
+	Provider string            `json:"provider,omitempty"`
+	Subtotal float64           `json:"subtotal,omitempty"`
+	TaxTotal float64           `json:"taxTotal,omitempty"`
+	Total    float64           `json:"total,omitempty"`
+}
+
+type ResponseJurisdiction struct {
+	Code      string `json:"code,omitempty"`
+	Country   string `json:"country,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Region    string `json:"region,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+type ResponseLineTax struct {
+	Reference     string               `json:"reference,omitempty"`
+	TaxAmount     float64              `json:"taxAmount,omitempty"`
+	TaxClass      string               `json:"taxClass,omitempty"`
+	TaxableAmount float64              `json:"taxableAmount,omitempty"`
+	Taxes         []ResponseAppliedTax `json:"taxes,omitempty"`
+}
+
+type ResponseRate struct {
+	CreatedAt    string                `json:"createdAt,omitempty"`
+	ID           string                `json:"id,omitempty"`
+	Jurisdiction *ResponseJurisdiction `json:"jurisdiction,omitempty"`
+	Name         string                `json:"name,omitempty"`
+	Rate         float64               `json:"rate,omitempty"`
+	TaxClass     string                `json:"taxClass,omitempty"`
+	ValidFrom    string                `json:"validFrom,omitempty"`
+	ValidTo      string                `json:"validTo,omitempty"`
+}
+
+// AddTaxRateVersion calls POST /v1/tax/rates: Add a tax rate version.
+//
+// Rates are never edited: a new version for the same jurisdiction and tax class ends the current one
+// at validFrom. validFrom may lie in the future to schedule a change, but not in the past once the
+// class has a rate, since that would change past calculations.
+//
+// It needs an access token.
+func (c *Client) AddTaxRateVersion(ctx context.Context, body RateRequest) (*ResponseRate, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/tax/rates")
+	req.Body = body
+	var out ResponseRate
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CalculateTax calls POST /v1/tax/calculate: Calculate tax.
+//
+// Taxes every line for the destination: the country's rate and its region's rate of the line's tax
+// class (standard when omitted) apply. Line amounts are quantity × unitPrice − discount. With
+// pricesIncludeTax the tax is taken out of the amounts instead of added. at recalculates with the rates
+// in force at that moment, so the tax of past orders can be reproduced.
+func (c *Client) CalculateTax(ctx context.Context, body CalculateRequest) (*ResponseCalculation, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/tax/calculate")
+	req.Body = body
+	var out ResponseCalculation
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateJurisdiction calls POST /v1/tax/jurisdictions: Create a jurisdiction.
+//
+// A country, or with region a state or province of it. Destinations are taxed by both their country's
+// and their region's jurisdiction.
+//
+// It needs an access token.
+func (c *Client) CreateJurisdiction(ctx context.Context, body JurisdictionRequest) (*ResponseJurisdiction, error) {
+	req := client.NewRequest(http.MethodPost, "/v1/tax/jurisdictions")
+	req.Body = body
+	var out ResponseJurisdiction
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteJurisdiction calls DELETE /v1/tax/jurisdictions/{id}: Delete a jurisdiction.
+//
+// Only jurisdictions without rates can be deleted; retire the rates of the others
+//
+// It needs an access token.
+func (c *Client) DeleteJurisdiction(ctx context.Context, id string) error {
+	req := client.NewRequest(http.MethodDelete, client.Path("/v1/tax/jurisdictions/{id}", id))
+	return c.api.Do(ctx, req, nil)
+}
+
+// GetJurisdictionParams are the optional parameters of GetJurisdiction
+type GetJurisdictionParams struct {
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// GetJurisdiction calls GET /v1/tax/jurisdictions/{id}: Get a jurisdiction.
+//
+// It needs an access token.
+func (c *Client) GetJurisdiction(ctx context.Context, id string, params *GetJurisdictionParams) (*ResponseJurisdiction, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/tax/jurisdictions/{id}", id))
+	if params != nil {
+		req.Query.String("fields", params.Fields)
+	}
+	var out ResponseJurisdiction
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaxRateVersionParams are the optional parameters of GetTaxRateVersion
+type GetTaxRateVersionParams struct {
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// GetTaxRateVersion calls GET /v1/tax/rates/{id}: Get a tax rate version.
+//
+// It needs an access token.
+func (c *Client) GetTaxRateVersion(ctx context.Context, id string, params *GetTaxRateVersionParams) (*ResponseRate, error) {
+	req := client.NewRequest(http.MethodGet, client.Path("/v1/tax/rates/{id}", id))
+	if params != nil {
+		req.Query.String("fields", params.Fields)
+	}
+	var out ResponseRate
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListJurisdictionsParams are the optional parameters of ListJurisdictions
+type ListJurisdictionsParams struct {
+	// ISO 3166 country code
+	Country string
+	// Region code
+	Region string
+	// Page number
+	Page int
+	// Page size
+	Limit int
+	// Comma separated fields, prefix - for descending (country, region, name, createdAt)
+	Sort string
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// ListJurisdictions calls GET /v1/tax/jurisdictions: List jurisdictions.
+//
+// It needs an access token.
+func (c *Client) ListJurisdictions(ctx context.Context, params *ListJurisdictionsParams) (*client.Page[ResponseJurisdiction], error) {
+	req := client.NewRequest(http.MethodGet, "/v1/tax/jurisdictions")
+	if params != nil {
+		req.Query.String("country", params.Country)
+		req.Query.String("region", params.Region)
+		req.Query.Int("page", params.Page)
+		req.Query.Int("limit", params.Limit)
+		req.Query.String("sort", params.Sort)
+		req.Query.String("fields", params.Fields)
+	}
+	var out client.Page[ResponseJurisdiction]
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTaxRatesParams are the optional parameters of ListTaxRates
+type ListTaxRatesParams struct {
+	// Jurisdiction ID
+	Jurisdiction string
+	// Tax class
+	TaxClass string
+	// RFC 3339 time
+	At string
+	// Page number
+	Page int
+	// Page size
+	Limit int
+	// Comma separated fields, prefix - for descending (validFrom, taxClass, rate)
+	Sort string
+	// Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object
+	Fields string
+}
+
+// ListTaxRates calls GET /v1/tax/rates: List tax rates.
+//
+// Lists every version by default, the rate history; at limits the list to the versions in force then
+//
+// It needs an access token.
+func (c *Client) ListTaxRates(ctx context.Context, params *ListTaxRatesParams) (*client.Page[ResponseRate], error) {
+	req := client.NewRequest(http.MethodGet, "/v1/tax/rates")
+	if params != nil {
+		req.Query.String("jurisdiction", params.Jurisdiction)
+		req.Query.String("taxClass", params.TaxClass)
+		req.Query.String("at", params.At)
+		req.Query.Int("page", params.Page)
+		req.Query.Int("limit", params.Limit)
+		req.Query.String("sort", params.Sort)
+		req.Query.String("fields", params.Fields)
+	}
+	var out client.Page[ResponseRate]
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RenameJurisdiction calls PUT /v1/tax/jurisdictions/{id}: Rename a jurisdiction.
+//
+// # Country and region cannot change, since past calculations name them
+//
+// It needs an access token.
+func (c *Client) RenameJurisdiction(ctx context.Context, id string, body RenameJurisdictionRequest) (*ResponseJurisdiction, error) {
+	req := client.NewRequest(http.MethodPut, client.Path("/v1/tax/jurisdictions/{id}", id))
+	req.Body = body
+	var out ResponseJurisdiction
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RetireTaxRateVersion calls DELETE /v1/tax/rates/{id}: Retire a tax rate version.
+//
+// Ends the version now, leaving the class untaxed until a new version starts. A version scheduled for
+// the future is removed instead and the one it would have ended continues.
+//
+// It needs an access token.
+func (c *Client) RetireTaxRateVersion(ctx context.Context, id string) (*ResponseRate, error) {
+	req := client.NewRequest(http.MethodDelete, client.Path("/v1/tax/rates/{id}", id))
+	var out ResponseRate
+	if err := c.api.Do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,158 @@
+// The HTTP runtime of the generated clients: one module per service (catalog, order, ...) and
+// the GatewayClient combining them in index.ts. They are generated from the services' OpenAPI
+// specs by sdk/cmd/sdkgen ("make sdk"); this module is written by hand.
+import type { ErrorCode } from './codes.js';
+
+export interface ClientOptions {
+  /** The gateway or a single service, e.g. http://localhost:9090 */
+  baseUrl: string;
+  /** Sent as the bearer access token of every request */
+  token?: string;
+  /** Sent with every request */
+  headers?: Record<string, string>;
+  /** Replaces the global fetch, e.g. in tests */
+  fetch?: typeof fetch;
+}
+
+export interface ApiRequest {
+  method: string;
+  /** The path under the base URL, with its parameters escaped by path() */
+  path: string;
+  /** Parameters left undefined are not sent */
+  query?: Record<string, string | number | boolean | undefined>;
+  headers?: Record<string, string | undefined>;
+  /** Sent as JSON, unless form is set */
+  body?: unknown;
+  form?: FormData;
+}
+
+export interface FieldError {
+  field?: string;
+  rule?: string;
+  message?: string;
+}
+
+/** An error the API answered with */
+export interface ErrorResponse {
+  code?: ErrorCode;
+  message?: string;
+  details?: FieldError[];
+  /** Identifies a recovered panic in the logs, for users to quote when reporting it */
+  errorId?: string;
+  requestId?: string;
+}
+
+/** Describes a response: the envelope version, and the page of a listing */
+export interface Meta {
+  version?: number;
+  page?: number;
+  limit?: number;
+  total?: number;
+  totalPages?: number;
+}
+
+/** The body of the services answering with their result in data; the generated methods return the data */
+export interface Envelope<T> {
+  data: T;
+  meta: Meta;
+}
+
+/** The error body of the services answering in an Envelope */
+export interface ErrorEnvelope {
+  meta: Meta;
+  errors: ErrorResponse[];
+}
+
+/** One page of a listing */
+export interface Page<T> {
+  data: T[];
+  meta: Meta;
+}
+
+export interface MessageResponse {
+  message?: string;
+}
+
+/** A response outside 2xx */
+export class ApiError extends Error {
+  constructor(
+    readonly method: string,
+    readonly path: string,
+    readonly status: number,
+    readonly body: ErrorResponse,
+  ) {
+    super(`${method} ${path}: status ${status}: ${body.code ? `${body.code}: ` : ''}${body.message ?? ''}`);
+    this.name = 'ApiError';
+  }
+
+  get code(): ErrorCode | undefined {
+    return this.body.code;
+  }
+}
+
+/** Fills the {parameters} of template with args, in order, escaping each */
+export function path(template: string, ...args: (string | number)[]): string {
+  let i = 0;
+  return template.replace(/\{[^}]+\}/g, (param) => (i < args.length ? encodeURIComponent(String(args[i++])) : param));
+}
+
+export class ApiClient {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions) {
+    this.options = { ...options, baseUrl: options.baseUrl.replace(/\/+$/, '') };
+  }
+
+  /** Returns a copy calling as the user token belongs to */
+  withToken(token: string): ApiClient {
+    return new ApiClient({ ...this.options, token });
+  }
+
+  /** Sends the request and decodes the JSON response; an empty response is undefined */
+  async request<T>(req: ApiRequest): Promise<T> {
+    const res = await this.raw(req);
+    const text = await res.text();
+    return (text === '' ? undefined : JSON.parse(text)) as T;
+  }
+
+  /** Sends the request and returns the response unread, for downloads and exports */
+  async raw(req: ApiRequest): Promise<Response> {
+    const headers: Record<string, string> = { ...this.options.headers };
+    for (const [name, value] of Object.entries(req.headers ?? {})) {
+      if (value !== undefined && value !== '') {
+        headers[name] = value;
+      }
+    }
+    if (this.options.token) {
+      headers.Authorization = `Bearer ${this.options.token}`;
+    }
+    let body: BodyInit | undefined;
+    if (req.form) {
+      body = req.form;
+    } else if (req.body !== undefined) {
+      body = JSON.stringify(req.body);
+      headers['Content-Type'] = 'application/json';
+    }
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(req.query ?? {})) {
+      if (value !== undefined && value !== '') {
+        query.set(name, String(value));
+      }
+    }
+    const qs = query.toString();
+    const url = `${this.options.baseUrl}${req.path}${qs ? `?${qs}` : ''}`;
+    const res = await (this.options.fetch ?? fetch)(url, { method: req.method, headers, body });
+    if (!res.ok) {
+      const text = await res.text();
+      let errorBody: ErrorResponse;
+      try {
+        const parsed = JSON.parse(text) as ErrorResponse & Partial<ErrorEnvelope>;
+        errorBody = parsed.errors?.[0] ?? parsed;
+      } catch {
+        errorBody = { message: text.trim() };
+      }
+      throw new ApiError(req.method, req.path, res.status, errorBody);
+    }
+    return res;
+  }
+}
@@ -0,0 +1,68 @@
+// Code generated by sdkgen from services/*/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient } from './client.js';
+import type { ClientOptions } from './client.js';
+import { CatalogClient } from './catalog.js';
+import { MediaClient } from './media.js';
+import { OrderClient } from './order.js';
+import { PaymentClient } from './payment.js';
+import { PromotionClient } from './promotion.js';
+import { ReviewClient } from './review.js';
+import { SagaClient } from './saga.js';
+import { SearchClient } from './search.js';
+import { TaxClient } from './tax.js';
+import { UserClient } from './user.js';
+import { WebhookClient } from './webhook.js';
+
+export * from './client.js';
+export * from './codes.js';
+export * as catalog from './catalog.js';
+export * as media from './media.js';
+export * as order from './order.js';
+export * as payment from './payment.js';
+export * as promotion from './promotion.js';
+export * as review from './review.js';
+export * as saga from './saga.js';
+export * as search from './search.js';
+export * as tax from './tax.js';
+export * as user from './user.js';
+export * as webhook from './webhook.js';
+
+/** Calls the whole API through the gateway: one client per service, sharing the base URL, access token and fetch */
+export class GatewayClient {
+  readonly catalog: CatalogClient;
+  readonly media: MediaClient;
+  readonly order: OrderClient;
+  readonly payment: PaymentClient;
+  readonly promotion: PromotionClient;
+  readonly review: ReviewClient;
+  readonly saga: SagaClient;
+  readonly search: SearchClient;
+  readonly tax: TaxClient;
+  readonly user: UserClient;
+  readonly webhook: WebhookClient;
+
+  constructor(readonly api: ApiClient) {
+    this.catalog = new CatalogClient(api);
+    this.media = new MediaClient(api);
+    this.order = new OrderClient(api);
+    this.payment = new PaymentClient(api);
+    this.promotion = new PromotionClient(api);
+    this.review = new ReviewClient(api);
+    this.saga = new SagaClient(api);
+    this.search = new SearchClient(api);
+    this.tax = new TaxClient(api);
+    this.user = new UserClient(api);
+    this.webhook = new WebhookClient(api);
+  }
+
+  /** Returns a client of the gateway at options.baseUrl, e.g. http://localhost:9090 */
+  static create(options: ClientOptions): GatewayClient {
+    return new GatewayClient(new ApiClient(options));
+  }
+
+  /** Returns a copy calling as the user token belongs to */
+  withToken(token: string): GatewayClient {
+    return new GatewayClient(this.api.withToken(token));
+  }
+}
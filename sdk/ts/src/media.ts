@@ -0,0 +1,194 @@
+// Code generated by sdkgen from services/media/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { Page } from './client.js';
+
+export interface ResponseFile {
+  checksum?: string;
+  contentType?: string;
+  createdAt?: string;
+  fileName?: string;
+  height?: number;
+  id?: string;
+  /** OrphanedSince is set while nothing references the file; it is deleted after the grace period */
+  orphanedSince?: string;
+  ownerId?: number;
+  purpose?: string;
+  references?: string[];
+  size?: number;
+  /** URL is set for public files; private ones are downloaded through a signed URL */
+  url?: string;
+  variants?: ResponseVariant[];
+  width?: number;
+}
+
+export interface ResponseSignedURL {
+  expiresAt?: string;
+  url?: string;
+}
+
+export interface ResponseVariant {
+  contentType?: string;
+  height?: number;
+  name?: string;
+  size?: number;
+  url?: string;
+  width?: number;
+}
+
+/** The optional parameters of downloadFile */
+export interface DownloadFileParams {
+  /** Variant name, e.g. thumb */
+  variant?: string;
+  /** Expiry of a signed URL */
+  expires?: number;
+  /** Signature of a signed URL */
+  signature?: string;
+}
+
+/** The optional parameters of getFileDetails */
+export interface GetFileDetailsParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of getSignedDownloadURL */
+export interface GetSignedDownloadURLParams {
+  /** Variant name, e.g. thumb */
+  variant?: string;
+  /** Validity in seconds */
+  ttl?: number;
+}
+
+/** The optional parameters of getUserAvatar */
+export interface GetUserAvatarParams {
+  /** thumb or medium */
+  variant?: string;
+}
+
+/** The optional parameters of listFiles */
+export interface ListFilesParams {
+  /** product_image, avatar or invoice */
+  purpose?: string;
+  /** Owner, for media:manage */
+  ownerId?: number;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (createdAt, size) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** Calls the media service, directly or through the gateway */
+export class MediaClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * Delete a file: DELETE /v1/media/files/{id}
+   *
+   * Deletes a file nothing references; files in use answer 409 with the references
+   *
+   * Needs an access token.
+   */
+  deleteFile(id: string): Promise<void> {
+    return this.api.request<void>({
+      method: 'DELETE',
+      path: path('/v1/media/files/{id}', id),
+    });
+  }
+
+  /**
+   * Download a file: GET /v1/media/files/{id}/content
+   *
+   * Public files (product images, avatars) are served to anyone and may be cached forever. Private files
+   * need the expires and signature parameters of a signed URL.
+   */
+  downloadFile(id: string, params: DownloadFileParams = {}): Promise<Response> {
+    return this.api.raw({
+      method: 'GET',
+      path: path('/v1/media/files/{id}/content', id),
+      query: { variant: params.variant, expires: params.expires, signature: params.signature },
+    });
+  }
+
+  /**
+   * Get a file's details: GET /v1/media/files/{id}
+   *
+   * Needs an access token.
+   */
+  getFileDetails(id: string, params: GetFileDetailsParams = {}): Promise<ResponseFile> {
+    return this.api.request<ResponseFile>({
+      method: 'GET',
+      path: path('/v1/media/files/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * Get a signed download URL: GET /v1/media/files/{id}/signed-url
+   *
+   * A URL anyone holding it can download the file or variant from until it expires, for private files
+   * such as invoices. With S3 storage the URL points at the bucket.
+   *
+   * Needs an access token.
+   */
+  getSignedDownloadURL(id: string, params: GetSignedDownloadURLParams = {}): Promise<ResponseSignedURL> {
+    return this.api.request<ResponseSignedURL>({
+      method: 'GET',
+      path: path('/v1/media/files/{id}/signed-url', id),
+      query: { variant: params.variant, ttl: params.ttl },
+    });
+  }
+
+  /**
+   * Get a user's avatar: GET /v1/media/avatars/{userId}
+   *
+   * Redirects to the user's current avatar
+   */
+  getUserAvatar(userId: number, params: GetUserAvatarParams = {}): Promise<void> {
+    return this.api.request<void>({
+      method: 'GET',
+      path: path('/v1/media/avatars/{userId}', userId),
+      query: { variant: params.variant },
+    });
+  }
+
+  /**
+   * List files: GET /v1/media/files
+   *
+   * The caller's files, newest first; media:manage lists every user's
+   *
+   * Needs an access token.
+   */
+  listFiles(params: ListFilesParams = {}): Promise<Page<ResponseFile>> {
+    return this.api.request<Page<ResponseFile>>({
+      method: 'GET',
+      path: '/v1/media/files',
+      query: { purpose: params.purpose, ownerId: params.ownerId, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * Upload a file: POST /v1/media/files
+   *
+   * Stores a product image (needs catalog:write) or the caller's avatar, which replaces the previous one.
+   * Images must be JPEG, PNG or GIF; they are re-encoded without metadata, turned upright and resized
+   * into the purpose's variants. Product images are deleted unless a product uses their URL within the
+   * grace period.
+   *
+   * Needs an access token.
+   */
+  uploadFile(purpose: string, file: Blob): Promise<ResponseFile> {
+    const form = new FormData();
+    form.append('file', file);
+    form.append('purpose', purpose);
+    return this.api.request<ResponseFile>({
+      method: 'POST',
+      path: '/v1/media/files',
+      form,
+    });
+  }
+}
@@ -0,0 +1,289 @@
+// Code generated by sdkgen from services/promotion/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { Page } from './client.js';
+
+export interface CartItemRequest {
+  productId: number;
+  quantity: number;
+}
+
+export interface EvaluateRequest {
+  currency?: string;
+  items: CartItemRequest[];
+  shippingFee?: number;
+}
+
+export interface PromotionRequest {
+  active?: boolean;
+  buyQuantity?: number;
+  categoryIds?: number[];
+  currency?: string;
+  description?: string;
+  endsAt?: string;
+  getQuantity?: number;
+  minSubtotal?: number;
+  name: string;
+  percent?: number;
+  priority?: number;
+  productIds?: number[];
+  segments?: string[];
+  stacking?: string;
+  startsAt?: string;
+  type: string;
+}
+
+export interface ResponseApplied {
+  amount?: number;
+  name?: string;
+  promotionId?: string;
+  type?: string;
+}
+
+export interface ResponseEvaluation {
+  applied?: ResponseApplied[];
+  currency?: string;
+  discountAmount?: number;
+  items?: ResponseLine[];
+  shippingDiscount?: number;
+  shippingFee?: number;
+  subtotal?: number;
+  total?: number;
+}
+
+export interface ResponseLine {
+  discountAmount?: number;
+  discounts?: ResponseLineDiscount[];
+  price?: number;
+  productId?: number;
+  quantity?: number;
+  subtotal?: number;
+}
+
+export interface ResponseLineDiscount {
+  amount?: number;
+  name?: string;
+  promotionId?: string;
+}
+
+export interface ResponsePromotion {
+  active?: boolean;
+  buyQuantity?: number;
+  categoryIds?: number[];
+  createdAt?: string;
+  currency?: string;
+  description?: string;
+  endsAt?: string;
+  getQuantity?: number;
+  id?: string;
+  minSubtotal?: number;
+  name?: string;
+  percent?: number;
+  priority?: number;
+  productIds?: number[];
+  segments?: string[];
+  stacking?: string;
+  startsAt?: string;
+  type?: string;
+  updatedAt?: string;
+}
+
+export interface ResponseSegment {
+  members?: number;
+  name?: string;
+}
+
+export interface ResponseSegmentMembers {
+  added?: number;
+}
+
+export interface SegmentMembersRequest {
+  userIds: number[];
+}
+
+/** The optional parameters of getPromotion */
+export interface GetPromotionParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listCustomerSegments */
+export interface ListCustomerSegmentsParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listPromotions */
+export interface ListPromotionsParams {
+  /** buy_x_get_y, category_discount or free_shipping */
+  type?: string;
+  /** Active or not */
+  active?: boolean;
+  /** stackable or exclusive */
+  stacking?: string;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (priority, createdAt, name) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listSegmentMembers */
+export interface ListSegmentMembersParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+}
+
+/** Calls the promotion service, directly or through the gateway */
+export class PromotionClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * Add users to a segment: POST /v1/promotions/segments/{segment}/members
+   *
+   * Creates the segment when it has no members yet; users already in it are skipped
+   *
+   * Needs an access token.
+   */
+  addUsersToSegment(segment: string, body: SegmentMembersRequest): Promise<ResponseSegmentMembers> {
+    return this.api.request<ResponseSegmentMembers>({
+      method: 'POST',
+      path: path('/v1/promotions/segments/{segment}/members', segment),
+      body,
+    });
+  }
+
+  /**
+   * Create a promotion: POST /v1/promotions
+   *
+   * category_discount takes percent off the items in categoryIds (or productIds). buy_x_get_y discounts
+   * the cheapest getQuantity of every buyQuantity+getQuantity eligible units by percent (100 when
+   * omitted). free_shipping takes percent (100 when omitted) off the shipping fee. minSubtotal, in
+   * currency, and segments restrict who gets the promotion; startsAt and endsAt when.
+   *
+   * Needs an access token.
+   */
+  createPromotion(body: PromotionRequest): Promise<ResponsePromotion> {
+    return this.api.request<ResponsePromotion>({
+      method: 'POST',
+      path: '/v1/promotions',
+      body,
+    });
+  }
+
+  /**
+   * Delete a promotion: DELETE /v1/promotions/{id}
+   *
+   * Needs an access token.
+   */
+  deletePromotion(id: string): Promise<void> {
+    return this.api.request<void>({
+      method: 'DELETE',
+      path: path('/v1/promotions/{id}', id),
+    });
+  }
+
+  /**
+   * Evaluate the cart's promotions: POST /v1/promotions/evaluate
+   *
+   * Applies the live promotions the caller may use to a cart priced from the catalog and returns the
+   * discounted lines and totals. Stackable promotions apply one after another by priority; an exclusive
+   * promotion replaces them when it saves more. Checkout applies the same promotions again, so the quote
+   * changes when prices or promotions do.
+   *
+   * Needs an access token.
+   */
+  evaluateCartPromotions(body: EvaluateRequest): Promise<ResponseEvaluation> {
+    return this.api.request<ResponseEvaluation>({
+      method: 'POST',
+      path: '/v1/promotions/evaluate',
+      body,
+    });
+  }
+
+  /**
+   * Get a promotion: GET /v1/promotions/{id}
+   *
+   * Needs an access token.
+   */
+  getPromotion(id: string, params: GetPromotionParams = {}): Promise<ResponsePromotion> {
+    return this.api.request<ResponsePromotion>({
+      method: 'GET',
+      path: path('/v1/promotions/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * List customer segments: GET /v1/promotions/segments
+   *
+   * Segments exist while they have members
+   *
+   * Needs an access token.
+   */
+  listCustomerSegments(params: ListCustomerSegmentsParams = {}): Promise<ResponseSegment[]> {
+    return this.api.request<ResponseSegment[]>({
+      method: 'GET',
+      path: '/v1/promotions/segments',
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * List promotions: GET /v1/promotions
+   *
+   * Needs an access token.
+   */
+  listPromotions(params: ListPromotionsParams = {}): Promise<Page<ResponsePromotion>> {
+    return this.api.request<Page<ResponsePromotion>>({
+      method: 'GET',
+      path: '/v1/promotions',
+      query: { type: params.type, active: params.active, stacking: params.stacking, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * List a segment's members: GET /v1/promotions/segments/{segment}/members
+   *
+   * Needs an access token.
+   */
+  listSegmentMembers(segment: string, params: ListSegmentMembersParams = {}): Promise<Page<number>> {
+    return this.api.request<Page<number>>({
+      method: 'GET',
+      path: path('/v1/promotions/segments/{segment}/members', segment),
+      query: { page: params.page, limit: params.limit },
+    });
+  }
+
+  /**
+   * Remove a user from a segment: DELETE /v1/promotions/segments/{segment}/members/{userId}
+   *
+   * Needs an access token.
+   */
+  removeUserFromSegment(segment: string, userId: number): Promise<void> {
+    return this.api.request<void>({
+      method: 'DELETE',
+      path: path('/v1/promotions/segments/{segment}/members/{userId}', segment, userId),
+    });
+  }
+
+  /**
+   * Replace a promotion: PUT /v1/promotions/{id}
+   *
+   * Takes the same body as creating one; carts evaluated afterwards see the change
+   *
+   * Needs an access token.
+   */
+  replacePromotion(id: string, body: PromotionRequest): Promise<ResponsePromotion> {
+    return this.api.request<ResponsePromotion>({
+      method: 'PUT',
+      path: path('/v1/promotions/{id}', id),
+      body,
+    });
+  }
+}
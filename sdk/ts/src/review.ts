@@ -0,0 +1,268 @@
+// Code generated by sdkgen from services/review/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { MessageResponse, Page } from './client.js';
+
+export interface ModerateReviewRequest {
+  note?: string;
+  status: string;
+}
+
+export interface NewReviewRequest {
+  body?: string;
+  /** Images are URLs of pictures the customer already uploaded */
+  images?: string[];
+  productId: number;
+  rating: number;
+  title?: string;
+}
+
+export interface ResponseRating {
+  average?: number;
+  count?: number;
+  /** Distribution counts the approved reviews per star, keyed "1" to "5" */
+  distribution?: Record<string, number>;
+  productId?: number;
+}
+
+export interface ResponseReview {
+  body?: string;
+  createdAt?: string;
+  helpfulCount?: number;
+  id?: number;
+  images?: string[];
+  moderationNote?: string;
+  productId?: number;
+  rating?: number;
+  status?: string;
+  title?: string;
+  updatedAt?: string;
+  userId?: number;
+  uuid?: string;
+  verifiedPurchase?: boolean;
+}
+
+export interface UpdateReviewRequest {
+  body?: string;
+  images?: string[];
+  rating?: number;
+  title?: string;
+}
+
+export interface VoteRequest {
+  helpful: boolean;
+}
+
+/** The optional parameters of getRatingSummaryOfProduct */
+export interface GetRatingSummaryOfProductParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of getReview */
+export interface GetReviewParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listApprovedReviewsOfProduct */
+export interface ListApprovedReviewsOfProductParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (createdAt, rating, helpful) */
+  sort?: string;
+  /** Filter by star rating */
+  rating?: number;
+  /** Filter by verified purchase */
+  verifiedPurchase?: boolean;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listMyReviews */
+export interface ListMyReviewsParams {
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (createdAt, rating, helpful) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listReviewsForModeration */
+export interface ListReviewsForModerationParams {
+  /** pending, approved or rejected */
+  status?: string;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (createdAt, rating) */
+  sort?: string;
+  /** Filter by product */
+  productId?: number;
+  /** Filter by verified purchase */
+  verifiedPurchase?: boolean;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** Calls the review service, directly or through the gateway */
+export class ReviewClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * Approve or reject a review: PUT /v1/review/{id}/moderation
+   *
+   * Moderators only. Changes to approved reviews update the product rating in the catalog.
+   *
+   * Needs an access token.
+   */
+  approveOrRejectReview(id: string, body: ModerateReviewRequest): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'PUT',
+      path: path('/v1/review/{id}/moderation', id),
+      body,
+    });
+  }
+
+  /**
+   * Delete review: DELETE /v1/review/{id}
+   *
+   * Authors delete their own reviews; moderators delete any review.
+   *
+   * Needs an access token.
+   */
+  deleteReview(id: string): Promise<MessageResponse> {
+    return this.api.request<MessageResponse>({
+      method: 'DELETE',
+      path: path('/v1/review/{id}', id),
+    });
+  }
+
+  /**
+   * Edit my review: PUT /v1/review/{id}
+   *
+   * The edited review goes back to the moderation queue.
+   *
+   * Needs an access token.
+   */
+  editMyReview(id: string, body: UpdateReviewRequest): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'PUT',
+      path: path('/v1/review/{id}', id),
+      body,
+    });
+  }
+
+  /**
+   * Get the rating summary of a product: GET /v1/review/product/{productId}/rating
+   */
+  getRatingSummaryOfProduct(productId: number, params: GetRatingSummaryOfProductParams = {}): Promise<ResponseRating> {
+    return this.api.request<ResponseRating>({
+      method: 'GET',
+      path: path('/v1/review/product/{productId}/rating', productId),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * Get review: GET /v1/review/{id}
+   *
+   * Only approved reviews are public; authors see their others under /review/mine.
+   */
+  getReview(id: string, params: GetReviewParams = {}): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'GET',
+      path: path('/v1/review/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * List the approved reviews of a product: GET /v1/review/product/{productId}
+   */
+  listApprovedReviewsOfProduct(productId: number, params: ListApprovedReviewsOfProductParams = {}): Promise<Page<ResponseReview>> {
+    return this.api.request<Page<ResponseReview>>({
+      method: 'GET',
+      path: path('/v1/review/product/{productId}', productId),
+      query: { page: params.page, limit: params.limit, sort: params.sort, rating: params.rating, verifiedPurchase: params.verifiedPurchase, fields: params.fields },
+    });
+  }
+
+  /**
+   * List my reviews: GET /v1/review/mine
+   *
+   * Includes reviews still waiting for moderation and rejected ones with the moderator's note.
+   *
+   * Needs an access token.
+   */
+  listMyReviews(params: ListMyReviewsParams = {}): Promise<Page<ResponseReview>> {
+    return this.api.request<Page<ResponseReview>>({
+      method: 'GET',
+      path: '/v1/review/mine',
+      query: { page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * List reviews for moderation: GET /v1/review/moderation
+   *
+   * Moderators only. Lists pending reviews, oldest first, unless another status is asked for.
+   *
+   * Needs an access token.
+   */
+  listReviewsForModeration(params: ListReviewsForModerationParams = {}): Promise<Page<ResponseReview>> {
+    return this.api.request<Page<ResponseReview>>({
+      method: 'GET',
+      path: '/v1/review/moderation',
+      query: { status: params.status, page: params.page, limit: params.limit, sort: params.sort, productId: params.productId, verifiedPurchase: params.verifiedPurchase, fields: params.fields },
+    });
+  }
+
+  /**
+   * Review a product: POST /v1/review/
+   *
+   * The review is published once a moderator approves it. It is marked as a verified purchase when the order service finds a paid order with the product.
+   *
+   * Needs an access token.
+   */
+  reviewProduct(body: NewReviewRequest): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'POST',
+      path: '/v1/review/',
+      body,
+    });
+  }
+
+  /**
+   * Vote on a review: PUT /v1/review/{id}/votes
+   *
+   * Voting again replaces the earlier vote. Authors cannot vote on their own reviews.
+   *
+   * Needs an access token.
+   */
+  voteOnReview(id: string, body: VoteRequest): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'PUT',
+      path: path('/v1/review/{id}/votes', id),
+      body,
+    });
+  }
+
+  /**
+   * Withdraw my vote on a review: DELETE /v1/review/{id}/votes
+   *
+   * Needs an access token.
+   */
+  withdrawMyVoteOnReview(id: string): Promise<ResponseReview> {
+    return this.api.request<ResponseReview>({
+      method: 'DELETE',
+      path: path('/v1/review/{id}/votes', id),
+    });
+  }
+}
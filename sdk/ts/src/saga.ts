@@ -0,0 +1,211 @@
+// Code generated by sdkgen from services/saga/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { Page } from './client.js';
+
+export interface SagaState {
+  amountDue?: number;
+  cancelledOrders?: number;
+  currency?: string;
+  orderId?: number;
+  orderNumber?: string;
+  paymentId?: string;
+  refundId?: string;
+  refundedAmount?: number;
+  reservationId?: string;
+}
+
+export interface AccountDeletionRequest {
+  /** UserID defaults to the caller; deleting another account needs user:manage */
+  userId?: number;
+}
+
+export interface RefundRequest {
+  /** Amount to refund; 0 refunds what is left of the payment */
+  amount?: number;
+  orderId?: number;
+  paymentId: string;
+  reason?: string;
+}
+
+export interface ResponseSaga {
+  attempts?: number;
+  createdAt?: string;
+  currentStep?: string;
+  failedDuring?: string;
+  failedStep?: string;
+  finishedAt?: string;
+  id?: number;
+  input?: Record<string, unknown>;
+  lastError?: string;
+  /** NextAttemptAt is set while the saga waits to retry a call */
+  nextAttemptAt?: string;
+  pivotReached?: boolean;
+  reference?: string;
+  startedBy?: number;
+  state?: SagaState;
+  status?: string;
+  stepDeadline?: string;
+  steps?: ResponseStep[];
+  type?: string;
+  updatedAt?: string;
+  userId?: number;
+}
+
+export interface ResponseStep {
+  attempt?: number;
+  createdAt?: string;
+  durationMs?: number;
+  error?: string;
+  phase?: string;
+  step?: string;
+  succeeded?: boolean;
+}
+
+/** The optional parameters of checkOutThroughSagaOrchestrator */
+export interface CheckOutThroughSagaOrchestratorParams {
+  /** Client-generated key, max 64 characters */
+  idempotencyKey?: string;
+}
+
+/** The optional parameters of deleteAccountThroughSagaOrchestrator */
+export interface DeleteAccountThroughSagaOrchestratorParams {
+  /** Client-generated key, max 64 characters */
+  idempotencyKey?: string;
+}
+
+/** The optional parameters of getSaga */
+export interface GetSagaParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listSagas */
+export interface ListSagasParams {
+  /** Only stuck sagas */
+  stuck?: boolean;
+  /** running, compensating, completed, compensated or failed */
+  status?: string;
+  /** checkout, refund or account_deletion */
+  type?: string;
+  /** Affected user */
+  userId?: number;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (id, createdAt, updatedAt) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of refundPaymentThroughSagaOrchestrator */
+export interface RefundPaymentThroughSagaOrchestratorParams {
+  /** Client-generated key, max 64 characters */
+  idempotencyKey?: string;
+}
+
+/** Calls the saga service, directly or through the gateway */
+export class SagaClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * Check out through the saga orchestrator: POST /v1/sagas/checkout
+   *
+   * Reserves the stock, creates the order, charges the card and confirms the order, cancelling the
+   * reservation, the order and the charge again when a step fails. The body is the order service's
+   * order body. Answers 200 once the saga completed or was rolled back (status compensated, see
+   * lastError), 202 while it waits to retry a step; poll GET /sagas/{id} then.
+   * Retrying with the same Idempotency-Key returns the first saga.
+   *
+   * Needs an access token.
+   */
+  checkOutThroughSagaOrchestrator(body: Record<string, unknown>, params: CheckOutThroughSagaOrchestratorParams = {}): Promise<ResponseSaga> {
+    return this.api.request<ResponseSaga>({
+      method: 'POST',
+      path: '/v1/sagas/checkout',
+      headers: { 'Idempotency-Key': params.idempotencyKey },
+      body,
+    });
+  }
+
+  /**
+   * Delete an account through the saga orchestrator: POST /v1/sagas/account-deletions
+   *
+   * Cancels the user's pending orders, then deletes the account. While the user has paid or shipped
+   * orders nothing is changed and the saga ends compensated with the reason in lastError.
+   *
+   * Needs an access token.
+   */
+  deleteAccountThroughSagaOrchestrator(body: AccountDeletionRequest, params: DeleteAccountThroughSagaOrchestratorParams = {}): Promise<ResponseSaga> {
+    return this.api.request<ResponseSaga>({
+      method: 'POST',
+      path: '/v1/sagas/account-deletions',
+      headers: { 'Idempotency-Key': params.idempotencyKey },
+      body,
+    });
+  }
+
+  /**
+   * Get a saga: GET /v1/sagas/{id}
+   *
+   * The saga with every call made for it. Customers see the sagas they started, operators every saga.
+   *
+   * Needs an access token.
+   */
+  getSaga(id: number, params: GetSagaParams = {}): Promise<ResponseSaga> {
+    return this.api.request<ResponseSaga>({
+      method: 'GET',
+      path: path('/v1/sagas/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * List sagas: GET /v1/sagas
+   *
+   * Sagas without their step log, newest first. stuck=true lists failed sagas and those still in progress
+   * longer than the configured threshold.
+   *
+   * Needs an access token.
+   */
+  listSagas(params: ListSagasParams = {}): Promise<Page<ResponseSaga>> {
+    return this.api.request<Page<ResponseSaga>>({
+      method: 'GET',
+      path: '/v1/sagas',
+      query: { stuck: params.stuck, status: params.status, type: params.type, userId: params.userId, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * Refund a payment through the saga orchestrator: POST /v1/sagas/refunds
+   *
+   * Refunds the payment and records the refund as a comment on the order
+   *
+   * Needs an access token.
+   */
+  refundPaymentThroughSagaOrchestrator(body: RefundRequest, params: RefundPaymentThroughSagaOrchestratorParams = {}): Promise<ResponseSaga> {
+    return this.api.request<ResponseSaga>({
+      method: 'POST',
+      path: '/v1/sagas/refunds',
+      headers: { 'Idempotency-Key': params.idempotencyKey },
+      body,
+    });
+  }
+
+  /**
+   * Retry a saga: POST /v1/sagas/{id}/retry
+   *
+   * Resumes a failed saga where it stopped, or a saga waiting to retry a step right away, with fresh
+   * attempts and a fresh step deadline. Fix the cause reported in lastError first.
+   *
+   * Needs an access token.
+   */
+  retrySaga(id: number): Promise<ResponseSaga> {
+    return this.api.request<ResponseSaga>({
+      method: 'POST',
+      path: path('/v1/sagas/{id}/retry', id),
+    });
+  }
+}
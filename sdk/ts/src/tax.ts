@@ -0,0 +1,291 @@
+// Code generated by sdkgen from services/tax/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { Page } from './client.js';
+
+export interface CalculateRequest {
+  /** At recalculates with the rates in force then, e.g. for a past order; now when omitted */
+  at?: string;
+  currency?: string;
+  destination: DestinationRequest;
+  lines: LineRequest[];
+  pricesIncludeTax?: boolean;
+}
+
+export interface DestinationRequest {
+  country: string;
+  postalCode?: string;
+  region?: string;
+}
+
+export interface JurisdictionRequest {
+  country: string;
+  name: string;
+  region?: string;
+}
+
+export interface LineRequest {
+  discount?: number;
+  quantity: number;
+  reference?: string;
+  taxClass?: string;
+  unitPrice?: number;
+}
+
+export interface RateRequest {
+  jurisdictionId: string;
+  name: string;
+  rate?: number;
+  taxClass?: string;
+  /** ValidFrom may be in the future to schedule a change; now when omitted */
+  validFrom?: string;
+}
+
+export interface RenameJurisdictionRequest {
+  name: string;
+}
+
+export interface ResponseAppliedTax {
+  amount?: number;
+  jurisdiction?: string;
+  name?: string;
+  rate?: number;
+  rateId?: string;
+}
+
+export interface ResponseCalculation {
+  at?: string;
+  currency?: string;
+  lines?: ResponseLineTax[];
+  provider?: string;
+  subtotal?: number;
+  taxTotal?: number;
+  total?: number;
+}
+
+export interface ResponseJurisdiction {
+  code?: string;
+  country?: string;
+  createdAt?: string;
+  id?: string;
+  name?: string;
+  region?: string;
+  updatedAt?: string;
+}
+
+export interface ResponseLineTax {
+  reference?: string;
+  taxAmount?: number;
+  taxClass?: string;
+  taxableAmount?: number;
+  taxes?: ResponseAppliedTax[];
+}
+
+export interface ResponseRate {
+  createdAt?: string;
+  id?: string;
+  jurisdiction?: ResponseJurisdiction;
+  name?: string;
+  rate?: number;
+  taxClass?: string;
+  validFrom?: string;
+  validTo?: string;
+}
+
+/** The optional parameters of getJurisdiction */
+export interface GetJurisdictionParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of getTaxRateVersion */
+export interface GetTaxRateVersionParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listJurisdictions */
+export interface ListJurisdictionsParams {
+  /** ISO 3166 country code */
+  country?: string;
+  /** Region code */
+  region?: string;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (country, region, name, createdAt) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listTaxRates */
+export interface ListTaxRatesParams {
+  /** Jurisdiction ID */
+  jurisdiction?: string;
+  /** Tax class */
+  taxClass?: string;
+  /** RFC 3339 time */
+  at?: string;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (validFrom, taxClass, rate) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** Calls the tax service, directly or through the gateway */
+export class TaxClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * Add a tax rate version: POST /v1/tax/rates
+   *
+   * Rates are never edited: a new version for the same jurisdiction and tax class ends the current one
+   * at validFrom. validFrom may lie in the future to schedule a change, but not in the past once the
+   * class has a rate, since that would change past calculations.
+   *
+   * Needs an access token.
+   */
+  addTaxRateVersion(body: RateRequest): Promise<ResponseRate> {
+    return this.api.request<ResponseRate>({
+      method: 'POST',
+      path: '/v1/tax/rates',
+      body,
+    });
+  }
+
+  /**
+   * Calculate tax: POST /v1/tax/calculate
+   *
+   * Taxes every line for the destination: the country's rate and its region's rate of the line's tax
+   * class (standard when omitted) apply. Line amounts are quantity × unitPrice − discount. With
+   * pricesIncludeTax the tax is taken out of the amounts instead of added. at recalculates with the rates
+   * in force at that moment, so the tax of past orders can be reproduced.
+   */
+  calculateTax(body: CalculateRequest): Promise<ResponseCalculation> {
+    return this.api.request<ResponseCalculation>({
+      method: 'POST',
+      path: '/v1/tax/calculate',
+      body,
+    });
+  }
+
+  /**
+   * Create a jurisdiction: POST /v1/tax/jurisdictions
+   *
+   * A country, or with region a state or province of it. Destinations are taxed by both their country's
+   * and their region's jurisdiction.
+   *
+   * Needs an access token.
+   */
+  createJurisdiction(body: JurisdictionRequest): Promise<ResponseJurisdiction> {
+    return this.api.request<ResponseJurisdiction>({
+      method: 'POST',
+      path: '/v1/tax/jurisdictions',
+      body,
+    });
+  }
+
+  /**
+   * Delete a jurisdiction: DELETE /v1/tax/jurisdictions/{id}
+   *
+   * Only jurisdictions without rates can be deleted; retire the rates of the others
+   *
+   * Needs an access token.
+   */
+  deleteJurisdiction(id: string): Promise<void> {
+    return this.api.request<void>({
+      method: 'DELETE',
+      path: path('/v1/tax/jurisdictions/{id}', id),
+    });
+  }
+
+  /**
+   * Get a jurisdiction: GET /v1/tax/jurisdictions/{id}
+   *
+   * Needs an access token.
+   */
+  getJurisdiction(id: string, params: GetJurisdictionParams = {}): Promise<ResponseJurisdiction> {
+    return this.api.request<ResponseJurisdiction>({
+      method: 'GET',
+      path: path('/v1/tax/jurisdictions/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * Get a tax rate version: GET /v1/tax/rates/{id}
+   *
+   * Needs an access token.
+   */
+  getTaxRateVersion(id: string, params: GetTaxRateVersionParams = {}): Promise<ResponseRate> {
+    return this.api.request<ResponseRate>({
+      method: 'GET',
+      path: path('/v1/tax/rates/{id}', id),
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * List jurisdictions: GET /v1/tax/jurisdictions
+   *
+   * Needs an access token.
+   */
+  listJurisdictions(params: ListJurisdictionsParams = {}): Promise<Page<ResponseJurisdiction>> {
+    return this.api.request<Page<ResponseJurisdiction>>({
+      method: 'GET',
+      path: '/v1/tax/jurisdictions',
+      query: { country: params.country, region: params.region, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * List tax rates: GET /v1/tax/rates
+   *
+   * Lists every version by default, the rate history; at limits the list to the versions in force then
+   *
+   * Needs an access token.
+   */
+  listTaxRates(params: ListTaxRatesParams = {}): Promise<Page<ResponseRate>> {
+    return this.api.request<Page<ResponseRate>>({
+      method: 'GET',
+      path: '/v1/tax/rates',
+      query: { jurisdiction: params.jurisdiction, taxClass: params.taxClass, at: params.at, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * Rename a jurisdiction: PUT /v1/tax/jurisdictions/{id}
+   *
+   * Country and region cannot change, since past calculations name them
+   *
+   * Needs an access token.
+   */
+  renameJurisdiction(id: string, body: RenameJurisdictionRequest): Promise<ResponseJurisdiction> {
+    return this.api.request<ResponseJurisdiction>({
+      method: 'PUT',
+      path: path('/v1/tax/jurisdictions/{id}', id),
+      body,
+    });
+  }
+
+  /**
+   * Retire a tax rate version: DELETE /v1/tax/rates/{id}
+   *
+   * Ends the version now, leaving the class untaxed until a new version starts. A version scheduled for
+   * the future is removed instead and the one it would have ended continues.
+   *
+   * Needs an access token.
+   */
+  retireTaxRateVersion(id: string): Promise<ResponseRate> {
+    return this.api.request<ResponseRate>({
+      method: 'DELETE',
+      path: path('/v1/tax/rates/{id}', id),
+    });
+  }
+}
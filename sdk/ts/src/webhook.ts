@@ -0,0 +1,158 @@
+// Code generated by sdkgen from services/webhook/docs/swagger.json. DO NOT EDIT.
+
+import { ApiClient, path } from './client.js';
+import type { Page } from './client.js';
+
+export interface NewWebhookRequest {
+  eventTypes: string[];
+  secret: string;
+  url: string;
+}
+
+export interface ResponseAttempt {
+  attempt?: number;
+  createdAt?: string;
+  durationMs?: number;
+  error?: string;
+  statusCode?: number;
+}
+
+export interface ResponseDelivery {
+  attemptLog?: ResponseAttempt[];
+  attempts?: number;
+  createdAt?: string;
+  deadLetteredAt?: string;
+  deliveredAt?: string;
+  eventId?: string;
+  eventType?: string;
+  id?: number;
+  lastError?: string;
+  nextAttemptAt?: string;
+  payload?: Record<string, unknown>;
+  redeliveries?: number;
+  status?: string;
+}
+
+export interface ResponseWebhook {
+  active?: boolean;
+  createdAt?: string;
+  eventTypes?: string[];
+  id?: number;
+  ownerId?: number;
+  updatedAt?: string;
+  url?: string;
+}
+
+/** The optional parameters of listWebhookDeliveries */
+export interface ListWebhookDeliveriesParams {
+  /** pending, succeeded or dead_lettered */
+  status?: string;
+  /** Event type */
+  eventType?: string;
+  /** Page number */
+  page?: number;
+  /** Page size */
+  limit?: number;
+  /** Comma separated fields, prefix - for descending (id, createdAt) */
+  sort?: string;
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** The optional parameters of listWebhooks */
+export interface ListWebhooksParams {
+  /** Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object */
+  fields?: string;
+}
+
+/** Calls the webhook service, directly or through the gateway */
+export class WebhookClient {
+  constructor(private readonly api: ApiClient) {}
+
+  /**
+   * List subscribable event types: GET /v1/webhooks/event-types
+   *
+   * Needs an access token.
+   */
+  listSubscribableEventTypes(): Promise<string[]> {
+    return this.api.request<string[]>({
+      method: 'GET',
+      path: '/v1/webhooks/event-types',
+    });
+  }
+
+  /**
+   * List webhook deliveries: GET /v1/webhooks/{webhookId}/deliveries
+   *
+   * Deliveries with every attempt made for them, newest first. Filter by status=dead_lettered for the dead letters.
+   *
+   * Needs an access token.
+   */
+  listWebhookDeliveries(webhookId: number, params: ListWebhookDeliveriesParams = {}): Promise<Page<ResponseDelivery>> {
+    return this.api.request<Page<ResponseDelivery>>({
+      method: 'GET',
+      path: path('/v1/webhooks/{webhookId}/deliveries', webhookId),
+      query: { status: params.status, eventType: params.eventType, page: params.page, limit: params.limit, sort: params.sort, fields: params.fields },
+    });
+  }
+
+  /**
+   * List webhooks: GET /v1/webhooks
+   *
+   * Staff see their own subscriptions, admins every subscription
+   *
+   * Needs an access token.
+   */
+  listWebhooks(params: ListWebhooksParams = {}): Promise<ResponseWebhook[]> {
+    return this.api.request<ResponseWebhook[]>({
+      method: 'GET',
+      path: '/v1/webhooks',
+      query: { fields: params.fields },
+    });
+  }
+
+  /**
+   * Redeliver a webhook event: POST /v1/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver
+   *
+   * Queues a dead-lettered or delivered event for immediate delivery with a fresh set of attempts
+   *
+   * Needs an access token.
+   */
+  redeliverWebhookEvent(webhookId: number, deliveryId: number): Promise<ResponseDelivery> {
+    return this.api.request<ResponseDelivery>({
+      method: 'POST',
+      path: path('/v1/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver', webhookId, deliveryId),
+    });
+  }
+
+  /**
+   * Subscribe a webhook: POST /v1/webhooks
+   *
+   * Events of the chosen types are POSTed to the URL as the broker envelope {id, type, source, occurredAt, payload},
+   * with an X-Webhook-Signature header "t=<unix>,v1=<hex HMAC-SHA256 of '<unix>.<body>' keyed by secret>".
+   * Failed deliveries are retried with exponential backoff and dead-lettered once the attempts run out.
+   *
+   * Needs an access token.
+   */
+  subscribeWebhook(body: NewWebhookRequest): Promise<ResponseWebhook> {
+    return this.api.request<ResponseWebhook>({
+      method: 'POST',
+      path: '/v1/webhooks',
+      body,
+    });
+  }
+
+  /**
+   * Unsubscribe a webhook: DELETE /v1/webhooks/{webhookId}
+   *
+   * Deactivates the subscription; pending deliveries are dead-lettered and the delivery log is kept
+   *
+   * Needs an access token.
+   */
+  unsubscribeWebhook(webhookId: number): Promise<void> {
+    return this.api.request<void>({
+      method: 'DELETE',
+      path: path('/v1/webhooks/{webhookId}', webhookId),
+    });
+  }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/services/catalog/repository"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	Features  featureflags.Config
+	Authz     authz.Config
+	Fixtures  fixtures.Config
+	Backup    backup.Config
+	Retention retention.Config
+	Currency  currency.Config
+	IDs       config.IDs
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Outbox    events.OutboxRelayConfig
+	// BrokerURL is optional; without it product ratings are not kept up to date and catalog
+	// events are only logged
+	BrokerURL      string
+	BrokerExchange string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8082", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "catalog", false),
+		Features:  featureflags.LoadConfig(src),
+		Authz:     authz.LoadConfig(src),
+		Fixtures:  fixtures.LoadConfig(src),
+		Backup:    backup.LoadConfig(src),
+		Retention: retention.LoadConfig(src, repository.RetentionRules),
+		Currency:  currency.LoadConfig(src),
+		IDs:       config.LoadIDs(src),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Outbox: events.OutboxRelayConfig{
+			Interval:  src.Duration("OUTBOX_RELAY_INTERVAL_MS", 2000, time.Millisecond),
+			BatchSize: src.Int("OUTBOX_RELAY_BATCH_SIZE", 100),
+		},
+		BrokerURL:      src.String("BROKER_URL", ""),
+		BrokerExchange: src.String("BROKER_EXCHANGE", events.DefaultExchange),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "catalog", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
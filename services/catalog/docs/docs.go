@@ -15,6 +15,73 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/catalog/exchange-rates": {
+            "get": {
+                "description": "Base currency units per unit of every supported currency. Rates come from the provider set with\nEXCHANGE_RATES_URL, refreshed every EXCHANGE_RATES_REFRESH_MINUTES, and from the fixed EXCHANGE_RATES.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Get exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseExchangeRates"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/exchange-rates/convert": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Convert an amount between currencies",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Amount in from",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of amount; the base currency when omitted",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert into",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseConversion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
         "/category/": {
             "get": {
                 "produces": [
@@ -24,14 +91,51 @@ const docTemplate = `{
                     "Category"
                 ],
                 "summary": "Get all categories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, name, slug, createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by slug",
+                        "name": "slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ResponseCategory"
-                            }
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -61,62 +165,108 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseCategory"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseCategory"
                         }
                     }
                 }
             }
         },
-        "/category/{id}": {
-            "get": {
+        "/category/bulk": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Each item holds the \"id\" of a category and the CategoryPatch fields to change. Up to 500 items are written in one transaction;\nby default one invalid or failed item leaves every category unchanged, with atomic=false the others are still updated.\nAnswers 200 when every item was updated and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Category"
                 ],
-                "summary": "Get category by ID",
+                "summary": "Update categories in bulk",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Roll back every item when one fails",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields of each category, plus its id",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseCategory"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkCategories"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkCategories"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Creates up to 500 categories in one transaction and reports every item. By default the request is atomic:\none invalid or failed item leaves every category unwritten. With atomic=false the other items are still created.\nAnswers 200 when every item was created and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Category"
                 ],
-                "summary": "Update category",
+                "summary": "Create categories in bulk",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Roll back every item when one fails",
+                        "name": "atomic",
+                        "in": "query"
                     },
                     {
-                        "description": "Fields",
+                        "description": "Categories",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.NewCategoryRequest"
+                            }
                         }
                     }
                 ],
@@ -124,142 +274,246 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseCategory"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkCategories"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkCategories"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/category/search": {
+            "post": {
+                "description": "likeFilters match name, description or slug; matches take exact slugs; dateRanges bound createdAt or updatedAt.\nValues of one field are ORed, fields are ANDed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
                     "Category"
                 ],
-                "summary": "Delete category",
+                "summary": "Search categories",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.DataFilters"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/product/": {
+        "/category/{id}": {
             "get": {
                 "tags": [
-                    "Product"
+                    "Category"
+                ],
+                "summary": "Get category by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all products",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ResponseProduct"
-                            }
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseCategory"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Create product",
+                "summary": "Delete category",
                 "parameters": [
                     {
-                        "description": "Product",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.NewProductRequest"
-                        }
+                        "type": "string",
+                        "description": "Category UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseProduct"
+                            "$ref": "#/definitions/controllers.Envelope-controllers_MessageResponse"
                         }
                     }
                 }
-            }
-        },
-        "/product/category/{categoryId}": {
-            "get": {
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partial update: send a JSON merge patch of the fields to change, or a JSON Patch (application/json-patch+json)\nof add, replace and remove operations on them. Only the fields of CategoryPatch can be changed.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Get products by category",
+                "summary": "Update category",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "categoryId",
+                        "type": "string",
+                        "description": "Category UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CategoryPatch"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ResponseProduct"
-                            }
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown, read-only or invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/product/{id}": {
+        "/product/": {
             "get": {
                 "tags": [
                     "Product"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get all products",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, name, price, stock, createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by SKU",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by category",
+                        "name": "categoryId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show prices in this currency, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: category",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseProduct"
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -268,23 +522,15 @@ const docTemplate = `{
                 "tags": [
                     "Product"
                 ],
-                "summary": "Update product",
+                "summary": "Create product",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Fields",
+                        "description": "Product",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handler.NewProductRequest"
                         }
                     }
                 ],
@@ -292,42 +538,577 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseProduct"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseProduct"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/product/bulk": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Each item holds the \"id\" of a product, the ProductPatch fields to change and optionally the \"version\" read, which fails the\nitem with a conflict if the product changed since. Up to 500 items are written in one transaction; by default\none invalid or failed item leaves every product unchanged, with atomic=false the others are still updated.\nAnswers 200 when every item was updated and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Product"
                 ],
-                "summary": "Delete product",
+                "summary": "Update products in bulk",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Roll back every item when one fails",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields of each product, plus its id and optional version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "additionalProperties": true
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkProducts"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkProducts"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates up to 500 products in one transaction and reports every item. By default the request is atomic:\none invalid or failed item leaves every product unwritten. With atomic=false the other items are still created.\nAnswers 200 when every item was created and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Create products in bulk",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Roll back every item when one fails",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Products",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.NewProductRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkProducts"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBulkProducts"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/category/{categoryId}": {
+            "get": {
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get products by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, name, price, stock, createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by SKU",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show prices in this currency, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: category",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams the catalog as CSV (one row per product) or NDJSON (one product per line), reading it in batches\nso large catalogs can be synced. Send updatedSince to fetch only what changed since the last sync.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Export products",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Only products of this category",
+                        "name": "categoryId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products changed at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "updatedSince",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also export inactive products",
+                        "name": "includeInactive",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/search": {
+            "post": {
+                "description": "likeFilters match name, description or sku; matches take exact sku or categoryId values; dateRanges bound\ncreatedAt or updatedAt. Values of one field are ORed, fields are ANDed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Search active products",
+                "parameters": [
+                    {
+                        "description": "Search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.DataFilters"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show prices in this currency, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: category",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/product/{id}": {
+            "get": {
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product UUID, or integer ID while ID_ALLOW_INTEGER is on",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show the price in this currency, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: category",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Delete product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-controllers_MessageResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partial update: send a JSON merge patch of the fields to change, or a JSON Patch (application/json-patch+json)\nof add, replace and remove operations on them. Only the fields of ProductPatch can be changed.\nSend the \"version\" of the product you read to update it only if nobody changed it since",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Update product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change, plus the optional version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProductPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown, read-only or invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "The product changed since that version",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.Envelope-array_handler_ResponseCategory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseCategory"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseProduct": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseProduct"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
-                        }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-controllers_MessageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.MessageResponse"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseBulkCategories": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseBulkCategories"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseBulkProducts": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseBulkProducts"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseCategory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseCategory"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseConversion": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseConversion"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseExchangeRates": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseExchangeRates"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseProduct": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseProduct"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.ErrorResponse"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "controllers.MessageResponse": {
             "type": "object",
             "properties": {
@@ -336,6 +1117,206 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.Meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DataFilters": {
+            "type": "object",
+            "properties": {
+                "dateRanges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DateRangeFilter"
+                    }
+                },
+                "likeFilters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "matches": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "sortBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sortDirection": {
+                    "$ref": "#/definitions/domain.SortDirection"
+                }
+            }
+        },
+        "domain.DateRangeFilter": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SortDirection": {
+            "type": "string",
+            "enum": [
+                "asc",
+                "desc"
+            ],
+            "x-enum-varnames": [
+                "SortAsc",
+                "SortDesc"
+            ]
+        },
+        "errors.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "NOT_FOUND",
+                "VALIDATION_FAILED",
+                "ALREADY_EXISTS",
+                "NOT_AUTHENTICATED",
+                "NOT_AUTHORIZED",
+                "EXPIRED",
+                "PAYMENT_DECLINED",
+                "CSRF_TOKEN_INVALID",
+                "VERSION_CONFLICT",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "SERVICE_TIMEOUT",
+                "PAYLOAD_TOO_LARGE",
+                "RATE_LIMITED",
+                "REQUEST_IN_PROGRESS",
+                "IDEMPOTENCY_KEY_REUSED",
+                "USER_NOT_FOUND",
+                "EMAIL_CONFLICT",
+                "SKU_CONFLICT",
+                "INVALID_TRANSITION",
+                "REVIEW_EXISTS",
+                "PURCHASE_REQUIRED",
+                "TENANT_NOT_FOUND",
+                "TENANT_MISMATCH"
+            ],
+            "x-enum-varnames": [
+                "CodeNotFound",
+                "CodeValidation",
+                "CodeAlreadyExists",
+                "CodeNotAuthenticated",
+                "CodeNotAuthorized",
+                "CodeExpired",
+                "CodePaymentDeclined",
+                "CodeCSRFTokenInvalid",
+                "CodeVersionConflict",
+                "CodeInternal",
+                "CodeServiceUnavailable",
+                "CodeServiceTimeout",
+                "CodePayloadTooLarge",
+                "CodeRateLimited",
+                "CodeRequestInProgress",
+                "CodeIdempotencyKeyReused",
+                "CodeUserNotFound",
+                "CodeEmailConflict",
+                "CodeSKUConflict",
+                "CodeInvalidTransition",
+                "CodeReviewExists",
+                "CodePurchaseRequired",
+                "CodeTenantNotFound",
+                "CodeTenantMismatch"
+            ]
+        },
+        "errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/errors.ErrorCode"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.FieldError"
+                    }
+                },
+                "errorId": {
+                    "description": "ErrorID identifies a recovered panic in the logs, for users to quote when reporting it",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                }
+            }
+        },
+        "errors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CategoryPatch": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "x-nullable": true
+                },
+                "slug": {
+                    "type": "string",
+                    "x-nullable": true
+                }
+            }
+        },
         "handler.NewCategoryRequest": {
             "type": "object",
             "required": [
@@ -389,6 +1370,123 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.ProductPatch": {
+            "type": "object",
+            "properties": {
+                "categoryId": {
+                    "type": "integer",
+                    "x-nullable": true
+                },
+                "description": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "imageUrl": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "isActive": {
+                    "type": "boolean",
+                    "x-nullable": true
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "x-nullable": true
+                },
+                "price": {
+                    "type": "number",
+                    "x-nullable": true
+                },
+                "sku": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "x-nullable": true
+                },
+                "version": {
+                    "type": "integer",
+                    "x-nullable": true
+                }
+            }
+        },
+        "handler.ResponseBulkCategories": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseBulkCategory"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseBulkCategory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseCategory"
+                },
+                "error": {
+                    "$ref": "#/definitions/errors.ErrorResponse"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseBulkProduct": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseProduct"
+                },
+                "error": {
+                    "$ref": "#/definitions/errors.ErrorResponse"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseBulkProducts": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseBulkProduct"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
         "handler.ResponseCategory": {
             "type": "object",
             "properties": {
@@ -409,6 +1507,60 @@ const docTemplate = `{
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseConversion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseExchangeRates": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string"
+                },
+                "fetchedAt": {
+                    "description": "FetchedAt is when the provider's rates were fetched; absent when only fixed rates are used",
+                    "type": "string"
+                },
+                "rates": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "handler.ResponseLocalPrice": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate is how many base currency units one unit of Currency was worth",
+                    "type": "number"
                 }
             }
         },
@@ -421,6 +1573,9 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -433,12 +1588,26 @@ const docTemplate = `{
                 "isActive": {
                     "type": "boolean"
                 },
+                "localPrice": {
+                    "description": "LocalPrice is Price converted into the currency asked for with ?currency=",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ResponseLocalPrice"
+                        }
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
                 "price": {
                     "type": "number"
                 },
+                "ratingAverage": {
+                    "type": "number"
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
                 "sku": {
                     "type": "string"
                 },
@@ -447,6 +1616,20 @@ const docTemplate = `{
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "category": {
+                    "description": "Category is the product's category, inlined with ?expand=category",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ResponseCategory"
+                        }
+                    ]
                 }
             }
         }
@@ -4,6 +4,8 @@ import "time"
 
 type Category struct {
 	ID          int
+	UUID        string
+	Tenant      string
 	Name        string
 	Description string
 	Slug        string
@@ -13,6 +15,8 @@ type Category struct {
 
 type Product struct {
 	ID          int
+	UUID        string
+	Tenant      string
 	Name        string
 	Description string
 	SKU         string
@@ -21,6 +25,60 @@ type Product struct {
 	CategoryID  int
 	ImageURL    string
 	IsActive    bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// RatingAverage and RatingCount summarize approved reviews and are kept by the review service
+	RatingAverage float64
+	RatingCount   int
+	Version       int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CategoryUpdate is one item of a bulk category update: the fields to set on the category
+type CategoryUpdate struct {
+	ID     int
+	Fields map[string]interface{}
+}
+
+// ProductUpdate is one item of a bulk product update: the fields to set on the product while it
+// is still at Version, or whatever version it is at when Version is zero
+type ProductUpdate struct {
+	ID      int
+	Version int
+	Fields  map[string]interface{}
+}
+
+// BulkResult is the outcome of one item of a bulk write: the stored item, or the error it failed
+// with. Both are nil for an item that was rolled back because another item of an atomic write failed.
+type BulkResult[T any] struct {
+	Item *T
+	Err  error
+}
+
+// ProductExportFilter narrows an export to the products of a category, changed since a time.
+// Zero values leave the corresponding bound open; inactive products are left out unless
+// IncludeInactive is set.
+type ProductExportFilter struct {
+	CategoryID      int
+	UpdatedSince    time.Time
+	IncludeInactive bool
+}
+
+const (
+	ReservationStatusActive   = "active"
+	ReservationStatusReleased = "released"
+)
+
+type ReservationItem struct {
+	ProductID int
+	Quantity  int
+}
+
+// StockReservation holds product stock for a checkout under a caller-chosen reference.
+// Reserved units are taken out of Stock until the reservation is released.
+type StockReservation struct {
+	Reference string
+	Status    string
+	Items     []ReservationItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
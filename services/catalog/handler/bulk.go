@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/catalog/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxBulkItems = 500
+	// maxBulkBodyBytes leaves room for maxBulkItems items of a few kilobytes each
+	maxBulkBodyBytes = 4 << 20
+)
+
+const (
+	bulkStatusCreated = "created"
+	bulkStatusUpdated = "updated"
+	bulkStatusFailed  = "failed"
+	// bulkStatusSkipped marks a valid item left unwritten because another item of an atomic request failed
+	bulkStatusSkipped = "skipped"
+)
+
+type ResponseBulkCategory struct {
+	Index  int                         `json:"index"`
+	Status string                      `json:"status"`
+	Data   *ResponseCategory           `json:"data,omitempty"`
+	Error  *domainErrors.ErrorResponse `json:"error,omitempty"`
+}
+
+type ResponseBulkCategories struct {
+	Atomic    bool                   `json:"atomic"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Items     []ResponseBulkCategory `json:"items"`
+}
+
+type ResponseBulkProduct struct {
+	Index  int                         `json:"index"`
+	Status string                      `json:"status"`
+	Data   *ResponseProduct            `json:"data,omitempty"`
+	Error  *domainErrors.ErrorResponse `json:"error,omitempty"`
+}
+
+type ResponseBulkProducts struct {
+	Atomic    bool                  `json:"atomic"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Items     []ResponseBulkProduct `json:"items"`
+}
+
+// bulkRequest is a bulk request read item by item: the items that decoded and validated, and for
+// every item of the body the index into valid or the error it was rejected with
+type bulkRequest[T any] struct {
+	atomic bool
+	valid  []T
+	slots  []int
+	errs   []error
+}
+
+// bindBulk reads a JSON array body, decoding each item with decode; invalid items are
+// reported per item instead of failing the request
+func bindBulk[T any](ctx *gin.Context, decode func(item json.RawMessage) (T, error)) (*bulkRequest[T], error) {
+	atomic := true
+	if v := ctx.Query("atomic"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, domainErrors.NewAppError(errors.New("atomic must be true or false"), domainErrors.ValidationError)
+		}
+		atomic = parsed
+	}
+	items, err := controllers.BindJSONItems(ctx, maxBulkItems, controllers.BindOptions{MaxBytes: maxBulkBodyBytes})
+	if err != nil {
+		return nil, validation.BindingError(err)
+	}
+	req := &bulkRequest[T]{atomic: atomic, slots: make([]int, len(items)), errs: make([]error, len(items))}
+	for i, item := range items {
+		v, err := decode(item)
+		if err != nil {
+			req.slots[i], req.errs[i] = -1, err
+			continue
+		}
+		req.slots[i] = len(req.valid)
+		req.valid = append(req.valid, v)
+	}
+	return req, nil
+}
+
+// failed tells whether an atomic request must be skipped because some items are invalid
+func (r *bulkRequest[T]) failed() bool {
+	return r.atomic && len(r.valid) < len(r.slots)
+}
+
+// outcomes calls each with the outcome of every item of the request, written being the status
+// of a stored item, and counts the stored and failed items
+func outcomes[T, R any](req *bulkRequest[T], results []domain.BulkResult[R], written string, each func(i int, status string, item *R, err error)) (succeeded, failed int) {
+	for i, slot := range req.slots {
+		switch {
+		case slot < 0:
+			failed++
+			each(i, bulkStatusFailed, nil, req.errs[i])
+		case results == nil || (results[slot].Item == nil && results[slot].Err == nil):
+			each(i, bulkStatusSkipped, nil, nil)
+		case results[slot].Err != nil:
+			failed++
+			each(i, bulkStatusFailed, nil, results[slot].Err)
+		default:
+			succeeded++
+			each(i, written, results[slot].Item, nil)
+		}
+	}
+	return succeeded, failed
+}
+
+// bulkStatus is 200 when every item was written and 207 otherwise
+func bulkStatus(succeeded, total int) int {
+	if succeeded == total {
+		return http.StatusOK
+	}
+	return http.StatusMultiStatus
+}
+
+func errorResponse(err error) *domainErrors.ErrorResponse {
+	if err == nil {
+		return nil
+	}
+	res := domainErrors.ResponseFor(err)
+	return &res
+}
+
+func respondBulkCategories[T any](ctx *gin.Context, req *bulkRequest[T], results []domain.BulkResult[domain.Category], written string) {
+	res := ResponseBulkCategories{Atomic: req.atomic, Items: make([]ResponseBulkCategory, len(req.slots))}
+	res.Succeeded, res.Failed = outcomes(req, results, written, func(i int, status string, c *domain.Category, err error) {
+		res.Items[i] = ResponseBulkCategory{Index: i, Status: status, Error: errorResponse(err)}
+		if c != nil {
+			data := catToResponse(c)
+			res.Items[i].Data = &data
+		}
+	})
+	controllers.Respond(ctx, bulkStatus(res.Succeeded, len(req.slots)), res)
+}
+
+func respondBulkProducts[T any](ctx *gin.Context, req *bulkRequest[T], results []domain.BulkResult[domain.Product], written, baseCurrency string) {
+	res := ResponseBulkProducts{Atomic: req.atomic, Items: make([]ResponseBulkProduct, len(req.slots))}
+	res.Succeeded, res.Failed = outcomes(req, results, written, func(i int, status string, p *domain.Product, err error) {
+		res.Items[i] = ResponseBulkProduct{Index: i, Status: status, Error: errorResponse(err)}
+		if p != nil {
+			data := prodToResponse(p, baseCurrency)
+			res.Items[i].Data = &data
+		}
+	})
+	controllers.Respond(ctx, bulkStatus(res.Succeeded, len(req.slots)), res)
+}
+
+func decodeCategory(item json.RawMessage) (domain.Category, error) {
+	var req NewCategoryRequest
+	if err := controllers.DecodeItem(item, &req, controllers.BindOptions{}); err != nil {
+		return domain.Category{}, validation.BindingError(err)
+	}
+	return domain.Category{Name: req.Name, Description: req.Description, Slug: req.Slug}, nil
+}
+
+func decodeProduct(item json.RawMessage) (domain.Product, error) {
+	var req NewProductRequest
+	if err := controllers.DecodeItem(item, &req, controllers.BindOptions{}); err != nil {
+		return domain.Product{}, validation.BindingError(err)
+	}
+	return domain.Product{
+		Name: req.Name, Description: req.Description, SKU: req.SKU,
+		Price: req.Price, Stock: req.Stock, CategoryID: req.CategoryID,
+		ImageURL: req.ImageURL, IsActive: req.IsActive,
+	}, nil
+}
+
+// decodeUpdate reads a bulk update item: the fields to change, decoded into patch as a merge
+// patch, plus the "id" of the record, resolved like a path ID
+func (h *Handler) decodeUpdate(ctx *gin.Context, item json.RawMessage, patch any, resolve controllers.IDResolver) (int, map[string]any, error) {
+	var doc map[string]json.RawMessage
+	if err := controllers.DecodeItem(item, &doc, controllers.BindOptions{}); err != nil {
+		return 0, nil, validation.BindingError(err)
+	}
+	var raw any
+	rawID, ok := doc["id"]
+	delete(doc, "id")
+	if ok {
+		_ = json.Unmarshal(rawID, &raw)
+	}
+	var id string
+	switch v := raw.(type) {
+	case string:
+		id = v
+	case float64:
+		id = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		if !ok {
+			return 0, nil, domainErrors.NewValidationError(errors.New("id is required"), domainErrors.FieldError{Field: "id", Rule: "required", Message: "id is required"})
+		}
+	}
+	resolved, err := controllers.ResolveID(ctx.Request.Context(), "id", id, h.ids, resolve)
+	if err != nil {
+		return 0, nil, err
+	}
+	m, err := controllers.DecodePatch(doc, patch)
+	if err != nil {
+		return 0, nil, validation.BindingError(err)
+	}
+	return resolved, m, nil
+}
+
+// NewCategories godoc
+// @Summary      Create categories in bulk
+// @Description  Creates up to 500 categories in one transaction and reports every item. By default the request is atomic:
+// @Description  one invalid or failed item leaves every category unwritten. With atomic=false the other items are still created.
+// @Description  Answers 200 when every item was created and 207 otherwise.
+// @Tags         Category
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic  query bool                 false "Roll back every item when one fails" default(true)
+// @Param        request body  []NewCategoryRequest true  "Categories"
+// @Success      200 {object} controllers.Envelope[ResponseBulkCategories]
+// @Success      207 {object} controllers.Envelope[ResponseBulkCategories]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /category/bulk [post]
+func (h *Handler) NewCategories(ctx *gin.Context) {
+	req, err := bindBulk(ctx, decodeCategory)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var results []domain.BulkResult[domain.Category]
+	if !req.failed() && len(req.valid) > 0 {
+		if results, err = h.catUC.CreateMany(ctx.Request.Context(), req.valid, req.atomic); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	respondBulkCategories(ctx, req, results, bulkStatusCreated)
+}
+
+// UpdateCategories godoc
+// @Summary      Update categories in bulk
+// @Description  Each item holds the "id" of a category and the CategoryPatch fields to change. Up to 500 items are written in one transaction;
+// @Description  by default one invalid or failed item leaves every category unchanged, with atomic=false the others are still updated.
+// @Description  Answers 200 when every item was updated and 207 otherwise.
+// @Tags         Category
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic  query bool                     false "Roll back every item when one fails" default(true)
+// @Param        request body  []map[string]interface{} true  "Fields of each category, plus its id"
+// @Success      200 {object} controllers.Envelope[ResponseBulkCategories]
+// @Success      207 {object} controllers.Envelope[ResponseBulkCategories]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /category/bulk [put]
+func (h *Handler) UpdateCategories(ctx *gin.Context) {
+	req, err := bindBulk(ctx, func(item json.RawMessage) (domain.CategoryUpdate, error) {
+		var patch CategoryPatch
+		id, m, err := h.decodeUpdate(ctx, item, &patch, h.catUC.ResolveID)
+		return domain.CategoryUpdate{ID: id, Fields: m}, err
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var results []domain.BulkResult[domain.Category]
+	if !req.failed() && len(req.valid) > 0 {
+		if results, err = h.catUC.UpdateMany(ctx.Request.Context(), req.valid, req.atomic); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	respondBulkCategories(ctx, req, results, bulkStatusUpdated)
+}
+
+// NewProducts godoc
+// @Summary      Create products in bulk
+// @Description  Creates up to 500 products in one transaction and reports every item. By default the request is atomic:
+// @Description  one invalid or failed item leaves every product unwritten. With atomic=false the other items are still created.
+// @Description  Answers 200 when every item was created and 207 otherwise.
+// @Tags         Product
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic  query bool                false "Roll back every item when one fails" default(true)
+// @Param        request body  []NewProductRequest true  "Products"
+// @Success      200 {object} controllers.Envelope[ResponseBulkProducts]
+// @Success      207 {object} controllers.Envelope[ResponseBulkProducts]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /product/bulk [post]
+func (h *Handler) NewProducts(ctx *gin.Context) {
+	req, err := bindBulk(ctx, decodeProduct)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var results []domain.BulkResult[domain.Product]
+	if !req.failed() && len(req.valid) > 0 {
+		if results, err = h.prodUC.CreateMany(ctx.Request.Context(), req.valid, req.atomic); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	respondBulkProducts(ctx, req, results, bulkStatusCreated, h.rates.BaseCurrency())
+}
+
+// UpdateProducts godoc
+// @Summary      Update products in bulk
+// @Description  Each item holds the "id" of a product, the ProductPatch fields to change and optionally the "version" read, which fails the
+// @Description  item with a conflict if the product changed since. Up to 500 items are written in one transaction; by default
+// @Description  one invalid or failed item leaves every product unchanged, with atomic=false the others are still updated.
+// @Description  Answers 200 when every item was updated and 207 otherwise.
+// @Tags         Product
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        atomic  query bool                     false "Roll back every item when one fails" default(true)
+// @Param        request body  []map[string]interface{} true  "Fields of each product, plus its id and optional version"
+// @Success      200 {object} controllers.Envelope[ResponseBulkProducts]
+// @Success      207 {object} controllers.Envelope[ResponseBulkProducts]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /product/bulk [put]
+func (h *Handler) UpdateProducts(ctx *gin.Context) {
+	req, err := bindBulk(ctx, func(item json.RawMessage) (domain.ProductUpdate, error) {
+		var patch ProductPatch
+		id, m, err := h.decodeUpdate(ctx, item, &patch, h.prodUC.ResolveID)
+		return domain.ProductUpdate{ID: id, Version: patch.version(), Fields: m}, err
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var results []domain.BulkResult[domain.Product]
+	if !req.failed() && len(req.valid) > 0 {
+		if results, err = h.prodUC.UpdateMany(ctx.Request.Context(), req.valid, req.atomic); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	respondBulkProducts(ctx, req, results, bulkStatusUpdated, h.rates.BaseCurrency())
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/services/catalog/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+var csvExportHeader = []string{
+	"product_id", "uuid", "sku", "name", "description", "price", "currency", "stock", "category_id",
+	"image_url", "is_active", "rating_average", "rating_count", "version", "created_at", "updated_at",
+}
+
+type productExportWriter interface {
+	WriteBatch(products *[]domain.Product) error
+	Flush() error
+}
+
+// ExportProducts godoc
+// @Summary      Export products
+// @Description  Streams the catalog as CSV (one row per product) or NDJSON (one product per line), reading it in batches
+// @Description  so large catalogs can be synced. Send updatedSince to fetch only what changed since the last sync.
+// @Tags         Product
+// @Security     BearerAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        format          query string false "csv (default) or ndjson"
+// @Param        categoryId      query int    false "Only products of this category"
+// @Param        updatedSince    query string false "Only products changed at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        includeInactive query bool   false "Also export inactive products"
+// @Success      200 {string} string
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /product/export [get]
+func (h *Handler) ExportProducts(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", exportFormatCSV)
+	if format != exportFormatCSV && format != exportFormatNDJSON {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("format must be csv or ndjson"), domainErrors.ValidationError))
+		return
+	}
+	filter, err := exportFilterFromQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+
+	// headers are sent with the first batch so that query errors still reach the client as a
+	// normal error response
+	var w productExportWriter
+	start := func() error {
+		if w != nil {
+			return nil
+		}
+		var err error
+		w, err = h.startExport(ctx, format)
+		return err
+	}
+	err = h.prodUC.Export(ctx.Request.Context(), filter, func(batch *[]domain.Product) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := w.WriteBatch(batch); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if w == nil {
+			_ = ctx.Error(err)
+			return
+		}
+		h.Logger.Error("Product export aborted", zap.Error(err))
+		return
+	}
+	if err := start(); err != nil {
+		h.Logger.Error("Error writing product export", zap.Error(err))
+		return
+	}
+	if err := w.Flush(); err != nil {
+		h.Logger.Error("Error writing product export", zap.Error(err))
+	}
+}
+
+func (h *Handler) startExport(ctx *gin.Context, format string) (productExportWriter, error) {
+	filename := fmt.Sprintf("products-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == exportFormatNDJSON {
+		ctx.Header("Content-Type", "application/x-ndjson")
+		ctx.Status(http.StatusOK)
+		return &ndjsonExportWriter{enc: json.NewEncoder(ctx.Writer), currency: h.rates.BaseCurrency()}, nil
+	}
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	cw := csv.NewWriter(ctx.Writer)
+	if err := cw.Write(csvExportHeader); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw, currency: h.rates.BaseCurrency()}, nil
+}
+
+func exportFilterFromQuery(ctx *gin.Context) (domain.ProductExportFilter, error) {
+	var filter domain.ProductExportFilter
+	if v := ctx.Query("categoryId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return filter, errors.New("categoryId must be a positive integer")
+		}
+		filter.CategoryID = id
+	}
+	if v := ctx.Query("updatedSince"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if since, err = time.Parse("2006-01-02", v); err != nil {
+				return filter, errors.New("updatedSince must be RFC3339 or YYYY-MM-DD")
+			}
+		}
+		filter.UpdatedSince = since
+	}
+	if v := ctx.Query("includeInactive"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("includeInactive must be true or false")
+		}
+		filter.IncludeInactive = include
+	}
+	return filter, nil
+}
+
+type csvExportWriter struct {
+	w        *csv.Writer
+	currency string
+}
+
+func (c *csvExportWriter) WriteBatch(products *[]domain.Product) error {
+	for _, p := range *products {
+		row := []string{
+			strconv.Itoa(p.ID), p.UUID, p.SKU, p.Name, p.Description, strconv.FormatFloat(p.Price, 'f', 2, 64), c.currency,
+			strconv.Itoa(p.Stock), strconv.Itoa(p.CategoryID), p.ImageURL, strconv.FormatBool(p.IsActive),
+			strconv.FormatFloat(p.RatingAverage, 'f', -1, 64), strconv.Itoa(p.RatingCount), strconv.Itoa(p.Version),
+			p.CreatedAt.UTC().Format(time.RFC3339), p.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+	return c.Flush()
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonExportWriter struct {
+	enc      *json.Encoder
+	currency string
+}
+
+func (n *ndjsonExportWriter) WriteBatch(products *[]domain.Product) error {
+	for i := range *products {
+		if err := n.enc.Encode(prodToResponse(&(*products)[i], n.currency)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *ndjsonExportWriter) Flush() error { return nil }
@@ -2,13 +2,21 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"ecommerce-microservice-go/pkg/config"
 	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/currency"
+	filters "ecommerce-microservice-go/pkg/domain"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/validation"
 	"ecommerce-microservice-go/services/catalog/domain"
 	"ecommerce-microservice-go/services/catalog/usecase"
 
@@ -18,11 +26,19 @@ import (
 type NewCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
-	Slug        string `json:"slug" binding:"required"`
+	Slug        string `json:"slug" binding:"required,slug"`
+}
+
+// CategoryPatch lists the category fields a partial update may change
+type CategoryPatch struct {
+	Name        *string `json:"name" binding:"omitnil,min=1" patch:"name" extensions:"x-nullable"`
+	Description *string `json:"description" patch:"description,nullable" extensions:"x-nullable"`
+	Slug        *string `json:"slug" binding:"omitnil,slug" patch:"slug" extensions:"x-nullable"`
 }
 
 type ResponseCategory struct {
 	ID          int       `json:"id"`
+	UUID        string    `json:"uuid"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Slug        string    `json:"slug"`
@@ -33,7 +49,7 @@ type ResponseCategory struct {
 type NewProductRequest struct {
 	Name        string  `json:"name" binding:"required"`
 	Description string  `json:"description"`
-	SKU         string  `json:"sku" binding:"required"`
+	SKU         string  `json:"sku" binding:"required,sku"`
 	Price       float64 `json:"price" binding:"required"`
 	Stock       int     `json:"stock"`
 	CategoryID  int     `json:"categoryId" binding:"required"`
@@ -41,40 +57,194 @@ type NewProductRequest struct {
 	IsActive    bool    `json:"isActive"`
 }
 
+// ProductPatch lists the product fields a partial update may change, plus the version the
+// client read. Ratings and the version itself are only written by the service.
+type ProductPatch struct {
+	Name        *string  `json:"name" binding:"omitnil,min=1" patch:"name" extensions:"x-nullable"`
+	Description *string  `json:"description" patch:"description,nullable" extensions:"x-nullable"`
+	SKU         *string  `json:"sku" binding:"omitnil,sku" patch:"sku" extensions:"x-nullable"`
+	Price       *float64 `json:"price" binding:"omitnil,gt=0" patch:"price" extensions:"x-nullable"`
+	Stock       *int     `json:"stock" binding:"omitnil,gte=0" patch:"stock" extensions:"x-nullable"`
+	CategoryID  *int     `json:"categoryId" binding:"omitnil,gt=0" patch:"category_id" extensions:"x-nullable"`
+	ImageURL    *string  `json:"imageUrl" binding:"omitnil,url" patch:"image_url,nullable" extensions:"x-nullable"`
+	IsActive    *bool    `json:"isActive" patch:"is_active" extensions:"x-nullable"`
+	Version     *int     `json:"version" binding:"omitnil,gt=0" patch:"-" extensions:"x-nullable"`
+}
+
+// version returns the version the client sent, or zero for an unconditional update
+func (p *ProductPatch) version() int {
+	if p.Version == nil {
+		return 0
+	}
+	return *p.Version
+}
+
 type ResponseProduct struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	SKU         string    `json:"sku"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
-	CategoryID  int       `json:"categoryId"`
-	ImageURL    string    `json:"imageUrl"`
-	IsActive    bool      `json:"isActive"`
-	CreatedAt   time.Time `json:"createdAt,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	ID          int     `json:"id"`
+	UUID        string  `json:"uuid"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	SKU         string  `json:"sku"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+	// LocalPrice is Price converted into the currency asked for with ?currency=
+	LocalPrice    *ResponseLocalPrice `json:"localPrice,omitempty"`
+	Stock         int                 `json:"stock"`
+	CategoryID    int                 `json:"categoryId"`
+	ImageURL      string              `json:"imageUrl"`
+	IsActive      bool                `json:"isActive"`
+	RatingAverage float64             `json:"ratingAverage"`
+	RatingCount   int                 `json:"ratingCount"`
+	Version       int                 `json:"version"`
+	CreatedAt     time.Time           `json:"createdAt,omitempty"`
+	UpdatedAt     time.Time           `json:"updatedAt,omitempty"`
+	// Category is the product's category, inlined with ?expand=category
+	Category *ResponseCategory `json:"category,omitempty"`
+}
+
+type ResponseLocalPrice struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	// Rate is how many base currency units one unit of Currency was worth
+	Rate float64 `json:"rate"`
+}
+
+type ResponseExchangeRates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+	// FetchedAt is when the provider's rates were fetched; absent when only fixed rates are used
+	FetchedAt *time.Time `json:"fetchedAt,omitempty"`
+}
+
+type ResponseConversion struct {
+	Amount float64 `json:"amount"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Result float64 `json:"result"`
+}
+
+type ReservationItemRequest struct {
+	ProductID int `json:"productId" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required,gt=0"`
+}
+
+type ReserveStockRequest struct {
+	Reference string                   `json:"reference" binding:"required,max=64"`
+	Items     []ReservationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type ResponseReservationItem struct {
+	ProductID int `json:"productId"`
+	Quantity  int `json:"quantity"`
+}
+
+type ResponseReservation struct {
+	Reference string                    `json:"reference"`
+	Status    string                    `json:"status"`
+	Items     []ResponseReservationItem `json:"items"`
+	CreatedAt time.Time                 `json:"createdAt"`
 }
 
 type Handler struct {
 	catUC  usecase.ICategoryUseCase
 	prodUC usecase.IProductUseCase
+	resUC  usecase.IReservationUseCase
+	rates  currency.RateProvider
+	ids    config.IDs
 	Logger *logger.Logger
 }
 
-func NewHandler(c usecase.ICategoryUseCase, p usecase.IProductUseCase, l *logger.Logger) *Handler {
-	return &Handler{catUC: c, prodUC: p, Logger: l}
+func NewHandler(c usecase.ICategoryUseCase, p usecase.IProductUseCase, r usecase.IReservationUseCase, rates currency.RateProvider, ids config.IDs,
+	l *logger.Logger) *Handler {
+	return &Handler{catUC: c, prodUC: p, resUC: r, rates: rates, ids: ids, Logger: l}
 }
 
 // --- Category handlers ---
 
+var categoryListSpec = pagination.Spec{
+	Sortable:    map[string]string{"id": "id", "name": "name", "slug": "slug", "createdAt": "created_at"},
+	Filterable:  map[string]string{"slug": "slug"},
+	DefaultSort: "id",
+}
+
+// expandCategory is the ?expand= name inlining a product's category
+const expandCategory = "category"
+
+var productListSpec = pagination.Spec{
+	Sortable:    map[string]string{"id": "id", "name": "name", "price": "price", "stock": "stock", "createdAt": "created_at"},
+	Filterable:  map[string]string{"sku": "sku", "categoryId": "category_id"},
+	DefaultSort: "id",
+}
+
+var categorySearchSpec = pagination.SearchSpec{
+	Spec:       categoryListSpec,
+	Searchable: map[string]string{"name": "name", "description": "description", "slug": "slug"},
+	Dates:      map[string]string{"createdAt": "created_at", "updatedAt": "updated_at"},
+}
+
+var productSearchSpec = pagination.SearchSpec{
+	Spec:       productListSpec,
+	Searchable: map[string]string{"name": "name", "description": "description", "sku": "sku"},
+	Dates:      map[string]string{"createdAt": "created_at", "updatedAt": "updated_at"},
+}
+
+// bindSearch reads a DataFilters body and checks it against spec
+func bindSearch(ctx *gin.Context, spec pagination.SearchSpec) (pagination.Search, error) {
+	var f filters.DataFilters
+	if err := controllers.BindJSONWithOptions(ctx, &f, controllers.BindOptions{DisallowUnknownFields: true}); err != nil {
+		return pagination.Search{}, validation.BindingError(err)
+	}
+	return pagination.ParseSearch(f, spec)
+}
+
 // GetAllCategories godoc
 // @Summary      Get all categories
 // @Tags         Category
 // @Produce      json
-// @Success      200 {array} ResponseCategory
+// @Param        page   query int    false "Page number" default(1)
+// @Param        limit  query int    false "Page size" default(20)
+// @Param        sort   query string false "Comma separated fields, prefix - for descending (id, name, slug, createdAt)"
+// @Param        slug   query string false "Filter by slug"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} controllers.Envelope[[]ResponseCategory]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /category/ [get]
 func (h *Handler) GetAllCategories(ctx *gin.Context) {
-	cats, err := h.catUC.GetAll()
+	p, err := pagination.Parse(ctx, categoryListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	cats, total, err := h.catUC.GetAll(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseCategory, len(*cats))
+	for i, c := range *cats {
+		res[i] = catToResponse(&c)
+	}
+	controllers.RespondPage(ctx, res, total, p)
+}
+
+// SearchCategories godoc
+// @Summary      Search categories
+// @Description  likeFilters match name, description or slug; matches take exact slugs; dateRanges bound createdAt or updatedAt.
+// @Description  Values of one field are ORed, fields are ANDed.
+// @Tags         Category
+// @Accept       json
+// @Produce      json
+// @Param        request body filters.DataFilters true "Search"
+// @Success      200 {object} controllers.Envelope[[]ResponseCategory]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /category/search [post]
+func (h *Handler) SearchCategories(ctx *gin.Context) {
+	s, err := bindSearch(ctx, categorySearchSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	cats, total, err := h.catUC.Search(ctx.Request.Context(), s)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -83,27 +253,28 @@ func (h *Handler) GetAllCategories(ctx *gin.Context) {
 	for i, c := range *cats {
 		res[i] = catToResponse(&c)
 	}
-	ctx.JSON(http.StatusOK, res)
+	controllers.RespondPage(ctx, res, total, s.Params)
 }
 
 // GetCategoryByID godoc
 // @Summary      Get category by ID
 // @Tags         Category
-// @Param        id path int true "Category ID"
-// @Success      200 {object} ResponseCategory
+// @Param        id     path  string true  "Category UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} controllers.Envelope[ResponseCategory]
 // @Router       /category/{id} [get]
 func (h *Handler) GetCategoryByID(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.catUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	c, err := h.catUC.GetByID(id)
+	c, err := h.catUC.GetByID(ctx.Request.Context(), id)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, catToResponse(c))
+	controllers.Respond(ctx, http.StatusOK, catToResponse(c))
 }
 
 // NewCategory godoc
@@ -111,67 +282,74 @@ func (h *Handler) GetCategoryByID(ctx *gin.Context) {
 // @Tags         Category
 // @Security     BearerAuth
 // @Param        request body NewCategoryRequest true "Category"
-// @Success      200 {object} ResponseCategory
+// @Success      200 {object} controllers.Envelope[ResponseCategory]
 // @Router       /category/ [post]
 func (h *Handler) NewCategory(ctx *gin.Context) {
 	var req NewCategoryRequest
 	if err := controllers.BindJSON(ctx, &req); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	c, err := h.catUC.Create(&domain.Category{Name: req.Name, Description: req.Description, Slug: req.Slug})
+	c, err := h.catUC.Create(ctx.Request.Context(), &domain.Category{Name: req.Name, Description: req.Description, Slug: req.Slug})
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, catToResponse(c))
+	controllers.Respond(ctx, http.StatusOK, catToResponse(c))
 }
 
 // UpdateCategory godoc
 // @Summary      Update category
+// @Description  Partial update: send a JSON merge patch of the fields to change, or a JSON Patch (application/json-patch+json)
+// @Description  of add, replace and remove operations on them. Only the fields of CategoryPatch can be changed.
 // @Tags         Category
+// @Accept       json
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
 // @Security     BearerAuth
-// @Param        id path int true "Category ID"
-// @Param        request body map[string]interface{} true "Fields"
-// @Success      200 {object} ResponseCategory
-// @Router       /category/{id} [put]
+// @Param        id path string true "Category UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body CategoryPatch true "Fields to change"
+// @Success      200 {object} controllers.Envelope[ResponseCategory]
+// @Failure      400 {object} controllers.ErrorEnvelope "Unknown, read-only or invalid fields"
+// @Router       /category/{id} [patch]
 func (h *Handler) UpdateCategory(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.catUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	var m map[string]any
-	if err := controllers.BindJSONMap(ctx, &m); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+	var patch CategoryPatch
+	m, err := controllers.BindPatch(ctx, &patch)
+	if err != nil {
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	c, err := h.catUC.Update(id, m)
+	c, err := h.catUC.Update(ctx.Request.Context(), id, m)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, catToResponse(c))
+	controllers.Respond(ctx, http.StatusOK, catToResponse(c))
 }
 
 // DeleteCategory godoc
 // @Summary      Delete category
 // @Tags         Category
 // @Security     BearerAuth
-// @Param        id path int true "Category ID"
-// @Success      200 {object} controllers.MessageResponse
+// @Param        id path string true "Category UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[controllers.MessageResponse]
 // @Router       /category/{id} [delete]
 func (h *Handler) DeleteCategory(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.catUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	if err := h.catUC.Delete(id); err != nil {
+	if err := h.catUC.Delete(ctx.Request.Context(), id); err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	controllers.RespondMessage(ctx, http.StatusOK, "resource deleted successfully")
 }
 
 // --- Product handlers ---
@@ -179,55 +357,134 @@ func (h *Handler) DeleteCategory(ctx *gin.Context) {
 // GetAllProducts godoc
 // @Summary      Get all products
 // @Tags         Product
-// @Success      200 {array} ResponseProduct
+// @Param        page       query int    false "Page number" default(1)
+// @Param        limit      query int    false "Page size" default(20)
+// @Param        sort       query string false "Comma separated fields, prefix - for descending (id, name, price, stock, createdAt)"
+// @Param        sku        query string false "Filter by SKU"
+// @Param        categoryId query int    false "Filter by category"
+// @Param        currency   query string false "Also show prices in this currency, e.g. USD"
+// @Param        fields     query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand     query string false "Related resources to inline: category"
+// @Success      200 {object} controllers.Envelope[[]ResponseProduct]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /product/ [get]
 func (h *Handler) GetAllProducts(ctx *gin.Context) {
-	products, err := h.prodUC.GetAll()
+	p, err := pagination.Parse(ctx, productListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	products, total, err := h.prodUC.GetAll(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res, err := h.productsToResponse(ctx, products)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, res, total, p)
+}
+
+// SearchProducts godoc
+// @Summary      Search active products
+// @Description  likeFilters match name, description or sku; matches take exact sku or categoryId values; dateRanges bound
+// @Description  createdAt or updatedAt. Values of one field are ORed, fields are ANDed.
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Param        request  body  filters.DataFilters true  "Search"
+// @Param        currency query string              false "Also show prices in this currency, e.g. USD"
+// @Param        fields   query string              false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand   query string              false "Related resources to inline: category"
+// @Success      200 {object} controllers.Envelope[[]ResponseProduct]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /product/search [post]
+func (h *Handler) SearchProducts(ctx *gin.Context) {
+	s, err := bindSearch(ctx, productSearchSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	products, total, err := h.prodUC.Search(ctx.Request.Context(), s)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res, err := h.productsToResponse(ctx, products)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, productsToResponse(products))
+	controllers.RespondPage(ctx, res, total, s.Params)
 }
 
 // GetProductByID godoc
 // @Summary      Get product by ID
 // @Tags         Product
-// @Param        id path int true "Product ID"
-// @Success      200 {object} ResponseProduct
+// @Param        id       path  string true  "Product UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        currency query string false "Also show the price in this currency, e.g. USD"
+// @Param        fields   query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand   query string false "Related resources to inline: category"
+// @Success      200 {object} controllers.Envelope[ResponseProduct]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /product/{id} [get]
 func (h *Handler) GetProductByID(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.prodUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := h.prodUC.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
 		return
 	}
-	p, err := h.prodUC.GetByID(id)
+	res, err := h.productsToResponse(ctx, &[]domain.Product{*p})
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, prodToResponse(p))
+	controllers.Respond(ctx, http.StatusOK, res[0])
 }
 
 // GetProductsByCategory godoc
 // @Summary      Get products by category
 // @Tags         Product
-// @Param        categoryId path int true "Category ID"
-// @Success      200 {array} ResponseProduct
+// @Param        categoryId path  string true  "Category UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        page       query int    false "Page number" default(1)
+// @Param        limit      query int    false "Page size" default(20)
+// @Param        sort       query string false "Comma separated fields, prefix - for descending (id, name, price, stock, createdAt)"
+// @Param        sku        query string false "Filter by SKU"
+// @Param        currency   query string false "Also show prices in this currency, e.g. USD"
+// @Param        fields     query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand     query string false "Related resources to inline: category"
+// @Success      200 {object} controllers.Envelope[[]ResponseProduct]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /product/category/{categoryId} [get]
 func (h *Handler) GetProductsByCategory(ctx *gin.Context) {
-	catID, err := strconv.Atoi(ctx.Param("categoryId"))
+	catID, err := controllers.ParamID(ctx, "categoryId", h.ids, h.catUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid category id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := pagination.Parse(ctx, productListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
 		return
 	}
-	products, err := h.prodUC.GetByCategory(catID)
+	products, total, err := h.prodUC.GetByCategory(ctx.Request.Context(), catID, p)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, productsToResponse(products))
+	res, err := h.productsToResponse(ctx, products)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, res, total, p)
 }
 
 // NewProduct godoc
@@ -235,15 +492,15 @@ func (h *Handler) GetProductsByCategory(ctx *gin.Context) {
 // @Tags         Product
 // @Security     BearerAuth
 // @Param        request body NewProductRequest true "Product"
-// @Success      200 {object} ResponseProduct
+// @Success      200 {object} controllers.Envelope[ResponseProduct]
 // @Router       /product/ [post]
 func (h *Handler) NewProduct(ctx *gin.Context) {
 	var req NewProductRequest
 	if err := controllers.BindJSON(ctx, &req); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	p, err := h.prodUC.Create(&domain.Product{
+	p, err := h.prodUC.Create(ctx.Request.Context(), &domain.Product{
 		Name: req.Name, Description: req.Description, SKU: req.SKU,
 		Price: req.Price, Stock: req.Stock, CategoryID: req.CategoryID,
 		ImageURL: req.ImageURL, IsActive: req.IsActive,
@@ -252,69 +509,224 @@ func (h *Handler) NewProduct(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, prodToResponse(p))
+	controllers.Respond(ctx, http.StatusOK, prodToResponse(p, h.rates.BaseCurrency()))
 }
 
 // UpdateProduct godoc
 // @Summary      Update product
 // @Tags         Product
+// @Accept       json
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
 // @Security     BearerAuth
-// @Param        id path int true "Product ID"
-// @Param        request body map[string]interface{} true "Fields"
-// @Success      200 {object} ResponseProduct
-// @Router       /product/{id} [put]
+// @Param        id path string true "Product UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Description  Partial update: send a JSON merge patch of the fields to change, or a JSON Patch (application/json-patch+json)
+// @Description  of add, replace and remove operations on them. Only the fields of ProductPatch can be changed.
+// @Description  Send the "version" of the product you read to update it only if nobody changed it since
+// @Param        request body ProductPatch true "Fields to change, plus the optional version"
+// @Success      200 {object} controllers.Envelope[ResponseProduct]
+// @Failure      400 {object} controllers.ErrorEnvelope "Unknown, read-only or invalid fields"
+// @Failure      409 {object} controllers.ErrorEnvelope "The product changed since that version"
+// @Router       /product/{id} [patch]
 func (h *Handler) UpdateProduct(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.prodUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	var m map[string]any
-	if err := controllers.BindJSONMap(ctx, &m); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+	var patch ProductPatch
+	m, err := controllers.BindPatch(ctx, &patch)
+	if err != nil {
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	p, err := h.prodUC.Update(id, m)
+	p, err := h.prodUC.Update(ctx.Request.Context(), id, patch.version(), m)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, prodToResponse(p))
+	controllers.Respond(ctx, http.StatusOK, prodToResponse(p, h.rates.BaseCurrency()))
 }
 
 // DeleteProduct godoc
 // @Summary      Delete product
 // @Tags         Product
 // @Security     BearerAuth
-// @Param        id path int true "Product ID"
-// @Success      200 {object} controllers.MessageResponse
+// @Param        id path string true "Product UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[controllers.MessageResponse]
 // @Router       /product/{id} [delete]
 func (h *Handler) DeleteProduct(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.prodUC.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	if err := h.prodUC.Delete(id); err != nil {
+	if err := h.prodUC.Delete(ctx.Request.Context(), id); err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	controllers.RespondMessage(ctx, http.StatusOK, "resource deleted successfully")
+}
+
+// --- Internal reservation handlers ---
+
+// ReserveStock takes items out of stock for a checkout. It is only reachable with the
+// internal token; repeating a reference returns the original reservation.
+func (h *Handler) ReserveStock(ctx *gin.Context) {
+	var req ReserveStockRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	items := make([]domain.ReservationItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = domain.ReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	r, err := h.resUC.Reserve(ctx.Request.Context(), &domain.StockReservation{Reference: req.Reference, Items: items})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, reservationToResponse(r))
+}
+
+// ReleaseStock returns reserved units to stock. It is only reachable with the internal token.
+func (h *Handler) ReleaseStock(ctx *gin.Context) {
+	r, err := h.resUC.Release(ctx.Request.Context(), ctx.Param("reference"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, reservationToResponse(r))
 }
 
 // Mappers
+func reservationToResponse(r *domain.StockReservation) ResponseReservation {
+	items := make([]ResponseReservationItem, len(r.Items))
+	for i, it := range r.Items {
+		items[i] = ResponseReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	return ResponseReservation{Reference: r.Reference, Status: r.Status, Items: items, CreatedAt: r.CreatedAt}
+}
+
 func catToResponse(c *domain.Category) ResponseCategory {
-	return ResponseCategory{ID: c.ID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
+	return ResponseCategory{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
 }
 
-func prodToResponse(p *domain.Product) ResponseProduct {
-	return ResponseProduct{ID: p.ID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, Stock: p.Stock, CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
+func prodToResponse(p *domain.Product, baseCurrency string) ResponseProduct {
+	return ResponseProduct{ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, Currency: baseCurrency, Stock: p.Stock, CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive,
+		RatingAverage: p.RatingAverage, RatingCount: p.RatingCount, Version: p.Version, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
 }
 
-func productsToResponse(ps *[]domain.Product) []ResponseProduct {
+// productsToResponse maps the products, inlines their categories with ?expand=category and,
+// with ?currency=, adds their prices in that currency
+func (h *Handler) productsToResponse(ctx *gin.Context, ps *[]domain.Product) ([]ResponseProduct, error) {
+	expand, err := controllers.ParseExpand(ctx, expandCategory)
+	if err != nil {
+		return nil, err
+	}
+	base := h.rates.BaseCurrency()
 	res := make([]ResponseProduct, len(*ps))
 	for i, p := range *ps {
-		res[i] = prodToResponse(&p)
+		res[i] = prodToResponse(&p, base)
+	}
+	if expand.Has(expandCategory) {
+		if err := h.expandCategories(ctx, res); err != nil {
+			return nil, err
+		}
+	}
+	code := strings.ToUpper(ctx.Query("currency"))
+	if code == "" || code == base {
+		return res, nil
+	}
+	rate, err := h.rates.Rate(code)
+	if err != nil {
+		return nil, currencyError(err, code)
+	}
+	for i := range res {
+		res[i].LocalPrice = &ResponseLocalPrice{Currency: code, Amount: round2(res[i].Price / rate), Rate: rate}
+	}
+	return res, nil
+}
+
+// expandCategories sets the category of every product, reading each category once
+func (h *Handler) expandCategories(ctx *gin.Context, res []ResponseProduct) error {
+	categories := map[int]*ResponseCategory{}
+	for i := range res {
+		id := res[i].CategoryID
+		c, seen := categories[id]
+		if !seen {
+			category, err := h.catUC.GetByID(ctx.Request.Context(), id)
+			if err != nil && !errors.Is(err, domainErrors.ErrNotFound) {
+				return err
+			}
+			if err == nil {
+				r := catToResponse(category)
+				c = &r
+			}
+			categories[id] = c
+		}
+		res[i].Category = c
+	}
+	return nil
+}
+
+// --- Exchange rate handlers ---
+
+// GetExchangeRates godoc
+// @Summary      Get exchange rates
+// @Description  Base currency units per unit of every supported currency. Rates come from the provider set with
+// @Description  EXCHANGE_RATES_URL, refreshed every EXCHANGE_RATES_REFRESH_MINUTES, and from the fixed EXCHANGE_RATES.
+// @Tags         Currency
+// @Produce      json
+// @Success      200 {object} controllers.Envelope[ResponseExchangeRates]
+// @Router       /catalog/exchange-rates [get]
+func (h *Handler) GetExchangeRates(ctx *gin.Context) {
+	t := h.rates.Table()
+	res := ResponseExchangeRates{Base: t.Base, Rates: t.Rates}
+	if !t.FetchedAt.IsZero() {
+		res.FetchedAt = &t.FetchedAt
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// ConvertCurrency godoc
+// @Summary      Convert an amount between currencies
+// @Tags         Currency
+// @Produce      json
+// @Param        amount query number true  "Amount in from"
+// @Param        from   query string false "Currency of amount; the base currency when omitted"
+// @Param        to     query string true  "Currency to convert into"
+// @Success      200 {object} controllers.Envelope[ResponseConversion]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /catalog/exchange-rates/convert [get]
+func (h *Handler) ConvertCurrency(ctx *gin.Context) {
+	amount, err := strconv.ParseFloat(ctx.Query("amount"), 64)
+	if err != nil || amount < 0 || math.IsInf(amount, 0) {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("amount must be a non-negative number"), domainErrors.ValidationError))
+		return
 	}
-	return res
+	from := strings.ToUpper(ctx.DefaultQuery("from", h.rates.BaseCurrency()))
+	to := strings.ToUpper(ctx.Query("to"))
+	if to == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("to is required"), domainErrors.ValidationError))
+		return
+	}
+	result, err := currency.Convert(h.rates, amount, from, to)
+	if err != nil {
+		_ = ctx.Error(currencyError(err, ""))
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, ResponseConversion{Amount: amount, From: from, To: to, Result: round2(result)})
+}
+
+func currencyError(err error, code string) error {
+	if code != "" {
+		err = fmt.Errorf("%w: %s", err, code)
+	}
+	return domainErrors.NewAppError(err, domainErrors.ValidationError)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
 }
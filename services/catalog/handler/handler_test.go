@@ -0,0 +1,286 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	currencymocks "ecommerce-microservice-go/pkg/currency/mocks"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/catalog/domain"
+	"ecommerce-microservice-go/services/catalog/handler"
+	"ecommerce-microservice-go/services/catalog/usecase/mocks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	testJWT    = config.JWT{AccessSecret: "test-access-secret", AccessTime: time.Hour, Issuer: "user", Audience: "catalog",
+		AccessAudiences: []string{"catalog"}}
+	tee = domain.Product{ID: 7, UUID: "0b7e5c2a-6d1f-4f7b-8a39-2c4e9d1f6a53", Name: "Basic Tee", SKU: "TEE-01", Price: 25.5, Stock: 10,
+		CategoryID: 1, IsActive: true, Version: 3}
+)
+
+// handlerMocks are the use cases behind the handler
+type handlerMocks struct {
+	categories *mocks.MockICategoryUseCase
+	products   *mocks.MockIProductUseCase
+	rates      *currencymocks.MockRateProvider
+}
+
+// newRouter serves the product routes as main.go does, without the database
+func newRouter(t *testing.T) (*gin.Engine, handlerMocks) {
+	t.Helper()
+	if err := validation.Register(); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	m := handlerMocks{categories: mocks.NewMockICategoryUseCase(ctrl), products: mocks.NewMockIProductUseCase(ctrl),
+		rates: currencymocks.NewMockRateProvider(ctrl)}
+	m.rates.EXPECT().BaseCurrency().Return("IDR").AnyTimes()
+	h := handler.NewHandler(m.categories, m.products, mocks.NewMockIReservationUseCase(ctrl), m.rates, config.IDs{AllowIntegers: true}, testLogger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(controllers.UseEnvelope(), middleware.ErrorHandler(testLogger))
+	router.GET("/v1/product/:id", h.GetProductByID)
+	router.PATCH("/v1/product/:id", middleware.AuthJWTMiddleware(testJWT), h.UpdateProduct)
+	return router, m
+}
+
+func bearer(t *testing.T) string {
+	t.Helper()
+	token, err := security.NewJWTService(testJWT).GenerateJWTToken(1, security.RoleStaff, "", security.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token.Token
+}
+
+func TestGetProductByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		setup      func(m handlerMocks)
+		wantStatus int
+		wantCode   domainErrors.ErrorCode
+		wantData   map[string]any
+	}{
+		{
+			name:   "found by integer id",
+			target: "/v1/product/7",
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantData:   map[string]any{"id": 7.0, "name": "Basic Tee", "currency": "IDR", "version": 3.0},
+		},
+		{
+			name:   "found by uuid",
+			target: "/v1/product/" + tee.UUID,
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().ResolveID(gomock.Any(), tee.UUID).Return(7, nil)
+				m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantData:   map[string]any{"id": 7.0, "uuid": tee.UUID},
+		},
+		{
+			name:   "missing product",
+			target: "/v1/product/8",
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().GetByID(gomock.Any(), 8).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   domainErrors.CodeNotFound,
+		},
+		{
+			name:       "malformed id",
+			target:     "/v1/product/tee",
+			setup:      func(handlerMocks) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+		{
+			name:   "fields trim the product",
+			target: "/v1/product/7?fields=id,name",
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantData:   map[string]any{"id": 7.0, "name": "Basic Tee"},
+		},
+		{
+			name:   "expanded category is kept whole",
+			target: "/v1/product/7?fields=id&expand=category",
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil)
+				m.categories.EXPECT().GetByID(gomock.Any(), 1).Return(&domain.Category{ID: 1, Name: "Shirts"}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantData:   map[string]any{"id": 7.0, "category": handler.ResponseCategory{ID: 1, Name: "Shirts"}},
+		},
+		{
+			name:   "price in another currency",
+			target: "/v1/product/7?fields=localPrice&currency=usd",
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil)
+				m.rates.EXPECT().Rate("USD").Return(0.5, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantData:   map[string]any{"localPrice": map[string]any{"currency": "USD", "amount": 51.0, "rate": 0.5}},
+		},
+		{
+			name:       "unknown expansion",
+			target:     "/v1/product/7?expand=owner",
+			setup:      func(m handlerMocks) { m.products.EXPECT().GetByID(gomock.Any(), 7).Return(&tee, nil) },
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newRouter(t)
+			tt.setup(m)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				if got := controllers.DecodeError(w.Body).Code; got != tt.wantCode {
+					t.Errorf("code = %s, want %s", got, tt.wantCode)
+				}
+				return
+			}
+			var data map[string]any
+			if err := controllers.DecodeData(w.Body, &data); err != nil {
+				t.Fatal(err)
+			}
+			checkFields(t, data, tt.wantData, strings.Contains(tt.target, controllers.FieldsParam+"="))
+		})
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	updated := tee
+	updated.Price, updated.Version = 30, 4
+	tests := []struct {
+		name       string
+		auth       bool
+		body       string
+		setup      func(m handlerMocks)
+		wantStatus int
+		wantCode   domainErrors.ErrorCode
+	}{
+		{
+			name:       "anonymous request is rejected",
+			body:       `{"price":30}`,
+			setup:      func(handlerMocks) {},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   domainErrors.CodeNotAuthenticated,
+		},
+		{
+			name: "price is changed at the version read",
+			auth: true,
+			body: `{"price":30,"version":3}`,
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().Update(gomock.Any(), 7, 3, map[string]interface{}{"price": 30.0}).Return(&updated, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "stale version conflicts",
+			auth: true,
+			body: `{"price":30,"version":2}`,
+			setup: func(m handlerMocks) {
+				m.products.EXPECT().Update(gomock.Any(), 7, 2, gomock.Any()).Return(nil, psql.NewConflictError("products", 7, 2, 3))
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   domainErrors.CodeVersionConflict,
+		},
+		{
+			name:       "invalid price is rejected before the use case",
+			auth:       true,
+			body:       `{"price":-1}`,
+			setup:      func(handlerMocks) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+		{
+			name:       "read-only field is rejected",
+			auth:       true,
+			body:       `{"ratingAverage":5}`,
+			setup:      func(handlerMocks) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, m := newRouter(t)
+			tt.setup(m)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/product/7", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			if tt.auth {
+				req.Header.Set("Authorization", bearer(t))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				if got := controllers.DecodeError(w.Body).Code; got != tt.wantCode {
+					t.Errorf("code = %s, want %s", got, tt.wantCode)
+				}
+				return
+			}
+			var res handler.ResponseProduct
+			if err := controllers.DecodeData(w.Body, &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Price != 30 || res.Version != 4 {
+				t.Errorf("product = %+v, want price 30 at version 4", res)
+			}
+		})
+	}
+}
+
+// checkFields compares the fields of want with data through their JSON; with exact, data may
+// not have other fields
+func checkFields(t *testing.T, data, want map[string]any, exact bool) {
+	t.Helper()
+	for k, v := range want {
+		got, _ := json.Marshal(data[k])
+		exp, _ := json.Marshal(v)
+		// structs are compared as the client decodes them
+		var decoded any
+		_ = json.Unmarshal(exp, &decoded)
+		exp, _ = json.Marshal(decoded)
+		if string(got) != string(exp) {
+			t.Errorf("%s = %s, want %s", k, got, exp)
+		}
+	}
+	if exact && len(data) != len(want) {
+		t.Errorf("data = %v, want only %v", data, want)
+	}
+}
@@ -12,119 +12,268 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"os"
-	"time"
 
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/health"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
 	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
 	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/pkg/scheduler"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
 	"ecommerce-microservice-go/services/catalog/handler"
+	"ecommerce-microservice-go/services/catalog/migrations"
 	"ecommerce-microservice-go/services/catalog/repository"
 	"ecommerce-microservice-go/services/catalog/usecase"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	_ "ecommerce-microservice-go/services/catalog/docs"
 )
 
+const service app.Service = "catalog"
+
 func main() {
-	env := getEnvOrDefault("GO_ENV", "development")
-	var log *logger.Logger
-	var err error
-	if env == "development" {
-		log, err = logger.NewDevelopmentLogger()
-	} else {
-		log, err = logger.NewLogger()
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
 	}
+	log, err := logger.New(cfg.Log)
 	if err != nil {
 		panic(fmt.Errorf("error initializing logger: %w", err))
 	}
-	defer func() { _ = log.Log.Sync() }()
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
 
 	log.Info("Starting Catalog Service")
 
-	db, err := psql.ConnectDB(log)
-	if err != nil {
-		log.Panic("Failed to connect to database", zap.Error(err))
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
 	}
-
-	if err := psql.AutoMigrate(db, log, &repository.Category{}, &repository.Product{}); err != nil {
-		log.Panic("Failed to migrate database", zap.Error(err))
+	if len(args) > 0 && args[0] == "snapshot" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.SnapshotCommand(args[1:])); err != nil {
+			log.Fatal("Snapshot command failed", zap.Error(err))
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "seed" {
+		err := app.RunCommand(service, log, cfg.Server, fx.Options(provide(cfg), fx.Invoke(app.EnsureSchema)), func(seeder fixtures.Seeder) error {
+			return fixtures.RunSeedCommand(context.Background(), cfg.Fixtures, args[1:], seeder, os.Stdout)
+		})
+		if err != nil {
+			log.Fatal("Seed command failed", zap.Error(err))
+		}
+		return
 	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(seeder fixtures.Seeder) error {
+			return fixtures.SeedOnStart(context.Background(), cfg.Fixtures, seeder, log)
+		}),
+		// committed product and category changes go to the broker, where the search service picks them up
+		fx.Invoke(func(lc fx.Lifecycle, relay *events.OutboxRelay) { app.Go(lc, relay.Run) }),
+		fx.Invoke(func(lc fx.Lifecycle, probes *health.Registry, uc usecase.IRatingUseCase) error {
+			if cfg.BrokerURL == "" {
+				log.Warn("BROKER_URL not set, product ratings will not be updated")
+				return nil
+			}
+			subscriber, err := app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			if err != nil {
+				return err
+			}
+			app.Subscribe(lc, subscriber, "catalog.product-ratings", contracts.Types(contracts.RatingUpdated), uc.HandleRatingEvent)
+			probes.Add("broker", events.BrokerCheck(subscriber))
+			return nil
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, db *gorm.DB, rates *currency.Rates) {
+			jobs := scheduler.New(log)
+			if cfg.Currency.URL != "" {
+				jobs.Add(scheduler.Job{
+					Name:     "refresh-exchange-rates",
+					Interval: cfg.Currency.Refresh,
+					Run:      rates.Refresh,
+				})
+			}
+			for _, job := range retention.Jobs(db, repository.RetentionRules, cfg.Retention, log) {
+				jobs.Add(job)
+			}
+			app.Go(lc, jobs.Run)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Catalog Service", cfg.Server, router)
+		}),
+	), log)
+}
 
-	catRepo := repository.NewCategoryRepository(db, log)
-	prodRepo := repository.NewProductRepository(db, log)
-	catUC := usecase.NewCategoryUseCase(catRepo, log)
-	prodUC := usecase.NewProductUseCase(prodRepo, log)
-	h := handler.NewHandler(catUC, prodUC, log)
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Currency, cfg.IDs, cfg.Features, cfg.Authz, cfg.Outbox),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Snapshots(repository.SnapshotTables),
+			app.Cache,
+			app.Audit,
+			app.Auditor,
+			app.AuthzStore,
+			app.AuthzEnforcer,
+			app.Enforcer,
+			app.ExchangeRates,
+			app.RateProvider,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Publisher, error) {
+				return app.BrokerPublisher(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			events.NewOutboxRelay,
+			psql.NewTxManager,
+			featureflags.NewFromConfig,
+			func(store cache.Store, log *logger.Logger) *cache.Cache {
+				return cache.New(store, "catalog", cfg.Cache.DefaultTTL, log)
+			},
+			repository.NewCategoryRepository,
+			repository.NewProductRepository,
+			repository.NewReservationRepository,
+			usecase.NewCategoryUseCase,
+			usecase.NewProductUseCase,
+			usecase.NewReservationUseCase,
+			usecase.NewRatingUseCase,
+			usecase.NewFixtureSeeder,
+			handler.NewHandler,
+			newProbes,
+			newRouter,
+		),
+	)
+}
 
-	if env != "development" {
+// newProbes registers the health checks; the broker check joins once the ratings subscriber connects
+func newProbes(db *gorm.DB, cacheStore cache.Store, migrator *psql.Migrator, rates *currency.Rates, publisher events.Publisher) *health.Registry {
+	return health.NewRegistry("catalog").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		Add("exchange-rates", currency.Check(rates)).
+		Add("broker-publisher", events.BrokerCheck(publisher))
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, cacheStore cache.Store, probes *health.Registry, flags *featureflags.Client,
+	auditStore audit.Store, authzStore authz.Store, enforcer *authz.PolicyEnforcer, snapshots *backup.Snapshotter, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
 		log.SetupGinWithZapLogger()
 	} else {
 		log.SetupGinWithZapLoggerInDevelopment()
 	}
 
-	router := gin.New()
-	router.Use(gin.Recovery(), cors.Default())
-	router.Use(middleware.ErrorHandler())
-	router.Use(middleware.CommonHeaders)
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(controllers.UseEnvelope(), middleware.RequestID(), middleware.Recovery("catalog", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("catalog"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
 	router.Use(log.GinZapLogger())
+	router.Use(featureflags.Middleware(flags))
 
 	v1 := router.Group("/v1")
 
-	v1.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "catalog"})
-	})
+	probes.RegisterRoutes(v1)
 
 	v1.GET("/catalog/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
 
 	// Category routes
+	apiRateLimit := middleware.RateLimit(cacheStore, "catalog", cfg.RateLimit, middleware.KeyByIP, log)
+
 	cat := v1.Group("/category")
+	cat.Use(apiRateLimit)
 	cat.GET("/", h.GetAllCategories)
 	cat.GET("/:id", h.GetCategoryByID)
+	cat.POST("/search", h.SearchCategories)
 	catAuth := cat.Group("")
-	catAuth.Use(middleware.AuthJWTMiddleware())
+	catAuth.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermCatalogWrite))
 	{
 		catAuth.POST("/", h.NewCategory)
+		catAuth.POST("/bulk", h.NewCategories)
+		catAuth.PUT("/bulk", h.UpdateCategories)
+		catAuth.PATCH("/:id", h.UpdateCategory)
+		// PUT predates PATCH and takes the same merge patch
 		catAuth.PUT("/:id", h.UpdateCategory)
 		catAuth.DELETE("/:id", h.DeleteCategory)
 	}
 
 	// Product routes
 	prod := v1.Group("/product")
+	prod.Use(apiRateLimit)
 	prod.GET("/", h.GetAllProducts)
 	prod.GET("/:id", h.GetProductByID)
 	prod.GET("/category/:categoryId", h.GetProductsByCategory)
+	prod.POST("/search", h.SearchProducts)
 	prodAuth := prod.Group("")
-	prodAuth.Use(middleware.AuthJWTMiddleware())
+	prodAuth.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermCatalogWrite))
 	{
+		prodAuth.GET("/export", h.ExportProducts)
 		prodAuth.POST("/", h.NewProduct)
+		prodAuth.POST("/bulk", h.NewProducts)
+		prodAuth.PUT("/bulk", h.UpdateProducts)
+		prodAuth.PATCH("/:id", h.UpdateProduct)
 		prodAuth.PUT("/:id", h.UpdateProduct)
 		prodAuth.DELETE("/:id", h.DeleteProduct)
 	}
 
-	port := getEnvOrDefault("SERVER_PORT", "8082")
-	log.Info("Catalog Service starting", zap.String("port", port))
-	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-	}
-	if err := server.ListenAndServe(); err != nil {
-		log.Panic("Server failed", zap.Error(err))
-	}
-}
+	// Exchange rates, for showing prices in the shopper's currency
+	v1.GET("/catalog/exchange-rates", apiRateLimit, h.GetExchangeRates)
+	v1.GET("/catalog/exchange-rates/convert", apiRateLimit, h.ConvertCurrency)
 
-func getEnvOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+	// Audit trail (admins)
+	v1.GET("/catalog/audit", apiRateLimit, middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermAuditRead),
+		audit.NewHandler(auditStore, log).List)
+
+	// Authorization policies (admins)
+	authz.NewHandler(authzStore, enforcer, log).RegisterRoutes(v1.Group("/catalog/authz/policies", apiRateLimit, middleware.AuthJWTMiddleware(cfg.JWT),
+		middleware.RequirePermissions(security.PermAuthzManage)))
+
+	// Snapshots of the catalog (admins of the default tenant)
+	backup.NewHandler(snapshots, cfg.Backup, log).RegisterRoutes(v1.Group("/catalog/snapshot", apiRateLimit, middleware.AuthJWTMiddleware(cfg.JWT),
+		middleware.RequirePermissions(security.PermBackupManage)))
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+		internal.GET("/feature-flags/:key", flags.Handler())
+		internal.PUT("/feature-flags/:key", flags.Handler())
+		internal.POST("/reservations", h.ReserveStock)
+		internal.POST("/reservations/:reference/release", h.ReleaseStock)
 	}
-	return def
+
+	return router, nil
 }
@@ -0,0 +1,49 @@
+-- Baseline schema, matching what GORM AutoMigrate created before versioned migrations.
+-- IF NOT EXISTS lets databases created that way adopt it without changes.
+
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial,
+    name text NOT NULL,
+    description text,
+    slug text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_categories_slug UNIQUE (slug)
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id bigserial,
+    name text NOT NULL,
+    description text,
+    sku text NOT NULL,
+    price decimal NOT NULL,
+    stock bigint DEFAULT 0,
+    category_id bigint NOT NULL,
+    image_url text,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_products_sku UNIQUE (sku)
+);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id bigserial,
+    reference text NOT NULL,
+    status text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_reservations_reference ON stock_reservations (reference);
+
+CREATE TABLE IF NOT EXISTS stock_reservation_items (
+    id bigserial,
+    reservation_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    quantity bigint NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_stock_reservations_items FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id)
+);
+CREATE INDEX IF NOT EXISTS idx_stock_reservation_items_reservation_id ON stock_reservation_items (reservation_id);
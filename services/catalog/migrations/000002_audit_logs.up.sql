@@ -0,0 +1,18 @@
+CREATE TABLE audit_logs (
+    id bigserial PRIMARY KEY,
+    service text NOT NULL,
+    actor_id bigint NOT NULL DEFAULT 0,
+    actor_roles text,
+    action text NOT NULL,
+    entity text NOT NULL,
+    entity_id text NOT NULL,
+    before jsonb,
+    after jsonb,
+    changes jsonb,
+    request_id text,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs (entity, entity_id);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
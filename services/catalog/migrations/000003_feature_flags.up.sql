@@ -0,0 +1,6 @@
+CREATE TABLE feature_flags (
+    key text PRIMARY KEY,
+    enabled boolean NOT NULL DEFAULT false,
+    rollout integer NOT NULL DEFAULT 100 CHECK (rollout BETWEEN 0 AND 100),
+    users jsonb NOT NULL DEFAULT '[]'
+);
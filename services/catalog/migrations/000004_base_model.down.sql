@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS uni_products_sku;
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+ALTER TABLE products ADD CONSTRAINT uni_products_sku UNIQUE (sku);
+DROP INDEX IF EXISTS idx_products_deleted_at;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS updated_by;
+
+DROP INDEX IF EXISTS uni_categories_slug;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+ALTER TABLE categories ADD CONSTRAINT uni_categories_slug UNIQUE (slug);
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS updated_by;
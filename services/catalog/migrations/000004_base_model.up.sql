@@ -0,0 +1,19 @@
+ALTER TABLE categories
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN created_by bigint,
+    ADD COLUMN updated_by bigint;
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+-- soft-deleted rows keep their slug, so only live rows have to be unique
+ALTER TABLE categories DROP CONSTRAINT uni_categories_slug;
+CREATE UNIQUE INDEX uni_categories_slug ON categories (slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE products
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN created_by bigint,
+    ADD COLUMN updated_by bigint;
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+-- soft-deleted rows keep their sku, so only live rows have to be unique
+ALTER TABLE products DROP CONSTRAINT uni_products_sku;
+CREATE UNIQUE INDEX uni_products_sku ON products (sku) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_uuid;
+ALTER TABLE products DROP COLUMN IF EXISTS uuid;
+DROP INDEX IF EXISTS idx_categories_uuid;
+ALTER TABLE categories DROP COLUMN IF EXISTS uuid;
@@ -0,0 +1,7 @@
+-- Existing rows get a random UUID; the services assign time-ordered UUIDv7s to new ones.
+
+ALTER TABLE categories ADD COLUMN uuid uuid NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX idx_categories_uuid ON categories (uuid);
+
+ALTER TABLE products ADD COLUMN uuid uuid NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX idx_products_uuid ON products (uuid);
@@ -0,0 +1,3 @@
+-- Bumped by every product update and stock change; updates that send an older version fail with 409.
+
+ALTER TABLE products ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP COLUMN IF EXISTS rating_updated_at;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_average;
@@ -0,0 +1,6 @@
+-- Aggregated from approved reviews by the review service; rating_updated_at orders the updates
+-- so a late event cannot overwrite a newer aggregate.
+
+ALTER TABLE products ADD COLUMN rating_average decimal NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN rating_count bigint NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN rating_updated_at timestamptz;
@@ -0,0 +1,17 @@
+-- Product and category changes are published through the outbox for the search service.
+
+CREATE TABLE outbox_messages (
+    id bigserial,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    source text NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    published_at timestamptz,
+    attempts bigint DEFAULT 0,
+    last_error text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages (published_at);
+CREATE UNIQUE INDEX idx_outbox_messages_event_id ON outbox_messages (event_id);
@@ -0,0 +1,16 @@
+-- Object-level authorization policies read by pkg/authz; admins change them through
+-- /v1/catalog/authz/policies. Only staff and admins write categories and products.
+
+CREATE TABLE authz_policies (
+    id bigserial PRIMARY KEY,
+    role text NOT NULL,
+    resource text NOT NULL,
+    action text NOT NULL,
+    condition text NOT NULL DEFAULT '' CHECK (condition IN ('', 'owner')),
+    effect text NOT NULL CHECK (effect IN ('allow', 'deny'))
+);
+
+INSERT INTO authz_policies (role, resource, action, condition, effect) VALUES
+    ('staff', 'category', 'write', '', 'allow'),
+    ('staff', 'product', 'write', '', 'allow'),
+    ('admin', '*', '*', '', 'allow');
@@ -0,0 +1,11 @@
+DELETE FROM products WHERE tenant_id <> 'default';
+DROP INDEX IF EXISTS uni_products_sku;
+CREATE UNIQUE INDEX uni_products_sku ON products (sku) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_products_tenant_id;
+ALTER TABLE products DROP COLUMN IF EXISTS tenant_id;
+
+DELETE FROM categories WHERE tenant_id <> 'default';
+DROP INDEX IF EXISTS uni_categories_slug;
+CREATE UNIQUE INDEX uni_categories_slug ON categories (slug) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_categories_tenant_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS tenant_id;
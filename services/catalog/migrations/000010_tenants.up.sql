@@ -0,0 +1,13 @@
+-- Categories and products belong to a tenant, the store the user service provisioned; rows
+-- created before tenants existed belong to the default tenant. Slugs and SKUs are unique per
+-- store.
+
+ALTER TABLE categories ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_categories_tenant_id ON categories (tenant_id);
+DROP INDEX IF EXISTS uni_categories_slug;
+CREATE UNIQUE INDEX uni_categories_slug ON categories (tenant_id, slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE products ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_products_tenant_id ON products (tenant_id);
+DROP INDEX IF EXISTS uni_products_sku;
+CREATE UNIQUE INDEX uni_products_sku ON products (tenant_id, sku) WHERE deleted_at IS NULL;
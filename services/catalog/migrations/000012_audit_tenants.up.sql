@@ -0,0 +1,5 @@
+-- Audit entries belong to the tenant the change was made in; entries recorded before tenants
+-- existed belong to the default tenant.
+
+ALTER TABLE audit_logs ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_audit_logs_tenant_id ON audit_logs (tenant_id);
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/currency"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/pact"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/services/catalog/domain"
+	"ecommerce-microservice-go/services/catalog/handler"
+	"ecommerce-microservice-go/services/catalog/usecase/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var pactProduct = domain.Product{ID: 7, UUID: "0b7e5c2a-6d1f-4f7b-8a39-2c4e9d1f6a53", Name: "Basic Tee", SKU: "TEE-01", Price: 25.5,
+	Stock: 10, CategoryID: 1, IsActive: true, Version: 1}
+
+// pactStates put the mocked use cases in the provider states the consumers' pacts name
+var pactStates = map[string]func(products *mocks.MockIProductUseCase, reservations *mocks.MockIReservationUseCase){
+	"product 7 exists": func(products *mocks.MockIProductUseCase, _ *mocks.MockIReservationUseCase) {
+		serveProducts(products, pactProduct)
+	},
+	"product 99 does not exist": func(products *mocks.MockIProductUseCase, _ *mocks.MockIReservationUseCase) {
+		serveProducts(products, pactProduct)
+	},
+	"no product has the SKU NOPE-01": func(products *mocks.MockIProductUseCase, _ *mocks.MockIReservationUseCase) {
+		serveProducts(products, pactProduct)
+	},
+	"product 7 has 10 in stock": func(_ *mocks.MockIProductUseCase, reservations *mocks.MockIReservationUseCase) {
+		reserveFrom(reservations, pactProduct.ID, 10)
+	},
+	"product 7 has 1 in stock": func(_ *mocks.MockIProductUseCase, reservations *mocks.MockIReservationUseCase) {
+		reserveFrom(reservations, pactProduct.ID, 1)
+	},
+	"reservation order-1 is active": func(_ *mocks.MockIProductUseCase, reservations *mocks.MockIReservationUseCase) {
+		reservations.EXPECT().Release(gomock.Any(), "order-1").Return(&domain.StockReservation{Reference: "order-1",
+			Status: domain.ReservationStatusReleased, Items: []domain.ReservationItem{{ProductID: pactProduct.ID, Quantity: 2}}}, nil).AnyTimes()
+	},
+	"there is no reservation order-2": func(_ *mocks.MockIProductUseCase, reservations *mocks.MockIReservationUseCase) {
+		reservations.EXPECT().Release(gomock.Any(), "order-2").Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)).AnyTimes()
+	},
+}
+
+// serveProducts answers lookups by ID and listings, filtered by SKU, from products
+func serveProducts(m *mocks.MockIProductUseCase, products ...domain.Product) {
+	m.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int) (*domain.Product, error) {
+		for _, p := range products {
+			if p.ID == id {
+				return &p, nil
+			}
+		}
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}).AnyTimes()
+	m.EXPECT().GetAll(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, params pagination.Params) (*[]domain.Product, int64, error) {
+		matched := []domain.Product{}
+		for _, p := range products {
+			if sku, ok := params.Filters["sku"]; !ok || sku == p.SKU {
+				matched = append(matched, p)
+			}
+		}
+		return &matched, int64(len(matched)), nil
+	}).AnyTimes()
+}
+
+// reserveFrom accepts reservations of product while they fit in stock, like the repository does
+func reserveFrom(m *mocks.MockIReservationUseCase, product, stock int) {
+	m.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *domain.StockReservation) (*domain.StockReservation, error) {
+		for _, it := range r.Items {
+			if it.ProductID != product {
+				return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+			}
+			if it.Quantity > stock {
+				return nil, domainErrors.NewAppError(fmt.Errorf("insufficient stock for product %d", product), domainErrors.ValidationError)
+			}
+		}
+		r.Status = domain.ReservationStatusActive
+		return r, nil
+	}).AnyTimes()
+}
+
+// TestPacts replays the pacts of the catalog's consumers against its router, with the use
+// cases mocked; health checks and the audit log are left out since no pact reaches them
+func TestPacts(t *testing.T) {
+	t.Setenv("INTERNAL_API_TOKEN", pact.InternalToken)
+	log, err := logger.New(logger.Config{Level: zap.WarnLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	telemetry, err := otel.Setup(context.Background(), otel.Config{ServiceName: string(service)}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := config.NewSource()
+	cfg := Config{CORS: config.Watch(src, "CORS", config.LoadCORS), Security: config.LoadSecurityHeaders(src), IDs: config.IDs{AllowIntegers: true}}
+	store := cache.NewMemoryStore()
+	flags := featureflags.NewFromConfig(featureflags.Config{}, store, nil, log)
+	rates := currency.NewFromConfig(currency.Config{Base: "IDR"}, store, log)
+
+	err = pact.VerifyProvider(string(service), func(pt *pact.T, state string) (http.Handler, error) {
+		setup, ok := pactStates[state]
+		if !ok {
+			return nil, errors.New("unknown provider state")
+		}
+		ctrl := gomock.NewController(pt)
+		products, reservations := mocks.NewMockIProductUseCase(ctrl), mocks.NewMockIReservationUseCase(ctrl)
+		setup(products, reservations)
+		h := handler.NewHandler(mocks.NewMockICategoryUseCase(ctrl), products, reservations, rates, cfg.IDs, log)
+		authzStore := authz.NewDBStore(nil, log)
+		return newRouter(cfg, log, telemetry, store, health.NewRegistry(string(service)), flags, audit.NewDBStore(nil, string(service), log),
+			authzStore, authz.NewEnforcer(authzStore, authz.Config{}, log), nil, h)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/services/catalog/domain"
+)
+
+// SnapshotTables are the tables `catalog snapshot` exports and restores, parents first. Active
+// stock reservations go with the products since the stock counts already exclude them.
+var SnapshotTables = []string{"categories", "products", "stock_reservations", "stock_reservation_items"}
+
+// RetentionRules purge stock reservations once released, together with their items
+var RetentionRules = []retention.Rule{
+	{
+		Name:        "purge-released-reservations",
+		Setting:     "RETENTION_RELEASED_RESERVATIONS_DAYS",
+		DefaultDays: 30,
+		Table:       "stock_reservations",
+		Column:      "updated_at",
+		Where:       fmt.Sprintf("status = '%s'", domain.ReservationStatusReleased),
+		Children:    []retention.Child{{Table: "stock_reservation_items", Column: "reservation_id"}},
+	},
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/psql"
+)
+
+// BenchmarkProductsToDomain maps a page of 20 products
+func BenchmarkProductsToDomain(b *testing.B) {
+	b.ReportAllocs()
+	now := time.Now()
+	products := make([]Product, 20)
+	for i := range products {
+		products[i] = Product{ID: i + 1, Name: "Basic Tee", Description: "A plain cotton tee", SKU: "TEE-01", Price: 25.5, Stock: 10,
+			CategoryID: 1, ImageURL: "https://cdn.example.com/tee.png", IsActive: true, RatingAverage: 4.5, RatingCount: 12, Version: 1,
+			BaseModel: psql.BaseModel{UUID: "0b7e5c2a-6d1f-4f7b-8a39-2c4e9d1f6a53", CreatedAt: now, UpdatedAt: now}}
+	}
+	for b.Loop() {
+		productsToDomainn(products)
+	}
+}
@@ -1,54 +1,93 @@
 package repository
 
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
+	"ecommerce-microservice-go/pkg/contracts"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
 	"ecommerce-microservice-go/services/catalog/domain"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // --- Category GORM model ---
 type Category struct {
-	ID          int       `gorm:"primaryKey"`
-	Name        string    `gorm:"column:name;not null"`
-	Description string    `gorm:"column:description"`
-	Slug        string    `gorm:"column:slug;unique;not null"`
-	CreatedAt   time.Time `gorm:"autoCreateTime:mili"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime:mili"`
+	ID          int    `gorm:"primaryKey"`
+	Name        string `gorm:"column:name;not null"`
+	Description string `gorm:"column:description"`
+	Slug        string `gorm:"column:slug;unique;not null"`
+	psql.BaseModel
+	psql.TenantModel
 }
 
 func (Category) TableName() string { return "categories" }
 
 // --- Product GORM model ---
 type Product struct {
-	ID          int       `gorm:"primaryKey"`
-	Name        string    `gorm:"column:name;not null"`
-	Description string    `gorm:"column:description"`
-	SKU         string    `gorm:"column:sku;unique;not null"`
-	Price       float64   `gorm:"column:price;not null"`
-	Stock       int       `gorm:"column:stock;default:0"`
-	CategoryID  int       `gorm:"column:category_id;not null"`
-	ImageURL    string    `gorm:"column:image_url"`
-	IsActive    bool      `gorm:"column:is_active;default:true"`
-	CreatedAt   time.Time `gorm:"autoCreateTime:mili"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime:mili"`
+	ID              int        `gorm:"primaryKey"`
+	Name            string     `gorm:"column:name;not null"`
+	Description     string     `gorm:"column:description"`
+	SKU             string     `gorm:"column:sku;unique;not null"`
+	Price           float64    `gorm:"column:price;not null"`
+	Stock           int        `gorm:"column:stock;default:0"`
+	CategoryID      int        `gorm:"column:category_id;not null"`
+	ImageURL        string     `gorm:"column:image_url"`
+	IsActive        bool       `gorm:"column:is_active;default:true"`
+	RatingAverage   float64    `gorm:"column:rating_average;not null;default:0"`
+	RatingCount     int        `gorm:"column:rating_count;not null;default:0"`
+	RatingUpdatedAt *time.Time `gorm:"column:rating_updated_at"`
+	Version         int        `gorm:"column:version;not null;default:1"`
+	psql.BaseModel
+	psql.TenantModel
 }
 
 func (Product) TableName() string { return "products" }
 
+// --- Stock Reservation GORM models ---
+type StockReservation struct {
+	ID        int                    `gorm:"primaryKey"`
+	Reference string                 `gorm:"column:reference;uniqueIndex;not null"`
+	Status    string                 `gorm:"column:status;not null"`
+	Items     []StockReservationItem `gorm:"foreignKey:ReservationID"`
+	CreatedAt time.Time              `gorm:"autoCreateTime:mili"`
+	UpdatedAt time.Time              `gorm:"autoUpdateTime:mili"`
+}
+
+func (StockReservation) TableName() string { return "stock_reservations" }
+
+type StockReservationItem struct {
+	ID            int `gorm:"primaryKey"`
+	ReservationID int `gorm:"column:reservation_id;not null;index"`
+	ProductID     int `gorm:"column:product_id;not null"`
+	Quantity      int `gorm:"column:quantity;not null"`
+}
+
+func (StockReservationItem) TableName() string { return "stock_reservation_items" }
+
 // --- Category Repository ---
 
 type CategoryRepositoryInterface interface {
-	GetAll() (*[]domain.Category, error)
-	GetByID(id int) (*domain.Category, error)
-	Create(c *domain.Category) (*domain.Category, error)
-	Update(id int, m map[string]interface{}) (*domain.Category, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Category, int64, error)
+	SearchPaginated(ctx context.Context, s pagination.Search) (*[]domain.Category, int64, error)
+	GetByID(ctx context.Context, id int) (*domain.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	Create(ctx context.Context, c *domain.Category) (*domain.Category, error)
+	Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Category, error)
+	Delete(ctx context.Context, id int) error
+	SaveOutboxEvent(ctx context.Context, event *events.Event) error
 }
 
 type CategoryRepository struct {
@@ -60,58 +99,75 @@ func NewCategoryRepository(db *gorm.DB, l *logger.Logger) CategoryRepositoryInte
 	return &CategoryRepository{DB: db, Logger: l}
 }
 
-func (r *CategoryRepository) GetAll() (*[]domain.Category, error) {
+func (r *CategoryRepository) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Category, int64, error) {
 	var cats []Category
-	if err := r.DB.Find(&cats).Error; err != nil {
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	total, err := pagination.Find(r.DB.WithContext(ctx).Model(&Category{}), p, &cats)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
 	}
-	result := make([]domain.Category, len(cats))
-	for i, c := range cats {
-		result[i] = domain.Category{ID: c.ID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
+	return categoriesToDomain(cats), total, nil
+}
+
+func (r *CategoryRepository) SearchPaginated(ctx context.Context, s pagination.Search) (*[]domain.Category, int64, error) {
+	var cats []Category
+	total, err := pagination.FindSearch(r.DB.WithContext(ctx).Model(&Category{}), s, &cats)
+	if err != nil {
+		r.Logger.Error("Error searching categories", zap.Error(err))
+		return nil, 0, psql.TranslateError(err)
 	}
-	return &result, nil
+	return categoriesToDomain(cats), total, nil
 }
 
-func (r *CategoryRepository) GetByID(id int) (*domain.Category, error) {
+func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
 	var c Category
-	if err := r.DB.Where("id = ?", id).First(&c).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&c).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
-	return &domain.Category{ID: c.ID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
+	return &domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, Tenant: c.TenantID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
 }
 
-func (r *CategoryRepository) Create(d *domain.Category) (*domain.Category, error) {
-	c := Category{Name: d.Name, Description: d.Description, Slug: d.Slug}
-	if err := r.DB.Create(&c).Error; err != nil {
-		byteErr, _ := json.Marshal(err)
-		var ge domainErrors.GormErr
-		if json.Unmarshal(byteErr, &ge) == nil && ge.Number == 1062 {
-			return nil, domainErrors.NewAppErrorWithType(domainErrors.ResourceAlreadyExists)
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	var c Category
+	if err := psql.Conn(ctx, r.DB).Where("slug = ?", slug).First(&c).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
-	return &domain.Category{ID: c.ID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
+	return &domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, Tenant: c.TenantID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
 }
 
-func (r *CategoryRepository) Update(id int, m map[string]interface{}) (*domain.Category, error) {
+func (r *CategoryRepository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(r.DB.WithContext(ctx), &Category{}, publicID)
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, d *domain.Category) (*domain.Category, error) {
+	c := Category{Name: d.Name, Description: d.Description, Slug: d.Slug}
+	if err := psql.Conn(ctx, r.DB).Create(&c).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return &domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, Tenant: c.TenantID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Category, error) {
 	var c Category
 	c.ID = id
-	if err := r.DB.Model(&c).Updates(m).Error; err != nil {
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	if err := psql.Conn(ctx, r.DB).Model(&c).Updates(m).Error; err != nil {
+		return nil, psql.TranslateError(err)
 	}
-	if err := r.DB.Where("id = ?", id).First(&c).Error; err != nil {
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&c).Error; err != nil {
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 	}
-	return &domain.Category{ID: c.ID, Name: c.Name, Description: c.Description, Slug: c.Slug, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
+	return &domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, Tenant: c.TenantID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}, nil
 }
 
-func (r *CategoryRepository) Delete(id int) error {
-	tx := r.DB.Delete(&Category{}, id)
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&Category{}, id)
 	if tx.Error != nil {
-		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return psql.TranslateError(tx.Error)
 	}
 	if tx.RowsAffected == 0 {
 		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
@@ -119,15 +175,38 @@ func (r *CategoryRepository) Delete(id int) error {
 	return nil
 }
 
+func (r *CategoryRepository) SaveOutboxEvent(ctx context.Context, event *events.Event) error {
+	return saveOutboxEvent(ctx, r.DB, r.Logger, event)
+}
+
+// saveOutboxEvent stores the event for the relay; call it within the write transaction
+func saveOutboxEvent(ctx context.Context, db *gorm.DB, l *logger.Logger, event *events.Event) error {
+	if err := events.SaveToOutbox(psql.Conn(ctx, db), event); err != nil {
+		l.Error("Error saving outbox event", zap.String("type", event.Type), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
 // --- Product Repository ---
 
 type ProductRepositoryInterface interface {
-	GetAll() (*[]domain.Product, error)
-	GetByID(id int) (*domain.Product, error)
-	GetByCategory(categoryID int) (*[]domain.Product, error)
-	Create(p *domain.Product) (*domain.Product, error)
-	Update(id int, m map[string]interface{}) (*domain.Product, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Product, int64, error)
+	// SearchPaginated returns the page of active products matching the search
+	SearchPaginated(ctx context.Context, s pagination.Search) (*[]domain.Product, int64, error)
+	GetByID(ctx context.Context, id int) (*domain.Product, error)
+	GetBySKU(ctx context.Context, sku string) (*domain.Product, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	GetByCategory(ctx context.Context, categoryID int, p pagination.Params) (*[]domain.Product, int64, error)
+	// ExportInBatches calls fn with the matching products, batchSize at a time
+	ExportInBatches(ctx context.Context, filter domain.ProductExportFilter, batchSize int, fn func(batch *[]domain.Product) error) error
+	Create(ctx context.Context, p *domain.Product) (*domain.Product, error)
+	Update(ctx context.Context, id, version int, m map[string]interface{}) (*domain.Product, error)
+	// UpdateRating stores the aggregate unless a newer one is already stored. It leaves the
+	// version alone, so it never conflicts with an admin edit.
+	UpdateRating(ctx context.Context, r contracts.ProductRating) error
+	Delete(ctx context.Context, id int) error
+	SaveOutboxEvent(ctx context.Context, event *events.Event) error
 }
 
 type ProductRepository struct {
@@ -139,17 +218,39 @@ func NewProductRepository(db *gorm.DB, l *logger.Logger) ProductRepositoryInterf
 	return &ProductRepository{DB: db, Logger: l}
 }
 
-func (r *ProductRepository) GetAll() (*[]domain.Product, error) {
+func (r *ProductRepository) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Product, int64, error) {
+	var products []Product
+	total, err := pagination.Find(r.DB.WithContext(ctx).Model(&Product{}).Where("is_active = ?", true), p, &products)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	return productsToDomainn(products), total, nil
+}
+
+func (r *ProductRepository) SearchPaginated(ctx context.Context, s pagination.Search) (*[]domain.Product, int64, error) {
 	var products []Product
-	if err := r.DB.Where("is_active = ?", true).Find(&products).Error; err != nil {
+	total, err := pagination.FindSearch(r.DB.WithContext(ctx).Model(&Product{}).Where("is_active = ?", true), s, &products)
+	if err != nil {
+		r.Logger.Error("Error searching products", zap.Error(err))
+		return nil, 0, psql.TranslateError(err)
+	}
+	return productsToDomainn(products), total, nil
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id int) (*domain.Product, error) {
+	var p Product
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&p).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
-	return productsToDomainn(products), nil
+	return productToDomain(&p), nil
 }
 
-func (r *ProductRepository) GetByID(id int) (*domain.Product, error) {
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
 	var p Product
-	if err := r.DB.Where("id = ?", id).First(&p).Error; err != nil {
+	if err := psql.Conn(ctx, r.DB).Where("sku = ?", sku).First(&p).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 		}
@@ -158,44 +259,91 @@ func (r *ProductRepository) GetByID(id int) (*domain.Product, error) {
 	return productToDomain(&p), nil
 }
 
-func (r *ProductRepository) GetByCategory(categoryID int) (*[]domain.Product, error) {
+func (r *ProductRepository) GetByCategory(ctx context.Context, categoryID int, p pagination.Params) (*[]domain.Product, int64, error) {
 	var products []Product
-	if err := r.DB.Where("category_id = ? AND is_active = ?", categoryID, true).Find(&products).Error; err != nil {
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	total, err := pagination.Find(r.DB.WithContext(ctx).Model(&Product{}).Where("category_id = ? AND is_active = ?", categoryID, true), p, &products)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
 	}
-	return productsToDomainn(products), nil
+	return productsToDomainn(products), total, nil
 }
 
-func (r *ProductRepository) Create(d *domain.Product) (*domain.Product, error) {
+// ExportInBatches walks the matching products in primary key order, loading batchSize of them
+// at a time so exporting a large catalog never holds it in memory at once.
+func (r *ProductRepository) ExportInBatches(ctx context.Context, filter domain.ProductExportFilter, batchSize int, fn func(batch *[]domain.Product) error) error {
+	query := r.DB.WithContext(ctx).Model(&Product{})
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if !filter.UpdatedSince.IsZero() {
+		query = query.Where("updated_at >= ?", filter.UpdatedSince)
+	}
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	var products []Product
+	if err := query.FindInBatches(&products, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(productsToDomainn(products))
+	}).Error; err != nil {
+		r.Logger.Error("Error exporting products", zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *ProductRepository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(r.DB.WithContext(ctx), &Product{}, publicID)
+}
+
+func (r *ProductRepository) Create(ctx context.Context, d *domain.Product) (*domain.Product, error) {
 	p := Product{Name: d.Name, Description: d.Description, SKU: d.SKU, Price: d.Price, Stock: d.Stock, CategoryID: d.CategoryID, ImageURL: d.ImageURL, IsActive: d.IsActive}
-	if err := r.DB.Create(&p).Error; err != nil {
+	if err := psql.Conn(ctx, r.DB).Create(&p).Error; err != nil {
 		r.Logger.Error("Error creating product", zap.Error(err))
-		byteErr, _ := json.Marshal(err)
-		var ge domainErrors.GormErr
-		if json.Unmarshal(byteErr, &ge) == nil && ge.Number == 1062 {
-			return nil, domainErrors.NewAppErrorWithType(domainErrors.ResourceAlreadyExists)
-		}
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return nil, translateProductError(err)
 	}
 	return productToDomain(&p), nil
 }
 
-func (r *ProductRepository) Update(id int, m map[string]interface{}) (*domain.Product, error) {
-	var p Product
-	p.ID = id
-	if err := r.DB.Model(&p).Updates(m).Error; err != nil {
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+// Update applies m only while the product is still at version, failing with a Conflict otherwise
+func (r *ProductRepository) Update(ctx context.Context, id, version int, m map[string]interface{}) (*domain.Product, error) {
+	if err := psql.UpdateVersioned[Product](psql.Conn(ctx, r.DB), id, version, m); err != nil {
+		return nil, translateProductError(err)
 	}
-	if err := r.DB.Where("id = ?", id).First(&p).Error; err != nil {
+	var p Product
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&p).Error; err != nil {
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 	}
 	return productToDomain(&p), nil
 }
 
-func (r *ProductRepository) Delete(id int) error {
-	tx := r.DB.Delete(&Product{}, id)
+func (r *ProductRepository) UpdateRating(ctx context.Context, rating contracts.ProductRating) error {
+	tx := psql.Conn(ctx, r.DB).Model(&Product{}).
+		Where("id = ? AND (rating_updated_at IS NULL OR rating_updated_at < ?)", rating.ProductID, rating.UpdatedAt).
+		UpdateColumns(map[string]any{"rating_average": rating.Average, "rating_count": rating.Count, "rating_updated_at": rating.UpdatedAt})
+	if tx.Error != nil {
+		r.Logger.Error("Error updating product rating", zap.Int("productID", rating.ProductID), zap.Error(tx.Error))
+		return psql.TranslateError(tx.Error)
+	}
+	return nil
+}
+
+func (r *ProductRepository) SaveOutboxEvent(ctx context.Context, event *events.Event) error {
+	return saveOutboxEvent(ctx, r.DB, r.Logger, event)
+}
+
+// translateProductError reports a duplicate SKU, the product's only unique column, with its own code
+func translateProductError(err error) error {
+	if psql.IsUniqueViolation(err) {
+		return domainErrors.NewAppError(errors.New("a product with this SKU already exists"), domainErrors.ResourceAlreadyExists).
+			WithCode(domainErrors.CodeSKUConflict)
+	}
+	return psql.TranslateError(err)
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&Product{}, id)
 	if tx.Error != nil {
-		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return psql.TranslateError(tx.Error)
 	}
 	if tx.RowsAffected == 0 {
 		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
@@ -203,8 +351,131 @@ func (r *ProductRepository) Delete(id int) error {
 	return nil
 }
 
+// --- Stock Reservation Repository ---
+
+type ReservationRepositoryInterface interface {
+	// Reserve takes the items out of stock. Reserving a reference that already exists
+	// returns the existing reservation, so callers can safely retry.
+	Reserve(ctx context.Context, d *domain.StockReservation) (*domain.StockReservation, error)
+	// Release puts the reserved units back into stock; releasing twice is a no-op
+	Release(ctx context.Context, reference string) (*domain.StockReservation, error)
+}
+
+type ReservationRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewReservationRepository(db *gorm.DB, l *logger.Logger) ReservationRepositoryInterface {
+	return &ReservationRepository{DB: db, Logger: l}
+}
+
+func (r *ReservationRepository) Reserve(ctx context.Context, d *domain.StockReservation) (*domain.StockReservation, error) {
+	quantities := map[int]int{}
+	for _, it := range d.Items {
+		quantities[it.ProductID] += it.Quantity
+	}
+	productIDs := make([]int, 0, len(quantities))
+	for id := range quantities {
+		productIDs = append(productIDs, id)
+	}
+	// lock products in id order so concurrent reservations cannot deadlock
+	sort.Ints(productIDs)
+
+	var res StockReservation
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Preload("Items").Where("reference = ?", d.Reference).First(&res).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		res = StockReservation{Reference: d.Reference, Status: domain.ReservationStatusActive}
+		for _, id := range productIDs {
+			var p Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&p).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domainErrors.NewAppError(fmt.Errorf("product %d not found", id), domainErrors.NotFound)
+				}
+				return err
+			}
+			if !p.IsActive || p.Stock < quantities[id] {
+				return domainErrors.NewAppError(fmt.Errorf("insufficient stock for product %d", id), domainErrors.ValidationError)
+			}
+			if err := tx.Model(&p).Updates(stockChange("stock - ?", quantities[id])).Error; err != nil {
+				return err
+			}
+			res.Items = append(res.Items, StockReservationItem{ProductID: id, Quantity: quantities[id]})
+		}
+		return tx.Create(&res).Error
+	})
+	if err != nil {
+		var appErr *domainErrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		r.Logger.Error("Error reserving stock", zap.String("reference", d.Reference), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return reservationToDomain(&res), nil
+}
+
+func (r *ReservationRepository) Release(ctx context.Context, reference string) (*domain.StockReservation, error) {
+	var res StockReservation
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("reference = ?", reference).First(&res).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("reservation_id = ?", res.ID).Find(&res.Items).Error; err != nil {
+			return err
+		}
+		if res.Status == domain.ReservationStatusReleased {
+			return nil
+		}
+		for _, it := range res.Items {
+			if err := tx.Model(&Product{}).Where("id = ?", it.ProductID).Updates(stockChange("stock + ?", it.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+		res.Status = domain.ReservationStatusReleased
+		return tx.Model(&res).Update("status", res.Status).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		r.Logger.Error("Error releasing stock", zap.String("reference", reference), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return reservationToDomain(&res), nil
+}
+
+// stockChange adjusts stock and bumps the version, so an edit made from a copy read before
+// the reservation conflicts instead of overwriting the new stock
+func stockChange(expr string, quantity int) map[string]any {
+	return map[string]any{"stock": gorm.Expr(expr, quantity), "version": gorm.Expr("version + 1")}
+}
+
+func reservationToDomain(r *StockReservation) *domain.StockReservation {
+	items := make([]domain.ReservationItem, len(r.Items))
+	for i, it := range r.Items {
+		items[i] = domain.ReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	return &domain.StockReservation{Reference: r.Reference, Status: r.Status, Items: items, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+}
+
+func categoriesToDomain(cats []Category) *[]domain.Category {
+	result := make([]domain.Category, len(cats))
+	for i, c := range cats {
+		result[i] = domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Description: c.Description, Slug: c.Slug, Tenant: c.TenantID, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt}
+	}
+	return &result
+}
+
 func productToDomain(p *Product) *domain.Product {
-	return &domain.Product{ID: p.ID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, Stock: p.Stock, CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
+	return &domain.Product{ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, Stock: p.Stock, CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive,
+		RatingAverage: p.RatingAverage, RatingCount: p.RatingCount, Version: p.Version, Tenant: p.TenantID, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
 }
 
 func productsToDomainn(products []Product) *[]domain.Product {
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/catalog/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// errRolledBack rolls back an atomic bulk write once one of its items failed
+var errRolledBack = errors.New("bulk write rolled back")
+
+// writeEach runs write for every item in one transaction, each in a savepoint of its own so a
+// failed item undoes only its own writes. When atomic, one failure rolls back every item and the
+// results of the others are left empty. The error is only set when the transaction itself failed.
+func writeEach[In, Out any](ctx context.Context, txManager psql.TxManager, items []In, atomic bool,
+	write func(ctx context.Context, i int, item In) (*Out, error)) ([]domain.BulkResult[Out], error) {
+	results := make([]domain.BulkResult[Out], len(items))
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		failed := false
+		for i, item := range items {
+			err := txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+				out, err := write(ctx, i, item)
+				results[i].Item = out
+				return err
+			})
+			if err != nil {
+				results[i] = domain.BulkResult[Out]{Err: err}
+				failed = true
+			}
+		}
+		if atomic && failed {
+			return errRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errRolledBack) {
+		for i := range results {
+			results[i].Item = nil
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *CategoryUseCase) CreateMany(ctx context.Context, cs []domain.Category, atomic bool) ([]domain.BulkResult[domain.Category], error) {
+	if err := s.access.Enforce(ctx, categoryObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Creating categories in bulk", zap.Int("count", len(cs)), zap.Bool("atomic", atomic))
+	results, err := writeEach(ctx, s.txManager, cs, atomic, func(ctx context.Context, _ int, c domain.Category) (*domain.Category, error) {
+		created, err := s.repo.Create(ctx, &c)
+		if err != nil {
+			return nil, err
+		}
+		return created, publishCategory(ctx, s.repo, contracts.CategoryUpserted, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if r.Item != nil {
+			s.auditor.Record(ctx, audit.ActionCreate, auditEntityCategory, r.Item.ID, nil, r.Item)
+		}
+	}
+	return results, nil
+}
+
+func (s *CategoryUseCase) UpdateMany(ctx context.Context, updates []domain.CategoryUpdate, atomic bool) ([]domain.BulkResult[domain.Category], error) {
+	if err := s.access.Enforce(ctx, categoryObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Updating categories in bulk", zap.Int("count", len(updates)), zap.Bool("atomic", atomic))
+	befores := make([]*domain.Category, len(updates))
+	results, err := writeEach(ctx, s.txManager, updates, atomic, func(ctx context.Context, i int, u domain.CategoryUpdate) (*domain.Category, error) {
+		before, err := s.repo.GetByID(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		befores[i] = before
+		updated, err := s.repo.Update(ctx, u.ID, u.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return updated, publishCategory(ctx, s.repo, contracts.CategoryUpserted, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(updates))
+	for i, r := range results {
+		if r.Item != nil {
+			ids = append(ids, r.Item.ID)
+			s.auditor.Record(ctx, audit.ActionUpdate, auditEntityCategory, r.Item.ID, befores[i], r.Item)
+		}
+	}
+	s.invalidate(ctx, ids...)
+	return results, nil
+}
+
+func (s *ProductUseCase) CreateMany(ctx context.Context, ps []domain.Product, atomic bool) ([]domain.BulkResult[domain.Product], error) {
+	if err := s.access.Enforce(ctx, productObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Creating products in bulk", zap.Int("count", len(ps)), zap.Bool("atomic", atomic))
+	results, err := writeEach(ctx, s.txManager, ps, atomic, func(ctx context.Context, _ int, p domain.Product) (*domain.Product, error) {
+		created, err := s.repo.Create(ctx, &p)
+		if err != nil {
+			return nil, err
+		}
+		return created, publishProduct(ctx, s.repo, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if r.Item != nil {
+			s.auditor.Record(ctx, audit.ActionCreate, auditEntityProduct, r.Item.ID, nil, r.Item)
+		}
+	}
+	return results, nil
+}
+
+func (s *ProductUseCase) UpdateMany(ctx context.Context, updates []domain.ProductUpdate, atomic bool) ([]domain.BulkResult[domain.Product], error) {
+	if err := s.access.Enforce(ctx, productObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Updating products in bulk", zap.Int("count", len(updates)), zap.Bool("atomic", atomic))
+	befores := make([]*domain.Product, len(updates))
+	results, err := writeEach(ctx, s.txManager, updates, atomic, func(ctx context.Context, i int, u domain.ProductUpdate) (*domain.Product, error) {
+		before, err := s.repo.GetByID(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		befores[i] = before
+		version := u.Version
+		if version == 0 {
+			version = before.Version
+		}
+		updated, err := s.repo.Update(ctx, u.ID, version, u.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return updated, publishProduct(ctx, s.repo, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		if r.Item != nil {
+			s.auditor.Record(ctx, audit.ActionUpdate, auditEntityProduct, r.Item.ID, befores[i], r.Item)
+		}
+	}
+	return results, nil
+}
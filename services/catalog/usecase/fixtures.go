@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/catalog/domain"
+	"ecommerce-microservice-go/services/catalog/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FixtureSeeder upserts the categories of a fixture set by slug and its products by SKU. Every
+// write publishes the same event as the API, so the search index and media references follow.
+type FixtureSeeder struct {
+	categoryRepo repository.CategoryRepositoryInterface
+	productRepo  repository.ProductRepositoryInterface
+	txManager    psql.TxManager
+	Logger       *logger.Logger
+}
+
+func NewFixtureSeeder(categoryRepo repository.CategoryRepositoryInterface, productRepo repository.ProductRepositoryInterface, txManager psql.TxManager, l *logger.Logger) fixtures.Seeder {
+	return &FixtureSeeder{categoryRepo: categoryRepo, productRepo: productRepo, txManager: txManager, Logger: l}
+}
+
+func (s *FixtureSeeder) Seed(ctx context.Context, set *fixtures.Set) (fixtures.Result, error) {
+	var result fixtures.Result
+	for _, f := range set.Categories {
+		if err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+			return s.seedCategory(ctx, f, &result)
+		}); err != nil {
+			return result, fmt.Errorf("category %s: %w", f.Slug, err)
+		}
+	}
+	for _, f := range set.Products {
+		if err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+			return s.seedProduct(ctx, f, &result)
+		}); err != nil {
+			return result, fmt.Errorf("product %s: %w", f.SKU, err)
+		}
+	}
+	s.Logger.Info("Seeded catalog", zap.Int("created", result.Created), zap.Int("updated", result.Updated), zap.Int("unchanged", result.Unchanged))
+	return result, nil
+}
+
+func (s *FixtureSeeder) seedCategory(ctx context.Context, f fixtures.Category, result *fixtures.Result) error {
+	existing, err := s.categoryRepo.GetBySlug(ctx, f.Slug)
+	if errors.Is(err, domainErrors.ErrNotFound) {
+		created, err := s.categoryRepo.Create(ctx, &domain.Category{Slug: f.Slug, Name: f.Name, Description: f.Description})
+		if err != nil {
+			return err
+		}
+		result.Created++
+		return publishCategory(ctx, s.categoryRepo, contracts.CategoryUpserted, created)
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Name == f.Name && existing.Description == f.Description {
+		result.Unchanged++
+		return nil
+	}
+	updated, err := s.categoryRepo.Update(ctx, existing.ID, map[string]interface{}{"name": f.Name, "description": f.Description})
+	if err != nil {
+		return err
+	}
+	result.Updated++
+	return publishCategory(ctx, s.categoryRepo, contracts.CategoryUpserted, updated)
+}
+
+func (s *FixtureSeeder) seedProduct(ctx context.Context, f fixtures.Product, result *fixtures.Result) error {
+	category, err := s.categoryRepo.GetBySlug(ctx, f.Category)
+	if errors.Is(err, domainErrors.ErrNotFound) {
+		return fmt.Errorf("category %q does not exist", f.Category)
+	}
+	if err != nil {
+		return err
+	}
+	active := f.Active == nil || *f.Active
+	existing, err := s.productRepo.GetBySKU(ctx, f.SKU)
+	created := false
+	if errors.Is(err, domainErrors.ErrNotFound) {
+		existing, err = s.productRepo.Create(ctx, &domain.Product{
+			SKU: f.SKU, Name: f.Name, Description: f.Description, Price: f.Price, Stock: f.Stock,
+			CategoryID: category.ID, ImageURL: f.ImageURL, IsActive: active,
+		})
+		created = true
+	}
+	if err != nil {
+		return err
+	}
+	// compared after creating too, since an insert leaves a false IsActive to the column default
+	changes := map[string]interface{}{}
+	if existing.Name != f.Name {
+		changes["name"] = f.Name
+	}
+	if existing.Description != f.Description {
+		changes["description"] = f.Description
+	}
+	if existing.Price != f.Price {
+		changes["price"] = f.Price
+	}
+	if existing.Stock != f.Stock {
+		changes["stock"] = f.Stock
+	}
+	if existing.CategoryID != category.ID {
+		changes["category_id"] = category.ID
+	}
+	if existing.ImageURL != f.ImageURL {
+		changes["image_url"] = f.ImageURL
+	}
+	if existing.IsActive != active {
+		changes["is_active"] = active
+	}
+	if len(changes) > 0 {
+		if existing, err = s.productRepo.Update(ctx, existing.ID, existing.Version, changes); err != nil {
+			return err
+		}
+	}
+	switch {
+	case created:
+		result.Created++
+	case len(changes) > 0:
+		result.Updated++
+	default:
+		result.Unchanged++
+		return nil
+	}
+	return publishProduct(ctx, s.productRepo, existing)
+}
@@ -1,94 +1,431 @@
 package usecase
 
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
 import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/tenant"
 	"ecommerce-microservice-go/services/catalog/domain"
 	"ecommerce-microservice-go/services/catalog/repository"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	auditEntityCategory = "category"
+	auditEntityProduct  = "product"
+)
+
+// Categories and products have no owner; the authorization policies decide who may write them,
+// by default staff and admins
+var (
+	categoryObject = authz.Object{Resource: "category"}
+	productObject  = authz.Object{Resource: "product"}
 )
 
 // --- Category UseCase ---
 
 type ICategoryUseCase interface {
-	GetAll() (*[]domain.Category, error)
-	GetByID(id int) (*domain.Category, error)
-	Create(c *domain.Category) (*domain.Category, error)
-	Update(id int, m map[string]interface{}) (*domain.Category, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Category, int64, error)
+	Search(ctx context.Context, s pagination.Search) (*[]domain.Category, int64, error)
+	GetByID(ctx context.Context, id int) (*domain.Category, error)
+	// ResolveID returns the integer ID of the category with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	Create(ctx context.Context, c *domain.Category) (*domain.Category, error)
+	Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Category, error)
+	// CreateMany and UpdateMany write the categories in one transaction with a result per item;
+	// when atomic, one failed item rolls back all of them
+	CreateMany(ctx context.Context, cs []domain.Category, atomic bool) ([]domain.BulkResult[domain.Category], error)
+	UpdateMany(ctx context.Context, updates []domain.CategoryUpdate, atomic bool) ([]domain.BulkResult[domain.Category], error)
+	Delete(ctx context.Context, id int) error
 }
 
 type CategoryUseCase struct {
-	repo   repository.CategoryRepositoryInterface
-	Logger *logger.Logger
+	repo      repository.CategoryRepositoryInterface
+	txManager psql.TxManager
+	cache     *cache.Cache
+	access    authz.Enforcer
+	auditor   audit.Auditor
+	Logger    *logger.Logger
 }
 
-func NewCategoryUseCase(r repository.CategoryRepositoryInterface, l *logger.Logger) ICategoryUseCase {
-	return &CategoryUseCase{repo: r, Logger: l}
+// categoryCacheTTL is short enough that a missed invalidation on another replica heals quickly
+const categoryCacheTTL = 10 * time.Minute
+
+func NewCategoryUseCase(r repository.CategoryRepositoryInterface, txManager psql.TxManager, c *cache.Cache, access authz.Enforcer,
+	auditor audit.Auditor, l *logger.Logger) ICategoryUseCase {
+	return &CategoryUseCase{repo: r, txManager: txManager, cache: c, access: access, auditor: auditor, Logger: l}
 }
 
-func (s *CategoryUseCase) GetAll() (*[]domain.Category, error) {
-	s.Logger.Info("Getting all categories")
-	return s.repo.GetAll()
+func (s *CategoryUseCase) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Category, int64, error) {
+	s.Logger.Info("Getting all categories", zap.Int("page", p.Page), zap.Int("limit", p.Limit))
+	return s.repo.GetAll(ctx, p)
+}
+func (s *CategoryUseCase) Search(ctx context.Context, search pagination.Search) (*[]domain.Category, int64, error) {
+	s.Logger.Info("Searching categories", zap.Int("page", search.Page), zap.Int("limit", search.Limit))
+	return s.repo.SearchPaginated(ctx, search)
 }
-func (s *CategoryUseCase) GetByID(id int) (*domain.Category, error) {
+func (s *CategoryUseCase) GetByID(ctx context.Context, id int) (*domain.Category, error) {
 	s.Logger.Info("Getting category by ID", zap.Int("id", id))
-	return s.repo.GetByID(id)
+	return cache.GetOrLoad(ctx, s.cache, s.categoryKey(ctx, id), categoryCacheTTL, func(ctx context.Context) (*domain.Category, error) {
+		return s.repo.GetByID(ctx, id)
+	})
+}
+func (s *CategoryUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
 }
-func (s *CategoryUseCase) Create(c *domain.Category) (*domain.Category, error) {
+func (s *CategoryUseCase) Create(ctx context.Context, c *domain.Category) (*domain.Category, error) {
+	if err := s.access.Enforce(ctx, categoryObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
 	s.Logger.Info("Creating category", zap.String("name", c.Name))
-	return s.repo.Create(c)
+	var created *domain.Category
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		var err error
+		if created, err = s.repo.Create(ctx, c); err != nil {
+			return err
+		}
+		return publishCategory(ctx, s.repo, contracts.CategoryUpserted, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionCreate, auditEntityCategory, created.ID, nil, created)
+	return created, nil
 }
-func (s *CategoryUseCase) Update(id int, m map[string]interface{}) (*domain.Category, error) {
+func (s *CategoryUseCase) Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Category, error) {
+	if err := s.access.Enforce(ctx, categoryObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
 	s.Logger.Info("Updating category", zap.Int("id", id))
-	return s.repo.Update(id, m)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var updated *domain.Category
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if updated, err = s.repo.Update(ctx, id, m); err != nil {
+			return err
+		}
+		return publishCategory(ctx, s.repo, contracts.CategoryUpserted, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, id)
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityCategory, id, before, updated)
+	return updated, nil
 }
-func (s *CategoryUseCase) Delete(id int) error {
+func (s *CategoryUseCase) Delete(ctx context.Context, id int) error {
+	if err := s.access.Enforce(ctx, categoryObject, authz.ActionWrite); err != nil {
+		return err
+	}
 	s.Logger.Info("Deleting category", zap.Int("id", id))
-	return s.repo.Delete(id)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return publishCategory(ctx, s.repo, contracts.CategoryDeleted, before)
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidate(ctx, id)
+	s.auditor.Record(ctx, audit.ActionDelete, auditEntityCategory, id, before, nil)
+	return nil
+}
+
+// publishCategory queues the category event for the relay. The event belongs to the
+// category's tenant, also when the system changed it acting across tenants.
+func publishCategory(ctx context.Context, repo repository.CategoryRepositoryInterface, contract contracts.Contract[contracts.Category], c *domain.Category) error {
+	event, err := contract.NewEvent(tenant.ContextWithTenant(ctx, c.Tenant), contracts.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Slug: c.Slug})
+	if err != nil {
+		return err
+	}
+	return repo.SaveOutboxEvent(ctx, event)
+}
+
+func (s *CategoryUseCase) categoryKey(ctx context.Context, id int) string {
+	return s.cache.Key(ctx, "category", strconv.Itoa(id))
+}
+
+// invalidate drops the given categories after a write
+func (s *CategoryUseCase) invalidate(ctx context.Context, ids ...int) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, s.categoryKey(ctx, id))
+	}
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		s.Logger.Warn("Error invalidating category cache", zap.Error(err))
+	}
 }
 
 // --- Product UseCase ---
 
 type IProductUseCase interface {
-	GetAll() (*[]domain.Product, error)
-	GetByID(id int) (*domain.Product, error)
-	GetByCategory(categoryID int) (*[]domain.Product, error)
-	Create(p *domain.Product) (*domain.Product, error)
-	Update(id int, m map[string]interface{}) (*domain.Product, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Product, int64, error)
+	Search(ctx context.Context, s pagination.Search) (*[]domain.Product, int64, error)
+	GetByID(ctx context.Context, id int) (*domain.Product, error)
+	// ResolveID returns the integer ID of the product with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	GetByCategory(ctx context.Context, categoryID int, p pagination.Params) (*[]domain.Product, int64, error)
+	// Export streams the matching products to fn in batches
+	Export(ctx context.Context, filter domain.ProductExportFilter, fn func(batch *[]domain.Product) error) error
+	Create(ctx context.Context, p *domain.Product) (*domain.Product, error)
+	Update(ctx context.Context, id, version int, m map[string]interface{}) (*domain.Product, error)
+	// CreateMany and UpdateMany write the products in one transaction with a result per item;
+	// when atomic, one failed item rolls back all of them
+	CreateMany(ctx context.Context, ps []domain.Product, atomic bool) ([]domain.BulkResult[domain.Product], error)
+	UpdateMany(ctx context.Context, updates []domain.ProductUpdate, atomic bool) ([]domain.BulkResult[domain.Product], error)
+	Delete(ctx context.Context, id int) error
 }
 
 type ProductUseCase struct {
-	repo   repository.ProductRepositoryInterface
-	Logger *logger.Logger
+	repo      repository.ProductRepositoryInterface
+	txManager psql.TxManager
+	access    authz.Enforcer
+	auditor   audit.Auditor
+	Logger    *logger.Logger
 }
 
-func NewProductUseCase(r repository.ProductRepositoryInterface, l *logger.Logger) IProductUseCase {
-	return &ProductUseCase{repo: r, Logger: l}
+func NewProductUseCase(r repository.ProductRepositoryInterface, txManager psql.TxManager, access authz.Enforcer, auditor audit.Auditor,
+	l *logger.Logger) IProductUseCase {
+	return &ProductUseCase{repo: r, txManager: txManager, access: access, auditor: auditor, Logger: l}
 }
 
-func (s *ProductUseCase) GetAll() (*[]domain.Product, error) {
-	s.Logger.Info("Getting all products")
-	return s.repo.GetAll()
+func (s *ProductUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
+}
+
+func (s *ProductUseCase) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Product, int64, error) {
+	s.Logger.Info("Getting all products", zap.Int("page", p.Page), zap.Int("limit", p.Limit))
+	return s.repo.GetAll(ctx, p)
+}
+func (s *ProductUseCase) Search(ctx context.Context, search pagination.Search) (*[]domain.Product, int64, error) {
+	s.Logger.Info("Searching products", zap.Int("page", search.Page), zap.Int("limit", search.Limit))
+	return s.repo.SearchPaginated(ctx, search)
 }
-func (s *ProductUseCase) GetByID(id int) (*domain.Product, error) {
+func (s *ProductUseCase) GetByID(ctx context.Context, id int) (*domain.Product, error) {
 	s.Logger.Info("Getting product by ID", zap.Int("id", id))
-	return s.repo.GetByID(id)
+	return s.repo.GetByID(ctx, id)
 }
-func (s *ProductUseCase) GetByCategory(categoryID int) (*[]domain.Product, error) {
-	s.Logger.Info("Getting products by category", zap.Int("categoryID", categoryID))
-	return s.repo.GetByCategory(categoryID)
+func (s *ProductUseCase) GetByCategory(ctx context.Context, categoryID int, p pagination.Params) (*[]domain.Product, int64, error) {
+	s.Logger.Info("Getting products by category", zap.Int("categoryID", categoryID), zap.Int("page", p.Page))
+	return s.repo.GetByCategory(ctx, categoryID, p)
 }
-func (s *ProductUseCase) Create(p *domain.Product) (*domain.Product, error) {
+
+const exportBatchSize = 500
+
+func (s *ProductUseCase) Export(ctx context.Context, filter domain.ProductExportFilter, fn func(batch *[]domain.Product) error) error {
+	s.Logger.Info("Exporting products", zap.Int("categoryID", filter.CategoryID), zap.Time("updatedSince", filter.UpdatedSince),
+		zap.Bool("includeInactive", filter.IncludeInactive))
+	return s.repo.ExportInBatches(ctx, filter, exportBatchSize, fn)
+}
+func (s *ProductUseCase) Create(ctx context.Context, p *domain.Product) (*domain.Product, error) {
+	if err := s.access.Enforce(ctx, productObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
 	s.Logger.Info("Creating product", zap.String("name", p.Name))
-	return s.repo.Create(p)
+	var created *domain.Product
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		var err error
+		if created, err = s.repo.Create(ctx, p); err != nil {
+			return err
+		}
+		return publishProduct(ctx, s.repo, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionCreate, auditEntityProduct, created.ID, nil, created)
+	return created, nil
 }
-func (s *ProductUseCase) Update(id int, m map[string]interface{}) (*domain.Product, error) {
+
+// Update changes the product if it is still at version, the one the client read; a zero
+// version updates whatever this call loaded, which still rejects a write that lands in between
+func (s *ProductUseCase) Update(ctx context.Context, id, version int, m map[string]interface{}) (*domain.Product, error) {
+	if err := s.access.Enforce(ctx, productObject, authz.ActionWrite); err != nil {
+		return nil, err
+	}
 	s.Logger.Info("Updating product", zap.Int("id", id))
-	return s.repo.Update(id, m)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		version = before.Version
+	}
+	var updated *domain.Product
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if updated, err = s.repo.Update(ctx, id, version, m); err != nil {
+			return err
+		}
+		return publishProduct(ctx, s.repo, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityProduct, id, before, updated)
+	return updated, nil
 }
-func (s *ProductUseCase) Delete(id int) error {
+func (s *ProductUseCase) Delete(ctx context.Context, id int) error {
+	if err := s.access.Enforce(ctx, productObject, authz.ActionWrite); err != nil {
+		return err
+	}
 	s.Logger.Info("Deleting product", zap.Int("id", id))
-	return s.repo.Delete(id)
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		event, err := contracts.ProductDeleted.NewEvent(tenant.ContextWithTenant(ctx, before.Tenant), contracts.Product{ID: id, UUID: before.UUID})
+		if err != nil {
+			return err
+		}
+		return s.repo.SaveOutboxEvent(ctx, event)
+	})
+	if err != nil {
+		return err
+	}
+	s.auditor.Record(ctx, audit.ActionDelete, auditEntityProduct, id, before, nil)
+	return nil
+}
+
+// publishProduct queues the product's current state for the relay; call it within the
+// transaction that changed the product. The event belongs to the product's tenant.
+func publishProduct(ctx context.Context, repo repository.ProductRepositoryInterface, p *domain.Product) error {
+	event, err := contracts.ProductUpserted.NewEvent(tenant.ContextWithTenant(ctx, p.Tenant), contracts.Product{
+		ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, Stock: p.Stock,
+		CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive, RatingAverage: p.RatingAverage,
+		RatingCount: p.RatingCount, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return repo.SaveOutboxEvent(ctx, event)
+}
+
+// publishProductByID reloads the product within the transaction and queues it
+func publishProductByID(ctx context.Context, repo repository.ProductRepositoryInterface, id int) error {
+	p, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return publishProduct(ctx, repo, p)
+}
+
+// --- Rating UseCase ---
+
+type IRatingUseCase interface {
+	// HandleRatingEvent stores the rating aggregate published by the review service
+	HandleRatingEvent(ctx context.Context, event *events.Event) error
+}
+
+type RatingUseCase struct {
+	repo      repository.ProductRepositoryInterface
+	txManager psql.TxManager
+	Logger    *logger.Logger
+}
+
+func NewRatingUseCase(r repository.ProductRepositoryInterface, txManager psql.TxManager, l *logger.Logger) IRatingUseCase {
+	return &RatingUseCase{repo: r, txManager: txManager, Logger: l}
+}
+
+func (s *RatingUseCase) HandleRatingEvent(ctx context.Context, event *events.Event) error {
+	if event.Type != contracts.RatingUpdated.EventType() {
+		return nil
+	}
+	rating, err := contracts.RatingUpdated.Decode(event)
+	if err != nil {
+		s.Logger.WithContext(ctx).Error("Discarding rating event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+		return nil
+	}
+	s.Logger.WithContext(ctx).Info("Updating product rating", zap.Int("productID", rating.ProductID), zap.Float64("average", rating.Average), zap.Int("count", rating.Count))
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if err := s.repo.UpdateRating(ctx, rating); err != nil {
+			return err
+		}
+		err := publishProductByID(ctx, s.repo, rating.ProductID)
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			// the product was deleted since the review; nothing to index
+			return nil
+		}
+		return err
+	})
+}
+
+// --- Reservation UseCase ---
+
+type IReservationUseCase interface {
+	Reserve(ctx context.Context, r *domain.StockReservation) (*domain.StockReservation, error)
+	Release(ctx context.Context, reference string) (*domain.StockReservation, error)
+}
+
+type ReservationUseCase struct {
+	repo      repository.ReservationRepositoryInterface
+	prodRepo  repository.ProductRepositoryInterface
+	txManager psql.TxManager
+	Logger    *logger.Logger
+}
+
+func NewReservationUseCase(r repository.ReservationRepositoryInterface, prodRepo repository.ProductRepositoryInterface, txManager psql.TxManager,
+	l *logger.Logger) IReservationUseCase {
+	return &ReservationUseCase{repo: r, prodRepo: prodRepo, txManager: txManager, Logger: l}
+}
+
+func (s *ReservationUseCase) Reserve(ctx context.Context, r *domain.StockReservation) (*domain.StockReservation, error) {
+	s.Logger.Info("Reserving stock", zap.String("reference", r.Reference), zap.Int("items", len(r.Items)))
+	return s.withStockChange(ctx, func(ctx context.Context) (*domain.StockReservation, error) {
+		return s.repo.Reserve(ctx, r)
+	})
+}
+func (s *ReservationUseCase) Release(ctx context.Context, reference string) (*domain.StockReservation, error) {
+	s.Logger.Info("Releasing stock", zap.String("reference", reference))
+	return s.withStockChange(ctx, func(ctx context.Context) (*domain.StockReservation, error) {
+		return s.repo.Release(ctx, reference)
+	})
+}
+
+// withStockChange runs fn and queues the new state of every product it reserved or released.
+// A replayed reservation queues them again, which consumers apply as a no-op.
+func (s *ReservationUseCase) withStockChange(ctx context.Context,
+	fn func(ctx context.Context) (*domain.StockReservation, error)) (*domain.StockReservation, error) {
+	var res *domain.StockReservation
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		var err error
+		if res, err = fn(ctx); err != nil {
+			return err
+		}
+		for _, it := range res.Items {
+			if err := publishProductByID(ctx, s.prodRepo, it.ProductID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/audit"
+	auditmocks "ecommerce-microservice-go/pkg/audit/mocks"
+	"ecommerce-microservice-go/pkg/authz"
+	authzmocks "ecommerce-microservice-go/pkg/authz/mocks"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	psqlmocks "ecommerce-microservice-go/pkg/psql/mocks"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/catalog/domain"
+	"ecommerce-microservice-go/services/catalog/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	errDB      = errors.New("connection reset")
+	tee        = domain.Product{ID: 7, UUID: "0b7e5c2a-6d1f-4f7b-8a39-2c4e9d1f6a53", Name: "Basic Tee", SKU: "TEE-01", Price: 25.5, Stock: 10,
+		CategoryID: 1, IsActive: true, Version: 3}
+)
+
+// inTransaction runs every transaction's function, as the real manager does when it commits
+func inTransaction(tx *psqlmocks.MockTxManager) {
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, *gorm.DB) error) error { return fn(ctx, nil) }).AnyTimes()
+}
+
+// productMocks are the dependencies of ProductUseCase
+type productMocks struct {
+	repo    *mocks.MockProductRepositoryInterface
+	access  *authzmocks.MockEnforcer
+	auditor *auditmocks.MockAuditor
+}
+
+func newProductUseCase(t *testing.T) (IProductUseCase, productMocks) {
+	ctrl := gomock.NewController(t)
+	m := productMocks{repo: mocks.NewMockProductRepositoryInterface(ctrl), access: authzmocks.NewMockEnforcer(ctrl),
+		auditor: auditmocks.NewMockAuditor(ctrl)}
+	tx := psqlmocks.NewMockTxManager(ctrl)
+	inTransaction(tx)
+	return NewProductUseCase(m.repo, tx, m.access, m.auditor, testLogger), m
+}
+
+func TestProductUseCaseCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m productMocks)
+		wantErr error
+	}{
+		{
+			name: "created, queued and audited",
+			setup: func(m productMocks) {
+				m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(nil)
+				m.repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&tee, nil)
+				m.repo.EXPECT().SaveOutboxEvent(gomock.Any(), eventOfType(contracts.ProductUpserted.EventType())).Return(nil)
+				m.auditor.EXPECT().Record(gomock.Any(), audit.ActionCreate, auditEntityProduct, tee.ID, nil, &tee)
+			},
+		},
+		{
+			name: "caller may not write products",
+			setup: func(m productMocks) {
+				m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(domainErrors.NewAppErrorWithType(domainErrors.NotAuthorized))
+			},
+			wantErr: domainErrors.ErrNotAuthorized,
+		},
+		{
+			name: "outbox failure is not audited",
+			setup: func(m productMocks) {
+				m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(nil)
+				m.repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&tee, nil)
+				m.repo.EXPECT().SaveOutboxEvent(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newProductUseCase(t)
+			tt.setup(m)
+			p := tee
+			got, err := uc.Create(context.Background(), &p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != tee.ID {
+				t.Errorf("created product %d, want %d", got.ID, tee.ID)
+			}
+		})
+	}
+}
+
+func TestProductUseCaseUpdate(t *testing.T) {
+	changes := map[string]interface{}{"price": 30.0}
+	updated := tee
+	updated.Price = 30
+	// updates expects an update at version that succeeds
+	updates := func(version int) func(m productMocks) {
+		return func(m productMocks) {
+			m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(nil)
+			m.repo.EXPECT().GetByID(gomock.Any(), tee.ID).Return(&tee, nil)
+			m.repo.EXPECT().Update(gomock.Any(), tee.ID, version, changes).Return(&updated, nil)
+			m.repo.EXPECT().SaveOutboxEvent(gomock.Any(), eventOfType(contracts.ProductUpserted.EventType())).Return(nil)
+			m.auditor.EXPECT().Record(gomock.Any(), audit.ActionUpdate, auditEntityProduct, tee.ID, &tee, &updated)
+		}
+	}
+	tests := []struct {
+		name    string
+		version int
+		setup   func(m productMocks)
+		wantErr error
+	}{
+		{
+			name:    "version the client read",
+			version: 2,
+			setup:   updates(2),
+		},
+		{
+			name:  "no version updates the loaded one",
+			setup: updates(tee.Version),
+		},
+		{
+			name: "missing product",
+			setup: func(m productMocks) {
+				m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(nil)
+				m.repo.EXPECT().GetByID(gomock.Any(), tee.ID).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantErr: domainErrors.ErrNotFound,
+		},
+		{
+			name:    "stale version",
+			version: 2,
+			setup: func(m productMocks) {
+				m.access.EXPECT().Enforce(gomock.Any(), productObject, authz.ActionWrite).Return(nil)
+				m.repo.EXPECT().GetByID(gomock.Any(), tee.ID).Return(&tee, nil)
+				m.repo.EXPECT().Update(gomock.Any(), tee.ID, 2, changes).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.Conflict))
+			},
+			wantErr: domainErrors.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newProductUseCase(t)
+			tt.setup(m)
+			if _, err := uc.Update(context.Background(), tee.ID, tt.version, changes); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReservationUseCaseReserve(t *testing.T) {
+	reservation := domain.StockReservation{Reference: "order-1", Items: []domain.ReservationItem{{ProductID: 7, Quantity: 2}, {ProductID: 8, Quantity: 1}}}
+	tests := []struct {
+		name    string
+		setup   func(repo *mocks.MockReservationRepositoryInterface, products *mocks.MockProductRepositoryInterface)
+		wantErr error
+	}{
+		{
+			name: "every reserved product is queued",
+			setup: func(repo *mocks.MockReservationRepositoryInterface, products *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(&reservation, nil)
+				for _, id := range []int{7, 8} {
+					p := tee
+					p.ID = id
+					products.EXPECT().GetByID(gomock.Any(), id).Return(&p, nil)
+				}
+				products.EXPECT().SaveOutboxEvent(gomock.Any(), eventOfType(contracts.ProductUpserted.EventType())).Return(nil).Times(2)
+			},
+		},
+		{
+			name: "insufficient stock queues nothing",
+			setup: func(repo *mocks.MockReservationRepositoryInterface, _ *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.ValidationError))
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "product deleted since the reservation",
+			setup: func(repo *mocks.MockReservationRepositoryInterface, products *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(&reservation, nil)
+				products.EXPECT().GetByID(gomock.Any(), 7).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantErr: domainErrors.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, products, tx := mocks.NewMockReservationRepositoryInterface(ctrl), mocks.NewMockProductRepositoryInterface(ctrl), psqlmocks.NewMockTxManager(ctrl)
+			inTransaction(tx)
+			tt.setup(repo, products)
+			r := reservation
+			_, err := NewReservationUseCase(repo, products, tx, testLogger).Reserve(context.Background(), &r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRatingUseCaseHandleRatingEvent(t *testing.T) {
+	rating := contracts.ProductRating{ProductID: 7, Average: 4.5, Count: 12}
+	ratingEvent, err := contracts.RatingUpdated.NewEvent(tenant.ContextWithTenant(context.Background(), "acme"), rating)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// published before events carried a tenant
+	untenantedEvent, err := contracts.RatingUpdated.NewEvent(context.Background(), rating)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acmeTee := tee
+	acmeTee.Tenant = "acme"
+	tests := []struct {
+		name    string
+		event   *events.Event
+		setup   func(repo *mocks.MockProductRepositoryInterface)
+		wantErr error
+	}{
+		{
+			name:  "rating stored and the product queued",
+			event: ratingEvent,
+			setup: func(repo *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().UpdateRating(inTenant("acme"), rating).Return(nil)
+				repo.EXPECT().GetByID(inTenant("acme"), 7).Return(&acmeTee, nil)
+				repo.EXPECT().SaveOutboxEvent(gomock.Any(), eventInTenant{contracts.ProductUpserted.EventType(), "acme"}).Return(nil)
+			},
+		},
+		{
+			name:  "event without a tenant queues the product to its own tenant",
+			event: untenantedEvent,
+			setup: func(repo *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().UpdateRating(inTenant(""), rating).Return(nil)
+				repo.EXPECT().GetByID(inTenant(""), 7).Return(&acmeTee, nil)
+				repo.EXPECT().SaveOutboxEvent(gomock.Any(), eventInTenant{contracts.ProductUpserted.EventType(), "acme"}).Return(nil)
+			},
+		},
+		{
+			name:  "product deleted since the review",
+			event: ratingEvent,
+			setup: func(repo *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().UpdateRating(gomock.Any(), rating).Return(nil)
+				repo.EXPECT().GetByID(gomock.Any(), 7).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+		},
+		{
+			name:  "store failure is retried",
+			event: ratingEvent,
+			setup: func(repo *mocks.MockProductRepositoryInterface) {
+				repo.EXPECT().UpdateRating(gomock.Any(), rating).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "other event types are ignored",
+			event: &events.Event{ID: "1", Type: contracts.ProductDeleted.EventType()},
+		},
+		{
+			name:  "invalid payload is discarded",
+			event: &events.Event{ID: "1", Type: contracts.RatingUpdated.EventType(), Payload: json.RawMessage(`{"productId":"seven"}`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, tx := mocks.NewMockProductRepositoryInterface(ctrl), psqlmocks.NewMockTxManager(ctrl)
+			inTransaction(tx)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			if err := NewRatingUseCase(repo, tx, testLogger).HandleRatingEvent(tt.event.Context(context.Background()), tt.event); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// eventOfType matches an outbox event of the type
+type eventOfType string
+
+func (t eventOfType) Matches(x any) bool {
+	e, ok := x.(*events.Event)
+	return ok && e.Type == string(t)
+}
+
+func (t eventOfType) String() string { return "is a " + string(t) + " event" }
+
+// eventInTenant matches an outbox event of the type published to the tenant
+type eventInTenant struct{ eventType, tenant string }
+
+func (m eventInTenant) Matches(x any) bool {
+	e, ok := x.(*events.Event)
+	return ok && e.Type == m.eventType && e.TenantID == m.tenant
+}
+
+func (m eventInTenant) String() string { return "is a " + m.eventType + " event of tenant " + m.tenant }
+
+// inTenant matches a context scoped to the tenant, "" for one acting across tenants
+type inTenant string
+
+func (m inTenant) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	return ok && tenant.FromContext(ctx) == string(m)
+}
+
+func (m inTenant) String() string { return "is a context of tenant " + strconv.Quote(string(m)) }
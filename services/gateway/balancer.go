@@ -0,0 +1,192 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+const (
+	BalancingRoundRobin       = "round-robin"
+	BalancingLeastConnections = "least-connections"
+)
+
+// BalancerPolicy is how the gateway spreads the requests of a service over its instances
+type BalancerPolicy struct {
+	Strategy string
+	// an instance that failed to connect MaxFails times in a row is skipped for FailTimeout
+	MaxFails    int
+	FailTimeout time.Duration
+}
+
+// loadBalancerPolicies reads <NAME>_SERVICE_BALANCING, _MAX_FAILS and _FAIL_TIMEOUT_SECONDS for
+// each service, defaulting to the PROXY_* settings
+func loadBalancerPolicies(src *config.Source, services []string) map[string]BalancerPolicy {
+	def := BalancerPolicy{
+		Strategy:    src.String("PROXY_BALANCING", BalancingRoundRobin),
+		MaxFails:    src.Int("PROXY_MAX_FAILS", 1),
+		FailTimeout: src.Duration("PROXY_FAIL_TIMEOUT_SECONDS", 10, time.Second),
+	}
+	validateBalancerPolicy(src, "PROXY", def)
+	policies := make(map[string]BalancerPolicy, len(services))
+	for _, name := range services {
+		prefix := strings.ToUpper(name) + "_SERVICE"
+		p := BalancerPolicy{
+			Strategy:    src.String(prefix+"_BALANCING", def.Strategy),
+			MaxFails:    src.Int(prefix+"_MAX_FAILS", def.MaxFails),
+			FailTimeout: src.Duration(prefix+"_FAIL_TIMEOUT_SECONDS", int(def.FailTimeout/time.Second), time.Second),
+		}
+		validateBalancerPolicy(src, prefix, p)
+		policies[name] = p
+	}
+	return policies
+}
+
+func validateBalancerPolicy(src *config.Source, prefix string, p BalancerPolicy) {
+	if p.Strategy != BalancingRoundRobin && p.Strategy != BalancingLeastConnections {
+		src.Fail(prefix+"_BALANCING", "must be round-robin or least-connections")
+	}
+	if p.MaxFails < 1 {
+		src.Fail(prefix+"_MAX_FAILS", "must be at least 1")
+	}
+	if p.FailTimeout < 0 {
+		src.Fail(prefix+"_FAIL_TIMEOUT_SECONDS", "must not be negative")
+	}
+}
+
+// instance is one address of a service with the requests in flight to it and its failures
+type instance struct {
+	url    *url.URL
+	active atomic.Int64
+
+	mu        sync.Mutex
+	fails     int
+	downUntil time.Time
+}
+
+func (i *instance) available(now time.Time) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return !now.Before(i.downUntil)
+}
+
+// balancer is the transport of a service's proxy: every request, including each retry, goes to
+// the next instance by the policy's strategy, skipping those marked down. When all of them are
+// down it tries one anyway rather than fail without trying.
+type balancer struct {
+	instances []*instance
+	policy    BalancerPolicy
+	next      http.RoundTripper
+	turn      atomic.Uint64
+	log       *zap.Logger
+}
+
+func newBalancer(targets []*url.URL, policy BalancerPolicy, next http.RoundTripper, log *zap.Logger) *balancer {
+	b := &balancer{policy: policy, next: next, log: log}
+	for _, u := range targets {
+		b.instances = append(b.instances, &instance{url: u})
+	}
+	return b
+}
+
+func (b *balancer) RoundTrip(r *http.Request) (*http.Response, error) {
+	in := b.pick()
+	out := r.Clone(r.Context())
+	out.URL.Scheme, out.URL.Host = in.url.Scheme, in.url.Host
+	in.active.Add(1)
+	resp, err := b.next.RoundTrip(out)
+	if err != nil {
+		in.active.Add(-1)
+		if isConnectError(err) {
+			b.failed(in, err)
+		}
+		return nil, err
+	}
+	b.succeeded(in)
+	resp.Body = releaseOnClose(resp.Body, func() { in.active.Add(-1) })
+	return resp, nil
+}
+
+// pick starts at the next instance in turn, so round-robin takes the first available one and
+// least-connections breaks ties between instances fairly
+func (b *balancer) pick() *instance {
+	now := time.Now()
+	n := uint64(len(b.instances))
+	start := b.turn.Add(1) - 1
+	var best *instance
+	for k := uint64(0); k < n; k++ {
+		in := b.instances[(start+k)%n]
+		if !in.available(now) {
+			continue
+		}
+		if b.policy.Strategy == BalancingRoundRobin {
+			return in
+		}
+		if best == nil || in.active.Load() < best.active.Load() {
+			best = in
+		}
+	}
+	if best == nil {
+		return b.instances[start%n]
+	}
+	return best
+}
+
+func (b *balancer) failed(in *instance, err error) {
+	in.mu.Lock()
+	in.fails++
+	down := in.fails >= b.policy.MaxFails && len(b.instances) > 1
+	if down {
+		in.downUntil = time.Now().Add(b.policy.FailTimeout)
+	}
+	fails := in.fails
+	in.mu.Unlock()
+	if down {
+		b.log.Warn("Service instance marked down", zap.String("instance", in.url.String()), zap.Int("fails", fails),
+			zap.Duration("for", b.policy.FailTimeout), zap.Error(err))
+	}
+}
+
+func (b *balancer) succeeded(in *instance) {
+	in.mu.Lock()
+	recovered := in.fails >= b.policy.MaxFails
+	in.fails, in.downUntil = 0, time.Time{}
+	in.mu.Unlock()
+	if recovered && len(b.instances) > 1 {
+		b.log.Info("Service instance recovered", zap.String("instance", in.url.String()))
+	}
+}
+
+// releaseOnClose calls release once when body is closed. Bodies of upgraded connections stay
+// writable, as the proxy needs.
+func releaseOnClose(body io.ReadCloser, release func()) io.ReadCloser {
+	var once sync.Once
+	r := releasingBody{ReadCloser: body, release: func() { once.Do(release) }}
+	if rwc, ok := body.(io.ReadWriteCloser); ok {
+		return &releasingReadWriteBody{releasingBody: r, Writer: rwc}
+	}
+	return &r
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+type releasingReadWriteBody struct {
+	releasingBody
+	io.Writer
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BFFConfig tunes the endpoints the gateway composes from several services for the frontends
+type BFFConfig struct {
+	// CatalogConcurrency bounds the products of one order read from the catalog at once
+	CatalogConcurrency int
+}
+
+func loadBFFConfig(src *config.Source) BFFConfig {
+	cfg := BFFConfig{CatalogConcurrency: src.Int("BFF_CATALOG_CONCURRENCY", 8)}
+	if cfg.CatalogConcurrency < 1 {
+		src.Fail("BFF_CATALOG_CONCURRENCY", "must be at least 1")
+	}
+	return cfg
+}
+
+// forwardedHeaders are the headers of the client's request the BFF passes to the services, so
+// they see the same caller and tenant as through the proxy
+var forwardedHeaders = []string{"Authorization", middleware.UserIDHeader, middleware.UserRolesHeader, middleware.InternalTokenHeader, tenant.Header}
+
+// itemProduct is the catalog's entry for the product of an order item
+type itemProduct struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	SKU      string `json:"sku"`
+	ImageURL string `json:"imageUrl"`
+	IsActive bool   `json:"isActive"`
+}
+
+// bff serves the /v1/bff endpoints, which call the services themselves rather than proxy to
+// one of them. Each call goes to the instances of the service in turn until one answers.
+type bff struct {
+	services *routeTable
+	cfg      BFFConfig
+	timeouts map[string]TimeoutPolicy
+	http     *http.Client
+	turn     atomic.Uint64
+	log      *zap.Logger
+}
+
+func newBFF(services *routeTable, cfg BFFConfig, timeouts map[string]TimeoutPolicy, log *zap.Logger) *bff {
+	return &bff{services: services, cfg: cfg, timeouts: timeouts, log: log,
+		http: &http.Client{Transport: middleware.RequestIDTransport(otel.Transport(nil))}}
+}
+
+// RegisterRoutes adds the BFF endpoints to router; identify authenticates for the service
+// whose answer decides access
+func (b *bff) RegisterRoutes(router gin.IRoutes, identify func(audience string) gin.HandlerFunc) {
+	router.GET("/bff/order/:id", identify("order"), b.OrderDetails)
+}
+
+// OrderDetails answers GET /v1/bff/order/{id}: the order as the order service returns it to
+// the caller, each item with the name, SKU and image of its product from the catalog. Errors
+// of the order service, such as 404 or 403, are passed on as they are. A product the catalog
+// does not return leaves its items without "product" rather than fail the order.
+func (b *bff) OrderDetails(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := b.get(ctx, "order", "/v1/order/"+url.PathEscape(c.Param("id")), c.Request.Header)
+	if err != nil {
+		b.fail(c, "order", err)
+		return
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		c.Data(res.StatusCode, res.Header.Get("Content-Type"), body)
+		return
+	}
+	var order map[string]any
+	if err := controllers.DecodeData(res.Body, &order); err != nil {
+		b.fail(c, "order", fmt.Errorf("reading the order: %w", err))
+		return
+	}
+
+	items, _ := order["items"].([]any)
+	var ids []int
+	for _, item := range items {
+		if id, ok := productID(item); ok {
+			ids = append(ids, id)
+		}
+	}
+	// products are public: the catalog only needs the tenant
+	products := b.products(ctx, ids, http.Header{tenant.Header: {c.GetHeader(tenant.Header)}})
+	for _, item := range items {
+		if id, ok := productID(item); ok && products[id] != nil {
+			item.(map[string]any)["product"] = products[id]
+		}
+	}
+	controllers.Respond(c, http.StatusOK, order)
+}
+
+func productID(item any) (int, bool) {
+	fields, _ := item.(map[string]any)
+	id, ok := fields["productId"].(float64)
+	return int(id), ok
+}
+
+// products reads the distinct products of ids from the catalog in parallel, leaving out those
+// it could not read
+func (b *bff) products(ctx context.Context, ids []int, header http.Header) map[int]*itemProduct {
+	distinct := make(map[int]bool, len(ids))
+	products := make(map[int]*itemProduct, len(ids))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, b.cfg.CatalogConcurrency)
+	for _, id := range ids {
+		if distinct[id] {
+			continue
+		}
+		distinct[id] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			product, err := b.product(ctx, id, header)
+			if err != nil {
+				b.log.Warn("Order item left without its product", zap.Int("product_id", id), zap.Error(err))
+				return
+			}
+			mu.Lock()
+			products[id] = product
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return products
+}
+
+func (b *bff) product(ctx context.Context, id int, header http.Header) (*itemProduct, error) {
+	res, err := b.get(ctx, "catalog", "/v1/product/"+strconv.Itoa(id), header)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog service returned %d for product %d", res.StatusCode, id)
+	}
+	var product itemProduct
+	if err := controllers.DecodeData(res.Body, &product); err != nil {
+		return nil, fmt.Errorf("reading product %d: %w", id, err)
+	}
+	return &product, nil
+}
+
+// get sends GET path to the named service with the forwarded headers of header, within the
+// service's read timeout. Instances that cannot be reached are skipped; the first answer is
+// returned whatever its status.
+func (b *bff) get(ctx context.Context, service, path string, header http.Header) (*http.Response, error) {
+	timeout := b.timeouts[service].Read
+	if timeout <= 0 {
+		return b.send(ctx, service, path, header)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	res, err := b.send(ctx, service, path, header)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = releaseOnClose(res.Body, cancel)
+	return res, nil
+}
+
+func (b *bff) send(ctx context.Context, service, path string, header http.Header) (*http.Response, error) {
+	cfg := b.services.Get()
+	instances := *cfg.instances(service)
+	start := int(b.turn.Add(1) % uint64(len(instances)))
+	var errs []error
+	for k := range instances {
+		instance := strings.TrimSuffix(instances[(start+k)%len(instances)], "/")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, instance+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range forwardedHeaders {
+			if v := header.Get(name); v != "" {
+				req.Header.Set(name, v)
+			}
+		}
+		res, err := b.http.Do(req)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, fmt.Errorf("%s service request failed: %w", service, err))
+		if !isConnectError(err) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// fail answers 504 when the service timed out and 502 when it could not be reached or read
+func (b *bff) fail(c *gin.Context, service string, err error) {
+	b.log.Error("BFF request failed", zap.String("service", service), zap.String("path", c.Request.URL.Path),
+		zap.String("request_id", middleware.GetRequestID(c)), zap.Error(err))
+	if errors.Is(err, context.DeadlineExceeded) {
+		middleware.AbortWithError(c, http.StatusGatewayTimeout, domainErrors.CodeServiceTimeout, "service timed out")
+		return
+	}
+	middleware.AbortWithError(c, http.StatusBadGateway, domainErrors.CodeServiceUnavailable, "service unavailable")
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+// ServiceConfig is the route table: the URLs of the instances of each service the gateway
+// proxies to. It follows reloads, so instances can be added or moved without restarting the
+// gateway.
+type ServiceConfig struct {
+	UserURLs      []string
+	CatalogURLs   []string
+	OrderURLs     []string
+	PaymentURLs   []string
+	ReviewURLs    []string
+	SearchURLs    []string
+	WebhookURLs   []string
+	SagaURLs      []string
+	MediaURLs     []string
+	PromotionURLs []string
+	TaxURLs       []string
+}
+
+// serviceNames are the services behind the gateway, as named in their settings
+var serviceNames = []string{"user", "catalog", "order", "payment", "review", "search", "webhook", "saga", "media", "promotion", "tax"}
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	CSRF      config.CSRF
+	// Services are the configured instances, which Discovery may replace
+	Services  *config.Live[ServiceConfig]
+	Discovery DiscoveryConfig
+	Docs      DocsConfig
+	BFF       BFFConfig
+	// Transcode holds the REST routes answered by gRPC methods of the services
+	Transcode TranscodeConfig
+	Tenants   TenantConfig
+	// Retries holds the retry policy of each service by name, e.g. "catalog"
+	Retries map[string]RetryPolicy
+	// Balancers holds how the requests of each service are spread over its instances
+	Balancers map[string]BalancerPolicy
+	// Timeouts holds how long each service is given to answer
+	Timeouts map[string]TimeoutPolicy
+	// IPRateLimit is the allowance of every client address and TokenRateLimit that of every
+	// bearer token; a request with a token must fit both, so rotating addresses does not
+	// escape the limit of a token
+	IPRateLimit    *config.Live[config.RateLimit]
+	TokenRateLimit *config.Live[config.RateLimit]
+	// Cache holds the token buckets and cached responses, shared by the replicas through REDIS_URL
+	Cache         cache.Config
+	ResponseCache ResponseCacheConfig
+	// JWT verifies access tokens at the edge; each route checks the audience of its service
+	JWT config.JWT
+	// InternalToken vouches for the caller identity forwarded to the services; without it they
+	// verify the token themselves
+	InternalToken string
+}
+
+func loadConfig(args []string) (Config, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "9090", 30*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		CSRF:      config.LoadCSRF(src),
+		Tenants:   loadTenantConfig(src),
+		Services:  config.Watch(src, "SERVICE_URLS", loadServiceConfig),
+		Retries:   loadRetryPolicies(src, serviceNames),
+		Balancers: loadBalancerPolicies(src, serviceNames),
+		Discovery: loadDiscoveryConfig(src, serviceNames),
+		Docs:      loadDocsConfig(src, serviceNames),
+		BFF:       loadBFFConfig(src),
+		JWT:       config.LoadJWT(src, "", false),
+		Cache:     cache.LoadConfig(src),
+	}
+	cfg.Timeouts = loadTimeoutPolicies(src, cfg.Server, serviceNames)
+	cfg.ResponseCache = loadResponseCacheConfig(src)
+	cfg.IPRateLimit = config.Watch(src, "RATE_LIMIT_IP", func(src *config.Source) config.RateLimit {
+		return config.LoadRateLimitPrefix(src, "RATE_LIMIT_IP")
+	})
+	cfg.TokenRateLimit = config.Watch(src, "RATE_LIMIT_TOKEN", func(src *config.Source) config.RateLimit {
+		return config.LoadRateLimitPrefix(src, "RATE_LIMIT_TOKEN")
+	})
+	cfg.InternalToken = cfg.Tenants.InternalToken
+	cfg.Transcode = loadTranscodeConfig(src, cfg.InternalToken)
+	cfg.Telemetry = otel.LoadConfig(src, "gateway", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Err()
+}
+
+func loadServiceConfig(src *config.Source) ServiceConfig {
+	return ServiceConfig{
+		UserURLs:      serviceURLs(src, "USER_SERVICE_URL", "http://localhost:9091"),
+		CatalogURLs:   serviceURLs(src, "CATALOG_SERVICE_URL", "http://localhost:9092"),
+		OrderURLs:     serviceURLs(src, "ORDER_SERVICE_URL", "http://localhost:9093"),
+		PaymentURLs:   serviceURLs(src, "PAYMENT_SERVICE_URL", "http://localhost:9095"),
+		ReviewURLs:    serviceURLs(src, "REVIEW_SERVICE_URL", "http://localhost:9096"),
+		SearchURLs:    serviceURLs(src, "SEARCH_SERVICE_URL", "http://localhost:9097"),
+		WebhookURLs:   serviceURLs(src, "WEBHOOK_SERVICE_URL", "http://localhost:9098"),
+		SagaURLs:      serviceURLs(src, "SAGA_SERVICE_URL", "http://localhost:9099"),
+		MediaURLs:     serviceURLs(src, "MEDIA_SERVICE_URL", "http://localhost:9100"),
+		PromotionURLs: serviceURLs(src, "PROMOTION_SERVICE_URL", "http://localhost:9101"),
+		TaxURLs:       serviceURLs(src, "TAX_SERVICE_URL", "http://localhost:9102"),
+	}
+}
+
+// serviceURLs reads a comma separated list of absolute http(s) URLs, one per instance, which
+// may differ only in scheme and host; proxies cannot be built for anything else
+func serviceURLs(src *config.Source, key, def string) []string {
+	vs := src.List(key, []string{def})
+	var path string
+	for i, v := range vs {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.Fail(key, "must be http or https URLs")
+			return []string{def}
+		}
+		if i > 0 && strings.TrimSuffix(u.Path, "/") != path {
+			src.Fail(key, "instances must share one path")
+			return []string{def}
+		}
+		path = strings.TrimSuffix(u.Path, "/")
+	}
+	return vs
+}
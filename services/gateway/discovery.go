@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/scheduler"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DiscoveryStatic = "static"
+	DiscoveryConsul = "consul"
+	DiscoveryDNS    = "dns"
+)
+
+// DiscoveryConfig selects where the instances of the services come from. With static they are
+// the *_SERVICE_URL settings; with consul or dns the services in Names are looked up every
+// Refresh, and their settings are only used until they are first found.
+type DiscoveryConfig struct {
+	Mode    string
+	Refresh time.Duration
+	// Scheme is that of the discovered instances, which registries only list by address
+	Scheme      string
+	ConsulAddr  string
+	ConsulToken string
+	// DNSDomain is where SRV records are looked up, as _<name>._tcp.<DNSDomain>
+	DNSDomain string
+	// Names holds the name each discovered service is registered under, by gateway name; the
+	// services missing keep following their settings
+	Names map[string]string
+}
+
+func loadDiscoveryConfig(src *config.Source, services []string) DiscoveryConfig {
+	cfg := DiscoveryConfig{
+		Mode:        src.OneOf("DISCOVERY", DiscoveryStatic, DiscoveryStatic, DiscoveryConsul, DiscoveryDNS),
+		Refresh:     src.Duration("DISCOVERY_REFRESH_SECONDS", 10, time.Second),
+		Scheme:      src.OneOf("DISCOVERY_SCHEME", "http", "http", "https"),
+		ConsulAddr:  src.String("CONSUL_HTTP_ADDR", "http://localhost:8500"),
+		ConsulToken: src.String("CONSUL_HTTP_TOKEN", ""),
+		DNSDomain:   src.String("DISCOVERY_DNS_DOMAIN", "service.consul"),
+		Names:       make(map[string]string, len(services)),
+	}
+	for _, name := range src.List("DISCOVERY_SERVICES", services) {
+		if !slices.Contains(services, name) {
+			src.Fail("DISCOVERY_SERVICES", "lists unknown service "+name)
+			continue
+		}
+		cfg.Names[name] = src.String(strings.ToUpper(name)+"_SERVICE_DISCOVERY_NAME", name+"-service")
+	}
+	if cfg.Mode != DiscoveryStatic && cfg.Refresh <= 0 {
+		src.Fail("DISCOVERY_REFRESH_SECONDS", "must be positive")
+	}
+	if cfg.Mode == DiscoveryConsul {
+		if u, err := url.Parse(cfg.ConsulAddr); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			src.Fail("CONSUL_HTTP_ADDR", "must be an http or https URL")
+		}
+	}
+	return cfg
+}
+
+// instances returns the field of c holding the instances of the named service
+func (c *ServiceConfig) instances(name string) *[]string {
+	switch name {
+	case "user":
+		return &c.UserURLs
+	case "catalog":
+		return &c.CatalogURLs
+	case "order":
+		return &c.OrderURLs
+	case "payment":
+		return &c.PaymentURLs
+	case "review":
+		return &c.ReviewURLs
+	case "search":
+		return &c.SearchURLs
+	case "webhook":
+		return &c.WebhookURLs
+	case "saga":
+		return &c.SagaURLs
+	case "media":
+		return &c.MediaURLs
+	case "promotion":
+		return &c.PromotionURLs
+	case "tax":
+		return &c.TaxURLs
+	}
+	panic("unknown service " + name)
+}
+
+// routeTable is the route table the proxies follow, fed by config reloads or by discovery
+type routeTable struct {
+	mu          sync.Mutex
+	current     atomic.Pointer[ServiceConfig]
+	subscribers []func(ServiceConfig)
+}
+
+// staticRoutes follows the *_SERVICE_URL settings
+func staticRoutes(services *config.Live[ServiceConfig]) *routeTable {
+	t := &routeTable{}
+	t.set(services.Get())
+	services.Subscribe(t.set)
+	return t
+}
+
+func (t *routeTable) Get() ServiceConfig {
+	return *t.current.Load()
+}
+
+// Subscribe calls fn with the route table after each change
+func (t *routeTable) Subscribe(fn func(ServiceConfig)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subscribers = append(t.subscribers, fn)
+}
+
+// set stores cfg and hands it to the subscribers when it changed the table
+func (t *routeTable) set(cfg ServiceConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if prev := t.current.Load(); prev != nil && reflect.DeepEqual(*prev, cfg) {
+		return
+	}
+	t.current.Store(&cfg)
+	for _, fn := range t.subscribers {
+		fn(cfg)
+	}
+}
+
+// resolver looks up the instances of a service in a registry as base URLs
+type resolver interface {
+	resolve(ctx context.Context, name string) ([]string, error)
+}
+
+// discovery keeps the route table in line with the registry
+type discovery struct {
+	routes   *routeTable
+	static   *config.Live[ServiceConfig]
+	resolver resolver
+	names    map[string]string
+}
+
+// newRoutes returns the route table of cfg. With discovery the services are looked up once
+// before the gateway starts and then by the returned job; static routes need no job.
+func newRoutes(cfg Config, log *zap.Logger) (*routeTable, []scheduler.Job) {
+	if cfg.Discovery.Mode == DiscoveryStatic {
+		return staticRoutes(cfg.Services), nil
+	}
+	d := &discovery{routes: &routeTable{}, static: cfg.Services, names: cfg.Discovery.Names}
+	switch cfg.Discovery.Mode {
+	case DiscoveryConsul:
+		d.resolver = &consulResolver{addr: strings.TrimSuffix(cfg.Discovery.ConsulAddr, "/"), token: cfg.Discovery.ConsulToken,
+			scheme: cfg.Discovery.Scheme, http: &http.Client{Timeout: 5 * time.Second, Transport: otel.Transport(nil)}}
+	case DiscoveryDNS:
+		d.resolver = &dnsResolver{domain: cfg.Discovery.DNSDomain, scheme: cfg.Discovery.Scheme, resolver: net.DefaultResolver}
+	}
+	d.routes.set(cfg.Services.Get())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := d.refresh(ctx); err != nil {
+		log.Error("Service discovery failed, using the configured URLs", zap.Error(err))
+	}
+	return d.routes, []scheduler.Job{{Name: "discover-services", Interval: cfg.Discovery.Refresh, Run: d.refresh}}
+}
+
+// refresh looks every discovered service up and returns how many changed. A service that
+// cannot be looked up, or has no instance, keeps the instances it had.
+func (d *discovery) refresh(ctx context.Context) (int, error) {
+	current := d.routes.Get()
+	next := d.static.Get()
+	changed := 0
+	var errs []error
+	for _, name := range serviceNames {
+		if _, ok := d.names[name]; !ok {
+			continue
+		}
+		found, err := d.resolver.resolve(ctx, d.names[name])
+		if err == nil && len(found) == 0 {
+			err = errors.New("no healthy instance registered")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.names[name], err))
+			*next.instances(name) = *current.instances(name)
+			continue
+		}
+		slices.Sort(found)
+		if !slices.Equal(found, *current.instances(name)) {
+			changed++
+		}
+		*next.instances(name) = found
+	}
+	d.routes.set(next)
+	return changed, errors.Join(errs...)
+}
+
+// consulResolver reads the instances passing their health checks from the Consul agent
+type consulResolver struct {
+	addr   string
+	token  string
+	scheme string
+	http   *http.Client
+}
+
+// consulEntry is an entry of Consul's GET /v1/health/service/<name>
+type consulEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+func (r *consulResolver) resolve(ctx context.Context, name string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.addr+"/v1/health/service/"+url.PathEscape(name)+"?passing=true", nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.token != "" {
+		req.Header.Set("X-Consul-Token", r.token)
+	}
+	res, err := r.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned %d", res.StatusCode)
+	}
+	var entries []consulEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("reading consul answer: %w", err)
+	}
+	urls := make([]string, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		urls = append(urls, r.scheme+"://"+net.JoinHostPort(host, strconv.Itoa(e.Service.Port)))
+	}
+	return urls, nil
+}
+
+// dnsResolver reads the SRV records of a service and uses the targets of the best priority
+type dnsResolver struct {
+	domain   string
+	scheme   string
+	resolver *net.Resolver
+}
+
+func (r *dnsResolver) resolve(ctx context.Context, name string) ([]string, error) {
+	_, records, err := r.resolver.LookupSRV(ctx, name, "tcp", r.domain)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, srv := range records {
+		// records come sorted by priority; the others are backups
+		if srv.Priority != records[0].Priority {
+			break
+		}
+		urls = append(urls, r.scheme+"://"+net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+	}
+	return urls, nil
+}
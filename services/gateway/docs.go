@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/otel"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
+)
+
+// DocsConfig selects the services whose APIs the combined docs at /docs describe
+type DocsConfig struct {
+	Services []string
+	// CacheTTL is how long the combined spec is served before the services are asked again
+	CacheTTL time.Duration
+}
+
+func loadDocsConfig(src *config.Source, services []string) DocsConfig {
+	cfg := DocsConfig{
+		Services: src.List("DOCS_SERVICES", []string{"user", "catalog", "order"}),
+		CacheTTL: src.Duration("DOCS_CACHE_SECONDS", 60, time.Second),
+	}
+	for _, name := range cfg.Services {
+		if !slices.Contains(services, name) {
+			src.Fail("DOCS_SERVICES", "lists unknown service "+name)
+		}
+	}
+	return cfg
+}
+
+const refPrefix = "#/definitions/"
+
+// docsAggregator serves one swagger spec merging those of the services, read from their
+// /docs/doc.json. A service that does not answer is left out until it does; the last spec
+// is kept while none answers.
+type docsAggregator struct {
+	services *routeTable
+	cfg      DocsConfig
+	http     *http.Client
+	log      *zap.Logger
+
+	mu        sync.Mutex
+	spec      []byte
+	fetchedAt time.Time
+}
+
+func newDocsAggregator(services *routeTable, cfg DocsConfig, log *zap.Logger) *docsAggregator {
+	return &docsAggregator{services: services, cfg: cfg, log: log,
+		http: &http.Client{Timeout: 5 * time.Second, Transport: otel.Transport(nil)}}
+}
+
+// RegisterRoutes serves the swagger UI at /docs/index.html with the combined spec
+func (a *docsAggregator) RegisterRoutes(router gin.IRoutes) {
+	ui := ginSwagger.WrapHandler(swaggerFiles.Handler)
+	router.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/docs/index.html")
+	})
+	router.GET("/docs/*any", func(c *gin.Context) {
+		if c.Param("any") != "/doc.json" {
+			ui(c)
+			return
+		}
+		spec, err := a.current(c.Request.Context())
+		if err != nil {
+			a.log.Error("Failed to combine the API docs", zap.Error(err))
+			c.String(http.StatusBadGateway, "API docs are not available")
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+}
+
+// current returns the combined spec, merging it again once it is older than CacheTTL
+func (a *docsAggregator) current(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.spec != nil && time.Since(a.fetchedAt) < a.cfg.CacheTTL {
+		return a.spec, nil
+	}
+	var names []string
+	var specs []map[string]any
+	for _, name := range a.cfg.Services {
+		spec, err := a.fetch(ctx, name)
+		if err != nil {
+			a.log.Warn("Leaving a service out of the API docs", zap.String("service", name), zap.Error(err))
+			continue
+		}
+		names = append(names, name)
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		if a.spec != nil {
+			return a.spec, nil
+		}
+		return nil, errors.New("no service answered with its docs")
+	}
+	spec, err := json.Marshal(mergeSpecs(names, specs))
+	if err != nil {
+		return nil, err
+	}
+	a.spec, a.fetchedAt = spec, time.Now()
+	return spec, nil
+}
+
+// fetch reads the spec of a service from its instances in turn
+func (a *docsAggregator) fetch(ctx context.Context, name string) (map[string]any, error) {
+	path := strings.TrimSuffix(index.Docs[name], "index.html") + "doc.json"
+	cfg := a.services.Get()
+	var errs []error
+	for _, instance := range *cfg.instances(name) {
+		spec, err := a.fetchFrom(ctx, strings.TrimSuffix(instance, "/")+path)
+		if err == nil {
+			return spec, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (a *docsAggregator) fetchFrom(ctx context.Context, url string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", url, res.StatusCode)
+	}
+	var spec map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	return spec, nil
+}
+
+// mergeSpecs combines the swagger 2.0 specs of the named services under /v1. Paths and tags
+// are joined; a definition that another service already declared differently is renamed
+// <service>.<name> together with its references. The host is left out so the UI calls the
+// gateway it was loaded from.
+func mergeSpecs(names []string, specs []map[string]any) map[string]any {
+	paths, definitions, security := map[string]any{}, map[string]any{}, map[string]any{}
+	var tags []any
+	seenTags := map[string]bool{}
+	for i, spec := range specs {
+		own := asMap(spec["definitions"])
+		rename := definitionRenames(names[i], own, definitions)
+		for name, def := range own {
+			if renamed, ok := rename[name]; ok {
+				name = renamed
+			}
+			definitions[name] = rewriteRefs(def, rename)
+		}
+		for path, item := range asMap(spec["paths"]) {
+			if _, ok := paths[path]; !ok {
+				paths[path] = rewriteRefs(item, rename)
+			}
+		}
+		for name, def := range asMap(spec["securityDefinitions"]) {
+			security[name] = def
+		}
+		for _, tag := range asSlice(spec["tags"]) {
+			if name, _ := asMap(tag)["name"].(string); !seenTags[name] {
+				seenTags[name] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	merged := map[string]any{
+		"swagger": "2.0",
+		"info": map[string]any{
+			"title":       "Ecommerce API",
+			"description": "The APIs of the " + strings.Join(names, ", ") + " services, as routed by the gateway",
+			"version":     "1.0.0",
+		},
+		"basePath":    "/v1",
+		"paths":       paths,
+		"definitions": definitions,
+	}
+	if len(security) > 0 {
+		merged["securityDefinitions"] = security
+	}
+	if len(tags) > 0 {
+		merged["tags"] = tags
+	}
+	return merged
+}
+
+// definitionRenames picks the definitions of service to rename: those declared differently
+// before, and those referring to a renamed one, which would otherwise share a name with a
+// definition that means something else
+func definitionRenames(service string, own, merged map[string]any) map[string]string {
+	rename := map[string]string{}
+	for changed := true; changed; {
+		changed = false
+		for name, def := range own {
+			if _, ok := rename[name]; ok {
+				continue
+			}
+			if existing, ok := merged[name]; ok && (!reflect.DeepEqual(existing, def) || refersTo(def, rename)) {
+				rename[name] = service + "." + name
+				changed = true
+			}
+		}
+	}
+	return rename
+}
+
+func refersTo(v any, rename map[string]string) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if _, renamed := rename[strings.TrimPrefix(ref, refPrefix)]; renamed {
+				return true
+			}
+		}
+		for _, item := range v {
+			if refersTo(item, rename) {
+				return true
+			}
+		}
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool { return refersTo(item, rename) })
+	}
+	return false
+}
+
+// rewriteRefs returns v with the references to renamed definitions pointing to their new names
+func rewriteRefs(v any, rename map[string]string) any {
+	if len(rename) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				if renamed, ok := rename[strings.TrimPrefix(ref, refPrefix)]; ok {
+					item = refPrefix + renamed
+				}
+			}
+			out[key] = rewriteRefs(item, rename)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = rewriteRefs(item, rename)
+		}
+		return out
+	}
+	return v
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
@@ -1,6 +1,6 @@
 // @title           Ecommerce API Gateway
 // @version         1.0.0
-// @description     API Gateway that routes requests to microservices (User, Catalog, Order)
+// @description     API Gateway that routes requests to microservices (User, Catalog, Order, Payment, Review, Search, Webhook, Saga)
 
 // @host            localhost:9090
 // @BasePath        /v1
@@ -13,159 +13,378 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/gin-contrib/cors"
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/scheduler"
+
 	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
-type ServiceConfig struct {
-	UserURL    string
-	CatalogURL string
-	OrderURL   string
-}
+const service app.Service = "gateway"
 
 func main() {
-	log := initLogger()
-	defer func() { _ = log.Sync() }()
+	appCfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	appLog, err := logger.New(appCfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = appLog.Close() }()
+	defer appLog.ReloadOnSIGHUP()()
 
-	log.Info("Starting API Gateway")
+	appLog.Info("Starting API Gateway")
 
-	cfg := ServiceConfig{
-		UserURL:    getEnvOrDefault("USER_SERVICE_URL", "http://localhost:9091"),
-		CatalogURL: getEnvOrDefault("CATALOG_SERVICE_URL", "http://localhost:9092"),
-		OrderURL:   getEnvOrDefault("ORDER_SERVICE_URL", "http://localhost:9093"),
-	}
+	app.Run(app.New(service, appLog, appCfg.Server,
+		fx.Supply(appCfg, appCfg.Telemetry, appCfg.Cache),
+		fx.Provide(app.Telemetry, app.Cache, newTranscoder, newRouter, func() (*routeTable, []scheduler.Job) {
+			return newRoutes(appCfg, appLog.Log)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine, discovery []scheduler.Job) {
+			if len(discovery) > 0 {
+				jobs := scheduler.New(appLog)
+				for _, job := range discovery {
+					jobs.Add(job)
+				}
+				app.Go(lc, jobs.Run)
+			}
+			app.ServeHTTP(lc, s, appLog, "API Gateway", appCfg.Server, router)
+		}),
+	), appLog)
+}
+
+// Index is what the gateway answers on /: where to find each service's health and docs
+type Index struct {
+	Message  string            `json:"message"`
+	Status   string            `json:"status"`
+	Services map[string]string `json:"services"`
+	Docs     map[string]string `json:"docs"`
+}
 
-	env := getEnvOrDefault("GO_ENV", "development")
-	if env == "development" {
+var index = Index{
+	Message: "Welcome to Ecommerce Microservices API Gateway",
+	Status:  "running",
+	Services: map[string]string{
+		"user":      "/v1/health",
+		"catalog":   "/v1/health",
+		"order":     "/v1/health",
+		"payment":   "/v1/health",
+		"review":    "/v1/health",
+		"search":    "/v1/health",
+		"webhook":   "/v1/health",
+		"saga":      "/v1/health",
+		"media":     "/v1/health",
+		"promotion": "/v1/health",
+		"tax":       "/v1/health",
+	},
+	Docs: map[string]string{
+		"all":       "/docs/index.html",
+		"user":      "/v1/user/docs/index.html",
+		"catalog":   "/v1/catalog/docs/index.html",
+		"order":     "/v1/order/docs/index.html",
+		"payment":   "/v1/payment/docs/index.html",
+		"review":    "/v1/review/docs/index.html",
+		"search":    "/v1/search/docs/index.html",
+		"webhook":   "/v1/webhooks/docs/index.html",
+		"saga":      "/v1/sagas/docs/index.html",
+		"media":     "/v1/media/docs/index.html",
+		"promotion": "/v1/promotions/docs/index.html",
+		"tax":       "/v1/tax/docs/index.html",
+	},
+}
+
+func newRouter(appCfg Config, appLog *logger.Logger, telemetry *otel.Telemetry, cacheStore cache.Store, services *routeTable, grpcRoutes *transcoder) *gin.Engine {
+	log := appLog.Log
+
+	if appCfg.Server.IsDevelopment() {
 		gin.SetMode(gin.DebugMode)
 	} else {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.New()
-	router.Use(gin.Recovery())
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
+	router := app.Router(appCfg.Server)
+	router.Use(controllers.UseEnvelope(), middleware.RequestID(), middleware.Recovery("gateway", appLog), telemetry.Middleware(), metrics.Middleware("gateway"))
+	router.Use(middleware.CORS(appCfg.CORS), middleware.SecurityHeaders(appCfg.Security), middleware.CSRF(appCfg.CSRF))
 	router.Use(zapLoggerMiddleware(log))
 
 	// Root Handler
 	router.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Welcome to Ecommerce Microservices API Gateway",
-			"status":  "running",
-			"services": gin.H{
-				"user":    "/v1/health",
-				"catalog": "/v1/health",
-				"order":   "/v1/health",
-			},
-			"docs": gin.H{
-				"user":    "/v1/user/docs/index.html",
-				"catalog": "/v1/catalog/docs/index.html",
-				"order":   "/v1/order/docs/index.html",
-			},
-		})
+		controllers.Respond(c, http.StatusOK, index)
 	})
 
+	router.GET("/metrics", metrics.Handler())
+
+	// one swagger UI for the APIs of the services behind the gateway
+	newDocsAggregator(services, appCfg.Docs, log).RegisterRoutes(router)
+
 	v1 := router.Group("/v1")
 
 	// Health check
-	v1.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"service": "gateway",
-		})
-	})
+	health.NewRegistry("gateway").RegisterRoutes(v1)
+
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", appLog.LevelHandler())
+		internal.PUT("/log-level", appLog.LevelHandler())
+		internal.POST("/config/reload", appLog.ReloadHandler())
+	}
+
+	// every proxied request is rate limited, per client address and per bearer token, before
+	// anything else and carries the tenant it was resolved to
+	proxied := v1.Group("",
+		middleware.RateLimit(cacheStore, "gateway:ip", appCfg.IPRateLimit, middleware.KeyByIP, appLog),
+		tokenRateLimit(middleware.RateLimit(cacheStore, "gateway:token", appCfg.TokenRateLimit, middleware.KeyByToken, appLog)),
+		resolveTenant(appCfg.Tenants, newTenantRegistry(services, appCfg.Tenants, log)))
+	// GRPC_ROUTES are checked against the routes below, as listed in gatewayRoutes
+	// callers are authenticated here, for the audience of the service a route leads to
+	identify := func(audience string) gin.HandlerFunc {
+		return middleware.ForwardIdentity(appCfg.JWT, audience, appCfg.InternalToken)
+	}
+
+	policy := func(name string) proxyPolicy {
+		return proxyPolicy{Retry: appCfg.Retries[name], Balancer: appCfg.Balancers[name], Timeout: appCfg.Timeouts[name]}
+	}
 
 	// User Service routes
-	userProxy := createReverseProxy(cfg.UserURL, log)
-	v1.Any("/auth/*path", proxyHandler(userProxy))
-	v1.Any("/user/*path", proxyHandler(userProxy))
+	userProxy := newUpstream(services, func(s ServiceConfig) []string { return s.UserURLs }, policy("user"), createReverseProxy, log)
+	// signing in and refreshing work whatever stale token the client still sends
+	proxied.Any("/auth/*path", identify(""), proxyHandler(userProxy))
+	proxied.Any("/user/*path", identify("user"), proxyHandler(userProxy))
 
 	// Catalog Service routes
-	catalogProxy := createReverseProxy(cfg.CatalogURL, log)
-	v1.Any("/category/*path", proxyHandler(catalogProxy))
-	v1.Any("/product/*path", proxyHandler(catalogProxy))
-	v1.Any("/catalog/*path", proxyHandler(catalogProxy))
+	catalogProxy := newUpstream(services, func(s ServiceConfig) []string { return s.CatalogURLs }, policy("catalog"), createReverseProxy, log)
+	// browsing categories and products is served from the cache while fresh
+	responses := newResponseCache(cacheStore, appCfg.ResponseCache, appLog)
+	proxied.Any("/category/*path", identify("catalog"), responses.Handler(), proxyHandler(catalogProxy))
+	proxied.Any("/product/*path", identify("catalog"), responses.Handler(), proxyHandler(catalogProxy))
+	proxied.Any("/catalog/*path", identify("catalog"), proxyHandler(catalogProxy))
 
 	// Order Service routes
-	orderProxy := createReverseProxy(cfg.OrderURL, log)
-	v1.Any("/order/*path", proxyHandler(orderProxy))
+	orderProxy := newUpstream(services, func(s ServiceConfig) []string { return s.OrderURLs }, policy("order"), createReverseProxy, log)
+	proxied.Any("/order/*path", identify("order"), proxyHandler(orderProxy))
 
-	port := getEnvOrDefault("SERVER_PORT", "9090")
-	log.Info("API Gateway starting", zap.String("port", port), zap.String("userService", cfg.UserURL), zap.String("catalogService", cfg.CatalogURL), zap.String("orderService", cfg.OrderURL))
+	// Payment Service routes, including the provider webhooks
+	paymentProxy := newUpstream(services, func(s ServiceConfig) []string { return s.PaymentURLs }, policy("payment"), createReverseProxy, log)
+	proxied.Any("/payment/*path", identify("payment"), proxyHandler(paymentProxy))
 
-	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-	}
-	if err := server.ListenAndServe(); err != nil {
-		log.Fatal("Gateway failed to start", zap.Error(err))
+	// Review Service routes
+	reviewProxy := newUpstream(services, func(s ServiceConfig) []string { return s.ReviewURLs }, policy("review"), createReverseProxy, log)
+	proxied.Any("/review/*path", identify("review"), proxyHandler(reviewProxy))
+
+	// Search Service routes
+	searchProxy := newUpstream(services, func(s ServiceConfig) []string { return s.SearchURLs }, policy("search"), createReverseProxy, log)
+	// search is public and takes no tokens
+	proxied.Any("/search", identify(""), proxyHandler(searchProxy))
+	proxied.Any("/search/*path", identify(""), proxyHandler(searchProxy))
+
+	// Webhook Service routes
+	webhookProxy := newUpstream(services, func(s ServiceConfig) []string { return s.WebhookURLs }, policy("webhook"), createReverseProxy, log)
+	proxied.Any("/webhooks", identify("webhook"), proxyHandler(webhookProxy))
+	proxied.Any("/webhooks/*path", identify("webhook"), proxyHandler(webhookProxy))
+
+	// Saga Service routes
+	sagaProxy := newUpstream(services, func(s ServiceConfig) []string { return s.SagaURLs }, policy("saga"), createReverseProxy, log)
+	proxied.Any("/sagas", identify("saga"), proxyHandler(sagaProxy))
+	proxied.Any("/sagas/*path", identify("saga"), proxyHandler(sagaProxy))
+
+	// Media Service routes
+	mediaProxy := newUpstream(services, func(s ServiceConfig) []string { return s.MediaURLs }, policy("media"), createMediaProxy, log)
+	proxied.Any("/media/*path", identify("media"), mediaProxyHandler(mediaProxy))
+
+	// Promotion Service routes
+	promotionProxy := newUpstream(services, func(s ServiceConfig) []string { return s.PromotionURLs }, policy("promotion"), createReverseProxy, log)
+	proxied.Any("/promotions", identify("promotion"), proxyHandler(promotionProxy))
+	proxied.Any("/promotions/*path", identify("promotion"), proxyHandler(promotionProxy))
+
+	// Tax Service routes
+	taxProxy := newUpstream(services, func(s ServiceConfig) []string { return s.TaxURLs }, policy("tax"), createReverseProxy, log)
+	proxied.Any("/tax/*path", identify("tax"), proxyHandler(taxProxy))
+
+	// pages composed from several services, so frontends need one call
+	newBFF(services, appCfg.BFF, appCfg.Timeouts, log).RegisterRoutes(proxied, identify)
+
+	// REST routes answered by gRPC methods of the services, outside the paths proxied above
+	grpcRoutes.RegisterRoutes(proxied, appCfg.JWT, identify)
+
+	cfg := services.Get()
+	log.Info("Routing to services", zap.Strings("userService", cfg.UserURLs), zap.Strings("catalogService", cfg.CatalogURLs), zap.Strings("orderService", cfg.OrderURLs), zap.Strings("paymentService", cfg.PaymentURLs), zap.Strings("reviewService", cfg.ReviewURLs), zap.Strings("searchService", cfg.SearchURLs), zap.Strings("webhookService", cfg.WebhookURLs), zap.Strings("sagaService", cfg.SagaURLs), zap.Strings("mediaService", cfg.MediaURLs), zap.Strings("promotionService", cfg.PromotionURLs), zap.Strings("taxService", cfg.TaxURLs))
+	return router
+}
+
+// tokenRateLimit applies limit to requests with a bearer token and lets the others through
+func tokenRateLimit(limit gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		limit(c)
 	}
 }
 
-func createReverseProxy(target string, log *zap.Logger) *httputil.ReverseProxy {
-	targetURL, err := url.Parse(target)
-	if err != nil {
-		log.Fatal("Invalid service URL", zap.String("target", target), zap.Error(err))
+// proxyPolicy is how the gateway proxies to the instances of one service
+type proxyPolicy struct {
+	Retry    RetryPolicy
+	Balancer BalancerPolicy
+	Timeout  TimeoutPolicy
+}
+
+// upstream proxies to one service of the route table, building a new proxy when a reload
+// changes its instances
+type upstream struct {
+	proxy    atomic.Pointer[httputil.ReverseProxy]
+	timeouts TimeoutPolicy
+	log      *zap.Logger
+}
+
+func newUpstream(services *routeTable, targets func(ServiceConfig) []string, policy proxyPolicy,
+	build func(targets []string, policy proxyPolicy, log *zap.Logger) *httputil.ReverseProxy, log *zap.Logger) *upstream {
+	u := &upstream{timeouts: policy.Timeout, log: log}
+	current := targets(services.Get())
+	u.proxy.Store(build(current, policy, log))
+	services.Subscribe(func(cfg ServiceConfig) {
+		if next := targets(cfg); !slices.Equal(next, current) {
+			log.Warn("Service route changed", zap.Strings("from", current), zap.Strings("to", next))
+			current = next
+			u.proxy.Store(build(next, policy, log))
+		}
+	})
+	return u
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.proxy.Load().ServeHTTP(w, r)
+}
+
+// createReverseProxy proxies to the instances at targets, spreading requests over them and
+// trying GET and HEAD requests again, on another instance, as the policy allows when one
+// cannot be reached
+func createReverseProxy(targets []string, policy proxyPolicy, log *zap.Logger) *httputil.ReverseProxy {
+	urls := make([]*url.URL, len(targets))
+	for i, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			log.Fatal("Invalid service URL", zap.String("target", target), zap.Error(err))
+		}
+		urls[i] = u
 	}
+	target := strings.Join(targets, ",")
 
-	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	// instances share their path, so the first one rewrites requests and the balancer sets the
+	// host of the instance it picks
+	proxy := httputil.NewSingleHostReverseProxy(urls[0])
+	proxy.Transport = &retryTransport{
+		next:   newBalancer(urls, policy.Balancer, otel.Transport(nil), log),
+		policy: policy.Retry,
+		target: target,
+		log:    log,
+	}
+	// the gateway already set X-Request-ID and the security headers on the response; the
+	// service echoes the same ID
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		resp.Header.Del(middleware.RequestIDHeader)
+		middleware.DelSecurityHeaders(resp.Header)
+		return nil
+	}
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Error("Proxy error", zap.String("target", target), zap.String("path", r.URL.Path), zap.Error(err))
-		w.WriteHeader(http.StatusBadGateway)
-		_, _ = w.Write([]byte(`{"error": "service unavailable"}`))
+		status, res := http.StatusBadGateway, domainErrors.ErrorResponse{
+			Code:      domainErrors.CodeServiceUnavailable,
+			Message:   "service unavailable",
+			RequestID: r.Header.Get(middleware.RequestIDHeader),
+		}
+		if isServiceTimeout(r) {
+			status, res.Code, res.Message = http.StatusGatewayTimeout, domainErrors.CodeServiceTimeout, "service timed out"
+			log.Warn("Service timed out", zap.String("target", target), zap.String("method", r.Method), zap.String("path", r.URL.Path),
+				zap.String("request_id", res.RequestID))
+		} else {
+			log.Error("Proxy error", zap.String("target", target), zap.String("path", r.URL.Path),
+				zap.String("request_id", res.RequestID), zap.Error(err))
+		}
+		body, _ := json.Marshal(controllers.NewErrorEnvelope(res))
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
 	}
 	return proxy
 }
 
-func proxyHandler(proxy *httputil.ReverseProxy) gin.HandlerFunc {
+// proxyHandler proxies to the service of proxy, answering 504 when it does not respond within
+// the timeout of the route. WebSockets and event streams pass through and stay open.
+func proxyHandler(proxy *upstream) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Rebuild the URL path: strip the /v1 prefix group and re-add the full path
 		// Gin's *path captures everything after the route group
 		// The reverse proxy target already has /v1 in its path
 		c.Request.URL.Path = "/v1" + c.Request.URL.Path[len("/v1"):]
+		if isStream(c.Request) {
+			if err := allowStream(c); err != nil {
+				proxy.log.Error("Stream keeps the server write timeout", zap.String("path", c.Request.URL.Path),
+					zap.String("request_id", middleware.GetRequestID(c)), zap.Error(err))
+			}
+		}
+		defer withTimeout(c, proxy.timeouts.timeout(c.Request))()
 		proxy.ServeHTTP(c.Writer, c.Request)
 	}
 }
 
-func initLogger() *zap.Logger {
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "timestamp",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		MessageKey:     "msg",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
+// createMediaProxy is createReverseProxy keeping the caching headers of downloaded files,
+// which the media service sets per file, instead of the gateway's no-store
+func createMediaProxy(targets []string, policy proxyPolicy, log *zap.Logger) *httputil.ReverseProxy {
+	proxy := createReverseProxy(targets, policy, log)
+	modify := proxy.ModifyResponse
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		if !isMediaContent(resp.Request.URL.Path) {
+			return modify(resp)
+		}
+		cacheControl, csp := resp.Header.Get("Cache-Control"), resp.Header.Get("Content-Security-Policy")
+		if err := modify(resp); err != nil {
+			return err
+		}
+		resp.Header.Set("Cache-Control", cacheControl)
+		resp.Header.Set("Content-Security-Policy", csp)
+		return nil
 	}
+	return proxy
+}
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zap.NewAtomicLevelAt(zap.InfoLevel),
-	)
+// mediaProxyHandler drops the headers the media service replaces on downloads before proxying
+func mediaProxyHandler(proxy *upstream) gin.HandlerFunc {
+	next := proxyHandler(proxy)
+	return func(c *gin.Context) {
+		if isMediaContent(c.Request.URL.Path) {
+			h := c.Writer.Header()
+			for _, name := range []string{"Cache-Control", "Pragma", "Expires", "Content-Security-Policy"} {
+				h.Del(name)
+			}
+		}
+		next(c)
+	}
+}
 
-	return zap.New(core)
+func isMediaContent(path string) bool {
+	return strings.HasPrefix(path, "/v1/media/files/") && strings.HasSuffix(path, "/content")
 }
 
 func zapLoggerMiddleware(log *zap.Logger) gin.HandlerFunc {
@@ -178,13 +397,8 @@ func zapLoggerMiddleware(log *zap.Logger) gin.HandlerFunc {
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", time.Since(start)),
 			zap.String("client_ip", c.ClientIP()),
+			zap.String("request_id", middleware.GetRequestID(c)),
+			zap.String("tenant", middleware.GetTenant(c)),
 		)
 	}
 }
-
-func getEnvOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return def
-}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/pact"
+
+	"go.uber.org/zap"
+)
+
+// The pacts list the user and catalog routes the gateway forwards and the response fields its
+// clients read, plus the active tenants the gateway reads itself. TestPacts sends every
+// interaction through the gateway to a pact server standing in for the service; the services
+// verify them in their own TestPacts.
+
+var userPact = pact.Pact{
+	Consumer: "gateway",
+	Provider: "user",
+	Interactions: []pact.Interaction{
+		{
+			Description: "sign in",
+			State:       "user 7 signs in with secret123",
+			Request: pact.Request{Method: http.MethodPost, Path: "/v1/auth/login",
+				Body: map[string]any{"email": "buyer@example.com", "password": "secret123"}},
+			Response: pact.Response{Status: http.StatusOK, Body: map[string]any{
+				"data": map[string]any{
+					"user":     map[string]any{"id": 7, "email": "buyer@example.com", "role": "customer"},
+					"security": map[string]any{"jwtAccessToken": "access-token", "jwtRefreshToken": "refresh-token"},
+				},
+			}},
+		},
+		{
+			Description: "sign in with a wrong password",
+			State:       "user 7 signs in with secret123",
+			Request: pact.Request{Method: http.MethodPost, Path: "/v1/auth/login",
+				Body: map[string]any{"email": "buyer@example.com", "password": "wrong"}},
+			Response: pact.Response{Status: http.StatusUnauthorized, Body: map[string]any{
+				"errors": []any{map[string]any{"code": "NOT_AUTHENTICATED", "message": "not Authenticated"}},
+			}},
+		},
+		{
+			Description: "list the active tenants",
+			State:       "tenant acme is active on acme.example.com",
+			Request: pact.Request{Method: http.MethodGet, Path: "/v1/internal/tenants",
+				Headers: map[string]string{"X-Internal-Token": pact.InternalToken}},
+			Response: pact.Response{Status: http.StatusOK, Body: map[string]any{
+				"data": []any{map[string]any{"id": "acme", "host": "acme.example.com"}},
+			}},
+		},
+	},
+}
+
+// checkTenants reads the active tenants the way resolveTenant does
+func checkTenants(log *zap.Logger) pact.Check {
+	return func(ctx context.Context, baseURL string) error {
+		registry := newTenantRegistry(staticRoutes(config.Fixed(ServiceConfig{UserURLs: []string{baseURL}})), TenantConfig{InternalToken: pact.InternalToken}, log)
+		_, hosts, err := registry.current(ctx)
+		if err != nil {
+			return err
+		}
+		if hosts["acme.example.com"] != "acme" {
+			return fmt.Errorf("acme.example.com resolves to %q, want acme", hosts["acme.example.com"])
+		}
+		return nil
+	}
+}
+
+var catalogProduct = map[string]any{"id": 7, "name": "Basic Tee", "price": 25.5, "currency": "IDR", "stock": 10, "isActive": true}
+
+var catalogPact = pact.Pact{
+	Consumer: "gateway",
+	Provider: "catalog",
+	Interactions: []pact.Interaction{
+		{
+			Description: "list products",
+			State:       "product 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/", Query: "limit=20&page=1"},
+			Response: pact.Response{Status: http.StatusOK, Body: map[string]any{
+				"data": []any{catalogProduct},
+				"meta": map[string]any{"version": 1, "page": 1, "limit": 20, "total": 1, "totalPages": 1},
+			}},
+		},
+		{
+			Description: "get a product",
+			State:       "product 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/7"},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": catalogProduct}},
+		},
+	},
+}
+
+var pacts = []pact.Pact{userPact, catalogPact}
+
+// updatePacts writes the pacts instead of comparing them with the committed files (make pacts)
+var updatePacts = flag.Bool("update", false, "write the pacts to pkg/pact/pacts")
+
+// TestPacts checks the gateway against every interaction, then checks the committed pacts are
+// the same
+func TestPacts(t *testing.T) {
+	if err := checkPacts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	dir := ""
+	if *updatePacts {
+		dir = "../../pkg/pact/pacts"
+	}
+	if err := pact.Publish(pacts, dir); err != nil {
+		t.Error(err)
+	}
+}
+
+// checkPacts replays every interaction through the gateway, with the service behind it replaced
+// by the pact server
+func checkPacts(ctx context.Context) error {
+	log, err := logger.New(logger.Config{Level: zap.WarnLevel})
+	if err != nil {
+		return err
+	}
+	telemetry, err := otel.Setup(ctx, otel.Config{ServiceName: string(service)}, log)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, p := range pacts {
+		checks := map[string]pact.Check{}
+		for _, i := range p.Interactions {
+			if i.Request.Path == "/v1/internal/tenants" {
+				checks[i.Description] = checkTenants(log.Log)
+				continue
+			}
+			checks[i.Description] = func(ctx context.Context, baseURL string) error {
+				src := config.NewSource()
+				cfg := Config{CORS: config.Watch(src, "CORS", config.LoadCORS), Security: config.LoadSecurityHeaders(src)}
+				// the services outside the pact keep their defaults and are never called
+				services := loadServiceConfig(src)
+				switch p.Provider {
+				case "user":
+					services.UserURLs = []string{baseURL}
+				case "catalog":
+					services.CatalogURLs = []string{baseURL}
+				}
+				return pact.Replay(newRouter(cfg, log, telemetry, cache.NewMemoryStore(), staticRoutes(config.Fixed(services)), &transcoder{log: log.Log}), i)
+			}
+		}
+		errs = append(errs, pact.Consume(ctx, p, checks))
+	}
+	return errors.Join(errs...)
+}
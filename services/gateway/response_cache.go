@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CacheStatusHeader tells whether a response came from the gateway's cache (HIT), was stored
+// in it (MISS) or went around it (BYPASS)
+const CacheStatusHeader = "X-Cache"
+
+// ResponseCacheConfig is how long the gateway keeps the answers of public catalog routes
+type ResponseCacheConfig struct {
+	// TTL of a cached response; 0 disables the cache
+	TTL time.Duration
+	// MaxBodyBytes is the largest body cached; bigger responses are passed on only
+	MaxBodyBytes int
+}
+
+func loadResponseCacheConfig(src *config.Source) ResponseCacheConfig {
+	cfg := ResponseCacheConfig{
+		TTL:          src.Duration("RESPONSE_CACHE_TTL_SECONDS", 30, time.Second),
+		MaxBodyBytes: src.Int("RESPONSE_CACHE_MAX_BODY_KB", 512) * 1024,
+	}
+	if cfg.TTL < 0 {
+		src.Fail("RESPONSE_CACHE_TTL_SECONDS", "must not be negative")
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		src.Fail("RESPONSE_CACHE_MAX_BODY_KB", "must be positive")
+	}
+	return cfg
+}
+
+// cachedHeaders are the headers of a service's response replayed on a hit; the gateway sets
+// the others itself
+var cachedHeaders = []string{"Content-Type", "Content-Language", "Content-Encoding", "Vary"}
+
+// cachedResponse is a response stored by responseCache
+type cachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// responseCache keeps the 200 answers to anonymous GET requests for the TTL, per tenant, path
+// and query, so browsing does not reach the service for every page. A 5xx drops the entry, and
+// a request with Cache-Control: no-cache skips the lookup and refreshes it. Streams are never
+// cached.
+type responseCache struct {
+	cache *cache.Cache
+	cfg   ResponseCacheConfig
+	log   *zap.Logger
+}
+
+func newResponseCache(store cache.Store, cfg ResponseCacheConfig, appLog *logger.Logger) *responseCache {
+	return &responseCache{cache: cache.New(store, "gateway:response", cfg.TTL, appLog), cfg: cfg, log: appLog.Log}
+}
+
+// Handler caches the responses of the handlers after it
+func (rc *responseCache) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rc.cfg.TTL <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.Method != http.MethodGet || c.GetHeader("Authorization") != "" || isStream(c.Request) {
+			c.Header(CacheStatusHeader, "BYPASS")
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		key := rc.key(c)
+		if c.GetHeader("Cache-Control") != "no-cache" {
+			cached, found, err := cache.Get[cachedResponse](ctx, rc.cache, key)
+			if err != nil {
+				rc.log.Warn("Response cache read failed", zap.String("key", key), zap.Error(err))
+			}
+			if found {
+				h := c.Writer.Header()
+				for name, values := range cached.Header {
+					h[name] = values
+				}
+				h.Set(CacheStatusHeader, "HIT")
+				c.Data(cached.Status, cached.Header.Get("Content-Type"), cached.Body)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Header(CacheStatusHeader, "MISS")
+		w := &recordingWriter{ResponseWriter: c.Writer, limit: rc.cfg.MaxBodyBytes}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		switch status := w.Status(); {
+		case status >= http.StatusInternalServerError:
+			rc.drop(ctx, key)
+		case status == http.StatusOK && !w.overflow:
+			header := http.Header{}
+			for _, name := range cachedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					header[name] = values
+				}
+			}
+			res := cachedResponse{Status: status, Header: header, Body: w.body.Bytes()}
+			// the client is already answered; a slow store must not hold the request up
+			storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+			defer cancel()
+			if err := cache.Set(storeCtx, rc.cache, key, res, 0); err != nil {
+				rc.log.Warn("Response cache write failed", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+}
+
+// key identifies a response by tenant, path and query, the parameters sorted so their order
+// does not matter
+func (rc *responseCache) key(c *gin.Context) string {
+	ctx := tenant.ContextWithTenant(c.Request.Context(), middleware.GetTenant(c))
+	return rc.cache.Key(ctx, c.Request.URL.Path, c.Request.URL.Query().Encode())
+}
+
+// drop removes the entry of a route that failed, so the next request asks the service again
+// rather than get an answer older than the failure
+func (rc *responseCache) drop(ctx context.Context, key string) {
+	if err := rc.cache.Delete(context.WithoutCancel(ctx), key); err != nil {
+		rc.log.Warn("Response cache eviction failed", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// recordingWriter keeps a copy of the body written, up to limit bytes
+type recordingWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.record(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *recordingWriter) record(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/retry"
+
+	"go.uber.org/zap"
+)
+
+// RetryPolicy is how the gateway repeats a GET or HEAD whose connection to the service could
+// not be made. Nothing reached the service then, so repeating it is safe.
+type RetryPolicy struct {
+	// Attempts counts the first try; 1 disables retries
+	Attempts    int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// loadRetryPolicies reads <NAME>_SERVICE_RETRY_ATTEMPTS, _RETRY_BACKOFF_MS and
+// _RETRY_MAX_BACKOFF_MS for each service, defaulting to the PROXY_RETRY_* settings
+func loadRetryPolicies(src *config.Source, services []string) map[string]RetryPolicy {
+	def := RetryPolicy{
+		Attempts:    src.Int("PROXY_RETRY_ATTEMPTS", 3),
+		BaseBackoff: src.Duration("PROXY_RETRY_BACKOFF_MS", 100, time.Millisecond),
+		MaxBackoff:  src.Duration("PROXY_RETRY_MAX_BACKOFF_MS", 1000, time.Millisecond),
+	}
+	validateRetryPolicy(src, "PROXY", def)
+	policies := make(map[string]RetryPolicy, len(services))
+	for _, name := range services {
+		prefix := strings.ToUpper(name) + "_SERVICE"
+		p := RetryPolicy{
+			Attempts:    src.Int(prefix+"_RETRY_ATTEMPTS", def.Attempts),
+			BaseBackoff: src.Duration(prefix+"_RETRY_BACKOFF_MS", int(def.BaseBackoff/time.Millisecond), time.Millisecond),
+			MaxBackoff:  src.Duration(prefix+"_RETRY_MAX_BACKOFF_MS", int(def.MaxBackoff/time.Millisecond), time.Millisecond),
+		}
+		validateRetryPolicy(src, prefix, p)
+		policies[name] = p
+	}
+	return policies
+}
+
+func validateRetryPolicy(src *config.Source, prefix string, p RetryPolicy) {
+	if p.Attempts < 1 {
+		src.Fail(prefix+"_RETRY_ATTEMPTS", "must be at least 1")
+	}
+	if p.Attempts > 1 && p.BaseBackoff <= 0 {
+		src.Fail(prefix+"_RETRY_BACKOFF_MS", "must be positive")
+	}
+	if p.MaxBackoff < p.BaseBackoff {
+		src.Fail(prefix+"_RETRY_MAX_BACKOFF_MS", "must not be below the backoff")
+	}
+}
+
+// retryTransport repeats bodiless GET and HEAD requests to target that failed to connect, as
+// long as the policy and the request's context allow
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+	target string
+	log    *zap.Logger
+}
+
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !retryable(r) {
+		return t.next.RoundTrip(r)
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(r)
+		if err == nil || attempt >= t.policy.Attempts || !isConnectError(err) {
+			return resp, err
+		}
+		wait := retry.Backoff(attempt, t.policy.BaseBackoff, t.policy.MaxBackoff)
+		t.log.Warn("Service unreachable, retrying", zap.String("target", t.target), zap.String("path", r.URL.Path),
+			zap.String("request_id", r.Header.Get(middleware.RequestIDHeader)), zap.Int("attempt", attempt), zap.Duration("backoff", wait), zap.Error(err))
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func retryable(r *http.Request) bool {
+	return (r.Method == http.MethodGet || r.Method == http.MethodHead) && (r.Body == nil || r.Body == http.NoBody)
+}
+
+// isConnectError reports whether err happened while dialing, before the request was sent:
+// refused connections, unreachable hosts and failed lookups
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
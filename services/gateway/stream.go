@@ -0,0 +1,44 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// isStream reports whether r opens a connection meant to stay open: a WebSocket, or any other
+// protocol upgrade, or a Server-Sent Events stream
+func isStream(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" && headerHasToken(r.Header, "Connection", "upgrade") {
+		return true
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mediaType == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowStream lifts the server's write timeout from the response of a stream, which would
+// otherwise end it after SERVER_WRITE_TIMEOUT_SECONDS. Upgraded connections are hijacked by the
+// proxy, which clears their deadlines already; event streams stay open as long as the service
+// and the client keep them, and the proxy flushes every event as it arrives. It fails when a
+// writer wrapping the connection hides the deadline, in which case the stream is still cut off.
+func allowStream(c *gin.Context) error {
+	return http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Ways of resolving the tenant of a request, listed in TENANT_RESOLUTION
+const (
+	// ResolveByHost maps the Host of the request to the tenant with that domain
+	ResolveByHost = "host"
+	// ResolveByHeader reads the tenant ID the client sent in X-Tenant-ID
+	ResolveByHeader = "header"
+)
+
+// TenantConfig sets how the gateway finds the store a request is for. Resolution is tried in
+// order, and a request none of them resolves goes to tenant.Default; without Resolution the
+// gateway serves a single store and every request goes to tenant.Default.
+type TenantConfig struct {
+	Resolution []string
+	// Refresh is how long the active tenants read from the user service are reused
+	Refresh       time.Duration
+	InternalToken string
+}
+
+func loadTenantConfig(src *config.Source) TenantConfig {
+	cfg := TenantConfig{
+		Resolution: src.List("TENANT_RESOLUTION", nil),
+		Refresh:    src.Duration("TENANT_REFRESH_SECONDS", 30, time.Second),
+	}
+	for _, r := range cfg.Resolution {
+		if r != ResolveByHost && r != ResolveByHeader {
+			src.Fail("TENANT_RESOLUTION", "entries must be "+ResolveByHost+" or "+ResolveByHeader)
+		}
+	}
+	if len(cfg.Resolution) > 0 {
+		cfg.InternalToken = src.Required("INTERNAL_API_TOKEN")
+	} else {
+		cfg.InternalToken = src.String("INTERNAL_API_TOKEN", "")
+	}
+	return cfg
+}
+
+// activeTenant is an entry of the user service's GET /v1/internal/tenants
+type activeTenant struct {
+	ID   string `json:"id"`
+	Host string `json:"host"`
+}
+
+// tenantRegistry caches the active tenants of the user service. When the user service fails
+// the last known tenants are used.
+type tenantRegistry struct {
+	// services gives the user service instances, which may change on reload
+	services *routeTable
+	token    string
+	refresh  time.Duration
+	http     *http.Client
+	log      *zap.Logger
+
+	mu        sync.RWMutex
+	ids       map[string]bool
+	hosts     map[string]string
+	fetchedAt time.Time
+}
+
+func newTenantRegistry(services *routeTable, cfg TenantConfig, log *zap.Logger) *tenantRegistry {
+	return &tenantRegistry{services: services, token: cfg.InternalToken, refresh: cfg.Refresh,
+		http: &http.Client{Timeout: 5 * time.Second, Transport: otel.Transport(nil)}, log: log}
+}
+
+// fetch reads the active tenants from the user service, asking its instances in turn until
+// one answers
+func (r *tenantRegistry) fetch(ctx context.Context) ([]activeTenant, error) {
+	var errs []error
+	for _, instance := range r.services.Get().UserURLs {
+		tenants, err := r.fetchFrom(ctx, instance)
+		if err == nil {
+			return tenants, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (r *tenantRegistry) fetchFrom(ctx context.Context, instance string) ([]activeTenant, error) {
+	url := strings.TrimSuffix(instance, "/") + "/v1/internal/tenants"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", r.token)
+	res, err := r.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("user service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service %s returned %d for the active tenants", instance, res.StatusCode)
+	}
+	var tenants []activeTenant
+	if err := controllers.DecodeData(res.Body, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+var errNoTenants = errors.New("active tenants are not known yet")
+
+// current returns the tenants by ID and by host, reading them again once they are older than
+// Refresh. It fails only while the tenants were never read.
+func (r *tenantRegistry) current(ctx context.Context) (map[string]bool, map[string]string, error) {
+	r.mu.RLock()
+	ids, hosts, fetchedAt := r.ids, r.hosts, r.fetchedAt
+	r.mu.RUnlock()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < r.refresh {
+		return ids, hosts, nil
+	}
+	tenants, err := r.fetch(ctx)
+	if err != nil {
+		r.log.Error("Failed to read active tenants", zap.Error(err))
+		if ids == nil {
+			return nil, nil, errNoTenants
+		}
+		return ids, hosts, nil
+	}
+	ids, hosts = map[string]bool{tenant.Default: true}, map[string]string{}
+	for _, t := range tenants {
+		ids[t.ID] = true
+		if t.Host != "" {
+			hosts[strings.ToLower(t.Host)] = t.ID
+		}
+	}
+	r.mu.Lock()
+	r.ids, r.hosts, r.fetchedAt = ids, hosts, time.Now()
+	r.mu.Unlock()
+	return ids, hosts, nil
+}
+
+// resolveTenant sets tenant.Header on the request to the tenant cfg.Resolution finds, replacing
+// whatever the client sent. A tenant ID that names no active tenant is answered with 404.
+func resolveTenant(cfg TenantConfig, registry *tenantRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(cfg.Resolution) == 0 {
+			c.Set(middleware.TenantKey, tenant.Default)
+			c.Request.Header.Set(tenant.Header, tenant.Default)
+			c.Next()
+			return
+		}
+		ids, hosts, err := registry.current(c.Request.Context())
+		if err != nil {
+			middleware.AbortWithError(c, http.StatusServiceUnavailable, domainErrors.CodeServiceUnavailable, "service unavailable")
+			return
+		}
+		id := tenant.Default
+	resolve:
+		for _, by := range cfg.Resolution {
+			switch by {
+			case ResolveByHost:
+				if found, ok := hosts[requestHost(c.Request)]; ok {
+					id = found
+					break resolve
+				}
+			case ResolveByHeader:
+				if sent := c.GetHeader(tenant.Header); sent != "" {
+					if !ids[sent] {
+						middleware.AbortWithError(c, http.StatusNotFound, domainErrors.CodeTenantNotFound, "unknown tenant")
+						return
+					}
+					id = sent
+					break resolve
+				}
+			}
+		}
+		c.Set(middleware.TenantKey, id)
+		c.Request.Header.Set(tenant.Header, id)
+		c.Next()
+	}
+}
+
+// requestHost is the lowercase Host of r without the port
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errServiceTimeout cancels the requests of a service that did not answer within its timeout
+var errServiceTimeout = errors.New("service did not answer in time")
+
+// TimeoutPolicy is how long the gateway waits for a service to answer before replying 504
+type TimeoutPolicy struct {
+	// Read applies to GET, HEAD and OPTIONS requests, Write to the others; 0 waits as long as
+	// the server's write timeout allows
+	Read  time.Duration
+	Write time.Duration
+	// Routes override them for the requests they match
+	Routes []RouteTimeout
+}
+
+// RouteTimeout is the timeout of the requests under Prefix, for Method or any method when it
+// is empty
+type RouteTimeout struct {
+	Method  string
+	Prefix  string
+	Timeout time.Duration
+}
+
+// loadTimeoutPolicies reads <NAME>_SERVICE_READ_TIMEOUT_MS and _WRITE_TIMEOUT_MS for each
+// service, defaulting to the PROXY_*_TIMEOUT_MS settings, and the PROXY_ROUTE_TIMEOUTS shared
+// by all of them. Timeouts are kept below the server's write timeout, which would otherwise
+// cut the connection before the gateway could answer.
+func loadTimeoutPolicies(src *config.Source, server config.Server, services []string) map[string]TimeoutPolicy {
+	def := TimeoutPolicy{
+		Read:   src.Duration("PROXY_READ_TIMEOUT_MS", 10000, time.Millisecond),
+		Write:  src.Duration("PROXY_WRITE_TIMEOUT_MS", 25000, time.Millisecond),
+		Routes: routeTimeouts(src, "PROXY_ROUTE_TIMEOUTS"),
+	}
+	validateTimeout(src, server, "PROXY_READ_TIMEOUT_MS", def.Read)
+	validateTimeout(src, server, "PROXY_WRITE_TIMEOUT_MS", def.Write)
+	for _, route := range def.Routes {
+		validateTimeout(src, server, "PROXY_ROUTE_TIMEOUTS", route.Timeout)
+	}
+	policies := make(map[string]TimeoutPolicy, len(services))
+	for _, name := range services {
+		prefix := strings.ToUpper(name) + "_SERVICE"
+		p := TimeoutPolicy{
+			Read:   src.Duration(prefix+"_READ_TIMEOUT_MS", int(def.Read/time.Millisecond), time.Millisecond),
+			Write:  src.Duration(prefix+"_WRITE_TIMEOUT_MS", int(def.Write/time.Millisecond), time.Millisecond),
+			Routes: def.Routes,
+		}
+		validateTimeout(src, server, prefix+"_READ_TIMEOUT_MS", p.Read)
+		validateTimeout(src, server, prefix+"_WRITE_TIMEOUT_MS", p.Write)
+		policies[name] = p
+	}
+	return policies
+}
+
+func validateTimeout(src *config.Source, server config.Server, key string, timeout time.Duration) {
+	if timeout < 0 {
+		src.Fail(key, "must not be negative")
+	}
+	if server.WriteTimeout > 0 && timeout >= server.WriteTimeout {
+		src.Fail(key, "must be below SERVER_WRITE_TIMEOUT_SECONDS")
+	}
+}
+
+// routeTimeouts reads a comma separated list of [METHOD ]/path/prefix=milliseconds, e.g.
+// "POST /v1/sagas/checkout=15000,/v1/search=2000"
+func routeTimeouts(src *config.Source, key string) []RouteTimeout {
+	var routes []RouteTimeout
+	for _, entry := range src.List(key, nil) {
+		route, ms, ok := strings.Cut(entry, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(ms))
+		if !ok || err != nil {
+			src.Fail(key, "entries must be [METHOD ]/path=milliseconds")
+			continue
+		}
+		r := RouteTimeout{Prefix: strings.TrimSpace(route), Timeout: time.Duration(n) * time.Millisecond}
+		if method, path, found := strings.Cut(r.Prefix, " "); found {
+			r.Method, r.Prefix = strings.ToUpper(method), strings.TrimSpace(path)
+		}
+		if !strings.HasPrefix(r.Prefix, "/") {
+			src.Fail(key, "paths must start with /")
+			continue
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// timeout returns the timeout of r: that of the longest matching route, else the read or write
+// timeout by method
+func (p TimeoutPolicy) timeout(r *http.Request) time.Duration {
+	var best *RouteTimeout
+	for i, route := range p.Routes {
+		if (route.Method == "" || route.Method == r.Method) && strings.HasPrefix(r.URL.Path, route.Prefix) &&
+			(best == nil || len(route.Prefix) > len(best.Prefix) || (len(route.Prefix) == len(best.Prefix) && route.Method != "")) {
+			best = &p.Routes[i]
+		}
+	}
+	if best != nil {
+		return best.Timeout
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return p.Read
+	}
+	return p.Write
+}
+
+// withTimeout gives the service timeout to send its response headers, or to accept an upgrade,
+// cancelling the request with errServiceTimeout when it does not. Once they arrive the body may
+// take as long as it needs, so downloads and streams are not cut off. The returned function
+// releases the timer.
+func withTimeout(c *gin.Context, timeout time.Duration) func() {
+	if timeout <= 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancelCause(c.Request.Context())
+	timer := time.AfterFunc(timeout, func() { cancel(errServiceTimeout) })
+	c.Request = c.Request.WithContext(ctx)
+	w := &answeredWriter{ResponseWriter: c.Writer, answered: func() { timer.Stop() }}
+	c.Writer = w
+	return func() {
+		timer.Stop()
+		cancel(nil)
+		c.Writer = w.ResponseWriter
+	}
+}
+
+// isServiceTimeout reports whether r was cancelled by withTimeout
+func isServiceTimeout(r *http.Request) bool {
+	return errors.Is(context.Cause(r.Context()), errServiceTimeout)
+}
+
+// answeredWriter calls answered when the response headers are written or the connection is
+// taken over for an upgrade
+type answeredWriter struct {
+	gin.ResponseWriter
+	answered func()
+}
+
+func (w *answeredWriter) WriteHeader(code int) {
+	w.answered()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *answeredWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.answered()
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *answeredWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+
+	// the services whose gRPC methods routes may lead to, registering their descriptors
+	_ "ecommerce-microservice-go/pkg/proto/order/v1"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCRoute is a REST route the gateway answers by calling a unary gRPC method
+type GRPCRoute struct {
+	// Method is the HTTP method and Path the gin path under /v1, e.g. /orders/:id, whose
+	// parameters name fields of the method's request
+	Method string
+	Path   string
+	// Service names the service whose gRPC address is called, e.g. "order"
+	Service string
+	RPC     protoreflect.MethodDescriptor
+}
+
+// TranscodeConfig is the REST routes the gateway turns into gRPC calls
+type TranscodeConfig struct {
+	Routes []GRPCRoute
+	// Addrs holds the gRPC address of each service the routes lead to
+	Addrs map[string]string
+	// Roles, when set, limit the routes to callers with one of them; otherwise any
+	// authenticated caller may use them and the services decide what it may see
+	Roles []string
+}
+
+// loadTranscodeConfig reads GRPC_ROUTES, a comma separated list of
+// "METHOD /v1/path/{field}=service:package.Service/Method", e.g.
+// "GET /v1/orders/{id}=order:order.v1.OrderService/GetOrder", the <NAME>_SERVICE_GRPC_ADDR of
+// each service they name and GRPC_ROUTE_ROLES. Routes conflicting with one another or with
+// the routes of the gateway are rejected here, since the router would panic on them.
+func loadTranscodeConfig(src *config.Source, internalToken string) TranscodeConfig {
+	cfg := TranscodeConfig{
+		Addrs: map[string]string{},
+		Roles: src.List("GRPC_ROUTE_ROLES", nil),
+	}
+	for _, entry := range src.List("GRPC_ROUTES", nil) {
+		route, err := parseGRPCRoute(entry)
+		if err != nil {
+			src.Fail("GRPC_ROUTES", err.Error())
+			continue
+		}
+		cfg.Routes = append(cfg.Routes, route)
+		if _, ok := cfg.Addrs[route.Service]; !ok {
+			key := strings.ToUpper(route.Service) + "_SERVICE_GRPC_ADDR"
+			if cfg.Addrs[route.Service] = src.String(key, ""); cfg.Addrs[route.Service] == "" {
+				src.Fail(key, "is required by GRPC_ROUTES")
+			}
+		}
+	}
+	if err := routeConflict(cfg.Routes); err != nil {
+		src.Fail("GRPC_ROUTES", err.Error())
+	}
+	if len(cfg.Routes) > 0 && internalToken == "" {
+		src.Fail("GRPC_ROUTES", "require INTERNAL_API_TOKEN, which the services check on gRPC calls")
+	}
+	return cfg
+}
+
+// gatewayRoutes are the routes newRouter registers under /v1, by method or for any method
+// when it is empty
+var gatewayRoutes = []struct{ Method, Path string }{
+	{http.MethodGet, "/health"}, {http.MethodGet, "/health/live"}, {http.MethodGet, "/health/ready"},
+	{http.MethodGet, "/internal/log-level"}, {http.MethodPut, "/internal/log-level"}, {http.MethodPost, "/internal/config/reload"},
+	{"", "/auth/*path"}, {"", "/user/*path"},
+	{"", "/category/*path"}, {"", "/product/*path"}, {"", "/catalog/*path"},
+	{"", "/order/*path"}, {"", "/payment/*path"}, {"", "/review/*path"},
+	{"", "/search"}, {"", "/search/*path"}, {"", "/webhooks"}, {"", "/webhooks/*path"},
+	{"", "/sagas"}, {"", "/sagas/*path"}, {"", "/media/*path"},
+	{"", "/promotions"}, {"", "/promotions/*path"}, {"", "/tax/*path"},
+	{http.MethodGet, "/bff/order/:id"},
+}
+
+// routeConflict adds the gateway's routes and then routes to a router of its own, reporting
+// the first route it refuses, e.g. GET /v1/order/{id} under the proxied /v1/order/*path
+func routeConflict(routes []GRPCRoute) error {
+	// the throwaway router must not print its routes as the real one does in debug mode
+	mode := gin.Mode()
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(mode)
+
+	router := gin.New()
+	nop := func(*gin.Context) {}
+	for _, r := range gatewayRoutes {
+		if r.Method == "" {
+			router.Any(r.Path, nop)
+		} else {
+			router.Handle(r.Method, r.Path, nop)
+		}
+	}
+	for _, route := range routes {
+		if err := tryHandle(router, route.Method, route.Path); err != nil {
+			return fmt.Errorf("%s /v1%s conflicts with another route: %w", route.Method, route.Path, err)
+		}
+	}
+	return nil
+}
+
+func tryHandle(router *gin.Engine, method, path string) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+	router.Handle(method, path, func(*gin.Context) {})
+	return nil
+}
+
+func parseGRPCRoute(entry string) (GRPCRoute, error) {
+	route, target, ok := strings.Cut(entry, "=")
+	method, path, found := strings.Cut(strings.TrimSpace(route), " ")
+	service, rpc, named := strings.Cut(strings.TrimSpace(target), ":")
+	if !ok || !found || !named {
+		return GRPCRoute{}, fmt.Errorf("entries must be METHOD /v1/path=service:package.Service/Method")
+	}
+	r := GRPCRoute{Method: strings.ToUpper(method), Service: service}
+	switch r.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return GRPCRoute{}, fmt.Errorf("method %q is not supported", method)
+	}
+	if !slices.Contains(serviceNames, service) {
+		return GRPCRoute{}, fmt.Errorf("unknown service %q", service)
+	}
+
+	serviceName, methodName, _ := strings.Cut(rpc, "/")
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return GRPCRoute{}, fmt.Errorf("unknown gRPC service %q", serviceName)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok || sd.Methods().ByName(protoreflect.Name(methodName)) == nil {
+		return GRPCRoute{}, fmt.Errorf("unknown gRPC method %q", rpc)
+	}
+	r.RPC = sd.Methods().ByName(protoreflect.Name(methodName))
+	if r.RPC.IsStreamingClient() || r.RPC.IsStreamingServer() {
+		return GRPCRoute{}, fmt.Errorf("streaming method %q cannot be transcoded", rpc)
+	}
+
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/v1/") {
+		return GRPCRoute{}, fmt.Errorf("path %q must start with /v1/", path)
+	}
+	segments := strings.Split(strings.TrimPrefix(path, "/v1"), "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		field := segment[1 : len(segment)-1]
+		if fd := r.RPC.Input().Fields().ByName(protoreflect.Name(field)); fd == nil || !isScalar(fd) || fd.IsList() {
+			return GRPCRoute{}, fmt.Errorf("path parameter %q of %q is not a scalar field of %s", field, path, r.RPC.Input().FullName())
+		}
+		segments[i] = ":" + field
+	}
+	r.Path = strings.Join(segments, "/")
+	return r, nil
+}
+
+func isScalar(fd protoreflect.FieldDescriptor) bool {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind, protoreflect.BytesKind:
+		return false
+	}
+	return !fd.IsMap()
+}
+
+// transcoder answers the GRPC_ROUTES: it builds the request message of the route's method from
+// the path parameters and the query or JSON body, calls the service over gRPC with the
+// internal token, the tenant and the caller, and answers the reply as JSON, in the proto3 JSON
+// mapping.
+// gRPC errors become the HTTP status and code AppErrorToHTTP would give the service's error.
+type transcoder struct {
+	cfg           TranscodeConfig
+	conns         map[string]*grpc.ClientConn
+	timeouts      map[string]TimeoutPolicy
+	internalToken string
+	log           *zap.Logger
+}
+
+func newTranscoder(lc fx.Lifecycle, appCfg Config, appLog *logger.Logger) (*transcoder, error) {
+	t := &transcoder{cfg: appCfg.Transcode, conns: map[string]*grpc.ClientConn{}, timeouts: appCfg.Timeouts,
+		internalToken: appCfg.InternalToken, log: appLog.Log}
+	for service, addr := range t.cfg.Addrs {
+		// connections are made on the first call; dns:/// addresses are spread over all
+		// their instances
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultServiceConfig(`{"loadBalancingConfig":[{"round_robin":{}}]}`))
+		if err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("%s service gRPC client: %w", service, err)
+		}
+		t.conns[service] = conn
+	}
+	app.OnStop(lc, t.Close)
+	return t, nil
+}
+
+// RegisterRoutes adds the routes to router; identify authenticates for the service of a route
+func (t *transcoder) RegisterRoutes(router gin.IRoutes, jwt config.JWT, identify func(audience string) gin.HandlerFunc) {
+	for _, route := range t.cfg.Routes {
+		verify := jwt
+		verify.Audience = route.Service
+		handlers := []gin.HandlerFunc{identify(route.Service), middleware.AuthJWTMiddleware(verify)}
+		if len(t.cfg.Roles) > 0 {
+			handlers = append(handlers, middleware.RequireRoles(t.cfg.Roles...))
+		}
+		router.Handle(route.Method, route.Path, append(handlers, t.handler(route))...)
+		t.log.Info("Transcoding route to gRPC", zap.String("method", route.Method), zap.String("path", "/v1"+route.Path),
+			zap.String("rpc", string(route.RPC.FullName())))
+	}
+}
+
+func (t *transcoder) Close() error {
+	for _, conn := range t.conns {
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func (t *transcoder) handler(route GRPCRoute) gin.HandlerFunc {
+	fullMethod := fmt.Sprintf("/%s/%s", route.RPC.Parent().FullName(), route.RPC.Name())
+	return func(c *gin.Context) {
+		req := dynamicpb.NewMessage(route.RPC.Input())
+		if err := t.readRequest(c, req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				middleware.AbortWithError(c, http.StatusRequestEntityTooLarge, domainErrors.CodePayloadTooLarge,
+					fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+				return
+			}
+			middleware.AbortWithError(c, http.StatusBadRequest, domainErrors.CodeValidation, err.Error())
+			return
+		}
+
+		// the services hold the call to what the caller may do, as they would over REST
+		userID, _ := middleware.UserID(c)
+		ctx := metadata.AppendToOutgoingContext(c.Request.Context(),
+			middleware.InternalTokenMetadataKey, t.internalToken,
+			middleware.UserIDMetadataKey, strconv.Itoa(userID),
+			middleware.UserRolesMetadataKey, strings.Join(middleware.Roles(c), ","),
+			strings.ToLower(middleware.RequestIDHeader), middleware.GetRequestID(c))
+		if id := middleware.GetTenant(c); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, middleware.TenantMetadataKey, id)
+		}
+		if timeout := t.timeouts[route.Service].timeout(c.Request); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		res := dynamicpb.NewMessage(route.RPC.Output())
+		if err := t.conns[route.Service].Invoke(ctx, fullMethod, req, res); err != nil {
+			t.fail(c, fullMethod, err)
+			return
+		}
+
+		body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(res)
+		var reply map[string]any
+		if err == nil {
+			err = json.Unmarshal(body, &reply)
+		}
+		if err != nil {
+			t.log.Error("gRPC reply not encoded", zap.String("rpc", fullMethod), zap.Error(err))
+			middleware.AbortWithError(c, http.StatusInternalServerError, domainErrors.CodeInternal, "internal error")
+			return
+		}
+		controllers.Respond(c, http.StatusOK, reply)
+	}
+}
+
+// fail answers the error of a gRPC call. The caller was authenticated here, so a service
+// refusing the call's credentials means the gateway's INTERNAL_API_TOKEN is wrong: that is
+// answered 502, not 401.
+func (t *transcoder) fail(c *gin.Context, rpc string, err error) {
+	fields := []zap.Field{zap.String("rpc", rpc), zap.String("request_id", middleware.GetRequestID(c)), zap.Error(err)}
+	if grpcstatus.Code(err) == codes.Unauthenticated {
+		t.log.Error("gRPC call refused the internal token", fields...)
+		middleware.AbortWithError(c, http.StatusBadGateway, domainErrors.CodeServiceUnavailable, "service unavailable")
+		return
+	}
+	status, code, message := domainErrors.GRPCToHTTP(err)
+	switch {
+	case status == http.StatusGatewayTimeout:
+		t.log.Warn("gRPC call timed out", fields...)
+	case status >= http.StatusInternalServerError:
+		t.log.Error("gRPC call failed", fields...)
+	}
+	middleware.AbortWithError(c, status, code, message)
+}
+
+// readRequest fills req from the JSON body of POST, PUT and PATCH requests, up to
+// controllers.DefaultMaxBodyBytes, or the query of the others, then from the path parameters,
+// which win over both
+func (t *transcoder) readRequest(c *gin.Context, req *dynamicpb.Message) error {
+	fields := req.Descriptor().Fields()
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, controllers.DefaultMaxBodyBytes))
+		if err != nil {
+			return fmt.Errorf("reading the body: %w", err)
+		}
+		if len(body) > 0 {
+			if err := protojson.Unmarshal(body, req); err != nil {
+				return fmt.Errorf("invalid body: %w", err)
+			}
+		}
+	default:
+		for name, values := range c.Request.URL.Query() {
+			if name == "fields" {
+				// the fields of the reply to answer, for Respond
+				continue
+			}
+			fd := fields.ByJSONName(name)
+			if fd == nil {
+				fd = fields.ByName(protoreflect.Name(name))
+			}
+			if fd == nil || !isScalar(fd) {
+				return fmt.Errorf("unknown query parameter %q", name)
+			}
+			if !fd.IsList() {
+				values = values[len(values)-1:]
+			}
+			for _, v := range values {
+				value, err := scalarValue(fd, v)
+				if err != nil {
+					return fmt.Errorf("query parameter %q: %w", name, err)
+				}
+				if fd.IsList() {
+					req.Mutable(fd).List().Append(value)
+				} else {
+					req.Set(fd, value)
+				}
+			}
+		}
+	}
+	for _, param := range c.Params {
+		fd := fields.ByName(protoreflect.Name(param.Key))
+		value, err := scalarValue(fd, param.Value)
+		if err != nil {
+			return fmt.Errorf("path parameter %q: %w", param.Key, err)
+		}
+		req.Set(fd, value)
+	}
+	return nil
+}
+
+// scalarValue parses s as a value of the scalar field fd
+func scalarValue(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field type %s", fd.Kind())
+}
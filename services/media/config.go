@@ -0,0 +1,87 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/media/storage"
+	"ecommerce-microservice-go/services/media/usecase"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Media     usecase.MediaConfig
+	Storage   storage.Config
+	// GCInterval is how often orphaned files are collected
+	GCInterval     time.Duration
+	BrokerURL      string
+	BrokerExchange string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8090", 60*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "media", false),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Media: usecase.MediaConfig{
+			MaxImageBytes:    int64(src.Int("MEDIA_MAX_IMAGE_BYTES", 10<<20)),
+			MaxDocumentBytes: int64(src.Int("MEDIA_MAX_DOCUMENT_BYTES", 20<<20)),
+			MaxImagePixels:   src.Int("MEDIA_MAX_IMAGE_PIXELS", 40_000_000),
+			OrphanGrace:      src.Duration("MEDIA_ORPHAN_GRACE_HOURS", 24, time.Hour),
+			GCBatchSize:      src.Int("MEDIA_GC_BATCH_SIZE", 100),
+			PublicURL:        src.String("MEDIA_PUBLIC_URL", "http://localhost:9090"),
+			SigningKey:       src.Secret("MEDIA_URL_SIGNING_KEY"),
+			SignedURLTTL:     src.Duration("MEDIA_SIGNED_URL_TTL_SECONDS", 900, time.Second),
+			MaxSignedURLTTL:  src.Duration("MEDIA_MAX_SIGNED_URL_TTL_SECONDS", 86400, time.Second),
+		},
+		Storage: storage.Config{
+			Backend:  src.OneOf("MEDIA_STORAGE_BACKEND", "local", "local", "s3"),
+			LocalDir: src.String("MEDIA_STORAGE_DIR", "./data"),
+			S3: storage.S3Config{
+				Endpoint:       src.String("MEDIA_S3_ENDPOINT", ""),
+				PublicEndpoint: src.String("MEDIA_S3_PUBLIC_ENDPOINT", ""),
+				Region:         src.String("MEDIA_S3_REGION", "us-east-1"),
+				Bucket:         src.String("MEDIA_S3_BUCKET", ""),
+				AccessKey:      src.String("MEDIA_S3_ACCESS_KEY", ""),
+				SecretKey:      src.String("MEDIA_S3_SECRET_KEY", ""),
+				PathStyle:      src.Bool("MEDIA_S3_PATH_STYLE", false),
+				CreateBucket:   src.Bool("MEDIA_S3_CREATE_BUCKET", false),
+				Timeout:        src.Duration("MEDIA_S3_TIMEOUT_SECONDS", 30, time.Second),
+			},
+		},
+		GCInterval:     src.Duration("MEDIA_GC_INTERVAL_MINUTES", 60, time.Minute),
+		BrokerURL:      src.Required("BROKER_URL"),
+		BrokerExchange: src.String("BROKER_EXCHANGE", events.DefaultExchange),
+	}
+	if cfg.Storage.Backend == "s3" {
+		cfg.Storage.S3.AccessKey = src.Secret("MEDIA_S3_ACCESS_KEY")
+		cfg.Storage.S3.SecretKey = src.Secret("MEDIA_S3_SECRET_KEY")
+		if cfg.Storage.S3.Endpoint == "" || cfg.Storage.S3.Bucket == "" {
+			src.Fail("MEDIA_S3_BUCKET", "MEDIA_S3_ENDPOINT and MEDIA_S3_BUCKET are required for the s3 backend")
+		}
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "media", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+)
+
+type Purpose string
+
+const (
+	PurposeProductImage Purpose = "product_image"
+	PurposeAvatar       Purpose = "avatar"
+	// PurposeInvoice files are uploaded by other services and only served through signed URLs
+	PurposeInvoice Purpose = "invoice"
+)
+
+// VariantSpec is a resized copy generated for every uploaded image of a purpose. Crop fills
+// the whole box, cutting the longer side; otherwise the image is fit inside it.
+type VariantSpec struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// PurposeRules decide who may upload a purpose, what it may contain and who may read it
+type PurposeRules struct {
+	// Public files are served to anyone; the others only through signed URLs
+	Public bool
+	// Internal purposes can only be uploaded through the internal API
+	Internal bool
+	// Image purposes are decoded, stripped of metadata and resized into the variants
+	Image        bool
+	ContentTypes []string
+	Variants     []VariantSpec
+}
+
+var imageContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+var Purposes = map[Purpose]PurposeRules{
+	PurposeProductImage: {
+		Public: true, Image: true, ContentTypes: imageContentTypes,
+		Variants: []VariantSpec{{Name: "thumb", Width: 320, Height: 320}, {Name: "medium", Width: 1024, Height: 1024}},
+	},
+	PurposeAvatar: {
+		Public: true, Image: true, ContentTypes: imageContentTypes,
+		Variants: []VariantSpec{{Name: "thumb", Width: 128, Height: 128, Crop: true}, {Name: "medium", Width: 512, Height: 512, Crop: true}},
+	},
+	PurposeInvoice: {
+		Internal: true, ContentTypes: []string{"application/pdf", "text/html"},
+	},
+}
+
+// EventTypes are the broker events the media service consumes: catalog and user events that
+// attach and release files
+var EventTypes = contracts.Types[contracts.Descriptor](contracts.ProductUpserted, contracts.ProductDeleted, contracts.UserDeleted)
+
+// ProductReference is the reference of the image set on a catalog product
+func ProductReference(productID int) string {
+	return "product:" + strconv.Itoa(productID)
+}
+
+// AvatarReference is the reference of a user's current avatar
+func AvatarReference(userID int) string {
+	return "user:" + strconv.Itoa(userID) + ":avatar"
+}
+
+// Requester is the caller of a public endpoint
+type Requester struct {
+	UserID int
+	// CanManageAny reads and deletes every user's files
+	CanManageAny bool
+	// CanUploadProductImages is granted with catalog:write
+	CanUploadProductImages bool
+}
+
+// CanAccess reports whether the requester may read the file's details and private content
+func (r Requester) CanAccess(f *File) bool {
+	return r.CanManageAny || f.OwnerID == r.UserID
+}
+
+type FileStatus string
+
+const (
+	FileActive FileStatus = "active"
+	// FileDeleting files are being removed from storage by the garbage collector
+	FileDeleting FileStatus = "deleting"
+)
+
+// File is an uploaded object. References name what uses it, like "product:12"; files without
+// any are orphaned since OrphanedSince and collected after the grace period.
+type File struct {
+	ID            int
+	UUID          string
+	Purpose       Purpose
+	OwnerID       int
+	FileName      string
+	ContentType   string
+	Size          int64
+	Checksum      string
+	Width         int
+	Height        int
+	StorageKey    string
+	Variants      []Variant
+	Status        FileStatus
+	References    []string
+	OrphanedSince *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Variant returns the named variant, or nil when the file has none by that name
+func (f *File) Variant(name string) *Variant {
+	for i := range f.Variants {
+		if f.Variants[i].Name == name {
+			return &f.Variants[i]
+		}
+	}
+	return nil
+}
+
+// Variant is a resized copy of an image file
+type Variant struct {
+	Name        string `json:"name"`
+	StorageKey  string `json:"storageKey"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// Upload is a file received from a client before it is stored
+type Upload struct {
+	Purpose  Purpose
+	FileName string
+	Data     []byte
+	// OwnerID is set by internal uploads; public uploads are owned by the requester
+	OwnerID int
+	// Reference attaches the file on upload, replacing the files attached to it before
+	Reference string
+}
+
+// ContentPath is the path the media service serves a file's content under; variant may be empty
+func ContentPath(uuid, variant string) string {
+	path := "/v1/media/files/" + uuid + "/content"
+	if variant != "" {
+		path += "?variant=" + variant
+	}
+	return path
+}
@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/media/domain"
+	"ecommerce-microservice-go/services/media/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead is allowed on top of the file size for the other form fields and boundaries
+const multipartOverhead = 64 << 10
+
+type ResponseVariant struct {
+	Name        string `json:"name"`
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+type ResponseFile struct {
+	ID          string `json:"id"`
+	Purpose     string `json:"purpose"`
+	OwnerID     int    `json:"ownerId"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	// URL is set for public files; private ones are downloaded through a signed URL
+	URL        string            `json:"url,omitempty"`
+	Variants   []ResponseVariant `json:"variants,omitempty"`
+	References []string          `json:"references"`
+	// OrphanedSince is set while nothing references the file; it is deleted after the grace period
+	OrphanedSince *time.Time `json:"orphanedSince,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+type ResponseSignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type SetReferenceRequest struct {
+	FileIDs []string `json:"fileIds" binding:"max=20,dive,uuid"`
+}
+
+type Handler struct {
+	mediaUC usecase.IMediaUseCase
+	// publicURL is the base of the file URLs in responses
+	publicURL string
+	// maxUploadBytes bounds the request body of uploads
+	maxUploadBytes int64
+	Logger         *logger.Logger
+}
+
+func NewHandler(uc usecase.IMediaUseCase, cfg usecase.MediaConfig, l *logger.Logger) *Handler {
+	return &Handler{
+		mediaUC: uc, publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+		maxUploadBytes: max(cfg.MaxImageBytes, cfg.MaxDocumentBytes) + multipartOverhead, Logger: l,
+	}
+}
+
+var fileSpec = pagination.Spec{
+	Sortable:    map[string]string{"createdAt": "created_at", "size": "size"},
+	Filterable:  map[string]string{"purpose": "purpose", "ownerId": "owner_id"},
+	DefaultSort: "-createdAt",
+}
+
+// UploadFile godoc
+// @Summary      Upload a file
+// @Description  Stores a product image (needs catalog:write) or the caller's avatar, which replaces the previous one.
+// @Description  Images must be JPEG, PNG or GIF; they are re-encoded without metadata, turned upright and resized
+// @Description  into the purpose's variants. Product images are deleted unless a product uses their URL within the
+// @Description  grace period.
+// @Tags         Media
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Param        file    formData file   true "File"
+// @Param        purpose formData string true "product_image or avatar"
+// @Success      201 {object} ResponseFile
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Failure      413 {object} domainErrors.ErrorResponse
+// @Router       /media/files [post]
+func (h *Handler) UploadFile(ctx *gin.Context) {
+	upload, err := h.readUpload(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	f, err := h.mediaUC.Upload(ctx.Request.Context(), upload, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, h.fileToResponse(f))
+}
+
+// UploadInternal stores a file for another service, e.g. an order's invoice. The form takes
+// the owner's ownerId and a reference to attach the file to besides file and purpose.
+func (h *Handler) UploadInternal(ctx *gin.Context) {
+	upload, err := h.readUpload(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if owner := ctx.PostForm("ownerId"); owner != "" {
+		if upload.OwnerID, err = strconv.Atoi(owner); err != nil || upload.OwnerID < 0 {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid ownerId"), domainErrors.ValidationError))
+			return
+		}
+	}
+	if upload.Reference, err = reference(ctx.PostForm("reference"), true); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	f, err := h.mediaUC.UploadInternal(ctx.Request.Context(), upload)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, h.fileToResponse(f))
+}
+
+// GetFiles godoc
+// @Summary      List files
+// @Description  The caller's files, newest first; media:manage lists every user's
+// @Tags         Media
+// @Security     BearerAuth
+// @Param        purpose query string false "product_image, avatar or invoice"
+// @Param        ownerId query int    false "Owner, for media:manage"
+// @Param        page    query int    false "Page number" default(1)
+// @Param        limit   query int    false "Page size" default(20)
+// @Param        sort    query string false "Comma separated fields, prefix - for descending (createdAt, size)" default(-createdAt)
+// @Param        fields  query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseFile]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /media/files [get]
+func (h *Handler) GetFiles(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, fileSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	files, total, err := h.mediaUC.GetFiles(ctx.Request.Context(), p, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseFile, len(*files))
+	for i := range *files {
+		res[i] = h.fileToResponse(&(*files)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// GetFile godoc
+// @Summary      Get a file's details
+// @Tags         Media
+// @Security     BearerAuth
+// @Param        id     path  string true  "File ID"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseFile
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /media/files/{id} [get]
+func (h *Handler) GetFile(ctx *gin.Context) {
+	id, err := fileID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	f, err := h.mediaUC.GetFile(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, h.fileToResponse(f))
+}
+
+// GetSignedURL godoc
+// @Summary      Get a signed download URL
+// @Description  A URL anyone holding it can download the file or variant from until it expires, for private files
+// @Description  such as invoices. With S3 storage the URL points at the bucket.
+// @Tags         Media
+// @Security     BearerAuth
+// @Param        id      path  string true  "File ID"
+// @Param        variant query string false "Variant name, e.g. thumb"
+// @Param        ttl     query int    false "Validity in seconds"
+// @Success      200 {object} ResponseSignedURL
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /media/files/{id}/signed-url [get]
+func (h *Handler) GetSignedURL(ctx *gin.Context) {
+	id, err := fileID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var ttl time.Duration
+	if raw := ctx.Query("ttl"); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds <= 0 {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("ttl must be a positive number of seconds"), domainErrors.ValidationError))
+			return
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	url, expiresAt, err := h.mediaUC.SignedURL(ctx.Request.Context(), id, ctx.Query("variant"), ttl, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ResponseSignedURL{URL: url, ExpiresAt: expiresAt})
+}
+
+// DeleteFile godoc
+// @Summary      Delete a file
+// @Description  Deletes a file nothing references; files in use answer 409 with the references
+// @Tags         Media
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Success      204
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse
+// @Router       /media/files/{id} [delete]
+func (h *Handler) DeleteFile(ctx *gin.Context) {
+	id, err := fileID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.mediaUC.DeleteFile(ctx.Request.Context(), id, requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetContent godoc
+// @Summary      Download a file
+// @Description  Public files (product images, avatars) are served to anyone and may be cached forever. Private files
+// @Description  need the expires and signature parameters of a signed URL.
+// @Tags         Media
+// @Produce      image/jpeg,image/png,application/pdf,text/html
+// @Param        id        path  string true  "File ID"
+// @Param        variant   query string false "Variant name, e.g. thumb"
+// @Param        expires   query int    false "Expiry of a signed URL"
+// @Param        signature query string false "Signature of a signed URL"
+// @Success      200 {file} file
+// @Success      304
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      410 {object} domainErrors.ErrorResponse "The signed URL expired"
+// @Router       /media/files/{id}/content [get]
+func (h *Handler) GetContent(ctx *gin.Context) {
+	id, err := fileID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	content, err := h.mediaUC.OpenContent(ctx.Request.Context(), id, ctx.Query("variant"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer func() { _ = content.Body.Close() }()
+
+	header := ctx.Writer.Header()
+	etag := `"` + content.ETag + `"`
+	header.Set("ETag", etag)
+	header.Del("Pragma")
+	header.Del("Expires")
+	if content.Public {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		header.Set("Cache-Control", "private, no-store")
+	}
+	// stored HTML must never run as a page of this origin
+	header.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	disposition := "attachment"
+	if content.Inline {
+		disposition = "inline"
+	}
+	if content.FileName != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": content.FileName})
+	}
+	header.Set("Content-Disposition", disposition)
+	if match := ctx.GetHeader("If-None-Match"); match != "" && (match == etag || match == "*") {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.DataFromReader(http.StatusOK, content.Size, content.ContentType, content.Body, nil)
+}
+
+// GetAvatar godoc
+// @Summary      Get a user's avatar
+// @Description  Redirects to the user's current avatar
+// @Tags         Media
+// @Param        userId  path  int    true  "User ID"
+// @Param        variant query string false "thumb or medium"
+// @Success      302
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /media/avatars/{userId} [get]
+func (h *Handler) GetAvatar(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil || userID <= 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid userId"), domainErrors.ValidationError))
+		return
+	}
+	f, err := h.mediaUC.GetAvatar(ctx.Request.Context(), userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	// the avatar changes, so the redirect must not be cached like the content it points at
+	ctx.Redirect(http.StatusFound, h.publicURL+domain.ContentPath(f.UUID, ctx.Query("variant")))
+}
+
+// SetReference attaches exactly the given files to a reference, e.g. "order:12:invoice",
+// releasing the files attached to it before. An empty list releases them all.
+func (h *Handler) SetReference(ctx *gin.Context) {
+	ref, err := reference(ctx.Param("reference"), false)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req SetReferenceRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	if err := h.mediaUC.SetReference(ctx.Request.Context(), ref, req.FileIDs); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReleaseReference releases every file attached to a reference
+func (h *Handler) ReleaseReference(ctx *gin.Context) {
+	ref, err := reference(ctx.Param("reference"), false)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.mediaUC.SetReference(ctx.Request.Context(), ref, nil); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// readUpload reads the file and purpose of a multipart upload, refusing bodies larger than
+// the biggest file any purpose allows
+func (h *Handler) readUpload(ctx *gin.Context) (domain.Upload, error) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.maxUploadBytes)
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return domain.Upload{}, domainErrors.NewAppError(fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit), domainErrors.PayloadTooLarge)
+		}
+		return domain.Upload{}, domainErrors.NewAppError(errors.New("a multipart form with a file field is required"), domainErrors.ValidationError)
+	}
+	data, err := readFile(file)
+	if err != nil {
+		return domain.Upload{}, err
+	}
+	return domain.Upload{Purpose: domain.Purpose(ctx.PostForm("purpose")), FileName: file.Filename, Data: data}, nil
+}
+
+func readFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(f)
+}
+
+func fileID(ctx *gin.Context) (string, error) {
+	id := ctx.Param("id")
+	if uuid.Validate(id) != nil {
+		return "", domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return strings.ToLower(id), nil
+}
+
+// reference validates a reference like "product:12"
+func reference(ref string, optional bool) (string, error) {
+	if ref == "" && optional {
+		return "", nil
+	}
+	if ref == "" || len(ref) > 200 || strings.ContainsAny(ref, " /\\\t\r\n") {
+		return "", domainErrors.NewAppError(errors.New("reference must be 1 to 200 characters without spaces or slashes"), domainErrors.ValidationError)
+	}
+	return ref, nil
+}
+
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:                 int(userIDVal.(float64)),
+		CanManageAny:           middleware.HasPermission(ctx, security.PermMediaManage),
+		CanUploadProductImages: middleware.HasPermission(ctx, security.PermCatalogWrite),
+	}, nil
+}
+
+// Mappers
+func (h *Handler) fileToResponse(f *domain.File) ResponseFile {
+	public := domain.Purposes[f.Purpose].Public
+	res := ResponseFile{
+		ID: f.UUID, Purpose: string(f.Purpose), OwnerID: f.OwnerID, FileName: f.FileName, ContentType: f.ContentType,
+		Size: f.Size, Checksum: f.Checksum, Width: f.Width, Height: f.Height, References: f.References,
+		OrphanedSince: f.OrphanedSince, CreatedAt: f.CreatedAt,
+	}
+	if res.References == nil {
+		res.References = []string{}
+	}
+	if public {
+		res.URL = h.publicURL + domain.ContentPath(f.UUID, "")
+	}
+	for _, v := range f.Variants {
+		variant := ResponseVariant{Name: v.Name, ContentType: v.ContentType, Size: v.Size, Width: v.Width, Height: v.Height}
+		if public {
+			variant.URL = h.publicURL + domain.ContentPath(f.UUID, v.Name)
+		}
+		res.Variants = append(res.Variants, variant)
+	}
+	return res
+}
@@ -0,0 +1,271 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+const jpegQuality = 85
+
+var (
+	ErrInvalidImage  = errors.New("file is not a valid image")
+	ErrTooManyPixels = errors.New("image has too many pixels")
+)
+
+// Size is a box an image is scaled into. Crop fills the box, cutting the longer side;
+// otherwise the image is fit inside it.
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// Image is an encoded image with its dimensions
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Process decodes an uploaded image and encodes it again, which applies the EXIF orientation
+// and drops all metadata, such as the location a photo was taken at. JPEGs stay JPEGs; PNGs
+// and GIFs become PNGs, animated GIFs their first frame. Images with more than maxPixels are
+// rejected before they are decoded. A variant is returned for every size smaller than the
+// image, keyed by name.
+func Process(data []byte, maxPixels int, sizes []Size) (*Image, map[string]*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, nil, fmt.Errorf("%w: %dx%d exceeds %d", ErrTooManyPixels, cfg.Width, cfg.Height, maxPixels)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, ErrInvalidImage
+	}
+	img := toNRGBA(decoded)
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	original, err := encode(img, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	variants := map[string]*Image{}
+	for _, size := range sizes {
+		resized := scale(img, size)
+		if resized == nil {
+			continue
+		}
+		variant, err := encode(resized, format)
+		if err != nil {
+			return nil, nil, err
+		}
+		variants[size.Name] = variant
+	}
+	return original, variants, nil
+}
+
+func encode(img *image.NRGBA, format string) (*Image, error) {
+	var buf bytes.Buffer
+	contentType := "image/png"
+	if format == "jpeg" {
+		contentType = "image/jpeg"
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+	} else if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	return &Image{Data: buf.Bytes(), ContentType: contentType, Width: b.Dx(), Height: b.Dy()}, nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// scale returns the image scaled into the size, or nil when it already fits and needs no crop
+func scale(img *image.NRGBA, size Size) *image.NRGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if size.Crop {
+		// cut the longer side so the aspect ratio matches the box, keeping the center
+		cropW, cropH := w, h
+		if w*size.Height > h*size.Width {
+			cropW = h * size.Width / size.Height
+		} else {
+			cropH = w * size.Height / size.Width
+		}
+		cropped := cropW != w || cropH != h
+		if cropped {
+			x0, y0 := (w-cropW)/2, (h-cropH)/2
+			img = img.SubImage(image.Rect(x0, y0, x0+cropW, y0+cropH)).(*image.NRGBA)
+			w, h = cropW, cropH
+		}
+		if w <= size.Width && h <= size.Height {
+			if !cropped {
+				return nil
+			}
+			return toNRGBA(img)
+		}
+		return resize(img, size.Width, size.Height)
+	}
+	if w <= size.Width && h <= size.Height {
+		return nil
+	}
+	dw, dh := size.Width, h*size.Width/w
+	if dh > size.Height {
+		dw, dh = w*size.Height/h, size.Height
+	}
+	return resize(img, max(dw, 1), max(dh, 1))
+}
+
+// resize shrinks the image with a box filter, averaging every source pixel a target pixel
+// covers. Colors are weighted by alpha so transparent pixels do not darken the edges.
+func resize(src *image.NRGBA, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				off := src.PixOffset(b.Min.X+x0, b.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					p := src.Pix[off : off+4 : off+4]
+					pa := uint64(p[3])
+					r += uint64(p[0]) * pa
+					g += uint64(p[1]) * pa
+					bl += uint64(p[2]) * pa
+					a += pa
+					n++
+					off += 4
+				}
+			}
+			d := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[d] = uint8(r / a)
+				dst.Pix[d+1] = uint8(g / a)
+				dst.Pix[d+2] = uint8(bl / a)
+			}
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient turns the image upright according to its EXIF orientation (1 to 8)
+func orient(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			s, d := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF segment of a JPEG, returning 1 when
+// there is none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// the image data starts at SOS; EXIF always comes before it
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for e := 0; e < entries; e++ {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[off:off+2]) == 0x0112 {
+			return int(order.Uint16(tiff[off+8 : off+10]))
+		}
+	}
+	return 1
+}
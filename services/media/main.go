@@ -0,0 +1,179 @@
+// @title           Media Service API
+// @version         1.0.0
+// @description     Media microservice: uploads of product images, avatars and invoices with image variants, signed URLs and garbage collection of orphaned files
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/scheduler"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/media/domain"
+	"ecommerce-microservice-go/services/media/handler"
+	"ecommerce-microservice-go/services/media/migrations"
+	"ecommerce-microservice-go/services/media/repository"
+	"ecommerce-microservice-go/services/media/storage"
+	"ecommerce-microservice-go/services/media/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/media/docs"
+)
+
+const service app.Service = "media"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Media Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, subscriber events.Subscriber, uc usecase.IMediaUseCase) {
+			app.Subscribe(lc, subscriber, "media.files", domain.EventTypes, uc.HandleEvent)
+			jobs := scheduler.New(log)
+			jobs.Add(scheduler.Job{
+				Name:     "collect-orphaned-files",
+				Interval: cfg.GCInterval,
+				Run:      uc.CollectGarbage,
+			})
+			app.Go(lc, jobs.Run)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Media Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Media),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Subscriber, error) {
+				return app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			func() (storage.Storage, error) {
+				ctx, cancel := context.WithTimeout(context.Background(), cfg.Storage.S3.Timeout)
+				defer cancel()
+				store, err := storage.New(ctx, cfg.Storage)
+				if err != nil {
+					return nil, fmt.Errorf("opening file storage: %w", err)
+				}
+				return store, nil
+			},
+			repository.NewFileRepository,
+			usecase.NewMediaUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, subscriber events.Subscriber,
+	store storage.Storage, migrator *psql.Migrator, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("media", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("media"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("media").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(subscriber)).
+		Add("storage", storage.Check(store)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		RegisterRoutes(v1)
+
+	v1.GET("/media/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	// Public routes: public files and signed URLs are fetched without a token, e.g. by <img> tags
+	public := v1.Group("/media")
+	public.Use(middleware.RateLimit(cacheStore, "media-public", cfg.RateLimit, middleware.KeyByIP, log))
+	{
+		public.GET("/files/:id/content", h.GetContent)
+		public.GET("/avatars/:userId", h.GetAvatar)
+	}
+
+	files := v1.Group("/media/files")
+	files.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RateLimit(cacheStore, "media", cfg.RateLimit, middleware.KeyByUser, log))
+	{
+		files.POST("", h.UploadFile)
+		files.GET("", h.GetFiles)
+		files.GET("/:id", h.GetFile)
+		files.GET("/:id/signed-url", h.GetSignedURL)
+		files.DELETE("/:id", h.DeleteFile)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.POST("/files", h.UploadInternal)
+		internal.PUT("/references/:reference", h.SetReference)
+		internal.DELETE("/references/:reference", h.ReleaseReference)
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS file_references;
+DROP TABLE IF EXISTS file_attachments;
+DROP TABLE IF EXISTS files;
@@ -0,0 +1,43 @@
+CREATE TABLE files (
+    id bigserial,
+    uuid uuid NOT NULL,
+    purpose text NOT NULL,
+    owner_id bigint NOT NULL,
+    file_name text,
+    content_type text NOT NULL,
+    size bigint NOT NULL,
+    checksum text NOT NULL,
+    width bigint,
+    height bigint,
+    storage_key text NOT NULL,
+    variants jsonb NOT NULL DEFAULT '[]',
+    status text NOT NULL DEFAULT 'active',
+    orphaned_since timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_files_purpose CHECK (purpose IN ('product_image', 'avatar', 'invoice')),
+    CONSTRAINT chk_files_status CHECK (status IN ('active', 'deleting'))
+);
+CREATE UNIQUE INDEX idx_files_uuid ON files (uuid);
+CREATE INDEX idx_files_orphaned_since ON files (orphaned_since) WHERE orphaned_since IS NOT NULL;
+CREATE INDEX idx_files_owner_id ON files (owner_id);
+
+-- what uses each file, like "product:12"; files without attachments are orphaned
+CREATE TABLE file_attachments (
+    file_id bigint NOT NULL,
+    reference text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (file_id, reference),
+    CONSTRAINT fk_file_attachments_file FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_file_attachments_reference ON file_attachments (reference);
+
+-- the version of the last change applied to each reference, so events arriving out of order
+-- cannot attach an image that was already replaced
+CREATE TABLE file_references (
+    reference text,
+    version timestamptz NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (reference)
+);
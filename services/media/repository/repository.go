@@ -0,0 +1,324 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/media/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type File struct {
+	ID            int          `gorm:"primaryKey"`
+	UUID          string       `gorm:"column:uuid;type:uuid;not null;uniqueIndex"`
+	Purpose       string       `gorm:"column:purpose;not null"`
+	OwnerID       int          `gorm:"column:owner_id;not null"`
+	FileName      string       `gorm:"column:file_name"`
+	ContentType   string       `gorm:"column:content_type;not null"`
+	Size          int64        `gorm:"column:size;not null"`
+	Checksum      string       `gorm:"column:checksum;not null"`
+	Width         int          `gorm:"column:width"`
+	Height        int          `gorm:"column:height"`
+	StorageKey    string       `gorm:"column:storage_key;not null"`
+	Variants      []byte       `gorm:"column:variants;type:jsonb;not null"`
+	Status        string       `gorm:"column:status;not null"`
+	OrphanedSince *time.Time   `gorm:"column:orphaned_since"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime:mili"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime:mili"`
+	Attachments   []Attachment `gorm:"foreignKey:FileID"`
+}
+
+func (File) TableName() string { return "files" }
+
+type Attachment struct {
+	FileID    int       `gorm:"column:file_id;primaryKey"`
+	Reference string    `gorm:"column:reference;primaryKey"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (Attachment) TableName() string { return "file_attachments" }
+
+type Reference struct {
+	Reference string    `gorm:"column:reference;primaryKey"`
+	Version   time.Time `gorm:"column:version;not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:mili"`
+}
+
+func (Reference) TableName() string { return "file_references" }
+
+type FileRepositoryInterface interface {
+	// Create stores the file; with a reference it is attached in the same transaction, replacing
+	// the files attached to the reference before
+	Create(ctx context.Context, f *domain.File, reference string) (*domain.File, error)
+	// GetByUUID returns an active file with its references
+	GetByUUID(ctx context.Context, uuid string) (*domain.File, error)
+	// GetByReference returns the newest active file attached to the reference
+	GetByReference(ctx context.Context, reference string) (*domain.File, error)
+	// Search lists active files; ownerID 0 lists every owner's
+	Search(ctx context.Context, ownerID int, p pagination.Params) (*[]domain.File, int64, error)
+	// SetReference attaches exactly the given files to the reference, releasing the others. A
+	// change older than the last one applied to the reference is skipped and reports false.
+	// Files that are unknown or being deleted are not attached.
+	SetReference(ctx context.Context, reference string, uuids []string, version time.Time) (bool, error)
+	// MarkDeleting hands an unattached file to the garbage collector, failing with a Conflict
+	// while something uses it
+	MarkDeleting(ctx context.Context, id int) error
+	// ClaimOrphans marks files orphaned before the cutoff as deleting and returns them together
+	// with files whose deletion did not finish
+	ClaimOrphans(ctx context.Context, orphanedBefore time.Time, limit int) (*[]domain.File, error)
+	// Delete removes the row of a file whose objects were deleted
+	Delete(ctx context.Context, id int) error
+}
+
+type FileRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewFileRepository(db *gorm.DB, l *logger.Logger) FileRepositoryInterface {
+	return &FileRepository{DB: db, Logger: l}
+}
+
+func (r *FileRepository) Create(ctx context.Context, d *domain.File, reference string) (*domain.File, error) {
+	f, err := fileFromDomain(d)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	f.OrphanedSince = &now
+	err = psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(f).Error; err != nil {
+			return err
+		}
+		if reference == "" {
+			return nil
+		}
+		_, err := setReference(tx, reference, []string{f.UUID}, now)
+		return err
+	})
+	if err != nil {
+		r.Logger.Error("Error creating file", zap.String("purpose", f.Purpose), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetByUUID(ctx, f.UUID)
+}
+
+func (r *FileRepository) GetByUUID(ctx context.Context, uuid string) (*domain.File, error) {
+	var f File
+	if err := psql.Conn(ctx, r.DB).Preload("Attachments", orderAttachments).
+		Where("uuid = ? AND status = ?", uuid, string(domain.FileActive)).First(&f).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return fileToDomain(&f)
+}
+
+func (r *FileRepository) GetByReference(ctx context.Context, reference string) (*domain.File, error) {
+	var f File
+	if err := psql.Conn(ctx, r.DB).Preload("Attachments", orderAttachments).
+		Where("status = ? AND id IN (?)", string(domain.FileActive),
+			psql.Conn(ctx, r.DB).Model(&Attachment{}).Select("file_id").Where("reference = ?", reference)).
+		Order("id DESC").First(&f).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return fileToDomain(&f)
+}
+
+func (r *FileRepository) Search(ctx context.Context, ownerID int, p pagination.Params) (*[]domain.File, int64, error) {
+	query := psql.Conn(ctx, r.DB).Model(&File{}).Preload("Attachments", orderAttachments).Where("status = ?", string(domain.FileActive))
+	if ownerID != 0 {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	var files []File
+	total, err := pagination.Find(query, p, &files)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := make([]domain.File, len(files))
+	for i := range files {
+		f, err := fileToDomain(&files[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		result[i] = *f
+	}
+	return &result, total, nil
+}
+
+func (r *FileRepository) SetReference(ctx context.Context, reference string, uuids []string, version time.Time) (bool, error) {
+	var applied bool
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = setReference(tx, reference, uuids, version)
+		return err
+	})
+	if err != nil {
+		r.Logger.Error("Error setting file reference", zap.String("reference", reference), zap.Error(err))
+		return false, psql.TranslateError(err)
+	}
+	return applied, nil
+}
+
+func setReference(tx *gorm.DB, reference string, uuids []string, version time.Time) (bool, error) {
+	res := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "reference"}},
+		DoUpdates: clause.AssignmentColumns([]string{"version", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "file_references.version < excluded.version"}}},
+	}).Create(&Reference{Reference: reference, Version: version})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	// locking the files keeps the garbage collector from claiming them while they are attached
+	var keep []int
+	if len(uuids) > 0 {
+		if err := tx.Model(&File{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid IN ? AND status = ?", uuids, string(domain.FileActive)).Pluck("id", &keep).Error; err != nil {
+			return false, err
+		}
+	}
+
+	var released []int
+	release := tx.Model(&Attachment{}).Where("reference = ?", reference)
+	if len(keep) > 0 {
+		release = release.Where("file_id NOT IN ?", keep)
+	}
+	if err := release.Pluck("file_id", &released).Error; err != nil {
+		return false, err
+	}
+	if len(released) > 0 {
+		if err := tx.Where("reference = ? AND file_id IN ?", reference, released).Delete(&Attachment{}).Error; err != nil {
+			return false, err
+		}
+		if err := tx.Model(&File{}).
+			Where("id IN ? AND NOT EXISTS (SELECT 1 FROM file_attachments a WHERE a.file_id = files.id)", released).
+			Update("orphaned_since", time.Now()).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if len(keep) > 0 {
+		attachments := make([]Attachment, len(keep))
+		for i, id := range keep {
+			attachments[i] = Attachment{FileID: id, Reference: reference}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&attachments).Error; err != nil {
+			return false, err
+		}
+		if err := tx.Model(&File{}).Where("id IN ?", keep).Update("orphaned_since", nil).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (r *FileRepository) MarkDeleting(ctx context.Context, id int) error {
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var f File
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", id, string(domain.FileActive)).First(&f).Error; err != nil {
+			return err
+		}
+		var references []string
+		if err := tx.Model(&Attachment{}).Where("file_id = ?", id).Order("reference").Pluck("reference", &references).Error; err != nil {
+			return err
+		}
+		if len(references) > 0 {
+			return domainErrors.NewAppError(fmt.Errorf("file is in use by %s", strings.Join(references, ", ")), domainErrors.Conflict)
+		}
+		return tx.Model(&File{}).Where("id = ?", id).Update("status", string(domain.FileDeleting)).Error
+	})
+	if err != nil {
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *FileRepository) ClaimOrphans(ctx context.Context, orphanedBefore time.Time, limit int) (*[]domain.File, error) {
+	var claimed []File
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND orphaned_since < ?)", string(domain.FileDeleting), string(domain.FileActive), orphanedBefore).
+			Order("orphaned_since NULLS FIRST").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		ids := make([]int, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].Status = string(domain.FileDeleting)
+		}
+		return tx.Model(&File{}).Where("id IN ?", ids).Update("status", string(domain.FileDeleting)).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error claiming orphaned files", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.File, len(claimed))
+	for i := range claimed {
+		f, err := fileToDomain(&claimed[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *f
+	}
+	return &result, nil
+}
+
+func (r *FileRepository) Delete(ctx context.Context, id int) error {
+	if err := psql.Conn(ctx, r.DB).Where("id = ? AND status = ?", id, string(domain.FileDeleting)).Delete(&File{}).Error; err != nil {
+		r.Logger.Error("Error deleting file", zap.Int("id", id), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func orderAttachments(db *gorm.DB) *gorm.DB {
+	return db.Order("reference")
+}
+
+// Mappers
+func fileFromDomain(d *domain.File) (*File, error) {
+	variants, err := json.Marshal(d.Variants)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		UUID: d.UUID, Purpose: string(d.Purpose), OwnerID: d.OwnerID, FileName: d.FileName, ContentType: d.ContentType,
+		Size: d.Size, Checksum: d.Checksum, Width: d.Width, Height: d.Height, StorageKey: d.StorageKey,
+		Variants: variants, Status: string(domain.FileActive),
+	}, nil
+}
+
+func fileToDomain(f *File) (*domain.File, error) {
+	var variants []domain.Variant
+	if err := json.Unmarshal(f.Variants, &variants); err != nil {
+		return nil, err
+	}
+	references := make([]string, len(f.Attachments))
+	for i, a := range f.Attachments {
+		references[i] = a.Reference
+	}
+	return &domain.File{
+		ID: f.ID, UUID: f.UUID, Purpose: domain.Purpose(f.Purpose), OwnerID: f.OwnerID, FileName: f.FileName,
+		ContentType: f.ContentType, Size: f.Size, Checksum: f.Checksum, Width: f.Width, Height: f.Height,
+		StorageKey: f.StorageKey, Variants: variants, Status: domain.FileStatus(f.Status), References: references,
+		OrphanedSince: f.OrphanedSince, CreatedAt: f.CreatedAt, UpdatedAt: f.UpdatedAt,
+	}, nil
+}
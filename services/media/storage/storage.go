@@ -0,0 +1,79 @@
+package storage
+
+//go:generate mockgen -source=storage.go -destination=mocks/storage.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/health"
+)
+
+// ErrNotFound is returned by Open for keys that hold no object
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps the bytes of uploaded files under keys chosen by the media service. Put
+// overwrites and Delete ignores missing keys, so both are safe to repeat.
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// URLSigner is implemented by backends that serve objects themselves through pre-signed URLs,
+// so downloads do not pass through the media service
+type URLSigner interface {
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+type Config struct {
+	// Backend is "local" or "s3"
+	Backend string
+	// LocalDir is the root directory of the local backend
+	LocalDir string
+	S3       S3Config
+}
+
+// New opens the configured backend
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		return NewS3(ctx, cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+}
+
+// validKey rejects keys that could escape the local root or be read as S3 path segments
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// Check is a readiness check that looks up an object that never exists, which proves the
+// backend answers and the credentials are accepted
+func Check(s Storage) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		body, err := s.Open(ctx, "health/probe")
+		if errors.Is(err, ErrNotFound) {
+			return map[string]string{"backend": s.Name()}, nil
+		}
+		if err == nil {
+			_ = body.Close()
+		}
+		return map[string]string{"backend": s.Name()}, err
+	}
+}
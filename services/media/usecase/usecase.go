@@ -0,0 +1,462 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/services/media/domain"
+	"ecommerce-microservice-go/services/media/imaging"
+	"ecommerce-microservice-go/services/media/repository"
+	"ecommerce-microservice-go/services/media/storage"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type IMediaUseCase interface {
+	// Upload stores a file for the requester. Avatars replace the requester's previous avatar;
+	// other files stay orphaned until something references them.
+	Upload(ctx context.Context, upload domain.Upload, requester domain.Requester) (*domain.File, error)
+	// UploadInternal stores a file for another service, of any purpose, attached to the
+	// upload's reference when it has one
+	UploadInternal(ctx context.Context, upload domain.Upload) (*domain.File, error)
+	GetFile(ctx context.Context, uuid string, requester domain.Requester) (*domain.File, error)
+	// GetFiles lists the requester's files, or every file for those who can manage any
+	GetFiles(ctx context.Context, p pagination.Params, requester domain.Requester) (*[]domain.File, int64, error)
+	// GetAvatar returns the user's current avatar
+	GetAvatar(ctx context.Context, userID int) (*domain.File, error)
+	// OpenContent opens the file or one of its variants. Private files need the expiry and
+	// signature of a signed URL.
+	OpenContent(ctx context.Context, uuid, variant, expires, signature string) (*Content, error)
+	// SignedURL returns a URL the file can be downloaded from until it expires
+	SignedURL(ctx context.Context, uuid, variant string, ttl time.Duration, requester domain.Requester) (string, time.Time, error)
+	// DeleteFile hands an unused file to the garbage collector
+	DeleteFile(ctx context.Context, uuid string, requester domain.Requester) error
+	// SetReference attaches exactly the given files to the reference, releasing the others
+	SetReference(ctx context.Context, reference string, uuids []string) error
+	// HandleEvent attaches product images and releases the files of deleted products and users
+	HandleEvent(ctx context.Context, event *events.Event) error
+	// CollectGarbage deletes files orphaned longer than the grace period and returns how many
+	CollectGarbage(ctx context.Context) (int, error)
+}
+
+type MediaConfig struct {
+	MaxImageBytes    int64
+	MaxDocumentBytes int64
+	// MaxImagePixels rejects images that would take too much memory to decode
+	MaxImagePixels int
+	// OrphanGrace is how long a file may stay unreferenced before it is collected, which gives
+	// clients time to reference what they uploaded
+	OrphanGrace time.Duration
+	GCBatchSize int
+	// PublicURL is the base of the URLs handed out, usually the gateway
+	PublicURL       string
+	SigningKey      string
+	SignedURLTTL    time.Duration
+	MaxSignedURLTTL time.Duration
+}
+
+// Content is an opened file or variant
+type Content struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	// ETag never changes, since stored files are not modified
+	ETag     string
+	FileName string
+	Public   bool
+	// Inline content is shown by browsers; the rest is offered as a download
+	Inline bool
+}
+
+type MediaUseCase struct {
+	repo    repository.FileRepositoryInterface
+	storage storage.Storage
+	config  MediaConfig
+	Logger  *logger.Logger
+}
+
+func NewMediaUseCase(r repository.FileRepositoryInterface, s storage.Storage, cfg MediaConfig, l *logger.Logger) IMediaUseCase {
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &MediaUseCase{repo: r, storage: s, config: cfg, Logger: l}
+}
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg", "image/png": ".png", "image/gif": ".gif", "application/pdf": ".pdf", "text/html": ".html",
+}
+
+// fileURLPattern finds the file in URLs handed out by the media service
+var fileURLPattern = regexp.MustCompile(`/media/files/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})(/|$)`)
+
+func (s *MediaUseCase) Upload(ctx context.Context, upload domain.Upload, requester domain.Requester) (*domain.File, error) {
+	rules, ok := domain.Purposes[upload.Purpose]
+	if !ok {
+		return nil, domainErrors.NewAppError(fmt.Errorf("unknown purpose %q", upload.Purpose), domainErrors.ValidationError)
+	}
+	if rules.Internal {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%s files are uploaded by the services that create them", upload.Purpose), domainErrors.NotAuthorized)
+	}
+	if upload.Purpose == domain.PurposeProductImage && !requester.CanUploadProductImages {
+		return nil, domainErrors.NewAppError(errors.New("uploading product images needs catalog:write"), domainErrors.NotAuthorized)
+	}
+	upload.OwnerID, upload.Reference = requester.UserID, ""
+	if upload.Purpose == domain.PurposeAvatar {
+		upload.Reference = domain.AvatarReference(requester.UserID)
+	}
+	return s.store(ctx, upload, rules)
+}
+
+func (s *MediaUseCase) UploadInternal(ctx context.Context, upload domain.Upload) (*domain.File, error) {
+	rules, ok := domain.Purposes[upload.Purpose]
+	if !ok {
+		return nil, domainErrors.NewAppError(fmt.Errorf("unknown purpose %q", upload.Purpose), domainErrors.ValidationError)
+	}
+	return s.store(ctx, upload, rules)
+}
+
+func (s *MediaUseCase) store(ctx context.Context, upload domain.Upload, rules domain.PurposeRules) (*domain.File, error) {
+	limit := s.config.MaxDocumentBytes
+	if rules.Image {
+		limit = s.config.MaxImageBytes
+	}
+	if len(upload.Data) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("file is empty"), domainErrors.ValidationError)
+	}
+	if int64(len(upload.Data)) > limit {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%s files may be at most %d bytes", upload.Purpose, limit), domainErrors.PayloadTooLarge)
+	}
+	// the content decides the type; the name and type the client sent are not trusted
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(upload.Data))
+	if !slices.Contains(rules.ContentTypes, contentType) {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%s files must be one of %s, got %s", upload.Purpose,
+			strings.Join(rules.ContentTypes, ", "), contentType), domainErrors.ValidationError)
+	}
+
+	id := uuid.NewString()
+	base := fmt.Sprintf("%s/%s/%s", upload.Purpose, time.Now().UTC().Format("2006/01"), id)
+	f := &domain.File{
+		UUID: id, Purpose: upload.Purpose, OwnerID: upload.OwnerID, FileName: sanitizeFileName(upload.FileName),
+		ContentType: contentType,
+	}
+	var objects []object
+	data := upload.Data
+	if rules.Image {
+		sizes := make([]imaging.Size, len(rules.Variants))
+		for i, v := range rules.Variants {
+			sizes[i] = imaging.Size{Name: v.Name, Width: v.Width, Height: v.Height, Crop: v.Crop}
+		}
+		original, variants, err := imaging.Process(upload.Data, s.config.MaxImagePixels, sizes)
+		if err != nil {
+			if errors.Is(err, imaging.ErrInvalidImage) || errors.Is(err, imaging.ErrTooManyPixels) {
+				return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+			}
+			return nil, err
+		}
+		data, f.ContentType, f.Width, f.Height = original.Data, original.ContentType, original.Width, original.Height
+		for _, spec := range rules.Variants {
+			v, ok := variants[spec.Name]
+			if !ok {
+				continue
+			}
+			key := base + "_" + spec.Name + extensions[v.ContentType]
+			objects = append(objects, object{key: key, data: v.Data, contentType: v.ContentType})
+			f.Variants = append(f.Variants, domain.Variant{
+				Name: spec.Name, StorageKey: key, ContentType: v.ContentType, Size: int64(len(v.Data)), Width: v.Width, Height: v.Height,
+			})
+		}
+	}
+	f.StorageKey = base + extensions[f.ContentType]
+	f.Size = int64(len(data))
+	sum := sha256.Sum256(data)
+	f.Checksum = hex.EncodeToString(sum[:])
+	objects = append(objects, object{key: f.StorageKey, data: data, contentType: f.ContentType})
+
+	for _, o := range objects {
+		if err := s.storage.Put(ctx, o.key, o.data, o.contentType); err != nil {
+			s.Logger.Error("Error storing file", zap.String("key", o.key), zap.String("backend", s.storage.Name()), zap.Error(err))
+			s.deleteObjects(context.WithoutCancel(ctx), f)
+			return nil, err
+		}
+	}
+	created, err := s.repo.Create(ctx, f, upload.Reference)
+	if err != nil {
+		s.deleteObjects(context.WithoutCancel(ctx), f)
+		return nil, err
+	}
+	s.Logger.Info("Stored file", zap.String("uuid", id), zap.String("purpose", string(f.Purpose)), zap.Int("ownerID", f.OwnerID),
+		zap.Int64("size", f.Size), zap.Int("variants", len(f.Variants)))
+	return created, nil
+}
+
+func (s *MediaUseCase) GetFile(ctx context.Context, uuid string, requester domain.Requester) (*domain.File, error) {
+	f, err := s.repo.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanAccess(f) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return f, nil
+}
+
+func (s *MediaUseCase) GetFiles(ctx context.Context, p pagination.Params, requester domain.Requester) (*[]domain.File, int64, error) {
+	ownerID := requester.UserID
+	if requester.CanManageAny {
+		ownerID = 0
+	}
+	return s.repo.Search(ctx, ownerID, p)
+}
+
+func (s *MediaUseCase) GetAvatar(ctx context.Context, userID int) (*domain.File, error) {
+	return s.repo.GetByReference(ctx, domain.AvatarReference(userID))
+}
+
+func (s *MediaUseCase) OpenContent(ctx context.Context, uuid, variant, expires, signature string) (*Content, error) {
+	f, err := s.repo.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	rules := domain.Purposes[f.Purpose]
+	if !rules.Public {
+		if err := s.verifySignature(uuid, variant, expires, signature); err != nil {
+			return nil, err
+		}
+	}
+	key, contentType, size, err := s.object(f, variant)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.storage.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			s.Logger.Error("File is missing from storage", zap.String("uuid", uuid), zap.String("key", key))
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, err
+	}
+	etag := f.Checksum
+	if variant != "" {
+		etag += "-" + variant
+	}
+	return &Content{
+		Body: body, ContentType: contentType, Size: size, ETag: etag, FileName: f.FileName, Public: rules.Public, Inline: rules.Image,
+	}, nil
+}
+
+func (s *MediaUseCase) SignedURL(ctx context.Context, uuid, variant string, ttl time.Duration, requester domain.Requester) (string, time.Time, error) {
+	f, err := s.GetFile(ctx, uuid, requester)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if ttl == 0 {
+		ttl = s.config.SignedURLTTL
+	}
+	if ttl < 0 || ttl > s.config.MaxSignedURLTTL {
+		return "", time.Time{}, domainErrors.NewAppError(fmt.Errorf("ttl must be between 1 and %d seconds", int(s.config.MaxSignedURLTTL.Seconds())), domainErrors.ValidationError)
+	}
+	key, _, _, err := s.object(f, variant)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	// backends that can sign URLs serve the download themselves
+	if signer, ok := s.storage.(storage.URLSigner); ok {
+		signed, err := signer.SignedURL(key, ttl)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return signed, expiresAt, nil
+	}
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(uuid, variant, expires)}}
+	if variant != "" {
+		query.Set("variant", variant)
+	}
+	return s.config.PublicURL + domain.ContentPath(uuid, "") + "?" + query.Encode(), expiresAt, nil
+}
+
+func (s *MediaUseCase) DeleteFile(ctx context.Context, uuid string, requester domain.Requester) error {
+	f, err := s.GetFile(ctx, uuid, requester)
+	if err != nil {
+		return err
+	}
+	s.Logger.Info("Deleting file", zap.String("uuid", uuid), zap.Int("requesterID", requester.UserID))
+	return s.repo.MarkDeleting(ctx, f.ID)
+}
+
+func (s *MediaUseCase) SetReference(ctx context.Context, reference string, uuids []string) error {
+	for _, id := range uuids {
+		if uuid.Validate(id) != nil {
+			return domainErrors.NewAppError(fmt.Errorf("invalid file id %q", id), domainErrors.ValidationError)
+		}
+	}
+	s.Logger.Info("Setting file reference", zap.String("reference", reference), zap.Int("files", len(uuids)))
+	_, err := s.repo.SetReference(ctx, reference, uuids, time.Now())
+	return err
+}
+
+func (s *MediaUseCase) HandleEvent(ctx context.Context, event *events.Event) error {
+	var reference string
+	var uuids []string
+	switch event.Type {
+	case contracts.ProductUpserted.EventType(), contracts.ProductDeleted.EventType():
+		p, err := contracts.DecodeAny(event, contracts.ProductUpserted, contracts.ProductDeleted)
+		if err != nil {
+			s.Logger.Error("Discarding product event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+			return nil
+		}
+		reference = domain.ProductReference(p.ID)
+		if event.Type == contracts.ProductUpserted.EventType() {
+			uuids = fileUUIDs(p.ImageURL)
+		}
+	case contracts.UserDeleted.EventType():
+		u, err := contracts.UserDeleted.Decode(event)
+		if err != nil {
+			s.Logger.Error("Discarding user event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+			return nil
+		}
+		reference = domain.AvatarReference(u.ID)
+	default:
+		return nil
+	}
+	// the source services build each event after locking the row it changed, so for one
+	// record OccurredAt follows the order of the changes
+	applied, err := s.repo.SetReference(ctx, reference, uuids, event.OccurredAt)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		s.Logger.Debug("Skipping outdated event", zap.String("id", event.ID), zap.String("reference", reference))
+	}
+	return nil
+}
+
+func (s *MediaUseCase) CollectGarbage(ctx context.Context) (int, error) {
+	files, err := s.repo.ClaimOrphans(ctx, time.Now().Add(-s.config.OrphanGrace), s.config.GCBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for i := range *files {
+		f := &(*files)[i]
+		// files whose objects could not all be deleted stay claimed and are tried again
+		if !s.deleteObjects(ctx, f) {
+			continue
+		}
+		if err := s.repo.Delete(ctx, f.ID); err != nil {
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		s.Logger.Info("Deleted orphaned files", zap.Int("count", deleted))
+	}
+	return deleted, nil
+}
+
+// object returns the storage key and type of the file or the named variant. Images smaller
+// than a variant have no copy of that size and are served as they are.
+func (s *MediaUseCase) object(f *domain.File, variant string) (string, string, int64, error) {
+	if variant == "" {
+		return f.StorageKey, f.ContentType, f.Size, nil
+	}
+	if v := f.Variant(variant); v != nil {
+		return v.StorageKey, v.ContentType, v.Size, nil
+	}
+	for _, spec := range domain.Purposes[f.Purpose].Variants {
+		if spec.Name == variant {
+			return f.StorageKey, f.ContentType, f.Size, nil
+		}
+	}
+	return "", "", 0, domainErrors.NewAppError(fmt.Errorf("unknown variant %q", variant), domainErrors.ValidationError)
+}
+
+func (s *MediaUseCase) deleteObjects(ctx context.Context, f *domain.File) bool {
+	ok := true
+	keys := []string{f.StorageKey}
+	for _, v := range f.Variants {
+		keys = append(keys, v.StorageKey)
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.Logger.Error("Error deleting stored object", zap.String("key", key), zap.String("uuid", f.UUID), zap.Error(err))
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (s *MediaUseCase) sign(uuid, variant, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	mac.Write([]byte(uuid + "\n" + variant + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *MediaUseCase) verifySignature(uuid, variant, expires, signature string) error {
+	if expires == "" || signature == "" {
+		return domainErrors.NewAppError(errors.New("this file is only available through a signed URL"), domainErrors.NotAuthorized)
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(uuid, variant, expires))) {
+		return domainErrors.NewAppError(errors.New("invalid signature"), domainErrors.NotAuthorized)
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return domainErrors.NewAppError(errors.New("signed URL has expired"), domainErrors.Expired)
+	}
+	return nil
+}
+
+// fileUUIDs returns the file a media URL points at, if it is one
+func fileUUIDs(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	if m := fileURLPattern.FindStringSubmatch(u.Path); m != nil {
+		return []string{strings.ToLower(m[1])}
+	}
+	return nil
+}
+
+type object struct {
+	key         string
+	data        []byte
+	contentType string
+}
+
+// sanitizeFileName keeps the base name of what the client sent, for Content-Disposition
+func sanitizeFileName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return name
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/services/media/domain"
+	"ecommerce-microservice-go/services/media/repository/mocks"
+	"ecommerce-microservice-go/services/media/storage"
+	storagemocks "ecommerce-microservice-go/services/media/storage/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	testConfig = MediaConfig{MaxImageBytes: 1 << 20, MaxDocumentBytes: 1 << 10, MaxImagePixels: 1 << 20, OrphanGrace: time.Hour,
+		GCBatchSize: 10, PublicURL: "http://gateway/", SigningKey: "secret", SignedURLTTL: time.Minute, MaxSignedURLTTL: time.Hour}
+	pdf        = []byte("%PDF-1.4\n%test\n")
+	fileUUID   = "0b6f4d3e-5c1a-4f2b-9d8e-7a6b5c4d3e2f"
+	errStorage = errors.New("storage unavailable")
+)
+
+func newMediaUseCase(t *testing.T) (*MediaUseCase, *mocks.MockFileRepositoryInterface, *storagemocks.MockStorage) {
+	ctrl := gomock.NewController(t)
+	repo, s := mocks.NewMockFileRepositoryInterface(ctrl), storagemocks.NewMockStorage(ctrl)
+	s.EXPECT().Name().Return("local").AnyTimes()
+	return NewMediaUseCase(repo, s, testConfig, testLogger).(*MediaUseCase), repo, s
+}
+
+func pngOf(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMediaUseCaseUpload(t *testing.T) {
+	buyer := domain.Requester{UserID: 7}
+	tests := []struct {
+		name          string
+		upload        domain.Upload
+		requester     domain.Requester
+		setup         func(repo *mocks.MockFileRepositoryInterface, s *storagemocks.MockStorage)
+		wantReference string
+		wantErr       error
+	}{
+		{
+			name:      "avatar replaces the user's previous one",
+			upload:    domain.Upload{Purpose: domain.PurposeAvatar, FileName: "../me.png", Data: pngOf(t, 600, 600), OwnerID: 99},
+			requester: buyer,
+			setup: func(repo *mocks.MockFileRepositoryInterface, s *storagemocks.MockStorage) {
+				s.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), "image/png").Return(nil).Times(3)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), domain.AvatarReference(7)).DoAndReturn(
+					func(_ context.Context, f *domain.File, _ string) (*domain.File, error) { return f, nil })
+			},
+			wantReference: domain.AvatarReference(7),
+		},
+		{
+			name:      "product images need catalog:write",
+			upload:    domain.Upload{Purpose: domain.PurposeProductImage, Data: pngOf(t, 10, 10)},
+			requester: buyer,
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:      "internal purposes are not uploaded by users",
+			upload:    domain.Upload{Purpose: domain.PurposeInvoice, Data: pdf},
+			requester: buyer,
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:      "content decides the type",
+			upload:    domain.Upload{Purpose: domain.PurposeAvatar, FileName: "me.png", Data: pdf},
+			requester: buyer,
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "empty file",
+			upload:    domain.Upload{Purpose: domain.PurposeAvatar},
+			requester: buyer,
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "unknown purpose",
+			upload:    domain.Upload{Purpose: "resume", Data: pdf},
+			requester: buyer,
+			wantErr:   domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, s := newMediaUseCase(t)
+			if tt.setup != nil {
+				tt.setup(repo, s)
+			}
+			got, err := uc.Upload(context.Background(), tt.upload, tt.requester)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.OwnerID != 7 || got.FileName != "me.png" || len(got.Variants) != 2) {
+				t.Errorf("file = %+v, want me.png owned by 7 with two variants", got)
+			}
+		})
+	}
+}
+
+func TestMediaUseCaseUploadInternal(t *testing.T) {
+	invoice := domain.Upload{Purpose: domain.PurposeInvoice, FileName: "INV-1.pdf", Data: pdf, OwnerID: 7, Reference: "order:12"}
+	tests := []struct {
+		name    string
+		upload  domain.Upload
+		setup   func(repo *mocks.MockFileRepositoryInterface, s *storagemocks.MockStorage)
+		wantErr error
+	}{
+		{
+			name:   "stored with its reference",
+			upload: invoice,
+			setup: func(repo *mocks.MockFileRepositoryInterface, s *storagemocks.MockStorage) {
+				s.EXPECT().Put(gomock.Any(), gomock.Any(), pdf, "application/pdf").Return(nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), "order:12").DoAndReturn(
+					func(_ context.Context, f *domain.File, _ string) (*domain.File, error) { return f, nil })
+			},
+		},
+		{
+			name:   "stored object is removed when the record fails",
+			upload: invoice,
+			setup: func(repo *mocks.MockFileRepositoryInterface, s *storagemocks.MockStorage) {
+				s.EXPECT().Put(gomock.Any(), gomock.Any(), pdf, "application/pdf").Return(nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any(), "order:12").Return(nil, errStorage)
+				s.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: errStorage,
+		},
+		{
+			name:    "above the document limit",
+			upload:  domain.Upload{Purpose: domain.PurposeInvoice, Data: append(pdf, make([]byte, 1<<10)...)},
+			wantErr: domainErrors.ErrPayloadTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, s := newMediaUseCase(t)
+			if tt.setup != nil {
+				tt.setup(repo, s)
+			}
+			if _, err := uc.UploadInternal(context.Background(), tt.upload); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMediaUseCaseOpenContent(t *testing.T) {
+	invoice := &domain.File{ID: 1, UUID: fileUUID, Purpose: domain.PurposeInvoice, StorageKey: "invoice/a.pdf", ContentType: "application/pdf", Checksum: "abc"}
+	future := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	tests := []struct {
+		name      string
+		expires   string
+		signature func(uc *MediaUseCase) string
+		opens     error
+		wantErr   error
+	}{
+		{
+			name:      "signed URL",
+			expires:   future,
+			signature: func(uc *MediaUseCase) string { return uc.sign(fileUUID, "", future) },
+		},
+		{
+			name:      "without a signature",
+			signature: func(*MediaUseCase) string { return "" },
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:      "signature of another expiry",
+			expires:   future,
+			signature: func(uc *MediaUseCase) string { return uc.sign(fileUUID, "", past) },
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:      "expired signed URL",
+			expires:   past,
+			signature: func(uc *MediaUseCase) string { return uc.sign(fileUUID, "", past) },
+			wantErr:   domainErrors.ErrExpired,
+		},
+		{
+			name:      "object missing from storage",
+			expires:   future,
+			signature: func(uc *MediaUseCase) string { return uc.sign(fileUUID, "", future) },
+			opens:     storage.ErrNotFound,
+			wantErr:   domainErrors.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, s := newMediaUseCase(t)
+			repo.EXPECT().GetByUUID(gomock.Any(), fileUUID).Return(invoice, nil)
+			if tt.wantErr == nil || tt.opens != nil {
+				s.EXPECT().Open(gomock.Any(), "invoice/a.pdf").Return(io.NopCloser(strings.NewReader("%PDF")), tt.opens)
+			}
+			got, err := uc.OpenContent(context.Background(), fileUUID, "", tt.expires, tt.signature(uc))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Public || got.Inline || got.ETag != "abc") {
+				t.Errorf("content = %+v, want a private download", got)
+			}
+		})
+	}
+}
+
+func TestMediaUseCaseHandleEvent(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := func(e *events.Event, err error) *events.Event {
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.OccurredAt = at
+		return e
+	}
+	tests := []struct {
+		name      string
+		event     *events.Event
+		reference string
+		uuids     []string
+	}{
+		{
+			name:      "product image is attached",
+			event:     event(contracts.ProductUpserted.NewEvent(context.Background(), contracts.Product{ID: 3, ImageURL: "http://gateway/media/files/" + strings.ToUpper(fileUUID) + "/content"})),
+			reference: domain.ProductReference(3),
+			uuids:     []string{fileUUID},
+		},
+		{
+			name:      "external image releases the previous one",
+			event:     event(contracts.ProductUpserted.NewEvent(context.Background(), contracts.Product{ID: 3, ImageURL: "https://cdn.example.com/tee.png"})),
+			reference: domain.ProductReference(3),
+		},
+		{
+			name:      "deleted user's avatar is released",
+			event:     event(contracts.UserDeleted.NewEvent(context.Background(), contracts.User{ID: 7})),
+			reference: domain.AvatarReference(7),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newMediaUseCase(t)
+			repo.EXPECT().SetReference(gomock.Any(), tt.reference, tt.uuids, at).Return(true, nil)
+			if err := uc.HandleEvent(context.Background(), tt.event); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMediaUseCaseCollectGarbage(t *testing.T) {
+	kept := domain.File{ID: 1, StorageKey: "avatar/kept.png", Variants: []domain.Variant{{Name: "thumb", StorageKey: "avatar/kept_thumb.png"}}}
+	gone := domain.File{ID: 2, StorageKey: "invoice/gone.pdf"}
+	uc, repo, s := newMediaUseCase(t)
+	repo.EXPECT().ClaimOrphans(gomock.Any(), gomock.Any(), 10).Return(&[]domain.File{kept, gone}, nil)
+	s.EXPECT().Delete(gomock.Any(), "avatar/kept.png").Return(nil)
+	s.EXPECT().Delete(gomock.Any(), "avatar/kept_thumb.png").Return(errStorage)
+	s.EXPECT().Delete(gomock.Any(), "invoice/gone.pdf").Return(nil)
+	repo.EXPECT().Delete(gomock.Any(), 2).Return(nil)
+
+	deleted, err := uc.CollectGarbage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d, want 1", deleted)
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+//go:generate mockgen -source=user_client.go -destination=mocks/user_client.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/notification/domain"
+)
+
+type IUserClient interface {
+	GetContact(ctx context.Context, userID int) (*domain.Contact, error)
+}
+
+// UserClient calls the user service internal API directly, bypassing the gateway
+type UserClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewUserClient(baseURL, internalToken string) IUserClient {
+	return &UserClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+func (c *UserClient) GetContact(ctx context.Context, userID int) (*domain.Contact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/internal/users/"+strconv.Itoa(userID)+"/contact", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("user service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service returned %d for user %d", res.StatusCode, userID)
+	}
+	var contact domain.Contact
+	if err := controllers.DecodeData(res.Body, &contact); err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
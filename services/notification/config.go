@@ -0,0 +1,39 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+type Config struct {
+	Server         config.Server
+	Telemetry      otel.Config
+	Log            logger.Config
+	Security       config.SecurityHeaders
+	BrokerURL      string
+	BrokerExchange string
+	UserServiceURL string
+	InternalToken  string
+}
+
+func loadConfig(args []string) (Config, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{
+		Server:         config.LoadServer(src, "9094", 15*time.Second),
+		Security:       config.LoadSecurityHeaders(src),
+		BrokerURL:      src.Required("BROKER_URL"),
+		BrokerExchange: src.String("BROKER_EXCHANGE", events.DefaultExchange),
+		UserServiceURL: src.String("USER_SERVICE_URL", "http://localhost:9091"),
+		InternalToken:  src.String("INTERNAL_API_TOKEN", ""),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "notification", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Err()
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "ecommerce-microservice-go/pkg/contracts"
+
+// OrderEvents are the order events the service sends emails for
+var OrderEvents = []contracts.Contract[contracts.Order]{
+	contracts.OrderCreated, contracts.OrderPaid, contracts.OrderShipped, contracts.OrderDelivered, contracts.OrderCancelled,
+}
+
+type Contact struct {
+	ID          int    `json:"id"`
+	Email       string `json:"email"`
+	FirstName   string `json:"firstName"`
+	OrderEmails bool   `json:"orderEmails"`
+}
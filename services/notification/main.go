@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/mailer"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/notification/client"
+	"ecommerce-microservice-go/services/notification/domain"
+	"ecommerce-microservice-go/services/notification/templates"
+	"ecommerce-microservice-go/services/notification/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+const service app.Service = "notification"
+
+func main() {
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Notification Service")
+
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(func(lc fx.Lifecycle, subscriber events.Subscriber, uc usecase.INotificationUseCase) {
+			app.Subscribe(lc, subscriber, "notification.order-emails", contracts.Types(domain.OrderEvents...), uc.HandleOrderEvent)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Notification Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry),
+		fx.Provide(
+			app.Telemetry,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Subscriber, error) {
+				return app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			func() (*templates.Renderer, error) {
+				renderer, err := templates.NewRenderer()
+				if err != nil {
+					return nil, fmt.Errorf("parsing email templates: %w", err)
+				}
+				return renderer, nil
+			},
+			func() client.IUserClient { return client.NewUserClient(cfg.UserServiceURL, cfg.InternalToken) },
+			mailer.NewMailerFromEnv,
+			usecase.NewNotificationUseCase,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, subscriber events.Subscriber) *gin.Engine {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("notification", log), telemetry.Middleware(), metrics.Middleware("notification"))
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.GET("/metrics", metrics.Handler())
+	v1 := router.Group("/v1")
+	health.NewRegistry("notification").
+		Add("broker", events.BrokerCheck(subscriber)).
+		RegisterRoutes(v1)
+
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router
+}
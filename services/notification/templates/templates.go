@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/services/notification/domain"
+)
+
+//go:embed *.html
+var files embed.FS
+
+type Email struct {
+	Subject string
+	Body    string
+}
+
+type emailTemplate struct {
+	subject string
+	file    string
+}
+
+var byEvent = map[string]emailTemplate{
+	contracts.OrderCreated.EventType():   {"Order %s confirmed", "order_created.html"},
+	contracts.OrderPaid.EventType():      {"Payment received for order %s", "order_paid.html"},
+	contracts.OrderShipped.EventType():   {"Order %s has shipped", "order_shipped.html"},
+	contracts.OrderDelivered.EventType(): {"Order %s was delivered", "order_delivered.html"},
+	contracts.OrderCancelled.EventType(): {"Order %s was cancelled", "order_cancelled.html"},
+}
+
+type Renderer struct {
+	tmpl *template.Template
+}
+
+func NewRenderer() (*Renderer, error) {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	}).ParseFS(files, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{tmpl: tmpl}, nil
+}
+
+// Supports reports whether an email is sent for the event type
+func Supports(eventType string) bool {
+	_, ok := byEvent[eventType]
+	return ok
+}
+
+func (r *Renderer) Render(eventType string, contact *domain.Contact, order *contracts.Order) (*Email, error) {
+	t, ok := byEvent[eventType]
+	if !ok {
+		return nil, fmt.Errorf("no email template for %s", eventType)
+	}
+	var buf bytes.Buffer
+	err := r.tmpl.ExecuteTemplate(&buf, t.file, struct {
+		Contact *domain.Contact
+		Order   *contracts.Order
+	}{contact, order})
+	if err != nil {
+		return nil, err
+	}
+	return &Email{Subject: fmt.Sprintf(t.subject, order.OrderNumber), Body: buf.String()}, nil
+}
@@ -0,0 +1,59 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/mailer"
+	"ecommerce-microservice-go/services/notification/client"
+	"ecommerce-microservice-go/services/notification/domain"
+	"ecommerce-microservice-go/services/notification/templates"
+
+	"go.uber.org/zap"
+)
+
+type INotificationUseCase interface {
+	HandleOrderEvent(ctx context.Context, event *events.Event) error
+}
+
+type NotificationUseCase struct {
+	userClient client.IUserClient
+	renderer   *templates.Renderer
+	mailer     mailer.Mailer
+	Logger     *logger.Logger
+}
+
+func NewNotificationUseCase(uc client.IUserClient, r *templates.Renderer, m mailer.Mailer, l *logger.Logger) INotificationUseCase {
+	return &NotificationUseCase{userClient: uc, renderer: r, mailer: m, Logger: l}
+}
+
+// HandleOrderEvent sends the email matching the order event unless the user opted out. The
+// contact is looked up in the event's tenant, so an order only ever mails a user of its store.
+func (s *NotificationUseCase) HandleOrderEvent(ctx context.Context, event *events.Event) error {
+	if !templates.Supports(event.Type) {
+		return nil
+	}
+	order, err := contracts.DecodeAny(event, domain.OrderEvents...)
+	if err != nil {
+		s.Logger.WithContext(ctx).Error("Discarding order event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+		return nil
+	}
+	contact, err := s.userClient.GetContact(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	if !contact.OrderEmails {
+		s.Logger.WithContext(ctx).Info("User opted out of order emails", zap.Int("userID", order.UserID), zap.String("type", event.Type))
+		return nil
+	}
+	email, err := s.renderer.Render(event.Type, contact, &order)
+	if err != nil {
+		return err
+	}
+	s.Logger.WithContext(ctx).Info("Sending order email", zap.String("type", event.Type), zap.Int("orderID", order.OrderID))
+	return s.mailer.Send(&mailer.Message{To: contact.Email, Subject: email.Subject, HTMLBody: email.Body})
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/mailer"
+	mailermocks "ecommerce-microservice-go/pkg/mailer/mocks"
+	"ecommerce-microservice-go/pkg/tenant"
+	clientmocks "ecommerce-microservice-go/services/notification/client/mocks"
+	"ecommerce-microservice-go/services/notification/domain"
+	"ecommerce-microservice-go/services/notification/templates"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+// mailTo matches a message to the address whose subject contains the text
+type mailTo struct{ to, subject string }
+
+func (m mailTo) Matches(x any) bool {
+	msg, ok := x.(*mailer.Message)
+	return ok && msg.To == m.to && strings.Contains(msg.Subject, m.subject)
+}
+
+func (m mailTo) String() string { return "is an email to " + m.to + " about " + m.subject }
+
+// inTenant matches a context scoped to the tenant
+type inTenant string
+
+func (m inTenant) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	return ok && tenant.FromContext(ctx) == string(m)
+}
+
+func (m inTenant) String() string { return "is a context of tenant " + string(m) }
+
+func TestNotificationUseCaseHandleOrderEvent(t *testing.T) {
+	order := contracts.Order{OrderID: 12, OrderNumber: "ORD-12", UserID: 7, Status: "shipped", TotalAmount: 50, Currency: "IDR"}
+	shipped, err := contracts.OrderShipped.NewEvent(tenant.ContextWithTenant(context.Background(), "acme"), order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contact := &domain.Contact{ID: 7, Email: "buyer@example.com", FirstName: "Budi", OrderEmails: true}
+	errUnavailable := errors.New("user service unavailable")
+
+	tests := []struct {
+		name    string
+		event   *events.Event
+		setup   func(users *clientmocks.MockIUserClient, m *mailermocks.MockMailer)
+		wantErr error
+	}{
+		{
+			name:  "email sent to the user's address",
+			event: shipped,
+			setup: func(users *clientmocks.MockIUserClient, m *mailermocks.MockMailer) {
+				users.EXPECT().GetContact(inTenant("acme"), 7).Return(contact, nil)
+				m.EXPECT().Send(mailTo{"buyer@example.com", "ORD-12"}).Return(nil)
+			},
+		},
+		{
+			name:  "user opted out",
+			event: shipped,
+			setup: func(users *clientmocks.MockIUserClient, _ *mailermocks.MockMailer) {
+				users.EXPECT().GetContact(gomock.Any(), 7).Return(&domain.Contact{ID: 7, Email: "buyer@example.com"}, nil)
+			},
+		},
+		{
+			name:  "user service failure is retried",
+			event: shipped,
+			setup: func(users *clientmocks.MockIUserClient, _ *mailermocks.MockMailer) {
+				users.EXPECT().GetContact(gomock.Any(), 7).Return(nil, errUnavailable)
+			},
+			wantErr: errUnavailable,
+		},
+		{
+			name:  "invalid payload is discarded",
+			event: &events.Event{ID: "1", Type: contracts.OrderShipped.EventType(), Payload: json.RawMessage(`{"userId":"seven"}`)},
+		},
+		{
+			name:  "events without a template are ignored",
+			event: &events.Event{ID: "1", Type: contracts.UserDeleted.EventType()},
+		},
+	}
+	renderer, err := templates.NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users, m := clientmocks.NewMockIUserClient(ctrl), mailermocks.NewMockMailer(ctrl)
+			if tt.setup != nil {
+				tt.setup(users, m)
+			}
+			if err := NewNotificationUseCase(users, renderer, m, testLogger).HandleOrderEvent(tt.event.Context(context.Background()), tt.event); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package carrier
+
+//go:generate mockgen -source=carrier.go -destination=mocks/carrier.go -package=mocks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+var (
+	ErrUnknownCarrier      = errors.New("unknown carrier")
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
+	ErrPollingNotSupported = errors.New("carrier does not support polling")
+)
+
+// Adapter connects one carrier. Carriers that push updates implement ParseWebhook;
+// carriers that must be polled implement Track and report SupportsPolling.
+type Adapter interface {
+	Name() string
+	TrackingURL(trackingNumber string) string
+	// ParseWebhook authenticates the carrier request and returns the updates it carries
+	ParseWebhook(r *http.Request) ([]domain.TrackingUpdate, error)
+	SupportsPolling() bool
+	Track(trackingNumber string) (*domain.TrackingUpdate, error)
+}
+
+type Registry struct {
+	adapters map[string]Adapter
+}
+
+func NewRegistry(adapters ...Adapter) *Registry {
+	r := &Registry{adapters: make(map[string]Adapter, len(adapters))}
+	for _, a := range adapters {
+		r.adapters[a.Name()] = a
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (Adapter, error) {
+	a, ok := r.adapters[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrUnknownCarrier
+	}
+	return a, nil
+}
+
+func (r *Registry) All() []Adapter {
+	result := make([]Adapter, 0, len(r.adapters))
+	for _, a := range r.adapters {
+		result = append(result, a)
+	}
+	return result
+}
+
+// NewRegistryFromEnv builds a generic adapter for every carrier listed in CARRIERS
+// (comma separated), configured by CARRIER_<NAME>_WEBHOOK_SECRET,
+// CARRIER_<NAME>_TRACKING_URL (with %s for the tracking number) and the optional
+// CARRIER_<NAME>_API_URL used for polling.
+func NewRegistryFromEnv() (*Registry, error) {
+	var adapters []Adapter
+	for _, name := range strings.Split(os.Getenv("CARRIERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "CARRIER_" + strings.ToUpper(name) + "_"
+		secret := os.Getenv(prefix + "WEBHOOK_SECRET")
+		if secret == "" {
+			return nil, fmt.Errorf("%sWEBHOOK_SECRET is required", prefix)
+		}
+		adapters = append(adapters, NewGenericAdapter(GenericConfig{
+			Name:                name,
+			WebhookSecret:       secret,
+			TrackingURLTemplate: os.Getenv(prefix + "TRACKING_URL"),
+			APIURL:              os.Getenv(prefix + "API_URL"),
+			Timeout:             10 * time.Second,
+		}))
+	}
+	return NewRegistry(adapters...), nil
+}
@@ -0,0 +1,131 @@
+package carrier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+const (
+	SignatureHeader = "X-Carrier-Signature"
+	maxWebhookBody  = 1 << 20
+)
+
+type GenericConfig struct {
+	Name                string
+	WebhookSecret       string
+	TrackingURLTemplate string
+	APIURL              string
+	Timeout             time.Duration
+}
+
+// GenericAdapter speaks a simple JSON format: webhooks post one update or an array of
+// updates signed with HMAC-SHA256 of the body in X-Carrier-Signature, and polling
+// issues GET {APIURL}/{trackingNumber} returning a single update.
+type GenericAdapter struct {
+	cfg    GenericConfig
+	client *http.Client
+}
+
+type genericUpdate struct {
+	TrackingNumber string    `json:"trackingNumber"`
+	Status         string    `json:"status"`
+	Description    string    `json:"description"`
+	OccurredAt     time.Time `json:"occurredAt"`
+}
+
+func NewGenericAdapter(cfg GenericConfig) Adapter {
+	return &GenericAdapter{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (a *GenericAdapter) Name() string { return a.cfg.Name }
+
+func (a *GenericAdapter) TrackingURL(trackingNumber string) string {
+	if a.cfg.TrackingURLTemplate == "" {
+		return ""
+	}
+	return fmt.Sprintf(a.cfg.TrackingURLTemplate, url.PathEscape(trackingNumber))
+}
+
+func (a *GenericAdapter) ParseWebhook(r *http.Request) ([]domain.TrackingUpdate, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, []byte(a.cfg.WebhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(r.Header.Get(SignatureHeader), "sha256="))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var raw []genericUpdate
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(body, &raw)
+	} else {
+		var single genericUpdate
+		err = json.Unmarshal(body, &single)
+		raw = []genericUpdate{single}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	updates := make([]domain.TrackingUpdate, 0, len(raw))
+	for _, u := range raw {
+		update, err := u.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+func (a *GenericAdapter) SupportsPolling() bool { return a.cfg.APIURL != "" }
+
+func (a *GenericAdapter) Track(trackingNumber string) (*domain.TrackingUpdate, error) {
+	if !a.SupportsPolling() {
+		return nil, ErrPollingNotSupported
+	}
+	resp, err := a.client.Get(strings.TrimRight(a.cfg.APIURL, "/") + "/" + url.PathEscape(trackingNumber))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("carrier %s returned %d", a.cfg.Name, resp.StatusCode)
+	}
+	var u genericUpdate
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxWebhookBody)).Decode(&u); err != nil {
+		return nil, err
+	}
+	if u.TrackingNumber == "" {
+		u.TrackingNumber = trackingNumber
+	}
+	update, err := u.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+func (u genericUpdate) toDomain() (domain.TrackingUpdate, error) {
+	status := domain.ShipmentStatus(u.Status)
+	if u.TrackingNumber == "" || !status.IsValid() {
+		return domain.TrackingUpdate{}, errors.New("tracking update needs trackingNumber and a valid status")
+	}
+	if u.OccurredAt.IsZero() {
+		u.OccurredAt = time.Now().UTC()
+	}
+	return domain.TrackingUpdate{TrackingNumber: u.TrackingNumber, Status: status, Description: u.Description, OccurredAt: u.OccurredAt}, nil
+}
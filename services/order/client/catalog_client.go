@@ -0,0 +1,143 @@
+package client
+
+//go:generate mockgen -source=catalog_client.go -destination=mocks/catalog_client.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	// ErrReservationRejected wraps the catalog's reason for refusing a reservation, such as missing stock
+	ErrReservationRejected = errors.New("stock reservation rejected")
+)
+
+type ICatalogClient interface {
+	GetProduct(ctx context.Context, productID int) (*domain.CatalogProduct, error)
+	// GetProductBySKU finds an active product by its SKU
+	GetProductBySKU(ctx context.Context, sku string) (*domain.CatalogProduct, error)
+	// ReserveStock takes the items out of catalog stock under reference; retrying a reference is safe
+	ReserveStock(ctx context.Context, reference string, items []domain.ReservationItem) error
+	// ReleaseStock returns the reserved units; releasing an unknown or released reference succeeds
+	ReleaseStock(ctx context.Context, reference string) error
+}
+
+// CatalogClient reads products from the catalog service's public API and reserves stock
+// through its internal API, bypassing the gateway. Requests carry the tenant of their context.
+type CatalogClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewCatalogClient(baseURL, internalToken string) ICatalogClient {
+	return &CatalogClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+func (c *CatalogClient) GetProduct(ctx context.Context, productID int) (*domain.CatalogProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/product/"+strconv.Itoa(productID), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("catalog service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrProductNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog service returned %d for product %d", res.StatusCode, productID)
+	}
+	var product domain.CatalogProduct
+	if err := controllers.DecodeData(res.Body, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (c *CatalogClient) GetProductBySKU(ctx context.Context, sku string) (*domain.CatalogProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/product/?"+url.Values{"sku": {sku}, "limit": {"1"}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("catalog service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog service returned %d for product %s", res.StatusCode, sku)
+	}
+	var page struct {
+		Data []domain.CatalogProduct `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	if len(page.Data) == 0 {
+		return nil, ErrProductNotFound
+	}
+	return &page.Data[0], nil
+}
+
+func (c *CatalogClient) ReserveStock(ctx context.Context, reference string, items []domain.ReservationItem) error {
+	body, err := json.Marshal(map[string]any{"reference": reference, "items": items})
+	if err != nil {
+		return err
+	}
+	res, err := c.internalPost(ctx, "/v1/internal/reservations", body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	switch {
+	case res.StatusCode == http.StatusOK:
+		return nil
+	case res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrReservationRejected, controllers.DecodeError(res.Body).Message)
+	default:
+		return fmt.Errorf("catalog service returned %d for reservation %s", res.StatusCode, reference)
+	}
+}
+
+func (c *CatalogClient) ReleaseStock(ctx context.Context, reference string) error {
+	res, err := c.internalPost(ctx, "/v1/internal/reservations/"+url.PathEscape(reference)+"/release", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("catalog service returned %d releasing reservation %s", res.StatusCode, reference)
+	}
+	return nil
+}
+
+func (c *CatalogClient) internalPost(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("catalog service request failed: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/pact"
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+// The pacts list what the order service needs from the user and catalog APIs. TestPacts runs
+// the clients against them; the providers verify them in their own TestPacts.
+
+var internalHeaders = map[string]string{"X-Internal-Token": pact.InternalToken}
+
+var userPact = pact.Pact{
+	Consumer: "order",
+	Provider: "user",
+	Interactions: []pact.Interaction{
+		{
+			Description: "get the email of a user",
+			State:       "user 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/internal/users/7/contact", Headers: internalHeaders},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": map[string]any{"email": "buyer@example.com"}}},
+		},
+		{
+			Description: "find a user by email",
+			State:       "user 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/internal/users", Query: "email=buyer%40example.com", Headers: internalHeaders},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": map[string]any{"id": 7}}},
+		},
+		{
+			Description: "find a user by an unknown email",
+			State:       "no user has the email nobody@example.com",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/internal/users", Query: "email=nobody%40example.com", Headers: internalHeaders},
+			Response:    pact.Response{Status: http.StatusNotFound},
+		},
+	},
+}
+
+var catalogProduct = map[string]any{"id": 7, "name": "Basic Tee", "price": 25.5, "stock": 10, "isActive": true}
+
+var catalogPact = pact.Pact{
+	Consumer: "order",
+	Provider: "catalog",
+	Interactions: []pact.Interaction{
+		{
+			Description: "get a product",
+			State:       "product 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/7"},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": catalogProduct}},
+		},
+		{
+			Description: "get a missing product",
+			State:       "product 99 does not exist",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/99"},
+			Response:    pact.Response{Status: http.StatusNotFound},
+		},
+		{
+			Description: "find a product by SKU",
+			State:       "product 7 exists",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/", Query: "limit=1&sku=TEE-01"},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": []any{catalogProduct}}},
+		},
+		{
+			Description: "find a product by an unknown SKU",
+			State:       "no product has the SKU NOPE-01",
+			Request:     pact.Request{Method: http.MethodGet, Path: "/v1/product/", Query: "limit=1&sku=NOPE-01"},
+			Response:    pact.Response{Status: http.StatusOK, Body: map[string]any{"data": []any{}}},
+		},
+		{
+			Description: "reserve stock",
+			State:       "product 7 has 10 in stock",
+			Request: pact.Request{Method: http.MethodPost, Path: "/v1/internal/reservations", Headers: internalHeaders,
+				Body: map[string]any{"reference": "order-1", "items": []any{map[string]any{"productId": 7, "quantity": 2}}}},
+			Response: pact.Response{Status: http.StatusOK},
+		},
+		{
+			Description: "reserve more than the stock",
+			State:       "product 7 has 1 in stock",
+			Request: pact.Request{Method: http.MethodPost, Path: "/v1/internal/reservations", Headers: internalHeaders,
+				Body: map[string]any{"reference": "order-1", "items": []any{map[string]any{"productId": 7, "quantity": 2}}}},
+			Response: pact.Response{Status: http.StatusBadRequest, Body: map[string]any{
+				"errors": []any{map[string]any{"message": "insufficient stock for product 7"}},
+			}},
+		},
+		{
+			Description: "release a reservation",
+			State:       "reservation order-1 is active",
+			Request:     pact.Request{Method: http.MethodPost, Path: "/v1/internal/reservations/order-1/release", Headers: internalHeaders},
+			Response:    pact.Response{Status: http.StatusOK},
+		},
+		{
+			Description: "release an unknown reservation",
+			State:       "there is no reservation order-2",
+			Request:     pact.Request{Method: http.MethodPost, Path: "/v1/internal/reservations/order-2/release", Headers: internalHeaders},
+			Response:    pact.Response{Status: http.StatusNotFound},
+		},
+	},
+}
+
+// updatePacts writes the pacts instead of comparing them with the committed files (make pacts)
+var updatePacts = flag.Bool("update", false, "write the pacts to pkg/pact/pacts")
+
+// TestPacts runs the user and catalog clients against every interaction of their pacts, then
+// checks the committed pacts are the same
+func TestPacts(t *testing.T) {
+	if err := checkPacts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	dir := ""
+	if *updatePacts {
+		dir = "../../../pkg/pact/pacts"
+	}
+	if err := pact.Publish([]pact.Pact{userPact, catalogPact}, dir); err != nil {
+		t.Error(err)
+	}
+}
+
+func checkPacts(ctx context.Context) error {
+	users := func(baseURL string) IUserClient { return NewUserClient(baseURL, pact.InternalToken) }
+	catalog := func(baseURL string) ICatalogClient { return NewCatalogClient(baseURL, pact.InternalToken) }
+	items := []domain.ReservationItem{{ProductID: 7, Quantity: 2}}
+
+	return errors.Join(
+		pact.Consume(ctx, userPact, map[string]pact.Check{
+			"get the email of a user": func(ctx context.Context, baseURL string) error {
+				email, err := users(baseURL).GetEmail(ctx, 7)
+				return expect(err == nil && email == "buyer@example.com", "got %q, %v", email, err)
+			},
+			"find a user by email": func(ctx context.Context, baseURL string) error {
+				id, err := users(baseURL).FindUserID(ctx, "buyer@example.com")
+				return expect(err == nil && id == 7, "got user %d, %v", id, err)
+			},
+			"find a user by an unknown email": func(ctx context.Context, baseURL string) error {
+				_, err := users(baseURL).FindUserID(ctx, "nobody@example.com")
+				return expect(errors.Is(err, ErrUserNotFound), "got %v, want ErrUserNotFound", err)
+			},
+		}),
+		pact.Consume(ctx, catalogPact, map[string]pact.Check{
+			"get a product": func(ctx context.Context, baseURL string) error {
+				p, err := catalog(baseURL).GetProduct(ctx, 7)
+				return expect(err == nil && *p == domain.CatalogProduct{ID: 7, Name: "Basic Tee", Price: 25.5, Stock: 10, IsActive: true},
+					"got %+v, %v", p, err)
+			},
+			"get a missing product": func(ctx context.Context, baseURL string) error {
+				_, err := catalog(baseURL).GetProduct(ctx, 99)
+				return expect(errors.Is(err, ErrProductNotFound), "got %v, want ErrProductNotFound", err)
+			},
+			"find a product by SKU": func(ctx context.Context, baseURL string) error {
+				p, err := catalog(baseURL).GetProductBySKU(ctx, "TEE-01")
+				return expect(err == nil && p.ID == 7, "got %+v, %v", p, err)
+			},
+			"find a product by an unknown SKU": func(ctx context.Context, baseURL string) error {
+				_, err := catalog(baseURL).GetProductBySKU(ctx, "NOPE-01")
+				return expect(errors.Is(err, ErrProductNotFound), "got %v, want ErrProductNotFound", err)
+			},
+			"reserve stock": func(ctx context.Context, baseURL string) error {
+				err := catalog(baseURL).ReserveStock(ctx, "order-1", items)
+				return expect(err == nil, "got %v", err)
+			},
+			"reserve more than the stock": func(ctx context.Context, baseURL string) error {
+				err := catalog(baseURL).ReserveStock(ctx, "order-1", items)
+				return expect(errors.Is(err, ErrReservationRejected) && strings.Contains(err.Error(), "insufficient stock"),
+					"got %v, want ErrReservationRejected with the catalog's reason", err)
+			},
+			"release a reservation": func(ctx context.Context, baseURL string) error {
+				err := catalog(baseURL).ReleaseStock(ctx, "order-1")
+				return expect(err == nil, "got %v", err)
+			},
+			"release an unknown reservation": func(ctx context.Context, baseURL string) error {
+				err := catalog(baseURL).ReleaseStock(ctx, "order-2")
+				return expect(err == nil, "got %v", err)
+			},
+		}),
+	)
+}
+
+func expect(ok bool, format string, args ...any) error {
+	if ok {
+		return nil
+	}
+	return fmt.Errorf(format, args...)
+}
@@ -0,0 +1,83 @@
+package client
+
+//go:generate mockgen -source=promotion_client.go -destination=mocks/promotion_client.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+// ErrPromotionRejected wraps the promotion service's reason for refusing to evaluate an order,
+// such as a product the catalog does not know
+var ErrPromotionRejected = errors.New("promotion evaluation rejected")
+
+type IPromotionClient interface {
+	// Evaluate applies the user's live promotions to the items and returns the discounts of
+	// every item, in the order of items
+	Evaluate(ctx context.Context, userID int, currency string, items []domain.PromotionItem) ([][]domain.PromotionDiscount, error)
+}
+
+// PromotionClient calls the promotion service internal API directly, bypassing the gateway
+type PromotionClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewPromotionClient(baseURL, internalToken string) IPromotionClient {
+	return &PromotionClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+func (c *PromotionClient) Evaluate(ctx context.Context, userID int, currency string, items []domain.PromotionItem) ([][]domain.PromotionDiscount, error) {
+	body, err := json.Marshal(map[string]any{"userId": userID, "currency": currency, "items": items})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/internal/promotions/evaluate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("promotion service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	switch {
+	case res.StatusCode == http.StatusBadRequest:
+		var payload struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&payload)
+		return nil, fmt.Errorf("%w: %s", ErrPromotionRejected, payload.Error)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("promotion service returned %d evaluating the order", res.StatusCode)
+	}
+	var evaluation struct {
+		Items []struct {
+			Discounts []domain.PromotionDiscount `json:"discounts"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&evaluation); err != nil {
+		return nil, err
+	}
+	if len(evaluation.Items) != len(items) {
+		return nil, fmt.Errorf("promotion service returned %d lines for %d items", len(evaluation.Items), len(items))
+	}
+	discounts := make([][]domain.PromotionDiscount, len(items))
+	for i, item := range evaluation.Items {
+		discounts[i] = item.Discounts
+	}
+	return discounts, nil
+}
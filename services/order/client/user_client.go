@@ -0,0 +1,88 @@
+package client
+
+//go:generate mockgen -source=user_client.go -destination=mocks/user_client.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+)
+
+// ErrUserNotFound is returned when no user has the email
+var ErrUserNotFound = errors.New("user not found")
+
+type IUserClient interface {
+	GetEmail(ctx context.Context, userID int) (string, error)
+	// FindUserID returns the ID of the user with the email
+	FindUserID(ctx context.Context, email string) (int, error)
+}
+
+// UserClient calls the user service internal API directly, bypassing the gateway. Requests
+// carry the tenant of their context, so users of other tenants are not found.
+type UserClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewUserClient(baseURL, internalToken string) IUserClient {
+	return &UserClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+func (c *UserClient) GetEmail(ctx context.Context, userID int) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/internal/users/"+strconv.Itoa(userID)+"/contact", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("user service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("user service returned %d for user %d", res.StatusCode, userID)
+	}
+	var contact struct {
+		Email string `json:"email"`
+	}
+	if err := controllers.DecodeData(res.Body, &contact); err != nil {
+		return "", err
+	}
+	return contact.Email, nil
+}
+
+func (c *UserClient) FindUserID(ctx context.Context, email string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/internal/users?email="+url.QueryEscape(email), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("user service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return 0, ErrUserNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("user service returned %d for user %s", res.StatusCode, email)
+	}
+	var contact struct {
+		ID int `json:"id"`
+	}
+	if err := controllers.DecodeData(res.Body, &contact); err != nil {
+		return 0, err
+	}
+	return contact.ID, nil
+}
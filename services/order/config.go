@@ -0,0 +1,129 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/idempotency"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+)
+
+type Config struct {
+	Server      config.Server
+	Telemetry   otel.Config
+	Log         logger.Config
+	CORS        *config.Live[config.CORS]
+	Security    config.SecurityHeaders
+	Database    config.Database
+	JWT         config.JWT
+	Features    featureflags.Config
+	Authz       authz.Config
+	Currency    currency.Config
+	IDs         config.IDs
+	Cache       cache.Config
+	RateLimit   *config.Live[config.RateLimit]
+	Idempotency idempotency.Config
+	Fixtures    fixtures.Config
+	Backup      backup.Config
+	Retention   retention.Config
+	GRPCPort    string
+
+	UserServiceURL      string
+	CatalogServiceURL   string
+	PromotionServiceURL string
+	InternalToken       string
+
+	Outbox events.OutboxRelayConfig
+	Risk   RiskConfig
+	Seller domain.SellerDetails
+	Jobs   JobsConfig
+
+	InvoiceTaxRate float64
+	// ReservationTTL is how long checkout holds stock; PaymentWindow how long an order may stay unpaid
+	ReservationTTL time.Duration
+	PaymentWindow  time.Duration
+}
+
+type RiskConfig struct {
+	ReviewScore          int
+	MaxOrdersPerUserHour int
+	MaxOrdersPerIPHour   int
+}
+
+// JobsConfig holds the intervals of the background jobs
+type JobsConfig struct {
+	AutoCancel       time.Duration
+	CarrierPoll      time.Duration
+	ReservationSweep time.Duration
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:      config.LoadServer(src, "8083", 15*time.Second),
+		CORS:        config.Watch(src, "CORS", config.LoadCORS),
+		Security:    config.LoadSecurityHeaders(src),
+		Database:    config.LoadDatabase(src),
+		JWT:         config.LoadJWT(src, "order", false),
+		Features:    featureflags.LoadConfig(src),
+		Authz:       authz.LoadConfig(src),
+		Currency:    currency.LoadConfig(src),
+		IDs:         config.LoadIDs(src),
+		Cache:       cache.LoadConfig(src),
+		RateLimit:   config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Idempotency: idempotency.LoadConfig(src),
+		Fixtures:    fixtures.LoadConfig(src),
+		Backup:      backup.LoadConfig(src),
+		Retention:   retention.LoadConfig(src, repository.RetentionRules),
+		GRPCPort:    src.String("GRPC_PORT", "9193"),
+
+		UserServiceURL:    src.String("USER_SERVICE_URL", "http://localhost:9091"),
+		CatalogServiceURL: src.String("CATALOG_SERVICE_URL", "http://localhost:9092"),
+		// promotions are applied at checkout only when the promotion service is configured
+		PromotionServiceURL: src.String("PROMOTION_SERVICE_URL", ""),
+		InternalToken:       src.String("INTERNAL_API_TOKEN", ""),
+
+		Outbox: events.OutboxRelayConfig{
+			Interval:  src.Duration("OUTBOX_RELAY_INTERVAL_MS", 2000, time.Millisecond),
+			BatchSize: src.Int("OUTBOX_RELAY_BATCH_SIZE", 100),
+		},
+		Risk: RiskConfig{
+			ReviewScore:          src.Int("RISK_REVIEW_SCORE", 50),
+			MaxOrdersPerUserHour: src.Int("RISK_MAX_ORDERS_PER_USER_HOUR", 5),
+			MaxOrdersPerIPHour:   src.Int("RISK_MAX_ORDERS_PER_IP_HOUR", 10),
+		},
+		Seller: domain.SellerDetails{
+			Name:    src.String("INVOICE_SELLER_NAME", "Ecommerce Store"),
+			Address: src.String("INVOICE_SELLER_ADDRESS", ""),
+			TaxID:   src.String("INVOICE_SELLER_TAX_ID", ""),
+			Email:   src.String("INVOICE_SELLER_EMAIL", ""),
+		},
+		Jobs: JobsConfig{
+			AutoCancel:       src.Duration("ORDER_AUTOCANCEL_INTERVAL_SECONDS", 60, time.Second),
+			CarrierPoll:      src.Duration("CARRIER_POLL_INTERVAL_SECONDS", 900, time.Second),
+			ReservationSweep: src.Duration("RESERVATION_SWEEP_INTERVAL_SECONDS", 60, time.Second),
+		},
+
+		InvoiceTaxRate: src.Float("INVOICE_TAX_RATE", 0.11),
+		ReservationTTL: src.Duration("CHECKOUT_RESERVATION_TTL_MINUTES", 15, time.Minute),
+		PaymentWindow:  src.Duration("ORDER_PAYMENT_TIMEOUT_MINUTES", 60, time.Minute),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "order", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
@@ -22,18 +22,68 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Staff see every order, archived ones included; customers only their own, without archived orders unless includeArchived is true",
                 "tags": [
                     "Order"
                 ],
                 "summary": "Get all orders",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include archived orders",
+                        "name": "includeArchived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, createdAt, totalAmount, status)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: items.product",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ResponseOrder"
-                            }
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -44,11 +94,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Retrying with the same Idempotency-Key returns the order created by the first request.\nstoreCreditAmount (order currency) is paid from store credit; the rest becomes a pending card payment.\nWith reservationId the items must match that reservation exactly; an expired reservation fails with 410.\nDiscounts (order currency) can be given per item or for the whole order by staff only and are deducted from the total.\nWhen the promotion service is configured it adds the discounts of the customer's promotions to the items; promotion discounts may not be sent.",
                 "tags": [
                     "Order"
                 ],
                 "summary": "Create order",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client-generated key, max 255 characters",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Order",
                         "name": "request",
@@ -63,173 +120,2294 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseOrder"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
                         }
                     }
                 }
             }
         },
-        "/order/{id}": {
+        "/order/carriers/{carrier}/webhook": {
+            "post": {
+                "description": "Receives tracking updates pushed by a carrier. Authenticated by the carrier's X-Carrier-Signature.",
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Carrier tracking webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Carrier name",
+                        "name": "carrier",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/order/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Streams orders as CSV (one row per item) or NDJSON (one order per line). Admin only.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
                 "tags": [
                     "Order"
                 ],
-                "summary": "Get order by ID",
+                "summary": "Export orders with items",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Created at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339, or YYYY-MM-DD inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseOrder"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/order/{id}/status": {
-            "put": {
+        "/order/metrics": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Totals and per-period series in the store's base currency. Revenue and average order value count paid, shipped and delivered orders. Admin only.",
                 "tags": [
                     "Order"
                 ],
-                "summary": "Update order status",
+                "summary": "Order and revenue metrics",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Created at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339, or YYYY-MM-DD inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day (default), week or month",
+                        "name": "groupBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrderMetrics"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/number/{orderNumber}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get order by order number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order number, e.g. ORD-2024-000123",
+                        "name": "orderNumber",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Status",
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: items.product",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/reservations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Holds the items in the catalog until expiresAt. Pass reservationId when creating the order; an expired reservation fails with 410 and the items must be reserved again.",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Reserve stock for checkout",
+                "parameters": [
+                    {
+                        "description": "Items",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateStatusRequest"
+                            "$ref": "#/definitions/handler.NewReservationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseOrder"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseReservation"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.NewOrderRequest": {
-            "type": "object",
-            "required": [
-                "items"
-            ],
-            "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.OrderItemRequest"
+        },
+        "/order/reservations/{reservationId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the reserved stock to the catalog when checkout is abandoned",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Release a checkout reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "reservationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "handler.OrderItemRequest": {
-            "type": "object",
-            "required": [
-                "price",
-                "productId",
-                "quantity"
-            ],
-            "properties": {
-                "price": {
-                    "type": "number"
-                },
-                "productId": {
-                    "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer"
+        "/order/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Orders the fraud and velocity checks flagged at checkout, oldest first. Needs the order review permission.",
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "List orders held for risk review",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseOrderRisk"
+                        }
+                    }
                 }
             }
         },
-        "handler.ResponseOrder": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.ResponseOrderItem"
+        "/order/risk/blocklist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "List blocklist entries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseBlocklistEntry"
+                        }
                     }
-                },
-                "status": {
-                    "type": "string"
-                },
-                "totalAmount": {
-                    "type": "number"
-                },
-                "updatedAt": {
-                    "type": "string"
-                },
-                "userId": {
-                    "type": "integer"
                 }
-            }
-        },
-        "handler.ResponseOrderItem": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "productId": {
-                    "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "subtotal": {
-                    "type": "number"
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Orders matching an entry are held for review. Adding an existing entry returns it unchanged.",
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Block an email, email domain or IP",
+                "parameters": [
+                    {
+                        "description": "Entry",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NewBlocklistEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseBlocklistEntry"
+                        }
+                    }
                 }
             }
         },
-        "handler.UpdateStatusRequest": {
-            "type": "object",
-            "required": [
-                "status"
-            ],
-            "properties": {
-                "status": {
-                    "type": "string"
+        "/order/risk/blocklist/{entryId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Remove a blocklist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/order/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Search orders by order number prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order number prefix",
+                        "name": "number",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: items.product",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseOrder"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Staff search every order, customers only their own, archived ones included.\nlikeFilters match orderNumber or cancellationReason; matches take exact status, currency or userId values;\ndateRanges bound createdAt, updatedAt or archivedAt. Values of one field are ORed, fields are ANDed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Search orders",
+                "parameters": [
+                    {
+                        "description": "Search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.DataFilters"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: items.product",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/store-credit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Balance and recent movements in the base currency. Staff may pass userId.",
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Get store credit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID (staff only)",
+                        "name": "userId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseStoreCredit"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/store-credit/adjustments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds (positive amount) or removes (negative amount) base-currency store credit. Admin only.",
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Adjust store credit",
+                "parameters": [
+                    {
+                        "description": "Adjustment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StoreCreditAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseStoreCredit"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: items.product",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Hides a delivered or cancelled order from the customer's default listing; staff still see it. Only the order's customer can archive it.",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Archive order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows the order in the customer's default listing again",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Restore archived order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Staff only; comments are never shown to customers",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "List internal order comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseComment"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Add internal order comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NewCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseComment"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/invoice": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues the invoice on first request and returns the stored HTML document afterwards",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get order invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML invoice",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "List order payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponsePayment"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/payments/capture": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms the pending card payment and marks the order paid. Staff only.",
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Capture card payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment reference",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CapturePaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key, max 255 characters",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/refunds/store-credit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Credits part of a paid order, in order currency, to the customer's store credit. Admin only.",
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Refund to store credit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StoreCreditRefundRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key, max 255 characters",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseStoreCredit"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/reorder": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rebuilds a checkout from the order at current catalog prices and stock. Items that can no longer be bought are flagged and left out of checkout, which can be posted to /order/ as is.",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Reorder a past order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseReorder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approved orders continue as pending, or paid when store credit covered them; rejected orders are cancelled and their store credit returned. Needs the order review permission.",
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Approve or reject an order held for review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReviewOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/risk": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The risk score and the signals that made it up. Needs the order review permission.",
+                "tags": [
+                    "Risk"
+                ],
+                "summary": "Get order risk assessment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrderRisk"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/shipment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Get order shipment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseShipment"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores carrier and tracking number and marks a paid order as shipped. Staff or fulfillment only.",
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Ship order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shipment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ShipOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseShipment"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Customers may only cancel their own pending orders. Paid needs staff; shipped and delivered need staff or fulfillment.",
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseOrder"
+                        }
+                    },
+                    "409": {
+                        "description": "The order changed since the version sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/{id}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get order status timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseTimelineEntry"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.Envelope-array_handler_ResponseBlocklistEntry": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseBlocklistEntry"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseComment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseComment"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseOrder": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseOrder"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseOrderRisk": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseOrderRisk"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponsePayment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponsePayment"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseTimelineEntry": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseTimelineEntry"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseBlocklistEntry": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseBlocklistEntry"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseComment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseComment"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseOrder": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseOrder"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseOrderMetrics": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseOrderMetrics"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseOrderRisk": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseOrderRisk"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseReorder": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseReorder"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseReservation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseReservation"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseShipment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseShipment"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseStoreCredit": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseStoreCredit"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.ErrorResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DataFilters": {
+            "type": "object",
+            "properties": {
+                "dateRanges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DateRangeFilter"
+                    }
+                },
+                "likeFilters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "matches": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "sortBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sortDirection": {
+                    "$ref": "#/definitions/domain.SortDirection"
+                }
+            }
+        },
+        "domain.DateRangeFilter": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SortDirection": {
+            "type": "string",
+            "enum": [
+                "asc",
+                "desc"
+            ],
+            "x-enum-varnames": [
+                "SortAsc",
+                "SortDesc"
+            ]
+        },
+        "errors.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "NOT_FOUND",
+                "VALIDATION_FAILED",
+                "ALREADY_EXISTS",
+                "NOT_AUTHENTICATED",
+                "NOT_AUTHORIZED",
+                "EXPIRED",
+                "PAYMENT_DECLINED",
+                "CSRF_TOKEN_INVALID",
+                "VERSION_CONFLICT",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "SERVICE_TIMEOUT",
+                "PAYLOAD_TOO_LARGE",
+                "RATE_LIMITED",
+                "REQUEST_IN_PROGRESS",
+                "IDEMPOTENCY_KEY_REUSED",
+                "USER_NOT_FOUND",
+                "EMAIL_CONFLICT",
+                "SKU_CONFLICT",
+                "INVALID_TRANSITION",
+                "REVIEW_EXISTS",
+                "PURCHASE_REQUIRED",
+                "TENANT_NOT_FOUND",
+                "TENANT_MISMATCH"
+            ],
+            "x-enum-varnames": [
+                "CodeNotFound",
+                "CodeValidation",
+                "CodeAlreadyExists",
+                "CodeNotAuthenticated",
+                "CodeNotAuthorized",
+                "CodeExpired",
+                "CodePaymentDeclined",
+                "CodeCSRFTokenInvalid",
+                "CodeVersionConflict",
+                "CodeInternal",
+                "CodeServiceUnavailable",
+                "CodeServiceTimeout",
+                "CodePayloadTooLarge",
+                "CodeRateLimited",
+                "CodeRequestInProgress",
+                "CodeIdempotencyKeyReused",
+                "CodeUserNotFound",
+                "CodeEmailConflict",
+                "CodeSKUConflict",
+                "CodeInvalidTransition",
+                "CodeReviewExists",
+                "CodePurchaseRequired",
+                "CodeTenantNotFound",
+                "CodeTenantMismatch"
+            ]
+        },
+        "errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/errors.ErrorCode"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.FieldError"
+                    }
+                },
+                "errorId": {
+                    "description": "ErrorID identifies a recovered panic in the logs, for users to quote when reporting it",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                }
+            }
+        },
+        "errors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.Address": {
+            "type": "object",
+            "required": [
+                "city",
+                "country",
+                "line1"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "country": {
+                    "type": "string"
+                },
+                "line1": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "line2": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "postalCode": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "handler.CapturePaymentRequest": {
+            "type": "object",
+            "properties": {
+                "reference": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handler.DiscountRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "source"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "coupon",
+                        "manual"
+                    ]
+                }
+            }
+        },
+        "handler.NewBlocklistEntryRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "value"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "email_domain",
+                        "ip"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "value": {
+                    "type": "string",
+                    "maxLength": 320
+                }
+            }
+        },
+        "handler.NewCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "handler.NewOrderRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "billingAddress": {
+                    "$ref": "#/definitions/handler.Address"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deliveryInstructions": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.DiscountRequest"
+                    }
+                },
+                "giftMessage": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderItemRequest"
+                    }
+                },
+                "reservationId": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "shippingAddress": {
+                    "$ref": "#/definitions/handler.Address"
+                },
+                "storeCreditAmount": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "handler.NewReservationRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.ReservationItemRequest"
+                    }
+                }
+            }
+        },
+        "handler.OrderItemRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "productId",
+                "quantity"
+            ],
+            "properties": {
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.DiscountRequest"
+                    }
+                },
+                "price": {
+                    "type": "number"
+                },
+                "productId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ReservationItemRequest": {
+            "type": "object",
+            "required": [
+                "productId",
+                "quantity"
+            ],
+            "properties": {
+                "productId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseBlocklistEntry": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseComment": {
+            "type": "object",
+            "properties": {
+                "authorId": {
+                    "type": "integer"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseItemProduct": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseMetricsPeriod": {
+            "type": "object",
+            "properties": {
+                "averageOrderValue": {
+                    "type": "number"
+                },
+                "cancellationReasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "cancelledBy": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "statusBreakdown": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "totalOrders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseOrder": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "archivedAt": {
+                    "type": "string"
+                },
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "baseTotalAmount": {
+                    "type": "number"
+                },
+                "billingAddress": {
+                    "$ref": "#/definitions/handler.Address"
+                },
+                "cancellationReason": {
+                    "type": "string"
+                },
+                "cancelledBy": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deliveryInstructions": {
+                    "type": "string"
+                },
+                "discountAmount": {
+                    "type": "number"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseDiscount"
+                    }
+                },
+                "exchangeRate": {
+                    "type": "number"
+                },
+                "giftMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseOrderItem"
+                    }
+                },
+                "orderNumber": {
+                    "type": "string"
+                },
+                "shippingAddress": {
+                    "$ref": "#/definitions/handler.Address"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotalAmount": {
+                    "type": "number"
+                },
+                "totalAmount": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "uuid": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseOrderItem": {
+            "type": "object",
+            "properties": {
+                "discountAmount": {
+                    "type": "number"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseDiscount"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "productId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "product": {
+                    "description": "Product is the catalog's current entry for the product, inlined with ?expand=items.product",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ResponseItemProduct"
+                        }
+                    ]
+                }
+            }
+        },
+        "handler.ResponseOrderMetrics": {
+            "type": "object",
+            "properties": {
+                "averageOrderValue": {
+                    "type": "number"
+                },
+                "cancellationReasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "cancelledBy": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "groupBy": {
+                    "type": "string"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseMetricsPeriod"
+                    }
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "statusBreakdown": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "totalOrders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseOrderRisk": {
+            "type": "object",
+            "properties": {
+                "clientIp": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "riskScore": {
+                    "type": "integer"
+                },
+                "signals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseRiskSignal"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponsePayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "baseAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseReorder": {
+            "type": "object",
+            "properties": {
+                "checkout": {
+                    "$ref": "#/definitions/handler.NewOrderRequest"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseReorderItem"
+                    }
+                },
+                "sourceOrderId": {
+                    "type": "integer"
+                },
+                "sourceOrderNumber": {
+                    "type": "string"
+                },
+                "totalAmount": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.ResponseReorderItem": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "previousPrice": {
+                    "type": "number"
+                },
+                "previousQuantity": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "productId": {
+                    "type": "integer"
+                },
+                "purchasable": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseReservation": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseReservationItem"
+                    }
+                },
+                "reservationId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseReservationItem": {
+            "type": "object",
+            "properties": {
+                "productId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseRiskSignal": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseShipment": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastEvent": {
+                    "type": "string"
+                },
+                "lastEventAt": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "trackingNumber": {
+                    "type": "string"
+                },
+                "trackingUrl": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseStoreCredit": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseStoreCreditEntry"
+                    }
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ResponseStoreCreditEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResponseTimelineEntry": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "actorType": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "newStatus": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "oldStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ReviewOrderRequest": {
+            "type": "object",
+            "required": [
+                "approve"
+            ],
+            "properties": {
+                "approve": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "handler.ShipOrderRequest": {
+            "type": "object",
+            "required": [
+                "carrier",
+                "trackingNumber"
+            ],
+            "properties": {
+                "carrier": {
+                    "type": "string"
+                },
+                "trackingNumber": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handler.StoreCreditAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "userId"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.StoreCreditRefundRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "handler.UpdateStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason applies to cancellations; customers default to changed_mind, staff to other",
+                    "type": "string",
+                    "enum": [
+                        "changed_mind",
+                        "out_of_stock",
+                        "fraud",
+                        "payment_failed",
+                        "other"
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the order version the client read; when set the change fails with 409 if the order moved on",
+                    "type": "integer",
+                    "minimum": 1
                 }
             }
         }
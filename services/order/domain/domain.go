@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/security"
+)
 
 type OrderStatus string
 
@@ -10,23 +15,527 @@ const (
 	OrderStatusShipped   OrderStatus = "shipped"
 	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusReview holds an order flagged by the risk checks until staff approve or reject it
+	OrderStatusReview OrderStatus = "review"
 )
 
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled, OrderStatusReview:
+		return true
+	}
+	return false
+}
+
 type Order struct {
 	ID          int
+	UUID        string
+	Number      string
 	UserID      int
+	Tenant      string
 	Status      OrderStatus
 	TotalAmount float64
-	Items       []OrderItem
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// Currency is what the customer pays in. ExchangeRate converts it into BaseCurrency
+	// and is snapshotted when the order is placed.
+	Currency        string
+	BaseCurrency    string
+	ExchangeRate    float64
+	BaseTotalAmount float64
+	// SubtotalAmount is the sum of item subtotals before discounts; TotalAmount is what is charged
+	SubtotalAmount float64
+	DiscountAmount float64
+	// Discounts holds order-level discounts; item-level ones are on their item
+	Discounts []OrderDiscount
+	Items     []OrderItem
+	Notes     OrderNotes
+	// Addresses are optional; ClientIP is where the order was placed from
+	ShippingAddress *Address
+	BillingAddress  *Address
+	ClientIP        string
+	// RiskScore and RiskSignals are set by the risk checks at checkout and shown to staff only
+	RiskScore   int
+	RiskSignals []RiskSignal
+	// CancellationReason and CancelledBy (an actor type) are set when the order is cancelled
+	CancellationReason CancellationReason
+	CancelledBy        string
+	// ArchivedAt is set when the customer hides the order from their default listing
+	ArchivedAt *time.Time
+	// Version is bumped by every status change
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
-type OrderItem struct {
+// IsFinal reports statuses an order never leaves
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusDelivered || s == OrderStatusCancelled
+}
+
+type CancellationReason string
+
+const (
+	CancellationChangedMind   CancellationReason = "changed_mind"
+	CancellationOutOfStock    CancellationReason = "out_of_stock"
+	CancellationFraud         CancellationReason = "fraud"
+	CancellationPaymentFailed CancellationReason = "payment_failed"
+	// CancellationAccountDeleted is set by the saga orchestrator when the customer's account is deleted
+	CancellationAccountDeleted CancellationReason = "account_deleted"
+	// CancellationOther covers staff cancellations given without a reason
+	CancellationOther CancellationReason = "other"
+)
+
+func (r CancellationReason) IsValid() bool {
+	switch r {
+	case CancellationChangedMind, CancellationOutOfStock, CancellationFraud, CancellationPaymentFailed, CancellationAccountDeleted, CancellationOther:
+		return true
+	}
+	return false
+}
+
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+// OrderNotes are provided by the customer at checkout and visible to them
+type OrderNotes struct {
+	GiftMessage          string
+	DeliveryInstructions string
+}
+
+// OrderComment is an internal staff comment, never shown to customers
+type OrderComment struct {
+	ID        int
+	OrderID   int
+	AuthorID  int
+	Body      string
+	CreatedAt time.Time
+}
+
+// Requester is the authenticated caller; staff can act on every user's orders
+type Requester struct {
+	UserID   int
+	Role     string
+	IsStaff  bool
+	IsAdmin  bool
+	IsSystem bool
+}
+
+// SystemRequester acts for background jobs and carrier callbacks
+var SystemRequester = Requester{IsSystem: true}
+
+// Can reports whether the requester's role grants the permission; the system can do anything
+func (r Requester) Can(p security.Permission) bool {
+	return r.IsSystem || security.HasPermission(r.Role, p)
+}
+
+// Subject is the requester as the authorization policies see it
+func (r Requester) Subject() authz.Subject {
+	if r.IsSystem {
+		return authz.Subject{}
+	}
+	return authz.Subject{ID: r.UserID, Roles: []string{r.Role}}
+}
+
+// AuthzResource names orders in the authorization policies
+const AuthzResource = "order"
+
+// AuthzObject is the order as the authorization policies see it, owned by its customer
+func (o *Order) AuthzObject() authz.Object {
+	return authz.Object{Resource: AuthzResource, OwnerID: o.UserID}
+}
+
+const (
+	ActorCustomer = "customer"
+	ActorStaff    = "staff"
+	ActorSystem   = "system"
+)
+
+func (r Requester) ActorType() string {
+	if r.IsSystem {
+		return ActorSystem
+	}
+	if r.IsStaff || r.Can(security.PermOrderReadAny) {
+		return ActorStaff
+	}
+	return ActorCustomer
+}
+
+// OrderExportFilter narrows an export to orders created in [From, To) with the given status.
+// Zero values leave the corresponding bound open.
+type OrderExportFilter struct {
+	From   time.Time
+	To     time.Time
+	Status OrderStatus
+}
+
+type MetricsGranularity string
+
+const (
+	MetricsByDay   MetricsGranularity = "day"
+	MetricsByWeek  MetricsGranularity = "week"
+	MetricsByMonth MetricsGranularity = "month"
+)
+
+func (g MetricsGranularity) IsValid() bool {
+	return g == MetricsByDay || g == MetricsByWeek || g == MetricsByMonth
+}
+
+type OrderMetricsFilter struct {
+	From    time.Time
+	To      time.Time
+	GroupBy MetricsGranularity
+}
+
+// OrderMetricsRow is one aggregate row: orders of one status within one period. Cancelled
+// orders are further split by reason and actor.
+type OrderMetricsRow struct {
+	PeriodStart        time.Time
+	Status             OrderStatus
+	CancellationReason CancellationReason
+	CancelledBy        string
+	Orders             int64
+	Amount             float64
+}
+
+// OrderMetricsSummary holds the figures reported for the whole range and for each period.
+// Revenue counts only paid, shipped and delivered orders.
+type OrderMetricsSummary struct {
+	TotalOrders       int64
+	Revenue           float64
+	AverageOrderValue float64
+	StatusBreakdown   map[OrderStatus]int64
+	// cancelled orders counted by reason and by the actor type that cancelled them
+	CancellationReasons map[CancellationReason]int64
+	CancelledBy         map[string]int64
+}
+
+type OrderMetricsPeriod struct {
+	PeriodStart time.Time
+	OrderMetricsSummary
+}
+
+// OrderMetrics amounts are in Currency, the store's base currency
+type OrderMetrics struct {
+	GroupBy  MetricsGranularity
+	Currency string
+	OrderMetricsSummary
+	Periods []OrderMetricsPeriod
+}
+
+// CountsAsRevenue reports whether an order in this status has been paid for
+func (s OrderStatus) CountsAsRevenue() bool {
+	return s == OrderStatusPaid || s == OrderStatusShipped || s == OrderStatusDelivered
+}
+
+// Purchase is the most recent paid order in which a user bought a product; the review
+// service uses it to mark reviews as verified purchases
+type Purchase struct {
+	OrderID     int
+	OrderNumber string
+	Status      OrderStatus
+	PurchasedAt time.Time
+}
+
+// OrderStatusEvent is one entry of the order timeline
+type OrderStatusEvent struct {
 	ID        int
 	OrderID   int
-	ProductID int
-	Quantity  int
-	Price     float64
-	Subtotal  float64
+	OldStatus OrderStatus
+	NewStatus OrderStatus
+	ActorID   int
+	ActorType string
+	Note      string
+	CreatedAt time.Time
+}
+
+type OrderItem struct {
+	ID             int
+	OrderID        int
+	ProductID      int
+	Quantity       int
+	Price          float64
+	Subtotal       float64
+	DiscountAmount float64
+	Discounts      []OrderDiscount
+}
+
+type DiscountSource string
+
+const (
+	DiscountSourceCoupon    DiscountSource = "coupon"
+	DiscountSourcePromotion DiscountSource = "promotion"
+	DiscountSourceManual    DiscountSource = "manual"
+)
+
+func (s DiscountSource) IsValid() bool {
+	switch s {
+	case DiscountSourceCoupon, DiscountSourcePromotion, DiscountSourceManual:
+		return true
+	}
+	return false
+}
+
+// OrderDiscount is a positive amount in order currency taken off the order or, when
+// OrderItemID is set, off that item. Discounts are kept as records so invoices and
+// refunds can show where a reduction came from.
+type OrderDiscount struct {
+	ID          int
+	OrderID     int
+	OrderItemID *int
+	Source      DiscountSource
+	Code        string
+	Description string
+	Amount      float64
+	ActorID     int
+	CreatedAt   time.Time
+}
+
+type ShipmentStatus string
+
+const (
+	ShipmentStatusLabelCreated   ShipmentStatus = "label_created"
+	ShipmentStatusInTransit      ShipmentStatus = "in_transit"
+	ShipmentStatusOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentStatusDelivered      ShipmentStatus = "delivered"
+	ShipmentStatusException      ShipmentStatus = "exception"
+)
+
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusLabelCreated, ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusException:
+		return true
+	}
+	return false
+}
+
+// Shipment is the carrier parcel for an order
+type Shipment struct {
+	ID             int
+	OrderID        int
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    string
+	Status         ShipmentStatus
+	LastEvent      string
+	LastEventAt    *time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TrackingUpdate is one status report pushed or polled from a carrier
+type TrackingUpdate struct {
+	TrackingNumber string
+	Status         ShipmentStatus
+	Description    string
+	OccurredAt     time.Time
+}
+
+type SellerDetails struct {
+	Name    string
+	Address string
+	TaxID   string
+	Email   string
+}
+
+// Invoice is issued once per order; prices are tax inclusive so Total equals the order total.
+type Invoice struct {
+	ID          int
+	OrderID     int
+	Number      string
+	NetAmount   float64
+	TaxRate     float64
+	TaxAmount   float64
+	Total       float64
+	ContentType string
+	Content     []byte
+	IssuedAt    time.Time
+}
+
+// CheckoutOptions are the per-request choices made when placing an order
+type CheckoutOptions struct {
+	IdempotencyKey string
+	// StoreCreditAmount is the part of the total, in order currency, paid from store credit
+	StoreCreditAmount float64
+	// ReservationID, when set, must name an unexpired reservation holding exactly the order items
+	ReservationID string
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodStoreCredit PaymentMethod = "store_credit"
+	PaymentMethodCard        PaymentMethod = "card"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending  PaymentStatus = "pending"
+	PaymentStatusCaptured PaymentStatus = "captured"
+	PaymentStatusReleased PaymentStatus = "released"
+)
+
+// Payment is one tender used for an order; split payments have several.
+// Amount is in the order currency, BaseAmount in the base currency.
+type Payment struct {
+	ID         int
+	OrderID    int
+	Method     PaymentMethod
+	Status     PaymentStatus
+	Amount     float64
+	BaseAmount float64
+	Reference  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+const (
+	StoreCreditReasonAdjustment = "adjustment"
+	StoreCreditReasonCheckout   = "checkout"
+	StoreCreditReasonRefund     = "refund"
+	StoreCreditReasonReleased   = "order_cancelled"
+)
+
+// StoreCreditEntry is one ledger movement; Amount is in the base currency and negative for spending
+type StoreCreditEntry struct {
+	ID        int
+	UserID    int
+	Amount    float64
+	Reason    string
+	Note      string
+	OrderID   *int
+	ActorID   int
+	CreatedAt time.Time
+}
+
+type StoreCreditAccount struct {
+	UserID   int
+	Balance  float64
+	Currency string
+	Entries  []StoreCreditEntry
+}
+
+// CatalogProduct is the catalog's current view of a product; Price is in the base currency
+type CatalogProduct struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	IsActive bool    `json:"isActive"`
+}
+
+// ReorderIssue explains why a reordered item differs from the original order line
+type ReorderIssue string
+
+const (
+	ReorderIssueNotFound          ReorderIssue = "not_found"
+	ReorderIssueInactive          ReorderIssue = "inactive"
+	ReorderIssueOutOfStock        ReorderIssue = "out_of_stock"
+	ReorderIssueInsufficientStock ReorderIssue = "insufficient_stock"
+	ReorderIssuePriceChanged      ReorderIssue = "price_changed"
+)
+
+// ReorderItem is one line of a past order checked against the current catalog.
+// Quantity may be lower than PreviousQuantity when stock is short.
+type ReorderItem struct {
+	ProductID        int
+	Name             string
+	PreviousQuantity int
+	Quantity         int
+	PreviousPrice    float64
+	Price            float64
+	Purchasable      bool
+	Issues           []ReorderIssue
+}
+
+// Reorder is a checkout proposal rebuilt from a past order at current prices
+type Reorder struct {
+	SourceOrderID     int
+	SourceOrderNumber string
+	Currency          string
+	Items             []ReorderItem
+	TotalAmount       float64
+}
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive   ReservationStatus = "active"
+	ReservationStatusConsumed ReservationStatus = "consumed"
+	ReservationStatusReleased ReservationStatus = "released"
+	ReservationStatusExpired  ReservationStatus = "expired"
+)
+
+type ReservationItem struct {
+	ProductID int `json:"productId"`
+	Quantity  int `json:"quantity"`
+}
+
+// PromotionItem is an order line the promotion service evaluates, priced in the order currency
+type PromotionItem struct {
+	ProductID int     `json:"productId"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// PromotionDiscount is what one promotion took off one order line
+type PromotionDiscount struct {
+	PromotionID string  `json:"promotionId"`
+	Name        string  `json:"name"`
+	Amount      float64 `json:"amount"`
+}
+
+// Reservation holds catalog stock for a checkout until ExpiresAt. Reference is the
+// client-facing ID and also names the reservation in the catalog.
+type Reservation struct {
+	ID        int
+	Reference string
+	UserID    int
+	Items     []ReservationItem
+	Status    ReservationStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RiskSignal is one finding of a risk check; the scores of an order's signals add up to its risk score
+type RiskSignal struct {
+	Check  string `json:"check"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+type RiskAssessment struct {
+	Score   int
+	Signals []RiskSignal
+	// Review is set when the score reaches the review threshold
+	Review bool
+}
+
+type BlocklistKind string
+
+const (
+	BlocklistEmail BlocklistKind = "email"
+	// BlocklistEmailDomain entries block every address at the domain
+	BlocklistEmailDomain BlocklistKind = "email_domain"
+	BlocklistIP          BlocklistKind = "ip"
+)
+
+func (k BlocklistKind) IsValid() bool {
+	return k == BlocklistEmail || k == BlocklistEmailDomain || k == BlocklistIP
+}
+
+type BlocklistEntry struct {
+	ID        int
+	Kind      BlocklistKind
+	Value     string
+	Reason    string
+	ActorID   int
+	CreatedAt time.Time
 }
@@ -0,0 +1,97 @@
+package grpcserver
+
+import (
+	"context"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	orderv1 "ecommerce-microservice-go/pkg/proto/order/v1"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/usecase"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements orderv1.OrderServiceServer for trusted internal callers. Calls forwarded
+// for a user, such as those the gateway transcodes, are held to what that user may see and do
+// over REST; the others act with system privileges. Both stay within the tenant of the call if
+// any.
+type Server struct {
+	orderv1.UnimplementedOrderServiceServer
+	orderUC usecase.IOrderUseCase
+	Logger  *logger.Logger
+}
+
+func NewServer(orderUC usecase.IOrderUseCase, l *logger.Logger) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(middleware.InternalTokenUnaryInterceptor(), middleware.TenantUnaryInterceptor(),
+		middleware.IdentityUnaryInterceptor()))
+	orderv1.RegisterOrderServiceServer(s, &Server{orderUC: orderUC, Logger: l})
+	return s
+}
+
+func (s *Server) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.Order, error) {
+	o, err := s.orderUC.GetByID(ctx, int(req.GetId()), requester(ctx))
+	if err != nil {
+		return nil, domainErrors.AppErrorToGRPC(err)
+	}
+	return orderToProto(o), nil
+}
+
+func (s *Server) GetOrdersByUser(ctx context.Context, req *orderv1.GetOrdersByUserRequest) (*orderv1.GetOrdersByUserResponse, error) {
+	// customers only list their own orders, as GET /v1/order does
+	if r := requester(ctx); r.ActorType() == domain.ActorCustomer && int64(r.UserID) != req.GetUserId() {
+		return nil, status.Error(codes.PermissionDenied, "orders belong to another user")
+	}
+	orders, err := s.orderUC.GetByUserID(ctx, int(req.GetUserId()))
+	if err != nil {
+		return nil, domainErrors.AppErrorToGRPC(err)
+	}
+	res := &orderv1.GetOrdersByUserResponse{Orders: make([]*orderv1.Order, len(*orders))}
+	for i := range *orders {
+		res.Orders[i] = orderToProto(&(*orders)[i])
+	}
+	return res, nil
+}
+
+func (s *Server) UpdateStatus(ctx context.Context, req *orderv1.UpdateStatusRequest) (*orderv1.Order, error) {
+	if !domain.OrderStatus(req.GetStatus()).IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "invalid status")
+	}
+	s.Logger.Info("Updating order status over gRPC", zap.Int64("id", req.GetId()), zap.String("status", req.GetStatus()))
+	o, err := s.orderUC.UpdateStatus(ctx, int(req.GetId()), 0, req.GetStatus(), req.GetNote(), "", requester(ctx))
+	if err != nil {
+		return nil, domainErrors.AppErrorToGRPC(err)
+	}
+	return orderToProto(o), nil
+}
+
+// requester is the user the call is made for, or the system
+func requester(ctx context.Context) domain.Requester {
+	identity, ok := middleware.IdentityFromGRPC(ctx)
+	if !ok {
+		return domain.SystemRequester
+	}
+	role := identity.Roles[0]
+	return domain.Requester{UserID: identity.UserID, Role: role, IsStaff: security.IsStaffRole(role), IsAdmin: role == security.RoleAdmin}
+}
+
+func orderToProto(o *domain.Order) *orderv1.Order {
+	items := make([]*orderv1.OrderItem, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = &orderv1.OrderItem{
+			Id: int64(it.ID), ProductId: int64(it.ProductID), Quantity: int32(it.Quantity), Price: it.Price, Subtotal: it.Subtotal,
+		}
+	}
+	return &orderv1.Order{
+		Id: int64(o.ID), OrderNumber: o.Number, UserId: int64(o.UserID), Status: string(o.Status),
+		TotalAmount: o.TotalAmount, Currency: o.Currency, BaseCurrency: o.BaseCurrency,
+		ExchangeRate: o.ExchangeRate, BaseTotalAmount: o.BaseTotalAmount, Items: items,
+		CreatedAt: timestamppb.New(o.CreatedAt), UpdatedAt: timestamppb.New(o.UpdatedAt),
+	}
+}
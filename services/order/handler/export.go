@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+var csvExportHeader = []string{
+	"order_id", "order_number", "user_id", "status", "total_amount", "currency",
+	"exchange_rate", "base_total_amount", "base_currency", "cancellation_reason", "cancelled_by", "created_at",
+	"item_id", "product_id", "quantity", "price", "subtotal",
+}
+
+type orderExportWriter interface {
+	WriteBatch(orders *[]domain.Order) error
+	Flush() error
+}
+
+// ExportOrders godoc
+// @Summary      Export orders with items
+// @Description  Streams orders as CSV (one row per item) or NDJSON (one order per line). Admin only.
+// @Tags         Order
+// @Security     BearerAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        to query string false "Created before (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param        status query string false "Order status"
+// @Param        format query string false "csv (default) or ndjson"
+// @Success      200 {string} string
+// @Router       /order/export [get]
+func (h *Handler) ExportOrders(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", exportFormatCSV)
+	if format != exportFormatCSV && format != exportFormatNDJSON {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("format must be csv or ndjson"), domainErrors.ValidationError))
+		return
+	}
+	filter, err := exportFilterFromQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	// headers are sent with the first batch so that authorization and query
+	// errors still reach the client as a normal error response
+	var w orderExportWriter
+	start := func() error {
+		if w != nil {
+			return nil
+		}
+		var err error
+		w, err = h.startExport(ctx, format)
+		return err
+	}
+	err = h.orderUC.Export(ctx.Request.Context(), filter, requester, func(batch *[]domain.Order) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := w.WriteBatch(batch); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if w == nil {
+			_ = ctx.Error(err)
+			return
+		}
+		h.Logger.Error("Order export aborted", zap.Error(err))
+		return
+	}
+	if err := start(); err != nil {
+		h.Logger.Error("Error writing order export", zap.Error(err))
+		return
+	}
+	if err := w.Flush(); err != nil {
+		h.Logger.Error("Error writing order export", zap.Error(err))
+	}
+}
+
+func (h *Handler) startExport(ctx *gin.Context, format string) (orderExportWriter, error) {
+	filename := fmt.Sprintf("orders-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == exportFormatNDJSON {
+		ctx.Header("Content-Type", "application/x-ndjson")
+		ctx.Status(http.StatusOK)
+		return &ndjsonExportWriter{enc: json.NewEncoder(ctx.Writer)}, nil
+	}
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	cw := csv.NewWriter(ctx.Writer)
+	if err := cw.Write(csvExportHeader); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw}, nil
+}
+
+func exportFilterFromQuery(ctx *gin.Context) (domain.OrderExportFilter, error) {
+	var filter domain.OrderExportFilter
+	var err error
+	if filter.From, filter.To, err = dateRangeFromQuery(ctx); err != nil {
+		return filter, err
+	}
+	if v := ctx.Query("status"); v != "" {
+		status := domain.OrderStatus(v)
+		if !status.IsValid() {
+			return filter, errors.New("invalid status")
+		}
+		filter.Status = status
+	}
+	return filter, nil
+}
+
+// dateRangeFromQuery reads the optional from/to query parameters. A date-only "to" is
+// inclusive, so it is moved to the start of the following day.
+func dateRangeFromQuery(ctx *gin.Context) (from, to time.Time, err error) {
+	if v := ctx.Query("from"); v != "" {
+		if from, _, err = parseQueryTime(v); err != nil {
+			return from, to, errors.New("from must be RFC3339 or YYYY-MM-DD")
+		}
+	}
+	if v := ctx.Query("to"); v != "" {
+		var dateOnly bool
+		if to, dateOnly, err = parseQueryTime(v); err != nil {
+			return from, to, errors.New("to must be RFC3339 or YYYY-MM-DD")
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseQueryTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	return t, true, err
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) WriteBatch(orders *[]domain.Order) error {
+	for _, o := range *orders {
+		base := []string{
+			strconv.Itoa(o.ID), o.Number, strconv.Itoa(o.UserID), string(o.Status),
+			strconv.FormatFloat(o.TotalAmount, 'f', 2, 64), o.Currency, strconv.FormatFloat(o.ExchangeRate, 'f', -1, 64),
+			strconv.FormatFloat(o.BaseTotalAmount, 'f', 2, 64), o.BaseCurrency, string(o.CancellationReason), o.CancelledBy,
+			o.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if len(o.Items) == 0 {
+			if err := c.w.Write(append(base, "", "", "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, it := range o.Items {
+			row := append(append([]string{}, base...),
+				strconv.Itoa(it.ID), strconv.Itoa(it.ProductID), strconv.Itoa(it.Quantity),
+				strconv.FormatFloat(it.Price, 'f', 2, 64), strconv.FormatFloat(it.Subtotal, 'f', 2, 64),
+			)
+			if err := c.w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	return c.Flush()
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonExportWriter) WriteBatch(orders *[]domain.Order) error {
+	for i := range *orders {
+		if err := n.enc.Encode(orderToResponse(&(*orders)[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *ndjsonExportWriter) Flush() error { return nil }
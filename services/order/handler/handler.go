@@ -4,163 +4,731 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"ecommerce-microservice-go/pkg/config"
 	"ecommerce-microservice-go/pkg/controllers"
+	filters "ecommerce-microservice-go/pkg/domain"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/carrier"
 	"ecommerce-microservice-go/services/order/domain"
 	"ecommerce-microservice-go/services/order/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
+type DiscountRequest struct {
+	Source      string  `json:"source" binding:"required,oneof=coupon manual"`
+	Code        string  `json:"code" binding:"max=64"`
+	Description string  `json:"description" binding:"max=255"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+}
+
 type OrderItemRequest struct {
-	ProductID int     `json:"productId" binding:"required"`
-	Quantity  int     `json:"quantity" binding:"required"`
-	Price     float64 `json:"price" binding:"required"`
+	ProductID int               `json:"productId" binding:"required"`
+	Quantity  int               `json:"quantity" binding:"required"`
+	Price     float64           `json:"price" binding:"required"`
+	Discounts []DiscountRequest `json:"discounts,omitempty"`
+}
+
+type Address struct {
+	Name       string `json:"name" binding:"max=200"`
+	Line1      string `json:"line1" binding:"required,max=200"`
+	Line2      string `json:"line2,omitempty" binding:"max=200"`
+	City       string `json:"city" binding:"required,max=100"`
+	PostalCode string `json:"postalCode" binding:"max=20"`
+	Country    string `json:"country" binding:"required,len=2"`
 }
 
 type NewOrderRequest struct {
-	Items []OrderItemRequest `json:"items" binding:"required"`
+	Items                []OrderItemRequest `json:"items" binding:"required"`
+	Discounts            []DiscountRequest  `json:"discounts,omitempty" binding:"omitempty,dive"`
+	Currency             string             `json:"currency" binding:"omitempty,currency"`
+	StoreCreditAmount    float64            `json:"storeCreditAmount" binding:"gte=0"`
+	ReservationID        string             `json:"reservationId" binding:"max=64"`
+	GiftMessage          string             `json:"giftMessage" binding:"max=500"`
+	DeliveryInstructions string             `json:"deliveryInstructions" binding:"max=500"`
+	ShippingAddress      *Address           `json:"shippingAddress,omitempty"`
+	BillingAddress       *Address           `json:"billingAddress,omitempty"`
+}
+
+type NewCommentRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+type ResponseComment struct {
+	ID        int       `json:"id"`
+	AuthorID  int       `json:"authorId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type UpdateStatusRequest struct {
 	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+	// Reason applies to cancellations; customers default to changed_mind, staff to other
+	Reason string `json:"reason" binding:"omitempty,oneof=changed_mind out_of_stock fraud payment_failed other"`
+	// Version is the order version the client read; when set the change fails with 409 if the order moved on
+	Version int `json:"version" binding:"omitempty,min=1"`
+}
+
+type ResponseTimelineEntry struct {
+	ID        int       `json:"id"`
+	OldStatus string    `json:"oldStatus"`
+	NewStatus string    `json:"newStatus"`
+	ActorID   int       `json:"actorId"`
+	ActorType string    `json:"actorType"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ResponseDiscount struct {
+	ID          int     `json:"id"`
+	Source      string  `json:"source"`
+	Code        string  `json:"code,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Amount      float64 `json:"amount"`
 }
 
 type ResponseOrderItem struct {
-	ID        int     `json:"id"`
-	ProductID int     `json:"productId"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-	Subtotal  float64 `json:"subtotal"`
+	ID             int                `json:"id"`
+	ProductID      int                `json:"productId"`
+	Quantity       int                `json:"quantity"`
+	Price          float64            `json:"price"`
+	Subtotal       float64            `json:"subtotal"`
+	DiscountAmount float64            `json:"discountAmount"`
+	Discounts      []ResponseDiscount `json:"discounts,omitempty"`
+	// Product is the catalog's current entry for the product, inlined with ?expand=items.product
+	Product *ResponseItemProduct `json:"product,omitempty"`
+}
+
+// ResponseItemProduct is a product as the catalog lists it now; its price is in the base
+// currency and may differ from the price the item was ordered at
+type ResponseItemProduct struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	IsActive bool    `json:"isActive"`
 }
 
 type ResponseOrder struct {
-	ID          int                 `json:"id"`
-	UserID      int                 `json:"userId"`
-	Status      string              `json:"status"`
-	TotalAmount float64             `json:"totalAmount"`
-	Items       []ResponseOrderItem `json:"items"`
-	CreatedAt   time.Time           `json:"createdAt,omitempty"`
-	UpdatedAt   time.Time           `json:"updatedAt,omitempty"`
+	ID                 int                 `json:"id"`
+	UUID               string              `json:"uuid"`
+	Number             string              `json:"orderNumber"`
+	UserID             int                 `json:"userId"`
+	Status             string              `json:"status"`
+	SubtotalAmount     float64             `json:"subtotalAmount"`
+	DiscountAmount     float64             `json:"discountAmount"`
+	Discounts          []ResponseDiscount  `json:"discounts,omitempty"`
+	TotalAmount        float64             `json:"totalAmount"`
+	Currency           string              `json:"currency"`
+	BaseCurrency       string              `json:"baseCurrency"`
+	ExchangeRate       float64             `json:"exchangeRate"`
+	BaseTotalAmount    float64             `json:"baseTotalAmount"`
+	Items              []ResponseOrderItem `json:"items"`
+	GiftMessage        string              `json:"giftMessage,omitempty"`
+	DeliveryIns        string              `json:"deliveryInstructions,omitempty"`
+	ShippingAddress    *Address            `json:"shippingAddress,omitempty"`
+	BillingAddress     *Address            `json:"billingAddress,omitempty"`
+	CancellationReason string              `json:"cancellationReason,omitempty"`
+	CancelledBy        string              `json:"cancelledBy,omitempty"`
+	Archived           bool                `json:"archived"`
+	ArchivedAt         *time.Time          `json:"archivedAt,omitempty"`
+	Version            int                 `json:"version"`
+	CreatedAt          time.Time           `json:"createdAt,omitempty"`
+	UpdatedAt          time.Time           `json:"updatedAt,omitempty"`
 }
 
 type Handler struct {
-	orderUC usecase.IOrderUseCase
-	Logger  *logger.Logger
+	orderUC       usecase.IOrderUseCase
+	invoiceUC     usecase.IInvoiceUseCase
+	shipmentUC    usecase.IShipmentUseCase
+	paymentUC     usecase.IPaymentUseCase
+	reorderUC     usecase.IReorderUseCase
+	reservationUC usecase.IReservationUseCase
+	riskUC        usecase.IRiskUseCase
+	carriers      *carrier.Registry
+	ids           config.IDs
+	Logger        *logger.Logger
 }
 
-func NewHandler(uc usecase.IOrderUseCase, l *logger.Logger) *Handler {
-	return &Handler{orderUC: uc, Logger: l}
+func NewHandler(uc usecase.IOrderUseCase, invoiceUC usecase.IInvoiceUseCase, shipmentUC usecase.IShipmentUseCase, paymentUC usecase.IPaymentUseCase,
+	reorderUC usecase.IReorderUseCase, reservationUC usecase.IReservationUseCase,
+	riskUC usecase.IRiskUseCase, carriers *carrier.Registry, ids config.IDs, l *logger.Logger) *Handler {
+	return &Handler{
+		orderUC: uc, invoiceUC: invoiceUC, shipmentUC: shipmentUC, paymentUC: paymentUC, reorderUC: reorderUC,
+		reservationUC: reservationUC, riskUC: riskUC, carriers: carriers, ids: ids, Logger: l,
+	}
+}
+
+var orderListSpec = pagination.Spec{
+	Sortable: map[string]string{
+		"id": "id", "createdAt": "created_at", "totalAmount": "total_amount", "status": "status",
+	},
+	Filterable:  map[string]string{"status": "status"},
+	DefaultSort: "-createdAt",
+}
+
+// expandItemsProduct is the ?expand= name inlining the products of the order items
+const expandItemsProduct = "items.product"
+
+var orderSearchSpec = pagination.SearchSpec{
+	Spec: pagination.Spec{
+		Sortable:    orderListSpec.Sortable,
+		Filterable:  map[string]string{"status": "status", "currency": "currency", "userId": "user_id"},
+		DefaultSort: orderListSpec.DefaultSort,
+	},
+	Searchable: map[string]string{"orderNumber": "order_number", "cancellationReason": "cancellation_reason"},
+	Dates:      map[string]string{"createdAt": "created_at", "updatedAt": "updated_at", "archivedAt": "archived_at"},
 }
 
 // GetAllOrders godoc
 // @Summary      Get all orders
+// @Description  Staff see every order, archived ones included; customers only their own, without archived orders unless includeArchived is true
 // @Tags         Order
 // @Security     BearerAuth
-// @Success      200 {array} ResponseOrder
+// @Param        includeArchived query bool   false "Include archived orders"
+// @Param        page            query int    false "Page number" default(1)
+// @Param        limit           query int    false "Page size" default(20)
+// @Param        sort            query string false "Comma separated fields, prefix - for descending (id, createdAt, totalAmount, status)"
+// @Param        status          query string false "Filter by status"
+// @Param        fields          query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand          query string false "Related resources to inline: items.product"
+// @Success      200 {object} controllers.Envelope[[]ResponseOrder]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /order/ [get]
 func (h *Handler) GetAllOrders(ctx *gin.Context) {
-	orders, err := h.orderUC.GetAll()
+	includeArchived := false
+	if v := ctx.Query("includeArchived"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("includeArchived must be true or false"), domainErrors.ValidationError))
+			return
+		}
+		includeArchived = parsed
+	}
+	p, err := pagination.Parse(ctx, orderListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	orders, total, err := h.orderUC.GetAll(ctx.Request.Context(), requester, includeArchived, p)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, ordersToResponse(orders))
+	res := ordersToResponse(orders)
+	if err := h.expandOrders(ctx, res); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, res, total, p)
 }
 
 // GetOrderByID godoc
 // @Summary      Get order by ID
 // @Tags         Order
 // @Security     BearerAuth
-// @Param        id path int true "Order ID"
-// @Success      200 {object} ResponseOrder
+// @Param        id     path  string true  "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand query string false "Related resources to inline: items.product"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
 // @Router       /order/{id} [get]
 func (h *Handler) GetOrderByID(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	o, err := h.orderUC.GetByID(id)
+	o, err := h.orderUC.GetByID(ctx.Request.Context(), id, requester)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, orderToResponse(o))
+	res := []ResponseOrder{orderToResponse(o)}
+	if err := h.expandOrders(ctx, res); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, res[0])
+}
+
+// GetOrderByNumber godoc
+// @Summary      Get order by order number
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        orderNumber path  string true  "Order number, e.g. ORD-2024-000123"
+// @Param        fields      query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand      query string false "Related resources to inline: items.product"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Router       /order/number/{orderNumber} [get]
+func (h *Handler) GetOrderByNumber(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.orderUC.GetByNumber(ctx.Request.Context(), ctx.Param("orderNumber"), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := []ResponseOrder{orderToResponse(o)}
+	if err := h.expandOrders(ctx, res); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, res[0])
+}
+
+// SearchOrders godoc
+// @Summary      Search orders by order number prefix
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        number query string true  "Order number prefix"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand query string false "Related resources to inline: items.product"
+// @Success      200 {object} controllers.Envelope[[]ResponseOrder]
+// @Router       /order/search [get]
+func (h *Handler) SearchOrders(ctx *gin.Context) {
+	number := strings.TrimSpace(ctx.Query("number"))
+	if number == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("number query parameter is required"), domainErrors.ValidationError))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	orders, err := h.orderUC.SearchByNumber(ctx.Request.Context(), strings.ToUpper(number), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := ordersToResponse(orders)
+	if err := h.expandOrders(ctx, res); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// SearchOrdersPaginated godoc
+// @Summary      Search orders
+// @Description  Staff search every order, customers only their own, archived ones included.
+// @Description  likeFilters match orderNumber or cancellationReason; matches take exact status, currency or userId values;
+// @Description  dateRanges bound createdAt, updatedAt or archivedAt. Values of one field are ORed, fields are ANDed.
+// @Tags         Order
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body  filters.DataFilters true  "Search"
+// @Param        fields  query string              false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Param        expand  query string              false "Related resources to inline: items.product"
+// @Success      200 {object} controllers.Envelope[[]ResponseOrder]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Router       /order/search [post]
+func (h *Handler) SearchOrdersPaginated(ctx *gin.Context) {
+	var f filters.DataFilters
+	if err := controllers.BindJSONWithOptions(ctx, &f, controllers.BindOptions{DisallowUnknownFields: true}); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	s, err := pagination.ParseSearch(f, orderSearchSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	orders, total, err := h.orderUC.Search(ctx.Request.Context(), s, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := ordersToResponse(orders)
+	if err := h.expandOrders(ctx, res); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, res, total, s.Params)
 }
 
 // NewOrder godoc
 // @Summary      Create order
 // @Tags         Order
 // @Security     BearerAuth
+// @Description  Retrying with the same Idempotency-Key returns the order created by the first request.
+// @Description  storeCreditAmount (order currency) is paid from store credit; the rest becomes a pending card payment.
+// @Description  With reservationId the items must match that reservation exactly; an expired reservation fails with 410.
+// @Description  Discounts (order currency) can be given per item or for the whole order by staff only and are deducted from the total.
+// @Description  When the promotion service is configured it adds the discounts of the customer's promotions to the items; promotion discounts may not be sent.
+// @Param        Idempotency-Key header string false "Client-generated key, max 255 characters"
 // @Param        request body NewOrderRequest true "Order"
-// @Success      200 {object} ResponseOrder
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
 // @Router       /order/ [post]
 func (h *Handler) NewOrder(ctx *gin.Context) {
 	var req NewOrderRequest
 	if err := controllers.BindJSON(ctx, &req); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
 
-	// Extract user ID from JWT context
-	userIDVal, exists := ctx.Get("userId")
-	if !exists {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated))
+	idempotencyKey := ctx.GetHeader("Idempotency-Key")
+	if len(idempotencyKey) > 255 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("Idempotency-Key must be at most 255 characters"), domainErrors.ValidationError))
 		return
 	}
-	userID := int(userIDVal.(float64))
 
-	items := make([]domain.OrderItem, len(req.Items))
-	for i, it := range req.Items {
-		items[i] = domain.OrderItem{ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
 	}
 
-	o, err := h.orderUC.Create(&domain.Order{UserID: userID, Items: items})
+	o, err := h.orderUC.Create(ctx.Request.Context(), orderFromRequest(ctx, &req, requester.UserID), domain.CheckoutOptions{
+		IdempotencyKey: idempotencyKey, StoreCreditAmount: req.StoreCreditAmount, ReservationID: req.ReservationID,
+	}, requester)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, orderToResponse(o))
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
 }
 
 // UpdateOrderStatus godoc
 // @Summary      Update order status
+// @Description  Customers may only cancel their own pending orders. Paid needs staff; shipped and delivered need staff or fulfillment.
 // @Tags         Order
 // @Security     BearerAuth
-// @Param        id path int true "Order ID"
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
 // @Param        request body UpdateStatusRequest true "Status"
-// @Success      200 {object} ResponseOrder
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Failure      409 {object} controllers.ErrorEnvelope "The order changed since the version sent"
 // @Router       /order/{id}/status [put]
 func (h *Handler) UpdateOrderStatus(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := h.orderID(ctx)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
 	var req UpdateStatusRequest
 	if err := controllers.BindJSON(ctx, &req); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.orderUC.UpdateStatus(ctx.Request.Context(), id, req.Version, req.Status, req.Note, domain.CancellationReason(req.Reason), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// GetOrderTimeline godoc
+// @Summary      Get order status timeline
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[[]ResponseTimelineEntry]
+// @Router       /order/{id}/timeline [get]
+func (h *Handler) GetOrderTimeline(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	timeline, err := h.orderUC.GetTimeline(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseTimelineEntry, len(*timeline))
+	for i, e := range *timeline {
+		res[i] = ResponseTimelineEntry{
+			ID: e.ID, OldStatus: string(e.OldStatus), NewStatus: string(e.NewStatus),
+			ActorID: e.ActorID, ActorType: e.ActorType, Note: e.Note, CreatedAt: e.CreatedAt,
+		}
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// GetOrderComments godoc
+// @Summary      List internal order comments
+// @Description  Staff only; comments are never shown to customers
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[[]ResponseComment]
+// @Router       /order/{id}/comments [get]
+func (h *Handler) GetOrderComments(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	comments, err := h.orderUC.GetComments(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseComment, len(*comments))
+	for i, c := range *comments {
+		res[i] = ResponseComment{ID: c.ID, AuthorID: c.AuthorID, Body: c.Body, CreatedAt: c.CreatedAt}
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// NewOrderComment godoc
+// @Summary      Add internal order comment
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body NewCommentRequest true "Comment"
+// @Success      200 {object} controllers.Envelope[ResponseComment]
+// @Router       /order/{id}/comments [post]
+func (h *Handler) NewOrderComment(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req NewCommentRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	o, err := h.orderUC.UpdateStatus(id, req.Status)
+	requester, err := requesterFromContext(ctx)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, orderToResponse(o))
+	c, err := h.orderUC.AddComment(ctx.Request.Context(), id, req.Body, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, ResponseComment{ID: c.ID, AuthorID: c.AuthorID, Body: c.Body, CreatedAt: c.CreatedAt})
+}
+
+// ArchiveOrder godoc
+// @Summary      Archive order
+// @Description  Hides a delivered or cancelled order from the customer's default listing; staff still see it. Only the order's customer can archive it.
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Router       /order/{id}/archive [post]
+func (h *Handler) ArchiveOrder(ctx *gin.Context) {
+	h.setArchived(ctx, true)
+}
+
+// UnarchiveOrder godoc
+// @Summary      Restore archived order
+// @Description  Shows the order in the customer's default listing again
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Router       /order/{id}/archive [delete]
+func (h *Handler) UnarchiveOrder(ctx *gin.Context) {
+	h.setArchived(ctx, false)
+}
+
+func (h *Handler) setArchived(ctx *gin.Context, archived bool) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.orderUC.SetArchived(ctx.Request.Context(), id, archived, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// GetOrderInvoice godoc
+// @Summary      Get order invoice
+// @Description  Issues the invoice on first request and returns the stored HTML document afterwards
+// @Tags         Order
+// @Security     BearerAuth
+// @Produce      html
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {string} string "HTML invoice"
+// @Router       /order/{id}/invoice [get]
+func (h *Handler) GetOrderInvoice(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	inv, err := h.invoiceUC.GetOrCreate(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Header("X-Invoice-Number", inv.Number)
+	ctx.Data(http.StatusOK, inv.ContentType, inv.Content)
+}
+
+// orderID reads the order UUID or integer ID of the :id path parameter
+func (h *Handler) orderID(ctx *gin.Context) (int, error) {
+	return controllers.ParamID(ctx, "id", h.ids, h.orderUC.ResolveID)
+}
+
+// requesterFromContext reads the caller set by the JWT middleware
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:  int(userIDVal.(float64)),
+		Role:    ctx.GetString("userRole"),
+		IsStaff: security.IsStaffRole(ctx.GetString("userRole")),
+		IsAdmin: ctx.GetString("userRole") == security.RoleAdmin,
+	}, nil
 }
 
 // Mappers
 func orderToResponse(o *domain.Order) ResponseOrder {
 	items := make([]ResponseOrderItem, len(o.Items))
 	for i, it := range o.Items {
-		items[i] = ResponseOrderItem{ID: it.ID, ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Subtotal: it.Subtotal}
+		items[i] = ResponseOrderItem{
+			ID: it.ID, ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Subtotal: it.Subtotal,
+			DiscountAmount: it.DiscountAmount, Discounts: discountsToResponse(it.Discounts),
+		}
+	}
+	return ResponseOrder{
+		ID: o.ID, UUID: o.UUID, Number: o.Number, UserID: o.UserID, Status: string(o.Status), TotalAmount: o.TotalAmount, Items: items,
+		SubtotalAmount: o.SubtotalAmount, DiscountAmount: o.DiscountAmount, Discounts: discountsToResponse(o.Discounts),
+		Currency: o.Currency, BaseCurrency: o.BaseCurrency, ExchangeRate: o.ExchangeRate, BaseTotalAmount: o.BaseTotalAmount,
+		GiftMessage: o.Notes.GiftMessage, DeliveryIns: o.Notes.DeliveryInstructions,
+		ShippingAddress: addressToResponse(o.ShippingAddress), BillingAddress: addressToResponse(o.BillingAddress),
+		CancellationReason: string(o.CancellationReason), CancelledBy: o.CancelledBy,
+		Archived: o.ArchivedAt != nil, ArchivedAt: o.ArchivedAt, Version: o.Version,
+		CreatedAt: o.CreatedAt, UpdatedAt: o.UpdatedAt,
+	}
+}
+
+func orderFromRequest(ctx *gin.Context, req *NewOrderRequest, userID int) *domain.Order {
+	items := make([]domain.OrderItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = domain.OrderItem{ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Discounts: discountsFromRequest(it.Discounts)}
+	}
+	return &domain.Order{
+		UserID:          userID,
+		Items:           items,
+		Discounts:       discountsFromRequest(req.Discounts),
+		Currency:        req.Currency,
+		Notes:           domain.OrderNotes{GiftMessage: req.GiftMessage, DeliveryInstructions: req.DeliveryInstructions},
+		ShippingAddress: addressToDomain(req.ShippingAddress),
+		BillingAddress:  addressToDomain(req.BillingAddress),
+		ClientIP:        ctx.ClientIP(),
+	}
+}
+
+func addressToDomain(a *Address) *domain.Address {
+	if a == nil {
+		return nil
+	}
+	return &domain.Address{Name: a.Name, Line1: a.Line1, Line2: a.Line2, City: a.City, PostalCode: a.PostalCode, Country: strings.ToUpper(a.Country)}
+}
+
+func addressToResponse(a *domain.Address) *Address {
+	if a == nil {
+		return nil
+	}
+	return &Address{Name: a.Name, Line1: a.Line1, Line2: a.Line2, City: a.City, PostalCode: a.PostalCode, Country: a.Country}
+}
+
+func discountsFromRequest(reqs []DiscountRequest) []domain.OrderDiscount {
+	discounts := make([]domain.OrderDiscount, len(reqs))
+	for i, d := range reqs {
+		discounts[i] = domain.OrderDiscount{Source: domain.DiscountSource(d.Source), Code: d.Code, Description: d.Description, Amount: d.Amount}
+	}
+	return discounts
+}
+
+func discountsToResponse(discounts []domain.OrderDiscount) []ResponseDiscount {
+	res := make([]ResponseDiscount, len(discounts))
+	for i, d := range discounts {
+		res[i] = ResponseDiscount{ID: d.ID, Source: string(d.Source), Code: d.Code, Description: d.Description, Amount: d.Amount}
+	}
+	return res
+}
+
+// expandOrders inlines the related resources ?expand= asks for into the orders
+func (h *Handler) expandOrders(ctx *gin.Context, res []ResponseOrder) error {
+	expand, err := controllers.ParseExpand(ctx, expandItemsProduct)
+	if err != nil || !expand.Has(expandItemsProduct) {
+		return err
+	}
+	var ids []int
+	for _, o := range res {
+		for _, it := range o.Items {
+			ids = append(ids, it.ProductID)
+		}
+	}
+	products, err := h.orderUC.GetProducts(ctx.Request.Context(), ids)
+	if err != nil {
+		return err
+	}
+	for i := range res {
+		for j := range res[i].Items {
+			if p, ok := products[res[i].Items[j].ProductID]; ok {
+				res[i].Items[j].Product = &ResponseItemProduct{ID: p.ID, Name: p.Name, Price: p.Price, Stock: p.Stock, IsActive: p.IsActive}
+			}
+		}
 	}
-	return ResponseOrder{ID: o.ID, UserID: o.UserID, Status: string(o.Status), TotalAmount: o.TotalAmount, Items: items, CreatedAt: o.CreatedAt, UpdatedAt: o.UpdatedAt}
+	return nil
 }
 
 func ordersToResponse(orders *[]domain.Order) []ResponseOrder {
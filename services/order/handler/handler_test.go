@@ -0,0 +1,251 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/handler"
+	"ecommerce-microservice-go/services/order/usecase/mocks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	testJWT    = config.JWT{AccessSecret: "test-access-secret", AccessTime: time.Hour, Issuer: "user", Audience: "order",
+		AccessAudiences: []string{"order"}}
+	customer = domain.Requester{UserID: 5, Role: security.RoleCustomer}
+	staff    = domain.Requester{UserID: 1, Role: security.RoleStaff, IsStaff: true}
+	order    = domain.Order{ID: 12, UUID: "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f", Number: "ORD-2026-000012", UserID: 5,
+		Status: domain.OrderStatusPending, TotalAmount: 51, Currency: "IDR", Version: 2,
+		Items: []domain.OrderItem{{ID: 1, OrderID: 12, ProductID: 7, Quantity: 2, Price: 25.5, Subtotal: 51}}}
+)
+
+// newRouter serves the order routes as main.go does, without the database
+func newRouter(t *testing.T) (*gin.Engine, *mocks.MockIOrderUseCase) {
+	t.Helper()
+	if err := validation.Register(); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	orders := mocks.NewMockIOrderUseCase(ctrl)
+	h := handler.NewHandler(orders, mocks.NewMockIInvoiceUseCase(ctrl), mocks.NewMockIShipmentUseCase(ctrl), mocks.NewMockIPaymentUseCase(ctrl),
+		mocks.NewMockIReorderUseCase(ctrl), mocks.NewMockIReservationUseCase(ctrl), mocks.NewMockIRiskUseCase(ctrl), nil,
+		config.IDs{AllowIntegers: true}, testLogger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(controllers.UseEnvelope(), middleware.ErrorHandler(testLogger))
+	auth := router.Group("/v1/order", middleware.AuthJWTMiddleware(testJWT))
+	auth.GET("/:id", h.GetOrderByID)
+	auth.PUT("/:id/status", h.UpdateOrderStatus)
+	return router, orders
+}
+
+func bearer(t *testing.T, r domain.Requester) string {
+	t.Helper()
+	token, err := security.NewJWTService(testJWT).GenerateJWTToken(r.UserID, r.Role, "", security.Access)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token.Token
+}
+
+func TestGetOrderByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		requester  *domain.Requester
+		setup      func(m *mocks.MockIOrderUseCase)
+		wantStatus int
+		wantCode   domainErrors.ErrorCode
+		check      func(t *testing.T, res handler.ResponseOrder)
+	}{
+		{
+			name:       "anonymous request is rejected",
+			target:     "/v1/order/12",
+			setup:      func(*mocks.MockIOrderUseCase) {},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   domainErrors.CodeNotAuthenticated,
+		},
+		{
+			name:      "owner reads the order",
+			target:    "/v1/order/12",
+			requester: &customer,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().GetByID(gomock.Any(), 12, customer).Return(&order, nil)
+			},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, res handler.ResponseOrder) {
+				if res.Number != order.Number || len(res.Items) != 1 || res.Items[0].Product != nil {
+					t.Errorf("order = %+v, want %s with one item and no product", res, order.Number)
+				}
+			},
+		},
+		{
+			name:      "order by uuid",
+			target:    "/v1/order/" + order.UUID,
+			requester: &staff,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().ResolveID(gomock.Any(), order.UUID).Return(12, nil)
+				m.EXPECT().GetByID(gomock.Any(), 12, staff).Return(&order, nil)
+			},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, res handler.ResponseOrder) {
+				if res.UUID != order.UUID {
+					t.Errorf("uuid = %s, want %s", res.UUID, order.UUID)
+				}
+			},
+		},
+		{
+			name:      "products of the items are expanded",
+			target:    "/v1/order/12?fields=id,items.quantity&expand=items.product",
+			requester: &customer,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().GetByID(gomock.Any(), 12, customer).Return(&order, nil)
+				m.EXPECT().GetProducts(gomock.Any(), []int{7}).Return(map[int]*domain.CatalogProduct{7: {ID: 7, Name: "Basic Tee", Price: 25.5}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, res handler.ResponseOrder) {
+				item := res.Items[0]
+				if res.Number != "" || item.Price != 0 || item.Quantity != 2 || item.Product == nil || item.Product.Name != "Basic Tee" {
+					t.Errorf("order = %+v, item = %+v; want only the id and the item quantity and product", res, item)
+				}
+			},
+		},
+		{
+			name:      "order of someone else is not found",
+			target:    "/v1/order/13",
+			requester: &customer,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().GetByID(gomock.Any(), 13, customer).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   domainErrors.CodeNotFound,
+		},
+		{
+			name:       "unknown field",
+			target:     "/v1/order/12?fields=secret",
+			requester:  &customer,
+			setup:      func(m *mocks.MockIOrderUseCase) { m.EXPECT().GetByID(gomock.Any(), 12, customer).Return(&order, nil) },
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, orders := newRouter(t)
+			tt.setup(orders)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.requester != nil {
+				req.Header.Set("Authorization", bearer(t, *tt.requester))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				if got := controllers.DecodeError(w.Body).Code; got != tt.wantCode {
+					t.Errorf("code = %s, want %s", got, tt.wantCode)
+				}
+				return
+			}
+			var res handler.ResponseOrder
+			if err := controllers.DecodeData(w.Body, &res); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, res)
+		})
+	}
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	paid := order
+	paid.Status, paid.Version = domain.OrderStatusPaid, 3
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(m *mocks.MockIOrderUseCase)
+		wantStatus int
+		wantCode   domainErrors.ErrorCode
+	}{
+		{
+			name: "status changes at the version read",
+			body: `{"status":"paid","version":2}`,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 12, 2, "paid", "", domain.CancellationReason(""), staff).Return(&paid, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "stale version conflicts",
+			body: `{"status":"paid","version":1}`,
+			setup: func(m *mocks.MockIOrderUseCase) {
+				m.EXPECT().UpdateStatus(gomock.Any(), 12, 1, "paid", "", domain.CancellationReason(""), staff).
+					Return(nil, psql.NewConflictError("orders", 12, 1, 2))
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   domainErrors.CodeVersionConflict,
+		},
+		{
+			name:       "missing status is rejected before the use case",
+			body:       `{"note":"paid by transfer"}`,
+			setup:      func(*mocks.MockIOrderUseCase) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+		{
+			name:       "unknown cancellation reason is rejected",
+			body:       `{"status":"cancelled","reason":"bored"}`,
+			setup:      func(*mocks.MockIOrderUseCase) {},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domainErrors.CodeValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, orders := newRouter(t)
+			tt.setup(orders)
+
+			req := httptest.NewRequest(http.MethodPut, "/v1/order/12/status", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", bearer(t, staff))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				if got := controllers.DecodeError(w.Body).Code; got != tt.wantCode {
+					t.Errorf("code = %s, want %s", got, tt.wantCode)
+				}
+				return
+			}
+			var res handler.ResponseOrder
+			if err := controllers.DecodeData(w.Body, &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != string(domain.OrderStatusPaid) || res.Version != 3 {
+				t.Errorf("order = %+v, want paid at version 3", res)
+			}
+		})
+	}
+}
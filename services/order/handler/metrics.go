@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseMetricsSummary struct {
+	TotalOrders         int64            `json:"totalOrders"`
+	Revenue             float64          `json:"revenue"`
+	AverageOrderValue   float64          `json:"averageOrderValue"`
+	StatusBreakdown     map[string]int64 `json:"statusBreakdown"`
+	CancellationReasons map[string]int64 `json:"cancellationReasons"`
+	CancelledBy         map[string]int64 `json:"cancelledBy"`
+}
+
+type ResponseMetricsPeriod struct {
+	PeriodStart time.Time `json:"periodStart"`
+	ResponseMetricsSummary
+}
+
+type ResponseOrderMetrics struct {
+	GroupBy  string `json:"groupBy"`
+	Currency string `json:"currency"`
+	ResponseMetricsSummary
+	Periods []ResponseMetricsPeriod `json:"periods"`
+}
+
+// GetOrderMetrics godoc
+// @Summary      Order and revenue metrics
+// @Description  Totals and per-period series in the store's base currency. Revenue and average order value count paid, shipped and delivered orders. Admin only.
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        to query string false "Created before (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param        groupBy query string false "day (default), week or month"
+// @Success      200 {object} controllers.Envelope[ResponseOrderMetrics]
+// @Router       /order/metrics [get]
+func (h *Handler) GetOrderMetrics(ctx *gin.Context) {
+	from, to, err := dateRangeFromQuery(ctx)
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	groupBy := domain.MetricsGranularity(ctx.DefaultQuery("groupBy", string(domain.MetricsByDay)))
+	if !groupBy.IsValid() {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("groupBy must be day, week or month"), domainErrors.ValidationError))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	metrics, err := h.orderUC.GetMetrics(ctx.Request.Context(), domain.OrderMetricsFilter{From: from, To: to, GroupBy: groupBy}, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, metricsToResponse(metrics))
+}
+
+func metricsToResponse(m *domain.OrderMetrics) ResponseOrderMetrics {
+	periods := make([]ResponseMetricsPeriod, len(m.Periods))
+	for i, p := range m.Periods {
+		periods[i] = ResponseMetricsPeriod{PeriodStart: p.PeriodStart, ResponseMetricsSummary: summaryToResponse(p.OrderMetricsSummary)}
+	}
+	return ResponseOrderMetrics{GroupBy: string(m.GroupBy), Currency: m.Currency, ResponseMetricsSummary: summaryToResponse(m.OrderMetricsSummary), Periods: periods}
+}
+
+func summaryToResponse(s domain.OrderMetricsSummary) ResponseMetricsSummary {
+	breakdown := make(map[string]int64, len(s.StatusBreakdown))
+	for status, n := range s.StatusBreakdown {
+		breakdown[string(status)] = n
+	}
+	reasons := make(map[string]int64, len(s.CancellationReasons))
+	for reason, n := range s.CancellationReasons {
+		reasons[string(reason)] = n
+	}
+	return ResponseMetricsSummary{
+		TotalOrders: s.TotalOrders, Revenue: s.Revenue, AverageOrderValue: s.AverageOrderValue, StatusBreakdown: breakdown,
+		CancellationReasons: reasons, CancelledBy: s.CancelledBy,
+	}
+}
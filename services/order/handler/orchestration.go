@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Saga participant endpoints. The saga orchestrator drives checkouts and account deletions
+// through them; every call is safe to repeat, since the orchestrator retries after timeouts.
+
+type CheckoutReservationRequest struct {
+	Reference string                   `json:"reference" binding:"required,max=64"`
+	UserID    int                      `json:"userId" binding:"required"`
+	Role      string                   `json:"role"`
+	Items     []ReservationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type CheckoutOrderRequest struct {
+	Reference     string          `json:"reference" binding:"required,max=64"`
+	UserID        int             `json:"userId" binding:"required"`
+	Role          string          `json:"role"`
+	ReservationID string          `json:"reservationId" binding:"max=64"`
+	ClientIP      string          `json:"clientIp"`
+	Order         NewOrderRequest `json:"order" binding:"required"`
+}
+
+type ResponseCheckoutOrder struct {
+	ResponseOrder
+	// CardAmountDue is the part of the total still to be charged to the card, in order currency
+	CardAmountDue float64 `json:"cardAmountDue"`
+}
+
+type InternalCaptureRequest struct {
+	ChargeID string `json:"chargeId" binding:"max=255"`
+}
+
+type InternalCancelRequest struct {
+	Note   string `json:"note" binding:"max=500"`
+	Reason string `json:"reason" binding:"omitempty,oneof=payment_failed account_deleted other"`
+}
+
+type InternalCommentRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+type ResponseCancelledOrders struct {
+	Cancelled int `json:"cancelled"`
+}
+
+// ReserveCheckoutStock holds the stock of a checkout under the saga's reference
+func (h *Handler) ReserveCheckoutStock(ctx *gin.Context) {
+	var req CheckoutReservationRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	items := make([]domain.ReservationItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = domain.ReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	r, err := h.reservationUC.ReserveAs(ctx.Request.Context(), req.Reference, items, participantRequester(req.UserID, req.Role))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, reservationToResponse(r))
+}
+
+// CancelCheckoutReservation returns the stock of a checkout that is being rolled back
+func (h *Handler) CancelCheckoutReservation(ctx *gin.Context) {
+	if err := h.reservationUC.Cancel(ctx.Request.Context(), ctx.Param("reference")); err != nil && !errors.Is(err, domainErrors.ErrNotFound) {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateCheckoutOrder creates the order of a checkout; repeating the reference returns it again
+func (h *Handler) CreateCheckoutOrder(ctx *gin.Context) {
+	var req CheckoutOrderRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	order := orderFromRequest(ctx, &req.Order, req.UserID)
+	order.ClientIP = req.ClientIP
+	created, err := h.orderUC.Create(ctx.Request.Context(), order, domain.CheckoutOptions{
+		IdempotencyKey: req.Reference, StoreCreditAmount: req.Order.StoreCreditAmount, ReservationID: req.ReservationID,
+	}, participantRequester(req.UserID, req.Role))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	payments, err := h.paymentUC.GetPayments(ctx.Request.Context(), created.ID, domain.SystemRequester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := ResponseCheckoutOrder{ResponseOrder: orderToResponse(created)}
+	if created.Status == domain.OrderStatusPending {
+		for _, p := range *payments {
+			if p.Method == domain.PaymentMethodCard && p.Status == domain.PaymentStatusPending {
+				res.CardAmountDue += p.Amount
+			}
+		}
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// CaptureCheckoutPayment marks a checkout's order paid once its card was charged
+func (h *Handler) CaptureCheckoutPayment(ctx *gin.Context) {
+	id, err := internalID(ctx, "id")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req InternalCaptureRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	o, err := h.orderUC.GetByID(ctx.Request.Context(), id, domain.SystemRequester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	switch o.Status {
+	case domain.OrderStatusPending:
+		if o, err = h.paymentUC.CapturePayment(ctx.Request.Context(), id, req.ChargeID, domain.SystemRequester); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	case domain.OrderStatusCancelled:
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("order was cancelled before the payment was confirmed"), domainErrors.ValidationError))
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// CancelOrderInternal cancels an order for the orchestrator; cancelling it again is a no-op
+func (h *Handler) CancelOrderInternal(ctx *gin.Context) {
+	id, err := internalID(ctx, "id")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req InternalCancelRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	o, err := h.orderUC.GetByID(ctx.Request.Context(), id, domain.SystemRequester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if o.Status != domain.OrderStatusCancelled {
+		o, err = h.orderUC.UpdateStatus(ctx.Request.Context(), id, 0, string(domain.OrderStatusCancelled), req.Note,
+			domain.CancellationReason(req.Reason), domain.SystemRequester)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// AddOrderNoteInternal records a staff comment on the order, e.g. the outcome of a refund saga
+func (h *Handler) AddOrderNoteInternal(ctx *gin.Context) {
+	id, err := internalID(ctx, "id")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req InternalCommentRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	c, err := h.orderUC.AddComment(ctx.Request.Context(), id, req.Body, domain.SystemRequester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusCreated, ResponseComment{ID: c.ID, AuthorID: c.AuthorID, Body: c.Body, CreatedAt: c.CreatedAt})
+}
+
+// CancelUserOrders cancels the unpaid orders of an account being deleted, answering 400 while
+// the user still has paid or shipped orders
+func (h *Handler) CancelUserOrders(ctx *gin.Context) {
+	userID, err := internalID(ctx, "userId")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	n, err := h.orderUC.CancelOpenForUser(ctx.Request.Context(), userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, ResponseCancelledOrders{Cancelled: n})
+}
+
+// participantRequester acts as the customer the saga runs for, so their limits and risk checks apply
+func participantRequester(userID int, role string) domain.Requester {
+	if role == "" {
+		role = security.RoleCustomer
+	}
+	return domain.Requester{UserID: userID, Role: role, IsStaff: security.IsStaffRole(role), IsAdmin: role == security.RoleAdmin}
+}
+
+func internalID(ctx *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil || id <= 0 {
+		return 0, domainErrors.NewAppError(errors.New(name+" must be a positive integer"), domainErrors.ValidationError)
+	}
+	return id, nil
+}
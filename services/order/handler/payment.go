@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CapturePaymentRequest struct {
+	Reference string `json:"reference" binding:"max=255"`
+}
+
+type StoreCreditAdjustmentRequest struct {
+	UserID int     `json:"userId" binding:"required"`
+	Amount float64 `json:"amount" binding:"required"`
+	Note   string  `json:"note" binding:"max=500"`
+}
+
+type StoreCreditRefundRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Note   string  `json:"note" binding:"max=500"`
+}
+
+type ResponsePayment struct {
+	ID         int       `json:"id"`
+	Method     string    `json:"method"`
+	Status     string    `json:"status"`
+	Amount     float64   `json:"amount"`
+	BaseAmount float64   `json:"baseAmount"`
+	Reference  string    `json:"reference,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type ResponseStoreCreditEntry struct {
+	ID        int       `json:"id"`
+	Amount    float64   `json:"amount"`
+	Reason    string    `json:"reason"`
+	Note      string    `json:"note,omitempty"`
+	OrderID   *int      `json:"orderId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ResponseStoreCredit struct {
+	UserID   int                        `json:"userId"`
+	Balance  float64                    `json:"balance"`
+	Currency string                     `json:"currency"`
+	Entries  []ResponseStoreCreditEntry `json:"entries"`
+}
+
+// GetOrderPayments godoc
+// @Summary      List order payments
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[[]ResponsePayment]
+// @Router       /order/{id}/payments [get]
+func (h *Handler) GetOrderPayments(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	payments, err := h.paymentUC.GetPayments(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponsePayment, len(*payments))
+	for i, p := range *payments {
+		res[i] = ResponsePayment{
+			ID: p.ID, Method: string(p.Method), Status: string(p.Status), Amount: p.Amount, BaseAmount: p.BaseAmount,
+			Reference: p.Reference, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+		}
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// CaptureOrderPayment godoc
+// @Summary      Capture card payment
+// @Description  Confirms the pending card payment and marks the order paid. Staff only.
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body CapturePaymentRequest true "Payment reference"
+// @Param        Idempotency-Key header string false "Client-generated key, max 255 characters"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Router       /order/{id}/payments/capture [post]
+func (h *Handler) CaptureOrderPayment(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req CapturePaymentRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.paymentUC.CapturePayment(ctx.Request.Context(), id, req.Reference, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// RefundOrderToStoreCredit godoc
+// @Summary      Refund to store credit
+// @Description  Credits part of a paid order, in order currency, to the customer's store credit. Admin only.
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body StoreCreditRefundRequest true "Refund"
+// @Param        Idempotency-Key header string false "Client-generated key, max 255 characters"
+// @Success      200 {object} controllers.Envelope[ResponseStoreCredit]
+// @Router       /order/{id}/refunds/store-credit [post]
+func (h *Handler) RefundOrderToStoreCredit(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req StoreCreditRefundRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	account, err := h.paymentUC.RefundToStoreCredit(ctx.Request.Context(), id, req.Amount, req.Note, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, storeCreditToResponse(account))
+}
+
+// GetStoreCredit godoc
+// @Summary      Get store credit
+// @Description  Balance and recent movements in the base currency. Staff may pass userId.
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        userId query int false "User ID (staff only)"
+// @Success      200 {object} controllers.Envelope[ResponseStoreCredit]
+// @Router       /order/store-credit [get]
+func (h *Handler) GetStoreCredit(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	userID := requester.UserID
+	if v := ctx.Query("userId"); v != "" {
+		if userID, err = strconv.Atoi(v); err != nil {
+			_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid userId"), domainErrors.ValidationError))
+			return
+		}
+	}
+	account, err := h.paymentUC.GetStoreCredit(ctx.Request.Context(), userID, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, storeCreditToResponse(account))
+}
+
+// AdjustStoreCredit godoc
+// @Summary      Adjust store credit
+// @Description  Adds (positive amount) or removes (negative amount) base-currency store credit. Admin only.
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        request body StoreCreditAdjustmentRequest true "Adjustment"
+// @Success      200 {object} controllers.Envelope[ResponseStoreCredit]
+// @Router       /order/store-credit/adjustments [post]
+func (h *Handler) AdjustStoreCredit(ctx *gin.Context) {
+	var req StoreCreditAdjustmentRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	account, err := h.paymentUC.AdjustStoreCredit(ctx.Request.Context(), req.UserID, req.Amount, req.Note, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, storeCreditToResponse(account))
+}
+
+func storeCreditToResponse(a *domain.StoreCreditAccount) ResponseStoreCredit {
+	entries := make([]ResponseStoreCreditEntry, len(a.Entries))
+	for i, e := range a.Entries {
+		entries[i] = ResponseStoreCreditEntry{ID: e.ID, Amount: e.Amount, Reason: e.Reason, Note: e.Note, OrderID: e.OrderID, CreatedAt: e.CreatedAt}
+	}
+	return ResponseStoreCredit{UserID: a.UserID, Balance: a.Balance, Currency: a.Currency, Entries: entries}
+}
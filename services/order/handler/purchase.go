@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponsePurchase struct {
+	OrderID     int       `json:"orderId"`
+	OrderNumber string    `json:"orderNumber"`
+	Status      string    `json:"status"`
+	PurchasedAt time.Time `json:"purchasedAt"`
+}
+
+// FindPurchase tells the review service whether a user bought a product, answering 404
+// when they have not. It is only reachable with the internal token.
+func (h *Handler) FindPurchase(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Query("userId"))
+	if err != nil || userID <= 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("userId must be a positive integer"), domainErrors.ValidationError))
+		return
+	}
+	productID, err := strconv.Atoi(ctx.Query("productId"))
+	if err != nil || productID <= 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("productId must be a positive integer"), domainErrors.ValidationError))
+		return
+	}
+	p, err := h.orderUC.FindPurchase(ctx.Request.Context(), userID, productID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, ResponsePurchase{OrderID: p.OrderID, OrderNumber: p.OrderNumber, Status: string(p.Status), PurchasedAt: p.PurchasedAt})
+}
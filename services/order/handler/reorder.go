@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseReorderItem struct {
+	ProductID        int      `json:"productId"`
+	Name             string   `json:"name,omitempty"`
+	PreviousQuantity int      `json:"previousQuantity"`
+	Quantity         int      `json:"quantity"`
+	PreviousPrice    float64  `json:"previousPrice"`
+	Price            float64  `json:"price"`
+	Purchasable      bool     `json:"purchasable"`
+	Issues           []string `json:"issues"`
+}
+
+type ResponseReorder struct {
+	SourceOrderID     int                   `json:"sourceOrderId"`
+	SourceOrderNumber string                `json:"sourceOrderNumber"`
+	Currency          string                `json:"currency"`
+	TotalAmount       float64               `json:"totalAmount"`
+	Items             []ResponseReorderItem `json:"items"`
+	Checkout          NewOrderRequest       `json:"checkout"`
+}
+
+// ReorderOrder godoc
+// @Summary      Reorder a past order
+// @Description  Rebuilds a checkout from the order at current catalog prices and stock. Items that can no longer be bought are flagged and left out of checkout, which can be posted to /order/ as is.
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[ResponseReorder]
+// @Router       /order/{id}/reorder [post]
+func (h *Handler) ReorderOrder(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	reorder, err := h.reorderUC.Reorder(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, reorderToResponse(reorder))
+}
+
+func reorderToResponse(r *domain.Reorder) ResponseReorder {
+	res := ResponseReorder{
+		SourceOrderID: r.SourceOrderID, SourceOrderNumber: r.SourceOrderNumber, Currency: r.Currency, TotalAmount: r.TotalAmount,
+		Items:    make([]ResponseReorderItem, len(r.Items)),
+		Checkout: NewOrderRequest{Items: []OrderItemRequest{}, Currency: r.Currency},
+	}
+	for i, item := range r.Items {
+		issues := make([]string, len(item.Issues))
+		for j, issue := range item.Issues {
+			issues[j] = string(issue)
+		}
+		res.Items[i] = ResponseReorderItem{
+			ProductID: item.ProductID, Name: item.Name, PreviousQuantity: item.PreviousQuantity, Quantity: item.Quantity,
+			PreviousPrice: item.PreviousPrice, Price: item.Price, Purchasable: item.Purchasable, Issues: issues,
+		}
+		if item.Purchasable {
+			res.Checkout.Items = append(res.Checkout.Items, OrderItemRequest{ProductID: item.ProductID, Quantity: item.Quantity, Price: item.Price})
+		}
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReservationItemRequest struct {
+	ProductID int `json:"productId" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required,gt=0"`
+}
+
+type NewReservationRequest struct {
+	Items []ReservationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type ResponseReservationItem struct {
+	ProductID int `json:"productId"`
+	Quantity  int `json:"quantity"`
+}
+
+type ResponseReservation struct {
+	ReservationID string                    `json:"reservationId"`
+	Status        string                    `json:"status"`
+	Items         []ResponseReservationItem `json:"items"`
+	ExpiresAt     time.Time                 `json:"expiresAt"`
+}
+
+// NewReservation godoc
+// @Summary      Reserve stock for checkout
+// @Description  Holds the items in the catalog until expiresAt. Pass reservationId when creating the order; an expired reservation fails with 410 and the items must be reserved again.
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        request body NewReservationRequest true "Items"
+// @Success      201 {object} controllers.Envelope[ResponseReservation]
+// @Router       /order/reservations [post]
+func (h *Handler) NewReservation(ctx *gin.Context) {
+	var req NewReservationRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	items := make([]domain.ReservationItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = domain.ReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	r, err := h.reservationUC.Reserve(ctx.Request.Context(), items, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusCreated, reservationToResponse(r))
+}
+
+// DeleteReservation godoc
+// @Summary      Release a checkout reservation
+// @Description  Returns the reserved stock to the catalog when checkout is abandoned
+// @Tags         Order
+// @Security     BearerAuth
+// @Param        reservationId path string true "Reservation ID"
+// @Success      204
+// @Router       /order/reservations/{reservationId} [delete]
+func (h *Handler) DeleteReservation(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.reservationUC.Release(ctx.Request.Context(), ctx.Param("reservationId"), requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func reservationToResponse(r *domain.Reservation) ResponseReservation {
+	res := ResponseReservation{ReservationID: r.Reference, Status: string(r.Status), ExpiresAt: r.ExpiresAt, Items: make([]ResponseReservationItem, len(r.Items))}
+	for i, it := range r.Items {
+		res.Items[i] = ResponseReservationItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	return res
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewOrderRequest struct {
+	Approve *bool  `json:"approve" binding:"required"`
+	Note    string `json:"note" binding:"max=500"`
+}
+
+type NewBlocklistEntryRequest struct {
+	Kind   string `json:"kind" binding:"required,oneof=email email_domain ip"`
+	Value  string `json:"value" binding:"required,max=320"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type ResponseRiskSignal struct {
+	Check  string `json:"check"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+type ResponseOrderRisk struct {
+	OrderID   int                  `json:"orderId"`
+	Status    string               `json:"status"`
+	ClientIP  string               `json:"clientIp,omitempty"`
+	RiskScore int                  `json:"riskScore"`
+	Signals   []ResponseRiskSignal `json:"signals"`
+}
+
+type ResponseBlocklistEntry struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason,omitempty"`
+	ActorID   int       `json:"actorId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetReviewQueue godoc
+// @Summary      List orders held for risk review
+// @Description  Orders the fraud and velocity checks flagged at checkout, oldest first. Needs the order review permission.
+// @Tags         Risk
+// @Security     BearerAuth
+// @Success      200 {object} controllers.Envelope[[]ResponseOrderRisk]
+// @Router       /order/reviews [get]
+func (h *Handler) GetReviewQueue(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	orders, err := h.riskUC.GetReviewQueue(ctx.Request.Context(), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseOrderRisk, len(*orders))
+	for i := range *orders {
+		res[i] = orderRiskToResponse(&(*orders)[i])
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// GetOrderRisk godoc
+// @Summary      Get order risk assessment
+// @Description  The risk score and the signals that made it up. Needs the order review permission.
+// @Tags         Risk
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[ResponseOrderRisk]
+// @Router       /order/{id}/risk [get]
+func (h *Handler) GetOrderRisk(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.riskUC.GetRisk(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderRiskToResponse(o))
+}
+
+// ReviewOrder godoc
+// @Summary      Approve or reject an order held for review
+// @Description  Approved orders continue as pending, or paid when store credit covered them; rejected orders are cancelled and their store credit returned. Needs the order review permission.
+// @Tags         Risk
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body ReviewOrderRequest true "Decision"
+// @Success      200 {object} controllers.Envelope[ResponseOrder]
+// @Router       /order/{id}/review [post]
+func (h *Handler) ReviewOrder(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req ReviewOrderRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	o, err := h.riskUC.ReviewOrder(ctx.Request.Context(), id, *req.Approve, req.Note, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, orderToResponse(o))
+}
+
+// GetBlocklist godoc
+// @Summary      List blocklist entries
+// @Tags         Risk
+// @Security     BearerAuth
+// @Success      200 {object} controllers.Envelope[[]ResponseBlocklistEntry]
+// @Router       /order/risk/blocklist [get]
+func (h *Handler) GetBlocklist(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	entries, err := h.riskUC.GetBlocklist(ctx.Request.Context(), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseBlocklistEntry, len(*entries))
+	for i := range *entries {
+		res[i] = blocklistEntryToResponse(&(*entries)[i])
+	}
+	controllers.Respond(ctx, http.StatusOK, res)
+}
+
+// NewBlocklistEntry godoc
+// @Summary      Block an email, email domain or IP
+// @Description  Orders matching an entry are held for review. Adding an existing entry returns it unchanged.
+// @Tags         Risk
+// @Security     BearerAuth
+// @Param        request body NewBlocklistEntryRequest true "Entry"
+// @Success      201 {object} controllers.Envelope[ResponseBlocklistEntry]
+// @Router       /order/risk/blocklist [post]
+func (h *Handler) NewBlocklistEntry(ctx *gin.Context) {
+	var req NewBlocklistEntryRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	e, err := h.riskUC.AddToBlocklist(ctx.Request.Context(), domain.BlocklistKind(req.Kind), req.Value, req.Reason, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusCreated, blocklistEntryToResponse(e))
+}
+
+// DeleteBlocklistEntry godoc
+// @Summary      Remove a blocklist entry
+// @Tags         Risk
+// @Security     BearerAuth
+// @Param        entryId path int true "Entry ID"
+// @Success      204
+// @Router       /order/risk/blocklist/{entryId} [delete]
+func (h *Handler) DeleteBlocklistEntry(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("entryId"))
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid entry id"), domainErrors.ValidationError))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.riskUC.RemoveFromBlocklist(ctx.Request.Context(), id, requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func orderRiskToResponse(o *domain.Order) ResponseOrderRisk {
+	signals := make([]ResponseRiskSignal, len(o.RiskSignals))
+	for i, s := range o.RiskSignals {
+		signals[i] = ResponseRiskSignal{Check: s.Check, Score: s.Score, Reason: s.Reason}
+	}
+	return ResponseOrderRisk{OrderID: o.ID, Status: string(o.Status), ClientIP: o.ClientIP, RiskScore: o.RiskScore, Signals: signals}
+}
+
+func blocklistEntryToResponse(e *domain.BlocklistEntry) ResponseBlocklistEntry {
+	return ResponseBlocklistEntry{ID: e.ID, Kind: string(e.Kind), Value: e.Value, Reason: e.Reason, ActorID: e.ActorID, CreatedAt: e.CreatedAt}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/carrier"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ShipOrderRequest struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"trackingNumber" binding:"required,max=100"`
+}
+
+type ResponseShipment struct {
+	ID             int        `json:"id"`
+	OrderID        int        `json:"orderId"`
+	Carrier        string     `json:"carrier"`
+	TrackingNumber string     `json:"trackingNumber"`
+	TrackingURL    string     `json:"trackingUrl,omitempty"`
+	Status         string     `json:"status"`
+	LastEvent      string     `json:"lastEvent,omitempty"`
+	LastEventAt    *time.Time `json:"lastEventAt,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// ShipOrder godoc
+// @Summary      Ship order
+// @Description  Stores carrier and tracking number and marks a paid order as shipped. Staff or fulfillment only.
+// @Tags         Shipment
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body ShipOrderRequest true "Shipment"
+// @Success      200 {object} controllers.Envelope[ResponseShipment]
+// @Router       /order/{id}/shipment [put]
+func (h *Handler) ShipOrder(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req ShipOrderRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	shipment, err := h.shipmentUC.Ship(ctx.Request.Context(), id, req.Carrier, strings.TrimSpace(req.TrackingNumber), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, shipmentToResponse(shipment))
+}
+
+// GetOrderShipment godoc
+// @Summary      Get order shipment
+// @Tags         Shipment
+// @Security     BearerAuth
+// @Param        id path string true "Order UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[ResponseShipment]
+// @Router       /order/{id}/shipment [get]
+func (h *Handler) GetOrderShipment(ctx *gin.Context) {
+	id, err := h.orderID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	shipment, err := h.shipmentUC.GetByOrderID(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, shipmentToResponse(shipment))
+}
+
+// CarrierWebhook godoc
+// @Summary      Carrier tracking webhook
+// @Description  Receives tracking updates pushed by a carrier. Authenticated by the carrier's X-Carrier-Signature.
+// @Tags         Shipment
+// @Param        carrier path string true "Carrier name"
+// @Success      204
+// @Router       /order/carriers/{carrier}/webhook [post]
+func (h *Handler) CarrierWebhook(ctx *gin.Context) {
+	adapter, err := h.carriers.Get(ctx.Param("carrier"))
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.NotFound))
+		return
+	}
+	updates, err := adapter.ParseWebhook(ctx.Request)
+	if err != nil {
+		if errors.Is(err, carrier.ErrInvalidSignature) {
+			h.Logger.Warn("Rejected carrier webhook", zap.String("carrier", adapter.Name()))
+			_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.NotAuthenticated))
+			return
+		}
+		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		return
+	}
+	if err := h.shipmentUC.ApplyTrackingUpdates(ctx.Request.Context(), adapter.Name(), updates); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func shipmentToResponse(s *domain.Shipment) ResponseShipment {
+	return ResponseShipment{
+		ID: s.ID, OrderID: s.OrderID, Carrier: s.Carrier, TrackingNumber: s.TrackingNumber, TrackingURL: s.TrackingURL,
+		Status: string(s.Status), LastEvent: s.LastEvent, LastEventAt: s.LastEventAt, DeliveredAt: s.DeliveredAt,
+		CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package invoice
+
+//go:generate mockgen -source=invoice.go -destination=mocks/invoice.go -package=mocks
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+//go:embed template.html
+var invoiceTemplate string
+
+type IRenderer interface {
+	Render(inv *domain.Invoice, order *domain.Order) ([]byte, error)
+}
+
+type HTMLRenderer struct {
+	seller domain.SellerDetails
+	tmpl   *template.Template
+}
+
+func NewHTMLRenderer(seller domain.SellerDetails) (IRenderer, error) {
+	tmpl, err := template.New("invoice").Funcs(template.FuncMap{
+		"money":   func(v float64) string { return fmt.Sprintf("%.2f", v) },
+		"percent": func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) },
+	}).Parse(invoiceTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &HTMLRenderer{seller: seller, tmpl: tmpl}, nil
+}
+
+func (r *HTMLRenderer) Render(inv *domain.Invoice, order *domain.Order) ([]byte, error) {
+	var buf bytes.Buffer
+	err := r.tmpl.Execute(&buf, struct {
+		Invoice *domain.Invoice
+		Order   *domain.Order
+		Seller  domain.SellerDetails
+	}{inv, order, r.seller})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -12,102 +12,320 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"time"
 
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/idempotency"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
 	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
 	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/pkg/scheduler"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/order/carrier"
+	"ecommerce-microservice-go/services/order/client"
+	"ecommerce-microservice-go/services/order/grpcserver"
 	"ecommerce-microservice-go/services/order/handler"
+	"ecommerce-microservice-go/services/order/invoice"
+	"ecommerce-microservice-go/services/order/migrations"
 	"ecommerce-microservice-go/services/order/repository"
+	"ecommerce-microservice-go/services/order/risk"
 	"ecommerce-microservice-go/services/order/usecase"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
 
 	_ "ecommerce-microservice-go/services/order/docs"
 )
 
+const service app.Service = "order"
+
 func main() {
-	env := getEnvOrDefault("GO_ENV", "development")
-	var log *logger.Logger
-	var err error
-	if env == "development" {
-		log, err = logger.NewDevelopmentLogger()
-	} else {
-		log, err = logger.NewLogger()
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
 	}
+	log, err := logger.New(cfg.Log)
 	if err != nil {
 		panic(fmt.Errorf("error initializing logger: %w", err))
 	}
-	defer func() { _ = log.Log.Sync() }()
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
 
 	log.Info("Starting Order Service")
 
-	db, err := psql.ConnectDB(log)
-	if err != nil {
-		log.Panic("Failed to connect to database", zap.Error(err))
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
 	}
+	if len(args) > 0 && args[0] == "snapshot" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.SnapshotCommand(args[1:])); err != nil {
+			log.Fatal("Snapshot command failed", zap.Error(err))
+		}
+		return
+	}
+	schema := fx.Options(fx.Invoke(app.EnsureSchema), fx.Invoke(backfill))
+	if len(args) > 0 && args[0] == "seed" {
+		err := app.RunCommand(service, log, cfg.Server, fx.Options(provide(cfg), schema), func(seeder fixtures.Seeder) error {
+			return fixtures.RunSeedCommand(context.Background(), cfg.Fixtures, args[1:], seeder, os.Stdout)
+		})
+		if err != nil {
+			log.Fatal("Seed command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		schema,
+		fx.Invoke(func(seeder fixtures.Seeder) error {
+			return fixtures.SeedOnStart(context.Background(), cfg.Fixtures, seeder, log)
+		}),
+		// committed order events go to the broker, where the webhook service picks them up
+		fx.Invoke(func(lc fx.Lifecycle, relay *events.OutboxRelay) { app.Go(lc, relay.Run) }),
+		fx.Invoke(startJobs),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, server *grpc.Server) {
+			app.ServeGRPC(lc, s, log, "Order gRPC server", cfg.GRPCPort, server)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Order Service", cfg.Server, router)
+		}),
+	), log)
+}
 
-	if err := psql.AutoMigrate(db, log, &repository.Order{}, &repository.OrderItem{}); err != nil {
-		log.Panic("Failed to migrate database", zap.Error(err))
+// backfill fills in the columns that migrations added to existing orders
+func backfill(cfg Config, db *gorm.DB, log *logger.Logger) error {
+	if err := repository.BackfillOrderNumbers(db, log); err != nil {
+		return fmt.Errorf("backfilling order numbers: %w", err)
 	}
+	if err := repository.BackfillCurrencies(db, cfg.Currency.Base, log); err != nil {
+		return fmt.Errorf("backfilling order currencies: %w", err)
+	}
+	return nil
+}
 
-	orderRepo := repository.NewOrderRepository(db, log)
-	orderUC := usecase.NewOrderUseCase(orderRepo, log)
-	h := handler.NewHandler(orderUC, log)
+// startJobs runs the periodic jobs of the service while it is up
+func startJobs(lc fx.Lifecycle, cfg Config, log *logger.Logger, db *gorm.DB, orderUC usecase.IOrderUseCase, shipmentUC usecase.IShipmentUseCase,
+	reservationUC usecase.IReservationUseCase, rates *currency.Rates) {
+	jobs := scheduler.New(log)
+	jobs.Add(scheduler.Job{
+		Name:     "cancel-unpaid-orders",
+		Interval: cfg.Jobs.AutoCancel,
+		Run: func(ctx context.Context) (int, error) {
+			return orderUC.CancelUnpaid(ctx, cfg.PaymentWindow)
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "poll-carrier-tracking",
+		Interval: cfg.Jobs.CarrierPoll,
+		Run:      shipmentUC.PollActiveShipments,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "expire-checkout-reservations",
+		Interval: cfg.Jobs.ReservationSweep,
+		Run:      reservationUC.ExpireAbandoned,
+	})
+	if cfg.Currency.URL != "" {
+		jobs.Add(scheduler.Job{
+			Name:     "refresh-exchange-rates",
+			Interval: cfg.Currency.Refresh,
+			Run:      rates.Refresh,
+		})
+	}
+	for _, job := range retention.Jobs(db, repository.RetentionRules, cfg.Retention, log) {
+		jobs.Add(job)
+	}
+	app.Go(lc, jobs.Run)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Currency, cfg.IDs, cfg.Features, cfg.Authz, cfg.Seller),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Snapshots(repository.SnapshotTables),
+			app.Cache,
+			app.Audit,
+			app.Auditor,
+			app.AuthzStore,
+			app.AuthzEnforcer,
+			app.Enforcer,
+			app.ExchangeRates,
+			app.RateProvider,
+			app.Publisher,
+			func(db *gorm.DB, publisher events.Publisher, log *logger.Logger) *events.OutboxRelay {
+				return events.NewOutboxRelay(db, publisher, log, cfg.Outbox)
+			},
+			psql.NewTxManager,
+			featureflags.NewFromConfig,
+			func() client.IUserClient { return client.NewUserClient(cfg.UserServiceURL, cfg.InternalToken) },
+			func() client.ICatalogClient { return client.NewCatalogClient(cfg.CatalogServiceURL, cfg.InternalToken) },
+			func() client.IPromotionClient {
+				if cfg.PromotionServiceURL == "" {
+					return nil
+				}
+				return client.NewPromotionClient(cfg.PromotionServiceURL, cfg.InternalToken)
+			},
+			func(orderRepo repository.OrderRepositoryInterface, blocklistRepo repository.BlocklistRepositoryInterface, userClient client.IUserClient,
+				log *logger.Logger) risk.Assessor {
+				return risk.NewEngine(cfg.Risk.ReviewScore, log,
+					risk.NewVelocityCheck(orderRepo, time.Hour, cfg.Risk.MaxOrdersPerUserHour, cfg.Risk.MaxOrdersPerIPHour, 50),
+					risk.NewAddressMismatchCheck(30, 20),
+					risk.NewBlocklistCheck(blocklistRepo, userClient, 100),
+				)
+			},
+			carrier.NewRegistryFromEnv,
+			invoice.NewHTMLRenderer,
+			repository.NewOrderRepository,
+			repository.NewPaymentRepository,
+			repository.NewStoreCreditRepository,
+			repository.NewReservationRepository,
+			repository.NewBlocklistRepository,
+			repository.NewInvoiceRepository,
+			repository.NewShipmentRepository,
+			usecase.NewOrderUseCase,
+			usecase.NewPaymentUseCase,
+			usecase.NewRiskUseCase,
+			usecase.NewReorderUseCase,
+			func(r repository.ReservationRepositoryInterface, catalog client.ICatalogClient, txManager psql.TxManager, log *logger.Logger) usecase.IReservationUseCase {
+				return usecase.NewReservationUseCase(r, catalog, txManager, cfg.ReservationTTL, log)
+			},
+			func(orderUC usecase.IOrderUseCase, r repository.InvoiceRepositoryInterface, renderer invoice.IRenderer, log *logger.Logger) usecase.IInvoiceUseCase {
+				return usecase.NewInvoiceUseCase(orderUC, r, renderer, cfg.InvoiceTaxRate, log)
+			},
+			usecase.NewShipmentUseCase,
+			// fixture orders refer to seeded customers and products, so user and catalog seed first
+			usecase.NewFixtureSeeder,
+			handler.NewHandler,
+			grpcserver.NewServer,
+			newRouter,
+		),
+	)
+}
 
-	if env != "development" {
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, publisher events.Publisher,
+	migrator *psql.Migrator, rates *currency.Rates, flags *featureflags.Client, auditStore audit.Store,
+	authzStore authz.Store, enforcer *authz.PolicyEnforcer, snapshots *backup.Snapshotter, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
 		log.SetupGinWithZapLogger()
 	} else {
 		log.SetupGinWithZapLoggerInDevelopment()
 	}
 
-	router := gin.New()
-	router.Use(gin.Recovery(), cors.Default())
-	router.Use(middleware.ErrorHandler())
-	router.Use(middleware.CommonHeaders)
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(controllers.UseEnvelope(), middleware.RequestID(), middleware.Recovery("order", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("order"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
 	router.Use(log.GinZapLogger())
+	router.Use(featureflags.Middleware(flags))
 
 	v1 := router.Group("/v1")
 
-	v1.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "order"})
-	})
+	probes := health.NewRegistry("order").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(publisher)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		Add("exchange-rates", currency.Check(rates))
+	probes.RegisterRoutes(v1)
 
 	v1.GET("/order/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+	// Carriers authenticate with their webhook signature instead of a user token
+	v1.POST("/order/carriers/:carrier/webhook", h.CarrierWebhook)
+
+	// order creation and payments replay their first response to retries with the same Idempotency-Key
+	idempotent := idempotency.Middleware(idempotency.NewDBStore(db), "order", cfg.Idempotency, log)
 
 	// All order routes require auth
 	order := v1.Group("/order")
-	order.Use(middleware.AuthJWTMiddleware())
+	order.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RateLimit(cacheStore, "order", cfg.RateLimit, middleware.KeyByUser, log))
 	{
 		order.GET("/", h.GetAllOrders)
-		order.POST("/", h.NewOrder)
+		order.POST("/", idempotent, h.NewOrder)
+		order.GET("/reviews", h.GetReviewQueue)
+		order.GET("/risk/blocklist", h.GetBlocklist)
+		order.POST("/risk/blocklist", h.NewBlocklistEntry)
+		order.DELETE("/risk/blocklist/:entryId", h.DeleteBlocklistEntry)
+		order.GET("/audit", middleware.RequirePermissions(security.PermAuditRead), audit.NewHandler(auditStore, log).List)
+		authz.NewHandler(authzStore, enforcer, log).RegisterRoutes(order.Group("/authz/policies", middleware.RequirePermissions(security.PermAuthzManage)))
+		backup.NewHandler(snapshots, cfg.Backup, log).RegisterRoutes(order.Group("/snapshot", middleware.RequirePermissions(security.PermBackupManage)))
+		order.GET("/search", h.SearchOrders)
+		order.POST("/search", h.SearchOrdersPaginated)
+		order.GET("/export", h.ExportOrders)
+		order.GET("/metrics", h.GetOrderMetrics)
+		order.GET("/store-credit", h.GetStoreCredit)
+		order.POST("/store-credit/adjustments", h.AdjustStoreCredit)
+		order.POST("/reservations", h.NewReservation)
+		order.DELETE("/reservations/:reservationId", h.DeleteReservation)
+		order.GET("/number/:orderNumber", h.GetOrderByNumber)
 		order.GET("/:id", h.GetOrderByID)
-		order.PUT("/:id/status", h.UpdateOrderStatus)
+		order.PUT("/:id/status", middleware.RequirePermissions(security.PermOrderCancelOwn, security.PermOrderCancelAny, security.PermOrderMarkPaid,
+			security.PermOrderShip, security.PermOrderDeliver, security.PermOrderUpdateAny), h.UpdateOrderStatus)
+		order.POST("/:id/reorder", h.ReorderOrder)
+		order.POST("/:id/archive", h.ArchiveOrder)
+		order.DELETE("/:id/archive", h.UnarchiveOrder)
+		order.GET("/:id/risk", h.GetOrderRisk)
+		order.POST("/:id/review", h.ReviewOrder)
+		order.GET("/:id/timeline", h.GetOrderTimeline)
+		order.GET("/:id/invoice", h.GetOrderInvoice)
+		order.GET("/:id/shipment", h.GetOrderShipment)
+		order.PUT("/:id/shipment", h.ShipOrder)
+		order.GET("/:id/payments", h.GetOrderPayments)
+		order.POST("/:id/payments/capture", idempotent, h.CaptureOrderPayment)
+		order.POST("/:id/refunds/store-credit", idempotent, h.RefundOrderToStoreCredit)
+		order.GET("/:id/comments", h.GetOrderComments)
+		order.POST("/:id/comments", h.NewOrderComment)
 	}
 
-	port := getEnvOrDefault("SERVER_PORT", "8083")
-	log.Info("Order Service starting", zap.String("port", port))
-	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-	}
-	if err := server.ListenAndServe(); err != nil {
-		log.Panic("Server failed", zap.Error(err))
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+		internal.GET("/feature-flags/:key", flags.Handler())
+		internal.PUT("/feature-flags/:key", flags.Handler())
+		internal.GET("/purchases", h.FindPurchase)
+		// saga participant steps, driven by the saga orchestrator
+		internal.POST("/checkout/reservations", h.ReserveCheckoutStock)
+		internal.POST("/checkout/reservations/:reference/cancel", h.CancelCheckoutReservation)
+		internal.POST("/checkout/orders", h.CreateCheckoutOrder)
+		internal.POST("/orders/:id/capture", h.CaptureCheckoutPayment)
+		internal.POST("/orders/:id/cancel", h.CancelOrderInternal)
+		internal.POST("/orders/:id/comments", h.AddOrderNoteInternal)
+		internal.POST("/users/:userId/orders/cancel", h.CancelUserOrders)
 	}
-}
 
-func getEnvOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return def
+	return router, nil
 }
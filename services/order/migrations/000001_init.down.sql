@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS store_credit_entries;
+DROP TABLE IF EXISTS store_credit_balances;
+DROP TABLE IF EXISTS order_payments;
+DROP TABLE IF EXISTS shipments;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS risk_blocklist;
+DROP TABLE IF EXISTS checkout_reservations;
+DROP TABLE IF EXISTS order_idempotency_keys;
+DROP TABLE IF EXISTS order_comments;
+DROP TABLE IF EXISTS order_events;
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
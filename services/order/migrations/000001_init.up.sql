@@ -0,0 +1,201 @@
+-- Baseline schema, matching what GORM AutoMigrate created before versioned migrations.
+-- IF NOT EXISTS lets databases created that way adopt it without changes.
+
+CREATE TABLE IF NOT EXISTS orders (
+    id bigserial,
+    order_number text,
+    user_id bigint NOT NULL,
+    status text DEFAULT 'pending',
+    total_amount decimal DEFAULT 0,
+    currency varchar(3),
+    base_currency varchar(3),
+    exchange_rate decimal,
+    base_total_amount decimal,
+    discount_amount decimal NOT NULL DEFAULT 0,
+    gift_message text,
+    delivery_instructions text,
+    shipping_address jsonb,
+    billing_address jsonb,
+    client_ip text,
+    risk_score bigint NOT NULL DEFAULT 0,
+    risk_signals jsonb,
+    cancellation_reason text,
+    cancelled_by text,
+    archived_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_orders_client_ip ON orders (client_ip);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_number ON orders (order_number);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id bigserial,
+    order_id bigint NOT NULL,
+    product_id bigint NOT NULL,
+    quantity bigint NOT NULL,
+    price decimal NOT NULL,
+    subtotal decimal NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id bigserial,
+    order_id bigint NOT NULL,
+    order_item_id bigint,
+    source text NOT NULL,
+    code text,
+    description text,
+    amount decimal NOT NULL,
+    actor_id bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_orders_discounts FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+CREATE INDEX IF NOT EXISTS idx_order_discounts_order_id ON order_discounts (order_id);
+
+CREATE TABLE IF NOT EXISTS order_events (
+    id bigserial,
+    order_id bigint NOT NULL,
+    old_status text,
+    new_status text NOT NULL,
+    actor_id bigint,
+    actor_type text NOT NULL,
+    note text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_order_events_order_id ON order_events (order_id);
+
+CREATE TABLE IF NOT EXISTS order_comments (
+    id bigserial,
+    order_id bigint NOT NULL,
+    author_id bigint NOT NULL,
+    body text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_order_comments_order_id ON order_comments (order_id);
+
+CREATE TABLE IF NOT EXISTS order_idempotency_keys (
+    id bigserial,
+    user_id bigint NOT NULL,
+    idempotency_key text NOT NULL,
+    order_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_idempotency_user_key ON order_idempotency_keys (user_id,idempotency_key);
+
+CREATE TABLE IF NOT EXISTS checkout_reservations (
+    id bigserial,
+    reference text NOT NULL,
+    user_id bigint NOT NULL,
+    items jsonb NOT NULL,
+    status text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_checkout_reservations_expiry ON checkout_reservations (status,expires_at);
+CREATE INDEX IF NOT EXISTS idx_checkout_reservations_user_id ON checkout_reservations (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_checkout_reservations_reference ON checkout_reservations (reference);
+
+CREATE TABLE IF NOT EXISTS risk_blocklist (
+    id bigserial,
+    kind text NOT NULL,
+    value text NOT NULL,
+    reason text,
+    actor_id bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_blocklist_kind_value ON risk_blocklist (kind,value);
+
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id bigserial,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    source text NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    published_at timestamptz,
+    attempts bigint DEFAULT 0,
+    last_error text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_published_at ON outbox_messages (published_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_event_id ON outbox_messages (event_id);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id bigserial,
+    order_id bigint NOT NULL,
+    invoice_number text,
+    net_amount decimal NOT NULL,
+    tax_rate decimal NOT NULL,
+    tax_amount decimal NOT NULL,
+    total decimal NOT NULL,
+    content_type text NOT NULL,
+    content bytea,
+    issued_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_number ON invoices (invoice_number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_order_id ON invoices (order_id);
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id bigserial,
+    order_id bigint NOT NULL,
+    carrier text NOT NULL,
+    tracking_number text NOT NULL,
+    tracking_url text,
+    status text NOT NULL,
+    last_event text,
+    last_event_at timestamptz,
+    delivered_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_shipments_status ON shipments (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipments_carrier_tracking ON shipments (carrier,tracking_number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments (order_id);
+
+CREATE TABLE IF NOT EXISTS order_payments (
+    id bigserial,
+    order_id bigint NOT NULL,
+    method text NOT NULL,
+    status text NOT NULL,
+    amount decimal NOT NULL,
+    base_amount decimal NOT NULL,
+    reference text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_order_payments_order_id ON order_payments (order_id);
+
+CREATE TABLE IF NOT EXISTS store_credit_balances (
+    user_id bigint,
+    balance decimal NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS store_credit_entries (
+    id bigserial,
+    user_id bigint NOT NULL,
+    amount decimal NOT NULL,
+    reason text NOT NULL,
+    note text,
+    order_id bigint,
+    actor_id bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_store_credit_entries_order_id ON store_credit_entries (order_id);
+CREATE INDEX IF NOT EXISTS idx_store_credit_entries_user_id ON store_credit_entries (user_id);
@@ -0,0 +1,7 @@
+CREATE TABLE idempotency_records (
+    key text PRIMARY KEY,
+    record jsonb NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_deleted_at;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS updated_by;
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN created_by bigint,
+    ADD COLUMN updated_by bigint;
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at);
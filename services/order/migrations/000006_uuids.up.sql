@@ -0,0 +1,4 @@
+-- Existing rows get a random UUID; the services assign time-ordered UUIDv7s to new ones.
+
+ALTER TABLE orders ADD COLUMN uuid uuid NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX idx_orders_uuid ON orders (uuid);
@@ -0,0 +1,3 @@
+-- Bumped by every status change; transitions that send an older version fail with 409.
+
+ALTER TABLE orders ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,18 @@
+-- Object-level authorization policies read by pkg/authz; admins change them through
+-- /v1/order/authz/policies. The seeded rows keep the access rules the service had before:
+-- customers see their own orders, fulfillment and staff every order.
+
+CREATE TABLE authz_policies (
+    id bigserial PRIMARY KEY,
+    role text NOT NULL,
+    resource text NOT NULL,
+    action text NOT NULL,
+    condition text NOT NULL DEFAULT '' CHECK (condition IN ('', 'owner')),
+    effect text NOT NULL CHECK (effect IN ('allow', 'deny'))
+);
+
+INSERT INTO authz_policies (role, resource, action, condition, effect) VALUES
+    ('*', 'order', 'read', 'owner', 'allow'),
+    ('fulfillment', 'order', 'read', '', 'allow'),
+    ('staff', 'order', '*', '', 'allow'),
+    ('admin', '*', '*', '', 'allow');
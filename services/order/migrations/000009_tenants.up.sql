@@ -0,0 +1,6 @@
+-- Orders belong to the tenant they were placed in; orders placed before tenants existed
+-- belong to the default tenant. Order numbers derive from the row ID and stay unique across
+-- tenants.
+
+ALTER TABLE orders ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_orders_tenant_id ON orders (tenant_id);
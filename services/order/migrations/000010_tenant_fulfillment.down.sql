@@ -0,0 +1,10 @@
+ALTER TABLE outbox_messages DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_checkout_reservations_tenant_id;
+ALTER TABLE checkout_reservations DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_shipments_tenant_id;
+ALTER TABLE shipments DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_invoices_tenant_id;
+ALTER TABLE invoices DROP COLUMN IF EXISTS tenant_id;
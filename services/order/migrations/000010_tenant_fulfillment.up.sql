@@ -0,0 +1,14 @@
+-- Invoices and shipments belong to the tenant of their order, checkout reservations to the
+-- tenant they were made in; rows created before tenants existed belong to the default tenant.
+-- Order events carry the tenant of their order.
+
+ALTER TABLE invoices ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_invoices_tenant_id ON invoices (tenant_id);
+
+ALTER TABLE shipments ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_shipments_tenant_id ON shipments (tenant_id);
+
+ALTER TABLE checkout_reservations ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_checkout_reservations_tenant_id ON checkout_reservations (tenant_id);
+
+ALTER TABLE outbox_messages ADD COLUMN tenant_id text NOT NULL DEFAULT '';
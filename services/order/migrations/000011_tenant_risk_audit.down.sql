@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_audit_logs_tenant_id;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_risk_blocklist_kind_value;
+DELETE FROM risk_blocklist a USING risk_blocklist b WHERE a.kind = b.kind AND a.value = b.value AND a.id > b.id;
+ALTER TABLE risk_blocklist DROP COLUMN IF EXISTS tenant_id;
+CREATE UNIQUE INDEX idx_risk_blocklist_kind_value ON risk_blocklist (kind, value);
@@ -0,0 +1,9 @@
+-- Blocklist entries and audit entries belong to the tenant they were made in; rows created
+-- before tenants existed belong to the default tenant. A value is blocked per store.
+
+ALTER TABLE risk_blocklist ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+DROP INDEX IF EXISTS idx_risk_blocklist_kind_value;
+CREATE UNIQUE INDEX idx_risk_blocklist_kind_value ON risk_blocklist (tenant_id, kind, value);
+
+ALTER TABLE audit_logs ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_audit_logs_tenant_id ON audit_logs (tenant_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_invoices_number;
+CREATE UNIQUE INDEX idx_invoices_number ON invoices (invoice_number);
+
+DROP TABLE IF EXISTS invoice_counters;
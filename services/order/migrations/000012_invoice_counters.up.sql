@@ -0,0 +1,18 @@
+-- Invoice numbers run per tenant and year from a counter, rather than from the invoice ID.
+-- The counters start after the numbers already issued, and numbers are unique per tenant.
+
+CREATE TABLE invoice_counters (
+    tenant_id text NOT NULL,
+    year integer NOT NULL,
+    last_number bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, year)
+);
+
+INSERT INTO invoice_counters (tenant_id, year, last_number)
+SELECT tenant_id, extract(year FROM issued_at)::integer, max(substring(invoice_number FROM '[0-9]+$')::bigint)
+FROM invoices
+WHERE invoice_number IS NOT NULL
+GROUP BY 1, 2;
+
+DROP INDEX IF EXISTS idx_invoices_number;
+CREATE UNIQUE INDEX idx_invoices_number ON invoices (tenant_id, invoice_number);
@@ -0,0 +1,132 @@
+package repository
+
+//go:generate mockgen -source=invoice_repository.go -destination=mocks/invoice_repository.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Invoice struct {
+	ID          int       `gorm:"primaryKey"`
+	OrderID     int       `gorm:"column:order_id;uniqueIndex;not null"`
+	Number      string    `gorm:"column:invoice_number;uniqueIndex"`
+	NetAmount   float64   `gorm:"column:net_amount;not null"`
+	TaxRate     float64   `gorm:"column:tax_rate;not null"`
+	TaxAmount   float64   `gorm:"column:tax_amount;not null"`
+	Total       float64   `gorm:"column:total;not null"`
+	ContentType string    `gorm:"column:content_type;not null"`
+	Content     []byte    `gorm:"column:content"`
+	IssuedAt    time.Time `gorm:"column:issued_at;not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime:mili"`
+	psql.TenantModel
+}
+
+func (Invoice) TableName() string { return "invoices" }
+
+// InvoiceCounter is the last invoice number issued in a tenant and year
+type InvoiceCounter struct {
+	TenantID   string `gorm:"column:tenant_id;primaryKey"`
+	Year       int    `gorm:"column:year;primaryKey"`
+	LastNumber int    `gorm:"column:last_number;not null"`
+}
+
+func (InvoiceCounter) TableName() string { return "invoice_counters" }
+
+// InvoiceRenderFunc renders the stored document once the invoice number is assigned
+type InvoiceRenderFunc func(inv *domain.Invoice) ([]byte, error)
+
+type InvoiceRepositoryInterface interface {
+	GetByOrderID(ctx context.Context, orderID int) (*domain.Invoice, error)
+	Create(ctx context.Context, inv *domain.Invoice, render InvoiceRenderFunc) (*domain.Invoice, error)
+}
+
+type InvoiceRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewInvoiceRepository(db *gorm.DB, l *logger.Logger) InvoiceRepositoryInterface {
+	return &InvoiceRepository{DB: db, Logger: l}
+}
+
+func (r *InvoiceRepository) GetByOrderID(ctx context.Context, orderID int) (*domain.Invoice, error) {
+	var inv Invoice
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", orderID).First(&inv).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return invoiceToDomain(&inv), nil
+}
+
+// Create inserts the invoice and numbers it from the counter of its tenant and issue year,
+// locked in the same transaction. A failed insert rolls the counter back with it, so each
+// tenant's numbers run without gaps and start again every year.
+func (r *InvoiceRepository) Create(ctx context.Context, d *domain.Invoice, render InvoiceRenderFunc) (*domain.Invoice, error) {
+	inv := Invoice{
+		OrderID: d.OrderID, NetAmount: d.NetAmount, TaxRate: d.TaxRate, TaxAmount: d.TaxAmount,
+		Total: d.Total, ContentType: d.ContentType, IssuedAt: d.IssuedAt,
+	}
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&inv).Error; err != nil {
+			return err
+		}
+		number, err := nextInvoiceNumber(ctx, tx, inv.IssuedAt.Year())
+		if err != nil {
+			return err
+		}
+		inv.Number = fmt.Sprintf("INV-%d-%06d", inv.IssuedAt.Year(), number)
+		content, err := render(invoiceToDomain(&inv))
+		if err != nil {
+			return err
+		}
+		inv.Content = content
+		return tx.Model(&inv).Updates(map[string]interface{}{"invoice_number": inv.Number, "content": inv.Content}).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error creating invoice", zap.Int("orderID", d.OrderID), zap.Error(err))
+		// a concurrent request may have issued the invoice first
+		if existing, getErr := r.GetByOrderID(ctx, d.OrderID); getErr == nil {
+			return existing, nil
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return invoiceToDomain(&inv), nil
+}
+
+// nextInvoiceNumber takes the next number of the year from the counter of ctx's tenant, which
+// stays locked until tx ends
+func nextInvoiceNumber(ctx context.Context, tx *gorm.DB, year int) (int, error) {
+	tenantID := tenant.FromContext(ctx)
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	counter := InvoiceCounter{TenantID: tenantID, Year: year}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tenant_id = ? AND year = ?", tenantID, year).First(&counter).Error; err != nil {
+		return 0, err
+	}
+	counter.LastNumber++
+	if err := tx.Model(&counter).Update("last_number", counter.LastNumber).Error; err != nil {
+		return 0, err
+	}
+	return counter.LastNumber, nil
+}
+
+func invoiceToDomain(inv *Invoice) *domain.Invoice {
+	return &domain.Invoice{
+		ID: inv.ID, OrderID: inv.OrderID, Number: inv.Number, NetAmount: inv.NetAmount, TaxRate: inv.TaxRate,
+		TaxAmount: inv.TaxAmount, Total: inv.Total, ContentType: inv.ContentType, Content: inv.Content, IssuedAt: inv.IssuedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+// SnapshotTables are the tables `order snapshot` exports and restores, parents first. The
+// outbox, idempotency records, audit trail, feature flags and policies stay out: they are
+// transient or describe the deployment rather than the store.
+var SnapshotTables = []string{
+	"orders", "order_items", "order_discounts", "order_events", "order_comments", "order_idempotency_keys",
+	"invoices", "invoice_counters", "shipments", "order_payments", "store_credit_balances", "store_credit_entries",
+	"checkout_reservations", "risk_blocklist",
+}
+
+// RetentionRules purge the keys and reservations checkout only needs for a while, and strip
+// the personal details from orders abandoned before payment
+var RetentionRules = []retention.Rule{
+	{
+		Name:        "purge-idempotency-records",
+		Setting:     "RETENTION_IDEMPOTENCY_RECORDS_DAYS",
+		DefaultDays: 1,
+		Table:       "idempotency_records",
+		Key:         "key",
+		Column:      "expires_at",
+	},
+	{
+		Name:        "purge-order-idempotency-keys",
+		Setting:     "RETENTION_ORDER_IDEMPOTENCY_KEYS_DAYS",
+		DefaultDays: 30,
+		Table:       "order_idempotency_keys",
+		Column:      "created_at",
+	},
+	{
+		Name:        "purge-checkout-reservations",
+		Setting:     "RETENTION_CHECKOUT_RESERVATIONS_DAYS",
+		DefaultDays: 30,
+		Table:       "checkout_reservations",
+		Column:      "updated_at",
+		Where:       fmt.Sprintf("status <> '%s'", domain.ReservationStatusActive),
+	},
+	{
+		// irreversible, so off unless configured
+		Name:    "anonymize-abandoned-orders",
+		Setting: "RETENTION_ABANDONED_ORDERS_DAYS",
+		Table:   "orders",
+		Column:  "updated_at",
+		Where: fmt.Sprintf("status = '%s' AND cancellation_reason = '%s' AND (client_ip IS NOT NULL OR shipping_address IS NOT NULL "+
+			"OR billing_address IS NOT NULL OR gift_message IS NOT NULL OR delivery_instructions IS NOT NULL)",
+			domain.OrderStatusCancelled, domain.CancellationPaymentFailed),
+		Anonymize: map[string]string{
+			"client_ip":             "NULL",
+			"shipping_address":      "NULL",
+			"billing_address":       "NULL",
+			"gift_message":          "NULL",
+			"delivery_instructions": "NULL",
+		},
+	},
+}
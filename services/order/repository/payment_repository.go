@@ -0,0 +1,199 @@
+package repository
+
+//go:generate mockgen -source=payment_repository.go -destination=mocks/payment_repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Payment struct {
+	ID         int       `gorm:"primaryKey"`
+	OrderID    int       `gorm:"column:order_id;not null;index"`
+	Method     string    `gorm:"column:method;not null"`
+	Status     string    `gorm:"column:status;not null"`
+	Amount     float64   `gorm:"column:amount;not null"`
+	BaseAmount float64   `gorm:"column:base_amount;not null"`
+	Reference  string    `gorm:"column:reference"`
+	CreatedAt  time.Time `gorm:"autoCreateTime:mili"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime:mili"`
+}
+
+func (Payment) TableName() string { return "order_payments" }
+
+type StoreCreditBalance struct {
+	UserID    int       `gorm:"primaryKey;autoIncrement:false"`
+	Balance   float64   `gorm:"column:balance;not null;default:0"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:mili"`
+}
+
+func (StoreCreditBalance) TableName() string { return "store_credit_balances" }
+
+type StoreCreditEntry struct {
+	ID        int       `gorm:"primaryKey"`
+	UserID    int       `gorm:"column:user_id;not null;index"`
+	Amount    float64   `gorm:"column:amount;not null"`
+	Reason    string    `gorm:"column:reason;not null"`
+	Note      string    `gorm:"column:note"`
+	OrderID   *int      `gorm:"column:order_id;index"`
+	ActorID   int       `gorm:"column:actor_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (StoreCreditEntry) TableName() string { return "store_credit_entries" }
+
+type PaymentRepositoryInterface interface {
+	WithTx(tx *gorm.DB) PaymentRepositoryInterface
+	GetByOrderID(ctx context.Context, orderID int) (*[]domain.Payment, error)
+	Create(ctx context.Context, p *domain.Payment) (*domain.Payment, error)
+	UpdateStatus(ctx context.Context, id int, status domain.PaymentStatus, reference string) error
+}
+
+type StoreCreditRepositoryInterface interface {
+	WithTx(tx *gorm.DB) StoreCreditRepositoryInterface
+	GetBalance(ctx context.Context, userID int) (float64, error)
+	// GetBalanceForUpdate locks the user's balance row, creating it when missing
+	GetBalanceForUpdate(ctx context.Context, userID int) (float64, error)
+	// AddEntry records the movement and applies it to the balance; call it after GetBalanceForUpdate
+	AddEntry(ctx context.Context, e *domain.StoreCreditEntry) error
+	GetEntries(ctx context.Context, userID int, limit int) (*[]domain.StoreCreditEntry, error)
+	// GetRefundedAmount sums refund entries issued for the order
+	GetRefundedAmount(ctx context.Context, orderID int) (float64, error)
+}
+
+type PaymentRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewPaymentRepository(db *gorm.DB, l *logger.Logger) PaymentRepositoryInterface {
+	return &PaymentRepository{DB: db, Logger: l}
+}
+
+func (r *PaymentRepository) WithTx(tx *gorm.DB) PaymentRepositoryInterface {
+	return &PaymentRepository{DB: tx, Logger: r.Logger}
+}
+
+func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID int) (*[]domain.Payment, error) {
+	var payments []Payment
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", orderID).Order("id").Find(&payments).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Payment, len(payments))
+	for i, p := range payments {
+		result[i] = domain.Payment{
+			ID: p.ID, OrderID: p.OrderID, Method: domain.PaymentMethod(p.Method), Status: domain.PaymentStatus(p.Status),
+			Amount: p.Amount, BaseAmount: p.BaseAmount, Reference: p.Reference, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+		}
+	}
+	return &result, nil
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, d *domain.Payment) (*domain.Payment, error) {
+	p := Payment{
+		OrderID: d.OrderID, Method: string(d.Method), Status: string(d.Status),
+		Amount: d.Amount, BaseAmount: d.BaseAmount, Reference: d.Reference,
+	}
+	if err := psql.Conn(ctx, r.DB).Create(&p).Error; err != nil {
+		r.Logger.Error("Error recording payment", zap.Int("orderID", d.OrderID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	d.ID, d.CreatedAt, d.UpdatedAt = p.ID, p.CreatedAt, p.UpdatedAt
+	return d, nil
+}
+
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, id int, status domain.PaymentStatus, reference string) error {
+	fields := map[string]interface{}{"status": string(status)}
+	if reference != "" {
+		fields["reference"] = reference
+	}
+	if err := psql.Conn(ctx, r.DB).Model(&Payment{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Logger.Error("Error updating payment", zap.Int("id", id), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+type StoreCreditRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewStoreCreditRepository(db *gorm.DB, l *logger.Logger) StoreCreditRepositoryInterface {
+	return &StoreCreditRepository{DB: db, Logger: l}
+}
+
+func (r *StoreCreditRepository) WithTx(tx *gorm.DB) StoreCreditRepositoryInterface {
+	return &StoreCreditRepository{DB: tx, Logger: r.Logger}
+}
+
+func (r *StoreCreditRepository) GetBalance(ctx context.Context, userID int) (float64, error) {
+	var b StoreCreditBalance
+	err := psql.Conn(ctx, r.DB).Where("user_id = ?", userID).First(&b).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, psql.TranslateError(err)
+	}
+	return b.Balance, nil
+}
+
+func (r *StoreCreditRepository) GetBalanceForUpdate(ctx context.Context, userID int) (float64, error) {
+	if err := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&StoreCreditBalance{UserID: userID}).Error; err != nil {
+		r.Logger.Error("Error creating store credit balance", zap.Int("userID", userID), zap.Error(err))
+		return 0, psql.TranslateError(err)
+	}
+	var b StoreCreditBalance
+	if err := psql.Conn(ctx, r.DB).Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&b).Error; err != nil {
+		return 0, psql.TranslateError(err)
+	}
+	return b.Balance, nil
+}
+
+func (r *StoreCreditRepository) AddEntry(ctx context.Context, d *domain.StoreCreditEntry) error {
+	e := StoreCreditEntry{UserID: d.UserID, Amount: d.Amount, Reason: d.Reason, Note: d.Note, OrderID: d.OrderID, ActorID: d.ActorID}
+	if err := psql.Conn(ctx, r.DB).Create(&e).Error; err != nil {
+		r.Logger.Error("Error recording store credit entry", zap.Int("userID", d.UserID), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	if err := psql.Conn(ctx, r.DB).Model(&StoreCreditBalance{}).Where("user_id = ?", d.UserID).
+		Update("balance", gorm.Expr("balance + ?", d.Amount)).Error; err != nil {
+		r.Logger.Error("Error updating store credit balance", zap.Int("userID", d.UserID), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	d.ID, d.CreatedAt = e.ID, e.CreatedAt
+	return nil
+}
+
+func (r *StoreCreditRepository) GetEntries(ctx context.Context, userID int, limit int) (*[]domain.StoreCreditEntry, error) {
+	var entries []StoreCreditEntry
+	if err := psql.Conn(ctx, r.DB).Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.StoreCreditEntry, len(entries))
+	for i, e := range entries {
+		result[i] = domain.StoreCreditEntry{
+			ID: e.ID, UserID: e.UserID, Amount: e.Amount, Reason: e.Reason, Note: e.Note,
+			OrderID: e.OrderID, ActorID: e.ActorID, CreatedAt: e.CreatedAt,
+		}
+	}
+	return &result, nil
+}
+
+func (r *StoreCreditRepository) GetRefundedAmount(ctx context.Context, orderID int) (float64, error) {
+	var total float64
+	if err := psql.Conn(ctx, r.DB).Model(&StoreCreditEntry{}).Where("order_id = ? AND reason = ?", orderID, domain.StoreCreditReasonRefund).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error; err != nil {
+		return 0, psql.TranslateError(err)
+	}
+	return total, nil
+}
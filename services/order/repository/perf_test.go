@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+// BenchmarkOrdersToDomain maps a page of 20 orders of 3 items each, with an item and an order
+// discount
+func BenchmarkOrdersToDomain(b *testing.B) {
+	b.ReportAllocs()
+	now := time.Now()
+	address := &domain.Address{Name: "Budi", Line1: "1 Test Street", City: "Jakarta", PostalCode: "10110", Country: "ID"}
+	orders := make([]Order, 20)
+	for i := range orders {
+		itemID := i*3 + 1
+		orders[i] = Order{ID: i + 1, Number: "ORD-BENCH", UserID: 7, Status: "paid", TotalAmount: 71.5, DiscountAmount: 5,
+			Currency: "IDR", BaseCurrency: "IDR", ExchangeRate: 1, BaseTotalAmount: 71.5, ShippingAddress: address, BillingAddress: address,
+			Items: []OrderItem{
+				{ID: itemID, OrderID: i + 1, ProductID: 1, Quantity: 1, Price: 25.5, Subtotal: 25.5},
+				{ID: itemID + 1, OrderID: i + 1, ProductID: 2, Quantity: 2, Price: 10, Subtotal: 20},
+				{ID: itemID + 2, OrderID: i + 1, ProductID: 3, Quantity: 1, Price: 31, Subtotal: 31},
+			},
+			Discounts: []OrderDiscount{
+				{ID: i*2 + 1, OrderID: i + 1, OrderItemID: &itemID, Source: "coupon", Code: "TEN", Amount: 2.5, CreatedAt: now},
+				{ID: i*2 + 2, OrderID: i + 1, Source: "promotion", Code: "SALE", Amount: 2.5, CreatedAt: now},
+			},
+			BaseModel: psql.BaseModel{UUID: "5f0c7a8e-3f5c-4d52-9b7e-0d6f3c1e2a47", CreatedAt: now, UpdatedAt: now},
+		}
+	}
+	for b.Loop() {
+		ordersToDomain(orders)
+	}
+}
@@ -1,25 +1,53 @@
 package repository
 
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
 	"ecommerce-microservice-go/services/order/domain"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GORM models
 type Order struct {
-	ID          int         `gorm:"primaryKey"`
-	UserID      int         `gorm:"column:user_id;not null"`
-	Status      string      `gorm:"column:status;default:pending"`
-	TotalAmount float64     `gorm:"column:total_amount;default:0"`
-	Items       []OrderItem `gorm:"foreignKey:OrderID"`
-	CreatedAt   time.Time   `gorm:"autoCreateTime:mili"`
-	UpdatedAt   time.Time   `gorm:"autoUpdateTime:mili"`
+	ID                 int                 `gorm:"primaryKey"`
+	Number             string              `gorm:"column:order_number;uniqueIndex"`
+	UserID             int                 `gorm:"column:user_id;not null"`
+	Status             string              `gorm:"column:status;default:pending"`
+	TotalAmount        float64             `gorm:"column:total_amount;default:0"`
+	Currency           string              `gorm:"column:currency;size:3"`
+	BaseCurrency       string              `gorm:"column:base_currency;size:3"`
+	ExchangeRate       float64             `gorm:"column:exchange_rate"`
+	BaseTotalAmount    float64             `gorm:"column:base_total_amount"`
+	DiscountAmount     float64             `gorm:"column:discount_amount;not null;default:0"`
+	GiftMessage        string              `gorm:"column:gift_message"`
+	DeliveryIns        string              `gorm:"column:delivery_instructions"`
+	ShippingAddress    *domain.Address     `gorm:"column:shipping_address;type:jsonb;serializer:json"`
+	BillingAddress     *domain.Address     `gorm:"column:billing_address;type:jsonb;serializer:json"`
+	ClientIP           string              `gorm:"column:client_ip;index"`
+	RiskScore          int                 `gorm:"column:risk_score;not null;default:0"`
+	RiskSignals        []domain.RiskSignal `gorm:"column:risk_signals;type:jsonb;serializer:json"`
+	CancellationReason string              `gorm:"column:cancellation_reason"`
+	CancelledBy        string              `gorm:"column:cancelled_by"`
+	ArchivedAt         *time.Time          `gorm:"column:archived_at"`
+	Version            int                 `gorm:"column:version;not null;default:1"`
+	Items              []OrderItem         `gorm:"foreignKey:OrderID"`
+	Discounts          []OrderDiscount     `gorm:"foreignKey:OrderID"`
+	psql.BaseModel
+	psql.TenantModel
 }
 
 func (Order) TableName() string { return "orders" }
@@ -35,14 +63,92 @@ type OrderItem struct {
 
 func (OrderItem) TableName() string { return "order_items" }
 
+type OrderDiscount struct {
+	ID          int       `gorm:"primaryKey"`
+	OrderID     int       `gorm:"column:order_id;not null;index"`
+	OrderItemID *int      `gorm:"column:order_item_id"`
+	Source      string    `gorm:"column:source;not null"`
+	Code        string    `gorm:"column:code"`
+	Description string    `gorm:"column:description"`
+	Amount      float64   `gorm:"column:amount;not null"`
+	ActorID     int       `gorm:"column:actor_id"`
+	CreatedAt   time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (OrderDiscount) TableName() string { return "order_discounts" }
+
+type OrderEvent struct {
+	ID        int       `gorm:"primaryKey"`
+	OrderID   int       `gorm:"column:order_id;not null;index"`
+	OldStatus string    `gorm:"column:old_status"`
+	NewStatus string    `gorm:"column:new_status;not null"`
+	ActorID   int       `gorm:"column:actor_id"`
+	ActorType string    `gorm:"column:actor_type;not null"`
+	Note      string    `gorm:"column:note"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (OrderEvent) TableName() string { return "order_events" }
+
+type OrderComment struct {
+	ID        int       `gorm:"primaryKey"`
+	OrderID   int       `gorm:"column:order_id;not null;index"`
+	AuthorID  int       `gorm:"column:author_id;not null"`
+	Body      string    `gorm:"column:body;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (OrderComment) TableName() string { return "order_comments" }
+
+type OrderIdempotencyKey struct {
+	ID        int       `gorm:"primaryKey"`
+	UserID    int       `gorm:"column:user_id;not null;uniqueIndex:idx_order_idempotency_user_key"`
+	Key       string    `gorm:"column:idempotency_key;not null;uniqueIndex:idx_order_idempotency_user_key"`
+	OrderID   int       `gorm:"column:order_id;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (OrderIdempotencyKey) TableName() string { return "order_idempotency_keys" }
+
 // Interfaces
 
 type OrderRepositoryInterface interface {
-	GetAll() (*[]domain.Order, error)
-	GetByID(id int) (*domain.Order, error)
-	GetByUserID(userID int) (*[]domain.Order, error)
-	Create(order *domain.Order) (*domain.Order, error)
-	UpdateStatus(id int, status string) (*domain.Order, error)
+	// WithTx returns a repository bound to the transaction started by psql.TxManager
+	WithTx(tx *gorm.DB) OrderRepositoryInterface
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Order, int64, error)
+	GetByID(ctx context.Context, id int) (*domain.Order, error)
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.Order, error)
+	GetByNumber(ctx context.Context, number string) (*domain.Order, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	SearchByNumber(ctx context.Context, prefix string, userID int) (*[]domain.Order, error)
+	// SearchPaginated returns the page of orders matching the search, only the user's unless userID is 0
+	SearchPaginated(ctx context.Context, s pagination.Search, userID int) (*[]domain.Order, int64, error)
+	GetByUserID(ctx context.Context, userID int, includeArchived bool) (*[]domain.Order, error)
+	GetPageByUserID(ctx context.Context, userID int, includeArchived bool, p pagination.Params) (*[]domain.Order, int64, error)
+	// SetArchivedAt archives the order, or restores it when at is nil
+	SetArchivedAt(ctx context.Context, id int, at *time.Time) (*domain.Order, error)
+	// GetPendingIDsUnchangedSince finds orders that have been pending since before, counting
+	// from their creation or from approval after a risk review
+	GetPendingIDsUnchangedSince(ctx context.Context, before time.Time, limit int) ([]int, error)
+	// GetByStatus returns the oldest orders in the status first
+	GetByStatus(ctx context.Context, status domain.OrderStatus, limit int) (*[]domain.Order, error)
+	// CountCreatedSince counts orders placed since the given time by the user and from the IP
+	CountCreatedSince(ctx context.Context, userID int, clientIP string, since time.Time) (byUser int, byIP int, err error)
+	// FindPurchase returns the user's latest paid, shipped or delivered order containing the product
+	FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error)
+	GetMetrics(ctx context.Context, filter domain.OrderMetricsFilter) (*[]domain.OrderMetricsRow, error)
+	ExportInBatches(ctx context.Context, filter domain.OrderExportFilter, batchSize int, fn func(batch *[]domain.Order) error) error
+	Create(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	// UpdateStatus changes the status only while the order is still at version
+	UpdateStatus(ctx context.Context, id, version int, status domain.OrderStatus) (*domain.Order, error)
+	SetCancellation(ctx context.Context, id int, reason domain.CancellationReason, actorType string) error
+	AddStatusEvent(ctx context.Context, e *domain.OrderStatusEvent) error
+	GetTimeline(ctx context.Context, orderID int) (*[]domain.OrderStatusEvent, error)
+	SaveOutboxEvent(ctx context.Context, event *events.Event) error
+	GetOrderIDByIdempotencyKey(ctx context.Context, userID int, key string) (int, error)
+	SaveIdempotencyKey(ctx context.Context, userID int, key string, orderID int) error
+	AddComment(ctx context.Context, c *domain.OrderComment) (*domain.OrderComment, error)
+	GetComments(ctx context.Context, orderID int) (*[]domain.OrderComment, error)
 }
 
 type Repository struct {
@@ -54,67 +160,434 @@ func NewOrderRepository(db *gorm.DB, l *logger.Logger) OrderRepositoryInterface
 	return &Repository{DB: db, Logger: l}
 }
 
-func (r *Repository) GetAll() (*[]domain.Order, error) {
+func (r *Repository) WithTx(tx *gorm.DB) OrderRepositoryInterface {
+	return &Repository{DB: tx, Logger: r.Logger}
+}
+
+func (r *Repository) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Order, int64, error) {
 	var orders []Order
-	if err := r.DB.Preload("Items").Find(&orders).Error; err != nil {
+	total, err := pagination.Find(psql.Conn(ctx, r.DB).Model(&Order{}).Preload("Items").Preload("Discounts"), p, &orders)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	return ordersToDomain(orders), total, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int) (*domain.Order, error) {
+	var o Order
+	if err := psql.Conn(ctx, r.DB).Preload("Items").Preload("Discounts").Where("id = ?", id).First(&o).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
-	return ordersToDomain(orders), nil
+	return orderToDomain(&o), nil
 }
 
-func (r *Repository) GetByID(id int) (*domain.Order, error) {
+// GetByIDForUpdate locks the order row until the surrounding transaction ends
+func (r *Repository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Order, error) {
 	var o Order
-	if err := r.DB.Preload("Items").Where("id = ?", id).First(&o).Error; err != nil {
+	if err := psql.Conn(ctx, r.DB).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&o).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", id).Find(&o.Items).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", id).Find(&o.Discounts).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
 	return orderToDomain(&o), nil
 }
 
-func (r *Repository) GetByUserID(userID int) (*[]domain.Order, error) {
-	var orders []Order
-	if err := r.DB.Preload("Items").Where("user_id = ?", userID).Find(&orders).Error; err != nil {
+func (r *Repository) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
+	var o Order
+	if err := psql.Conn(ctx, r.DB).Preload("Items").Preload("Discounts").Where("order_number = ?", number).First(&o).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
 		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
+	return orderToDomain(&o), nil
+}
+
+func (r *Repository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(psql.Conn(ctx, r.DB), &Order{}, publicID)
+}
+
+// SearchByNumber matches order numbers by prefix; userID 0 searches every user's orders
+func (r *Repository) SearchByNumber(ctx context.Context, prefix string, userID int) (*[]domain.Order, error) {
+	var orders []Order
+	query := psql.Conn(ctx, r.DB).Preload("Items").Preload("Discounts").Where("order_number LIKE ?", escapeLike(prefix)+"%")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Order("id DESC").Limit(50).Find(&orders).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
 	return ordersToDomain(orders), nil
 }
 
-func (r *Repository) Create(d *domain.Order) (*domain.Order, error) {
-	o := fromDomain(d)
-	if err := r.DB.Create(o).Error; err != nil {
-		r.Logger.Error("Error creating order", zap.Error(err))
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+func (r *Repository) SearchPaginated(ctx context.Context, s pagination.Search, userID int) (*[]domain.Order, int64, error) {
+	var orders []Order
+	query := psql.Conn(ctx, r.DB).Model(&Order{}).Preload("Items").Preload("Discounts")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	total, err := pagination.FindSearch(query, s, &orders)
+	if err != nil {
+		r.Logger.Error("Error searching orders", zap.Error(err))
+		return nil, 0, psql.TranslateError(err)
+	}
+	return ordersToDomain(orders), total, nil
+}
+
+func (r *Repository) GetByUserID(ctx context.Context, userID int, includeArchived bool) (*[]domain.Order, error) {
+	var orders []Order
+	if err := r.userOrders(ctx, userID, includeArchived).Find(&orders).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return ordersToDomain(orders), nil
+}
+
+func (r *Repository) GetPageByUserID(ctx context.Context, userID int, includeArchived bool, p pagination.Params) (*[]domain.Order, int64, error) {
+	var orders []Order
+	total, err := pagination.Find(r.userOrders(ctx, userID, includeArchived), p, &orders)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	return ordersToDomain(orders), total, nil
+}
+
+func (r *Repository) userOrders(ctx context.Context, userID int, includeArchived bool) *gorm.DB {
+	query := psql.Conn(ctx, r.DB).Model(&Order{}).Preload("Items").Preload("Discounts").Where("user_id = ?", userID)
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	return query
+}
+
+func (r *Repository) SetArchivedAt(ctx context.Context, id int, at *time.Time) (*domain.Order, error) {
+	// UpdateColumn keeps updated_at, which times the payment window of pending orders
+	if err := psql.Conn(ctx, r.DB).Model(&Order{}).Where("id = ?", id).UpdateColumn("archived_at", at).Error; err != nil {
+		r.Logger.Error("Error archiving order", zap.Int("id", id), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+var metricsTruncUnits = map[domain.MetricsGranularity]string{
+	domain.MetricsByDay:   "day",
+	domain.MetricsByWeek:  "week",
+	domain.MetricsByMonth: "month",
+}
+
+// GetMetrics aggregates order counts and amounts per period and status in the database
+func (r *Repository) GetMetrics(ctx context.Context, filter domain.OrderMetricsFilter) (*[]domain.OrderMetricsRow, error) {
+	unit, ok := metricsTruncUnits[filter.GroupBy]
+	if !ok {
+		return nil, domainErrors.NewAppError(errors.New("invalid metrics granularity"), domainErrors.ValidationError)
+	}
+	period := fmt.Sprintf("date_trunc('%s', created_at)", unit)
+	query := psql.Conn(ctx, r.DB).Model(&Order{}).
+		Select(period + " AS period_start, status, COALESCE(cancellation_reason, '') AS cancellation_reason, " +
+			"COALESCE(cancelled_by, '') AS cancelled_by, COUNT(*) AS orders, COALESCE(SUM(base_total_amount), 0) AS amount")
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	var rows []struct {
+		PeriodStart        time.Time
+		Status             string
+		CancellationReason string
+		CancelledBy        string
+		Orders             int64
+		Amount             float64
+	}
+	if err := query.Group(period + ", status, cancellation_reason, cancelled_by").Order("period_start").Scan(&rows).Error; err != nil {
+		r.Logger.Error("Error aggregating order metrics", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.OrderMetricsRow, len(rows))
+	for i, row := range rows {
+		result[i] = domain.OrderMetricsRow{
+			PeriodStart: row.PeriodStart, Status: domain.OrderStatus(row.Status), CancellationReason: domain.CancellationReason(row.CancellationReason),
+			CancelledBy: row.CancelledBy, Orders: row.Orders, Amount: row.Amount,
+		}
+	}
+	return &result, nil
+}
+
+// ExportInBatches walks the matching orders in primary key order, loading batchSize orders
+// with their items at a time so large exports never sit in memory at once.
+func (r *Repository) ExportInBatches(ctx context.Context, filter domain.OrderExportFilter, batchSize int, fn func(batch *[]domain.Order) error) error {
+	query := psql.Conn(ctx, r.DB).Preload("Items").Preload("Discounts")
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	var orders []Order
+	if err := query.FindInBatches(&orders, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(ordersToDomain(orders))
+	}).Error; err != nil {
+		r.Logger.Error("Error exporting orders", zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetPendingIDsUnchangedSince(ctx context.Context, before time.Time, limit int) ([]int, error) {
+	var ids []int
+	if err := psql.Conn(ctx, r.DB).Model(&Order{}).Where("status = ? AND updated_at < ?", string(domain.OrderStatusPending), before).
+		Order("id").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		r.Logger.Error("Error loading expired pending orders", zap.Error(err))
+		return nil, psql.TranslateError(err)
 	}
-	// Reload with items
-	var created Order
-	r.DB.Preload("Items").Where("id = ?", o.ID).First(&created)
-	return orderToDomain(&created), nil
+	return ids, nil
 }
 
-func (r *Repository) UpdateStatus(id int, status string) (*domain.Order, error) {
+func (r *Repository) GetByStatus(ctx context.Context, status domain.OrderStatus, limit int) (*[]domain.Order, error) {
+	var orders []Order
+	if err := psql.Conn(ctx, r.DB).Preload("Items").Preload("Discounts").Where("status = ?", string(status)).Order("id").Limit(limit).Find(&orders).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return ordersToDomain(orders), nil
+}
+
+func (r *Repository) CountCreatedSince(ctx context.Context, userID int, clientIP string, since time.Time) (int, int, error) {
+	var byUser, byIP int64
+	if err := psql.Conn(ctx, r.DB).Model(&Order{}).Where("user_id = ? AND created_at >= ?", userID, since).Count(&byUser).Error; err != nil {
+		return 0, 0, err
+	}
+	if clientIP != "" {
+		if err := psql.Conn(ctx, r.DB).Model(&Order{}).Where("client_ip = ? AND created_at >= ?", clientIP, since).Count(&byIP).Error; err != nil {
+			return 0, 0, err
+		}
+	}
+	return int(byUser), int(byIP), nil
+}
+
+func (r *Repository) FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error) {
 	var o Order
-	if err := r.DB.Where("id = ?", id).First(&o).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	err := psql.Conn(ctx, r.DB).
+		Where("user_id = ? AND status IN ?", userID, []domain.OrderStatus{domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered}).
+		Where("EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = ?)", productID).
+		Order("created_at DESC").First(&o).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
 		}
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return nil, psql.TranslateError(err)
 	}
-	if err := r.DB.Model(&o).Update("status", status).Error; err != nil {
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	return &domain.Purchase{OrderID: o.ID, OrderNumber: o.Number, Status: domain.OrderStatus(o.Status), PurchasedAt: o.CreatedAt}, nil
+}
+
+func (r *Repository) Create(ctx context.Context, d *domain.Order) (*domain.Order, error) {
+	o := fromDomain(d)
+	if err := psql.Conn(ctx, r.DB).Create(o).Error; err != nil {
+		r.Logger.Error("Error creating order", zap.Error(err))
+		return nil, psql.TranslateError(err)
 	}
-	r.DB.Preload("Items").Where("id = ?", id).First(&o)
-	return orderToDomain(&o), nil
+	if err := psql.Conn(ctx, r.DB).Model(o).Update("order_number", formatOrderNumber(o.CreatedAt, o.ID)).Error; err != nil {
+		r.Logger.Error("Error assigning order number", zap.Int("id", o.ID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	// discounts are inserted once the items have IDs to point at
+	discounts := discountsFromDomain(o.ID, nil, d.Discounts)
+	for i, it := range d.Items {
+		discounts = append(discounts, discountsFromDomain(o.ID, &o.Items[i].ID, it.Discounts)...)
+	}
+	if len(discounts) > 0 {
+		if err := psql.Conn(ctx, r.DB).Create(&discounts).Error; err != nil {
+			r.Logger.Error("Error recording order discounts", zap.Int("id", o.ID), zap.Error(err))
+			return nil, psql.TranslateError(err)
+		}
+	}
+	return r.GetByID(ctx, o.ID)
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, id, version int, status domain.OrderStatus) (*domain.Order, error) {
+	if err := psql.UpdateVersioned[Order](psql.Conn(ctx, r.DB), id, version, map[string]any{"status": string(status)}); err != nil {
+		r.Logger.Error("Error updating order status", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *Repository) SetCancellation(ctx context.Context, id int, reason domain.CancellationReason, actorType string) error {
+	if err := psql.Conn(ctx, r.DB).Model(&Order{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"cancellation_reason": string(reason), "cancelled_by": actorType}).Error; err != nil {
+		r.Logger.Error("Error recording order cancellation", zap.Int("id", id), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *Repository) AddStatusEvent(ctx context.Context, d *domain.OrderStatusEvent) error {
+	e := OrderEvent{
+		OrderID: d.OrderID, OldStatus: string(d.OldStatus), NewStatus: string(d.NewStatus),
+		ActorID: d.ActorID, ActorType: d.ActorType, Note: d.Note,
+	}
+	if e.ActorType == "" {
+		e.ActorType = domain.ActorSystem
+	}
+	if err := psql.Conn(ctx, r.DB).Create(&e).Error; err != nil {
+		r.Logger.Error("Error recording order status event", zap.Int("orderID", d.OrderID), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *Repository) GetTimeline(ctx context.Context, orderID int) (*[]domain.OrderStatusEvent, error) {
+	var rows []OrderEvent
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", orderID).Order("created_at, id").Find(&rows).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.OrderStatusEvent, len(rows))
+	for i, e := range rows {
+		result[i] = domain.OrderStatusEvent{
+			ID: e.ID, OrderID: e.OrderID, OldStatus: domain.OrderStatus(e.OldStatus), NewStatus: domain.OrderStatus(e.NewStatus),
+			ActorID: e.ActorID, ActorType: e.ActorType, Note: e.Note, CreatedAt: e.CreatedAt,
+		}
+	}
+	return &result, nil
+}
+
+func (r *Repository) AddComment(ctx context.Context, d *domain.OrderComment) (*domain.OrderComment, error) {
+	c := OrderComment{OrderID: d.OrderID, AuthorID: d.AuthorID, Body: d.Body}
+	if err := psql.Conn(ctx, r.DB).Create(&c).Error; err != nil {
+		r.Logger.Error("Error creating order comment", zap.Int("orderID", d.OrderID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return &domain.OrderComment{ID: c.ID, OrderID: c.OrderID, AuthorID: c.AuthorID, Body: c.Body, CreatedAt: c.CreatedAt}, nil
+}
+
+func (r *Repository) GetComments(ctx context.Context, orderID int) (*[]domain.OrderComment, error) {
+	var rows []OrderComment
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", orderID).Order("created_at, id").Find(&rows).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.OrderComment, len(rows))
+	for i, c := range rows {
+		result[i] = domain.OrderComment{ID: c.ID, OrderID: c.OrderID, AuthorID: c.AuthorID, Body: c.Body, CreatedAt: c.CreatedAt}
+	}
+	return &result, nil
+}
+
+// SaveOutboxEvent stores the event for the relay; call it within the write transaction
+func (r *Repository) SaveOutboxEvent(ctx context.Context, event *events.Event) error {
+	if err := events.SaveToOutbox(psql.Conn(ctx, r.DB), event); err != nil {
+		r.Logger.Error("Error saving outbox event", zap.String("type", event.Type), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+// GetOrderIDByIdempotencyKey returns 0 when the key has not been used by the user
+func (r *Repository) GetOrderIDByIdempotencyKey(ctx context.Context, userID int, key string) (int, error) {
+	var k OrderIdempotencyKey
+	err := psql.Conn(ctx, r.DB).Where("user_id = ? AND idempotency_key = ?", userID, key).First(&k).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, psql.TranslateError(err)
+	}
+	return k.OrderID, nil
+}
+
+// SaveIdempotencyKey fails with ResourceAlreadyExists when a concurrent request claimed the key first
+func (r *Repository) SaveIdempotencyKey(ctx context.Context, userID int, key string, orderID int) error {
+	tx := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&OrderIdempotencyKey{UserID: userID, Key: key, OrderID: orderID})
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppError(errors.New("a request with this Idempotency-Key is already being processed"), domainErrors.ResourceAlreadyExists)
+	}
+	return nil
+}
+
+// formatOrderNumber derives the customer-facing number from the row ID, e.g. ORD-2024-000123
+func formatOrderNumber(createdAt time.Time, id int) string {
+	return fmt.Sprintf("ORD-%d-%06d", createdAt.Year(), id)
+}
+
+// BackfillOrderNumbers assigns numbers to orders created before order numbers existed
+func BackfillOrderNumbers(db *gorm.DB, loggerInstance *logger.Logger) error {
+	tx := db.Exec(`UPDATE orders SET order_number = 'ORD-' || EXTRACT(YEAR FROM created_at)::int || '-' || LPAD(id::text, 6, '0')
+		WHERE order_number IS NULL OR order_number = ''`)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected > 0 {
+		loggerInstance.Info("Backfilled order numbers", zap.Int64("orders", tx.RowsAffected))
+	}
+	return nil
+}
+
+// BackfillCurrencies marks orders placed before multi-currency support as base currency orders.
+// The currency columns are nullable so they could be added to tables that already held orders.
+func BackfillCurrencies(db *gorm.DB, baseCurrency string, loggerInstance *logger.Logger) error {
+	tx := db.Exec(`UPDATE orders SET currency = ?, base_currency = ?, exchange_rate = 1, base_total_amount = total_amount
+		WHERE currency IS NULL OR currency = ''`, baseCurrency, baseCurrency)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected > 0 {
+		loggerInstance.Info("Backfilled order currencies", zap.Int64("orders", tx.RowsAffected))
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // Mappers
 func orderToDomain(o *Order) *domain.Order {
+	itemDiscounts := map[int][]domain.OrderDiscount{}
+	var orderDiscounts []domain.OrderDiscount
+	for _, d := range o.Discounts {
+		discount := domain.OrderDiscount{
+			ID: d.ID, OrderID: d.OrderID, OrderItemID: d.OrderItemID, Source: domain.DiscountSource(d.Source),
+			Code: d.Code, Description: d.Description, Amount: d.Amount, ActorID: d.ActorID, CreatedAt: d.CreatedAt,
+		}
+		if d.OrderItemID != nil {
+			itemDiscounts[*d.OrderItemID] = append(itemDiscounts[*d.OrderItemID], discount)
+		} else {
+			orderDiscounts = append(orderDiscounts, discount)
+		}
+	}
 	items := make([]domain.OrderItem, len(o.Items))
 	for i, it := range o.Items {
-		items[i] = domain.OrderItem{ID: it.ID, OrderID: it.OrderID, ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Subtotal: it.Subtotal}
+		items[i] = domain.OrderItem{
+			ID: it.ID, OrderID: it.OrderID, ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Subtotal: it.Subtotal,
+			Discounts: itemDiscounts[it.ID],
+		}
+		for _, d := range items[i].Discounts {
+			items[i].DiscountAmount += d.Amount
+		}
+	}
+	return &domain.Order{
+		ID: o.ID, UUID: o.UUID, Number: o.Number, UserID: o.UserID, Status: domain.OrderStatus(o.Status), TotalAmount: o.TotalAmount, Items: items,
+		Currency: o.Currency, BaseCurrency: o.BaseCurrency, ExchangeRate: o.ExchangeRate, BaseTotalAmount: o.BaseTotalAmount,
+		SubtotalAmount: o.TotalAmount + o.DiscountAmount, DiscountAmount: o.DiscountAmount, Discounts: orderDiscounts,
+		Notes:           domain.OrderNotes{GiftMessage: o.GiftMessage, DeliveryInstructions: o.DeliveryIns},
+		ShippingAddress: o.ShippingAddress, BillingAddress: o.BillingAddress, ClientIP: o.ClientIP,
+		RiskScore: o.RiskScore, RiskSignals: o.RiskSignals, ArchivedAt: o.ArchivedAt, Version: o.Version, Tenant: o.TenantID,
+		CancellationReason: domain.CancellationReason(o.CancellationReason), CancelledBy: o.CancelledBy,
+		CreatedAt: o.CreatedAt, UpdatedAt: o.UpdatedAt,
 	}
-	return &domain.Order{ID: o.ID, UserID: o.UserID, Status: domain.OrderStatus(o.Status), TotalAmount: o.TotalAmount, Items: items, CreatedAt: o.CreatedAt, UpdatedAt: o.UpdatedAt}
 }
 
 func ordersToDomain(orders []Order) *[]domain.Order {
@@ -130,5 +603,22 @@ func fromDomain(d *domain.Order) *Order {
 	for i, it := range d.Items {
 		items[i] = OrderItem{ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price, Subtotal: it.Subtotal}
 	}
-	return &Order{UserID: d.UserID, Status: string(d.Status), TotalAmount: d.TotalAmount, Items: items}
+	return &Order{
+		UserID: d.UserID, Status: string(d.Status), TotalAmount: d.TotalAmount, DiscountAmount: d.DiscountAmount, Items: items,
+		Currency: d.Currency, BaseCurrency: d.BaseCurrency, ExchangeRate: d.ExchangeRate, BaseTotalAmount: d.BaseTotalAmount,
+		GiftMessage: d.Notes.GiftMessage, DeliveryIns: d.Notes.DeliveryInstructions,
+		ShippingAddress: d.ShippingAddress, BillingAddress: d.BillingAddress, ClientIP: d.ClientIP,
+		RiskScore: d.RiskScore, RiskSignals: d.RiskSignals,
+	}
+}
+
+func discountsFromDomain(orderID int, orderItemID *int, discounts []domain.OrderDiscount) []OrderDiscount {
+	result := make([]OrderDiscount, len(discounts))
+	for i, d := range discounts {
+		result[i] = OrderDiscount{
+			OrderID: orderID, OrderItemID: orderItemID, Source: string(d.Source), Code: d.Code,
+			Description: d.Description, Amount: d.Amount, ActorID: d.ActorID,
+		}
+	}
+	return result
 }
@@ -0,0 +1,111 @@
+package repository
+
+//go:generate mockgen -source=reservation_repository.go -destination=mocks/reservation_repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CheckoutReservation struct {
+	ID        int                      `gorm:"primaryKey"`
+	Reference string                   `gorm:"column:reference;uniqueIndex;not null"`
+	UserID    int                      `gorm:"column:user_id;not null;index"`
+	Items     []domain.ReservationItem `gorm:"column:items;type:jsonb;serializer:json;not null"`
+	Status    string                   `gorm:"column:status;not null;index:idx_checkout_reservations_expiry,priority:1"`
+	ExpiresAt time.Time                `gorm:"column:expires_at;not null;index:idx_checkout_reservations_expiry,priority:2"`
+	CreatedAt time.Time                `gorm:"autoCreateTime:mili"`
+	UpdatedAt time.Time                `gorm:"autoUpdateTime:mili"`
+	psql.TenantModel
+}
+
+func (CheckoutReservation) TableName() string { return "checkout_reservations" }
+
+type ReservationRepositoryInterface interface {
+	WithTx(tx *gorm.DB) ReservationRepositoryInterface
+	Create(ctx context.Context, r *domain.Reservation) (*domain.Reservation, error)
+	GetByReference(ctx context.Context, reference string) (*domain.Reservation, error)
+	// GetByReferenceForUpdate locks the reservation until the surrounding transaction ends
+	GetByReferenceForUpdate(ctx context.Context, reference string) (*domain.Reservation, error)
+	UpdateStatus(ctx context.Context, id int, status domain.ReservationStatus) error
+	// GetExpired returns active reservations whose expiry is before the given time, oldest first
+	GetExpired(ctx context.Context, before time.Time, limit int) (*[]domain.Reservation, error)
+}
+
+type ReservationRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewReservationRepository(db *gorm.DB, l *logger.Logger) ReservationRepositoryInterface {
+	return &ReservationRepository{DB: db, Logger: l}
+}
+
+func (r *ReservationRepository) WithTx(tx *gorm.DB) ReservationRepositoryInterface {
+	return &ReservationRepository{DB: tx, Logger: r.Logger}
+}
+
+func (r *ReservationRepository) Create(ctx context.Context, d *domain.Reservation) (*domain.Reservation, error) {
+	res := CheckoutReservation{Reference: d.Reference, UserID: d.UserID, Items: d.Items, Status: string(d.Status), ExpiresAt: d.ExpiresAt}
+	if err := psql.Conn(ctx, r.DB).Create(&res).Error; err != nil {
+		r.Logger.Error("Error saving reservation", zap.String("reference", d.Reference), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return reservationToDomain(&res), nil
+}
+
+func (r *ReservationRepository) GetByReference(ctx context.Context, reference string) (*domain.Reservation, error) {
+	return r.getByReference(r.DB, reference)
+}
+
+func (r *ReservationRepository) GetByReferenceForUpdate(ctx context.Context, reference string) (*domain.Reservation, error) {
+	return r.getByReference(psql.Conn(ctx, r.DB).Clauses(clause.Locking{Strength: "UPDATE"}), reference)
+}
+
+func (r *ReservationRepository) getByReference(db *gorm.DB, reference string) (*domain.Reservation, error) {
+	var res CheckoutReservation
+	if err := db.Where("reference = ?", reference).First(&res).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return reservationToDomain(&res), nil
+}
+
+func (r *ReservationRepository) UpdateStatus(ctx context.Context, id int, status domain.ReservationStatus) error {
+	if err := psql.Conn(ctx, r.DB).Model(&CheckoutReservation{}).Where("id = ?", id).Update("status", string(status)).Error; err != nil {
+		r.Logger.Error("Error updating reservation", zap.Int("id", id), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *ReservationRepository) GetExpired(ctx context.Context, before time.Time, limit int) (*[]domain.Reservation, error) {
+	var reservations []CheckoutReservation
+	if err := psql.Conn(ctx, r.DB).Where("status = ? AND expires_at < ?", string(domain.ReservationStatusActive), before).
+		Order("expires_at").Limit(limit).Find(&reservations).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Reservation, len(reservations))
+	for i := range reservations {
+		result[i] = *reservationToDomain(&reservations[i])
+	}
+	return &result, nil
+}
+
+func reservationToDomain(r *CheckoutReservation) *domain.Reservation {
+	return &domain.Reservation{
+		ID: r.ID, Reference: r.Reference, UserID: r.UserID, Items: r.Items, Status: domain.ReservationStatus(r.Status),
+		ExpiresAt: r.ExpiresAt, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+	}
+}
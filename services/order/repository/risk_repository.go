@@ -0,0 +1,102 @@
+package repository
+
+//go:generate mockgen -source=risk_repository.go -destination=mocks/risk_repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BlocklistEntry struct {
+	ID        int       `gorm:"primaryKey"`
+	Kind      string    `gorm:"column:kind;not null;uniqueIndex:idx_risk_blocklist_kind_value"`
+	Value     string    `gorm:"column:value;not null;uniqueIndex:idx_risk_blocklist_kind_value"`
+	Reason    string    `gorm:"column:reason"`
+	ActorID   int       `gorm:"column:actor_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+	psql.TenantModel
+}
+
+func (BlocklistEntry) TableName() string { return "risk_blocklist" }
+
+type BlocklistRepositoryInterface interface {
+	// Create adds the entry; an existing entry for the same value is returned unchanged
+	Create(ctx context.Context, e *domain.BlocklistEntry) (*domain.BlocklistEntry, error)
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) (*[]domain.BlocklistEntry, error)
+	// Match returns the entries of the kind holding any of the values
+	Match(ctx context.Context, kind domain.BlocklistKind, values []string) (*[]domain.BlocklistEntry, error)
+}
+
+type BlocklistRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewBlocklistRepository(db *gorm.DB, l *logger.Logger) BlocklistRepositoryInterface {
+	return &BlocklistRepository{DB: db, Logger: l}
+}
+
+func (r *BlocklistRepository) Create(ctx context.Context, d *domain.BlocklistEntry) (*domain.BlocklistEntry, error) {
+	e := BlocklistEntry{Kind: string(d.Kind), Value: d.Value, Reason: d.Reason, ActorID: d.ActorID}
+	if err := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&e).Error; err != nil {
+		r.Logger.Error("Error adding blocklist entry", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	if e.ID == 0 {
+		if err := psql.Conn(ctx, r.DB).Where("kind = ? AND value = ?", e.Kind, e.Value).First(&e).Error; err != nil {
+			return nil, psql.TranslateError(err)
+		}
+	}
+	return blocklistEntryToDomain(&e), nil
+}
+
+func (r *BlocklistRepository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&BlocklistEntry{}, id)
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
+
+func (r *BlocklistRepository) GetAll(ctx context.Context) (*[]domain.BlocklistEntry, error) {
+	var entries []BlocklistEntry
+	if err := psql.Conn(ctx, r.DB).Order("id").Find(&entries).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return blocklistEntriesToDomain(entries), nil
+}
+
+func (r *BlocklistRepository) Match(ctx context.Context, kind domain.BlocklistKind, values []string) (*[]domain.BlocklistEntry, error) {
+	var entries []BlocklistEntry
+	if err := psql.Conn(ctx, r.DB).Where("kind = ? AND value IN ?", string(kind), values).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return blocklistEntriesToDomain(entries), nil
+}
+
+func blocklistEntryToDomain(e *BlocklistEntry) *domain.BlocklistEntry {
+	return &domain.BlocklistEntry{
+		ID: e.ID, Kind: domain.BlocklistKind(e.Kind), Value: e.Value, Reason: e.Reason, ActorID: e.ActorID, CreatedAt: e.CreatedAt,
+	}
+}
+
+func blocklistEntriesToDomain(entries []BlocklistEntry) *[]domain.BlocklistEntry {
+	result := make([]domain.BlocklistEntry, len(entries))
+	for i := range entries {
+		result[i] = *blocklistEntryToDomain(&entries[i])
+	}
+	return &result
+}
@@ -0,0 +1,131 @@
+package repository
+
+//go:generate mockgen -source=shipment_repository.go -destination=mocks/shipment_repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Shipment struct {
+	ID             int        `gorm:"primaryKey"`
+	OrderID        int        `gorm:"column:order_id;uniqueIndex;not null"`
+	Carrier        string     `gorm:"column:carrier;not null;uniqueIndex:idx_shipments_carrier_tracking"`
+	TrackingNumber string     `gorm:"column:tracking_number;not null;uniqueIndex:idx_shipments_carrier_tracking"`
+	TrackingURL    string     `gorm:"column:tracking_url"`
+	Status         string     `gorm:"column:status;not null;index"`
+	LastEvent      string     `gorm:"column:last_event"`
+	LastEventAt    *time.Time `gorm:"column:last_event_at"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime:mili"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime:mili"`
+	psql.TenantModel
+}
+
+func (Shipment) TableName() string { return "shipments" }
+
+type ShipmentRepositoryInterface interface {
+	WithTx(tx *gorm.DB) ShipmentRepositoryInterface
+	GetByOrderID(ctx context.Context, orderID int) (*domain.Shipment, error)
+	GetByTracking(ctx context.Context, carrier, trackingNumber string) (*domain.Shipment, error)
+	// GetActive returns the carrier's shipments that are not delivered yet
+	GetActive(ctx context.Context, carrier string, limit int) (*[]domain.Shipment, error)
+	Save(ctx context.Context, s *domain.Shipment) (*domain.Shipment, error)
+	ApplyUpdate(ctx context.Context, id int, update domain.TrackingUpdate) (*domain.Shipment, error)
+}
+
+type ShipmentRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewShipmentRepository(db *gorm.DB, l *logger.Logger) ShipmentRepositoryInterface {
+	return &ShipmentRepository{DB: db, Logger: l}
+}
+
+func (r *ShipmentRepository) WithTx(tx *gorm.DB) ShipmentRepositoryInterface {
+	return &ShipmentRepository{DB: tx, Logger: r.Logger}
+}
+
+func (r *ShipmentRepository) GetByOrderID(ctx context.Context, orderID int) (*domain.Shipment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("order_id = ?", orderID))
+}
+
+func (r *ShipmentRepository) GetByTracking(ctx context.Context, carrier, trackingNumber string) (*domain.Shipment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("carrier = ? AND tracking_number = ?", carrier, trackingNumber))
+}
+
+func (r *ShipmentRepository) GetActive(ctx context.Context, carrier string, limit int) (*[]domain.Shipment, error) {
+	var shipments []Shipment
+	if err := psql.Conn(ctx, r.DB).Where("carrier = ? AND status <> ?", carrier, string(domain.ShipmentStatusDelivered)).
+		Order("updated_at").Limit(limit).Find(&shipments).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Shipment, len(shipments))
+	for i := range shipments {
+		result[i] = *shipmentToDomain(&shipments[i])
+	}
+	return &result, nil
+}
+
+// Save creates the order's shipment or replaces carrier and tracking number on the existing one
+func (r *ShipmentRepository) Save(ctx context.Context, d *domain.Shipment) (*domain.Shipment, error) {
+	s := Shipment{
+		OrderID: d.OrderID, Carrier: d.Carrier, TrackingNumber: d.TrackingNumber,
+		TrackingURL: d.TrackingURL, Status: string(d.Status),
+	}
+	err := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "order_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"carrier", "tracking_number", "tracking_url", "status", "last_event", "last_event_at", "delivered_at", "updated_at"}),
+	}).Create(&s).Error
+	if err != nil {
+		r.Logger.Error("Error saving shipment", zap.Int("orderID", d.OrderID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetByOrderID(ctx, d.OrderID)
+}
+
+func (r *ShipmentRepository) ApplyUpdate(ctx context.Context, id int, update domain.TrackingUpdate) (*domain.Shipment, error) {
+	fields := map[string]interface{}{
+		"status":        string(update.Status),
+		"last_event":    update.Description,
+		"last_event_at": update.OccurredAt,
+	}
+	if update.Status == domain.ShipmentStatusDelivered {
+		fields["delivered_at"] = update.OccurredAt
+	}
+	if err := psql.Conn(ctx, r.DB).Model(&Shipment{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Logger.Error("Error applying tracking update", zap.Int("id", id), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.first(psql.Conn(ctx, r.DB).Where("id = ?", id))
+}
+
+func (r *ShipmentRepository) first(query *gorm.DB) (*domain.Shipment, error) {
+	var s Shipment
+	if err := query.First(&s).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return shipmentToDomain(&s), nil
+}
+
+func shipmentToDomain(s *Shipment) *domain.Shipment {
+	return &domain.Shipment{
+		ID: s.ID, OrderID: s.OrderID, Carrier: s.Carrier, TrackingNumber: s.TrackingNumber,
+		TrackingURL: s.TrackingURL, Status: domain.ShipmentStatus(s.Status),
+		LastEvent: s.LastEvent, LastEventAt: s.LastEventAt, DeliveredAt: s.DeliveredAt,
+		CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+	}
+}
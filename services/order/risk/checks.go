@@ -0,0 +1,130 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/services/order/client"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+)
+
+// VelocityCheck flags users and IPs placing more orders than usual within the window
+type VelocityCheck struct {
+	orders     repository.OrderRepositoryInterface
+	window     time.Duration
+	maxPerUser int
+	maxPerIP   int
+	score      int
+}
+
+func NewVelocityCheck(orders repository.OrderRepositoryInterface, window time.Duration, maxPerUser, maxPerIP, score int) Check {
+	return &VelocityCheck{orders: orders, window: window, maxPerUser: maxPerUser, maxPerIP: maxPerIP, score: score}
+}
+
+func (c *VelocityCheck) Name() string { return "velocity" }
+
+func (c *VelocityCheck) Evaluate(ctx context.Context, order *domain.Order) ([]domain.RiskSignal, error) {
+	byUser, byIP, err := c.orders.CountCreatedSince(ctx, order.UserID, order.ClientIP, time.Now().Add(-c.window))
+	if err != nil {
+		return nil, err
+	}
+	var signals []domain.RiskSignal
+	// the order being placed is not stored yet, so it makes one more
+	if c.maxPerUser > 0 && byUser+1 > c.maxPerUser {
+		signals = append(signals, domain.RiskSignal{Score: c.score, Reason: fmt.Sprintf("%d orders by the user within %s", byUser+1, c.window)})
+	}
+	if c.maxPerIP > 0 && order.ClientIP != "" && byIP+1 > c.maxPerIP {
+		signals = append(signals, domain.RiskSignal{Score: c.score, Reason: fmt.Sprintf("%d orders from %s within %s", byIP+1, order.ClientIP, c.window)})
+	}
+	return signals, nil
+}
+
+// AddressMismatchCheck flags orders shipped to another country, or to another name,
+// than the billing address
+type AddressMismatchCheck struct {
+	countryScore int
+	nameScore    int
+}
+
+func NewAddressMismatchCheck(countryScore, nameScore int) Check {
+	return &AddressMismatchCheck{countryScore: countryScore, nameScore: nameScore}
+}
+
+func (c *AddressMismatchCheck) Name() string { return "address_mismatch" }
+
+func (c *AddressMismatchCheck) Evaluate(_ context.Context, order *domain.Order) ([]domain.RiskSignal, error) {
+	shipping, billing := order.ShippingAddress, order.BillingAddress
+	if shipping == nil || billing == nil {
+		return nil, nil
+	}
+	var signals []domain.RiskSignal
+	if !sameText(shipping.Country, billing.Country) {
+		signals = append(signals, domain.RiskSignal{
+			Score: c.countryScore, Reason: fmt.Sprintf("shipping country %s differs from billing country %s", shipping.Country, billing.Country),
+		})
+	}
+	if shipping.Name != "" && billing.Name != "" && !sameText(shipping.Name, billing.Name) {
+		signals = append(signals, domain.RiskSignal{Score: c.nameScore, Reason: "shipping name differs from billing name"})
+	}
+	return signals, nil
+}
+
+// BlocklistCheck flags orders from blocked IPs and from customers whose email, or email
+// domain, is blocked
+type BlocklistCheck struct {
+	blocklist repository.BlocklistRepositoryInterface
+	users     client.IUserClient
+	score     int
+}
+
+func NewBlocklistCheck(blocklist repository.BlocklistRepositoryInterface, users client.IUserClient, score int) Check {
+	return &BlocklistCheck{blocklist: blocklist, users: users, score: score}
+}
+
+func (c *BlocklistCheck) Name() string { return "blocklist" }
+
+func (c *BlocklistCheck) Evaluate(ctx context.Context, order *domain.Order) ([]domain.RiskSignal, error) {
+	var signals []domain.RiskSignal
+	if order.ClientIP != "" {
+		found, err := c.blocklist.Match(ctx, domain.BlocklistIP, []string{order.ClientIP})
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, c.signals(*found)...)
+	}
+	email, err := c.users.GetEmail(ctx, order.UserID)
+	if err != nil {
+		return signals, err
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return signals, nil
+	}
+	found, err := c.blocklist.Match(ctx, domain.BlocklistEmail, []string{email})
+	if err != nil {
+		return signals, err
+	}
+	signals = append(signals, c.signals(*found)...)
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		if found, err = c.blocklist.Match(ctx, domain.BlocklistEmailDomain, []string{email[at+1:]}); err != nil {
+			return signals, err
+		}
+		signals = append(signals, c.signals(*found)...)
+	}
+	return signals, nil
+}
+
+func (c *BlocklistCheck) signals(entries []domain.BlocklistEntry) []domain.RiskSignal {
+	signals := make([]domain.RiskSignal, len(entries))
+	for i, e := range entries {
+		signals[i] = domain.RiskSignal{Score: c.score, Reason: fmt.Sprintf("%s %s is blocklisted", strings.ReplaceAll(string(e.Kind), "_", " "), e.Value)}
+	}
+	return signals
+}
+
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
@@ -0,0 +1,52 @@
+package risk
+
+//go:generate mockgen -source=risk.go -destination=mocks/risk.go -package=mocks
+
+import (
+	"context"
+
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/services/order/domain"
+
+	"go.uber.org/zap"
+)
+
+// Check is one risk rule run at checkout. It returns what it found, or nothing.
+type Check interface {
+	Name() string
+	Evaluate(ctx context.Context, order *domain.Order) ([]domain.RiskSignal, error)
+}
+
+type Assessor interface {
+	Assess(ctx context.Context, order *domain.Order) *domain.RiskAssessment
+}
+
+// Engine adds up the signals of its checks and asks for review once the score reaches the
+// threshold. A failing check is logged and skipped so an outage never blocks checkout.
+type Engine struct {
+	checks          []Check
+	reviewThreshold int
+	Logger          *logger.Logger
+}
+
+func NewEngine(reviewThreshold int, l *logger.Logger, checks ...Check) Assessor {
+	return &Engine{checks: checks, reviewThreshold: reviewThreshold, Logger: l}
+}
+
+func (e *Engine) Assess(ctx context.Context, order *domain.Order) *domain.RiskAssessment {
+	assessment := &domain.RiskAssessment{}
+	for _, c := range e.checks {
+		// signals found before a check failed still count
+		signals, err := c.Evaluate(ctx, order)
+		if err != nil {
+			e.Logger.Warn("Risk check failed", zap.String("check", c.Name()), zap.Int("userID", order.UserID), zap.Error(err))
+		}
+		for _, s := range signals {
+			s.Check = c.Name()
+			assessment.Signals = append(assessment.Signals, s)
+			assessment.Score += s.Score
+		}
+	}
+	assessment.Review = e.reviewThreshold > 0 && assessment.Score >= e.reviewThreshold
+	return assessment
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/currency"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/client"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fixtureKeyPrefix marks the idempotency keys that tie seeded orders to their fixture key
+const fixtureKeyPrefix = "fixture:"
+
+const fixtureNote = "Seeded from fixtures"
+
+// FixtureSeeder stores the orders of a fixture set as order history: they are matched by key
+// through the customer's idempotency keys, take no stock or payments and publish no events, so
+// seeding never sends emails or webhooks. Customers and products are looked up by email and SKU
+// in the user and catalog services, which have to be seeded first.
+type FixtureSeeder struct {
+	repo      repository.OrderRepositoryInterface
+	txManager psql.TxManager
+	users     client.IUserClient
+	catalog   client.ICatalogClient
+	rates     currency.RateProvider
+	Logger    *logger.Logger
+}
+
+func NewFixtureSeeder(r repository.OrderRepositoryInterface, txManager psql.TxManager, users client.IUserClient, catalog client.ICatalogClient,
+	rates currency.RateProvider, l *logger.Logger) fixtures.Seeder {
+	return &FixtureSeeder{repo: r, txManager: txManager, users: users, catalog: catalog, rates: rates, Logger: l}
+}
+
+func (s *FixtureSeeder) Seed(ctx context.Context, set *fixtures.Set) (fixtures.Result, error) {
+	var result fixtures.Result
+	for _, f := range set.Orders {
+		if err := s.seedOrder(ctx, f, &result); err != nil {
+			return result, fmt.Errorf("order %s: %w", f.Key, err)
+		}
+	}
+	s.Logger.Info("Seeded orders", zap.Int("created", result.Created), zap.Int("updated", result.Updated), zap.Int("unchanged", result.Unchanged))
+	return result, nil
+}
+
+func (s *FixtureSeeder) seedOrder(ctx context.Context, f fixtures.Order, result *fixtures.Result) error {
+	status := domain.OrderStatus(f.Status)
+	if status == "" {
+		status = domain.OrderStatusPending
+	}
+	if !status.IsValid() {
+		return fmt.Errorf("invalid status %q", f.Status)
+	}
+	userID, err := s.users.FindUserID(ctx, f.User)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", f.User, err)
+	}
+	existingID, err := s.repo.GetOrderIDByIdempotencyKey(ctx, userID, fixtureKeyPrefix+f.Key)
+	if err != nil {
+		return err
+	}
+	if existingID != 0 {
+		return s.updateStatus(ctx, existingID, status, result)
+	}
+	order := &domain.Order{UserID: userID, Status: status, Currency: f.Currency}
+	for _, item := range f.Items {
+		product, err := s.catalog.GetProductBySKU(ctx, item.Product)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", item.Product, err)
+		}
+		price := item.Price
+		if price == 0 {
+			price = product.Price
+		}
+		subtotal := round2(price * float64(item.Quantity))
+		order.Items = append(order.Items, domain.OrderItem{ProductID: product.ID, Quantity: item.Quantity, Price: price, Subtotal: subtotal})
+		order.SubtotalAmount = round2(order.SubtotalAmount + subtotal)
+	}
+	order.TotalAmount = order.SubtotalAmount
+	if err := snapshotExchangeRate(s.rates, order); err != nil {
+		return err
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		created, err := repo.Create(ctx, order)
+		if err != nil {
+			return err
+		}
+		if err := repo.AddStatusEvent(ctx, statusChange(created.ID, "", created.Status, fixtureNote, domain.SystemRequester)); err != nil {
+			return err
+		}
+		return repo.SaveIdempotencyKey(ctx, userID, fixtureKeyPrefix+f.Key, created.ID)
+	})
+	if err != nil {
+		return err
+	}
+	result.Created++
+	return nil
+}
+
+// updateStatus moves a seeded order to the fixture's status; its items are history and stay
+func (s *FixtureSeeder) updateStatus(ctx context.Context, id int, status domain.OrderStatus, result *fixtures.Result) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		current, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.Status == status {
+			result.Unchanged++
+			return nil
+		}
+		if _, err := repo.UpdateStatus(ctx, id, current.Version, status); err != nil {
+			return err
+		}
+		if err := repo.AddStatusEvent(ctx, statusChange(id, current.Status, status, fixtureNote, domain.SystemRequester)); err != nil {
+			return err
+		}
+		result.Updated++
+		return nil
+	})
+}
@@ -0,0 +1,81 @@
+package usecase
+
+//go:generate mockgen -source=invoice_usecase.go -destination=mocks/invoice_usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/invoice"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+)
+
+// --- Invoice UseCase ---
+
+type IInvoiceUseCase interface {
+	GetOrCreate(ctx context.Context, orderID int, requester domain.Requester) (*domain.Invoice, error)
+}
+
+type InvoiceUseCase struct {
+	orderUC     IOrderUseCase
+	invoiceRepo repository.InvoiceRepositoryInterface
+	renderer    invoice.IRenderer
+	taxRate     float64
+	Logger      *logger.Logger
+}
+
+func NewInvoiceUseCase(orderUC IOrderUseCase, r repository.InvoiceRepositoryInterface, renderer invoice.IRenderer, taxRate float64, l *logger.Logger) IInvoiceUseCase {
+	return &InvoiceUseCase{orderUC: orderUC, invoiceRepo: r, renderer: renderer, taxRate: taxRate, Logger: l}
+}
+
+var invoiceableStatuses = map[domain.OrderStatus]bool{
+	domain.OrderStatusPaid:      true,
+	domain.OrderStatusShipped:   true,
+	domain.OrderStatusDelivered: true,
+}
+
+// GetOrCreate returns the stored invoice, issuing it on the first request
+func (s *InvoiceUseCase) GetOrCreate(ctx context.Context, orderID int, requester domain.Requester) (*domain.Invoice, error) {
+	order, err := s.orderUC.GetByID(ctx, orderID, requester)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.invoiceRepo.GetByOrderID(ctx, orderID)
+	if err == nil {
+		return existing, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+	if !invoiceableStatuses[order.Status] {
+		return nil, domainErrors.NewAppError(errors.New("invoice is available once the order is paid"), domainErrors.ValidationError)
+	}
+
+	s.Logger.Info("Issuing invoice", zap.Int("orderID", orderID))
+	net := round2(order.TotalAmount / (1 + s.taxRate))
+	inv := &domain.Invoice{
+		OrderID:     orderID,
+		NetAmount:   net,
+		TaxRate:     s.taxRate,
+		TaxAmount:   round2(order.TotalAmount - net),
+		Total:       order.TotalAmount,
+		ContentType: "text/html; charset=utf-8",
+		IssuedAt:    time.Now().UTC(),
+	}
+	// the invoice belongs to the order's tenant, also when issued for a call across tenants
+	return s.invoiceRepo.Create(tenant.ContextWithTenant(ctx, order.Tenant), inv, func(numbered *domain.Invoice) ([]byte, error) {
+		return s.renderer.Render(numbered, order)
+	})
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
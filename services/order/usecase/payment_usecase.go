@@ -0,0 +1,261 @@
+package usecase
+
+//go:generate mockgen -source=payment_usecase.go -destination=mocks/payment_usecase.go -package=mocks
+
+import (
+	"context"
+	"ecommerce-microservice-go/pkg/currency"
+	"errors"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// --- Payment UseCase ---
+
+type IPaymentUseCase interface {
+	GetPayments(ctx context.Context, orderID int, requester domain.Requester) (*[]domain.Payment, error)
+	// CapturePayment confirms the pending card payment and marks the order paid
+	CapturePayment(ctx context.Context, orderID int, reference string, requester domain.Requester) (*domain.Order, error)
+	GetStoreCredit(ctx context.Context, userID int, requester domain.Requester) (*domain.StoreCreditAccount, error)
+	AdjustStoreCredit(ctx context.Context, userID int, amount float64, note string, requester domain.Requester) (*domain.StoreCreditAccount, error)
+	// RefundToStoreCredit credits part of a paid order, given in order currency, to the customer
+	RefundToStoreCredit(ctx context.Context, orderID int, amount float64, note string, requester domain.Requester) (*domain.StoreCreditAccount, error)
+}
+
+type PaymentUseCase struct {
+	orderRepo   repository.OrderRepositoryInterface
+	paymentRepo repository.PaymentRepositoryInterface
+	creditRepo  repository.StoreCreditRepositoryInterface
+	txManager   psql.TxManager
+	rates       currency.RateProvider
+	access      authz.Enforcer
+	auditor     audit.Auditor
+	Logger      *logger.Logger
+}
+
+func NewPaymentUseCase(orderRepo repository.OrderRepositoryInterface, paymentRepo repository.PaymentRepositoryInterface,
+	creditRepo repository.StoreCreditRepositoryInterface, txManager psql.TxManager, rates currency.RateProvider, access authz.Enforcer,
+	auditor audit.Auditor, l *logger.Logger) IPaymentUseCase {
+	return &PaymentUseCase{
+		orderRepo: orderRepo, paymentRepo: paymentRepo, creditRepo: creditRepo, txManager: txManager, rates: rates, access: access, auditor: auditor,
+		Logger: l,
+	}
+}
+
+const storeCreditHistoryLimit = 50
+
+func (s *PaymentUseCase) GetPayments(ctx context.Context, orderID int, requester domain.Requester) (*[]domain.Payment, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, order) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return s.paymentRepo.GetByOrderID(ctx, orderID)
+}
+
+func (s *PaymentUseCase) CapturePayment(ctx context.Context, orderID int, reference string, requester domain.Requester) (*domain.Order, error) {
+	if err := requireStaff(requester); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Capturing order payment", zap.Int("orderID", orderID))
+	var updated *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		current, err := orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if current.Status != domain.OrderStatusPending {
+			return domainErrors.NewAppError(errors.New("only pending orders can be paid"), domainErrors.ValidationError)
+		}
+		if err = settlePayments(ctx, s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx), current, domain.OrderStatusPaid, reference, requester); err != nil {
+			return err
+		}
+		updated, err = transitionStatus(ctx, orderRepo, current, domain.OrderStatusPaid, "Card payment captured", requester, orderEventPayload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *PaymentUseCase) GetStoreCredit(ctx context.Context, userID int, requester domain.Requester) (*domain.StoreCreditAccount, error) {
+	if !requester.IsStaff && requester.UserID != userID {
+		return nil, domainErrors.NewAppError(errors.New("store credit belongs to another user"), domainErrors.NotAuthorized)
+	}
+	balance, err := s.creditRepo.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.creditRepo.GetEntries(ctx, userID, storeCreditHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.StoreCreditAccount{UserID: userID, Balance: balance, Currency: s.rates.BaseCurrency(), Entries: *entries}, nil
+}
+
+func (s *PaymentUseCase) AdjustStoreCredit(ctx context.Context, userID int, amount float64, note string, requester domain.Requester) (*domain.StoreCreditAccount, error) {
+	if !requester.IsAdmin {
+		return nil, domainErrors.NewAppError(errors.New("admin role required"), domainErrors.NotAuthorized)
+	}
+	amount = round2(amount)
+	if amount == 0 {
+		return nil, domainErrors.NewAppError(errors.New("amount must not be zero"), domainErrors.ValidationError)
+	}
+	s.Logger.Info("Adjusting store credit", zap.Int("userID", userID), zap.Float64("amount", amount), zap.Int("actorID", requester.UserID))
+	var before float64
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		creditRepo := s.creditRepo.WithTx(tx)
+		balance, err := creditRepo.GetBalance(ctx, userID)
+		if err != nil {
+			return err
+		}
+		before = balance
+		return addStoreCredit(ctx, creditRepo, &domain.StoreCreditEntry{
+			UserID: userID, Amount: amount, Reason: domain.StoreCreditReasonAdjustment, Note: note, ActorID: requester.UserID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.GetStoreCredit(ctx, userID, requester)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityStoreCredit, userID,
+		map[string]any{"balance": before}, map[string]any{"balance": account.Balance, "adjustment": amount, "note": note})
+	return account, nil
+}
+
+func (s *PaymentUseCase) RefundToStoreCredit(ctx context.Context, orderID int, amount float64, note string, requester domain.Requester) (*domain.StoreCreditAccount, error) {
+	if !requester.IsAdmin {
+		return nil, domainErrors.NewAppError(errors.New("admin role required"), domainErrors.NotAuthorized)
+	}
+	if amount <= 0 {
+		return nil, domainErrors.NewAppError(errors.New("amount must be positive"), domainErrors.ValidationError)
+	}
+	var userID int
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		paymentRepo, creditRepo := s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx)
+		order, err := s.orderRepo.WithTx(tx).GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		userID = order.UserID
+		payments, err := paymentRepo.GetByOrderID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		var captured float64
+		for _, p := range *payments {
+			if p.Status == domain.PaymentStatusCaptured {
+				captured += p.BaseAmount
+			}
+		}
+		refunded, err := creditRepo.GetRefundedAmount(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		baseAmount := round2(amount * order.ExchangeRate)
+		if baseAmount > round2(captured-refunded) {
+			return domainErrors.NewAppError(errors.New("refund exceeds the captured amount not yet refunded"), domainErrors.ValidationError)
+		}
+		s.Logger.Info("Refunding order to store credit", zap.Int("orderID", orderID), zap.Float64("baseAmount", baseAmount))
+		return addStoreCredit(ctx, creditRepo, &domain.StoreCreditEntry{
+			UserID: order.UserID, Amount: baseAmount, Reason: domain.StoreCreditReasonRefund, Note: note, OrderID: &orderID, ActorID: requester.UserID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStoreCredit(ctx, userID, requester)
+}
+
+// takeCheckoutPayments splits the order total into a captured store credit payment and a
+// pending card payment for the rest. It reports whether store credit covered everything.
+func takeCheckoutPayments(ctx context.Context, paymentRepo repository.PaymentRepositoryInterface, creditRepo repository.StoreCreditRepositoryInterface,
+	order *domain.Order, storeCredit float64, requester domain.Requester) (bool, error) {
+	storeCredit = round2(storeCredit)
+	if storeCredit < 0 || storeCredit > order.TotalAmount {
+		return false, domainErrors.NewAppError(errors.New("store credit amount must be between 0 and the order total"), domainErrors.ValidationError)
+	}
+	if storeCredit > 0 {
+		baseAmount := round2(storeCredit * order.ExchangeRate)
+		if err := addStoreCredit(ctx, creditRepo, &domain.StoreCreditEntry{
+			UserID: order.UserID, Amount: -baseAmount, Reason: domain.StoreCreditReasonCheckout, OrderID: &order.ID, ActorID: requester.UserID,
+		}); err != nil {
+			return false, err
+		}
+		if _, err := paymentRepo.Create(ctx, &domain.Payment{
+			OrderID: order.ID, Method: domain.PaymentMethodStoreCredit, Status: domain.PaymentStatusCaptured,
+			Amount: storeCredit, BaseAmount: baseAmount,
+		}); err != nil {
+			return false, err
+		}
+	}
+	remaining := round2(order.TotalAmount - storeCredit)
+	if remaining <= 0 {
+		return storeCredit > 0, nil
+	}
+	_, err := paymentRepo.Create(ctx, &domain.Payment{
+		OrderID: order.ID, Method: domain.PaymentMethodCard, Status: domain.PaymentStatusPending,
+		Amount: remaining, BaseAmount: round2(remaining * order.ExchangeRate),
+	})
+	return false, err
+}
+
+// settlePayments keeps payment entries in line with an order status change: pending card
+// payments are captured when the order is paid, and store credit spent on an order is
+// returned to the customer when it is cancelled.
+func settlePayments(ctx context.Context, paymentRepo repository.PaymentRepositoryInterface, creditRepo repository.StoreCreditRepositoryInterface,
+	order *domain.Order, newStatus domain.OrderStatus, reference string, requester domain.Requester) error {
+	if newStatus != domain.OrderStatusPaid && newStatus != domain.OrderStatusCancelled {
+		return nil
+	}
+	payments, err := paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range *payments {
+		switch {
+		case newStatus == domain.OrderStatusPaid && p.Status == domain.PaymentStatusPending:
+			err = paymentRepo.UpdateStatus(ctx, p.ID, domain.PaymentStatusCaptured, reference)
+		case newStatus == domain.OrderStatusCancelled && p.Method == domain.PaymentMethodStoreCredit && p.Status == domain.PaymentStatusCaptured:
+			if err = paymentRepo.UpdateStatus(ctx, p.ID, domain.PaymentStatusReleased, ""); err == nil {
+				err = addStoreCredit(ctx, creditRepo, &domain.StoreCreditEntry{
+					UserID: order.UserID, Amount: p.BaseAmount, Reason: domain.StoreCreditReasonReleased, OrderID: &order.ID, ActorID: requester.UserID,
+				})
+			}
+		case newStatus == domain.OrderStatusCancelled && p.Status == domain.PaymentStatusPending:
+			err = paymentRepo.UpdateStatus(ctx, p.ID, domain.PaymentStatusReleased, "")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addStoreCredit locks the balance and applies the entry, refusing to go below zero
+func addStoreCredit(ctx context.Context, creditRepo repository.StoreCreditRepositoryInterface, entry *domain.StoreCreditEntry) error {
+	balance, err := creditRepo.GetBalanceForUpdate(ctx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	if round2(balance+entry.Amount) < 0 {
+		return domainErrors.NewAppError(errors.New("insufficient store credit"), domainErrors.ValidationError)
+	}
+	return creditRepo.AddEntry(ctx, entry)
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"testing"
+
+	"ecommerce-microservice-go/services/order/domain"
+)
+
+// BenchmarkApplyDiscounts totals a cart of 20 lines, every other one with a coupon, plus a
+// promotion on the whole order; the totals it sets are the same on every run
+func BenchmarkApplyDiscounts(b *testing.B) {
+	b.ReportAllocs()
+	order := &domain.Order{Discounts: []domain.OrderDiscount{{Source: domain.DiscountSourcePromotion, Code: "SALE", Amount: 5}}}
+	for i := range 20 {
+		item := domain.OrderItem{ProductID: i + 1, Quantity: i%3 + 1, Price: 25.5 + float64(i)}
+		if i%2 == 0 {
+			item.Discounts = []domain.OrderDiscount{{Source: domain.DiscountSourceCoupon, Code: "TEN", Amount: 2.25}}
+		}
+		order.Items = append(order.Items, item)
+	}
+	requester := domain.Requester{UserID: 7, Role: "customer"}
+	for b.Loop() {
+		if err := applyDiscounts(order, requester); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+//go:generate mockgen -source=reorder_usecase.go -destination=mocks/reorder_usecase.go -package=mocks
+
+import (
+	"context"
+	"ecommerce-microservice-go/pkg/currency"
+	"errors"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/authz"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/services/order/client"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+)
+
+// --- Reorder UseCase ---
+
+type IReorderUseCase interface {
+	// Reorder rebuilds a checkout from a past order using current catalog prices and stock.
+	// Nothing is created; the caller submits the purchasable items as a new order.
+	Reorder(ctx context.Context, orderID int, requester domain.Requester) (*domain.Reorder, error)
+}
+
+type ReorderUseCase struct {
+	orderRepo repository.OrderRepositoryInterface
+	catalog   client.ICatalogClient
+	rates     currency.RateProvider
+	access    authz.Enforcer
+	Logger    *logger.Logger
+}
+
+func NewReorderUseCase(orderRepo repository.OrderRepositoryInterface, catalog client.ICatalogClient, rates currency.RateProvider, access authz.Enforcer,
+	l *logger.Logger) IReorderUseCase {
+	return &ReorderUseCase{orderRepo: orderRepo, catalog: catalog, rates: rates, access: access, Logger: l}
+}
+
+func (s *ReorderUseCase) Reorder(ctx context.Context, orderID int, requester domain.Requester) (*domain.Reorder, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, order) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	// the source currency may have been dropped since; fall back to the base currency
+	currencyCode := order.Currency
+	rate, err := s.rates.Rate(currencyCode)
+	if err != nil {
+		currencyCode = s.rates.BaseCurrency()
+		rate = 1
+	}
+
+	reorder := &domain.Reorder{SourceOrderID: order.ID, SourceOrderNumber: order.Number, Currency: currencyCode}
+	for _, item := range order.Items {
+		line := domain.ReorderItem{ProductID: item.ProductID, PreviousQuantity: item.Quantity, PreviousPrice: item.Price}
+		product, err := s.catalog.GetProduct(ctx, item.ProductID)
+		if errors.Is(err, client.ErrProductNotFound) {
+			line.Issues = append(line.Issues, domain.ReorderIssueNotFound)
+			reorder.Items = append(reorder.Items, line)
+			continue
+		}
+		if err != nil {
+			s.Logger.Error("Error loading product for reorder", zap.Int("productID", item.ProductID), zap.Error(err))
+			return nil, domainErrors.NewAppError(fmt.Errorf("catalog unavailable: %w", err), domainErrors.UnknownError)
+		}
+		line.Name = product.Name
+		line.Price = round2(product.Price / rate)
+		line.Quantity = min(item.Quantity, product.Stock)
+		switch {
+		case !product.IsActive:
+			line.Issues = append(line.Issues, domain.ReorderIssueInactive)
+		case product.Stock <= 0:
+			line.Issues = append(line.Issues, domain.ReorderIssueOutOfStock)
+		case product.Stock < item.Quantity:
+			line.Issues = append(line.Issues, domain.ReorderIssueInsufficientStock)
+		}
+		if line.Price != item.Price {
+			line.Issues = append(line.Issues, domain.ReorderIssuePriceChanged)
+		}
+		line.Purchasable = product.IsActive && line.Quantity > 0
+		if !line.Purchasable {
+			line.Quantity = 0
+		} else {
+			reorder.TotalAmount += line.Price * float64(line.Quantity)
+		}
+		reorder.Items = append(reorder.Items, line)
+	}
+	reorder.TotalAmount = round2(reorder.TotalAmount)
+	return reorder, nil
+}
@@ -0,0 +1,237 @@
+package usecase
+
+//go:generate mockgen -source=reservation_usecase.go -destination=mocks/reservation_usecase.go -package=mocks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/order/client"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// --- Reservation UseCase ---
+
+type IReservationUseCase interface {
+	// Reserve holds catalog stock for a checkout until the reservation TTL passes
+	Reserve(ctx context.Context, items []domain.ReservationItem, requester domain.Requester) (*domain.Reservation, error)
+	// ReserveAs reserves under a caller-chosen reference; repeating it returns the existing reservation
+	ReserveAs(ctx context.Context, reference string, items []domain.ReservationItem, requester domain.Requester) (*domain.Reservation, error)
+	// Release gives the stock back when the customer abandons checkout
+	Release(ctx context.Context, reference string, requester domain.Requester) error
+	// ExpireAbandoned releases reservations past their expiry and returns how many were expired
+	ExpireAbandoned(ctx context.Context) (int, error)
+	// Cancel returns the stock whatever the reservation's state, undoing a checkout that failed
+	// after its order consumed the reservation
+	Cancel(ctx context.Context, reference string) error
+}
+
+type ReservationUseCase struct {
+	repo      repository.ReservationRepositoryInterface
+	catalog   client.ICatalogClient
+	txManager psql.TxManager
+	ttl       time.Duration
+	Logger    *logger.Logger
+}
+
+func NewReservationUseCase(r repository.ReservationRepositoryInterface, catalog client.ICatalogClient, txManager psql.TxManager,
+	ttl time.Duration, l *logger.Logger) IReservationUseCase {
+	return &ReservationUseCase{repo: r, catalog: catalog, txManager: txManager, ttl: ttl, Logger: l}
+}
+
+const expireBatchSize = 100
+
+var errReservationExpired = domainErrors.NewAppError(errors.New("reservation expired; reserve the items again"), domainErrors.Expired)
+
+func (s *ReservationUseCase) Reserve(ctx context.Context, items []domain.ReservationItem, requester domain.Requester) (*domain.Reservation, error) {
+	reference, err := newReservationReference()
+	if err != nil {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return s.ReserveAs(ctx, reference, items, requester)
+}
+
+func (s *ReservationUseCase) ReserveAs(ctx context.Context, reference string, items []domain.ReservationItem, requester domain.Requester) (*domain.Reservation, error) {
+	existing, err := s.repo.GetByReference(ctx, reference)
+	if err == nil {
+		if existing.UserID != requester.UserID {
+			return nil, domainErrors.NewAppError(errors.New("reservation reference is taken"), domainErrors.ValidationError)
+		}
+		return existing, nil
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+	quantities, err := reservationQuantities(items)
+	if err != nil {
+		return nil, err
+	}
+	merged := make([]domain.ReservationItem, 0, len(quantities))
+	for _, it := range items {
+		if q, ok := quantities[it.ProductID]; ok {
+			merged = append(merged, domain.ReservationItem{ProductID: it.ProductID, Quantity: q})
+			delete(quantities, it.ProductID)
+		}
+	}
+
+	s.Logger.Info("Reserving stock for checkout", zap.Int("userID", requester.UserID), zap.String("reference", reference))
+	if err := s.catalog.ReserveStock(ctx, reference, merged); err != nil {
+		if errors.Is(err, client.ErrReservationRejected) {
+			return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+		}
+		s.Logger.Error("Error reserving stock", zap.String("reference", reference), zap.Error(err))
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	reservation, err := s.repo.Create(ctx, &domain.Reservation{
+		Reference: reference, UserID: requester.UserID, Items: merged,
+		Status: domain.ReservationStatusActive, ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		if releaseErr := s.catalog.ReleaseStock(ctx, reference); releaseErr != nil {
+			s.Logger.Error("Error releasing unsaved reservation", zap.String("reference", reference), zap.Error(releaseErr))
+		}
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *ReservationUseCase) Release(ctx context.Context, reference string, requester domain.Requester) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		reservation, err := repo.GetByReferenceForUpdate(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if reservation.UserID != requester.UserID && !requester.IsStaff {
+			return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		if reservation.Status != domain.ReservationStatusActive {
+			return domainErrors.NewAppError(errors.New("reservation is no longer active"), domainErrors.ValidationError)
+		}
+		if err := s.catalog.ReleaseStock(ctx, reference); err != nil {
+			s.Logger.Error("Error releasing stock", zap.String("reference", reference), zap.Error(err))
+			return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		return repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusReleased)
+	})
+}
+
+func (s *ReservationUseCase) Cancel(ctx context.Context, reference string) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		reservation, err := repo.GetByReferenceForUpdate(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if reservation.Status == domain.ReservationStatusReleased || reservation.Status == domain.ReservationStatusExpired {
+			return nil
+		}
+		if err := s.catalog.ReleaseStock(ctx, reference); err != nil {
+			s.Logger.Error("Error releasing stock", zap.String("reference", reference), zap.Error(err))
+			return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		}
+		return repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusReleased)
+	})
+}
+
+func (s *ReservationUseCase) ExpireAbandoned(ctx context.Context) (int, error) {
+	reservations, err := s.repo.GetExpired(ctx, time.Now(), expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, r := range *reservations {
+		if ctx.Err() != nil {
+			return expired, ctx.Err()
+		}
+		// the row stays locked while the catalog is called so a checkout still holding it
+		// finishes first; a failed release rolls back and is retried on the next sweep
+		released := false
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+			repo := s.repo.WithTx(tx)
+			current, err := repo.GetByReferenceForUpdate(ctx, r.Reference)
+			if err != nil || current.Status != domain.ReservationStatusActive {
+				return err
+			}
+			if err := s.catalog.ReleaseStock(ctx, r.Reference); err != nil {
+				return err
+			}
+			released = true
+			return repo.UpdateStatus(ctx, current.ID, domain.ReservationStatusExpired)
+		})
+		if err != nil {
+			s.Logger.Error("Error expiring reservation", zap.String("reference", r.Reference), zap.Error(err))
+			continue
+		}
+		if released {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// consumeReservation marks the reservation as used by the order. It must name an unexpired
+// reservation of the same user holding exactly the ordered quantities.
+func consumeReservation(ctx context.Context, repo repository.ReservationRepositoryInterface, reference string, order *domain.Order) error {
+	reservation, err := repo.GetByReferenceForUpdate(ctx, reference)
+	if isNotFound(err) || (err == nil && reservation.UserID != order.UserID) {
+		return domainErrors.NewAppError(errors.New("unknown reservation"), domainErrors.ValidationError)
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case reservation.Status == domain.ReservationStatusExpired,
+		reservation.Status == domain.ReservationStatusActive && !time.Now().Before(reservation.ExpiresAt):
+		return errReservationExpired
+	case reservation.Status != domain.ReservationStatusActive:
+		return domainErrors.NewAppError(errors.New("reservation has already been used or released"), domainErrors.ValidationError)
+	}
+
+	ordered := map[int]int{}
+	for _, it := range order.Items {
+		ordered[it.ProductID] += it.Quantity
+	}
+	reserved, _ := reservationQuantities(reservation.Items)
+	if len(ordered) != len(reserved) {
+		return domainErrors.NewAppError(errors.New("order items do not match the reservation"), domainErrors.ValidationError)
+	}
+	for productID, quantity := range ordered {
+		if reserved[productID] != quantity {
+			return domainErrors.NewAppError(errors.New("order items do not match the reservation"), domainErrors.ValidationError)
+		}
+	}
+	return repo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusConsumed)
+}
+
+func reservationQuantities(items []domain.ReservationItem) (map[int]int, error) {
+	if len(items) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("at least one item is required"), domainErrors.ValidationError)
+	}
+	quantities := map[int]int{}
+	for _, it := range items {
+		if it.ProductID <= 0 || it.Quantity <= 0 {
+			return nil, domainErrors.NewAppError(errors.New("items need a product and a positive quantity"), domainErrors.ValidationError)
+		}
+		quantities[it.ProductID] += it.Quantity
+	}
+	return quantities, nil
+}
+
+func newReservationReference() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "res_" + hex.EncodeToString(b), nil
+}
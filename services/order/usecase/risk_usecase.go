@@ -0,0 +1,188 @@
+package usecase
+
+//go:generate mockgen -source=risk_usecase.go -destination=mocks/risk_usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/audit"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// --- Risk UseCase ---
+
+type IRiskUseCase interface {
+	// GetReviewQueue lists orders held by the risk checks, oldest first
+	GetReviewQueue(ctx context.Context, requester domain.Requester) (*[]domain.Order, error)
+	GetRisk(ctx context.Context, orderID int, requester domain.Requester) (*domain.Order, error)
+	// ReviewOrder releases a held order: approved orders await payment, or are paid when store
+	// credit covered them; rejected orders are cancelled and their store credit returned
+	ReviewOrder(ctx context.Context, orderID int, approve bool, note string, requester domain.Requester) (*domain.Order, error)
+	GetBlocklist(ctx context.Context, requester domain.Requester) (*[]domain.BlocklistEntry, error)
+	AddToBlocklist(ctx context.Context, kind domain.BlocklistKind, value, reason string, requester domain.Requester) (*domain.BlocklistEntry, error)
+	RemoveFromBlocklist(ctx context.Context, id int, requester domain.Requester) error
+}
+
+type RiskUseCase struct {
+	orderRepo     repository.OrderRepositoryInterface
+	paymentRepo   repository.PaymentRepositoryInterface
+	creditRepo    repository.StoreCreditRepositoryInterface
+	blocklistRepo repository.BlocklistRepositoryInterface
+	txManager     psql.TxManager
+	auditor       audit.Auditor
+	Logger        *logger.Logger
+}
+
+func NewRiskUseCase(orderRepo repository.OrderRepositoryInterface, paymentRepo repository.PaymentRepositoryInterface,
+	creditRepo repository.StoreCreditRepositoryInterface, blocklistRepo repository.BlocklistRepositoryInterface, txManager psql.TxManager,
+	auditor audit.Auditor, l *logger.Logger) IRiskUseCase {
+	return &RiskUseCase{
+		orderRepo: orderRepo, paymentRepo: paymentRepo, creditRepo: creditRepo, blocklistRepo: blocklistRepo, txManager: txManager,
+		auditor: auditor, Logger: l,
+	}
+}
+
+const reviewQueueLimit = 200
+
+func (s *RiskUseCase) GetReviewQueue(ctx context.Context, requester domain.Requester) (*[]domain.Order, error) {
+	if err := requireReviewer(requester); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetByStatus(ctx, domain.OrderStatusReview, reviewQueueLimit)
+}
+
+func (s *RiskUseCase) GetRisk(ctx context.Context, orderID int, requester domain.Requester) (*domain.Order, error) {
+	if err := requireReviewer(requester); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetByID(ctx, orderID)
+}
+
+func (s *RiskUseCase) ReviewOrder(ctx context.Context, orderID int, approve bool, note string, requester domain.Requester) (*domain.Order, error) {
+	if err := requireReviewer(requester); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Reviewing held order", zap.Int("orderID", orderID), zap.Bool("approve", approve), zap.Int("actorID", requester.UserID))
+	var before, updated *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		orderRepo, paymentRepo, creditRepo := s.orderRepo.WithTx(tx), s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx)
+		current, err := orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		before = current
+		if current.Status != domain.OrderStatusReview {
+			return domainErrors.NewAppError(errors.New("order is not held for review"), domainErrors.ValidationError).WithCode(domainErrors.CodeInvalidTransition)
+		}
+		if !approve {
+			if err = settlePayments(ctx, paymentRepo, creditRepo, current, domain.OrderStatusCancelled, "", requester); err != nil {
+				return err
+			}
+			updated, err = cancelOrder(ctx, orderRepo, current, domain.CancellationFraud, reviewNote("Rejected after risk review", note), requester)
+			return err
+		}
+		payments, err := paymentRepo.GetByOrderID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		next := domain.OrderStatusPaid
+		for _, p := range *payments {
+			if p.Status == domain.PaymentStatusPending {
+				next = domain.OrderStatusPending
+			}
+		}
+		updated, err = transitionStatus(ctx, orderRepo, current, next, reviewNote("Approved after risk review", note), requester, orderEventPayload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityOrder, orderID, before, updated)
+	return updated, nil
+}
+
+func (s *RiskUseCase) GetBlocklist(ctx context.Context, requester domain.Requester) (*[]domain.BlocklistEntry, error) {
+	if err := requireReviewer(requester); err != nil {
+		return nil, err
+	}
+	return s.blocklistRepo.GetAll(ctx)
+}
+
+func (s *RiskUseCase) AddToBlocklist(ctx context.Context, kind domain.BlocklistKind, value, reason string, requester domain.Requester) (*domain.BlocklistEntry, error) {
+	if err := requireReviewer(requester); err != nil {
+		return nil, err
+	}
+	value, err := normalizeBlocklistValue(kind, value)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Adding blocklist entry", zap.String("kind", string(kind)), zap.Int("actorID", requester.UserID))
+	entry, err := s.blocklistRepo.Create(ctx, &domain.BlocklistEntry{Kind: kind, Value: value, Reason: reason, ActorID: requester.UserID})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionCreate, auditEntityBlocklist, entry.ID, nil, entry)
+	return entry, nil
+}
+
+func (s *RiskUseCase) RemoveFromBlocklist(ctx context.Context, id int, requester domain.Requester) error {
+	if err := requireReviewer(requester); err != nil {
+		return err
+	}
+	s.Logger.Info("Removing blocklist entry", zap.Int("id", id), zap.Int("actorID", requester.UserID))
+	if err := s.blocklistRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditor.Record(ctx, audit.ActionDelete, auditEntityBlocklist, id, nil, nil)
+	return nil
+}
+
+func requireReviewer(requester domain.Requester) error {
+	if !requester.Can(security.PermOrderReview) {
+		return domainErrors.NewAppError(errors.New("order review permission required"), domainErrors.NotAuthorized)
+	}
+	return nil
+}
+
+// normalizeBlocklistValue puts values in the form the risk checks look them up by
+func normalizeBlocklistValue(kind domain.BlocklistKind, value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch kind {
+	case domain.BlocklistEmail:
+		if !strings.Contains(value, "@") {
+			return "", domainErrors.NewAppError(errors.New("invalid email"), domainErrors.ValidationError)
+		}
+	case domain.BlocklistEmailDomain:
+		value = strings.TrimPrefix(value, "@")
+		if value == "" || strings.Contains(value, "@") {
+			return "", domainErrors.NewAppError(errors.New("invalid email domain"), domainErrors.ValidationError)
+		}
+	case domain.BlocklistIP:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", domainErrors.NewAppError(errors.New("invalid IP address"), domainErrors.ValidationError)
+		}
+		value = ip.String()
+	default:
+		return "", domainErrors.NewAppError(errors.New("kind must be email, email_domain or ip"), domainErrors.ValidationError)
+	}
+	return value, nil
+}
+
+func reviewNote(outcome, note string) string {
+	if note == "" {
+		return outcome
+	}
+	return outcome + ": " + note
+}
@@ -0,0 +1,194 @@
+package usecase
+
+//go:generate mockgen -source=shipment_usecase.go -destination=mocks/shipment_usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/carrier"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// --- Shipment UseCase ---
+
+type IShipmentUseCase interface {
+	// Ship records carrier and tracking number and moves a paid order to shipped
+	Ship(ctx context.Context, orderID int, carrierName, trackingNumber string, requester domain.Requester) (*domain.Shipment, error)
+	GetByOrderID(ctx context.Context, orderID int, requester domain.Requester) (*domain.Shipment, error)
+	// ApplyTrackingUpdates stores carrier reports and delivers the order when the carrier does
+	ApplyTrackingUpdates(ctx context.Context, carrierName string, updates []domain.TrackingUpdate) error
+	// PollActiveShipments asks polling carriers for the status of undelivered shipments
+	// and returns how many updates were applied
+	PollActiveShipments(ctx context.Context) (int, error)
+}
+
+type ShipmentUseCase struct {
+	orderRepo    repository.OrderRepositoryInterface
+	shipmentRepo repository.ShipmentRepositoryInterface
+	carriers     *carrier.Registry
+	txManager    psql.TxManager
+	access       authz.Enforcer
+	Logger       *logger.Logger
+}
+
+func NewShipmentUseCase(orderRepo repository.OrderRepositoryInterface, shipmentRepo repository.ShipmentRepositoryInterface,
+	carriers *carrier.Registry, txManager psql.TxManager, access authz.Enforcer, l *logger.Logger) IShipmentUseCase {
+	return &ShipmentUseCase{orderRepo: orderRepo, shipmentRepo: shipmentRepo, carriers: carriers, txManager: txManager, access: access, Logger: l}
+}
+
+const pollBatchSize = 100
+
+func (s *ShipmentUseCase) Ship(ctx context.Context, orderID int, carrierName, trackingNumber string, requester domain.Requester) (*domain.Shipment, error) {
+	if !requester.Can(security.PermOrderShip) {
+		return nil, domainErrors.NewAppError(errors.New("not allowed to ship orders"), domainErrors.NotAuthorized)
+	}
+	adapter, err := s.carriers.Get(carrierName)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	s.Logger.Info("Shipping order", zap.Int("orderID", orderID), zap.String("carrier", adapter.Name()))
+
+	var shipment *domain.Shipment
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		orderRepo, shipmentRepo := s.orderRepo.WithTx(tx), s.shipmentRepo.WithTx(tx)
+		current, err := orderRepo.GetByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if current.Status != domain.OrderStatusPaid && current.Status != domain.OrderStatusShipped {
+			return domainErrors.NewAppError(errors.New("only paid or shipped orders can be given a shipment"), domainErrors.ValidationError).WithCode(domainErrors.CodeInvalidTransition)
+		}
+		existing, err := shipmentRepo.GetByTracking(ctx, adapter.Name(), trackingNumber)
+		if err == nil && existing.OrderID != orderID {
+			return domainErrors.NewAppError(errors.New("tracking number is already used by another order"), domainErrors.ResourceAlreadyExists)
+		}
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+
+		shipment, err = shipmentRepo.Save(tenant.ContextWithTenant(ctx, current.Tenant), &domain.Shipment{
+			OrderID: orderID, Carrier: adapter.Name(), TrackingNumber: trackingNumber,
+			TrackingURL: adapter.TrackingURL(trackingNumber), Status: domain.ShipmentStatusLabelCreated,
+		})
+		if err != nil || current.Status == domain.OrderStatusShipped {
+			return err
+		}
+		_, err = transitionStatus(ctx, orderRepo, current, domain.OrderStatusShipped, "Shipped with "+adapter.Name(), requester, shipmentPayload(shipment))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+func (s *ShipmentUseCase) GetByOrderID(ctx context.Context, orderID int, requester domain.Requester) (*domain.Shipment, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, order) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return s.shipmentRepo.GetByOrderID(ctx, orderID)
+}
+
+func (s *ShipmentUseCase) ApplyTrackingUpdates(ctx context.Context, carrierName string, updates []domain.TrackingUpdate) error {
+	adapter, err := s.carriers.Get(carrierName)
+	if err != nil {
+		return domainErrors.NewAppError(err, domainErrors.NotFound)
+	}
+	for _, update := range updates {
+		if err := s.applyUpdate(ctx, adapter.Name(), update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ShipmentUseCase) applyUpdate(ctx context.Context, carrierName string, update domain.TrackingUpdate) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		orderRepo, shipmentRepo := s.orderRepo.WithTx(tx), s.shipmentRepo.WithTx(tx)
+		shipment, err := shipmentRepo.GetByTracking(ctx, carrierName, update.TrackingNumber)
+		if isNotFound(err) {
+			// carriers report parcels we never registered; acknowledge and drop them
+			s.Logger.Warn("Tracking update for unknown shipment", zap.String("carrier", carrierName), zap.String("trackingNumber", update.TrackingNumber))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// lock the order first so concurrent updates for one parcel are applied in turn
+		current, err := orderRepo.GetByIDForUpdate(ctx, shipment.OrderID)
+		if err != nil {
+			return err
+		}
+		if shipment.LastEventAt != nil && update.OccurredAt.Before(*shipment.LastEventAt) {
+			s.Logger.Debug("Ignoring stale tracking update", zap.Int("shipmentID", shipment.ID))
+			return nil
+		}
+		if shipment, err = shipmentRepo.ApplyUpdate(ctx, shipment.ID, update); err != nil {
+			return err
+		}
+		if update.Status != domain.ShipmentStatusDelivered || current.Status != domain.OrderStatusShipped {
+			return nil
+		}
+		s.Logger.Info("Carrier reported delivery", zap.Int("orderID", current.ID), zap.String("carrier", carrierName))
+		_, err = transitionStatus(ctx, orderRepo, current, domain.OrderStatusDelivered, "Delivered according to "+carrierName,
+			domain.SystemRequester, shipmentPayload(shipment))
+		return err
+	})
+}
+
+func (s *ShipmentUseCase) PollActiveShipments(ctx context.Context) (int, error) {
+	applied := 0
+	for _, adapter := range s.carriers.All() {
+		if !adapter.SupportsPolling() {
+			continue
+		}
+		shipments, err := s.shipmentRepo.GetActive(ctx, adapter.Name(), pollBatchSize)
+		if err != nil {
+			s.Logger.Error("Error loading shipments to poll", zap.String("carrier", adapter.Name()), zap.Error(err))
+			continue
+		}
+		for _, shipment := range *shipments {
+			if ctx.Err() != nil {
+				return applied, ctx.Err()
+			}
+			update, err := adapter.Track(shipment.TrackingNumber)
+			if err != nil {
+				s.Logger.Warn("Error polling carrier", zap.String("carrier", adapter.Name()), zap.String("trackingNumber", shipment.TrackingNumber), zap.Error(err))
+				continue
+			}
+			if update.Status == shipment.Status && shipment.LastEventAt != nil && !update.OccurredAt.After(*shipment.LastEventAt) {
+				continue
+			}
+			if err := s.applyUpdate(ctx, adapter.Name(), *update); err != nil {
+				s.Logger.Error("Error applying polled tracking update", zap.Int("shipmentID", shipment.ID), zap.Error(err))
+				continue
+			}
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+func shipmentPayload(shipment *domain.Shipment) func(*domain.Order) contracts.Order {
+	return func(o *domain.Order) contracts.Order {
+		payload := orderEventPayload(o)
+		payload.Carrier, payload.TrackingNumber, payload.TrackingURL = shipment.Carrier, shipment.TrackingNumber, shipment.TrackingURL
+		return payload
+	}
+}
@@ -1,59 +1,774 @@
 package usecase
 
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
 import (
+	"context"
+	"ecommerce-microservice-go/pkg/currency"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/authz"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/order/client"
 	"ecommerce-microservice-go/services/order/domain"
 	"ecommerce-microservice-go/services/order/repository"
+	"ecommerce-microservice-go/services/order/risk"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type IOrderUseCase interface {
-	GetAll() (*[]domain.Order, error)
-	GetByID(id int) (*domain.Order, error)
-	GetByUserID(userID int) (*[]domain.Order, error)
-	Create(order *domain.Order) (*domain.Order, error)
-	UpdateStatus(id int, status string) (*domain.Order, error)
+	// GetAll lists the customer's own orders, without archived ones unless includeArchived is
+	// set; staff always see every order
+	GetAll(ctx context.Context, requester domain.Requester, includeArchived bool, p pagination.Params) (*[]domain.Order, int64, error)
+	GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Order, error)
+	GetByNumber(ctx context.Context, number string, requester domain.Requester) (*domain.Order, error)
+	// ResolveID returns the integer ID of the order with the UUID; access is checked by the
+	// call that uses the ID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	SearchByNumber(ctx context.Context, prefix string, requester domain.Requester) (*[]domain.Order, error)
+	// Search pages through every order matching the search for staff and the requester's own otherwise
+	Search(ctx context.Context, s pagination.Search, requester domain.Requester) (*[]domain.Order, int64, error)
+	GetByUserID(ctx context.Context, userID int) (*[]domain.Order, error)
+	// GetProducts reads the catalog entries of the products, for inlining them into orders;
+	// products the catalog no longer has are left out
+	GetProducts(ctx context.Context, ids []int) (map[int]*domain.CatalogProduct, error)
+	// FindPurchase returns the user's latest paid order containing the product, or a NotFound error
+	FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error)
+	GetMetrics(ctx context.Context, filter domain.OrderMetricsFilter, requester domain.Requester) (*domain.OrderMetrics, error)
+	Export(ctx context.Context, filter domain.OrderExportFilter, requester domain.Requester, fn func(batch *[]domain.Order) error) error
+	Create(ctx context.Context, order *domain.Order, checkout domain.CheckoutOptions, requester domain.Requester) (*domain.Order, error)
+	// UpdateStatus changes the order status; reason only applies to cancellations and defaults by actor.
+	// A non-zero version must match the order's, or the change fails with a Conflict.
+	UpdateStatus(ctx context.Context, id, version int, status string, note string, reason domain.CancellationReason, requester domain.Requester) (*domain.Order, error)
+	GetTimeline(ctx context.Context, id int, requester domain.Requester) (*[]domain.OrderStatusEvent, error)
+	AddComment(ctx context.Context, id int, body string, requester domain.Requester) (*domain.OrderComment, error)
+	GetComments(ctx context.Context, id int, requester domain.Requester) (*[]domain.OrderComment, error)
+	// SetArchived hides a delivered or cancelled order from its customer's default listing, or restores it
+	SetArchived(ctx context.Context, id int, archived bool, requester domain.Requester) (*domain.Order, error)
+	// CancelUnpaid cancels orders still pending after the payment window and returns how many
+	CancelUnpaid(ctx context.Context, paymentWindow time.Duration) (int, error)
+	// CancelOpenForUser cancels the user's unpaid orders before their account is deleted and
+	// returns how many. It fails with a ValidationError, cancelling nothing, while any of their
+	// orders is paid or shipped, since those still have to be fulfilled or refunded.
+	CancelOpenForUser(ctx context.Context, userID int) (int, error)
 }
 
 type OrderUseCase struct {
-	repo   repository.OrderRepositoryInterface
-	Logger *logger.Logger
+	repo            repository.OrderRepositoryInterface
+	paymentRepo     repository.PaymentRepositoryInterface
+	creditRepo      repository.StoreCreditRepositoryInterface
+	reservationRepo repository.ReservationRepositoryInterface
+	txManager       psql.TxManager
+	rates           currency.RateProvider
+	risk            risk.Assessor
+	// promotions, when set, decides the promotion discounts of new orders
+	promotions client.IPromotionClient
+	catalog    client.ICatalogClient
+	// access decides from the authorization policies who may see an order
+	access  authz.Enforcer
+	auditor audit.Auditor
+	Logger  *logger.Logger
+}
+
+func NewOrderUseCase(r repository.OrderRepositoryInterface, paymentRepo repository.PaymentRepositoryInterface, creditRepo repository.StoreCreditRepositoryInterface,
+	reservationRepo repository.ReservationRepositoryInterface, txManager psql.TxManager, rates currency.RateProvider, assessor risk.Assessor,
+	promotions client.IPromotionClient, catalog client.ICatalogClient, access authz.Enforcer, auditor audit.Auditor, l *logger.Logger) IOrderUseCase {
+	return &OrderUseCase{
+		repo: r, paymentRepo: paymentRepo, creditRepo: creditRepo, reservationRepo: reservationRepo,
+		txManager: txManager, rates: rates, risk: assessor, promotions: promotions, catalog: catalog, access: access, auditor: auditor, Logger: l,
+	}
 }
 
-func NewOrderUseCase(r repository.OrderRepositoryInterface, l *logger.Logger) IOrderUseCase {
-	return &OrderUseCase{repo: r, Logger: l}
+// canSee reports whether the authorization policies let the requester see the order, which
+// by default customers may only for their own orders
+func canSee(ctx context.Context, access authz.Enforcer, requester domain.Requester, o *domain.Order) bool {
+	return access.Allowed(ctx, requester.Subject(), o.AuthzObject(), authz.ActionRead)
 }
 
-func (s *OrderUseCase) GetAll() (*[]domain.Order, error) {
-	s.Logger.Info("Getting all orders")
-	return s.repo.GetAll()
+const (
+	auditEntityOrder       = "order"
+	auditEntityBlocklist   = "risk_blocklist"
+	auditEntityStoreCredit = "store_credit"
+)
+
+// GetAll returns every order for staff and only the requester's own orders otherwise
+func (s *OrderUseCase) GetAll(ctx context.Context, requester domain.Requester, includeArchived bool, p pagination.Params) (*[]domain.Order, int64, error) {
+	if !requester.IsStaff {
+		s.Logger.Info("Getting orders by user ID", zap.Int("userID", requester.UserID), zap.Bool("includeArchived", includeArchived), zap.Int("page", p.Page))
+		return s.repo.GetPageByUserID(ctx, requester.UserID, includeArchived, p)
+	}
+	s.Logger.Info("Getting all orders", zap.Int("page", p.Page))
+	return s.repo.GetAll(ctx, p)
 }
 
-func (s *OrderUseCase) GetByID(id int) (*domain.Order, error) {
+func (s *OrderUseCase) GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Order, error) {
 	s.Logger.Info("Getting order by ID", zap.Int("id", id))
-	return s.repo.GetByID(id)
+	o, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, o) {
+		s.Logger.Warn("Order access denied", zap.Int("id", id), zap.Int("userID", requester.UserID))
+		return nil, domainErrors.NewAppError(errors.New("order belongs to another user"), domainErrors.NotAuthorized)
+	}
+	return o, nil
 }
 
-func (s *OrderUseCase) GetByUserID(userID int) (*[]domain.Order, error) {
+func (s *OrderUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
+}
+
+func (s *OrderUseCase) FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error) {
+	return s.repo.FindPurchase(ctx, userID, productID)
+}
+
+func (s *OrderUseCase) GetByNumber(ctx context.Context, number string, requester domain.Requester) (*domain.Order, error) {
+	s.Logger.Info("Getting order by number", zap.String("number", number))
+	o, err := s.repo.GetByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, o) {
+		// do not reveal that another user's order number exists
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return o, nil
+}
+
+func (s *OrderUseCase) SearchByNumber(ctx context.Context, prefix string, requester domain.Requester) (*[]domain.Order, error) {
+	s.Logger.Info("Searching orders by number", zap.String("prefix", prefix))
+	userID := requester.UserID
+	if requester.IsStaff {
+		userID = 0
+	}
+	return s.repo.SearchByNumber(ctx, prefix, userID)
+}
+
+func (s *OrderUseCase) Search(ctx context.Context, search pagination.Search, requester domain.Requester) (*[]domain.Order, int64, error) {
+	s.Logger.Info("Searching orders", zap.Int("page", search.Page), zap.Int("limit", search.Limit))
+	userID := requester.UserID
+	if requester.IsStaff {
+		userID = 0
+	}
+	return s.repo.SearchPaginated(ctx, search, userID)
+}
+
+func (s *OrderUseCase) GetByUserID(ctx context.Context, userID int) (*[]domain.Order, error) {
 	s.Logger.Info("Getting orders by user ID", zap.Int("userID", userID))
-	return s.repo.GetByUserID(userID)
+	return s.repo.GetByUserID(ctx, userID, true)
+}
+
+func (s *OrderUseCase) GetProducts(ctx context.Context, ids []int) (map[int]*domain.CatalogProduct, error) {
+	products := make(map[int]*domain.CatalogProduct, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		product, err := s.catalog.GetProduct(ctx, id)
+		if errors.Is(err, client.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			s.Logger.Error("Error loading product to expand", zap.Int("productID", id), zap.Error(err))
+			return nil, domainErrors.NewAppError(fmt.Errorf("catalog unavailable: %w", err), domainErrors.UnknownError)
+		}
+		products[id] = product
+	}
+	return products, nil
+}
+
+func (s *OrderUseCase) SetArchived(ctx context.Context, id int, archived bool, requester domain.Requester) (*domain.Order, error) {
+	o, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, s.access, requester, o) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	if o.UserID != requester.UserID {
+		return nil, domainErrors.NewAppError(errors.New("only the customer can archive their order"), domainErrors.NotAuthorized)
+	}
+	if !archived {
+		if o.ArchivedAt == nil {
+			return o, nil
+		}
+		s.Logger.Info("Restoring archived order", zap.Int("id", id))
+		return s.setArchivedAt(ctx, o, nil)
+	}
+	if !o.Status.IsFinal() {
+		return nil, domainErrors.NewAppError(errors.New("only delivered or cancelled orders can be archived"), domainErrors.ValidationError).WithCode(domainErrors.CodeInvalidTransition)
+	}
+	if o.ArchivedAt != nil {
+		return o, nil
+	}
+	s.Logger.Info("Archiving order", zap.Int("id", id))
+	now := time.Now()
+	return s.setArchivedAt(ctx, o, &now)
+}
+
+func (s *OrderUseCase) setArchivedAt(ctx context.Context, before *domain.Order, at *time.Time) (*domain.Order, error) {
+	updated, err := s.repo.SetArchivedAt(ctx, before.ID, at)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityOrder, before.ID, before, updated)
+	return updated, nil
+}
+
+// GetMetrics folds the per-period, per-status aggregates into range totals and period series
+func (s *OrderUseCase) GetMetrics(ctx context.Context, filter domain.OrderMetricsFilter, requester domain.Requester) (*domain.OrderMetrics, error) {
+	if !requester.IsAdmin {
+		return nil, domainErrors.NewAppError(errors.New("admin role required"), domainErrors.NotAuthorized)
+	}
+	s.Logger.Info("Getting order metrics", zap.String("groupBy", string(filter.GroupBy)))
+	rows, err := s.repo.GetMetrics(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &domain.OrderMetrics{GroupBy: filter.GroupBy, Currency: s.rates.BaseCurrency(), OrderMetricsSummary: newMetricsSummary()}
+	paidOrders := int64(0)
+	periodPaid := []int64{}
+	for _, row := range *rows {
+		n := len(metrics.Periods)
+		if n == 0 || !metrics.Periods[n-1].PeriodStart.Equal(row.PeriodStart) {
+			metrics.Periods = append(metrics.Periods, domain.OrderMetricsPeriod{PeriodStart: row.PeriodStart, OrderMetricsSummary: newMetricsSummary()})
+			periodPaid = append(periodPaid, 0)
+			n++
+		}
+		period := &metrics.Periods[n-1]
+		addMetricsRow(&metrics.OrderMetricsSummary, row)
+		addMetricsRow(&period.OrderMetricsSummary, row)
+		if row.Status.CountsAsRevenue() {
+			paidOrders += row.Orders
+			periodPaid[n-1] += row.Orders
+		}
+	}
+	metrics.Revenue = round2(metrics.Revenue)
+	metrics.AverageOrderValue = averageOrderValue(metrics.Revenue, paidOrders)
+	for i := range metrics.Periods {
+		metrics.Periods[i].Revenue = round2(metrics.Periods[i].Revenue)
+		metrics.Periods[i].AverageOrderValue = averageOrderValue(metrics.Periods[i].Revenue, periodPaid[i])
+	}
+	return metrics, nil
+}
+
+func newMetricsSummary() domain.OrderMetricsSummary {
+	return domain.OrderMetricsSummary{
+		StatusBreakdown: map[domain.OrderStatus]int64{}, CancellationReasons: map[domain.CancellationReason]int64{}, CancelledBy: map[string]int64{},
+	}
+}
+
+func addMetricsRow(summary *domain.OrderMetricsSummary, row domain.OrderMetricsRow) {
+	summary.TotalOrders += row.Orders
+	summary.StatusBreakdown[row.Status] += row.Orders
+	if row.Status == domain.OrderStatusCancelled {
+		summary.CancellationReasons[cancellationReasonOrOther(row.CancellationReason)] += row.Orders
+		if row.CancelledBy != "" {
+			summary.CancelledBy[row.CancelledBy] += row.Orders
+		}
+	}
+	if row.Status.CountsAsRevenue() {
+		summary.Revenue += row.Amount
+	}
+}
+
+// cancellationReasonOrOther files orders cancelled before reasons were recorded under other
+func cancellationReasonOrOther(reason domain.CancellationReason) domain.CancellationReason {
+	if reason == "" {
+		return domain.CancellationOther
+	}
+	return reason
 }
 
-func (s *OrderUseCase) Create(order *domain.Order) (*domain.Order, error) {
+func averageOrderValue(revenue float64, orders int64) float64 {
+	if orders == 0 {
+		return 0
+	}
+	return round2(revenue / float64(orders))
+}
+
+const exportBatchSize = 500
+
+// Export streams matching orders to fn in batches; restricted to admins
+func (s *OrderUseCase) Export(ctx context.Context, filter domain.OrderExportFilter, requester domain.Requester, fn func(batch *[]domain.Order) error) error {
+	if !requester.IsAdmin {
+		return domainErrors.NewAppError(errors.New("admin role required"), domainErrors.NotAuthorized)
+	}
+	s.Logger.Info("Exporting orders", zap.Time("from", filter.From), zap.Time("to", filter.To), zap.String("status", string(filter.Status)))
+	return s.repo.ExportInBatches(ctx, filter, exportBatchSize, fn)
+}
+
+// Create stores the order, its items, payments, the first timeline entry, the outbox events and
+// the idempotency key in one transaction, consuming the checkout reservation when one is given.
+// A key already used by the user returns the original order. An order fully covered by store
+// credit is paid immediately.
+func (s *OrderUseCase) Create(ctx context.Context, order *domain.Order, checkout domain.CheckoutOptions, requester domain.Requester) (*domain.Order, error) {
+	idempotencyKey := checkout.IdempotencyKey
 	s.Logger.Info("Creating order", zap.Int("userID", order.UserID))
-	// Calculate subtotals and total
-	var total float64
-	for i := range order.Items {
-		order.Items[i].Subtotal = float64(order.Items[i].Quantity) * order.Items[i].Price
-		total += order.Items[i].Subtotal
+	if err := checkRequestedDiscounts(order, requester); err != nil {
+		return nil, err
+	}
+	if err := s.applyPromotions(ctx, order); err != nil {
+		return nil, err
+	}
+	if err := applyDiscounts(order, requester); err != nil {
+		return nil, err
 	}
-	order.TotalAmount = total
 	order.Status = domain.OrderStatusPending
-	return s.repo.Create(order)
+	if err := snapshotExchangeRate(s.rates, order); err != nil {
+		return nil, err
+	}
+	assessment := s.risk.Assess(ctx, order)
+	order.RiskScore, order.RiskSignals = assessment.Score, assessment.Signals
+	createNote := ""
+	if assessment.Review {
+		s.Logger.Warn("Holding order for risk review", zap.Int("userID", order.UserID), zap.Int("riskScore", assessment.Score))
+		order.Status, createNote = domain.OrderStatusReview, "Held for risk review"
+	}
+
+	var created *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		if idempotencyKey != "" {
+			existingID, err := repo.GetOrderIDByIdempotencyKey(ctx, order.UserID, idempotencyKey)
+			if err != nil {
+				return err
+			}
+			if existingID != 0 {
+				s.Logger.Info("Replaying idempotent order", zap.Int("id", existingID))
+				created, err = repo.GetByID(ctx, existingID)
+				return err
+			}
+		}
+		if checkout.ReservationID != "" {
+			if err := consumeReservation(ctx, s.reservationRepo.WithTx(tx), checkout.ReservationID, order); err != nil {
+				return err
+			}
+		}
+		var err error
+		if created, err = repo.Create(ctx, order); err != nil {
+			return err
+		}
+		if err = repo.AddStatusEvent(ctx, statusChange(created.ID, "", created.Status, createNote, requester)); err != nil {
+			return err
+		}
+		if err = saveOrderEvent(ctx, repo, contracts.OrderCreated, created, orderEventPayload); err != nil {
+			return err
+		}
+		paid, err := takeCheckoutPayments(ctx, s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx), created, checkout.StoreCreditAmount, requester)
+		if err != nil {
+			return err
+		}
+		if paid && created.Status == domain.OrderStatusPending {
+			if created, err = transitionStatus(ctx, repo, created, domain.OrderStatusPaid, "Paid with store credit", requester, orderEventPayload); err != nil {
+				return err
+			}
+		}
+		if idempotencyKey != "" {
+			return repo.SaveIdempotencyKey(ctx, order.UserID, idempotencyKey, created.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
-func (s *OrderUseCase) UpdateStatus(id int, status string) (*domain.Order, error) {
+// checkRequestedDiscounts vets the discounts sent with a new order. Their amounts are taken as
+// sent, so only staff may give them; promotion discounts only ever come from the promotion service.
+func checkRequestedDiscounts(order *domain.Order, requester domain.Requester) error {
+	check := func(discounts []domain.OrderDiscount) error {
+		for _, d := range discounts {
+			if d.Source == domain.DiscountSourcePromotion {
+				return domainErrors.NewAppError(errors.New("promotion discounts are applied automatically"), domainErrors.ValidationError)
+			}
+			if !requester.IsStaff && !requester.IsSystem {
+				return domainErrors.NewAppError(fmt.Errorf("%s discounts require a staff role", d.Source), domainErrors.NotAuthorized)
+			}
+		}
+		return nil
+	}
+	if err := check(order.Discounts); err != nil {
+		return err
+	}
+	for _, item := range order.Items {
+		if err := check(item.Discounts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyPromotions adds the discounts of the promotions the customer qualifies for, as evaluated
+// by the promotion service, to the order items. Without a promotion service there are none.
+func (s *OrderUseCase) applyPromotions(ctx context.Context, order *domain.Order) error {
+	if s.promotions == nil {
+		return nil
+	}
+	items := make([]domain.PromotionItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = domain.PromotionItem{ProductID: item.ProductID, Quantity: item.Quantity, Price: item.Price}
+	}
+	currencyCode := strings.ToUpper(order.Currency)
+	if currencyCode == "" {
+		currencyCode = s.rates.BaseCurrency()
+	}
+	discounts, err := s.promotions.Evaluate(ctx, order.UserID, currencyCode, items)
+	if errors.Is(err, client.ErrPromotionRejected) {
+		return domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if err != nil {
+		s.Logger.Error("Error evaluating promotions", zap.Int("userID", order.UserID), zap.Error(err))
+		return err
+	}
+	for i, lineDiscounts := range discounts {
+		for _, d := range lineDiscounts {
+			order.Items[i].Discounts = append(order.Items[i].Discounts, domain.OrderDiscount{
+				Source: domain.DiscountSourcePromotion, Code: d.PromotionID, Description: d.Name, Amount: d.Amount,
+			})
+		}
+	}
+	return nil
+}
+
+// applyDiscounts fills in subtotals and totals. Item discounts may not exceed their item subtotal
+// and order discounts may not exceed what is left.
+func applyDiscounts(order *domain.Order, requester domain.Requester) error {
+	var subtotal, discounted float64
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.Subtotal = round2(float64(item.Quantity) * item.Price)
+		item.DiscountAmount = 0
+		for j := range item.Discounts {
+			if err := validateDiscount(&item.Discounts[j], requester); err != nil {
+				return err
+			}
+			item.DiscountAmount = round2(item.DiscountAmount + item.Discounts[j].Amount)
+		}
+		if item.DiscountAmount > item.Subtotal {
+			return domainErrors.NewAppError(fmt.Errorf("discounts on product %d exceed its subtotal", item.ProductID), domainErrors.ValidationError)
+		}
+		subtotal += item.Subtotal
+		discounted += item.DiscountAmount
+	}
+	for j := range order.Discounts {
+		if err := validateDiscount(&order.Discounts[j], requester); err != nil {
+			return err
+		}
+		discounted += order.Discounts[j].Amount
+	}
+	order.SubtotalAmount, order.DiscountAmount = round2(subtotal), round2(discounted)
+	if order.DiscountAmount > order.SubtotalAmount {
+		return domainErrors.NewAppError(errors.New("discounts exceed the order subtotal"), domainErrors.ValidationError)
+	}
+	order.TotalAmount = round2(order.SubtotalAmount - order.DiscountAmount)
+	return nil
+}
+
+func validateDiscount(d *domain.OrderDiscount, requester domain.Requester) error {
+	if !d.Source.IsValid() {
+		return domainErrors.NewAppError(fmt.Errorf("invalid discount source %q", d.Source), domainErrors.ValidationError)
+	}
+	d.Amount = round2(d.Amount)
+	if d.Amount <= 0 {
+		return domainErrors.NewAppError(errors.New("discount amount must be positive"), domainErrors.ValidationError)
+	}
+	d.ActorID = requester.UserID
+	return nil
+}
+
+// snapshotExchangeRate fixes the order currency rate so later rate changes do not alter reported totals
+func snapshotExchangeRate(rates currency.RateProvider, order *domain.Order) error {
+	order.Currency = strings.ToUpper(order.Currency)
+	if order.Currency == "" {
+		order.Currency = rates.BaseCurrency()
+	}
+	rate, err := rates.Rate(order.Currency)
+	if err != nil {
+		return domainErrors.NewAppError(fmt.Errorf("%w: %s", err, order.Currency), domainErrors.ValidationError)
+	}
+	order.BaseCurrency = rates.BaseCurrency()
+	order.ExchangeRate = rate
+	order.BaseTotalAmount = round2(order.TotalAmount * rate)
+	return nil
+}
+
+func (s *OrderUseCase) UpdateStatus(ctx context.Context, id, version int, status string, note string, reason domain.CancellationReason, requester domain.Requester) (*domain.Order, error) {
 	s.Logger.Info("Updating order status", zap.Int("id", id), zap.String("status", status))
-	return s.repo.UpdateStatus(id, status)
+	newStatus := domain.OrderStatus(status)
+	if reason != "" && !reason.IsValid() {
+		return nil, domainErrors.NewAppError(fmt.Errorf("invalid cancellation reason %q", reason), domainErrors.ValidationError)
+	}
+	var before, updated *domain.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		current, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before = current
+		if !canSee(ctx, s.access, requester, current) {
+			s.Logger.Warn("Order access denied", zap.Int("id", id), zap.Int("userID", requester.UserID))
+			return domainErrors.NewAppError(errors.New("order belongs to another user"), domainErrors.NotAuthorized)
+		}
+		if version != 0 && version != current.Version {
+			return psql.NewConflictError("orders", id, version, current.Version)
+		}
+		if err = authorizeStatusChange(current, newStatus, requester); err != nil {
+			return err
+		}
+		if err = settlePayments(ctx, s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx), current, newStatus, "", requester); err != nil {
+			return err
+		}
+		if newStatus == domain.OrderStatusCancelled {
+			updated, err = cancelOrder(ctx, repo, current, defaultCancellationReason(reason, requester), note, requester)
+			return err
+		}
+		updated, err = transitionStatus(ctx, repo, current, newStatus, note, requester, orderEventPayload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityOrder, id, before, updated)
+	return updated, nil
+}
+
+func (s *OrderUseCase) GetTimeline(ctx context.Context, id int, requester domain.Requester) (*[]domain.OrderStatusEvent, error) {
+	if _, err := s.GetByID(ctx, id, requester); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTimeline(ctx, id)
+}
+
+// AddComment stores an internal staff comment on the order
+func (s *OrderUseCase) AddComment(ctx context.Context, id int, body string, requester domain.Requester) (*domain.OrderComment, error) {
+	if err := requireStaff(requester); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Adding order comment", zap.Int("id", id), zap.Int("authorID", requester.UserID))
+	return s.repo.AddComment(ctx, &domain.OrderComment{OrderID: id, AuthorID: requester.UserID, Body: body})
+}
+
+func (s *OrderUseCase) GetComments(ctx context.Context, id int, requester domain.Requester) (*[]domain.OrderComment, error) {
+	if err := requireStaff(requester); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetComments(ctx, id)
+}
+
+// transitionStatus moves a locked order to newStatus, records the timeline entry and queues
+// the status event. It must run inside a transaction on a repository bound with WithTx.
+func transitionStatus(ctx context.Context, repo repository.OrderRepositoryInterface, current *domain.Order, newStatus domain.OrderStatus, note string,
+	requester domain.Requester, payload func(*domain.Order) contracts.Order) (*domain.Order, error) {
+	updated, err := repo.UpdateStatus(ctx, current.ID, current.Version, newStatus)
+	if err != nil {
+		return nil, err
+	}
+	if err = repo.AddStatusEvent(ctx, statusChange(current.ID, current.Status, newStatus, note, requester)); err != nil {
+		return nil, err
+	}
+	if contract, ok := statusEvents[newStatus]; ok {
+		if err = saveOrderEvent(ctx, repo, contract, updated, payload); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// cancelOrder records why and by whom the order was cancelled before moving it to cancelled,
+// so the order.cancelled event carries both
+func cancelOrder(ctx context.Context, repo repository.OrderRepositoryInterface, current *domain.Order, reason domain.CancellationReason, note string,
+	requester domain.Requester) (*domain.Order, error) {
+	if err := repo.SetCancellation(ctx, current.ID, reason, requester.ActorType()); err != nil {
+		return nil, err
+	}
+	return transitionStatus(ctx, repo, current, domain.OrderStatusCancelled, note, requester, orderEventPayload)
+}
+
+func defaultCancellationReason(reason domain.CancellationReason, requester domain.Requester) domain.CancellationReason {
+	if reason != "" {
+		return reason
+	}
+	if requester.ActorType() == domain.ActorCustomer {
+		return domain.CancellationChangedMind
+	}
+	return domain.CancellationOther
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, domainErrors.ErrNotFound)
+}
+
+const cancelUnpaidBatchSize = 100
+
+// CancelUnpaid sweeps orders still pending after paymentWindow. Each order is re-checked under
+// a row lock so a payment recorded meanwhile wins; the order.cancelled event carries the items
+// so stock held for the order can be released by its consumers.
+func (s *OrderUseCase) CancelUnpaid(ctx context.Context, paymentWindow time.Duration) (int, error) {
+	ids, err := s.repo.GetPendingIDsUnchangedSince(ctx, time.Now().Add(-paymentWindow), cancelUnpaidBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	note := fmt.Sprintf("Cancelled automatically: payment not received within %s", paymentWindow)
+	cancelled := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return cancelled, ctx.Err()
+		}
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+			repo := s.repo.WithTx(tx)
+			current, err := repo.GetByIDForUpdate(ctx, id)
+			if err != nil || current.Status != domain.OrderStatusPending {
+				return err
+			}
+			if err = settlePayments(ctx, s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx), current, domain.OrderStatusCancelled, "", domain.SystemRequester); err != nil {
+				return err
+			}
+			if _, err = cancelOrder(ctx, repo, current, domain.CancellationPaymentFailed, note, domain.SystemRequester); err != nil {
+				return err
+			}
+			cancelled++
+			return nil
+		})
+		if err != nil {
+			return cancelled, err
+		}
+	}
+	return cancelled, nil
+}
+
+func (s *OrderUseCase) CancelOpenForUser(ctx context.Context, userID int) (int, error) {
+	orders, err := s.repo.GetByUserID(ctx, userID, true)
+	if err != nil {
+		return 0, err
+	}
+	var open []int
+	for _, o := range *orders {
+		switch o.Status {
+		case domain.OrderStatusPaid, domain.OrderStatusShipped:
+			return 0, domainErrors.NewAppError(fmt.Errorf("order %s is %s and must be fulfilled or refunded first", o.Number, o.Status), domainErrors.ValidationError)
+		case domain.OrderStatusPending, domain.OrderStatusReview:
+			open = append(open, o.ID)
+		}
+	}
+	s.Logger.Info("Cancelling open orders of deleted account", zap.Int("userID", userID), zap.Int("orders", len(open)))
+	cancelled := 0
+	for _, id := range open {
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, tx *gorm.DB) error {
+			repo := s.repo.WithTx(tx)
+			current, err := repo.GetByIDForUpdate(ctx, id)
+			if err != nil || (current.Status != domain.OrderStatusPending && current.Status != domain.OrderStatusReview) {
+				return err
+			}
+			if err = settlePayments(ctx, s.paymentRepo.WithTx(tx), s.creditRepo.WithTx(tx), current, domain.OrderStatusCancelled, "", domain.SystemRequester); err != nil {
+				return err
+			}
+			if _, err = cancelOrder(ctx, repo, current, domain.CancellationAccountDeleted, "Customer account deleted", domain.SystemRequester); err != nil {
+				return err
+			}
+			cancelled++
+			return nil
+		})
+		if err != nil {
+			return cancelled, err
+		}
+	}
+	return cancelled, nil
+}
+
+func requireStaff(requester domain.Requester) error {
+	if !requester.IsStaff && !requester.IsSystem {
+		return domainErrors.NewAppError(errors.New("staff role required"), domainErrors.NotAuthorized)
+	}
+	return nil
+}
+
+// statusPermissions is the permission needed to move an order into each status
+var statusPermissions = map[domain.OrderStatus]security.Permission{
+	domain.OrderStatusPending:   security.PermOrderUpdateAny,
+	domain.OrderStatusPaid:      security.PermOrderMarkPaid,
+	domain.OrderStatusShipped:   security.PermOrderShip,
+	domain.OrderStatusDelivered: security.PermOrderDeliver,
+	domain.OrderStatusCancelled: security.PermOrderCancelAny,
+	domain.OrderStatusReview:    security.PermOrderReview,
+}
+
+// authorizeStatusChange lets customers cancel their own pending orders; every other change needs
+// the permission for the target status. Payment and carrier integrations act as the system.
+func authorizeStatusChange(current *domain.Order, newStatus domain.OrderStatus, requester domain.Requester) error {
+	perm, ok := statusPermissions[newStatus]
+	if !ok {
+		return domainErrors.NewAppError(fmt.Errorf("invalid status %q", newStatus), domainErrors.ValidationError)
+	}
+	if requester.Can(perm) {
+		return nil
+	}
+	if newStatus == domain.OrderStatusCancelled && current.UserID == requester.UserID && requester.Can(security.PermOrderCancelOwn) {
+		if current.Status != domain.OrderStatusPending {
+			return domainErrors.NewAppError(errors.New("only pending orders can be cancelled"), domainErrors.ValidationError).WithCode(domainErrors.CodeInvalidTransition)
+		}
+		return nil
+	}
+	return domainErrors.NewAppError(fmt.Errorf("not allowed to set status %s", newStatus), domainErrors.NotAuthorized)
+}
+
+func statusChange(orderID int, oldStatus, newStatus domain.OrderStatus, note string, requester domain.Requester) *domain.OrderStatusEvent {
+	return &domain.OrderStatusEvent{
+		OrderID: orderID, OldStatus: oldStatus, NewStatus: newStatus,
+		ActorID: requester.UserID, ActorType: requester.ActorType(), Note: note,
+	}
+}
+
+var statusEvents = map[domain.OrderStatus]contracts.Contract[contracts.Order]{
+	domain.OrderStatusPaid:      contracts.OrderPaid,
+	domain.OrderStatusShipped:   contracts.OrderShipped,
+	domain.OrderStatusDelivered: contracts.OrderDelivered,
+	domain.OrderStatusCancelled: contracts.OrderCancelled,
+}
+
+// saveOrderEvent writes the event to the outbox; the relay publishes it after commit. The event
+// belongs to the order's tenant, also when a saga step or job changed it acting across tenants.
+func saveOrderEvent(ctx context.Context, repo repository.OrderRepositoryInterface, contract contracts.Contract[contracts.Order], o *domain.Order,
+	payload func(*domain.Order) contracts.Order) error {
+	event, err := contract.NewEvent(tenant.ContextWithTenant(ctx, o.Tenant), payload(o))
+	if err != nil {
+		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	}
+	return repo.SaveOutboxEvent(ctx, event)
+}
+
+func orderEventPayload(o *domain.Order) contracts.Order {
+	items := make([]contracts.OrderItem, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = contracts.OrderItem{
+			ID: item.ID, ProductID: item.ProductID, Quantity: item.Quantity, Price: item.Price, Subtotal: item.Subtotal, DiscountAmount: item.DiscountAmount,
+		}
+	}
+	return contracts.Order{
+		OrderID: o.ID, OrderNumber: o.Number, UserID: o.UserID, Status: string(o.Status), TotalAmount: o.TotalAmount, Currency: o.Currency, Items: items,
+		DiscountAmount: o.DiscountAmount, CancellationReason: string(o.CancellationReason), CancelledBy: o.CancelledBy,
+	}
 }
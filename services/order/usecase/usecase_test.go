@@ -0,0 +1,405 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	auditmocks "ecommerce-microservice-go/pkg/audit/mocks"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	psqlmocks "ecommerce-microservice-go/pkg/psql/mocks"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/services/order/carrier"
+	carriermocks "ecommerce-microservice-go/services/order/carrier/mocks"
+	"ecommerce-microservice-go/services/order/domain"
+	"ecommerce-microservice-go/services/order/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	staff      = domain.Requester{UserID: 1, Role: security.RoleStaff, IsStaff: true}
+	fulfilment = domain.Requester{UserID: 2, Role: security.RoleFulfillment}
+	customer   = domain.Requester{UserID: 7, Role: security.RoleCustomer}
+	notFound   = domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+)
+
+// inTransaction runs every transaction's function, as the real manager does when it commits
+func inTransaction(tx *psqlmocks.MockTxManager) {
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, *gorm.DB) error) error { return fn(ctx, nil) }).AnyTimes()
+}
+
+// eventOfType matches an outbox event of the type
+type eventOfType string
+
+func (t eventOfType) Matches(x any) bool {
+	e, ok := x.(*events.Event)
+	return ok && e.Type == string(t)
+}
+
+func (t eventOfType) String() string { return "is a " + string(t) + " event" }
+
+// transitions expects the order to move to the status and, when it has one, its event to be queued
+func transitions(orders *mocks.MockOrderRepositoryInterface, current *domain.Order, status domain.OrderStatus) {
+	updated := *current
+	updated.Status, updated.Version = status, current.Version+1
+	orders.EXPECT().UpdateStatus(gomock.Any(), current.ID, current.Version, status).Return(&updated, nil)
+	orders.EXPECT().AddStatusEvent(gomock.Any(), gomock.Any()).Return(nil)
+	if contract, ok := statusEvents[status]; ok {
+		orders.EXPECT().SaveOutboxEvent(gomock.Any(), eventOfType(contract.EventType())).Return(nil)
+	}
+}
+
+// riskMocks are the dependencies of RiskUseCase
+type riskMocks struct {
+	orders    *mocks.MockOrderRepositoryInterface
+	payments  *mocks.MockPaymentRepositoryInterface
+	credit    *mocks.MockStoreCreditRepositoryInterface
+	blocklist *mocks.MockBlocklistRepositoryInterface
+	auditor   *auditmocks.MockAuditor
+}
+
+func newRiskUseCase(t *testing.T) (IRiskUseCase, riskMocks) {
+	ctrl := gomock.NewController(t)
+	m := riskMocks{
+		orders: mocks.NewMockOrderRepositoryInterface(ctrl), payments: mocks.NewMockPaymentRepositoryInterface(ctrl),
+		credit: mocks.NewMockStoreCreditRepositoryInterface(ctrl), blocklist: mocks.NewMockBlocklistRepositoryInterface(ctrl),
+		auditor: auditmocks.NewMockAuditor(ctrl),
+	}
+	m.orders.EXPECT().WithTx(gomock.Any()).Return(m.orders).AnyTimes()
+	m.payments.EXPECT().WithTx(gomock.Any()).Return(m.payments).AnyTimes()
+	m.credit.EXPECT().WithTx(gomock.Any()).Return(m.credit).AnyTimes()
+	tx := psqlmocks.NewMockTxManager(ctrl)
+	inTransaction(tx)
+	return NewRiskUseCase(m.orders, m.payments, m.credit, m.blocklist, tx, m.auditor, testLogger), m
+}
+
+func TestRiskUseCaseReviewOrder(t *testing.T) {
+	held := &domain.Order{ID: 12, UserID: 7, Status: domain.OrderStatusReview, Version: 3}
+	credit := domain.Payment{ID: 1, OrderID: 12, Method: domain.PaymentMethodStoreCredit, Status: domain.PaymentStatusCaptured, Amount: 20, BaseAmount: 20}
+	card := domain.Payment{ID: 2, OrderID: 12, Method: domain.PaymentMethodCard, Status: domain.PaymentStatusPending, Amount: 30}
+
+	tests := []struct {
+		name       string
+		approve    bool
+		requester  domain.Requester
+		order      *domain.Order
+		setup      func(m riskMocks)
+		wantStatus domain.OrderStatus
+		wantErr    error
+	}{
+		{
+			name:      "approved order awaits its card payment",
+			approve:   true,
+			requester: staff,
+			order:     held,
+			setup: func(m riskMocks) {
+				m.payments.EXPECT().GetByOrderID(gomock.Any(), 12).Return(&[]domain.Payment{credit, card}, nil)
+				transitions(m.orders, held, domain.OrderStatusPending)
+			},
+			wantStatus: domain.OrderStatusPending,
+		},
+		{
+			name:      "approved order covered by store credit is paid",
+			approve:   true,
+			requester: staff,
+			order:     held,
+			setup: func(m riskMocks) {
+				m.payments.EXPECT().GetByOrderID(gomock.Any(), 12).Return(&[]domain.Payment{credit}, nil)
+				transitions(m.orders, held, domain.OrderStatusPaid)
+			},
+			wantStatus: domain.OrderStatusPaid,
+		},
+		{
+			name:      "rejected order returns the store credit",
+			requester: staff,
+			order:     held,
+			setup: func(m riskMocks) {
+				m.payments.EXPECT().GetByOrderID(gomock.Any(), 12).Return(&[]domain.Payment{credit, card}, nil)
+				m.payments.EXPECT().UpdateStatus(gomock.Any(), 1, domain.PaymentStatusReleased, "").Return(nil)
+				m.credit.EXPECT().GetBalanceForUpdate(gomock.Any(), 7).Return(0.0, nil)
+				m.credit.EXPECT().AddEntry(gomock.Any(), gomock.Any()).Return(nil)
+				m.payments.EXPECT().UpdateStatus(gomock.Any(), 2, domain.PaymentStatusReleased, "").Return(nil)
+				m.orders.EXPECT().SetCancellation(gomock.Any(), 12, domain.CancellationFraud, gomock.Any()).Return(nil)
+				transitions(m.orders, held, domain.OrderStatusCancelled)
+			},
+			wantStatus: domain.OrderStatusCancelled,
+		},
+		{
+			name:      "order not held for review",
+			approve:   true,
+			requester: staff,
+			order:     &domain.Order{ID: 12, Status: domain.OrderStatusPaid},
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "fulfilment cannot review",
+			approve:   true,
+			requester: fulfilment,
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newRiskUseCase(t)
+			if tt.order != nil {
+				m.orders.EXPECT().GetByIDForUpdate(gomock.Any(), 12).Return(tt.order, nil)
+			}
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			if tt.wantErr == nil {
+				m.auditor.EXPECT().Record(gomock.Any(), audit.ActionUpdate, auditEntityOrder, 12, tt.order, gomock.Any())
+			}
+			got, err := uc.ReviewOrder(context.Background(), 12, tt.approve, "checked", tt.requester)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRiskUseCaseAddToBlocklist(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      domain.BlocklistKind
+		value     string
+		wantValue string
+		wantErr   error
+	}{
+		{name: "email is lower-cased", kind: domain.BlocklistEmail, value: " Fraud@Example.com ", wantValue: "fraud@example.com"},
+		{name: "email domain without the @", kind: domain.BlocklistEmailDomain, value: "@Example.com", wantValue: "example.com"},
+		{name: "ip in canonical form", kind: domain.BlocklistIP, value: "2001:DB8:0:0::1", wantValue: "2001:db8::1"},
+		{name: "invalid ip", kind: domain.BlocklistIP, value: "10.0.0", wantErr: domainErrors.ErrValidation},
+		{name: "unknown kind", kind: "phone", value: "+62", wantErr: domainErrors.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newRiskUseCase(t)
+			if tt.wantErr == nil {
+				m.blocklist.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, e *domain.BlocklistEntry) (*domain.BlocklistEntry, error) {
+						e.ID = 4
+						return e, nil
+					})
+				m.auditor.EXPECT().Record(gomock.Any(), audit.ActionCreate, auditEntityBlocklist, 4, nil, gomock.Any())
+			}
+			got, err := uc.AddToBlocklist(context.Background(), tt.kind, tt.value, "chargebacks", staff)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Value != tt.wantValue || got.ActorID != staff.UserID) {
+				t.Errorf("entry = %+v, want %q added by %d", got, tt.wantValue, staff.UserID)
+			}
+		})
+	}
+}
+
+// shipmentMocks are the dependencies of ShipmentUseCase
+type shipmentMocks struct {
+	orders    *mocks.MockOrderRepositoryInterface
+	shipments *mocks.MockShipmentRepositoryInterface
+}
+
+func newShipmentUseCase(t *testing.T) (IShipmentUseCase, shipmentMocks) {
+	ctrl := gomock.NewController(t)
+	m := shipmentMocks{orders: mocks.NewMockOrderRepositoryInterface(ctrl), shipments: mocks.NewMockShipmentRepositoryInterface(ctrl)}
+	m.orders.EXPECT().WithTx(gomock.Any()).Return(m.orders).AnyTimes()
+	m.shipments.EXPECT().WithTx(gomock.Any()).Return(m.shipments).AnyTimes()
+	adapter := carriermocks.NewMockAdapter(ctrl)
+	adapter.EXPECT().Name().Return("jne").AnyTimes()
+	adapter.EXPECT().TrackingURL(gomock.Any()).DoAndReturn(func(n string) string { return "https://jne.example/" + n }).AnyTimes()
+	tx := psqlmocks.NewMockTxManager(ctrl)
+	inTransaction(tx)
+	return NewShipmentUseCase(m.orders, m.shipments, carrier.NewRegistry(adapter), tx, nil, testLogger), m
+}
+
+// saved returns the shipment the use case saved
+func saved(_ context.Context, s *domain.Shipment) (*domain.Shipment, error) {
+	s.ID = 9
+	return s, nil
+}
+
+func TestShipmentUseCaseShip(t *testing.T) {
+	paid := &domain.Order{ID: 12, Status: domain.OrderStatusPaid, Version: 2}
+	tests := []struct {
+		name      string
+		carrier   string
+		requester domain.Requester
+		order     *domain.Order
+		setup     func(m shipmentMocks)
+		wantErr   error
+	}{
+		{
+			name:      "paid order is shipped",
+			carrier:   "JNE",
+			requester: fulfilment,
+			order:     paid,
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(nil, notFound)
+				m.shipments.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(saved)
+				transitions(m.orders, paid, domain.OrderStatusShipped)
+			},
+		},
+		{
+			name:      "shipped order gets another parcel",
+			carrier:   "jne",
+			requester: fulfilment,
+			order:     &domain.Order{ID: 12, Status: domain.OrderStatusShipped},
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(nil, notFound)
+				m.shipments.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(saved)
+			},
+		},
+		{
+			name:      "tracking number of another order",
+			carrier:   "jne",
+			requester: fulfilment,
+			order:     paid,
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(&domain.Shipment{OrderID: 13}, nil)
+			},
+			wantErr: domainErrors.ErrAlreadyExists,
+		},
+		{
+			name:      "unpaid order",
+			carrier:   "jne",
+			requester: fulfilment,
+			order:     &domain.Order{ID: 12, Status: domain.OrderStatusPending},
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "unknown carrier",
+			carrier:   "dhl",
+			requester: fulfilment,
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "customers do not ship",
+			carrier:   "jne",
+			requester: customer,
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newShipmentUseCase(t)
+			if tt.order != nil {
+				m.orders.EXPECT().GetByIDForUpdate(gomock.Any(), 12).Return(tt.order, nil)
+			}
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			got, err := uc.Ship(context.Background(), 12, tt.carrier, "TRK-1", tt.requester)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Carrier != "jne" || got.TrackingURL != "https://jne.example/TRK-1" || got.Status != domain.ShipmentStatusLabelCreated) {
+				t.Errorf("shipment = %+v", got)
+			}
+		})
+	}
+}
+
+func TestShipmentUseCaseApplyTrackingUpdates(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := at.Add(time.Hour)
+	shipped := &domain.Order{ID: 12, Status: domain.OrderStatusShipped, Version: 4}
+	parcel := &domain.Shipment{ID: 9, OrderID: 12, Carrier: "jne", TrackingNumber: "TRK-1", Status: domain.ShipmentStatusInTransit, LastEventAt: &at}
+	delivered := domain.TrackingUpdate{TrackingNumber: "TRK-1", Status: domain.ShipmentStatusDelivered, OccurredAt: later}
+
+	tests := []struct {
+		name   string
+		update domain.TrackingUpdate
+		setup  func(m shipmentMocks)
+	}{
+		{
+			name:   "delivery delivers the order",
+			update: delivered,
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(parcel, nil)
+				m.orders.EXPECT().GetByIDForUpdate(gomock.Any(), 12).Return(shipped, nil)
+				m.shipments.EXPECT().ApplyUpdate(gomock.Any(), 9, delivered).Return(parcel, nil)
+				transitions(m.orders, shipped, domain.OrderStatusDelivered)
+			},
+		},
+		{
+			name:   "progress leaves the order shipped",
+			update: domain.TrackingUpdate{TrackingNumber: "TRK-1", Status: domain.ShipmentStatusOutForDelivery, OccurredAt: later},
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(parcel, nil)
+				m.orders.EXPECT().GetByIDForUpdate(gomock.Any(), 12).Return(shipped, nil)
+				m.shipments.EXPECT().ApplyUpdate(gomock.Any(), 9, gomock.Any()).Return(parcel, nil)
+			},
+		},
+		{
+			name:   "stale update is ignored",
+			update: domain.TrackingUpdate{TrackingNumber: "TRK-1", Status: domain.ShipmentStatusDelivered, OccurredAt: at.Add(-time.Hour)},
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(parcel, nil)
+				m.orders.EXPECT().GetByIDForUpdate(gomock.Any(), 12).Return(shipped, nil)
+			},
+		},
+		{
+			name:   "unknown parcel is dropped",
+			update: delivered,
+			setup: func(m shipmentMocks) {
+				m.shipments.EXPECT().GetByTracking(gomock.Any(), "jne", "TRK-1").Return(nil, notFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newShipmentUseCase(t)
+			tt.setup(m)
+			if err := uc.ApplyTrackingUpdates(context.Background(), "jne", []domain.TrackingUpdate{tt.update}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCheckRequestedDiscounts(t *testing.T) {
+	line := func(source domain.DiscountSource) []domain.OrderDiscount {
+		return []domain.OrderDiscount{{Source: source, Code: "TEN", Amount: 10}}
+	}
+	tests := []struct {
+		name      string
+		order     domain.Order
+		requester domain.Requester
+		wantErr   domainErrors.ErrorType
+	}{
+		{name: "customer without discounts", order: domain.Order{Items: []domain.OrderItem{{ProductID: 1}}}, requester: customer},
+		{name: "customer coupon on an item", order: domain.Order{Items: []domain.OrderItem{{ProductID: 1, Discounts: line(domain.DiscountSourceCoupon)}}}, requester: customer, wantErr: domainErrors.NotAuthorized},
+		{name: "customer manual on the order", order: domain.Order{Discounts: line(domain.DiscountSourceManual)}, requester: customer, wantErr: domainErrors.NotAuthorized},
+		{name: "customer promotion", order: domain.Order{Discounts: line(domain.DiscountSourcePromotion)}, requester: customer, wantErr: domainErrors.ValidationError},
+		{name: "staff coupon and manual", order: domain.Order{Discounts: line(domain.DiscountSourceManual), Items: []domain.OrderItem{{ProductID: 1, Discounts: line(domain.DiscountSourceCoupon)}}}, requester: staff},
+		{name: "staff promotion", order: domain.Order{Items: []domain.OrderItem{{ProductID: 1, Discounts: line(domain.DiscountSourcePromotion)}}}, requester: staff, wantErr: domainErrors.ValidationError},
+		{name: "system coupon", order: domain.Order{Discounts: line(domain.DiscountSourceCoupon)}, requester: domain.SystemRequester},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRequestedDiscounts(&tt.order, tt.requester)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkRequestedDiscounts() = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, domainErrors.NewAppErrorWithType(tt.wantErr)) {
+				t.Fatalf("checkRequestedDiscounts() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
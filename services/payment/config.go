@@ -0,0 +1,101 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/payment/provider"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	IDs       config.IDs
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Providers ProvidersConfig
+}
+
+// ProvidersConfig holds the provider credentials; providers without them are not registered
+type ProvidersConfig struct {
+	Default string
+	// Sandbox registers the sandbox provider, which approves every charge; only in development
+	Sandbox  bool
+	Stripe   provider.StripeConfig
+	Midtrans provider.MidtransConfig
+	PayPal   provider.PayPalConfig
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	timeout := src.Duration("PAYMENT_PROVIDER_TIMEOUT_SECONDS", 15, time.Second)
+	cfg := Config{
+		Server:    config.LoadServer(src, "8085", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "payment", false),
+		IDs:       config.LoadIDs(src),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Providers: ProvidersConfig{
+			Default: src.OneOf("PAYMENT_DEFAULT_PROVIDER", "sandbox", "sandbox", "stripe", "midtrans", "paypal"),
+			Stripe: provider.StripeConfig{
+				BaseURL:       src.String("STRIPE_BASE_URL", ""),
+				SecretKey:     src.String("STRIPE_SECRET_KEY", ""),
+				WebhookSecret: src.String("STRIPE_WEBHOOK_SECRET", ""),
+				Timeout:       timeout,
+			},
+			Midtrans: provider.MidtransConfig{
+				BaseURL:   src.String("MIDTRANS_BASE_URL", ""),
+				ServerKey: src.String("MIDTRANS_SERVER_KEY", ""),
+				Timeout:   timeout,
+			},
+			PayPal: provider.PayPalConfig{
+				BaseURL:      src.String("PAYPAL_BASE_URL", ""),
+				ClientID:     src.String("PAYPAL_CLIENT_ID", ""),
+				ClientSecret: src.String("PAYPAL_CLIENT_SECRET", ""),
+				WebhookID:    src.String("PAYPAL_WEBHOOK_ID", ""),
+				Timeout:      timeout,
+			},
+		},
+	}
+	cfg.Providers.Sandbox = cfg.Server.IsDevelopment()
+	// without the secret every webhook signature would verify against an empty key
+	if cfg.Providers.Stripe.SecretKey != "" && cfg.Providers.Stripe.WebhookSecret == "" {
+		src.Fail("STRIPE_WEBHOOK_SECRET", "is required with STRIPE_SECRET_KEY")
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "payment", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
+
+// registry registers every provider with credentials, and the sandbox in development
+func (c ProvidersConfig) registry() *provider.Registry {
+	var providers []provider.Provider
+	if c.Sandbox {
+		providers = append(providers, provider.NewSandbox())
+	}
+	if c.Stripe.SecretKey != "" {
+		providers = append(providers, provider.NewStripe(c.Stripe))
+	}
+	if c.Midtrans.ServerKey != "" {
+		providers = append(providers, provider.NewMidtrans(c.Midtrans))
+	}
+	if c.PayPal.ClientID != "" && c.PayPal.ClientSecret != "" {
+		providers = append(providers, provider.NewPayPal(c.PayPal))
+	}
+	return provider.NewRegistry(c.Default, providers...)
+}
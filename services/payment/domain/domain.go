@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending is a charge the provider has not settled yet, e.g. one waiting for
+	// 3-D Secure or a bank transfer; a webhook moves it on
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusSucceeded         PaymentStatus = "succeeded"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+)
+
+// Refundable reports whether money can still be returned from a payment in this status
+func (s PaymentStatus) Refundable() bool {
+	return s == PaymentStatusSucceeded || s == PaymentStatusPartiallyRefunded
+}
+
+type Payment struct {
+	ID   int
+	UUID string
+	// Reference is chosen by the caller and makes charging safe to retry
+	Reference        string
+	OrderID          int
+	UserID           int
+	Provider         string
+	ProviderChargeID string
+	PaymentMethodID  *int
+	Amount           float64
+	// RefundedAmount includes refunds still waiting for the provider
+	RefundedAmount float64
+	Currency       string
+	Status         PaymentStatus
+	FailureReason  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+type Refund struct {
+	ID               int
+	UUID             string
+	PaymentID        int
+	Reference        string
+	Amount           float64
+	Currency         string
+	Status           RefundStatus
+	ProviderRefundID string
+	Reason           string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PaymentMethod is a card or account the customer saved with a provider. Token is the
+// provider's reference to it; card numbers never reach this service.
+type PaymentMethod struct {
+	ID        int
+	UUID      string
+	UserID    int
+	Provider  string
+	Type      string
+	Token     string
+	Brand     string
+	Last4     string
+	ExpMonth  int
+	ExpYear   int
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ChargeInput is what the order service sends to charge an order. Without a payment method
+// the customer's default one is used, and without that the default provider.
+type ChargeInput struct {
+	Reference       string
+	OrderID         int
+	UserID          int
+	Amount          float64
+	Currency        string
+	Provider        string
+	PaymentMethodID *int
+}
+
+// Requester is the caller of a public endpoint
+type Requester struct {
+	UserID     int
+	CanReadAny bool
+}
+
+// SystemRequester is used for internal calls, which the internal token already authorized
+var SystemRequester = Requester{CanReadAny: true}
+
+// CanAccess reports whether the requester may see the user's payments and payment methods
+func (r Requester) CanAccess(userID int) bool {
+	return r.CanReadAny || r.UserID == userID
+}
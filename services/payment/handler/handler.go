@@ -0,0 +1,442 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/payment/domain"
+	"ecommerce-microservice-go/services/payment/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponsePayment struct {
+	ID               int       `json:"id"`
+	UUID             string    `json:"uuid"`
+	Reference        string    `json:"reference"`
+	OrderID          int       `json:"orderId"`
+	UserID           int       `json:"userId"`
+	Provider         string    `json:"provider"`
+	ProviderChargeID string    `json:"providerChargeId,omitempty"`
+	Amount           float64   `json:"amount"`
+	RefundedAmount   float64   `json:"refundedAmount"`
+	Currency         string    `json:"currency"`
+	Status           string    `json:"status"`
+	FailureReason    string    `json:"failureReason,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type ResponseRefund struct {
+	ID               int       `json:"id"`
+	UUID             string    `json:"uuid"`
+	PaymentID        int       `json:"paymentId"`
+	Reference        string    `json:"reference"`
+	Amount           float64   `json:"amount"`
+	Currency         string    `json:"currency"`
+	Status           string    `json:"status"`
+	ProviderRefundID string    `json:"providerRefundId,omitempty"`
+	Reason           string    `json:"reason,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type ResponsePaymentMethod struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	Provider  string    `json:"provider"`
+	Type      string    `json:"type"`
+	Brand     string    `json:"brand,omitempty"`
+	Last4     string    `json:"last4,omitempty"`
+	ExpMonth  int       `json:"expMonth,omitempty"`
+	ExpYear   int       `json:"expYear,omitempty"`
+	IsDefault bool      `json:"isDefault"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewPaymentMethodRequest saves a card or account the client tokenized with the provider's
+// own SDK; the token is all this service ever sees
+type NewPaymentMethodRequest struct {
+	Provider  string `json:"provider" binding:"required,oneof=stripe midtrans paypal"`
+	Type      string `json:"type" binding:"required,oneof=card paypal"`
+	Token     string `json:"token" binding:"required,max=255"`
+	Brand     string `json:"brand" binding:"max=32"`
+	Last4     string `json:"last4" binding:"omitempty,len=4,numeric"`
+	ExpMonth  int    `json:"expMonth" binding:"omitempty,min=1,max=12"`
+	ExpYear   int    `json:"expYear" binding:"omitempty,min=2000"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+type RefundRequest struct {
+	// Amount is in the payment currency; 0 refunds everything not refunded yet
+	Amount float64 `json:"amount" binding:"min=0"`
+	Reason string  `json:"reason" binding:"max=500"`
+}
+
+type ChargeRequest struct {
+	Reference       string  `json:"reference" binding:"required,max=64"`
+	OrderID         int     `json:"orderId" binding:"required"`
+	UserID          int     `json:"userId" binding:"required"`
+	Amount          float64 `json:"amount" binding:"required,gt=0"`
+	Currency        string  `json:"currency" binding:"required,len=3"`
+	Provider        string  `json:"provider"`
+	PaymentMethodID *int    `json:"paymentMethodId"`
+}
+
+type InternalRefundRequest struct {
+	Reference string  `json:"reference" binding:"required,max=128"`
+	Amount    float64 `json:"amount" binding:"min=0"`
+	Reason    string  `json:"reason" binding:"max=500"`
+}
+
+type Handler struct {
+	paymentUC usecase.IPaymentUseCase
+	methodUC  usecase.IPaymentMethodUseCase
+	ids       config.IDs
+	Logger    *logger.Logger
+}
+
+func NewHandler(p usecase.IPaymentUseCase, m usecase.IPaymentMethodUseCase, ids config.IDs, l *logger.Logger) *Handler {
+	return &Handler{paymentUC: p, methodUC: m, ids: ids, Logger: l}
+}
+
+// --- Payment handlers ---
+
+// GetPayment godoc
+// @Summary      Get payment
+// @Tags         Payment
+// @Security     BearerAuth
+// @Description  Customers see their own payments; staff see every payment.
+// @Param        id     path  string true  "Payment UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponsePayment
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /payment/{id} [get]
+func (h *Handler) GetPayment(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.paymentUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := h.paymentUC.GetByID(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, paymentToResponse(p))
+}
+
+// GetOrderPayments godoc
+// @Summary      Get the payments of an order
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        orderId path  int    true  "Order ID"
+// @Param        fields  query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponsePayment
+// @Router       /payment/order/{orderId} [get]
+func (h *Handler) GetOrderPayments(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid orderId"), domainErrors.ValidationError))
+		return
+	}
+	payments, err := h.paymentUC.GetByOrder(ctx.Request.Context(), orderID, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponsePayment, len(*payments))
+	for i := range *payments {
+		res[i] = paymentToResponse(&(*payments)[i])
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// GetRefunds godoc
+// @Summary      Get the refunds of a payment
+// @Tags         Payment
+// @Security     BearerAuth
+// @Param        id     path  string true  "Payment UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponseRefund
+// @Router       /payment/{id}/refunds [get]
+func (h *Handler) GetRefunds(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.paymentUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	refunds, err := h.paymentUC.GetRefunds(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, refundsToResponse(refunds))
+}
+
+// RefundPayment godoc
+// @Summary      Refund a payment
+// @Tags         Payment
+// @Security     BearerAuth
+// @Description  Admin only. Retrying with the same Idempotency-Key returns the first refund; without one every request is a new refund.
+// @Param        id path string true "Payment UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        Idempotency-Key header string false "Client-generated key, max 128 characters"
+// @Param        request body RefundRequest true "Refund"
+// @Success      200 {object} ResponseRefund
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /payment/{id}/refunds [post]
+func (h *Handler) RefundPayment(ctx *gin.Context) {
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.paymentUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req RefundRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	reference := ctx.GetHeader("Idempotency-Key")
+	if len(reference) > 128 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("Idempotency-Key must be at most 128 characters"), domainErrors.ValidationError))
+		return
+	}
+	refund, err := h.paymentUC.Refund(ctx.Request.Context(), id, req.Amount, reference, req.Reason)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, refundToResponse(refund))
+}
+
+// Webhook godoc
+// @Summary      Receive a payment provider notification
+// @Tags         Payment
+// @Description  Called by Stripe, Midtrans and PayPal. The provider's signature authenticates the request.
+// @Param        provider path string true "stripe, midtrans or paypal"
+// @Success      200 {object} controllers.MessageResponse
+// @Failure      401 {object} domainErrors.ErrorResponse
+// @Router       /payment/webhooks/{provider} [post]
+func (h *Handler) Webhook(ctx *gin.Context) {
+	if err := h.paymentUC.HandleWebhook(ctx.Request.Context(), ctx.Param("provider"), ctx.Request); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "event received"})
+}
+
+// --- Payment method handlers ---
+
+// GetPaymentMethods godoc
+// @Summary      List my payment methods
+// @Tags         Payment Method
+// @Security     BearerAuth
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponsePaymentMethod
+// @Router       /payment/methods [get]
+func (h *Handler) GetPaymentMethods(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	methods, err := h.methodUC.List(ctx.Request.Context(), requester.UserID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponsePaymentMethod, len(*methods))
+	for i := range *methods {
+		res[i] = methodToResponse(&(*methods)[i])
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// NewPaymentMethod godoc
+// @Summary      Save a payment method
+// @Tags         Payment Method
+// @Security     BearerAuth
+// @Description  The first saved method becomes the default one.
+// @Param        request body NewPaymentMethodRequest true "Payment method"
+// @Success      200 {object} ResponsePaymentMethod
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /payment/methods [post]
+func (h *Handler) NewPaymentMethod(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req NewPaymentMethodRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	m, err := h.methodUC.Add(ctx.Request.Context(), &domain.PaymentMethod{
+		UserID: requester.UserID, Provider: req.Provider, Type: req.Type, Token: req.Token, Brand: req.Brand, Last4: req.Last4,
+		ExpMonth: req.ExpMonth, ExpYear: req.ExpYear, IsDefault: req.IsDefault,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, methodToResponse(m))
+}
+
+// SetDefaultPaymentMethod godoc
+// @Summary      Make a payment method the default one
+// @Tags         Payment Method
+// @Security     BearerAuth
+// @Param        id path string true "Payment method UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} ResponsePaymentMethod
+// @Router       /payment/methods/{id}/default [put]
+func (h *Handler) SetDefaultPaymentMethod(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.methodUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	m, err := h.methodUC.SetDefault(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, methodToResponse(m))
+}
+
+// DeletePaymentMethod godoc
+// @Summary      Delete a payment method
+// @Tags         Payment Method
+// @Security     BearerAuth
+// @Param        id path string true "Payment method UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.MessageResponse
+// @Router       /payment/methods/{id} [delete]
+func (h *Handler) DeletePaymentMethod(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.methodUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.methodUC.Delete(ctx.Request.Context(), id, requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+// --- Internal handlers ---
+
+// Charge charges an order for the order service. It is only reachable with the internal
+// token; repeating a reference returns the original payment.
+func (h *Handler) Charge(ctx *gin.Context) {
+	var req ChargeRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	p, err := h.paymentUC.Charge(ctx.Request.Context(), domain.ChargeInput{
+		Reference: req.Reference, OrderID: req.OrderID, UserID: req.UserID, Amount: req.Amount, Currency: req.Currency,
+		Provider: req.Provider, PaymentMethodID: req.PaymentMethodID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, paymentToResponse(p))
+}
+
+// InternalRefund refunds a payment for the order service, e.g. when a checkout is rolled
+// back. It is only reachable with the internal token.
+func (h *Handler) InternalRefund(ctx *gin.Context) {
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.paymentUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req InternalRefundRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	refund, err := h.paymentUC.Refund(ctx.Request.Context(), id, req.Amount, req.Reference, req.Reason)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, refundToResponse(refund))
+}
+
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:     int(userIDVal.(float64)),
+		CanReadAny: middleware.HasPermission(ctx, security.PermPaymentReadAny),
+	}, nil
+}
+
+// Mappers
+func paymentToResponse(p *domain.Payment) ResponsePayment {
+	return ResponsePayment{
+		ID: p.ID, UUID: p.UUID, Reference: p.Reference, OrderID: p.OrderID, UserID: p.UserID, Provider: p.Provider,
+		ProviderChargeID: p.ProviderChargeID, Amount: p.Amount, RefundedAmount: p.RefundedAmount, Currency: p.Currency,
+		Status: string(p.Status), FailureReason: p.FailureReason, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func refundToResponse(r *domain.Refund) ResponseRefund {
+	return ResponseRefund{
+		ID: r.ID, UUID: r.UUID, PaymentID: r.PaymentID, Reference: r.Reference, Amount: r.Amount, Currency: r.Currency,
+		Status: string(r.Status), ProviderRefundID: r.ProviderRefundID, Reason: r.Reason, CreatedAt: r.CreatedAt,
+	}
+}
+
+func refundsToResponse(refunds *[]domain.Refund) []ResponseRefund {
+	res := make([]ResponseRefund, len(*refunds))
+	for i := range *refunds {
+		res[i] = refundToResponse(&(*refunds)[i])
+	}
+	return res
+}
+
+func methodToResponse(m *domain.PaymentMethod) ResponsePaymentMethod {
+	return ResponsePaymentMethod{
+		ID: m.ID, UUID: m.UUID, Provider: m.Provider, Type: m.Type, Brand: m.Brand, Last4: m.Last4,
+		ExpMonth: m.ExpMonth, ExpYear: m.ExpYear, IsDefault: m.IsDefault, CreatedAt: m.CreatedAt,
+	}
+}
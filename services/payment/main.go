@@ -0,0 +1,161 @@
+// @title           Payment Service API
+// @version         1.0.0
+// @description     Payment microservice: charges, refunds and saved payment methods through Stripe, Midtrans and PayPal
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/payment/handler"
+	"ecommerce-microservice-go/services/payment/migrations"
+	"ecommerce-microservice-go/services/payment/provider"
+	"ecommerce-microservice-go/services/payment/repository"
+	"ecommerce-microservice-go/services/payment/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/payment/docs"
+)
+
+const service app.Service = "payment"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Payment Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Payment Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.IDs),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			psql.NewTxManager,
+			func() (*provider.Registry, error) {
+				providers := cfg.Providers.registry()
+				if _, err := providers.Get(""); err != nil {
+					return nil, fmt.Errorf("default payment provider %q is not configured: %w", cfg.Providers.Default, err)
+				}
+				return providers, nil
+			},
+			repository.NewPaymentRepository,
+			repository.NewPaymentMethodRepository,
+			usecase.NewPaymentUseCase,
+			usecase.NewPaymentMethodUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, migrator *psql.Migrator,
+	h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("payment", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("payment"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	probes := health.NewRegistry("payment").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("migrations", psql.MigrationStateCheck(migrator))
+	probes.RegisterRoutes(v1)
+
+	v1.GET("/payment/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	apiRateLimit := middleware.RateLimit(cacheStore, "payment", cfg.RateLimit, middleware.KeyByIP, log)
+
+	// Provider webhooks, authenticated by the provider's signature
+	v1.POST("/payment/webhooks/:provider", h.Webhook)
+
+	pay := v1.Group("/payment")
+	pay.Use(apiRateLimit, middleware.AuthJWTMiddleware(cfg.JWT))
+	{
+		pay.GET("/methods", h.GetPaymentMethods)
+		pay.POST("/methods", h.NewPaymentMethod)
+		pay.PUT("/methods/:id/default", h.SetDefaultPaymentMethod)
+		pay.DELETE("/methods/:id", h.DeletePaymentMethod)
+		pay.GET("/order/:orderId", h.GetOrderPayments)
+		pay.GET("/:id", h.GetPayment)
+		pay.GET("/:id/refunds", h.GetRefunds)
+		pay.POST("/:id/refunds", middleware.RequirePermissions(security.PermPaymentRefund), h.RefundPayment)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+		internal.POST("/payments/charges", h.Charge)
+		internal.POST("/payments/:id/refunds", h.InternalRefund)
+	}
+
+	return router, nil
+}
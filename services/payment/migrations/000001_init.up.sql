@@ -0,0 +1,78 @@
+CREATE TABLE payment_methods (
+    id bigserial,
+    uuid uuid NOT NULL,
+    user_id bigint NOT NULL,
+    provider text NOT NULL,
+    type text NOT NULL,
+    token text NOT NULL,
+    brand text,
+    last4 varchar(4),
+    exp_month bigint,
+    exp_year bigint,
+    is_default boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_payment_methods_uuid ON payment_methods (uuid);
+CREATE INDEX idx_payment_methods_user_id ON payment_methods (user_id);
+CREATE INDEX idx_payment_methods_deleted_at ON payment_methods (deleted_at);
+-- a user has at most one live default method
+CREATE UNIQUE INDEX uni_payment_methods_default ON payment_methods (user_id) WHERE is_default AND deleted_at IS NULL;
+
+CREATE TABLE payments (
+    id bigserial,
+    uuid uuid NOT NULL,
+    reference text NOT NULL,
+    order_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    provider text NOT NULL,
+    provider_charge_id text,
+    payment_method_id bigint,
+    amount decimal NOT NULL,
+    refunded_amount decimal NOT NULL DEFAULT 0,
+    currency varchar(3) NOT NULL,
+    status text NOT NULL,
+    failure_reason text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_payments_payment_method FOREIGN KEY (payment_method_id) REFERENCES payment_methods(id),
+    CONSTRAINT chk_payments_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount)
+);
+CREATE UNIQUE INDEX idx_payments_uuid ON payments (uuid);
+CREATE UNIQUE INDEX uni_payments_reference ON payments (reference);
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+CREATE INDEX idx_payments_user_id ON payments (user_id);
+CREATE INDEX idx_payments_deleted_at ON payments (deleted_at);
+CREATE UNIQUE INDEX uni_payments_provider_charge ON payments (provider, provider_charge_id) WHERE provider_charge_id IS NOT NULL AND provider_charge_id <> '';
+
+CREATE TABLE refunds (
+    id bigserial,
+    uuid uuid NOT NULL,
+    payment_id bigint NOT NULL,
+    reference text NOT NULL,
+    amount decimal NOT NULL,
+    currency varchar(3) NOT NULL,
+    status text NOT NULL,
+    provider_refund_id text,
+    reason text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_refunds_payment FOREIGN KEY (payment_id) REFERENCES payments(id),
+    CONSTRAINT chk_refunds_amount CHECK (amount > 0)
+);
+CREATE UNIQUE INDEX idx_refunds_uuid ON refunds (uuid);
+CREATE UNIQUE INDEX uni_refunds_reference ON refunds (reference);
+CREATE INDEX idx_refunds_payment_id ON refunds (payment_id);
+CREATE INDEX idx_refunds_deleted_at ON refunds (deleted_at);
@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+type PayPalConfig struct {
+	BaseURL      string
+	ClientID     string
+	ClientSecret string
+	// WebhookID is the ID PayPal gave the webhook endpoint; PayPal verifies signatures against it
+	WebhookID string
+	Timeout   time.Duration
+}
+
+// PayPal charges vaulted PayPal accounts by creating and capturing an order in one call.
+// The charge ID is the capture ID, which refunds need.
+type PayPal struct {
+	cfg  PayPalConfig
+	http *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewPayPal(cfg PayPalConfig) Provider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api-m.sandbox.paypal.com"
+	}
+	return &PayPal{cfg: cfg, http: &http.Client{Timeout: cfg.Timeout, Transport: otel.Transport(nil)}}
+}
+
+func (p *PayPal) Name() string { return "paypal" }
+
+type paypalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type paypalCapture struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	CustomID string `json:"custom_id"`
+}
+
+func (p *PayPal) Charge(ctx context.Context, req ChargeRequest) (*Result, error) {
+	currency := strings.ToUpper(req.Currency)
+	body := map[string]any{
+		"intent": "CAPTURE",
+		"purchase_units": []map[string]any{{
+			"reference_id": req.Reference,
+			"custom_id":    req.Reference,
+			"description":  req.Description,
+			"amount":       paypalAmount{CurrencyCode: currency, Value: decimalString(req.Amount, currency)},
+		}},
+		"payment_source": map[string]any{"paypal": map[string]any{"vault_id": req.Token}},
+	}
+	var order struct {
+		Status        string `json:"status"`
+		PurchaseUnits []struct {
+			Payments struct {
+				Captures []paypalCapture `json:"captures"`
+			} `json:"payments"`
+		} `json:"purchase_units"`
+	}
+	if err := p.post(ctx, "/v2/checkout/orders", req.Reference, body, &order); err != nil {
+		return nil, err
+	}
+	if len(order.PurchaseUnits) == 0 || len(order.PurchaseUnits[0].Payments.Captures) == 0 {
+		return nil, declined("paypal order " + strings.ToLower(order.Status))
+	}
+	capture := order.PurchaseUnits[0].Payments.Captures[0]
+	status := paypalStatus(capture.Status)
+	if status == StatusFailed {
+		return nil, declined("paypal capture " + strings.ToLower(capture.Status))
+	}
+	return &Result{ID: capture.ID, Status: status}, nil
+}
+
+func (p *PayPal) Refund(ctx context.Context, req RefundRequest) (*Result, error) {
+	currency := strings.ToUpper(req.Currency)
+	body := map[string]any{
+		"amount":        paypalAmount{CurrencyCode: currency, Value: decimalString(req.Amount, currency)},
+		"invoice_id":    req.Reference,
+		"note_to_payer": req.Reason,
+		"custom_id":     req.Reference,
+	}
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.post(ctx, "/v2/payments/captures/"+url.PathEscape(req.ChargeID)+"/refund", req.Reference, body, &refund); err != nil {
+		return nil, err
+	}
+	return &Result{ID: refund.ID, Status: paypalStatus(refund.Status)}, nil
+}
+
+func (p *PayPal) ParseWebhook(ctx context.Context, r *http.Request) (*WebhookEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.verifyWebhook(ctx, r.Header, body); err != nil {
+		return nil, err
+	}
+	var event struct {
+		EventType string        `json:"event_type"`
+		Resource  paypalCapture `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid paypal event: %w", err)
+	}
+	e := &WebhookEvent{ChargeID: event.Resource.ID, Reference: event.Resource.CustomID}
+	switch event.EventType {
+	case "PAYMENT.CAPTURE.COMPLETED":
+		e.Status = StatusSucceeded
+	case "PAYMENT.CAPTURE.DENIED", "PAYMENT.CAPTURE.DECLINED":
+		e.Status, e.FailureReason = StatusFailed, "paypal capture denied"
+	default:
+		return nil, nil
+	}
+	return e, nil
+}
+
+// verifyWebhook asks PayPal to check the transmission signature, which is signed with a
+// certificate PayPal rotates
+func (p *PayPal) verifyWebhook(ctx context.Context, h http.Header, body []byte) error {
+	check := map[string]any{
+		"auth_algo":         h.Get("Paypal-Auth-Algo"),
+		"cert_url":          h.Get("Paypal-Cert-Url"),
+		"transmission_id":   h.Get("Paypal-Transmission-Id"),
+		"transmission_sig":  h.Get("Paypal-Transmission-Sig"),
+		"transmission_time": h.Get("Paypal-Transmission-Time"),
+		"webhook_id":        p.cfg.WebhookID,
+		"webhook_event":     json.RawMessage(body),
+	}
+	if !json.Valid(body) || check["transmission_sig"] == "" {
+		return ErrInvalidSignature
+	}
+	var res struct {
+		VerificationStatus string `json:"verification_status"`
+	}
+	if err := p.post(ctx, "/v1/notifications/verify-webhook-signature", "", check, &res); err != nil {
+		return err
+	}
+	if res.VerificationStatus != "SUCCESS" {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func paypalStatus(status string) Status {
+	switch status {
+	case "COMPLETED":
+		return StatusSucceeded
+	case "DECLINED", "FAILED", "DENIED", "CANCELLED":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+func (p *PayPal) post(ctx context.Context, path, requestID string, body, out any) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	if requestID != "" {
+		// PayPal-Request-Id makes PayPal return the first result for a repeated request
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	res, err := p.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("paypal request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusUnprocessableEntity {
+		var payload struct {
+			Details []struct {
+				Issue string `json:"issue"`
+			} `json:"details"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&payload)
+		if len(payload.Details) > 0 {
+			return declined(payload.Details[0].Issue)
+		}
+		return ErrDeclined
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("paypal returned %d for %s", res.StatusCode, path)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// accessToken returns the cached OAuth token, fetching a new one shortly before it expires
+func (p *PayPal) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.cfg.ClientID, p.cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := p.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("paypal token request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("paypal returned %d for the access token", res.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("paypal returned an empty access token")
+	}
+	p.token = token.AccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
@@ -0,0 +1,131 @@
+package provider
+
+//go:generate mockgen -source=provider.go -destination=mocks/provider.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDeclined means the provider refused the charge; retrying will not help
+	ErrDeclined            = errors.New("payment declined")
+	ErrUnknownProvider     = errors.New("unknown payment provider")
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
+	ErrUnsupportedCurrency = errors.New("currency not supported by the payment provider")
+)
+
+// Status is the outcome a provider reports for a charge or refund
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusPending   Status = "pending"
+	StatusFailed    Status = "failed"
+)
+
+type ChargeRequest struct {
+	// Reference is sent as the idempotency key, so charging a reference again returns the
+	// original charge
+	Reference   string
+	Amount      float64
+	Currency    string
+	Token       string
+	Description string
+}
+
+type RefundRequest struct {
+	Reference string
+	ChargeID  string
+	Amount    float64
+	Currency  string
+	Reason    string
+}
+
+type Result struct {
+	ID     string
+	Status Status
+}
+
+// WebhookEvent is a change of a charge's status pushed by the provider. ChargeID or
+// Reference identify the payment, whichever the provider sends.
+type WebhookEvent struct {
+	ChargeID      string
+	Reference     string
+	Status        Status
+	FailureReason string
+}
+
+// Provider connects one payment provider. Charge and Refund must be safe to retry with the
+// same reference.
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, req ChargeRequest) (*Result, error)
+	Refund(ctx context.Context, req RefundRequest) (*Result, error)
+	// ParseWebhook authenticates the provider's notification and returns the status change it
+	// carries, or nil for notifications the service does not act on
+	ParseWebhook(ctx context.Context, r *http.Request) (*WebhookEvent, error)
+}
+
+type Registry struct {
+	providers map[string]Provider
+	fallback  string
+}
+
+// NewRegistry holds the configured providers; fallback names the one used when a charge
+// does not pick one
+func NewRegistry(fallback string, providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers)), fallback: fallback}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the named provider, or the fallback for an empty name
+func (r *Registry) Get(name string) (Provider, error) {
+	if name == "" {
+		name = r.fallback
+	}
+	p, ok := r.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// declined wraps ErrDeclined with the provider's reason
+func declined(reason string) error {
+	if reason == "" {
+		return ErrDeclined
+	}
+	return fmt.Errorf("%w: %s", ErrDeclined, reason)
+}
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true, "KRW": true, "MGA": true,
+	"PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+func currencyDecimals(currency string) int {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// minorUnits converts an amount into the currency's smallest unit, e.g. cents
+func minorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(currencyDecimals(currency))))
+}
+
+// decimalString formats an amount with the currency's number of decimals, e.g. "12.50"
+func decimalString(amount float64, currency string) string {
+	return strconv.FormatFloat(amount, 'f', currencyDecimals(currency), 64)
+}
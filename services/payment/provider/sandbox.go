@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// SandboxName names the sandbox provider
+const SandboxName = "sandbox"
+
+// SandboxDeclineToken makes the sandbox decline a charge, for trying out the failure path
+const SandboxDeclineToken = "tok_decline"
+
+// Sandbox approves every charge and refund without talking to anyone, for development and
+// tests. It sends no webhooks and is only registered in development.
+type Sandbox struct{}
+
+func NewSandbox() Provider {
+	return &Sandbox{}
+}
+
+func (p *Sandbox) Name() string { return SandboxName }
+
+func (p *Sandbox) Charge(_ context.Context, req ChargeRequest) (*Result, error) {
+	if req.Token == SandboxDeclineToken {
+		return nil, declined("sandbox decline token")
+	}
+	return &Result{ID: "sandbox_" + req.Reference, Status: StatusSucceeded}, nil
+}
+
+func (p *Sandbox) Refund(_ context.Context, req RefundRequest) (*Result, error) {
+	return &Result{ID: "sandbox_" + req.Reference, Status: StatusSucceeded}, nil
+}
+
+func (p *Sandbox) ParseWebhook(context.Context, *http.Request) (*WebhookEvent, error) {
+	return nil, errors.New("the sandbox provider sends no webhooks")
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+const (
+	stripeSignatureHeader = "Stripe-Signature"
+	// stripeSignatureTolerance rejects replays of old webhook deliveries
+	stripeSignatureTolerance = 5 * time.Minute
+	maxWebhookBody           = 1 << 20
+)
+
+type StripeConfig struct {
+	BaseURL       string
+	SecretKey     string
+	WebhookSecret string
+	Timeout       time.Duration
+}
+
+// Stripe charges saved payment methods with PaymentIntents confirmed on the server and
+// verifies webhooks with the Stripe-Signature scheme
+type Stripe struct {
+	cfg  StripeConfig
+	http *http.Client
+}
+
+func NewStripe(cfg StripeConfig) Provider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.stripe.com"
+	}
+	return &Stripe{cfg: cfg, http: &http.Client{Timeout: cfg.Timeout, Transport: otel.Transport(nil)}}
+}
+
+func (p *Stripe) Name() string { return "stripe" }
+
+type stripeIntent struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (p *Stripe) Charge(ctx context.Context, req ChargeRequest) (*Result, error) {
+	form := url.Values{
+		"amount":               {strconv.FormatInt(minorUnits(req.Amount, req.Currency), 10)},
+		"currency":             {strings.ToLower(req.Currency)},
+		"confirm":              {"true"},
+		"off_session":          {"true"},
+		"description":          {req.Description},
+		"metadata[reference]":  {req.Reference},
+		"payment_method_types": {"card"},
+	}
+	if req.Token != "" {
+		form.Set("payment_method", req.Token)
+	}
+	var intent stripeIntent
+	if err := p.post(ctx, "/v1/payment_intents", req.Reference, form, &intent); err != nil {
+		return nil, err
+	}
+	status := stripeIntentStatus(intent.Status)
+	if status == StatusFailed {
+		reason := intent.Status
+		if intent.LastPaymentError != nil {
+			reason = intent.LastPaymentError.Message
+		}
+		return nil, declined(reason)
+	}
+	return &Result{ID: intent.ID, Status: status}, nil
+}
+
+func (p *Stripe) Refund(ctx context.Context, req RefundRequest) (*Result, error) {
+	form := url.Values{
+		"payment_intent":        {req.ChargeID},
+		"amount":                {strconv.FormatInt(minorUnits(req.Amount, req.Currency), 10)},
+		"metadata[reference]":   {req.Reference},
+		"metadata[description]": {req.Reason},
+	}
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.post(ctx, "/v1/refunds", req.Reference, form, &refund); err != nil {
+		return nil, err
+	}
+	status := StatusPending
+	switch refund.Status {
+	case "succeeded":
+		status = StatusSucceeded
+	case "failed", "canceled":
+		status = StatusFailed
+	}
+	return &Result{ID: refund.ID, Status: status}, nil
+}
+
+func (p *Stripe) ParseWebhook(_ context.Context, r *http.Request) (*WebhookEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.verifySignature(r.Header.Get(stripeSignatureHeader), body, time.Now()); err != nil {
+		return nil, err
+	}
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object stripeIntent `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid stripe event: %w", err)
+	}
+	intent := event.Data.Object
+	e := &WebhookEvent{ChargeID: intent.ID, Reference: intent.Metadata["reference"]}
+	switch event.Type {
+	case "payment_intent.succeeded":
+		e.Status = StatusSucceeded
+	case "payment_intent.payment_failed", "payment_intent.canceled":
+		e.Status = StatusFailed
+		if intent.LastPaymentError != nil {
+			e.FailureReason = intent.LastPaymentError.Message
+		}
+	default:
+		return nil, nil
+	}
+	return e, nil
+}
+
+// verifySignature checks the "t=<unix>,v1=<hex>" header: v1 is HMAC-SHA256 of "<t>.<body>"
+// with the endpoint's signing secret
+func (p *Stripe) verifySignature(header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(p.cfg.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(expected), []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func stripeIntentStatus(status string) Status {
+	switch status {
+	case "succeeded":
+		return StatusSucceeded
+	case "requires_payment_method", "canceled":
+		return StatusFailed
+	default:
+		// processing, requires_action and requires_capture settle later
+		return StatusPending
+	}
+}
+
+func (p *Stripe) post(ctx context.Context, path, idempotencyKey string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.cfg.SecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	res, err := p.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusPaymentRequired {
+		var payload struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&payload)
+		return declined(payload.Error.Message)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("stripe returned %d for %s", res.StatusCode, path)
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return errors.Join(errors.New("invalid stripe response"), err)
+	}
+	return nil
+}
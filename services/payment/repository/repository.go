@@ -0,0 +1,319 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"errors"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/payment/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// --- Payment GORM models ---
+type Payment struct {
+	ID               int     `gorm:"primaryKey"`
+	Reference        string  `gorm:"column:reference;not null"`
+	OrderID          int     `gorm:"column:order_id;not null;index"`
+	UserID           int     `gorm:"column:user_id;not null;index"`
+	Provider         string  `gorm:"column:provider;not null"`
+	ProviderChargeID string  `gorm:"column:provider_charge_id"`
+	PaymentMethodID  *int    `gorm:"column:payment_method_id"`
+	Amount           float64 `gorm:"column:amount;not null"`
+	RefundedAmount   float64 `gorm:"column:refunded_amount;not null;default:0"`
+	Currency         string  `gorm:"column:currency;size:3;not null"`
+	Status           string  `gorm:"column:status;not null"`
+	FailureReason    string  `gorm:"column:failure_reason"`
+	psql.BaseModel
+}
+
+func (Payment) TableName() string { return "payments" }
+
+type Refund struct {
+	ID               int     `gorm:"primaryKey"`
+	PaymentID        int     `gorm:"column:payment_id;not null;index"`
+	Reference        string  `gorm:"column:reference;not null"`
+	Amount           float64 `gorm:"column:amount;not null"`
+	Currency         string  `gorm:"column:currency;size:3;not null"`
+	Status           string  `gorm:"column:status;not null"`
+	ProviderRefundID string  `gorm:"column:provider_refund_id"`
+	Reason           string  `gorm:"column:reason"`
+	psql.BaseModel
+}
+
+func (Refund) TableName() string { return "refunds" }
+
+type PaymentMethod struct {
+	ID        int    `gorm:"primaryKey"`
+	UserID    int    `gorm:"column:user_id;not null;index"`
+	Provider  string `gorm:"column:provider;not null"`
+	Type      string `gorm:"column:type;not null"`
+	Token     string `gorm:"column:token;not null"`
+	Brand     string `gorm:"column:brand"`
+	Last4     string `gorm:"column:last4;size:4"`
+	ExpMonth  int    `gorm:"column:exp_month"`
+	ExpYear   int    `gorm:"column:exp_year"`
+	IsDefault bool   `gorm:"column:is_default;not null;default:false"`
+	psql.BaseModel
+}
+
+func (PaymentMethod) TableName() string { return "payment_methods" }
+
+// --- Payment Repository ---
+
+type PaymentRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*domain.Payment, error)
+	// GetByIDForUpdate locks the payment row until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int) (*domain.Payment, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	GetByReference(ctx context.Context, reference string) (*domain.Payment, error)
+	GetByChargeID(ctx context.Context, provider, chargeID string) (*domain.Payment, error)
+	GetByOrderID(ctx context.Context, orderID int) (*[]domain.Payment, error)
+	Create(ctx context.Context, p *domain.Payment) (*domain.Payment, error)
+	Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Payment, error)
+	GetRefunds(ctx context.Context, paymentID int) (*[]domain.Refund, error)
+	GetRefundByReference(ctx context.Context, reference string) (*domain.Refund, error)
+	CreateRefund(ctx context.Context, r *domain.Refund) (*domain.Refund, error)
+	UpdateRefund(ctx context.Context, id int, m map[string]interface{}) (*domain.Refund, error)
+}
+
+type PaymentRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewPaymentRepository(db *gorm.DB, l *logger.Logger) PaymentRepositoryInterface {
+	return &PaymentRepository{DB: db, Logger: l}
+}
+
+func (r *PaymentRepository) GetByID(ctx context.Context, id int) (*domain.Payment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("id = ?", id))
+}
+
+func (r *PaymentRepository) GetByIDForUpdate(ctx context.Context, id int) (*domain.Payment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id))
+}
+
+func (r *PaymentRepository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(psql.Conn(ctx, r.DB), &Payment{}, publicID)
+}
+
+func (r *PaymentRepository) GetByReference(ctx context.Context, reference string) (*domain.Payment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("reference = ?", reference))
+}
+
+func (r *PaymentRepository) GetByChargeID(ctx context.Context, provider, chargeID string) (*domain.Payment, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("provider = ? AND provider_charge_id = ?", provider, chargeID))
+}
+
+func (r *PaymentRepository) first(query *gorm.DB) (*domain.Payment, error) {
+	var p Payment
+	if err := query.First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return paymentToDomain(&p), nil
+}
+
+func (r *PaymentRepository) GetByOrderID(ctx context.Context, orderID int) (*[]domain.Payment, error) {
+	var payments []Payment
+	if err := psql.Conn(ctx, r.DB).Where("order_id = ?", orderID).Order("id").Find(&payments).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Payment, len(payments))
+	for i := range payments {
+		result[i] = *paymentToDomain(&payments[i])
+	}
+	return &result, nil
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, d *domain.Payment) (*domain.Payment, error) {
+	p := Payment{
+		Reference: d.Reference, OrderID: d.OrderID, UserID: d.UserID, Provider: d.Provider, ProviderChargeID: d.ProviderChargeID,
+		PaymentMethodID: d.PaymentMethodID, Amount: d.Amount, Currency: d.Currency, Status: string(d.Status), FailureReason: d.FailureReason,
+	}
+	if err := psql.Conn(ctx, r.DB).Create(&p).Error; err != nil {
+		r.Logger.Error("Error creating payment", zap.String("reference", d.Reference), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return paymentToDomain(&p), nil
+}
+
+func (r *PaymentRepository) Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Payment, error) {
+	if err := psql.Conn(ctx, r.DB).Model(&Payment{ID: id}).Updates(m).Error; err != nil {
+		r.Logger.Error("Error updating payment", zap.Int("id", id), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *PaymentRepository) GetRefunds(ctx context.Context, paymentID int) (*[]domain.Refund, error) {
+	var refunds []Refund
+	if err := psql.Conn(ctx, r.DB).Where("payment_id = ?", paymentID).Order("id").Find(&refunds).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Refund, len(refunds))
+	for i := range refunds {
+		result[i] = *refundToDomain(&refunds[i])
+	}
+	return &result, nil
+}
+
+func (r *PaymentRepository) GetRefundByReference(ctx context.Context, reference string) (*domain.Refund, error) {
+	var ref Refund
+	if err := psql.Conn(ctx, r.DB).Where("reference = ?", reference).First(&ref).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return refundToDomain(&ref), nil
+}
+
+func (r *PaymentRepository) CreateRefund(ctx context.Context, d *domain.Refund) (*domain.Refund, error) {
+	ref := Refund{PaymentID: d.PaymentID, Reference: d.Reference, Amount: d.Amount, Currency: d.Currency, Status: string(d.Status), Reason: d.Reason}
+	if err := psql.Conn(ctx, r.DB).Create(&ref).Error; err != nil {
+		r.Logger.Error("Error creating refund", zap.String("reference", d.Reference), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return refundToDomain(&ref), nil
+}
+
+func (r *PaymentRepository) UpdateRefund(ctx context.Context, id int, m map[string]interface{}) (*domain.Refund, error) {
+	var ref Refund
+	if err := psql.Conn(ctx, r.DB).Model(&Refund{ID: id}).Updates(m).Error; err != nil {
+		r.Logger.Error("Error updating refund", zap.Int("id", id), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&ref).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return refundToDomain(&ref), nil
+}
+
+// --- Payment Method Repository ---
+
+type PaymentMethodRepositoryInterface interface {
+	GetByUserID(ctx context.Context, userID int) (*[]domain.PaymentMethod, error)
+	GetByID(ctx context.Context, id int) (*domain.PaymentMethod, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	// GetDefault returns the user's default method, NotFound when none is set
+	GetDefault(ctx context.Context, userID int) (*domain.PaymentMethod, error)
+	Create(ctx context.Context, m *domain.PaymentMethod) (*domain.PaymentMethod, error)
+	// SetDefault makes the method the user's only default one
+	SetDefault(ctx context.Context, userID, id int) error
+	Delete(ctx context.Context, id int) error
+}
+
+type PaymentMethodRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewPaymentMethodRepository(db *gorm.DB, l *logger.Logger) PaymentMethodRepositoryInterface {
+	return &PaymentMethodRepository{DB: db, Logger: l}
+}
+
+func (r *PaymentMethodRepository) GetByUserID(ctx context.Context, userID int) (*[]domain.PaymentMethod, error) {
+	var methods []PaymentMethod
+	if err := psql.Conn(ctx, r.DB).Where("user_id = ?", userID).Order("is_default DESC, id").Find(&methods).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.PaymentMethod, len(methods))
+	for i := range methods {
+		result[i] = *methodToDomain(&methods[i])
+	}
+	return &result, nil
+}
+
+func (r *PaymentMethodRepository) GetByID(ctx context.Context, id int) (*domain.PaymentMethod, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("id = ?", id))
+}
+
+func (r *PaymentMethodRepository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(psql.Conn(ctx, r.DB), &PaymentMethod{}, publicID)
+}
+
+func (r *PaymentMethodRepository) GetDefault(ctx context.Context, userID int) (*domain.PaymentMethod, error) {
+	return r.first(psql.Conn(ctx, r.DB).Where("user_id = ? AND is_default", userID))
+}
+
+func (r *PaymentMethodRepository) first(query *gorm.DB) (*domain.PaymentMethod, error) {
+	var m PaymentMethod
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return methodToDomain(&m), nil
+}
+
+func (r *PaymentMethodRepository) Create(ctx context.Context, d *domain.PaymentMethod) (*domain.PaymentMethod, error) {
+	m := PaymentMethod{
+		UserID: d.UserID, Provider: d.Provider, Type: d.Type, Token: d.Token, Brand: d.Brand, Last4: d.Last4,
+		ExpMonth: d.ExpMonth, ExpYear: d.ExpYear, IsDefault: d.IsDefault,
+	}
+	if err := psql.Conn(ctx, r.DB).Create(&m).Error; err != nil {
+		r.Logger.Error("Error creating payment method", zap.Int("userId", d.UserID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return methodToDomain(&m), nil
+}
+
+func (r *PaymentMethodRepository) SetDefault(ctx context.Context, userID, id int) error {
+	return psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&PaymentMethod{}).Where("user_id = ? AND is_default AND id <> ?", userID, id).Update("is_default", false).Error; err != nil {
+			return psql.TranslateError(err)
+		}
+		res := tx.Model(&PaymentMethod{}).Where("id = ? AND user_id = ?", id, userID).Update("is_default", true)
+		if res.Error != nil {
+			return psql.TranslateError(res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil
+	})
+}
+
+func (r *PaymentMethodRepository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&PaymentMethod{}, id)
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
+
+func paymentToDomain(p *Payment) *domain.Payment {
+	return &domain.Payment{
+		ID: p.ID, UUID: p.UUID, Reference: p.Reference, OrderID: p.OrderID, UserID: p.UserID, Provider: p.Provider,
+		ProviderChargeID: p.ProviderChargeID, PaymentMethodID: p.PaymentMethodID, Amount: p.Amount, RefundedAmount: p.RefundedAmount,
+		Currency: p.Currency, Status: domain.PaymentStatus(p.Status), FailureReason: p.FailureReason, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func refundToDomain(r *Refund) *domain.Refund {
+	return &domain.Refund{
+		ID: r.ID, UUID: r.UUID, PaymentID: r.PaymentID, Reference: r.Reference, Amount: r.Amount, Currency: r.Currency,
+		Status: domain.RefundStatus(r.Status), ProviderRefundID: r.ProviderRefundID, Reason: r.Reason, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func methodToDomain(m *PaymentMethod) *domain.PaymentMethod {
+	return &domain.PaymentMethod{
+		ID: m.ID, UUID: m.UUID, UserID: m.UserID, Provider: m.Provider, Type: m.Type, Token: m.Token, Brand: m.Brand, Last4: m.Last4,
+		ExpMonth: m.ExpMonth, ExpYear: m.ExpYear, IsDefault: m.IsDefault, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt,
+	}
+}
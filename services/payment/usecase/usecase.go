@@ -0,0 +1,468 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/payment/domain"
+	"ecommerce-microservice-go/services/payment/provider"
+	"ecommerce-microservice-go/services/payment/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// --- Payment UseCase ---
+
+type IPaymentUseCase interface {
+	// Charge charges the order once per reference: repeating a reference returns the first
+	// payment, or retries it when the provider could not be reached
+	Charge(ctx context.Context, in domain.ChargeInput) (*domain.Payment, error)
+	GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Payment, error)
+	// ResolveID returns the integer ID of the payment with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	GetByOrder(ctx context.Context, orderID int, requester domain.Requester) (*[]domain.Payment, error)
+	// Refund returns amount, or everything not refunded yet when amount is 0. Repeating a
+	// reference returns the first refund.
+	Refund(ctx context.Context, paymentID int, amount float64, reference, reason string) (*domain.Refund, error)
+	GetRefunds(ctx context.Context, paymentID int, requester domain.Requester) (*[]domain.Refund, error)
+	// HandleWebhook applies a status change pushed by the named provider
+	HandleWebhook(ctx context.Context, providerName string, r *http.Request) error
+}
+
+type PaymentUseCase struct {
+	repo       repository.PaymentRepositoryInterface
+	methodRepo repository.PaymentMethodRepositoryInterface
+	providers  *provider.Registry
+	txManager  psql.TxManager
+	Logger     *logger.Logger
+}
+
+func NewPaymentUseCase(r repository.PaymentRepositoryInterface, methodRepo repository.PaymentMethodRepositoryInterface,
+	providers *provider.Registry, txManager psql.TxManager, l *logger.Logger) IPaymentUseCase {
+	return &PaymentUseCase{repo: r, methodRepo: methodRepo, providers: providers, txManager: txManager, Logger: l}
+}
+
+func (s *PaymentUseCase) Charge(ctx context.Context, in domain.ChargeInput) (*domain.Payment, error) {
+	in.Amount = round2(in.Amount)
+	if in.Amount <= 0 {
+		return nil, domainErrors.NewAppError(errors.New("amount must be positive"), domainErrors.ValidationError)
+	}
+	existing, err := s.repo.GetByReference(ctx, in.Reference)
+	if err == nil {
+		return s.resume(ctx, existing, in)
+	}
+	if !isNotFound(err) {
+		return nil, err
+	}
+
+	var method *domain.PaymentMethod
+	if in.PaymentMethodID != nil {
+		method, err = s.methodRepo.GetByID(ctx, *in.PaymentMethodID)
+		if err != nil {
+			return nil, err
+		}
+		if method.UserID != in.UserID {
+			return nil, domainErrors.NewAppError(errors.New("payment method belongs to another user"), domainErrors.ValidationError)
+		}
+	} else {
+		method, err = s.methodRepo.GetDefault(ctx, in.UserID)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+	}
+	providerName := in.Provider
+	if method != nil {
+		providerName = method.Provider
+	}
+	p, err := s.providers.Get(providerName)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+
+	payment := &domain.Payment{
+		Reference: in.Reference, OrderID: in.OrderID, UserID: in.UserID, Provider: p.Name(),
+		Amount: in.Amount, Currency: in.Currency, Status: domain.PaymentStatusPending,
+	}
+	if method != nil {
+		payment.PaymentMethodID = &method.ID
+	}
+	s.Logger.Info("Charging order", zap.String("reference", in.Reference), zap.Int("orderID", in.OrderID), zap.String("provider", p.Name()))
+	payment, err = s.repo.Create(ctx, payment)
+	if err != nil {
+		// a concurrent request with the same reference won the insert
+		if existing, getErr := s.repo.GetByReference(ctx, in.Reference); getErr == nil {
+			return s.resume(ctx, existing, in)
+		}
+		return nil, err
+	}
+	return s.charge(ctx, p, payment, method)
+}
+
+// resume answers a repeated charge from the stored payment, calling the provider again only
+// when the first attempt never got an answer from it
+func (s *PaymentUseCase) resume(ctx context.Context, payment *domain.Payment, in domain.ChargeInput) (*domain.Payment, error) {
+	if payment.Amount != in.Amount || payment.Currency != in.Currency || payment.OrderID != in.OrderID {
+		return nil, domainErrors.NewAppError(errors.New("reference was already used for a different charge"), domainErrors.ValidationError)
+	}
+	switch {
+	case payment.Status == domain.PaymentStatusFailed:
+		return nil, declinedError(payment.FailureReason)
+	case payment.Status != domain.PaymentStatusPending || payment.ProviderChargeID != "":
+		return payment, nil
+	}
+	p, err := s.providers.Get(payment.Provider)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	var method *domain.PaymentMethod
+	if payment.PaymentMethodID != nil {
+		if method, err = s.methodRepo.GetByID(ctx, *payment.PaymentMethodID); err != nil {
+			return nil, err
+		}
+	}
+	s.Logger.Info("Retrying charge", zap.String("reference", payment.Reference), zap.String("provider", payment.Provider))
+	return s.charge(ctx, p, payment, method)
+}
+
+func (s *PaymentUseCase) charge(ctx context.Context, p provider.Provider, payment *domain.Payment, method *domain.PaymentMethod) (*domain.Payment, error) {
+	req := provider.ChargeRequest{
+		Reference: payment.Reference, Amount: payment.Amount, Currency: payment.Currency, Description: "Order " + strconv.Itoa(payment.OrderID),
+	}
+	if method != nil {
+		req.Token = method.Token
+	}
+	res, err := p.Charge(ctx, req)
+	switch {
+	case errors.Is(err, provider.ErrDeclined), errors.Is(err, provider.ErrUnsupportedCurrency):
+		s.Logger.Info("Charge declined", zap.String("reference", payment.Reference), zap.Error(err))
+		if _, updateErr := s.repo.Update(ctx, payment.ID, map[string]interface{}{
+			"status": string(domain.PaymentStatusFailed), "failure_reason": err.Error(),
+		}); updateErr != nil {
+			return nil, updateErr
+		}
+		return nil, declinedError(err.Error())
+	case err != nil:
+		// the payment stays pending without a charge, so repeating the reference tries again
+		s.Logger.Error("Payment provider failed", zap.String("reference", payment.Reference), zap.String("provider", p.Name()), zap.Error(err))
+		return nil, domainErrors.NewAppError(fmt.Errorf("payment provider %s failed: %w", p.Name(), err), domainErrors.UnknownError)
+	}
+	status := domain.PaymentStatusPending
+	if res.Status == provider.StatusSucceeded {
+		status = domain.PaymentStatusSucceeded
+	}
+	return s.repo.Update(ctx, payment.ID, map[string]interface{}{"provider_charge_id": res.ID, "status": string(status)})
+}
+
+func (s *PaymentUseCase) GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanAccess(payment.UserID) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return payment, nil
+}
+
+func (s *PaymentUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
+}
+
+func (s *PaymentUseCase) GetByOrder(ctx context.Context, orderID int, requester domain.Requester) (*[]domain.Payment, error) {
+	payments, err := s.repo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]domain.Payment, 0, len(*payments))
+	for _, p := range *payments {
+		if requester.CanAccess(p.UserID) {
+			visible = append(visible, p)
+		}
+	}
+	return &visible, nil
+}
+
+func (s *PaymentUseCase) Refund(ctx context.Context, paymentID int, amount float64, reference, reason string) (*domain.Refund, error) {
+	if reference == "" {
+		reference = "refund_" + uuid.NewString()
+	}
+	if existing, err := s.repo.GetRefundByReference(ctx, reference); err == nil {
+		if existing.PaymentID != paymentID {
+			return nil, domainErrors.NewAppError(errors.New("reference was already used for another payment"), domainErrors.ValidationError)
+		}
+		return existing, nil
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+	if amount < 0 {
+		return nil, domainErrors.NewAppError(errors.New("amount must not be negative"), domainErrors.ValidationError)
+	}
+
+	// reserve the amount first, so concurrent refunds cannot return more than was paid
+	var payment *domain.Payment
+	var refund *domain.Refund
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		var err error
+		if payment, err = s.repo.GetByIDForUpdate(ctx, paymentID); err != nil {
+			return err
+		}
+		if !payment.Status.Refundable() {
+			return domainErrors.NewAppError(fmt.Errorf("a %s payment cannot be refunded", payment.Status), domainErrors.ValidationError)
+		}
+		remaining := round2(payment.Amount - payment.RefundedAmount)
+		if amount == 0 {
+			amount = remaining
+		}
+		amount = round2(amount)
+		if amount <= 0 || amount > remaining {
+			return domainErrors.NewAppError(fmt.Errorf("at most %.2f %s can be refunded", remaining, payment.Currency), domainErrors.ValidationError)
+		}
+		if refund, err = s.repo.CreateRefund(ctx, &domain.Refund{
+			PaymentID: paymentID, Reference: reference, Amount: amount, Currency: payment.Currency, Status: domain.RefundStatusPending, Reason: reason,
+		}); err != nil {
+			return err
+		}
+		_, err = s.repo.Update(ctx, paymentID, map[string]interface{}{"refunded_amount": gorm.Expr("refunded_amount + ?", amount)})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.providers.Get(payment.Provider)
+	if err == nil {
+		s.Logger.Info("Refunding payment", zap.Int("paymentID", paymentID), zap.Float64("amount", amount), zap.String("reference", reference))
+		var res *provider.Result
+		res, err = p.Refund(ctx, provider.RefundRequest{
+			Reference: reference, ChargeID: payment.ProviderChargeID, Amount: amount, Currency: payment.Currency, Reason: reason,
+		})
+		if err == nil && res.Status != provider.StatusFailed {
+			return s.completeRefund(ctx, payment, refund, res)
+		}
+		if err == nil {
+			err = errors.New("provider reported the refund as failed")
+		}
+	}
+	s.Logger.Error("Refund failed", zap.Int("paymentID", paymentID), zap.String("reference", reference), zap.Error(err))
+	if releaseErr := s.releaseRefund(ctx, refund); releaseErr != nil {
+		s.Logger.Error("Error releasing failed refund", zap.String("reference", reference), zap.Error(releaseErr))
+	}
+	return nil, domainErrors.NewAppError(fmt.Errorf("refund failed: %w", err), domainErrors.UnknownError)
+}
+
+func (s *PaymentUseCase) completeRefund(ctx context.Context, payment *domain.Payment, refund *domain.Refund, res *provider.Result) (*domain.Refund, error) {
+	status := domain.RefundStatusPending
+	if res.Status == provider.StatusSucceeded {
+		status = domain.RefundStatusSucceeded
+	}
+	var updated *domain.Refund
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		var err error
+		if updated, err = s.repo.UpdateRefund(ctx, refund.ID, map[string]interface{}{"status": string(status), "provider_refund_id": res.ID}); err != nil {
+			return err
+		}
+		current, err := s.repo.GetByIDForUpdate(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+		paymentStatus := domain.PaymentStatusPartiallyRefunded
+		if round2(current.RefundedAmount) >= current.Amount {
+			paymentStatus = domain.PaymentStatusRefunded
+		}
+		_, err = s.repo.Update(ctx, payment.ID, map[string]interface{}{"status": string(paymentStatus)})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// releaseRefund marks the refund failed and gives its amount back to the payment
+func (s *PaymentUseCase) releaseRefund(ctx context.Context, refund *domain.Refund) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if _, err := s.repo.UpdateRefund(ctx, refund.ID, map[string]interface{}{"status": string(domain.RefundStatusFailed)}); err != nil {
+			return err
+		}
+		_, err := s.repo.Update(ctx, refund.PaymentID, map[string]interface{}{"refunded_amount": gorm.Expr("refunded_amount - ?", refund.Amount)})
+		return err
+	})
+}
+
+func (s *PaymentUseCase) GetRefunds(ctx context.Context, paymentID int, requester domain.Requester) (*[]domain.Refund, error) {
+	if _, err := s.GetByID(ctx, paymentID, requester); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRefunds(ctx, paymentID)
+}
+
+func (s *PaymentUseCase) HandleWebhook(ctx context.Context, providerName string, r *http.Request) error {
+	if providerName == "" {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	p, err := s.providers.Get(providerName)
+	if err != nil {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	event, err := p.ParseWebhook(ctx, r)
+	if errors.Is(err, provider.ErrInvalidSignature) {
+		return domainErrors.NewAppError(err, domainErrors.NotAuthenticated)
+	}
+	if err != nil {
+		return domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if event == nil {
+		return nil
+	}
+
+	var payment *domain.Payment
+	err = domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	if event.ChargeID != "" {
+		payment, err = s.repo.GetByChargeID(ctx, p.Name(), event.ChargeID)
+	}
+	if isNotFound(err) && event.Reference != "" {
+		payment, err = s.repo.GetByReference(ctx, event.Reference)
+	}
+	if isNotFound(err) || (err == nil && payment.Provider != p.Name()) {
+		// acknowledge events for charges made elsewhere, or the provider keeps resending them
+		s.Logger.Warn("Webhook for an unknown payment", zap.String("provider", p.Name()), zap.String("chargeID", event.ChargeID),
+			zap.String("reference", event.Reference))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// succeeded and failed are final; refunds move a payment on from there
+	if payment.Status != domain.PaymentStatusPending || event.Status == provider.StatusPending {
+		return nil
+	}
+	updates := map[string]interface{}{"status": string(domain.PaymentStatusSucceeded)}
+	if event.Status == provider.StatusFailed {
+		updates = map[string]interface{}{"status": string(domain.PaymentStatusFailed), "failure_reason": event.FailureReason}
+	}
+	if payment.ProviderChargeID == "" && event.ChargeID != "" {
+		updates["provider_charge_id"] = event.ChargeID
+	}
+	s.Logger.Info("Payment settled by webhook", zap.Int("paymentID", payment.ID), zap.String("provider", p.Name()), zap.Any("status", updates["status"]))
+	_, err = s.repo.Update(ctx, payment.ID, updates)
+	return err
+}
+
+// --- Payment Method UseCase ---
+
+type IPaymentMethodUseCase interface {
+	List(ctx context.Context, userID int) (*[]domain.PaymentMethod, error)
+	// ResolveID returns the integer ID of the payment method with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	// Add saves a method tokenized by the provider; the user's first method becomes the default
+	Add(ctx context.Context, m *domain.PaymentMethod) (*domain.PaymentMethod, error)
+	SetDefault(ctx context.Context, id int, requester domain.Requester) (*domain.PaymentMethod, error)
+	Delete(ctx context.Context, id int, requester domain.Requester) error
+}
+
+type PaymentMethodUseCase struct {
+	repo      repository.PaymentMethodRepositoryInterface
+	providers *provider.Registry
+	Logger    *logger.Logger
+}
+
+func NewPaymentMethodUseCase(r repository.PaymentMethodRepositoryInterface, providers *provider.Registry, l *logger.Logger) IPaymentMethodUseCase {
+	return &PaymentMethodUseCase{repo: r, providers: providers, Logger: l}
+}
+
+func (s *PaymentMethodUseCase) List(ctx context.Context, userID int) (*[]domain.PaymentMethod, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+func (s *PaymentMethodUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
+}
+
+func (s *PaymentMethodUseCase) Add(ctx context.Context, m *domain.PaymentMethod) (*domain.PaymentMethod, error) {
+	p, err := s.providers.Get(m.Provider)
+	// the sandbox approves every charge, so a saved sandbox method would settle orders unpaid
+	if err != nil || m.Provider == "" || p.Name() == provider.SandboxName {
+		return nil, domainErrors.NewAppError(fmt.Errorf("%w %q", provider.ErrUnknownProvider, m.Provider), domainErrors.ValidationError)
+	}
+	m.Provider = p.Name()
+	makeDefault := m.IsDefault
+	if _, err := s.repo.GetDefault(ctx, m.UserID); isNotFound(err) {
+		makeDefault = true
+	} else if err != nil {
+		return nil, err
+	}
+	m.IsDefault = false
+	s.Logger.Info("Adding payment method", zap.Int("userID", m.UserID), zap.String("provider", m.Provider))
+	created, err := s.repo.Create(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if !makeDefault {
+		return created, nil
+	}
+	if err := s.repo.SetDefault(ctx, created.UserID, created.ID); err != nil {
+		return nil, err
+	}
+	created.IsDefault = true
+	return created, nil
+}
+
+func (s *PaymentMethodUseCase) SetDefault(ctx context.Context, id int, requester domain.Requester) (*domain.PaymentMethod, error) {
+	m, err := s.owned(ctx, id, requester)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetDefault(ctx, m.UserID, m.ID); err != nil {
+		return nil, err
+	}
+	m.IsDefault = true
+	return m, nil
+}
+
+func (s *PaymentMethodUseCase) Delete(ctx context.Context, id int, requester domain.Requester) error {
+	m, err := s.owned(ctx, id, requester)
+	if err != nil {
+		return err
+	}
+	s.Logger.Info("Deleting payment method", zap.Int("id", id), zap.Int("userID", m.UserID))
+	return s.repo.Delete(ctx, id)
+}
+
+// owned returns the method if it belongs to the requester; other users' methods are NotFound
+func (s *PaymentMethodUseCase) owned(ctx context.Context, id int, requester domain.Requester) (*domain.PaymentMethod, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.UserID != requester.UserID {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return m, nil
+}
+
+func declinedError(reason string) error {
+	err := errors.New("payment declined")
+	if reason != "" {
+		err = errors.New(reason)
+	}
+	return domainErrors.NewAppError(err, domainErrors.PaymentDeclined)
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, domainErrors.ErrNotFound)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
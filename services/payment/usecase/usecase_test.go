@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	psqlmocks "ecommerce-microservice-go/pkg/psql/mocks"
+	"ecommerce-microservice-go/services/payment/domain"
+	"ecommerce-microservice-go/services/payment/provider"
+	providermocks "ecommerce-microservice-go/services/payment/provider/mocks"
+	"ecommerce-microservice-go/services/payment/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	testLogger     = &logger.Logger{Log: zap.NewNop()}
+	notFound       = domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	errUnavailable = errors.New("connection refused")
+)
+
+// paymentMocks are the dependencies of PaymentUseCase
+type paymentMocks struct {
+	repo     *mocks.MockPaymentRepositoryInterface
+	methods  *mocks.MockPaymentMethodRepositoryInterface
+	provider *providermocks.MockProvider
+}
+
+func newPaymentUseCase(t *testing.T) (IPaymentUseCase, paymentMocks) {
+	ctrl := gomock.NewController(t)
+	m := paymentMocks{
+		repo: mocks.NewMockPaymentRepositoryInterface(ctrl), methods: mocks.NewMockPaymentMethodRepositoryInterface(ctrl),
+		provider: providermocks.NewMockProvider(ctrl),
+	}
+	m.provider.EXPECT().Name().Return("stripe").AnyTimes()
+	tx := psqlmocks.NewMockTxManager(ctrl)
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, *gorm.DB) error) error { return fn(ctx, nil) }).AnyTimes()
+	return NewPaymentUseCase(m.repo, m.methods, provider.NewRegistry("stripe", m.provider), tx, testLogger), m
+}
+
+// updated returns the payment with the changes the use case wrote
+func updated(payment domain.Payment) func(context.Context, int, map[string]interface{}) (*domain.Payment, error) {
+	return func(_ context.Context, _ int, changes map[string]interface{}) (*domain.Payment, error) {
+		if status, ok := changes["status"].(string); ok {
+			payment.Status = domain.PaymentStatus(status)
+		}
+		if id, ok := changes["provider_charge_id"].(string); ok {
+			payment.ProviderChargeID = id
+		}
+		return &payment, nil
+	}
+}
+
+func TestPaymentUseCaseCharge(t *testing.T) {
+	card := &domain.PaymentMethod{ID: 4, UserID: 7, Provider: "stripe", Token: "tok_visa"}
+	in := domain.ChargeInput{Reference: "order-12", OrderID: 12, UserID: 7, Amount: 49.999, Currency: "IDR"}
+	pending := domain.Payment{ID: 1, Reference: "order-12", OrderID: 12, UserID: 7, Provider: "stripe", Amount: 50, Currency: "IDR",
+		Status: domain.PaymentStatusPending}
+	// creates expects a new payment with the user's default card
+	creates := func(m paymentMocks) {
+		m.repo.EXPECT().GetByReference(gomock.Any(), "order-12").Return(nil, notFound)
+		m.methods.EXPECT().GetDefault(gomock.Any(), 7).Return(card, nil)
+		m.repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&pending, nil)
+	}
+
+	tests := []struct {
+		name       string
+		in         domain.ChargeInput
+		setup      func(m paymentMocks)
+		wantStatus domain.PaymentStatus
+		wantErr    error
+	}{
+		{
+			name: "default card charged",
+			in:   in,
+			setup: func(m paymentMocks) {
+				creates(m)
+				m.provider.EXPECT().Charge(gomock.Any(), provider.ChargeRequest{Reference: "order-12", Amount: 50, Currency: "IDR",
+					Description: "Order 12", Token: "tok_visa"}).Return(&provider.Result{ID: "ch_1", Status: provider.StatusSucceeded}, nil)
+				m.repo.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(updated(pending))
+			},
+			wantStatus: domain.PaymentStatusSucceeded,
+		},
+		{
+			name: "declined charge is stored as failed",
+			in:   in,
+			setup: func(m paymentMocks) {
+				creates(m)
+				m.provider.EXPECT().Charge(gomock.Any(), gomock.Any()).Return(nil, provider.ErrDeclined)
+				m.repo.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(updated(pending))
+			},
+			wantErr: domainErrors.ErrPaymentDeclined,
+		},
+		{
+			name: "unreachable provider leaves the payment pending",
+			in:   in,
+			setup: func(m paymentMocks) {
+				creates(m)
+				m.provider.EXPECT().Charge(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+			},
+			wantErr: domainErrors.NewAppErrorWithType(domainErrors.UnknownError),
+		},
+		{
+			name: "repeated reference returns the settled payment",
+			in:   in,
+			setup: func(m paymentMocks) {
+				settled := pending
+				settled.Status, settled.ProviderChargeID = domain.PaymentStatusSucceeded, "ch_1"
+				m.repo.EXPECT().GetByReference(gomock.Any(), "order-12").Return(&settled, nil)
+			},
+			wantStatus: domain.PaymentStatusSucceeded,
+		},
+		{
+			name: "repeated reference retries a charge that never reached the provider",
+			in:   in,
+			setup: func(m paymentMocks) {
+				retried := pending
+				retried.PaymentMethodID = &card.ID
+				m.repo.EXPECT().GetByReference(gomock.Any(), "order-12").Return(&retried, nil)
+				m.methods.EXPECT().GetByID(gomock.Any(), 4).Return(card, nil)
+				m.provider.EXPECT().Charge(gomock.Any(), gomock.Any()).Return(&provider.Result{ID: "ch_1", Status: provider.StatusSucceeded}, nil)
+				m.repo.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(updated(retried))
+			},
+			wantStatus: domain.PaymentStatusSucceeded,
+		},
+		{
+			name: "reference reused for another amount",
+			in:   domain.ChargeInput{Reference: "order-12", OrderID: 12, UserID: 7, Amount: 60, Currency: "IDR"},
+			setup: func(m paymentMocks) {
+				m.repo.EXPECT().GetByReference(gomock.Any(), "order-12").Return(&pending, nil)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "another user's card",
+			in:   domain.ChargeInput{Reference: "order-12", OrderID: 12, UserID: 8, Amount: 50, Currency: "IDR", PaymentMethodID: &card.ID},
+			setup: func(m paymentMocks) {
+				m.repo.EXPECT().GetByReference(gomock.Any(), "order-12").Return(nil, notFound)
+				m.methods.EXPECT().GetByID(gomock.Any(), 4).Return(card, nil)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name:    "amount rounding to zero",
+			in:      domain.ChargeInput{Reference: "order-12", Amount: 0.001},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newPaymentUseCase(t)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			got, err := uc.Charge(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPaymentUseCaseRefund(t *testing.T) {
+	paid := domain.Payment{ID: 1, Provider: "stripe", ProviderChargeID: "ch_1", Amount: 50, RefundedAmount: 20, Currency: "IDR",
+		Status: domain.PaymentStatusPartiallyRefunded}
+	// reserves expects the amount to be reserved on the payment before the provider is called
+	reserves := func(m paymentMocks, amount float64) {
+		m.repo.EXPECT().GetRefundByReference(gomock.Any(), "refund-1").Return(nil, notFound)
+		m.repo.EXPECT().GetByIDForUpdate(gomock.Any(), 1).Return(&paid, nil)
+		m.repo.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *domain.Refund) (*domain.Refund, error) {
+			if r.Amount != amount {
+				return nil, errors.New("unexpected refund amount")
+			}
+			r.ID = 9
+			return r, nil
+		})
+		m.repo.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(&paid, nil)
+	}
+
+	tests := []struct {
+		name       string
+		amount     float64
+		setup      func(m paymentMocks)
+		wantStatus domain.RefundStatus
+		wantErr    error
+	}{
+		{
+			name: "everything not refunded yet",
+			setup: func(m paymentMocks) {
+				reserves(m, 30)
+				m.provider.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(&provider.Result{ID: "re_1", Status: provider.StatusSucceeded}, nil)
+				m.repo.EXPECT().UpdateRefund(gomock.Any(), 9, gomock.Any()).Return(&domain.Refund{ID: 9, Status: domain.RefundStatusSucceeded}, nil)
+				m.repo.EXPECT().GetByIDForUpdate(gomock.Any(), 1).Return(&domain.Payment{ID: 1, Amount: 50, RefundedAmount: 50}, nil)
+				m.repo.EXPECT().Update(gomock.Any(), 1, map[string]interface{}{"status": string(domain.PaymentStatusRefunded)}).Return(&paid, nil)
+			},
+			wantStatus: domain.RefundStatusSucceeded,
+		},
+		{
+			name:   "failed refund gives the amount back",
+			amount: 10,
+			setup: func(m paymentMocks) {
+				reserves(m, 10)
+				m.provider.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+				m.repo.EXPECT().UpdateRefund(gomock.Any(), 9, map[string]interface{}{"status": string(domain.RefundStatusFailed)}).Return(nil, nil)
+				m.repo.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(&paid, nil)
+			},
+			wantErr: domainErrors.NewAppErrorWithType(domainErrors.UnknownError),
+		},
+		{
+			name:   "more than was paid",
+			amount: 31,
+			setup: func(m paymentMocks) {
+				m.repo.EXPECT().GetRefundByReference(gomock.Any(), "refund-1").Return(nil, notFound)
+				m.repo.EXPECT().GetByIDForUpdate(gomock.Any(), 1).Return(&paid, nil)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "repeated reference returns the first refund",
+			setup: func(m paymentMocks) {
+				m.repo.EXPECT().GetRefundByReference(gomock.Any(), "refund-1").Return(&domain.Refund{ID: 9, PaymentID: 1, Status: domain.RefundStatusPending}, nil)
+			},
+			wantStatus: domain.RefundStatusPending,
+		},
+		{
+			name: "reference of another payment's refund",
+			setup: func(m paymentMocks) {
+				m.repo.EXPECT().GetRefundByReference(gomock.Any(), "refund-1").Return(&domain.Refund{ID: 9, PaymentID: 2}, nil)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newPaymentUseCase(t)
+			tt.setup(m)
+			got, err := uc.Refund(context.Background(), 1, tt.amount, "refund-1", "damaged")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPaymentMethodUseCaseAdd(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      domain.PaymentMethod
+		setup       func(repo *mocks.MockPaymentMethodRepositoryInterface)
+		wantDefault bool
+		wantErr     error
+	}{
+		{
+			name:   "first method becomes the default",
+			method: domain.PaymentMethod{UserID: 7, Provider: "Stripe", Token: "tok_visa"},
+			setup: func(repo *mocks.MockPaymentMethodRepositoryInterface) {
+				repo.EXPECT().GetDefault(gomock.Any(), 7).Return(nil, notFound)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *domain.PaymentMethod) (*domain.PaymentMethod, error) {
+					m.ID = 4
+					return m, nil
+				})
+				repo.EXPECT().SetDefault(gomock.Any(), 7, 4).Return(nil)
+			},
+			wantDefault: true,
+		},
+		{
+			name:   "later methods keep the current default",
+			method: domain.PaymentMethod{UserID: 7, Provider: "stripe", Token: "tok_visa"},
+			setup: func(repo *mocks.MockPaymentMethodRepositoryInterface) {
+				repo.EXPECT().GetDefault(gomock.Any(), 7).Return(&domain.PaymentMethod{ID: 3, UserID: 7}, nil)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m *domain.PaymentMethod) (*domain.PaymentMethod, error) {
+					m.ID = 4
+					return m, nil
+				})
+			},
+		},
+		{
+			name:    "unknown provider",
+			method:  domain.PaymentMethod{UserID: 7, Provider: "paypal"},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name:    "sandbox cannot be saved",
+			method:  domain.PaymentMethod{UserID: 7, Provider: "sandbox", Token: "tok_visa"},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, p := mocks.NewMockPaymentMethodRepositoryInterface(ctrl), providermocks.NewMockProvider(ctrl)
+			p.EXPECT().Name().Return("stripe").AnyTimes()
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			method := tt.method
+			got, err := NewPaymentMethodUseCase(repo, provider.NewRegistry("stripe", p, provider.NewSandbox()), testLogger).Add(context.Background(), &method)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.IsDefault != tt.wantDefault || got.Provider != "stripe") {
+				t.Errorf("method = %+v, want default %v", got, tt.wantDefault)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+//go:generate mockgen -source=catalog_client.go -destination=mocks/catalog_client.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/promotion/domain"
+)
+
+var ErrProductNotFound = errors.New("product not found")
+
+type ICatalogClient interface {
+	GetProduct(ctx context.Context, productID int) (*domain.CatalogProduct, error)
+}
+
+// CatalogClient reads products from the catalog service's public API, bypassing the gateway
+type CatalogClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewCatalogClient(baseURL string) ICatalogClient {
+	return &CatalogClient{baseURL: baseURL, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(otel.Transport(nil))}}
+}
+
+func (c *CatalogClient) GetProduct(ctx context.Context, productID int) (*domain.CatalogProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/product/"+strconv.Itoa(productID), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("catalog service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrProductNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("catalog service returned %d for product %d", res.StatusCode, productID)
+	}
+	var product domain.CatalogProduct
+	if err := controllers.DecodeData(res.Body, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
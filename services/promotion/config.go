@@ -0,0 +1,56 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/promotion/usecase"
+)
+
+type Config struct {
+	Server            config.Server
+	Telemetry         otel.Config
+	Log               logger.Config
+	CORS              *config.Live[config.CORS]
+	Security          config.SecurityHeaders
+	Database          config.Database
+	JWT               config.JWT
+	Cache             cache.Config
+	RateLimit         *config.Live[config.RateLimit]
+	Promotion         usecase.PromotionConfig
+	CatalogServiceURL string
+	BrokerURL         string
+	BrokerExchange    string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8091", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "promotion", false),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Promotion: usecase.PromotionConfig{
+			Currency:        src.String("PROMOTION_CURRENCY", "IDR"),
+			LiveCacheTTL:    src.Duration("PROMOTION_LIVE_CACHE_SECONDS", 30, time.Second),
+			ProductCacheTTL: src.Duration("PROMOTION_PRODUCT_CACHE_SECONDS", 60, time.Second),
+		},
+		CatalogServiceURL: src.String("CATALOG_SERVICE_URL", "http://localhost:9092"),
+		BrokerURL:         src.Required("BROKER_URL"),
+		BrokerExchange:    src.String("BROKER_EXCHANGE", events.DefaultExchange),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "promotion", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
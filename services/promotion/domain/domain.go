@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+)
+
+type PromotionType string
+
+const (
+	// TypeBuyXGetY discounts the cheapest GetQuantity of every BuyQuantity+GetQuantity eligible units
+	TypeBuyXGetY PromotionType = "buy_x_get_y"
+	// TypeCategoryDiscount takes Percent off the items in CategoryIDs
+	TypeCategoryDiscount PromotionType = "category_discount"
+	// TypeFreeShipping takes Percent off the shipping fee, usually above a MinSubtotal
+	TypeFreeShipping PromotionType = "free_shipping"
+)
+
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case TypeBuyXGetY, TypeCategoryDiscount, TypeFreeShipping:
+		return true
+	}
+	return false
+}
+
+// Stacking decides which promotions combine. Stackable promotions all apply, one after the
+// other in priority order; an exclusive promotion applies alone, and only when it saves more
+// than the stackable ones together.
+type Stacking string
+
+const (
+	StackingStackable Stacking = "stackable"
+	StackingExclusive Stacking = "exclusive"
+)
+
+func (s Stacking) IsValid() bool {
+	return s == StackingStackable || s == StackingExclusive
+}
+
+type Promotion struct {
+	ID          int
+	UUID        string
+	Name        string
+	Description string
+	Type        PromotionType
+	Active      bool
+	// Priority orders stackable promotions, highest first; each one discounts what the
+	// previous ones left
+	Priority int
+	Stacking Stacking
+	// StartsAt and EndsAt bound when the promotion applies; nil is open-ended
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	// MinSubtotal is the cart subtotal, before discounts and in Currency, the promotion needs
+	Currency    string
+	MinSubtotal float64
+	Percent     float64
+	BuyQuantity int
+	GetQuantity int
+	// ProductIDs and CategoryIDs restrict the items a promotion discounts; both empty means all
+	ProductIDs  []int
+	CategoryIDs []int
+	// Segments restricts the promotion to members of any of the customer segments; empty is everyone
+	Segments  []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// LiveAt reports whether the promotion is active and within its window at t
+func (p *Promotion) LiveAt(t time.Time) bool {
+	return p.Active && (p.StartsAt == nil || !t.Before(*p.StartsAt)) && (p.EndsAt == nil || t.Before(*p.EndsAt))
+}
+
+// Eligible reports whether the promotion discounts an item
+func (p *Promotion) Eligible(item CartItem) bool {
+	if len(p.ProductIDs) == 0 && len(p.CategoryIDs) == 0 {
+		return true
+	}
+	return slices.Contains(p.ProductIDs, item.ProductID) || slices.Contains(p.CategoryIDs, item.CategoryID)
+}
+
+// TargetsAny reports whether a customer in the segments may use the promotion
+func (p *Promotion) TargetsAny(segments []string) bool {
+	if len(p.Segments) == 0 {
+		return true
+	}
+	for _, s := range segments {
+		if slices.Contains(p.Segments, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cart is what a customer is about to buy. Prices are unit prices in Currency.
+type Cart struct {
+	UserID      int
+	Currency    string
+	Items       []CartItem
+	ShippingFee float64
+}
+
+type CartItem struct {
+	ProductID  int
+	CategoryID int
+	Quantity   int
+	Price      float64
+}
+
+// Evaluation is the outcome of applying the live promotions to a cart. Lines follow the order
+// of the cart items.
+type Evaluation struct {
+	Currency         string
+	Subtotal         float64
+	DiscountAmount   float64
+	ShippingFee      float64
+	ShippingDiscount float64
+	// Total is Subtotal - DiscountAmount + ShippingFee - ShippingDiscount
+	Total   float64
+	Lines   []LineResult
+	Applied []AppliedPromotion
+}
+
+type LineResult struct {
+	ProductID      int
+	Quantity       int
+	Price          float64
+	Subtotal       float64
+	DiscountAmount float64
+	Discounts      []LineDiscount
+}
+
+// LineDiscount is what one promotion took off one line
+type LineDiscount struct {
+	PromotionID string
+	Name        string
+	Amount      float64
+}
+
+// AppliedPromotion is a promotion that discounted the cart, with its items and shipping discount combined
+type AppliedPromotion struct {
+	PromotionID string
+	Name        string
+	Type        PromotionType
+	Amount      float64
+}
+
+var segmentName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// ValidSegment reports whether name can name a customer segment, like "vip" or "b2b-jakarta"
+func ValidSegment(name string) bool {
+	return segmentName.MatchString(name)
+}
+
+// SegmentSummary is a customer segment with its number of members
+type SegmentSummary struct {
+	Name    string
+	Members int64
+}
+
+// EventTypes are the routing keys the service subscribes to
+var EventTypes = contracts.Types(contracts.UserDeleted)
+
+// CatalogProduct holds the fields of a catalog product evaluation needs
+type CatalogProduct struct {
+	ID         int     `json:"id"`
+	Price      float64 `json:"price"`
+	CategoryID int     `json:"categoryId"`
+	IsActive   bool    `json:"isActive"`
+}
@@ -0,0 +1,175 @@
+// Package engine applies promotions to a cart. It is pure: the promotions, the customer's
+// segments and the time are passed in, so the same inputs always give the same discounts.
+package engine
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"ecommerce-microservice-go/services/promotion/domain"
+)
+
+// Evaluate applies the promotions that are live at now, target one of the segments and whose
+// minimum subtotal the cart reaches. Stackable promotions apply in priority order, each
+// discounting what the previous ones left, so two 10% discounts take 19% off. Every exclusive
+// promotion is then tried on its own and replaces the stackable ones when it saves more; ties
+// keep the stackable ones. A line is never discounted below zero.
+func Evaluate(cart domain.Cart, promotions []domain.Promotion, segments []string, now time.Time) *domain.Evaluation {
+	subtotal := 0.0
+	for _, item := range cart.Items {
+		subtotal += float64(item.Quantity) * item.Price
+	}
+	subtotal = round2(subtotal)
+
+	var stackable, exclusive []*domain.Promotion
+	for i := range promotions {
+		p := &promotions[i]
+		if !applicable(p, cart, subtotal, segments, now) {
+			continue
+		}
+		if p.Stacking == domain.StackingExclusive {
+			exclusive = append(exclusive, p)
+		} else {
+			stackable = append(stackable, p)
+		}
+	}
+	byPriority := func(a, b *domain.Promotion) int {
+		return cmp.Or(cmp.Compare(b.Priority, a.Priority), cmp.Compare(a.ID, b.ID))
+	}
+	slices.SortStableFunc(stackable, byPriority)
+	slices.SortStableFunc(exclusive, byPriority)
+
+	best := newState(cart)
+	for _, p := range stackable {
+		best.apply(p, cart)
+	}
+	for _, p := range exclusive {
+		candidate := newState(cart)
+		candidate.apply(p, cart)
+		if candidate.saved() > best.saved() {
+			best = candidate
+		}
+	}
+	return best.result(cart, subtotal)
+}
+
+func applicable(p *domain.Promotion, cart domain.Cart, subtotal float64, segments []string, now time.Time) bool {
+	if !p.LiveAt(now) || !p.TargetsAny(segments) {
+		return false
+	}
+	// thresholds are amounts, which only compare within one currency
+	return p.MinSubtotal <= 0 || (p.Currency == cart.Currency && subtotal >= p.MinSubtotal)
+}
+
+// state is what is left to pay while promotions apply one after another
+type state struct {
+	remaining        []float64
+	discounts        [][]domain.LineDiscount
+	shipping         float64
+	shippingDiscount float64
+	applied          []domain.AppliedPromotion
+}
+
+func newState(cart domain.Cart) *state {
+	s := &state{remaining: make([]float64, len(cart.Items)), discounts: make([][]domain.LineDiscount, len(cart.Items)), shipping: round2(cart.ShippingFee)}
+	for i, item := range cart.Items {
+		s.remaining[i] = round2(float64(item.Quantity) * item.Price)
+	}
+	return s
+}
+
+func (s *state) apply(p *domain.Promotion, cart domain.Cart) {
+	amount := 0.0
+	switch p.Type {
+	case domain.TypeCategoryDiscount:
+		for i, item := range cart.Items {
+			if p.Eligible(item) {
+				amount += s.take(i, p, s.remaining[i]*p.Percent/100)
+			}
+		}
+	case domain.TypeBuyXGetY:
+		amount = s.applyBuyXGetY(p, cart)
+	case domain.TypeFreeShipping:
+		d := round2(s.shipping * p.Percent / 100)
+		s.shipping = round2(s.shipping - d)
+		s.shippingDiscount = round2(s.shippingDiscount + d)
+		amount = d
+	}
+	if amount > 0 {
+		s.applied = append(s.applied, domain.AppliedPromotion{PromotionID: p.UUID, Name: p.Name, Type: p.Type, Amount: round2(amount)})
+	}
+}
+
+// applyBuyXGetY discounts GetQuantity units of every BuyQuantity+GetQuantity eligible units
+// in the cart, taking the cheapest units, so mixing products never makes the dearer one free
+func (s *state) applyBuyXGetY(p *domain.Promotion, cart domain.Cart) float64 {
+	type units struct {
+		line      int
+		count     int
+		unitPrice float64
+	}
+	var pool []units
+	total := 0
+	for i, item := range cart.Items {
+		if item.Quantity > 0 && p.Eligible(item) {
+			pool = append(pool, units{line: i, count: item.Quantity, unitPrice: s.remaining[i] / float64(item.Quantity)})
+			total += item.Quantity
+		}
+	}
+	discounted := total / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+	slices.SortStableFunc(pool, func(a, b units) int { return cmp.Compare(a.unitPrice, b.unitPrice) })
+	amount := 0.0
+	for _, u := range pool {
+		if discounted == 0 {
+			break
+		}
+		n := min(discounted, u.count)
+		amount += s.take(u.line, p, float64(n)*u.unitPrice*p.Percent/100)
+		discounted -= n
+	}
+	return amount
+}
+
+// take discounts the line by amount, at most what is left of it, and returns what was taken
+func (s *state) take(line int, p *domain.Promotion, amount float64) float64 {
+	amount = round2(min(amount, s.remaining[line]))
+	if amount <= 0 {
+		return 0
+	}
+	s.remaining[line] = round2(s.remaining[line] - amount)
+	s.discounts[line] = append(s.discounts[line], domain.LineDiscount{PromotionID: p.UUID, Name: p.Name, Amount: amount})
+	return amount
+}
+
+// saved is what the applied promotions took off the items and the shipping fee
+func (s *state) saved() float64 {
+	total := 0.0
+	for _, a := range s.applied {
+		total += a.Amount
+	}
+	return round2(total)
+}
+
+func (s *state) result(cart domain.Cart, subtotal float64) *domain.Evaluation {
+	e := &domain.Evaluation{
+		Currency: cart.Currency, Subtotal: subtotal, ShippingFee: round2(cart.ShippingFee), ShippingDiscount: s.shippingDiscount,
+		Lines: make([]domain.LineResult, len(cart.Items)), Applied: s.applied,
+	}
+	for i, item := range cart.Items {
+		lineSubtotal := round2(float64(item.Quantity) * item.Price)
+		e.Lines[i] = domain.LineResult{
+			ProductID: item.ProductID, Quantity: item.Quantity, Price: item.Price, Subtotal: lineSubtotal,
+			DiscountAmount: round2(lineSubtotal - s.remaining[i]), Discounts: s.discounts[i],
+		}
+		e.DiscountAmount += e.Lines[i].DiscountAmount
+	}
+	e.DiscountAmount = round2(e.DiscountAmount)
+	e.Total = round2(e.Subtotal - e.DiscountAmount + e.ShippingFee - e.ShippingDiscount)
+	return e
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,510 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/promotion/domain"
+	"ecommerce-microservice-go/services/promotion/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PromotionRequest struct {
+	Name        string     `json:"name" binding:"required,max=120"`
+	Description string     `json:"description" binding:"max=1000"`
+	Type        string     `json:"type" binding:"required,oneof=buy_x_get_y category_discount free_shipping"`
+	Active      *bool      `json:"active"`
+	Priority    int        `json:"priority"`
+	Stacking    string     `json:"stacking" binding:"omitempty,oneof=stackable exclusive"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	Currency    string     `json:"currency" binding:"omitempty,len=3,alpha"`
+	MinSubtotal float64    `json:"minSubtotal" binding:"gte=0"`
+	Percent     float64    `json:"percent" binding:"gte=0,lte=100"`
+	BuyQuantity int        `json:"buyQuantity" binding:"gte=0"`
+	GetQuantity int        `json:"getQuantity" binding:"gte=0"`
+	ProductIDs  []int      `json:"productIds" binding:"max=500,dive,gt=0"`
+	CategoryIDs []int      `json:"categoryIds" binding:"max=500,dive,gt=0"`
+	Segments    []string   `json:"segments" binding:"max=50"`
+}
+
+type CartItemRequest struct {
+	ProductID int `json:"productId" binding:"required,gt=0"`
+	Quantity  int `json:"quantity" binding:"required,gt=0,max=10000"`
+}
+
+type EvaluateRequest struct {
+	Currency    string            `json:"currency" binding:"omitempty,len=3,alpha"`
+	ShippingFee float64           `json:"shippingFee" binding:"gte=0"`
+	Items       []CartItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+type InternalCartItemRequest struct {
+	ProductID int     `json:"productId" binding:"required,gt=0"`
+	Quantity  int     `json:"quantity" binding:"required,gt=0"`
+	Price     float64 `json:"price" binding:"gt=0"`
+}
+
+// InternalEvaluateRequest is a cart the order service prices itself, evaluated for the user
+type InternalEvaluateRequest struct {
+	UserID      int                       `json:"userId" binding:"gte=0"`
+	Currency    string                    `json:"currency" binding:"omitempty,len=3,alpha"`
+	ShippingFee float64                   `json:"shippingFee" binding:"gte=0"`
+	Items       []InternalCartItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type SegmentMembersRequest struct {
+	UserIDs []int `json:"userIds" binding:"required,min=1,max=1000,dive,gt=0"`
+}
+
+type ResponsePromotion struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Type        string     `json:"type"`
+	Active      bool       `json:"active"`
+	Priority    int        `json:"priority"`
+	Stacking    string     `json:"stacking"`
+	StartsAt    *time.Time `json:"startsAt,omitempty"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+	Currency    string     `json:"currency"`
+	MinSubtotal float64    `json:"minSubtotal"`
+	Percent     float64    `json:"percent"`
+	BuyQuantity int        `json:"buyQuantity,omitempty"`
+	GetQuantity int        `json:"getQuantity,omitempty"`
+	ProductIDs  []int      `json:"productIds"`
+	CategoryIDs []int      `json:"categoryIds"`
+	Segments    []string   `json:"segments"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+type ResponseLineDiscount struct {
+	PromotionID string  `json:"promotionId"`
+	Name        string  `json:"name"`
+	Amount      float64 `json:"amount"`
+}
+
+type ResponseLine struct {
+	ProductID      int                    `json:"productId"`
+	Quantity       int                    `json:"quantity"`
+	Price          float64                `json:"price"`
+	Subtotal       float64                `json:"subtotal"`
+	DiscountAmount float64                `json:"discountAmount"`
+	Discounts      []ResponseLineDiscount `json:"discounts"`
+}
+
+type ResponseApplied struct {
+	PromotionID string  `json:"promotionId"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Amount      float64 `json:"amount"`
+}
+
+type ResponseEvaluation struct {
+	Currency         string            `json:"currency"`
+	Subtotal         float64           `json:"subtotal"`
+	DiscountAmount   float64           `json:"discountAmount"`
+	ShippingFee      float64           `json:"shippingFee"`
+	ShippingDiscount float64           `json:"shippingDiscount"`
+	Total            float64           `json:"total"`
+	Items            []ResponseLine    `json:"items"`
+	Applied          []ResponseApplied `json:"applied"`
+}
+
+type ResponseSegment struct {
+	Name    string `json:"name"`
+	Members int64  `json:"members"`
+}
+
+type ResponseSegmentMembers struct {
+	Added int64 `json:"added"`
+}
+
+type Handler struct {
+	promotionUC usecase.IPromotionUseCase
+	Logger      *logger.Logger
+}
+
+func NewHandler(uc usecase.IPromotionUseCase, l *logger.Logger) *Handler {
+	return &Handler{promotionUC: uc, Logger: l}
+}
+
+var promotionSpec = pagination.Spec{
+	Sortable:    map[string]string{"priority": "priority", "createdAt": "created_at", "name": "name"},
+	Filterable:  map[string]string{"type": "type", "active": "active", "stacking": "stacking"},
+	DefaultSort: "-priority",
+}
+
+var memberSpec = pagination.Spec{
+	Sortable:    map[string]string{"userId": "user_id", "createdAt": "created_at"},
+	DefaultSort: "userId",
+}
+
+// Evaluate godoc
+// @Summary      Evaluate the cart's promotions
+// @Description  Applies the live promotions the caller may use to a cart priced from the catalog and returns the
+// @Description  discounted lines and totals. Stackable promotions apply one after another by priority; an exclusive
+// @Description  promotion replaces them when it saves more. Checkout applies the same promotions again, so the quote
+// @Description  changes when prices or promotions do.
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body EvaluateRequest true "Cart"
+// @Success      200 {object} ResponseEvaluation
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /promotions/evaluate [post]
+func (h *Handler) Evaluate(ctx *gin.Context) {
+	var req EvaluateRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	cart := domain.Cart{UserID: userID, Currency: req.Currency, ShippingFee: req.ShippingFee, Items: make([]domain.CartItem, len(req.Items))}
+	for i, it := range req.Items {
+		cart.Items[i] = domain.CartItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	h.evaluate(ctx, cart)
+}
+
+// EvaluateInternal applies the user's promotions to a cart priced by the caller, the order
+// service at checkout
+func (h *Handler) EvaluateInternal(ctx *gin.Context) {
+	var req InternalEvaluateRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	cart := domain.Cart{UserID: req.UserID, Currency: req.Currency, ShippingFee: req.ShippingFee, Items: make([]domain.CartItem, len(req.Items))}
+	for i, it := range req.Items {
+		cart.Items[i] = domain.CartItem{ProductID: it.ProductID, Quantity: it.Quantity, Price: it.Price}
+	}
+	h.evaluate(ctx, cart)
+}
+
+func (h *Handler) evaluate(ctx *gin.Context, cart domain.Cart) {
+	evaluation, err := h.promotionUC.Evaluate(ctx.Request.Context(), cart)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, evaluationToResponse(evaluation))
+}
+
+// GetPromotions godoc
+// @Summary      List promotions
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Produce      json
+// @Param        type     query string false "buy_x_get_y, category_discount or free_shipping"
+// @Param        active   query bool   false "Active or not"
+// @Param        stacking query string false "stackable or exclusive"
+// @Param        page     query int    false "Page number" default(1)
+// @Param        limit    query int    false "Page size" default(20)
+// @Param        sort     query string false "Comma separated fields, prefix - for descending (priority, createdAt, name)" default(-priority)
+// @Param        fields   query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponsePromotion]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Router       /promotions [get]
+func (h *Handler) GetPromotions(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, promotionSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	promotions, total, err := h.promotionUC.GetAll(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponsePromotion, len(*promotions))
+	for i := range *promotions {
+		res[i] = promotionToResponse(&(*promotions)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// GetPromotion godoc
+// @Summary      Get a promotion
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path  string true  "Promotion ID"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponsePromotion
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /promotions/{id} [get]
+func (h *Handler) GetPromotion(ctx *gin.Context) {
+	id, err := promotionID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := h.promotionUC.GetByUUID(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, promotionToResponse(p))
+}
+
+// NewPromotion godoc
+// @Summary      Create a promotion
+// @Description  category_discount takes percent off the items in categoryIds (or productIds). buy_x_get_y discounts
+// @Description  the cheapest getQuantity of every buyQuantity+getQuantity eligible units by percent (100 when
+// @Description  omitted). free_shipping takes percent (100 when omitted) off the shipping fee. minSubtotal, in
+// @Description  currency, and segments restrict who gets the promotion; startsAt and endsAt when.
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body PromotionRequest true "Promotion"
+// @Success      201 {object} ResponsePromotion
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Router       /promotions [post]
+func (h *Handler) NewPromotion(ctx *gin.Context) {
+	var req PromotionRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	created, err := h.promotionUC.Create(ctx.Request.Context(), promotionFromRequest(&req))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, promotionToResponse(created))
+}
+
+// UpdatePromotion godoc
+// @Summary      Replace a promotion
+// @Description  Takes the same body as creating one; carts evaluated afterwards see the change
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string           true "Promotion ID"
+// @Param        request body PromotionRequest true "Promotion"
+// @Success      200 {object} ResponsePromotion
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /promotions/{id} [put]
+func (h *Handler) UpdatePromotion(ctx *gin.Context) {
+	id, err := promotionID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req PromotionRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	p := promotionFromRequest(&req)
+	p.UUID = id
+	updated, err := h.promotionUC.Update(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, promotionToResponse(updated))
+}
+
+// DeletePromotion godoc
+// @Summary      Delete a promotion
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Param        id path string true "Promotion ID"
+// @Success      204
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /promotions/{id} [delete]
+func (h *Handler) DeletePromotion(ctx *gin.Context) {
+	id, err := promotionID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.promotionUC.Delete(ctx.Request.Context(), id); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetSegments godoc
+// @Summary      List customer segments
+// @Description  Segments exist while they have members
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Produce      json
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponseSegment
+// @Router       /promotions/segments [get]
+func (h *Handler) GetSegments(ctx *gin.Context) {
+	segments, err := h.promotionUC.GetSegments(ctx.Request.Context())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseSegment, len(*segments))
+	for i, s := range *segments {
+		res[i] = ResponseSegment{Name: s.Name, Members: s.Members}
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// GetSegmentMembers godoc
+// @Summary      List a segment's members
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Produce      json
+// @Param        segment path  string true  "Segment name"
+// @Param        page    query int    false "Page number" default(1)
+// @Param        limit   query int    false "Page size" default(20)
+// @Success      200 {object} pagination.Response[int]
+// @Router       /promotions/segments/{segment}/members [get]
+func (h *Handler) GetSegmentMembers(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, memberSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	members, total, err := h.promotionUC.GetSegmentMembers(ctx.Request.Context(), ctx.Param("segment"), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pagination.NewResponse(members, total, p))
+}
+
+// AddSegmentMembers godoc
+// @Summary      Add users to a segment
+// @Description  Creates the segment when it has no members yet; users already in it are skipped
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        segment path string                true "Segment name"
+// @Param        request body SegmentMembersRequest true "Users"
+// @Success      200 {object} ResponseSegmentMembers
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /promotions/segments/{segment}/members [post]
+func (h *Handler) AddSegmentMembers(ctx *gin.Context) {
+	var req SegmentMembersRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	added, err := h.promotionUC.AddSegmentMembers(ctx.Request.Context(), ctx.Param("segment"), req.UserIDs)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ResponseSegmentMembers{Added: added})
+}
+
+// RemoveSegmentMember godoc
+// @Summary      Remove a user from a segment
+// @Tags         Promotion
+// @Security     BearerAuth
+// @Param        segment path string true "Segment name"
+// @Param        userId  path int    true "User ID"
+// @Success      204
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /promotions/segments/{segment}/members/{userId} [delete]
+func (h *Handler) RemoveSegmentMember(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil || userID <= 0 {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid userId"), domainErrors.ValidationError))
+		return
+	}
+	if err := h.promotionUC.RemoveSegmentMember(ctx.Request.Context(), ctx.Param("segment"), userID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func promotionID(ctx *gin.Context) (string, error) {
+	id := ctx.Param("id")
+	if uuid.Validate(id) != nil {
+		return "", domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return strings.ToLower(id), nil
+}
+
+func userIDFromContext(ctx *gin.Context) (int, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return 0, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return int(userIDVal.(float64)), nil
+}
+
+// Mappers
+func promotionFromRequest(req *PromotionRequest) *domain.Promotion {
+	active := req.Active == nil || *req.Active
+	return &domain.Promotion{
+		Name: req.Name, Description: req.Description, Type: domain.PromotionType(req.Type), Active: active, Priority: req.Priority,
+		Stacking: domain.Stacking(req.Stacking), StartsAt: req.StartsAt, EndsAt: req.EndsAt, Currency: req.Currency,
+		MinSubtotal: req.MinSubtotal, Percent: req.Percent, BuyQuantity: req.BuyQuantity, GetQuantity: req.GetQuantity,
+		ProductIDs: req.ProductIDs, CategoryIDs: req.CategoryIDs, Segments: req.Segments,
+	}
+}
+
+func promotionToResponse(p *domain.Promotion) ResponsePromotion {
+	return ResponsePromotion{
+		ID: p.UUID, Name: p.Name, Description: p.Description, Type: string(p.Type), Active: p.Active, Priority: p.Priority,
+		Stacking: string(p.Stacking), StartsAt: p.StartsAt, EndsAt: p.EndsAt, Currency: p.Currency, MinSubtotal: p.MinSubtotal,
+		Percent: p.Percent, BuyQuantity: p.BuyQuantity, GetQuantity: p.GetQuantity,
+		ProductIDs: nonNil(p.ProductIDs), CategoryIDs: nonNil(p.CategoryIDs), Segments: nonNil(p.Segments),
+		CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func evaluationToResponse(e *domain.Evaluation) ResponseEvaluation {
+	res := ResponseEvaluation{
+		Currency: e.Currency, Subtotal: e.Subtotal, DiscountAmount: e.DiscountAmount, ShippingFee: e.ShippingFee,
+		ShippingDiscount: e.ShippingDiscount, Total: e.Total, Items: make([]ResponseLine, len(e.Lines)),
+		Applied: make([]ResponseApplied, len(e.Applied)),
+	}
+	for i, l := range e.Lines {
+		line := ResponseLine{
+			ProductID: l.ProductID, Quantity: l.Quantity, Price: l.Price, Subtotal: l.Subtotal, DiscountAmount: l.DiscountAmount,
+			Discounts: make([]ResponseLineDiscount, len(l.Discounts)),
+		}
+		for j, d := range l.Discounts {
+			line.Discounts[j] = ResponseLineDiscount{PromotionID: d.PromotionID, Name: d.Name, Amount: d.Amount}
+		}
+		res.Items[i] = line
+	}
+	for i, a := range e.Applied {
+		res.Applied[i] = ResponseApplied{PromotionID: a.PromotionID, Name: a.Name, Type: string(a.Type), Amount: a.Amount}
+	}
+	return res
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
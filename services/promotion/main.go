@@ -0,0 +1,162 @@
+// @title           Promotion Service API
+// @version         1.0.0
+// @description     Promotion microservice: buy X get Y, category discount and free shipping promotions targeted at customer segments, evaluated against carts and applied at checkout
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/promotion/client"
+	"ecommerce-microservice-go/services/promotion/domain"
+	"ecommerce-microservice-go/services/promotion/handler"
+	"ecommerce-microservice-go/services/promotion/migrations"
+	"ecommerce-microservice-go/services/promotion/repository"
+	"ecommerce-microservice-go/services/promotion/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/promotion/docs"
+)
+
+const service app.Service = "promotion"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Promotion Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, subscriber events.Subscriber, uc usecase.IPromotionUseCase) {
+			app.Subscribe(lc, subscriber, "promotion.segments", domain.EventTypes, uc.HandleEvent)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Promotion Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Promotion),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Subscriber, error) {
+				return app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			func(store cache.Store, log *logger.Logger) *cache.Cache {
+				return cache.New(store, "promotion", cfg.Cache.DefaultTTL, log)
+			},
+			func() client.ICatalogClient { return client.NewCatalogClient(cfg.CatalogServiceURL) },
+			repository.NewPromotionRepository,
+			usecase.NewPromotionUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, subscriber events.Subscriber,
+	migrator *psql.Migrator, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("promotion", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("promotion"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("promotion").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(subscriber)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		RegisterRoutes(v1)
+
+	v1.GET("/promotions/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	promotions := v1.Group("/promotions")
+	promotions.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RateLimit(cacheStore, "promotion", cfg.RateLimit, middleware.KeyByUser, log))
+	promotions.POST("/evaluate", h.Evaluate)
+	manage := promotions.Group("")
+	manage.Use(middleware.RequirePermissions(security.PermPromotionManage))
+	{
+		manage.GET("", h.GetPromotions)
+		manage.POST("", h.NewPromotion)
+		manage.GET("/segments", h.GetSegments)
+		manage.GET("/segments/:segment/members", h.GetSegmentMembers)
+		manage.POST("/segments/:segment/members", h.AddSegmentMembers)
+		manage.DELETE("/segments/:segment/members/:userId", h.RemoveSegmentMember)
+		manage.GET("/:id", h.GetPromotion)
+		manage.PUT("/:id", h.UpdatePromotion)
+		manage.DELETE("/:id", h.DeletePromotion)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.POST("/promotions/evaluate", h.EvaluateInternal)
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS segment_members;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,44 @@
+CREATE TABLE promotions (
+    id bigserial,
+    uuid uuid NOT NULL,
+    name text NOT NULL,
+    description text,
+    type text NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    priority bigint NOT NULL DEFAULT 0,
+    stacking text NOT NULL DEFAULT 'stackable',
+    starts_at timestamptz,
+    ends_at timestamptz,
+    currency text NOT NULL,
+    min_subtotal numeric(12,2) NOT NULL DEFAULT 0,
+    percent numeric(5,2) NOT NULL,
+    buy_quantity bigint NOT NULL DEFAULT 0,
+    get_quantity bigint NOT NULL DEFAULT 0,
+    product_ids jsonb NOT NULL DEFAULT '[]',
+    category_ids jsonb NOT NULL DEFAULT '[]',
+    segments jsonb NOT NULL DEFAULT '[]',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_promotions_type CHECK (type IN ('buy_x_get_y', 'category_discount', 'free_shipping')),
+    CONSTRAINT chk_promotions_stacking CHECK (stacking IN ('stackable', 'exclusive')),
+    CONSTRAINT chk_promotions_percent CHECK (percent > 0 AND percent <= 100),
+    CONSTRAINT chk_promotions_window CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE UNIQUE INDEX idx_promotions_uuid ON promotions (uuid);
+CREATE INDEX idx_promotions_deleted_at ON promotions (deleted_at);
+-- evaluation reads every active promotion in priority order
+CREATE INDEX idx_promotions_active ON promotions (priority DESC, id) WHERE active AND deleted_at IS NULL;
+
+CREATE TABLE segment_members (
+    segment text NOT NULL,
+    user_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (segment, user_id)
+);
+
+CREATE INDEX idx_segment_members_user_id ON segment_members (user_id);
@@ -0,0 +1,252 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/promotion/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Promotion struct {
+	ID          int        `gorm:"primaryKey"`
+	Name        string     `gorm:"column:name;not null"`
+	Description string     `gorm:"column:description"`
+	Type        string     `gorm:"column:type;not null"`
+	Active      bool       `gorm:"column:active;not null"`
+	Priority    int        `gorm:"column:priority;not null"`
+	Stacking    string     `gorm:"column:stacking;not null"`
+	StartsAt    *time.Time `gorm:"column:starts_at"`
+	EndsAt      *time.Time `gorm:"column:ends_at"`
+	Currency    string     `gorm:"column:currency;not null"`
+	MinSubtotal float64    `gorm:"column:min_subtotal;not null"`
+	Percent     float64    `gorm:"column:percent;not null"`
+	BuyQuantity int        `gorm:"column:buy_quantity;not null"`
+	GetQuantity int        `gorm:"column:get_quantity;not null"`
+	ProductIDs  []int      `gorm:"column:product_ids;type:jsonb;serializer:json;not null"`
+	CategoryIDs []int      `gorm:"column:category_ids;type:jsonb;serializer:json;not null"`
+	Segments    []string   `gorm:"column:segments;type:jsonb;serializer:json;not null"`
+	psql.BaseModel
+}
+
+func (Promotion) TableName() string { return "promotions" }
+
+type SegmentMember struct {
+	Segment   string    `gorm:"column:segment;primaryKey"`
+	UserID    int       `gorm:"column:user_id;primaryKey"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (SegmentMember) TableName() string { return "segment_members" }
+
+// updatableColumns are replaced by Update; the rest is set once on create
+var updatableColumns = []string{
+	"name", "description", "type", "active", "priority", "stacking", "starts_at", "ends_at", "currency", "min_subtotal",
+	"percent", "buy_quantity", "get_quantity", "product_ids", "category_ids", "segments", "updated_at", "updated_by",
+}
+
+type PromotionRepositoryInterface interface {
+	Create(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error)
+	GetByUUID(ctx context.Context, uuid string) (*domain.Promotion, error)
+	Search(ctx context.Context, p pagination.Params) (*[]domain.Promotion, int64, error)
+	// Update replaces every field of the promotion with the UUID but its IDs and timestamps
+	Update(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error)
+	Delete(ctx context.Context, uuid string) error
+	// GetLive returns the active promotions that have not ended at now, highest priority first
+	GetLive(ctx context.Context, now time.Time) (*[]domain.Promotion, error)
+
+	// SegmentsOf returns the names of the segments the user belongs to
+	SegmentsOf(ctx context.Context, userID int) ([]string, error)
+	GetSegments(ctx context.Context) (*[]domain.SegmentSummary, error)
+	GetSegmentMembers(ctx context.Context, segment string, p pagination.Params) ([]int, int64, error)
+	// AddSegmentMembers adds the users to the segment and returns how many were not members yet
+	AddSegmentMembers(ctx context.Context, segment string, userIDs []int) (int64, error)
+	RemoveSegmentMember(ctx context.Context, segment string, userID int) error
+	// RemoveUser takes the user out of every segment
+	RemoveUser(ctx context.Context, userID int) (int64, error)
+}
+
+type PromotionRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewPromotionRepository(db *gorm.DB, l *logger.Logger) PromotionRepositoryInterface {
+	return &PromotionRepository{DB: db, Logger: l}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, d *domain.Promotion) (*domain.Promotion, error) {
+	p := promotionFromDomain(d)
+	if err := psql.Conn(ctx, r.DB).Create(p).Error; err != nil {
+		r.Logger.Error("Error creating promotion", zap.String("name", d.Name), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return promotionToDomain(p), nil
+}
+
+func (r *PromotionRepository) GetByUUID(ctx context.Context, uuid string) (*domain.Promotion, error) {
+	var p Promotion
+	if err := psql.Conn(ctx, r.DB).Where("uuid = ?", uuid).First(&p).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return promotionToDomain(&p), nil
+}
+
+func (r *PromotionRepository) Search(ctx context.Context, p pagination.Params) (*[]domain.Promotion, int64, error) {
+	var promotions []Promotion
+	total, err := pagination.Find(psql.Conn(ctx, r.DB).Model(&Promotion{}), p, &promotions)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	return promotionsToDomain(promotions), total, nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, d *domain.Promotion) (*domain.Promotion, error) {
+	tx := psql.Conn(ctx, r.DB).Model(&Promotion{}).Where("uuid = ?", d.UUID).Select(updatableColumns).Updates(promotionFromDomain(d))
+	if tx.Error != nil {
+		r.Logger.Error("Error updating promotion", zap.String("uuid", d.UUID), zap.Error(tx.Error))
+		return nil, psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return r.GetByUUID(ctx, d.UUID)
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, uuid string) error {
+	tx := psql.Conn(ctx, r.DB).Where("uuid = ?", uuid).Delete(&Promotion{})
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
+
+func (r *PromotionRepository) GetLive(ctx context.Context, now time.Time) (*[]domain.Promotion, error) {
+	var promotions []Promotion
+	err := psql.Conn(ctx, r.DB).Where("active AND (ends_at IS NULL OR ends_at > ?)", now).
+		Order("priority DESC, id").Find(&promotions).Error
+	if err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return promotionsToDomain(promotions), nil
+}
+
+func (r *PromotionRepository) SegmentsOf(ctx context.Context, userID int) ([]string, error) {
+	var segments []string
+	if err := psql.Conn(ctx, r.DB).Model(&SegmentMember{}).Where("user_id = ?", userID).Order("segment").Pluck("segment", &segments).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return segments, nil
+}
+
+func (r *PromotionRepository) GetSegments(ctx context.Context) (*[]domain.SegmentSummary, error) {
+	var rows []struct {
+		Segment string
+		Members int64
+	}
+	err := psql.Conn(ctx, r.DB).Model(&SegmentMember{}).Select("segment, count(*) AS members").
+		Group("segment").Order("segment").Scan(&rows).Error
+	if err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.SegmentSummary, len(rows))
+	for i, row := range rows {
+		result[i] = domain.SegmentSummary{Name: row.Segment, Members: row.Members}
+	}
+	return &result, nil
+}
+
+func (r *PromotionRepository) GetSegmentMembers(ctx context.Context, segment string, p pagination.Params) ([]int, int64, error) {
+	var members []SegmentMember
+	total, err := pagination.Find(psql.Conn(ctx, r.DB).Model(&SegmentMember{}).Where("segment = ?", segment), p, &members)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	userIDs := make([]int, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	return userIDs, total, nil
+}
+
+func (r *PromotionRepository) AddSegmentMembers(ctx context.Context, segment string, userIDs []int) (int64, error) {
+	members := make([]SegmentMember, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = SegmentMember{Segment: segment, UserID: id}
+	}
+	tx := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&members)
+	if tx.Error != nil {
+		r.Logger.Error("Error adding segment members", zap.String("segment", segment), zap.Error(tx.Error))
+		return 0, psql.TranslateError(tx.Error)
+	}
+	return tx.RowsAffected, nil
+}
+
+func (r *PromotionRepository) RemoveSegmentMember(ctx context.Context, segment string, userID int) error {
+	tx := psql.Conn(ctx, r.DB).Where("segment = ? AND user_id = ?", segment, userID).Delete(&SegmentMember{})
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
+
+func (r *PromotionRepository) RemoveUser(ctx context.Context, userID int) (int64, error) {
+	tx := psql.Conn(ctx, r.DB).Where("user_id = ?", userID).Delete(&SegmentMember{})
+	if tx.Error != nil {
+		return 0, psql.TranslateError(tx.Error)
+	}
+	return tx.RowsAffected, nil
+}
+
+// Mappers
+func promotionFromDomain(d *domain.Promotion) *Promotion {
+	p := &Promotion{
+		ID: d.ID, Name: d.Name, Description: d.Description, Type: string(d.Type), Active: d.Active, Priority: d.Priority,
+		Stacking: string(d.Stacking), StartsAt: d.StartsAt, EndsAt: d.EndsAt, Currency: d.Currency, MinSubtotal: d.MinSubtotal,
+		Percent: d.Percent, BuyQuantity: d.BuyQuantity, GetQuantity: d.GetQuantity,
+		ProductIDs: d.ProductIDs, CategoryIDs: d.CategoryIDs, Segments: d.Segments,
+	}
+	// jsonb columns are not null; an empty list is stored as []
+	if p.ProductIDs == nil {
+		p.ProductIDs = []int{}
+	}
+	if p.CategoryIDs == nil {
+		p.CategoryIDs = []int{}
+	}
+	if p.Segments == nil {
+		p.Segments = []string{}
+	}
+	return p
+}
+
+func promotionToDomain(p *Promotion) *domain.Promotion {
+	return &domain.Promotion{
+		ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, Type: domain.PromotionType(p.Type), Active: p.Active,
+		Priority: p.Priority, Stacking: domain.Stacking(p.Stacking), StartsAt: p.StartsAt, EndsAt: p.EndsAt, Currency: p.Currency,
+		MinSubtotal: p.MinSubtotal, Percent: p.Percent, BuyQuantity: p.BuyQuantity, GetQuantity: p.GetQuantity,
+		ProductIDs: p.ProductIDs, CategoryIDs: p.CategoryIDs, Segments: p.Segments, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func promotionsToDomain(promotions []Promotion) *[]domain.Promotion {
+	result := make([]domain.Promotion, len(promotions))
+	for i := range promotions {
+		result[i] = *promotionToDomain(&promotions[i])
+	}
+	return &result
+}
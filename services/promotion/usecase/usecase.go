@@ -0,0 +1,268 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/services/promotion/client"
+	"ecommerce-microservice-go/services/promotion/domain"
+	"ecommerce-microservice-go/services/promotion/engine"
+	"ecommerce-microservice-go/services/promotion/repository"
+
+	"go.uber.org/zap"
+)
+
+type PromotionConfig struct {
+	// Currency is assumed for carts and promotion thresholds that do not name one
+	Currency string
+	// LiveCacheTTL bounds how long a replica may evaluate with promotions another replica changed
+	LiveCacheTTL time.Duration
+	// ProductCacheTTL bounds how stale the catalog prices and categories used in quotes may be
+	ProductCacheTTL time.Duration
+}
+
+type IPromotionUseCase interface {
+	Create(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error)
+	GetByUUID(ctx context.Context, uuid string) (*domain.Promotion, error)
+	GetAll(ctx context.Context, p pagination.Params) (*[]domain.Promotion, int64, error)
+	// Update replaces the promotion with the same UUID
+	Update(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error)
+	Delete(ctx context.Context, uuid string) error
+	// Evaluate applies the live promotions the cart's user may use. Categories always come from
+	// the catalog; items without a price are priced from it too.
+	Evaluate(ctx context.Context, cart domain.Cart) (*domain.Evaluation, error)
+
+	GetSegments(ctx context.Context) (*[]domain.SegmentSummary, error)
+	GetSegmentMembers(ctx context.Context, segment string, p pagination.Params) ([]int, int64, error)
+	AddSegmentMembers(ctx context.Context, segment string, userIDs []int) (int64, error)
+	RemoveSegmentMember(ctx context.Context, segment string, userID int) error
+	// HandleEvent removes deleted users from their segments
+	HandleEvent(ctx context.Context, event *events.Event) error
+}
+
+type PromotionUseCase struct {
+	repo    repository.PromotionRepositoryInterface
+	catalog client.ICatalogClient
+	cache   *cache.Cache
+	config  PromotionConfig
+	Logger  *logger.Logger
+}
+
+func NewPromotionUseCase(r repository.PromotionRepositoryInterface, catalog client.ICatalogClient, c *cache.Cache, cfg PromotionConfig,
+	l *logger.Logger) IPromotionUseCase {
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	return &PromotionUseCase{repo: r, catalog: catalog, cache: c, config: cfg, Logger: l}
+}
+
+func (s *PromotionUseCase) Create(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error) {
+	if err := s.validate(p); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateLive(ctx)
+	s.Logger.Info("Created promotion", zap.String("uuid", created.UUID), zap.String("type", string(created.Type)))
+	return created, nil
+}
+
+func (s *PromotionUseCase) GetByUUID(ctx context.Context, uuid string) (*domain.Promotion, error) {
+	return s.repo.GetByUUID(ctx, uuid)
+}
+
+func (s *PromotionUseCase) GetAll(ctx context.Context, p pagination.Params) (*[]domain.Promotion, int64, error) {
+	return s.repo.Search(ctx, p)
+}
+
+func (s *PromotionUseCase) Update(ctx context.Context, p *domain.Promotion) (*domain.Promotion, error) {
+	if err := s.validate(p); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.Update(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateLive(ctx)
+	s.Logger.Info("Updated promotion", zap.String("uuid", updated.UUID))
+	return updated, nil
+}
+
+func (s *PromotionUseCase) Delete(ctx context.Context, uuid string) error {
+	if err := s.repo.Delete(ctx, uuid); err != nil {
+		return err
+	}
+	s.invalidateLive(ctx)
+	s.Logger.Info("Deleted promotion", zap.String("uuid", uuid))
+	return nil
+}
+
+func (s *PromotionUseCase) Evaluate(ctx context.Context, cart domain.Cart) (*domain.Evaluation, error) {
+	cart.Currency = strings.ToUpper(cart.Currency)
+	if cart.Currency == "" {
+		cart.Currency = s.config.Currency
+	}
+	if cart.ShippingFee < 0 {
+		return nil, domainErrors.NewAppError(errors.New("shipping fee must not be negative"), domainErrors.ValidationError)
+	}
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		product, err := s.product(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		item.CategoryID = product.CategoryID
+		if item.Price <= 0 {
+			if !product.IsActive {
+				return nil, domainErrors.NewAppError(fmt.Errorf("product %d is not available", item.ProductID), domainErrors.ValidationError)
+			}
+			item.Price = product.Price
+		}
+	}
+
+	promotions, err := cache.GetOrLoad(ctx, s.cache, s.cache.Key(ctx, "live"), s.config.LiveCacheTTL, func(ctx context.Context) (*[]domain.Promotion, error) {
+		return s.repo.GetLive(ctx, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	var segments []string
+	if cart.UserID != 0 {
+		if segments, err = s.repo.SegmentsOf(ctx, cart.UserID); err != nil {
+			return nil, err
+		}
+	}
+	evaluation := engine.Evaluate(cart, *promotions, segments, time.Now())
+	s.Logger.Debug("Evaluated cart", zap.Int("userID", cart.UserID), zap.Int("items", len(cart.Items)),
+		zap.Int("applied", len(evaluation.Applied)), zap.Float64("discount", evaluation.DiscountAmount))
+	return evaluation, nil
+}
+
+func (s *PromotionUseCase) product(ctx context.Context, id int) (*domain.CatalogProduct, error) {
+	product, err := cache.GetOrLoad(ctx, s.cache, s.cache.Key(ctx, "product", strconv.Itoa(id)), s.config.ProductCacheTTL,
+		func(ctx context.Context) (*domain.CatalogProduct, error) {
+			return s.catalog.GetProduct(ctx, id)
+		})
+	if errors.Is(err, client.ErrProductNotFound) {
+		return nil, domainErrors.NewAppError(fmt.Errorf("product %d not found", id), domainErrors.ValidationError)
+	}
+	return product, err
+}
+
+func (s *PromotionUseCase) GetSegments(ctx context.Context) (*[]domain.SegmentSummary, error) {
+	return s.repo.GetSegments(ctx)
+}
+
+func (s *PromotionUseCase) GetSegmentMembers(ctx context.Context, segment string, p pagination.Params) ([]int, int64, error) {
+	return s.repo.GetSegmentMembers(ctx, segment, p)
+}
+
+func (s *PromotionUseCase) AddSegmentMembers(ctx context.Context, segment string, userIDs []int) (int64, error) {
+	if !domain.ValidSegment(segment) {
+		return 0, domainErrors.NewAppError(errors.New("segment names are lowercase letters, digits, - and _"), domainErrors.ValidationError)
+	}
+	added, err := s.repo.AddSegmentMembers(ctx, segment, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	s.Logger.Info("Added segment members", zap.String("segment", segment), zap.Int64("added", added))
+	return added, nil
+}
+
+func (s *PromotionUseCase) RemoveSegmentMember(ctx context.Context, segment string, userID int) error {
+	return s.repo.RemoveSegmentMember(ctx, segment, userID)
+}
+
+func (s *PromotionUseCase) HandleEvent(ctx context.Context, event *events.Event) error {
+	if event.Type != contracts.UserDeleted.EventType() {
+		return nil
+	}
+	u, err := contracts.UserDeleted.Decode(event)
+	if err != nil {
+		s.Logger.Error("Discarding user event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+		return nil
+	}
+	removed, err := s.repo.RemoveUser(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		s.Logger.Info("Removed deleted user from segments", zap.Int("userID", u.ID), zap.Int64("segments", removed))
+	}
+	return nil
+}
+
+// validate checks the rules of the promotion's type and fills in defaults
+func (s *PromotionUseCase) validate(p *domain.Promotion) error {
+	invalid := func(msg string) error {
+		return domainErrors.NewAppError(errors.New(msg), domainErrors.ValidationError)
+	}
+	if !p.Type.IsValid() {
+		return invalid(fmt.Sprintf("invalid promotion type %q", p.Type))
+	}
+	if p.Stacking == "" {
+		p.Stacking = domain.StackingStackable
+	}
+	if !p.Stacking.IsValid() {
+		return invalid(fmt.Sprintf("invalid stacking %q", p.Stacking))
+	}
+	// buy X get Y and free shipping give the whole thing away unless told otherwise
+	if p.Percent == 0 && p.Type != domain.TypeCategoryDiscount {
+		p.Percent = 100
+	}
+	if p.Percent <= 0 || p.Percent > 100 {
+		return invalid("percent must be above 0 and at most 100")
+	}
+	switch p.Type {
+	case domain.TypeCategoryDiscount:
+		if len(p.CategoryIDs) == 0 {
+			return invalid("a category discount needs categoryIds")
+		}
+	case domain.TypeBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return invalid("buy x get y needs buyQuantity and getQuantity of at least 1")
+		}
+	case domain.TypeFreeShipping:
+		if len(p.ProductIDs) > 0 || len(p.CategoryIDs) > 0 {
+			return invalid("free shipping applies to the whole cart and takes no productIds or categoryIds")
+		}
+	}
+	if p.Type != domain.TypeBuyXGetY {
+		p.BuyQuantity, p.GetQuantity = 0, 0
+	}
+	if p.MinSubtotal < 0 {
+		return invalid("minSubtotal must not be negative")
+	}
+	p.Currency = strings.ToUpper(p.Currency)
+	if p.Currency == "" {
+		p.Currency = s.config.Currency
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return invalid("endsAt must be after startsAt")
+	}
+	for _, segment := range p.Segments {
+		if !domain.ValidSegment(segment) {
+			return invalid(fmt.Sprintf("invalid segment %q", segment))
+		}
+	}
+	return nil
+}
+
+// invalidateLive drops the cached live promotions after a write
+func (s *PromotionUseCase) invalidateLive(ctx context.Context) {
+	if err := s.cache.Delete(ctx, s.cache.Key(ctx, "live")); err != nil {
+		s.Logger.Warn("Error invalidating live promotions", zap.Error(err))
+	}
+}
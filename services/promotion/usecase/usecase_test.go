@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/services/promotion/client"
+	clientmocks "ecommerce-microservice-go/services/promotion/client/mocks"
+	"ecommerce-microservice-go/services/promotion/domain"
+	"ecommerce-microservice-go/services/promotion/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+func newPromotionUseCase(t *testing.T) (IPromotionUseCase, *mocks.MockPromotionRepositoryInterface, *clientmocks.MockICatalogClient) {
+	ctrl := gomock.NewController(t)
+	repo, catalog := mocks.NewMockPromotionRepositoryInterface(ctrl), clientmocks.NewMockICatalogClient(ctrl)
+	c := cache.New(cache.NewMemoryStore(), "promotion", time.Minute, testLogger)
+	cfg := PromotionConfig{Currency: "idr", LiveCacheTTL: time.Minute, ProductCacheTTL: time.Minute}
+	return NewPromotionUseCase(repo, catalog, c, cfg, testLogger), repo, catalog
+}
+
+func TestPromotionUseCaseEvaluate(t *testing.T) {
+	shirts := domain.Promotion{UUID: "promo-1", Type: domain.TypeCategoryDiscount, Active: true, Stacking: domain.StackingStackable,
+		Percent: 10, CategoryIDs: []int{1}, Currency: "IDR"}
+	vip := shirts
+	vip.UUID, vip.Segments = "promo-2", []string{"vip"}
+	tee := &domain.CatalogProduct{ID: 7, Price: 100, CategoryID: 1, IsActive: true}
+
+	tests := []struct {
+		name         string
+		cart         domain.Cart
+		setup        func(repo *mocks.MockPromotionRepositoryInterface, catalog *clientmocks.MockICatalogClient)
+		wantDiscount float64
+		wantErr      error
+	}{
+		{
+			name: "price and category come from the catalog",
+			cart: domain.Cart{Items: []domain.CartItem{{ProductID: 7, Quantity: 2}}},
+			setup: func(repo *mocks.MockPromotionRepositoryInterface, catalog *clientmocks.MockICatalogClient) {
+				catalog.EXPECT().GetProduct(gomock.Any(), 7).Return(tee, nil)
+				repo.EXPECT().GetLive(gomock.Any(), gomock.Any()).Return(&[]domain.Promotion{shirts}, nil)
+			},
+			wantDiscount: 20,
+		},
+		{
+			name: "segment promotions apply to members only",
+			cart: domain.Cart{UserID: 9, Items: []domain.CartItem{{ProductID: 7, Quantity: 1, Price: 100}}},
+			setup: func(repo *mocks.MockPromotionRepositoryInterface, catalog *clientmocks.MockICatalogClient) {
+				catalog.EXPECT().GetProduct(gomock.Any(), 7).Return(tee, nil)
+				repo.EXPECT().GetLive(gomock.Any(), gomock.Any()).Return(&[]domain.Promotion{vip}, nil)
+				repo.EXPECT().SegmentsOf(gomock.Any(), 9).Return([]string{"newsletter"}, nil)
+			},
+		},
+		{
+			name: "unknown product",
+			cart: domain.Cart{Items: []domain.CartItem{{ProductID: 99, Quantity: 1}}},
+			setup: func(_ *mocks.MockPromotionRepositoryInterface, catalog *clientmocks.MockICatalogClient) {
+				catalog.EXPECT().GetProduct(gomock.Any(), 99).Return(nil, client.ErrProductNotFound)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "inactive product without a price",
+			cart: domain.Cart{Items: []domain.CartItem{{ProductID: 7, Quantity: 1}}},
+			setup: func(_ *mocks.MockPromotionRepositoryInterface, catalog *clientmocks.MockICatalogClient) {
+				catalog.EXPECT().GetProduct(gomock.Any(), 7).Return(&domain.CatalogProduct{ID: 7, Price: 100, CategoryID: 1}, nil)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name:    "negative shipping fee",
+			cart:    domain.Cart{ShippingFee: -1},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, catalog := newPromotionUseCase(t)
+			if tt.setup != nil {
+				tt.setup(repo, catalog)
+			}
+			got, err := uc.Evaluate(context.Background(), tt.cart)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.DiscountAmount != tt.wantDiscount {
+				t.Errorf("discount = %v, want %v", got.DiscountAmount, tt.wantDiscount)
+			}
+		})
+	}
+}
+
+func TestPromotionUseCaseCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		promotion   domain.Promotion
+		wantPercent float64
+		wantErr     error
+	}{
+		{
+			name:        "free shipping gives the whole fee away",
+			promotion:   domain.Promotion{Name: "Free shipping", Type: domain.TypeFreeShipping},
+			wantPercent: 100,
+		},
+		{
+			name:        "buy x get y",
+			promotion:   domain.Promotion{Name: "2+1", Type: domain.TypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1, Percent: 50},
+			wantPercent: 50,
+		},
+		{
+			name:      "category discount without categories",
+			promotion: domain.Promotion{Name: "Shirts", Type: domain.TypeCategoryDiscount, Percent: 10},
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "free shipping restricted to products",
+			promotion: domain.Promotion{Name: "Free shipping", Type: domain.TypeFreeShipping, ProductIDs: []int{7}},
+			wantErr:   domainErrors.ErrValidation,
+		},
+		{
+			name:      "invalid segment",
+			promotion: domain.Promotion{Name: "VIP", Type: domain.TypeFreeShipping, Segments: []string{"V I P"}},
+			wantErr:   domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, _ := newPromotionUseCase(t)
+			if tt.wantErr == nil {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, p *domain.Promotion) (*domain.Promotion, error) { return p, nil })
+			}
+			p := tt.promotion
+			got, err := uc.Create(context.Background(), &p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Percent != tt.wantPercent || got.Currency != "IDR" || got.Stacking != domain.StackingStackable) {
+				t.Errorf("promotion = %+v, want %v%% in IDR, stackable", got, tt.wantPercent)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+//go:generate mockgen -source=order_client.go -destination=mocks/order_client.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/review/domain"
+)
+
+type IOrderClient interface {
+	// FindPurchase returns the user's paid order containing the product, or nil when they
+	// have not bought it
+	FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error)
+}
+
+// OrderClient calls the order service internal API directly, bypassing the gateway
+type OrderClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewOrderClient(baseURL, internalToken string) IOrderClient {
+	return &OrderClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+func (c *OrderClient) FindPurchase(ctx context.Context, userID, productID int) (*domain.Purchase, error) {
+	query := url.Values{"userId": {strconv.Itoa(userID)}, "productId": {strconv.Itoa(productID)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/internal/purchases?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("order service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned %d checking purchase of product %d", res.StatusCode, productID)
+	}
+	var purchase domain.Purchase
+	if err := controllers.DecodeData(res.Body, &purchase); err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/review/usecase"
+)
+
+type Config struct {
+	Server          config.Server
+	Telemetry       otel.Config
+	Log             logger.Config
+	CORS            *config.Live[config.CORS]
+	Security        config.SecurityHeaders
+	Database        config.Database
+	JWT             config.JWT
+	IDs             config.IDs
+	Cache           cache.Config
+	RateLimit       *config.Live[config.RateLimit]
+	Outbox          events.OutboxRelayConfig
+	Review          usecase.ReviewConfig
+	OrderServiceURL string
+	InternalToken   string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8086", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "review", false),
+		IDs:       config.LoadIDs(src),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Outbox: events.OutboxRelayConfig{
+			Interval:  src.Duration("OUTBOX_RELAY_INTERVAL_MS", 2000, time.Millisecond),
+			BatchSize: src.Int("OUTBOX_RELAY_BATCH_SIZE", 100),
+		},
+		Review: usecase.ReviewConfig{
+			RequirePurchase: src.Bool("REVIEW_REQUIRE_PURCHASE", false),
+		},
+		OrderServiceURL: src.String("ORDER_SERVICE_URL", "http://localhost:9093"),
+		InternalToken:   src.String("INTERNAL_API_TOKEN", ""),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "review", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
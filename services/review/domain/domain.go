@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+type ReviewStatus string
+
+const (
+	// ReviewStatusPending reviews wait in the moderation queue and are only visible to their author
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+func (s ReviewStatus) IsValid() bool {
+	return s == ReviewStatusPending || s == ReviewStatusApproved || s == ReviewStatusRejected
+}
+
+type Review struct {
+	ID        int
+	UUID      string
+	ProductID int
+	UserID    int
+	Rating    int
+	Title     string
+	Body      string
+	Status    ReviewStatus
+	// VerifiedPurchase is set when the order service found a paid order with the product;
+	// OrderID is that order
+	VerifiedPurchase bool
+	OrderID          *int
+	HelpfulCount     int
+	// ModerationNote explains a rejection to the author
+	ModerationNote string
+	ModeratedBy    *int
+	ModeratedAt    *time.Time
+	Images         []ReviewImage
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ReviewImage struct {
+	ID       int
+	ReviewID int
+	URL      string
+	Position int
+}
+
+// ProductRating summarizes the approved reviews of a product. Distribution counts the
+// reviews per star, from 1 to 5.
+type ProductRating struct {
+	ProductID    int
+	Average      float64
+	Count        int
+	Distribution map[int]int
+}
+
+// ReviewUpdate holds the fields an author may change; nil fields are left alone
+type ReviewUpdate struct {
+	Rating *int
+	Title  *string
+	Body   *string
+	Images *[]string
+}
+
+// Purchase is the order in which the order service found the product
+type Purchase struct {
+	OrderID     int       `json:"orderId"`
+	OrderNumber string    `json:"orderNumber"`
+	Status      string    `json:"status"`
+	PurchasedAt time.Time `json:"purchasedAt"`
+}
+
+// Requester is the caller of a public endpoint; UserID is 0 for anonymous callers
+type Requester struct {
+	UserID      int
+	CanModerate bool
+}
+
+// CanSee reports whether the requester may read the review; unapproved reviews are only
+// shown to their author and moderators
+func (r Requester) CanSee(review *Review) bool {
+	return review.Status == ReviewStatusApproved || r.CanModerate || (r.UserID != 0 && r.UserID == review.UserID)
+}
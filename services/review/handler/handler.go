@@ -0,0 +1,465 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/review/domain"
+	"ecommerce-microservice-go/services/review/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseReview struct {
+	ID               int       `json:"id"`
+	UUID             string    `json:"uuid"`
+	ProductID        int       `json:"productId"`
+	UserID           int       `json:"userId"`
+	Rating           int       `json:"rating"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"`
+	Images           []string  `json:"images"`
+	Status           string    `json:"status"`
+	VerifiedPurchase bool      `json:"verifiedPurchase"`
+	HelpfulCount     int       `json:"helpfulCount"`
+	ModerationNote   string    `json:"moderationNote,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type ResponseRating struct {
+	ProductID int     `json:"productId"`
+	Average   float64 `json:"average"`
+	Count     int     `json:"count"`
+	// Distribution counts the approved reviews per star, keyed "1" to "5"
+	Distribution map[string]int `json:"distribution"`
+}
+
+type NewReviewRequest struct {
+	ProductID int    `json:"productId" binding:"required,gt=0"`
+	Rating    int    `json:"rating" binding:"required,min=1,max=5"`
+	Title     string `json:"title" binding:"max=150"`
+	Body      string `json:"body" binding:"max=5000"`
+	// Images are URLs of pictures the customer already uploaded
+	Images []string `json:"images" binding:"max=5,dive,url,max=2048"`
+}
+
+// UpdateReviewRequest changes only the fields it contains; images replaces all images
+type UpdateReviewRequest struct {
+	Rating *int      `json:"rating" binding:"omitempty,min=1,max=5"`
+	Title  *string   `json:"title" binding:"omitempty,max=150"`
+	Body   *string   `json:"body" binding:"omitempty,max=5000"`
+	Images *[]string `json:"images" binding:"omitempty,max=5,dive,url,max=2048"`
+}
+
+type VoteRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+var reviewListSpec = pagination.Spec{
+	Sortable:    map[string]string{"createdAt": "created_at", "rating": "rating", "helpful": "helpful_count"},
+	Filterable:  map[string]string{"rating": "rating", "verifiedPurchase": "verified_purchase"},
+	DefaultSort: "-createdAt",
+}
+
+// moderationListSpec shows the oldest reviews first, so the queue is worked in order
+var moderationListSpec = pagination.Spec{
+	Sortable:    map[string]string{"createdAt": "created_at", "rating": "rating"},
+	Filterable:  map[string]string{"productId": "product_id", "verifiedPurchase": "verified_purchase"},
+	DefaultSort: "createdAt",
+}
+
+type Handler struct {
+	reviewUC usecase.IReviewUseCase
+	ids      config.IDs
+	Logger   *logger.Logger
+}
+
+func NewHandler(r usecase.IReviewUseCase, ids config.IDs, l *logger.Logger) *Handler {
+	return &Handler{reviewUC: r, ids: ids, Logger: l}
+}
+
+// GetProductReviews godoc
+// @Summary      List the approved reviews of a product
+// @Tags         Review
+// @Param        productId        path  int    true  "Product ID"
+// @Param        page             query int    false "Page number" default(1)
+// @Param        limit            query int    false "Page size" default(20)
+// @Param        sort             query string false "Comma separated fields, prefix - for descending (createdAt, rating, helpful)"
+// @Param        rating           query int    false "Filter by star rating"
+// @Param        verifiedPurchase query bool   false "Filter by verified purchase"
+// @Param        fields           query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseReview]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /review/product/{productId} [get]
+func (h *Handler) GetProductReviews(ctx *gin.Context) {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := pagination.Parse(ctx, reviewListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	reviews, total, err := h.reviewUC.GetByProduct(ctx.Request.Context(), productID, p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPageBare(ctx, reviewsToResponse(reviews), total, p)
+}
+
+// GetProductRating godoc
+// @Summary      Get the rating summary of a product
+// @Tags         Review
+// @Param        productId path  int    true  "Product ID"
+// @Param        fields    query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseRating
+// @Router       /review/product/{productId}/rating [get]
+func (h *Handler) GetProductRating(ctx *gin.Context) {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	rating, err := h.reviewUC.GetRating(ctx.Request.Context(), productID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := ResponseRating{ProductID: rating.ProductID, Average: rating.Average, Count: rating.Count, Distribution: map[string]int{}}
+	for stars, count := range rating.Distribution {
+		res.Distribution[strconv.Itoa(stars)] = count
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// GetReview godoc
+// @Summary      Get review
+// @Tags         Review
+// @Description  Only approved reviews are public; authors see their others under /review/mine.
+// @Param        id     path  string true  "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseReview
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /review/{id} [get]
+func (h *Handler) GetReview(ctx *gin.Context) {
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r, err := h.reviewUC.GetByID(ctx.Request.Context(), id, domain.Requester{})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, reviewToResponse(r))
+}
+
+// GetMyReviews godoc
+// @Summary      List my reviews
+// @Tags         Review
+// @Security     BearerAuth
+// @Description  Includes reviews still waiting for moderation and rejected ones with the moderator's note.
+// @Param        page   query int    false "Page number" default(1)
+// @Param        limit  query int    false "Page size" default(20)
+// @Param        sort   query string false "Comma separated fields, prefix - for descending (createdAt, rating, helpful)"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseReview]
+// @Router       /review/mine [get]
+func (h *Handler) GetMyReviews(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := pagination.Parse(ctx, reviewListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	reviews, total, err := h.reviewUC.GetMine(ctx.Request.Context(), requester, p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPageBare(ctx, reviewsToResponse(reviews), total, p)
+}
+
+// NewReview godoc
+// @Summary      Review a product
+// @Tags         Review
+// @Security     BearerAuth
+// @Description  The review is published once a moderator approves it. It is marked as a verified purchase when the order service finds a paid order with the product.
+// @Param        request body NewReviewRequest true "Review"
+// @Success      200 {object} ResponseReview
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse
+// @Router       /review/ [post]
+func (h *Handler) NewReview(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req NewReviewRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	images := make([]domain.ReviewImage, len(req.Images))
+	for i, url := range req.Images {
+		images[i] = domain.ReviewImage{URL: url, Position: i}
+	}
+	r, err := h.reviewUC.Create(ctx.Request.Context(), &domain.Review{
+		ProductID: req.ProductID, Rating: req.Rating, Title: req.Title, Body: req.Body, Images: images,
+	}, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reviewToResponse(r))
+}
+
+// UpdateReview godoc
+// @Summary      Edit my review
+// @Tags         Review
+// @Security     BearerAuth
+// @Description  The edited review goes back to the moderation queue.
+// @Param        id path string true "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body UpdateReviewRequest true "Changed fields"
+// @Success      200 {object} ResponseReview
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /review/{id} [put]
+func (h *Handler) UpdateReview(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req UpdateReviewRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	r, err := h.reviewUC.Update(ctx.Request.Context(), id, domain.ReviewUpdate{
+		Rating: req.Rating, Title: req.Title, Body: req.Body, Images: req.Images,
+	}, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reviewToResponse(r))
+}
+
+// DeleteReview godoc
+// @Summary      Delete review
+// @Tags         Review
+// @Security     BearerAuth
+// @Description  Authors delete their own reviews; moderators delete any review.
+// @Param        id path string true "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.MessageResponse
+// @Router       /review/{id} [delete]
+func (h *Handler) DeleteReview(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.reviewUC.Delete(ctx.Request.Context(), id, requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+}
+
+// VoteReview godoc
+// @Summary      Vote on a review
+// @Tags         Review
+// @Security     BearerAuth
+// @Description  Voting again replaces the earlier vote. Authors cannot vote on their own reviews.
+// @Param        id path string true "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body VoteRequest true "Vote"
+// @Success      200 {object} ResponseReview
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /review/{id}/votes [put]
+func (h *Handler) VoteReview(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req VoteRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	r, err := h.reviewUC.Vote(ctx.Request.Context(), id, *req.Helpful, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reviewToResponse(r))
+}
+
+// DeleteReviewVote godoc
+// @Summary      Withdraw my vote on a review
+// @Tags         Review
+// @Security     BearerAuth
+// @Param        id path string true "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} ResponseReview
+// @Router       /review/{id}/votes [delete]
+func (h *Handler) DeleteReviewVote(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r, err := h.reviewUC.RemoveVote(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reviewToResponse(r))
+}
+
+// GetModerationQueue godoc
+// @Summary      List reviews for moderation
+// @Tags         Review Moderation
+// @Security     BearerAuth
+// @Description  Moderators only. Lists pending reviews, oldest first, unless another status is asked for.
+// @Param        status           query string false "pending, approved or rejected" default(pending)
+// @Param        page             query int    false "Page number" default(1)
+// @Param        limit            query int    false "Page size" default(20)
+// @Param        sort             query string false "Comma separated fields, prefix - for descending (createdAt, rating)"
+// @Param        productId        query int    false "Filter by product"
+// @Param        verifiedPurchase query bool   false "Filter by verified purchase"
+// @Param        fields           query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseReview]
+// @Router       /review/moderation [get]
+func (h *Handler) GetModerationQueue(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, moderationListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	reviews, total, err := h.reviewUC.GetModerationQueue(ctx.Request.Context(), domain.ReviewStatus(ctx.Query("status")), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPageBare(ctx, reviewsToResponse(reviews), total, p)
+}
+
+// ModerateReview godoc
+// @Summary      Approve or reject a review
+// @Tags         Review Moderation
+// @Security     BearerAuth
+// @Description  Moderators only. Changes to approved reviews update the product rating in the catalog.
+// @Param        id path string true "Review UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body ModerateReviewRequest true "Decision"
+// @Success      200 {object} ResponseReview
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /review/{id}/moderation [put]
+func (h *Handler) ModerateReview(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.reviewUC.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req ModerateReviewRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	r, err := h.reviewUC.Moderate(ctx.Request.Context(), id, domain.ReviewStatus(req.Status), req.Note, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reviewToResponse(r))
+}
+
+func productIDParam(ctx *gin.Context) (int, error) {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil || productID <= 0 {
+		return 0, domainErrors.NewAppError(errors.New("invalid productId"), domainErrors.ValidationError)
+	}
+	return productID, nil
+}
+
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:      int(userIDVal.(float64)),
+		CanModerate: middleware.HasPermission(ctx, security.PermReviewModerate),
+	}, nil
+}
+
+// Mappers
+func reviewToResponse(r *domain.Review) ResponseReview {
+	images := make([]string, len(r.Images))
+	for i, img := range r.Images {
+		images[i] = img.URL
+	}
+	return ResponseReview{
+		ID: r.ID, UUID: r.UUID, ProductID: r.ProductID, UserID: r.UserID, Rating: r.Rating, Title: r.Title, Body: r.Body,
+		Images: images, Status: string(r.Status), VerifiedPurchase: r.VerifiedPurchase, HelpfulCount: r.HelpfulCount,
+		ModerationNote: r.ModerationNote, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func reviewsToResponse(rs *[]domain.Review) []ResponseReview {
+	res := make([]ResponseReview, len(*rs))
+	for i := range *rs {
+		res[i] = reviewToResponse(&(*rs)[i])
+	}
+	return res
+}
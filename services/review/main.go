@@ -0,0 +1,163 @@
+// @title           Review Service API
+// @version         1.0.0
+// @description     Review microservice: product reviews, ratings, helpful votes and moderation
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/review/client"
+	"ecommerce-microservice-go/services/review/handler"
+	"ecommerce-microservice-go/services/review/migrations"
+	"ecommerce-microservice-go/services/review/repository"
+	"ecommerce-microservice-go/services/review/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/review/docs"
+)
+
+const service app.Service = "review"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Review Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		// committed rating updates go to the broker, where the catalog picks them up
+		fx.Invoke(func(lc fx.Lifecycle, relay *events.OutboxRelay) { app.Go(lc, relay.Run) }),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Review Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Outbox, cfg.Review, cfg.IDs),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			app.Publisher,
+			events.NewOutboxRelay,
+			psql.NewTxManager,
+			repository.NewReviewRepository,
+			func() client.IOrderClient { return client.NewOrderClient(cfg.OrderServiceURL, cfg.InternalToken) },
+			usecase.NewReviewUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, publisher events.Publisher,
+	migrator *psql.Migrator, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("review", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("review"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	probes := health.NewRegistry("review").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(publisher)).
+		Add("migrations", psql.MigrationStateCheck(migrator))
+	probes.RegisterRoutes(v1)
+
+	v1.GET("/review/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	apiRateLimit := middleware.RateLimit(cacheStore, "review", cfg.RateLimit, middleware.KeyByIP, log)
+
+	rev := v1.Group("/review")
+	rev.Use(apiRateLimit)
+	rev.GET("/product/:productId", h.GetProductReviews)
+	rev.GET("/product/:productId/rating", h.GetProductRating)
+	revAuth := rev.Group("")
+	revAuth.Use(middleware.AuthJWTMiddleware(cfg.JWT))
+	{
+		revAuth.GET("/mine", h.GetMyReviews)
+		revAuth.POST("/", h.NewReview)
+		revAuth.PUT("/:id", h.UpdateReview)
+		revAuth.DELETE("/:id", h.DeleteReview)
+		revAuth.PUT("/:id/votes", h.VoteReview)
+		revAuth.DELETE("/:id/votes", h.DeleteReviewVote)
+	}
+	moderation := rev.Group("")
+	moderation.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermReviewModerate))
+	{
+		moderation.GET("/moderation", h.GetModerationQueue)
+		moderation.PUT("/:id/moderation", h.ModerateReview)
+	}
+	rev.GET("/:id", h.GetReview)
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
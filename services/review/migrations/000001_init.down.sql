@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS outbox_messages;
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS review_images;
+DROP TABLE IF EXISTS reviews;
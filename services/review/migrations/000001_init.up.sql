@@ -0,0 +1,70 @@
+CREATE TABLE reviews (
+    id bigserial,
+    uuid uuid NOT NULL,
+    product_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    rating smallint NOT NULL,
+    title text,
+    body text,
+    status text NOT NULL DEFAULT 'pending',
+    verified_purchase boolean NOT NULL DEFAULT false,
+    order_id bigint,
+    helpful_count bigint NOT NULL DEFAULT 0,
+    moderation_note text,
+    moderated_by bigint,
+    moderated_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+CREATE UNIQUE INDEX idx_reviews_uuid ON reviews (uuid);
+CREATE INDEX idx_reviews_product_status ON reviews (product_id, status);
+CREATE INDEX idx_reviews_status_created_at ON reviews (status, created_at);
+CREATE INDEX idx_reviews_user_id ON reviews (user_id);
+CREATE INDEX idx_reviews_deleted_at ON reviews (deleted_at);
+-- a user reviews a product once; deleting the review lets them write a new one
+CREATE UNIQUE INDEX uni_reviews_user_product ON reviews (user_id, product_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE review_images (
+    id bigserial,
+    review_id bigint NOT NULL,
+    url text NOT NULL,
+    position bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_review_images_review FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_review_images_review_id ON review_images (review_id);
+
+CREATE TABLE review_votes (
+    id bigserial,
+    review_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    helpful boolean NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_review_votes_review FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX uni_review_votes_review_user ON review_votes (review_id, user_id);
+
+CREATE TABLE outbox_messages (
+    id bigserial,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    source text NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    published_at timestamptz,
+    attempts bigint DEFAULT 0,
+    last_error text,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages (published_at);
+CREATE UNIQUE INDEX idx_outbox_messages_event_id ON outbox_messages (event_id);
@@ -0,0 +1,4 @@
+ALTER TABLE outbox_messages DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_reviews_tenant_id;
+ALTER TABLE reviews DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,7 @@
+-- Reviews belong to the tenant of the product they review; reviews written before tenants
+-- existed belong to the default tenant. Rating events carry the tenant they were published in.
+
+ALTER TABLE reviews ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_reviews_tenant_id ON reviews (tenant_id);
+
+ALTER TABLE outbox_messages ADD COLUMN tenant_id text NOT NULL DEFAULT '';
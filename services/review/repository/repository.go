@@ -0,0 +1,294 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/review/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// --- Review GORM models ---
+type Review struct {
+	ID               int           `gorm:"primaryKey"`
+	ProductID        int           `gorm:"column:product_id;not null;index"`
+	UserID           int           `gorm:"column:user_id;not null;index"`
+	Rating           int           `gorm:"column:rating;not null"`
+	Title            string        `gorm:"column:title"`
+	Body             string        `gorm:"column:body"`
+	Status           string        `gorm:"column:status;not null;default:pending"`
+	VerifiedPurchase bool          `gorm:"column:verified_purchase;not null;default:false"`
+	OrderID          *int          `gorm:"column:order_id"`
+	HelpfulCount     int           `gorm:"column:helpful_count;not null;default:0"`
+	ModerationNote   string        `gorm:"column:moderation_note"`
+	ModeratedBy      *int          `gorm:"column:moderated_by"`
+	ModeratedAt      *time.Time    `gorm:"column:moderated_at"`
+	Images           []ReviewImage `gorm:"foreignKey:ReviewID"`
+	psql.BaseModel
+	psql.TenantModel
+}
+
+func (Review) TableName() string { return "reviews" }
+
+type ReviewImage struct {
+	ID        int       `gorm:"primaryKey"`
+	ReviewID  int       `gorm:"column:review_id;not null;index"`
+	URL       string    `gorm:"column:url;not null"`
+	Position  int       `gorm:"column:position;not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (ReviewImage) TableName() string { return "review_images" }
+
+type ReviewVote struct {
+	ID        int       `gorm:"primaryKey"`
+	ReviewID  int       `gorm:"column:review_id;not null"`
+	UserID    int       `gorm:"column:user_id;not null"`
+	Helpful   bool      `gorm:"column:helpful;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:mili"`
+}
+
+func (ReviewVote) TableName() string { return "review_votes" }
+
+// --- Review Repository ---
+
+type ReviewRepositoryInterface interface {
+	GetByID(ctx context.Context, id int) (*domain.Review, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	// GetByUserAndProduct returns the user's live review of the product, NotFound when there is none
+	GetByUserAndProduct(ctx context.Context, userID, productID int) (*domain.Review, error)
+	GetApprovedByProduct(ctx context.Context, productID int, p pagination.Params) (*[]domain.Review, int64, error)
+	GetByUserID(ctx context.Context, userID int, p pagination.Params) (*[]domain.Review, int64, error)
+	// GetByStatus lists reviews in the status; the pending ones are the moderation queue
+	GetByStatus(ctx context.Context, status domain.ReviewStatus, p pagination.Params) (*[]domain.Review, int64, error)
+	Create(ctx context.Context, r *domain.Review) (*domain.Review, error)
+	Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Review, error)
+	// ReplaceImages swaps the review's images for urls, kept in the given order
+	ReplaceImages(ctx context.Context, reviewID int, urls []string) error
+	Delete(ctx context.Context, id int) error
+	// SetVote records the user's vote, replacing an earlier one, and refreshes the helpful count
+	SetVote(ctx context.Context, reviewID, userID int, helpful bool) error
+	DeleteVote(ctx context.Context, reviewID, userID int) error
+	// LockProduct serializes rating updates of the product until the transaction ends
+	LockProduct(ctx context.Context, productID int) error
+	// GetRating aggregates the approved reviews of the product
+	GetRating(ctx context.Context, productID int) (*domain.ProductRating, error)
+	// SaveOutboxEvent stores the event for the relay; call it within the write transaction
+	SaveOutboxEvent(ctx context.Context, event *events.Event) error
+}
+
+type ReviewRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewReviewRepository(db *gorm.DB, l *logger.Logger) ReviewRepositoryInterface {
+	return &ReviewRepository{DB: db, Logger: l}
+}
+
+func (r *ReviewRepository) withImages(ctx context.Context) *gorm.DB {
+	return psql.Conn(ctx, r.DB).Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") })
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id int) (*domain.Review, error) {
+	return r.first(r.withImages(ctx).Where("id = ?", id))
+}
+
+func (r *ReviewRepository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	return psql.IDByUUID(psql.Conn(ctx, r.DB), &Review{}, publicID)
+}
+
+func (r *ReviewRepository) GetByUserAndProduct(ctx context.Context, userID, productID int) (*domain.Review, error) {
+	return r.first(r.withImages(ctx).Where("user_id = ? AND product_id = ?", userID, productID))
+}
+
+func (r *ReviewRepository) first(query *gorm.DB) (*domain.Review, error) {
+	var rv Review
+	if err := query.First(&rv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return reviewToDomain(&rv), nil
+}
+
+func (r *ReviewRepository) GetApprovedByProduct(ctx context.Context, productID int, p pagination.Params) (*[]domain.Review, int64, error) {
+	return r.find(r.withImages(ctx).Model(&Review{}).Where("product_id = ? AND status = ?", productID, domain.ReviewStatusApproved), p)
+}
+
+func (r *ReviewRepository) GetByUserID(ctx context.Context, userID int, p pagination.Params) (*[]domain.Review, int64, error) {
+	return r.find(r.withImages(ctx).Model(&Review{}).Where("user_id = ?", userID), p)
+}
+
+func (r *ReviewRepository) GetByStatus(ctx context.Context, status domain.ReviewStatus, p pagination.Params) (*[]domain.Review, int64, error) {
+	return r.find(r.withImages(ctx).Model(&Review{}).Where("status = ?", status), p)
+}
+
+func (r *ReviewRepository) find(query *gorm.DB, p pagination.Params) (*[]domain.Review, int64, error) {
+	var reviews []Review
+	total, err := pagination.Find(query, p, &reviews)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := make([]domain.Review, len(reviews))
+	for i := range reviews {
+		result[i] = *reviewToDomain(&reviews[i])
+	}
+	return &result, total, nil
+}
+
+func (r *ReviewRepository) Create(ctx context.Context, d *domain.Review) (*domain.Review, error) {
+	rv := Review{
+		ProductID: d.ProductID, UserID: d.UserID, Rating: d.Rating, Title: d.Title, Body: d.Body, Status: string(d.Status),
+		VerifiedPurchase: d.VerifiedPurchase, OrderID: d.OrderID, Images: imagesFromURLs(0, imageURLs(d.Images)),
+	}
+	if err := psql.Conn(ctx, r.DB).Create(&rv).Error; err != nil {
+		if psql.IsUniqueViolation(err) {
+			return nil, domainErrors.NewAppError(errors.New("you have already reviewed this product"), domainErrors.ResourceAlreadyExists).
+				WithCode(domainErrors.CodeReviewExists)
+		}
+		r.Logger.Error("Error creating review", zap.Int("productID", d.ProductID), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return reviewToDomain(&rv), nil
+}
+
+func (r *ReviewRepository) Update(ctx context.Context, id int, m map[string]interface{}) (*domain.Review, error) {
+	if err := psql.Conn(ctx, r.DB).Model(&Review{ID: id}).Updates(m).Error; err != nil {
+		r.Logger.Error("Error updating review", zap.Int("id", id), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *ReviewRepository) ReplaceImages(ctx context.Context, reviewID int, urls []string) error {
+	db := psql.Conn(ctx, r.DB)
+	if err := db.Where("review_id = ?", reviewID).Delete(&ReviewImage{}).Error; err != nil {
+		return psql.TranslateError(err)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	images := imagesFromURLs(reviewID, urls)
+	if err := db.Create(&images).Error; err != nil {
+		r.Logger.Error("Error saving review images", zap.Int("reviewID", reviewID), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&Review{}, id)
+	if tx.Error != nil {
+		return psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return nil
+}
+
+func (r *ReviewRepository) SetVote(ctx context.Context, reviewID, userID int, helpful bool) error {
+	vote := ReviewVote{ReviewID: reviewID, UserID: userID, Helpful: helpful}
+	err := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+	}).Create(&vote).Error
+	if err != nil {
+		r.Logger.Error("Error saving review vote", zap.Int("reviewID", reviewID), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return r.refreshHelpfulCount(ctx, reviewID)
+}
+
+func (r *ReviewRepository) DeleteVote(ctx context.Context, reviewID, userID int) error {
+	if err := psql.Conn(ctx, r.DB).Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&ReviewVote{}).Error; err != nil {
+		return psql.TranslateError(err)
+	}
+	return r.refreshHelpfulCount(ctx, reviewID)
+}
+
+// refreshHelpfulCount recounts rather than increments, so repeated or changed votes cannot drift
+func (r *ReviewRepository) refreshHelpfulCount(ctx context.Context, reviewID int) error {
+	err := psql.Conn(ctx, r.DB).Model(&Review{}).Where("id = ?", reviewID).
+		UpdateColumn("helpful_count", gorm.Expr("(SELECT count(*) FROM review_votes WHERE review_id = ? AND helpful)", reviewID)).Error
+	return psql.TranslateError(err)
+}
+
+func (r *ReviewRepository) LockProduct(ctx context.Context, productID int) error {
+	return psql.TranslateError(psql.Conn(ctx, r.DB).Exec("SELECT pg_advisory_xact_lock(?)", productID).Error)
+}
+
+func (r *ReviewRepository) GetRating(ctx context.Context, productID int) (*domain.ProductRating, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+	err := psql.Conn(ctx, r.DB).Model(&Review{}).Select("rating, count(*) AS count").
+		Where("product_id = ? AND status = ?", productID, domain.ReviewStatusApproved).Group("rating").Scan(&rows).Error
+	if err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	rating := &domain.ProductRating{ProductID: productID, Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	sum := 0
+	for _, row := range rows {
+		rating.Distribution[row.Rating] = row.Count
+		rating.Count += row.Count
+		sum += row.Rating * row.Count
+	}
+	if rating.Count > 0 {
+		rating.Average = math.Round(float64(sum)/float64(rating.Count)*100) / 100
+	}
+	return rating, nil
+}
+
+func (r *ReviewRepository) SaveOutboxEvent(ctx context.Context, event *events.Event) error {
+	if err := events.SaveToOutbox(psql.Conn(ctx, r.DB), event); err != nil {
+		r.Logger.Error("Error saving outbox event", zap.String("type", event.Type), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func imageURLs(images []domain.ReviewImage) []string {
+	urls := make([]string, len(images))
+	for i, img := range images {
+		urls[i] = img.URL
+	}
+	return urls
+}
+
+func imagesFromURLs(reviewID int, urls []string) []ReviewImage {
+	images := make([]ReviewImage, len(urls))
+	for i, url := range urls {
+		images[i] = ReviewImage{ReviewID: reviewID, URL: url, Position: i}
+	}
+	return images
+}
+
+func reviewToDomain(r *Review) *domain.Review {
+	images := make([]domain.ReviewImage, len(r.Images))
+	for i, img := range r.Images {
+		images[i] = domain.ReviewImage{ID: img.ID, ReviewID: img.ReviewID, URL: img.URL, Position: img.Position}
+	}
+	return &domain.Review{
+		ID: r.ID, UUID: r.UUID, ProductID: r.ProductID, UserID: r.UserID, Rating: r.Rating, Title: r.Title, Body: r.Body,
+		Status: domain.ReviewStatus(r.Status), VerifiedPurchase: r.VerifiedPurchase, OrderID: r.OrderID, HelpfulCount: r.HelpfulCount,
+		ModerationNote: r.ModerationNote, ModeratedBy: r.ModeratedBy, ModeratedAt: r.ModeratedAt, Images: images,
+		CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+	}
+}
@@ -0,0 +1,264 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/review/client"
+	"ecommerce-microservice-go/services/review/domain"
+	"ecommerce-microservice-go/services/review/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ReviewConfig struct {
+	// RequirePurchase rejects reviews from customers the order service has no paid order for;
+	// otherwise such reviews are accepted without the verified purchase badge
+	RequirePurchase bool
+}
+
+type IReviewUseCase interface {
+	GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Review, error)
+	// ResolveID returns the integer ID of the review with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	GetByProduct(ctx context.Context, productID int, p pagination.Params) (*[]domain.Review, int64, error)
+	GetRating(ctx context.Context, productID int) (*domain.ProductRating, error)
+	GetMine(ctx context.Context, requester domain.Requester, p pagination.Params) (*[]domain.Review, int64, error)
+	// Create adds a review to the moderation queue, marking it as a verified purchase when the
+	// order service confirms the customer bought the product
+	Create(ctx context.Context, r *domain.Review, requester domain.Requester) (*domain.Review, error)
+	// Update lets the author edit their review, which sends it back to the moderation queue
+	Update(ctx context.Context, id int, u domain.ReviewUpdate, requester domain.Requester) (*domain.Review, error)
+	Delete(ctx context.Context, id int, requester domain.Requester) error
+	// Vote records whether the requester found an approved review helpful
+	Vote(ctx context.Context, id int, helpful bool, requester domain.Requester) (*domain.Review, error)
+	RemoveVote(ctx context.Context, id int, requester domain.Requester) (*domain.Review, error)
+	// GetModerationQueue lists reviews in the status, pending ones by default
+	GetModerationQueue(ctx context.Context, status domain.ReviewStatus, p pagination.Params) (*[]domain.Review, int64, error)
+	// Moderate approves or rejects a review; note explains a rejection to the author
+	Moderate(ctx context.Context, id int, status domain.ReviewStatus, note string, requester domain.Requester) (*domain.Review, error)
+}
+
+type ReviewUseCase struct {
+	repo        repository.ReviewRepositoryInterface
+	orderClient client.IOrderClient
+	txManager   psql.TxManager
+	config      ReviewConfig
+	Logger      *logger.Logger
+}
+
+func NewReviewUseCase(r repository.ReviewRepositoryInterface, orderClient client.IOrderClient, txManager psql.TxManager,
+	cfg ReviewConfig, l *logger.Logger) IReviewUseCase {
+	return &ReviewUseCase{repo: r, orderClient: orderClient, txManager: txManager, config: cfg, Logger: l}
+}
+
+func (s *ReviewUseCase) GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Review, error) {
+	r, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanSee(r) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return r, nil
+}
+
+func (s *ReviewUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.repo.IDByUUID(ctx, uuid)
+}
+
+func (s *ReviewUseCase) GetByProduct(ctx context.Context, productID int, p pagination.Params) (*[]domain.Review, int64, error) {
+	return s.repo.GetApprovedByProduct(ctx, productID, p)
+}
+
+func (s *ReviewUseCase) GetRating(ctx context.Context, productID int) (*domain.ProductRating, error) {
+	return s.repo.GetRating(ctx, productID)
+}
+
+func (s *ReviewUseCase) GetMine(ctx context.Context, requester domain.Requester, p pagination.Params) (*[]domain.Review, int64, error) {
+	return s.repo.GetByUserID(ctx, requester.UserID, p)
+}
+
+func (s *ReviewUseCase) Create(ctx context.Context, r *domain.Review, requester domain.Requester) (*domain.Review, error) {
+	if _, err := s.repo.GetByUserAndProduct(ctx, requester.UserID, r.ProductID); err == nil {
+		return nil, domainErrors.NewAppError(errors.New("you have already reviewed this product"), domainErrors.ResourceAlreadyExists).
+			WithCode(domainErrors.CodeReviewExists)
+	} else if !errors.Is(err, domainErrors.ErrNotFound) {
+		return nil, err
+	}
+	purchase, err := s.orderClient.FindPurchase(ctx, requester.UserID, r.ProductID)
+	if err != nil {
+		s.Logger.Error("Error verifying purchase", zap.Int("userID", requester.UserID), zap.Int("productID", r.ProductID), zap.Error(err))
+		return nil, err
+	}
+	if purchase == nil && s.config.RequirePurchase {
+		return nil, domainErrors.NewAppError(errors.New("only customers who bought the product can review it"), domainErrors.NotAuthorized).
+			WithCode(domainErrors.CodePurchaseRequired)
+	}
+	r.UserID = requester.UserID
+	r.Status = domain.ReviewStatusPending
+	if purchase != nil {
+		r.VerifiedPurchase, r.OrderID = true, &purchase.OrderID
+	}
+	s.Logger.Info("Creating review", zap.Int("productID", r.ProductID), zap.Int("userID", r.UserID), zap.Bool("verifiedPurchase", r.VerifiedPurchase))
+	created, err := s.repo.Create(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, created.ID)
+}
+
+func (s *ReviewUseCase) Update(ctx context.Context, id int, u domain.ReviewUpdate, requester domain.Requester) (*domain.Review, error) {
+	var updated *domain.Review
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.UserID != requester.UserID {
+			return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		m := map[string]interface{}{"status": domain.ReviewStatusPending, "moderation_note": "", "moderated_by": nil, "moderated_at": nil}
+		if u.Rating != nil {
+			m["rating"] = *u.Rating
+		}
+		if u.Title != nil {
+			m["title"] = *u.Title
+		}
+		if u.Body != nil {
+			m["body"] = *u.Body
+		}
+		if u.Images != nil {
+			if err := s.repo.ReplaceImages(ctx, id, *u.Images); err != nil {
+				return err
+			}
+		}
+		if updated, err = s.repo.Update(ctx, id, m); err != nil {
+			return err
+		}
+		// the edited review leaves the published rating until it is approved again
+		if before.Status == domain.ReviewStatusApproved {
+			return s.publishRating(ctx, before.ProductID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Review updated", zap.Int("id", id))
+	return updated, nil
+}
+
+func (s *ReviewUseCase) Delete(ctx context.Context, id int, requester domain.Requester) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		r, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if r.UserID != requester.UserID && !requester.CanModerate {
+			return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		s.Logger.Info("Review deleted", zap.Int("id", id), zap.Int("actorID", requester.UserID))
+		if r.Status == domain.ReviewStatusApproved {
+			return s.publishRating(ctx, r.ProductID)
+		}
+		return nil
+	})
+}
+
+func (s *ReviewUseCase) Vote(ctx context.Context, id int, helpful bool, requester domain.Requester) (*domain.Review, error) {
+	r, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != domain.ReviewStatusApproved {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	if r.UserID == requester.UserID {
+		return nil, domainErrors.NewAppError(errors.New("you cannot vote on your own review"), domainErrors.ValidationError)
+	}
+	if err := s.repo.SetVote(ctx, id, requester.UserID, helpful); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ReviewUseCase) RemoveVote(ctx context.Context, id int, requester domain.Requester) (*domain.Review, error) {
+	if err := s.repo.DeleteVote(ctx, id, requester.UserID); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id, requester)
+}
+
+func (s *ReviewUseCase) GetModerationQueue(ctx context.Context, status domain.ReviewStatus, p pagination.Params) (*[]domain.Review, int64, error) {
+	if status == "" {
+		status = domain.ReviewStatusPending
+	}
+	if !status.IsValid() {
+		return nil, 0, domainErrors.NewAppError(errors.New("status must be pending, approved or rejected"), domainErrors.ValidationError)
+	}
+	return s.repo.GetByStatus(ctx, status, p)
+}
+
+func (s *ReviewUseCase) Moderate(ctx context.Context, id int, status domain.ReviewStatus, note string, requester domain.Requester) (*domain.Review, error) {
+	if status != domain.ReviewStatusApproved && status != domain.ReviewStatusRejected {
+		return nil, domainErrors.NewAppError(errors.New("status must be approved or rejected"), domainErrors.ValidationError)
+	}
+	var moderated *domain.Review
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		moderated, err = s.repo.Update(ctx, id, map[string]interface{}{
+			"status": status, "moderation_note": note, "moderated_by": requester.UserID, "moderated_at": time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if before.Status == status {
+			return nil
+		}
+		if before.Status == domain.ReviewStatusApproved || status == domain.ReviewStatusApproved {
+			return s.publishRating(ctx, before.ProductID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Review moderated", zap.Int("id", id), zap.String("status", string(status)), zap.Int("moderatorID", requester.UserID))
+	return moderated, nil
+}
+
+// publishRating queues the product's new aggregate for the catalog; call it within the
+// transaction that changed the approved reviews. The lock makes a concurrent change wait and
+// aggregate after this one commits, so the event with the latest UpdatedAt sees both.
+func (s *ReviewUseCase) publishRating(ctx context.Context, productID int) error {
+	if err := s.repo.LockProduct(ctx, productID); err != nil {
+		return err
+	}
+	rating, err := s.repo.GetRating(ctx, productID)
+	if err != nil {
+		return err
+	}
+	event, err := contracts.RatingUpdated.NewEvent(ctx, contracts.ProductRating{
+		ProductID: productID, Average: rating.Average, Count: rating.Count, UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveOutboxEvent(ctx, event)
+}
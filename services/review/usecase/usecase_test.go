@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	psqlmocks "ecommerce-microservice-go/pkg/psql/mocks"
+	clientmocks "ecommerce-microservice-go/services/review/client/mocks"
+	"ecommerce-microservice-go/services/review/domain"
+	"ecommerce-microservice-go/services/review/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	customer   = domain.Requester{UserID: 7}
+	moderator  = domain.Requester{UserID: 1, CanModerate: true}
+)
+
+// reviewMocks are the dependencies of ReviewUseCase
+type reviewMocks struct {
+	repo   *mocks.MockReviewRepositoryInterface
+	orders *clientmocks.MockIOrderClient
+}
+
+func newReviewUseCase(t *testing.T, cfg ReviewConfig) (IReviewUseCase, reviewMocks) {
+	ctrl := gomock.NewController(t)
+	m := reviewMocks{repo: mocks.NewMockReviewRepositoryInterface(ctrl), orders: clientmocks.NewMockIOrderClient(ctrl)}
+	tx := psqlmocks.NewMockTxManager(ctrl)
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, *gorm.DB) error) error { return fn(ctx, nil) }).AnyTimes()
+	return NewReviewUseCase(m.repo, m.orders, tx, cfg, testLogger), m
+}
+
+// publishesRating expects the product's rating to be queued for the catalog
+func publishesRating(m reviewMocks, productID int) {
+	m.repo.EXPECT().LockProduct(gomock.Any(), productID).Return(nil)
+	m.repo.EXPECT().GetRating(gomock.Any(), productID).Return(&domain.ProductRating{ProductID: productID, Average: 4, Count: 2}, nil)
+	m.repo.EXPECT().SaveOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *events.Event) error {
+		if e.Type != contracts.RatingUpdated.EventType() {
+			return errors.New("queued " + e.Type)
+		}
+		return nil
+	})
+}
+
+func TestReviewUseCaseCreate(t *testing.T) {
+	notFound := domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	tests := []struct {
+		name         string
+		config       ReviewConfig
+		setup        func(m reviewMocks)
+		wantVerified bool
+		wantErr      error
+	}{
+		{
+			name: "purchase is verified",
+			setup: func(m reviewMocks) {
+				m.repo.EXPECT().GetByUserAndProduct(gomock.Any(), 7, 3).Return(nil, notFound)
+				m.orders.EXPECT().FindPurchase(gomock.Any(), 7, 3).Return(&domain.Purchase{OrderID: 12}, nil)
+				m.repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *domain.Review) (*domain.Review, error) {
+					r.ID = 5
+					return r, nil
+				})
+				m.repo.EXPECT().GetByID(gomock.Any(), 5).DoAndReturn(func(_ context.Context, _ int) (*domain.Review, error) {
+					order := 12
+					return &domain.Review{ID: 5, UserID: 7, ProductID: 3, Status: domain.ReviewStatusPending, VerifiedPurchase: true, OrderID: &order}, nil
+				})
+			},
+			wantVerified: true,
+		},
+		{
+			name: "without a purchase when none is required",
+			setup: func(m reviewMocks) {
+				m.repo.EXPECT().GetByUserAndProduct(gomock.Any(), 7, 3).Return(nil, notFound)
+				m.orders.EXPECT().FindPurchase(gomock.Any(), 7, 3).Return(nil, nil)
+				m.repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *domain.Review) (*domain.Review, error) {
+					r.ID = 5
+					return r, nil
+				})
+				m.repo.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Review{ID: 5, UserID: 7, ProductID: 3, Status: domain.ReviewStatusPending}, nil)
+			},
+		},
+		{
+			name:   "without a purchase when one is required",
+			config: ReviewConfig{RequirePurchase: true},
+			setup: func(m reviewMocks) {
+				m.repo.EXPECT().GetByUserAndProduct(gomock.Any(), 7, 3).Return(nil, notFound)
+				m.orders.EXPECT().FindPurchase(gomock.Any(), 7, 3).Return(nil, nil)
+			},
+			wantErr: domainErrors.ErrNotAuthorized,
+		},
+		{
+			name: "second review of the product",
+			setup: func(m reviewMocks) {
+				m.repo.EXPECT().GetByUserAndProduct(gomock.Any(), 7, 3).Return(&domain.Review{ID: 4}, nil)
+			},
+			wantErr: domainErrors.ErrAlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newReviewUseCase(t, tt.config)
+			tt.setup(m)
+			got, err := uc.Create(context.Background(), &domain.Review{ProductID: 3, Rating: 5, UserID: 99, Status: domain.ReviewStatusApproved}, customer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.UserID != 7 || got.Status != domain.ReviewStatusPending || got.VerifiedPurchase != tt.wantVerified) {
+				t.Errorf("review = %+v, want a pending review by 7, verified %v", got, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestReviewUseCaseModerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  domain.ReviewStatus
+		status  domain.ReviewStatus
+		rating  bool
+		wantErr error
+	}{
+		{name: "approving publishes the rating", before: domain.ReviewStatusPending, status: domain.ReviewStatusApproved, rating: true},
+		{name: "rejecting an approved review publishes the rating", before: domain.ReviewStatusApproved, status: domain.ReviewStatusRejected, rating: true},
+		{name: "rejecting a pending review leaves the rating", before: domain.ReviewStatusPending, status: domain.ReviewStatusRejected},
+		{name: "approving again leaves the rating", before: domain.ReviewStatusApproved, status: domain.ReviewStatusApproved},
+		{name: "pending is not a moderation", before: domain.ReviewStatusApproved, status: domain.ReviewStatusPending, wantErr: domainErrors.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newReviewUseCase(t, ReviewConfig{})
+			if tt.wantErr == nil {
+				m.repo.EXPECT().GetByID(gomock.Any(), 5).Return(&domain.Review{ID: 5, ProductID: 3, Status: tt.before}, nil)
+				m.repo.EXPECT().Update(gomock.Any(), 5, gomock.Any()).Return(&domain.Review{ID: 5, ProductID: 3, Status: tt.status}, nil)
+			}
+			if tt.rating {
+				publishesRating(m, 3)
+			}
+			if _, err := uc.Moderate(context.Background(), 5, tt.status, "", moderator); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReviewUseCaseDelete(t *testing.T) {
+	tests := []struct {
+		name      string
+		requester domain.Requester
+		review    domain.Review
+		rating    bool
+		wantErr   error
+	}{
+		{name: "author deletes a pending review", requester: customer, review: domain.Review{ID: 5, UserID: 7, ProductID: 3, Status: domain.ReviewStatusPending}},
+		{name: "moderator deletes an approved review", requester: moderator, review: domain.Review{ID: 5, UserID: 7, ProductID: 3, Status: domain.ReviewStatusApproved}, rating: true},
+		{name: "another customer's review is not found", requester: domain.Requester{UserID: 8}, review: domain.Review{ID: 5, UserID: 7, ProductID: 3},
+			wantErr: domainErrors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newReviewUseCase(t, ReviewConfig{})
+			m.repo.EXPECT().GetByID(gomock.Any(), 5).Return(&tt.review, nil)
+			if tt.wantErr == nil {
+				m.repo.EXPECT().Delete(gomock.Any(), 5).Return(nil)
+			}
+			if tt.rating {
+				publishesRating(m, 3)
+			}
+			if err := uc.Delete(context.Background(), 5, tt.requester); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReviewUseCaseVote(t *testing.T) {
+	tests := []struct {
+		name    string
+		review  domain.Review
+		wantErr error
+	}{
+		{name: "approved review", review: domain.Review{ID: 5, UserID: 8, Status: domain.ReviewStatusApproved}},
+		{name: "own review", review: domain.Review{ID: 5, UserID: 7, Status: domain.ReviewStatusApproved}, wantErr: domainErrors.ErrValidation},
+		{name: "pending review is not found", review: domain.Review{ID: 5, UserID: 8, Status: domain.ReviewStatusPending}, wantErr: domainErrors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newReviewUseCase(t, ReviewConfig{})
+			m.repo.EXPECT().GetByID(gomock.Any(), 5).Return(&tt.review, nil)
+			if tt.wantErr == nil {
+				m.repo.EXPECT().SetVote(gomock.Any(), 5, 7, true).Return(nil)
+				m.repo.EXPECT().GetByID(gomock.Any(), 5).Return(&tt.review, nil)
+			}
+			if _, err := uc.Vote(context.Background(), 5, true, customer); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
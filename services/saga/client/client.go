@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+)
+
+// internalAPI calls another service's internal API directly, bypassing the gateway
+type internalAPI struct {
+	service       string
+	baseURL       string
+	internalToken string
+	http          *http.Client
+	// enveloped services answer with a controllers.Envelope
+	enveloped bool
+}
+
+func newInternalAPI(service, baseURL, internalToken string, timeout time.Duration) internalAPI {
+	return internalAPI{
+		service: service, baseURL: baseURL, internalToken: internalToken,
+		http: &http.Client{Timeout: timeout, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))},
+	}
+}
+
+// withEnvelope returns a copy of a reading the answers' data out of their envelope
+func (a internalAPI) withEnvelope() internalAPI {
+	a.enveloped = true
+	return a
+}
+
+// do sends the request and decodes a 2xx answer into out. A 4xx answer becomes an AppError
+// carrying the participant's message, which the saga treats as a final refusal; network
+// errors, 5xx answers and 408, 409 and 429 stay plain errors and are retried.
+func (a internalAPI) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Internal-Token", a.internalToken)
+	res, err := a.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s service request failed: %w", a.service, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		if out == nil || res.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		if a.enveloped {
+			return controllers.DecodeData(res.Body, out)
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	}
+	payload := controllers.DecodeError(res.Body)
+	message := payload.Message
+	if message == "" {
+		message = http.StatusText(res.StatusCode)
+	}
+	switch res.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return fmt.Errorf("%s service returned %d for %s: %s", a.service, res.StatusCode, path, message)
+	case http.StatusNotFound:
+		return domainErrors.NewAppError(fmt.Errorf("%s service: %s", a.service, message), domainErrors.NotFound)
+	case http.StatusPaymentRequired:
+		return domainErrors.NewAppError(fmt.Errorf("%s service: %s", a.service, message), domainErrors.PaymentDeclined)
+	}
+	if res.StatusCode < 500 {
+		return domainErrors.NewAppError(fmt.Errorf("%s service: %s", a.service, message), domainErrors.ValidationError)
+	}
+	return fmt.Errorf("%s service returned %d for %s: %s", a.service, res.StatusCode, path, message)
+}
+
+// isNotFound reports whether the participant answered 404
+func isNotFound(err error) bool {
+	var appErr *domainErrors.AppError
+	return errors.As(err, &appErr) && appErr.Type == domainErrors.NotFound
+}
@@ -0,0 +1,87 @@
+package client
+
+//go:generate mockgen -source=order_client.go -destination=mocks/order_client.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/services/saga/domain"
+)
+
+type IOrderClient interface {
+	// ReserveStock holds the checkout's items under reference and returns the reservation ID
+	ReserveStock(ctx context.Context, reference string, input *domain.CheckoutInput) (string, error)
+	// CancelReservation returns the stock; a reservation that no longer exists is fine
+	CancelReservation(ctx context.Context, reference string) error
+	// CreateOrder creates the checkout's order; repeating the reference returns the same order
+	CreateOrder(ctx context.Context, reference, reservationID string, input *domain.CheckoutInput) (*domain.CheckoutOrder, error)
+	// CaptureOrder marks the order paid with the card charge
+	CaptureOrder(ctx context.Context, orderID int, chargeID string) error
+	// CancelOrder cancels the order, releasing its stock and store credit; it is a no-op when cancelled
+	CancelOrder(ctx context.Context, orderID int, reason, note string) error
+	AddOrderNote(ctx context.Context, orderID int, body string) error
+	// CancelUserOrders cancels the user's unpaid orders and returns how many were cancelled
+	CancelUserOrders(ctx context.Context, userID int) (int, error)
+}
+
+type OrderClient struct {
+	api internalAPI
+}
+
+func NewOrderClient(baseURL, internalToken string, timeout time.Duration) IOrderClient {
+	return &OrderClient{api: newInternalAPI("order", baseURL, internalToken, timeout).withEnvelope()}
+}
+
+func (c *OrderClient) ReserveStock(ctx context.Context, reference string, input *domain.CheckoutInput) (string, error) {
+	body := map[string]interface{}{"reference": reference, "userId": input.UserID, "role": input.Role, "items": input.Items}
+	var res struct {
+		ReservationID string `json:"reservationId"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, "/v1/internal/checkout/reservations", body, &res); err != nil {
+		return "", err
+	}
+	return res.ReservationID, nil
+}
+
+func (c *OrderClient) CancelReservation(ctx context.Context, reference string) error {
+	return c.api.do(ctx, http.MethodPost, "/v1/internal/checkout/reservations/"+url.PathEscape(reference)+"/cancel", nil, nil)
+}
+
+func (c *OrderClient) CreateOrder(ctx context.Context, reference, reservationID string, input *domain.CheckoutInput) (*domain.CheckoutOrder, error) {
+	body := map[string]interface{}{
+		"reference": reference, "userId": input.UserID, "role": input.Role, "reservationId": reservationID,
+		"clientIp": input.ClientIP, "order": json.RawMessage(input.Order),
+	}
+	var order domain.CheckoutOrder
+	if err := c.api.do(ctx, http.MethodPost, "/v1/internal/checkout/orders", body, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (c *OrderClient) CaptureOrder(ctx context.Context, orderID int, chargeID string) error {
+	return c.api.do(ctx, http.MethodPost, "/v1/internal/orders/"+strconv.Itoa(orderID)+"/capture", map[string]string{"chargeId": chargeID}, nil)
+}
+
+func (c *OrderClient) CancelOrder(ctx context.Context, orderID int, reason, note string) error {
+	return c.api.do(ctx, http.MethodPost, "/v1/internal/orders/"+strconv.Itoa(orderID)+"/cancel", map[string]string{"reason": reason, "note": note}, nil)
+}
+
+func (c *OrderClient) AddOrderNote(ctx context.Context, orderID int, body string) error {
+	return c.api.do(ctx, http.MethodPost, "/v1/internal/orders/"+strconv.Itoa(orderID)+"/comments", map[string]string{"body": body}, nil)
+}
+
+func (c *OrderClient) CancelUserOrders(ctx context.Context, userID int) (int, error) {
+	var res struct {
+		Cancelled int `json:"cancelled"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, "/v1/internal/users/"+strconv.Itoa(userID)+"/orders/cancel", nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Cancelled, nil
+}
@@ -0,0 +1,46 @@
+package client
+
+//go:generate mockgen -source=payment_client.go -destination=mocks/payment_client.go -package=mocks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"ecommerce-microservice-go/services/saga/domain"
+)
+
+type IPaymentClient interface {
+	// Charge charges the order; repeating the reference returns the original payment. A
+	// declined card answers with a PaymentDeclined error or a failed payment.
+	Charge(ctx context.Context, charge domain.Charge) (*domain.Payment, error)
+	// Refund refunds amount of the payment, or what is left of it when amount is 0; repeating
+	// the reference returns the original refund
+	Refund(ctx context.Context, paymentID, reference string, amount float64, reason string) (*domain.Refund, error)
+}
+
+type PaymentClient struct {
+	api internalAPI
+}
+
+func NewPaymentClient(baseURL, internalToken string, timeout time.Duration) IPaymentClient {
+	return &PaymentClient{api: newInternalAPI("payment", baseURL, internalToken, timeout)}
+}
+
+func (c *PaymentClient) Charge(ctx context.Context, charge domain.Charge) (*domain.Payment, error) {
+	var p domain.Payment
+	if err := c.api.do(ctx, http.MethodPost, "/v1/internal/payments/charges", charge, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *PaymentClient) Refund(ctx context.Context, paymentID, reference string, amount float64, reason string) (*domain.Refund, error) {
+	body := map[string]interface{}{"reference": reference, "amount": amount, "reason": reason}
+	var r domain.Refund
+	if err := c.api.do(ctx, http.MethodPost, "/v1/internal/payments/"+url.PathEscape(paymentID)+"/refunds", body, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,30 @@
+package client
+
+//go:generate mockgen -source=user_client.go -destination=mocks/user_client.go -package=mocks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type IUserClient interface {
+	// DeleteUser deletes the account; an account that is already gone is fine
+	DeleteUser(ctx context.Context, userID int) error
+}
+
+type UserClient struct {
+	api internalAPI
+}
+
+func NewUserClient(baseURL, internalToken string, timeout time.Duration) IUserClient {
+	return &UserClient{api: newInternalAPI("user", baseURL, internalToken, timeout).withEnvelope()}
+}
+
+func (c *UserClient) DeleteUser(ctx context.Context, userID int) error {
+	if err := c.api.do(ctx, http.MethodDelete, "/v1/internal/users/"+strconv.Itoa(userID), nil, nil); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/saga/usecase"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Saga      usecase.SagaConfig
+	// ResumeInterval is how often sagas waiting to retry a step are resumed
+	ResumeInterval    time.Duration
+	OrderServiceURL   string
+	PaymentServiceURL string
+	UserServiceURL    string
+	InternalToken     string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		// start requests run the saga before answering, so they get more time than usual
+		Server:    config.LoadServer(src, "8089", 30*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "saga", false),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Saga: usecase.SagaConfig{
+			StepTimeout: src.Duration("SAGA_STEP_TIMEOUT_SECONDS", 120, time.Second),
+			CallTimeout: src.Duration("SAGA_CALL_TIMEOUT_SECONDS", 5, time.Second),
+			MaxAttempts: src.Int("SAGA_MAX_ATTEMPTS", 10),
+			BaseBackoff: src.Duration("SAGA_BASE_BACKOFF_SECONDS", 2, time.Second),
+			MaxBackoff:  src.Duration("SAGA_MAX_BACKOFF_SECONDS", 300, time.Second),
+			BatchSize:   src.Int("SAGA_BATCH_SIZE", 20),
+			StuckAfter:  src.Duration("SAGA_STUCK_AFTER_MINUTES", 30, time.Minute),
+		},
+		ResumeInterval:    src.Duration("SAGA_RESUME_INTERVAL_SECONDS", 5, time.Second),
+		OrderServiceURL:   src.String("ORDER_SERVICE_URL", "http://localhost:9093"),
+		PaymentServiceURL: src.String("PAYMENT_SERVICE_URL", "http://localhost:9095"),
+		UserServiceURL:    src.String("USER_SERVICE_URL", "http://localhost:9091"),
+		InternalToken:     src.String("INTERNAL_API_TOKEN", ""),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "saga", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
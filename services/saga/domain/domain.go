@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type SagaType string
+
+const (
+	SagaCheckout        SagaType = "checkout"
+	SagaRefund          SagaType = "refund"
+	SagaAccountDeletion SagaType = "account_deletion"
+)
+
+type SagaStatus string
+
+const (
+	// SagaRunning sagas execute their steps in order
+	SagaRunning SagaStatus = "running"
+	// SagaCompensating sagas undo their completed steps in reverse order after a step failed
+	SagaCompensating SagaStatus = "compensating"
+	SagaCompleted    SagaStatus = "completed"
+	// SagaCompensated sagas failed and were rolled back; LastError holds the cause
+	SagaCompensated SagaStatus = "compensated"
+	// SagaFailed sagas could neither finish nor be rolled back and wait for an operator retry
+	SagaFailed SagaStatus = "failed"
+)
+
+// IsFinal reports whether the saga will not execute any further step on its own
+func (s SagaStatus) IsFinal() bool {
+	return s == SagaCompleted || s == SagaCompensated || s == SagaFailed
+}
+
+// Requester is the caller of a public endpoint
+type Requester struct {
+	UserID int
+	Role   string
+	// CanOperate sees every saga and may retry failed ones
+	CanOperate bool
+	// CanDeleteAccounts may delete other users' accounts
+	CanDeleteAccounts bool
+}
+
+// CanAccess reports whether the requester may see the saga
+func (r Requester) CanAccess(s *Saga) bool {
+	return r.CanOperate || s.StartedBy == r.UserID
+}
+
+// Saga is one persisted run of a workflow. StepIndex is the step being executed while
+// running, or being undone while compensating.
+type Saga struct {
+	ID        int
+	Type      SagaType
+	Reference string
+	Status    SagaStatus
+	// FailedDuring is the status a failed saga resumes in when an operator retries it
+	FailedDuring SagaStatus
+	StepIndex    int
+	// FailedStep is the step whose failure started the compensation
+	FailedStep string
+	// PivotReached is set once a step that cannot be undone completed; later failures are
+	// retried instead of compensated
+	PivotReached  bool
+	Input         json.RawMessage
+	State         SagaState
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	StepDeadline  *time.Time
+	LockedUntil   *time.Time
+	UserID        int
+	StartedBy     int
+	// Tenant is the store the saga was started in; its calls to participants act in it
+	Tenant     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+	Steps      []StepLog
+}
+
+// SagaState collects the identifiers steps hand to later steps and to their compensations
+type SagaState struct {
+	ReservationID   string  `json:"reservationId,omitempty"`
+	OrderID         int     `json:"orderId,omitempty"`
+	OrderNumber     string  `json:"orderNumber,omitempty"`
+	AmountDue       float64 `json:"amountDue,omitempty"`
+	Currency        string  `json:"currency,omitempty"`
+	PaymentID       string  `json:"paymentId,omitempty"`
+	RefundID        string  `json:"refundId,omitempty"`
+	RefundedAmount  float64 `json:"refundedAmount,omitempty"`
+	CancelledOrders int     `json:"cancelledOrders,omitempty"`
+}
+
+type StepPhase string
+
+const (
+	PhaseAction       StepPhase = "action"
+	PhaseCompensation StepPhase = "compensation"
+)
+
+// StepLog records one call made for a step
+type StepLog struct {
+	ID         int
+	SagaID     int
+	Step       string
+	Phase      StepPhase
+	Attempt    int
+	Succeeded  bool
+	Error      string
+	DurationMs int64
+	CreatedAt  time.Time
+}
+
+type CheckoutItem struct {
+	ProductID int `json:"productId"`
+	Quantity  int `json:"quantity"`
+}
+
+// CheckoutInput is the customer's order as sent to the order service, plus who placed it
+type CheckoutInput struct {
+	UserID   int             `json:"userId"`
+	Role     string          `json:"role"`
+	ClientIP string          `json:"clientIp"`
+	Items    []CheckoutItem  `json:"items"`
+	Order    json.RawMessage `json:"order"`
+}
+
+type RefundInput struct {
+	OrderID   int     `json:"orderId"`
+	PaymentID string  `json:"paymentId"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+}
+
+type AccountDeletionInput struct {
+	UserID int `json:"userId"`
+}
+
+// CheckoutOrder is the order the order service created for a checkout
+type CheckoutOrder struct {
+	ID            int     `json:"id"`
+	Number        string  `json:"orderNumber"`
+	Status        string  `json:"status"`
+	TotalAmount   float64 `json:"totalAmount"`
+	Currency      string  `json:"currency"`
+	CardAmountDue float64 `json:"cardAmountDue"`
+}
+
+type Charge struct {
+	Reference string  `json:"reference"`
+	OrderID   int     `json:"orderId"`
+	UserID    int     `json:"userId"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+}
+
+type Payment struct {
+	UUID          string  `json:"uuid"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+	FailureReason string  `json:"failureReason"`
+}
+
+type Refund struct {
+	UUID   string  `json:"uuid"`
+	Amount float64 `json:"amount"`
+	Status string  `json:"status"`
+}
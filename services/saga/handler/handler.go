@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/saga/domain"
+	"ecommerce-microservice-go/services/saga/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckoutItemRequest struct {
+	ProductID int `json:"productId" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required,gt=0"`
+}
+
+// CheckoutRequest is the order body the order service accepts; fields other than items are
+// passed through to it unchanged
+type CheckoutRequest struct {
+	Items []CheckoutItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type RefundRequest struct {
+	PaymentID string `json:"paymentId" binding:"required,max=64"`
+	OrderID   int    `json:"orderId" binding:"omitempty,gt=0"`
+	// Amount to refund; 0 refunds what is left of the payment
+	Amount float64 `json:"amount" binding:"gte=0"`
+	Reason string  `json:"reason" binding:"max=500"`
+}
+
+type AccountDeletionRequest struct {
+	// UserID defaults to the caller; deleting another account needs user:manage
+	UserID int `json:"userId" binding:"omitempty,gt=0"`
+}
+
+type ResponseStep struct {
+	Step       string    `json:"step"`
+	Phase      string    `json:"phase"`
+	Attempt    int       `json:"attempt"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ResponseSaga struct {
+	ID           int              `json:"id"`
+	Type         string           `json:"type"`
+	Reference    string           `json:"reference"`
+	Status       string           `json:"status"`
+	FailedDuring string           `json:"failedDuring,omitempty"`
+	CurrentStep  string           `json:"currentStep,omitempty"`
+	FailedStep   string           `json:"failedStep,omitempty"`
+	PivotReached bool             `json:"pivotReached"`
+	State        domain.SagaState `json:"state"`
+	Input        json.RawMessage  `json:"input,omitempty" swaggertype:"object"`
+	Attempts     int              `json:"attempts"`
+	LastError    string           `json:"lastError,omitempty"`
+	// NextAttemptAt is set while the saga waits to retry a call
+	NextAttemptAt *time.Time     `json:"nextAttemptAt,omitempty"`
+	StepDeadline  *time.Time     `json:"stepDeadline,omitempty"`
+	UserID        int            `json:"userId,omitempty"`
+	StartedBy     int            `json:"startedBy"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	FinishedAt    *time.Time     `json:"finishedAt,omitempty"`
+	Steps         []ResponseStep `json:"steps,omitempty"`
+}
+
+type Handler struct {
+	sagaUC usecase.ISagaUseCase
+	steps  map[domain.SagaType][]string
+	Logger *logger.Logger
+}
+
+func NewHandler(uc usecase.ISagaUseCase, l *logger.Logger) *Handler {
+	return &Handler{sagaUC: uc, steps: usecase.StepNames(), Logger: l}
+}
+
+var sagaSpec = pagination.Spec{
+	Sortable:    map[string]string{"id": "id", "createdAt": "created_at", "updatedAt": "updated_at"},
+	Filterable:  map[string]string{"status": "status", "type": "type", "userId": "user_id"},
+	DefaultSort: "-id",
+}
+
+// StartCheckout godoc
+// @Summary      Check out through the saga orchestrator
+// @Description  Reserves the stock, creates the order, charges the card and confirms the order, cancelling the
+// @Description  reservation, the order and the charge again when a step fails. The body is the order service's
+// @Description  order body. Answers 200 once the saga completed or was rolled back (status compensated, see
+// @Description  lastError), 202 while it waits to retry a step; poll GET /sagas/{id} then.
+// @Description  Retrying with the same Idempotency-Key returns the first saga.
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        Idempotency-Key header string false "Client-generated key, max 64 characters"
+// @Param        request body object true "Order, as for POST /order"
+// @Success      200 {object} ResponseSaga
+// @Success      202 {object} ResponseSaga
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /sagas/checkout [post]
+func (h *Handler) StartCheckout(ctx *gin.Context) {
+	var req CheckoutRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	// BindJSON rewinds the body, so the order can be passed on exactly as it was sent
+	raw, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	reference, err := idempotencyKey(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	items := make([]domain.CheckoutItem, len(req.Items))
+	for i, it := range req.Items {
+		items[i] = domain.CheckoutItem{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	saga, err := h.sagaUC.StartCheckout(ctx.Request.Context(), reference, domain.CheckoutInput{
+		ClientIP: ctx.ClientIP(), Items: items, Order: raw,
+	}, requester)
+	h.respondStarted(ctx, saga, err)
+}
+
+// StartRefund godoc
+// @Summary      Refund a payment through the saga orchestrator
+// @Description  Refunds the payment and records the refund as a comment on the order
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        Idempotency-Key header string false "Client-generated key, max 64 characters"
+// @Param        request body RefundRequest true "Refund"
+// @Success      200 {object} ResponseSaga
+// @Success      202 {object} ResponseSaga
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /sagas/refunds [post]
+func (h *Handler) StartRefund(ctx *gin.Context) {
+	var req RefundRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	reference, err := idempotencyKey(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	saga, err := h.sagaUC.StartRefund(ctx.Request.Context(), reference, domain.RefundInput{
+		OrderID: req.OrderID, PaymentID: req.PaymentID, Amount: req.Amount, Reason: req.Reason,
+	}, requester)
+	h.respondStarted(ctx, saga, err)
+}
+
+// StartAccountDeletion godoc
+// @Summary      Delete an account through the saga orchestrator
+// @Description  Cancels the user's pending orders, then deletes the account. While the user has paid or shipped
+// @Description  orders nothing is changed and the saga ends compensated with the reason in lastError.
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        Idempotency-Key header string false "Client-generated key, max 64 characters"
+// @Param        request body AccountDeletionRequest false "Account, the caller's own by default"
+// @Success      200 {object} ResponseSaga
+// @Success      202 {object} ResponseSaga
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Router       /sagas/account-deletions [post]
+func (h *Handler) StartAccountDeletion(ctx *gin.Context) {
+	var req AccountDeletionRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := controllers.BindJSON(ctx, &req); err != nil {
+			_ = ctx.Error(validation.BindingError(err))
+			return
+		}
+	}
+	reference, err := idempotencyKey(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	userID := req.UserID
+	if userID == 0 {
+		userID = requester.UserID
+	}
+	saga, err := h.sagaUC.StartAccountDeletion(ctx.Request.Context(), reference, userID, requester)
+	h.respondStarted(ctx, saga, err)
+}
+
+// GetSaga godoc
+// @Summary      Get a saga
+// @Description  The saga with every call made for it. Customers see the sagas they started, operators every saga.
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        id     path  int    true  "Saga ID"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseSaga
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /sagas/{id} [get]
+func (h *Handler) GetSaga(ctx *gin.Context) {
+	id, err := paramInt(ctx, "id")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	saga, err := h.sagaUC.GetByID(ctx.Request.Context(), id, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, h.sagaToResponse(saga))
+}
+
+// GetSagas godoc
+// @Summary      List sagas
+// @Description  Sagas without their step log, newest first. stuck=true lists failed sagas and those still in progress
+// @Description  longer than the configured threshold.
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        stuck  query bool   false "Only stuck sagas"
+// @Param        status query string false "running, compensating, completed, compensated or failed"
+// @Param        type   query string false "checkout, refund or account_deletion"
+// @Param        userId query int    false "Affected user"
+// @Param        page   query int    false "Page number" default(1)
+// @Param        limit  query int    false "Page size" default(20)
+// @Param        sort   query string false "Comma separated fields, prefix - for descending (id, createdAt, updatedAt)" default(-id)
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseSaga]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /sagas [get]
+func (h *Handler) GetSagas(ctx *gin.Context) {
+	query := ctx.Request.URL.Query()
+	stuck, err := strconv.ParseBool(query.Get("stuck"))
+	if err != nil && query.Get("stuck") != "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("stuck must be true or false"), domainErrors.ValidationError))
+		return
+	}
+	query.Del("stuck")
+	p, err := pagination.ParseQuery(query, sagaSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	sagas, total, err := h.sagaUC.Search(ctx.Request.Context(), p, stuck)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseSaga, len(*sagas))
+	for i := range *sagas {
+		res[i] = h.sagaToResponse(&(*sagas)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// RetrySaga godoc
+// @Summary      Retry a saga
+// @Description  Resumes a failed saga where it stopped, or a saga waiting to retry a step right away, with fresh
+// @Description  attempts and a fresh step deadline. Fix the cause reported in lastError first.
+// @Tags         Saga
+// @Security     BearerAuth
+// @Param        id path int true "Saga ID"
+// @Success      200 {object} ResponseSaga
+// @Success      202 {object} ResponseSaga
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse "The saga finished or is being executed"
+// @Router       /sagas/{id}/retry [post]
+func (h *Handler) RetrySaga(ctx *gin.Context) {
+	id, err := paramInt(ctx, "id")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	saga, err := h.sagaUC.Retry(ctx.Request.Context(), id)
+	h.respondStarted(ctx, saga, err)
+}
+
+// respondStarted answers 200 once the saga stopped and 202 while it waits to retry a step
+func (h *Handler) respondStarted(ctx *gin.Context, saga *domain.Saga, err error) {
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	status := http.StatusOK
+	if !saga.Status.IsFinal() {
+		status = http.StatusAccepted
+	}
+	ctx.JSON(status, h.sagaToResponse(saga))
+}
+
+func idempotencyKey(ctx *gin.Context) (string, error) {
+	key := ctx.GetHeader("Idempotency-Key")
+	if len(key) > 64 {
+		return "", domainErrors.NewAppError(errors.New("Idempotency-Key must be at most 64 characters"), domainErrors.ValidationError)
+	}
+	return key, nil
+}
+
+func paramInt(ctx *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil || id <= 0 {
+		return 0, domainErrors.NewAppError(errors.New("invalid "+name), domainErrors.ValidationError)
+	}
+	return id, nil
+}
+
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:            int(userIDVal.(float64)),
+		Role:              ctx.GetString("userRole"),
+		CanOperate:        middleware.HasPermission(ctx, security.PermSagaOperate),
+		CanDeleteAccounts: middleware.HasPermission(ctx, security.PermUserManage),
+	}, nil
+}
+
+// Mappers
+func (h *Handler) sagaToResponse(s *domain.Saga) ResponseSaga {
+	res := ResponseSaga{
+		ID: s.ID, Type: string(s.Type), Reference: s.Reference, Status: string(s.Status), FailedDuring: string(s.FailedDuring),
+		FailedStep: s.FailedStep, PivotReached: s.PivotReached, State: s.State, Input: s.Input, Attempts: s.Attempts,
+		LastError: s.LastError, StepDeadline: s.StepDeadline, UserID: s.UserID, StartedBy: s.StartedBy,
+		CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt, FinishedAt: s.FinishedAt,
+	}
+	if names := h.steps[s.Type]; !s.Status.IsFinal() || s.Status == domain.SagaFailed {
+		if s.StepIndex >= 0 && s.StepIndex < len(names) {
+			res.CurrentStep = names[s.StepIndex]
+		}
+	}
+	if !s.Status.IsFinal() && s.Attempts > 0 {
+		next := s.NextAttemptAt
+		res.NextAttemptAt = &next
+	}
+	for _, st := range s.Steps {
+		res.Steps = append(res.Steps, ResponseStep{
+			Step: st.Step, Phase: string(st.Phase), Attempt: st.Attempt, Succeeded: st.Succeeded, Error: st.Error,
+			DurationMs: st.DurationMs, CreatedAt: st.CreatedAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,161 @@
+// @title           Saga Service API
+// @version         1.0.0
+// @description     Saga orchestrator: checkout, refund and account deletion workflows across the order, payment and user services, with compensation and an operator retry API
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/scheduler"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/saga/client"
+	"ecommerce-microservice-go/services/saga/handler"
+	"ecommerce-microservice-go/services/saga/migrations"
+	"ecommerce-microservice-go/services/saga/repository"
+	"ecommerce-microservice-go/services/saga/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/saga/docs"
+)
+
+const service app.Service = "saga"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Saga Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, uc usecase.ISagaUseCase) {
+			jobs := scheduler.New(log)
+			jobs.Add(scheduler.Job{
+				Name:     "resume-sagas",
+				Interval: cfg.ResumeInterval,
+				Run:      uc.ResumeDue,
+			})
+			app.Go(lc, jobs.Run)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Saga Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Saga),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			func() client.IOrderClient {
+				return client.NewOrderClient(cfg.OrderServiceURL, cfg.InternalToken, cfg.Saga.CallTimeout)
+			},
+			func() client.IPaymentClient {
+				return client.NewPaymentClient(cfg.PaymentServiceURL, cfg.InternalToken, cfg.Saga.CallTimeout)
+			},
+			func() client.IUserClient {
+				return client.NewUserClient(cfg.UserServiceURL, cfg.InternalToken, cfg.Saga.CallTimeout)
+			},
+			repository.NewSagaRepository,
+			usecase.NewSagaUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, migrator *psql.Migrator,
+	h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("saga", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("saga"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("saga").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		RegisterRoutes(v1)
+
+	v1.GET("/sagas/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	sagas := v1.Group("/sagas")
+	sagas.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RateLimit(cacheStore, "saga", cfg.RateLimit, middleware.KeyByUser, log))
+	{
+		sagas.POST("/checkout", h.StartCheckout)
+		sagas.POST("/refunds", middleware.RequirePermissions(security.PermPaymentRefund), h.StartRefund)
+		sagas.POST("/account-deletions", h.StartAccountDeletion)
+		sagas.GET("", middleware.RequirePermissions(security.PermSagaOperate), h.GetSagas)
+		sagas.GET("/:id", h.GetSaga)
+		sagas.POST("/:id/retry", middleware.RequirePermissions(security.PermSagaOperate), h.RetrySaga)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
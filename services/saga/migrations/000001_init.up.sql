@@ -0,0 +1,45 @@
+CREATE TABLE sagas (
+    id bigserial,
+    type text NOT NULL,
+    reference text NOT NULL,
+    status text NOT NULL,
+    failed_during text,
+    step_index bigint NOT NULL DEFAULT 0,
+    failed_step text,
+    pivot_reached boolean NOT NULL DEFAULT false,
+    input jsonb NOT NULL,
+    state jsonb NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL,
+    step_deadline timestamptz,
+    locked_until timestamptz,
+    user_id bigint NOT NULL,
+    started_by bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    finished_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_sagas_type CHECK (type IN ('checkout', 'refund', 'account_deletion')),
+    CONSTRAINT chk_sagas_status CHECK (status IN ('running', 'compensating', 'completed', 'compensated', 'failed'))
+);
+-- starting a saga again with the same reference returns the first run
+CREATE UNIQUE INDEX idx_sagas_reference ON sagas (type, started_by, reference);
+CREATE INDEX idx_sagas_due ON sagas (status, next_attempt_at);
+CREATE INDEX idx_sagas_user_id ON sagas (user_id);
+
+CREATE TABLE saga_steps (
+    id bigserial,
+    saga_id bigint NOT NULL,
+    step text NOT NULL,
+    phase text NOT NULL,
+    attempt bigint NOT NULL,
+    succeeded boolean NOT NULL,
+    error text,
+    duration_ms bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_sagas_steps FOREIGN KEY (saga_id) REFERENCES sagas(id),
+    CONSTRAINT chk_saga_steps_phase CHECK (phase IN ('action', 'compensation'))
+);
+CREATE INDEX idx_saga_steps_saga_id ON saga_steps (saga_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sagas_tenant_id;
+ALTER TABLE sagas DROP COLUMN IF EXISTS tenant_id;
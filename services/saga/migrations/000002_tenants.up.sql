@@ -0,0 +1,5 @@
+-- Sagas belong to the tenant they were started in, and their calls to participant services
+-- act in it. Sagas started before tenants existed belong to the default tenant.
+
+ALTER TABLE sagas ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_sagas_tenant_id ON sagas (tenant_id);
@@ -0,0 +1,258 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/saga/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Saga struct {
+	ID            int        `gorm:"primaryKey"`
+	Type          string     `gorm:"column:type;not null"`
+	Reference     string     `gorm:"column:reference;not null"`
+	Status        string     `gorm:"column:status;not null"`
+	FailedDuring  string     `gorm:"column:failed_during"`
+	StepIndex     int        `gorm:"column:step_index;not null"`
+	FailedStep    string     `gorm:"column:failed_step"`
+	PivotReached  bool       `gorm:"column:pivot_reached;not null"`
+	Input         []byte     `gorm:"column:input;type:jsonb;not null"`
+	State         []byte     `gorm:"column:state;type:jsonb;not null"`
+	Attempts      int        `gorm:"column:attempts;not null"`
+	LastError     string     `gorm:"column:last_error"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;not null"`
+	StepDeadline  *time.Time `gorm:"column:step_deadline"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	UserID        int        `gorm:"column:user_id;not null"`
+	StartedBy     int        `gorm:"column:started_by;not null"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime:mili"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime:mili"`
+	FinishedAt    *time.Time `gorm:"column:finished_at"`
+	Steps         []Step     `gorm:"foreignKey:SagaID"`
+	psql.TenantModel
+}
+
+func (Saga) TableName() string { return "sagas" }
+
+type Step struct {
+	ID         int       `gorm:"primaryKey"`
+	SagaID     int       `gorm:"column:saga_id;not null"`
+	Step       string    `gorm:"column:step;not null"`
+	Phase      string    `gorm:"column:phase;not null"`
+	Attempt    int       `gorm:"column:attempt;not null"`
+	Succeeded  bool      `gorm:"column:succeeded;not null"`
+	Error      string    `gorm:"column:error"`
+	DurationMs int64     `gorm:"column:duration_ms"`
+	CreatedAt  time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (Step) TableName() string { return "saga_steps" }
+
+type SagaRepositoryInterface interface {
+	// Create stores a new saga locked until lockedUntil. A saga of the same type, starter and
+	// reference is returned instead, with created false.
+	Create(ctx context.Context, s *domain.Saga, lockedUntil time.Time) (saga *domain.Saga, created bool, err error)
+	GetByID(ctx context.Context, id int) (*domain.Saga, error)
+	// Search lists sagas without their step log; stuckBefore selects failed sagas and those
+	// still in progress that were started before it
+	Search(ctx context.Context, p pagination.Params, stuckBefore *time.Time) (*[]domain.Saga, int64, error)
+	// Save writes the saga's progress and, when step is not nil, logs the call it made
+	Save(ctx context.Context, s *domain.Saga, step *domain.StepLog) error
+	// ClaimDue locks in-progress sagas whose next attempt is due so concurrent workers skip them
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) (*[]domain.Saga, error)
+	// Lock locks an unlocked saga that is in one of statuses for an operator retry, failing with
+	// a Conflict when it is finished or another worker holds it
+	Lock(ctx context.Context, id int, statuses []domain.SagaStatus, lease time.Duration) (*domain.Saga, error)
+}
+
+type SagaRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewSagaRepository(db *gorm.DB, l *logger.Logger) SagaRepositoryInterface {
+	return &SagaRepository{DB: db, Logger: l}
+}
+
+func (r *SagaRepository) Create(ctx context.Context, d *domain.Saga, lockedUntil time.Time) (*domain.Saga, bool, error) {
+	s, err := sagaFromDomain(d)
+	if err != nil {
+		return nil, false, err
+	}
+	s.LockedUntil = &lockedUntil
+	tx := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if tx.Error != nil {
+		r.Logger.Error("Error creating saga", zap.String("type", s.Type), zap.Error(tx.Error))
+		return nil, false, psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		var existing Saga
+		if err := psql.Conn(ctx, r.DB).Where("type = ? AND started_by = ? AND reference = ?", s.Type, s.StartedBy, s.Reference).
+			First(&existing).Error; err != nil {
+			return nil, false, psql.TranslateError(err)
+		}
+		saga, err := sagaToDomain(&existing)
+		return saga, false, err
+	}
+	saga, err := sagaToDomain(s)
+	return saga, true, err
+}
+
+func (r *SagaRepository) GetByID(ctx context.Context, id int) (*domain.Saga, error) {
+	var s Saga
+	if err := psql.Conn(ctx, r.DB).Preload("Steps", orderSteps).Where("id = ?", id).First(&s).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return sagaToDomain(&s)
+}
+
+func (r *SagaRepository) Search(ctx context.Context, p pagination.Params, stuckBefore *time.Time) (*[]domain.Saga, int64, error) {
+	query := psql.Conn(ctx, r.DB).Model(&Saga{})
+	if stuckBefore != nil {
+		query = query.Where("(status = ? OR (status IN ? AND created_at < ?))", string(domain.SagaFailed),
+			[]string{string(domain.SagaRunning), string(domain.SagaCompensating)}, *stuckBefore)
+	}
+	var sagas []Saga
+	total, err := pagination.Find(query, p, &sagas)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := make([]domain.Saga, len(sagas))
+	for i := range sagas {
+		s, err := sagaToDomain(&sagas[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		result[i] = *s
+	}
+	return &result, total, nil
+}
+
+func (r *SagaRepository) Save(ctx context.Context, d *domain.Saga, step *domain.StepLog) error {
+	state, err := json.Marshal(d.State)
+	if err != nil {
+		return err
+	}
+	return psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if step != nil {
+			log := Step{
+				SagaID: d.ID, Step: step.Step, Phase: string(step.Phase), Attempt: step.Attempt, Succeeded: step.Succeeded,
+				Error: step.Error, DurationMs: step.DurationMs,
+			}
+			if err := tx.Create(&log).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Saga{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"status": string(d.Status), "failed_during": string(d.FailedDuring), "step_index": d.StepIndex,
+			"failed_step": d.FailedStep, "pivot_reached": d.PivotReached, "state": state, "attempts": d.Attempts,
+			"last_error": d.LastError, "next_attempt_at": d.NextAttemptAt, "step_deadline": d.StepDeadline,
+			"locked_until": d.LockedUntil, "finished_at": d.FinishedAt,
+		}).Error
+	})
+}
+
+func (r *SagaRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) (*[]domain.Saga, error) {
+	var claimed []Saga
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+				[]string{string(domain.SagaRunning), string(domain.SagaCompensating)}, now, now).
+			Order("next_attempt_at").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		lockedUntil := now.Add(lease)
+		ids := make([]int, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].LockedUntil = &lockedUntil
+		}
+		return tx.Model(&Saga{}).Where("id IN ?", ids).Update("locked_until", lockedUntil).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error claiming sagas", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Saga, len(claimed))
+	for i := range claimed {
+		s, err := sagaToDomain(&claimed[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *s
+	}
+	return &result, nil
+}
+
+func (r *SagaRepository) Lock(ctx context.Context, id int, statuses []domain.SagaStatus, lease time.Duration) (*domain.Saga, error) {
+	allowed := make([]string, len(statuses))
+	for i, s := range statuses {
+		allowed[i] = string(s)
+	}
+	now := time.Now()
+	tx := psql.Conn(ctx, r.DB).Model(&Saga{}).
+		Where("id = ? AND status IN ? AND (locked_until IS NULL OR locked_until < ?)", id, allowed, now).
+		Update("locked_until", now.Add(lease))
+	if tx.Error != nil {
+		return nil, psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		// either the saga does not exist, it finished, or a worker is executing it right now
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domainErrors.NewAppError(errors.New("saga is finished or being executed"), domainErrors.Conflict)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func orderSteps(db *gorm.DB) *gorm.DB { return db.Order("id") }
+
+func sagaFromDomain(d *domain.Saga) (*Saga, error) {
+	state, err := json.Marshal(d.State)
+	if err != nil {
+		return nil, err
+	}
+	return &Saga{
+		Type: string(d.Type), Reference: d.Reference, Status: string(d.Status), StepIndex: d.StepIndex, Input: d.Input,
+		State: state, NextAttemptAt: d.NextAttemptAt, UserID: d.UserID, StartedBy: d.StartedBy,
+		TenantModel: psql.TenantModel{TenantID: d.Tenant},
+	}, nil
+}
+
+func sagaToDomain(s *Saga) (*domain.Saga, error) {
+	var state domain.SagaState
+	if err := json.Unmarshal(s.State, &state); err != nil {
+		return nil, err
+	}
+	steps := make([]domain.StepLog, len(s.Steps))
+	for i, st := range s.Steps {
+		steps[i] = domain.StepLog{
+			ID: st.ID, SagaID: st.SagaID, Step: st.Step, Phase: domain.StepPhase(st.Phase), Attempt: st.Attempt,
+			Succeeded: st.Succeeded, Error: st.Error, DurationMs: st.DurationMs, CreatedAt: st.CreatedAt,
+		}
+	}
+	return &domain.Saga{
+		ID: s.ID, Type: domain.SagaType(s.Type), Reference: s.Reference, Status: domain.SagaStatus(s.Status),
+		FailedDuring: domain.SagaStatus(s.FailedDuring), StepIndex: s.StepIndex, FailedStep: s.FailedStep,
+		PivotReached: s.PivotReached, Input: s.Input, State: state, Attempts: s.Attempts, LastError: s.LastError,
+		NextAttemptAt: s.NextAttemptAt, StepDeadline: s.StepDeadline, LockedUntil: s.LockedUntil, UserID: s.UserID,
+		StartedBy: s.StartedBy, Tenant: s.TenantID, CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt, FinishedAt: s.FinishedAt, Steps: steps,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/retry"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/saga/domain"
+
+	"go.uber.org/zap"
+)
+
+const maxStepErrorLength = 500
+
+// step is one participant call of a workflow and, when its effect can be undone, the call
+// undoing it. Both must be safe to repeat: a call that timed out may still have been applied.
+type step struct {
+	name string
+	// pivot steps cannot be undone; once one completed the saga only moves forward
+	pivot      bool
+	action     func(ctx context.Context, saga *domain.Saga) error
+	compensate func(ctx context.Context, saga *domain.Saga) error
+}
+
+// run executes a locked saga until it finishes or has to wait before retrying a call, saving
+// its progress after every call. The lock is released once the saga stops.
+func (s *SagaUseCase) run(ctx context.Context, saga *domain.Saga) {
+	steps := s.workflows[saga.Type]
+	for {
+		var more bool
+		switch saga.Status {
+		case domain.SagaRunning:
+			more = s.execute(ctx, saga, steps)
+		case domain.SagaCompensating:
+			more = s.compensate(ctx, saga, steps)
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// execute makes the call of the current step and reports whether the saga can go on right away.
+// Before the pivot a refused call, or a step still failing at its deadline, rolls the saga back;
+// after it the step is retried until the attempts run out.
+func (s *SagaUseCase) execute(ctx context.Context, saga *domain.Saga, steps []step) bool {
+	st := steps[saga.StepIndex]
+	if saga.StepDeadline == nil {
+		deadline := time.Now().Add(s.config.StepTimeout)
+		saga.StepDeadline = &deadline
+	}
+	log, err := s.call(ctx, saga, st.name, domain.PhaseAction, st.action)
+	if err == nil {
+		saga.StepIndex++
+		saga.PivotReached = saga.PivotReached || st.pivot
+		saga.Attempts, saga.StepDeadline, saga.LastError = 0, nil, ""
+		if saga.StepIndex == len(steps) {
+			s.finish(saga, domain.SagaCompleted)
+		}
+		return s.save(ctx, saga, log) && !saga.Status.IsFinal()
+	}
+	saga.Attempts++
+	saga.LastError = log.Error
+	switch {
+	case saga.PivotReached:
+		if isPermanent(err) || saga.Attempts >= s.config.MaxAttempts {
+			s.fail(saga, domain.SagaRunning)
+		} else {
+			s.retryLater(saga)
+		}
+	case isPermanent(err) || time.Now().After(*saga.StepDeadline):
+		s.Logger.Info("Rolling back saga", zap.Int("sagaID", saga.ID), zap.String("step", st.name), zap.String("error", log.Error))
+		saga.Status, saga.FailedStep = domain.SagaCompensating, st.name
+		// the failed step is undone too, since a call that timed out may have been applied
+		saga.Attempts, saga.StepDeadline = 0, nil
+	default:
+		s.retryLater(saga)
+	}
+	return s.save(ctx, saga, log) && saga.Status == domain.SagaCompensating
+}
+
+// compensate undoes the current step and reports whether the saga can go on right away. A
+// compensation is retried until the attempts run out, then the saga is failed. LastError keeps
+// the failure that started the rollback unless the saga fails.
+func (s *SagaUseCase) compensate(ctx context.Context, saga *domain.Saga, steps []step) bool {
+	for saga.StepIndex >= 0 && steps[saga.StepIndex].compensate == nil {
+		saga.StepIndex--
+	}
+	if saga.StepIndex < 0 {
+		s.finish(saga, domain.SagaCompensated)
+		s.save(ctx, saga, nil)
+		return false
+	}
+	st := steps[saga.StepIndex]
+	log, err := s.call(ctx, saga, st.name, domain.PhaseCompensation, st.compensate)
+	if err == nil {
+		saga.StepIndex--
+		saga.Attempts = 0
+		return s.save(ctx, saga, log)
+	}
+	saga.Attempts++
+	if saga.Attempts >= s.config.MaxAttempts {
+		saga.LastError = log.Error
+		s.fail(saga, domain.SagaCompensating)
+	} else {
+		s.retryLater(saga)
+	}
+	s.save(ctx, saga, log)
+	return false
+}
+
+func (s *SagaUseCase) call(ctx context.Context, saga *domain.Saga, name string, phase domain.StepPhase,
+	fn func(ctx context.Context, saga *domain.Saga) error) (*domain.StepLog, error) {
+	// workers resume sagas of every tenant; each call acts in the tenant the saga started in
+	callCtx, cancel := context.WithTimeout(tenant.ContextWithTenant(ctx, saga.Tenant), s.config.CallTimeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(callCtx, saga)
+	log := &domain.StepLog{
+		SagaID: saga.ID, Step: name, Phase: phase, Attempt: saga.Attempts + 1, Succeeded: err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.Error = truncate(err.Error(), maxStepErrorLength)
+		s.Logger.Warn("Saga step failed", zap.Int("sagaID", saga.ID), zap.String("step", name),
+			zap.String("phase", string(phase)), zap.Int("attempt", log.Attempt), zap.Error(err))
+	}
+	return log, err
+}
+
+func (s *SagaUseCase) retryLater(saga *domain.Saga) {
+	saga.NextAttemptAt = time.Now().Add(retry.Backoff(saga.Attempts, s.config.BaseBackoff, s.config.MaxBackoff))
+	saga.LockedUntil = nil
+}
+
+func (s *SagaUseCase) fail(saga *domain.Saga, during domain.SagaStatus) {
+	s.Logger.Error("Saga failed and needs an operator", zap.Int("sagaID", saga.ID), zap.String("type", string(saga.Type)),
+		zap.Int("step", saga.StepIndex), zap.String("during", string(during)), zap.String("error", saga.LastError))
+	saga.Status, saga.FailedDuring = domain.SagaFailed, during
+	saga.LockedUntil = nil
+}
+
+func (s *SagaUseCase) finish(saga *domain.Saga, status domain.SagaStatus) {
+	now := time.Now()
+	s.Logger.Info("Saga finished", zap.Int("sagaID", saga.ID), zap.String("type", string(saga.Type)), zap.String("status", string(status)))
+	saga.Status, saga.FinishedAt = status, &now
+	saga.StepDeadline, saga.LockedUntil = nil, nil
+}
+
+// save stores the saga's progress, reporting whether it did. A saga that could not be saved
+// stays locked until its lease expires and is then resumed from its last saved step.
+func (s *SagaUseCase) save(ctx context.Context, saga *domain.Saga, log *domain.StepLog) bool {
+	// the call has already been made, so record it even when shutdown cancelled ctx
+	if err := s.repo.Save(context.WithoutCancel(ctx), saga, log); err != nil {
+		s.Logger.Error("Error saving saga", zap.Int("sagaID", saga.ID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// isPermanent reports whether a participant refused the call, which repeating it will not change
+func isPermanent(err error) bool {
+	var appErr *domainErrors.AppError
+	return errors.As(err, &appErr)
+}
+
+// participantReference identifies the saga's requests to participants, which deduplicate by it
+func participantReference(saga *domain.Saga) string {
+	return "saga-" + strconv.Itoa(saga.ID)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
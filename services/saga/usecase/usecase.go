@@ -0,0 +1,182 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/saga/client"
+	"ecommerce-microservice-go/services/saga/domain"
+	"ecommerce-microservice-go/services/saga/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ISagaUseCase interface {
+	// StartCheckout places the customer's order: it reserves the stock, creates the order,
+	// charges the card and confirms the order, undoing the completed steps when one fails.
+	// Starting the same reference again returns the first run.
+	StartCheckout(ctx context.Context, reference string, input domain.CheckoutInput, requester domain.Requester) (*domain.Saga, error)
+	// StartRefund refunds a payment and notes the refund on its order
+	StartRefund(ctx context.Context, reference string, input domain.RefundInput, requester domain.Requester) (*domain.Saga, error)
+	// StartAccountDeletion cancels the user's unpaid orders and deletes the account. It is rolled
+	// back while the user has paid or shipped orders.
+	StartAccountDeletion(ctx context.Context, reference string, userID int, requester domain.Requester) (*domain.Saga, error)
+	// GetByID returns the saga with its step log; other users' sagas are hidden from
+	// requesters who cannot operate sagas
+	GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Saga, error)
+	// Search lists sagas for operators; stuck selects failed sagas and those still in
+	// progress after StuckAfter
+	Search(ctx context.Context, p pagination.Params, stuck bool) (*[]domain.Saga, int64, error)
+	// Retry resumes a failed saga, or one waiting to retry a step, with fresh attempts and a
+	// fresh step deadline
+	Retry(ctx context.Context, id int) (*domain.Saga, error)
+	// ResumeDue runs the sagas whose next attempt is due and returns how many finished
+	ResumeDue(ctx context.Context) (int, error)
+}
+
+type SagaConfig struct {
+	// StepTimeout is how long a step may keep failing before the saga is rolled back
+	StepTimeout time.Duration
+	// CallTimeout bounds a single call to a participant service
+	CallTimeout time.Duration
+	// MaxAttempts bounds the attempts of a compensation, or of a step after the pivot, before
+	// the saga is failed and left to an operator
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+	StuckAfter  time.Duration
+}
+
+type SagaUseCase struct {
+	repo      repository.SagaRepositoryInterface
+	orders    client.IOrderClient
+	payments  client.IPaymentClient
+	users     client.IUserClient
+	workflows map[domain.SagaType][]step
+	// lease outlasts the slowest possible run, in which every call of the longest workflow times out
+	lease  time.Duration
+	config SagaConfig
+	Logger *logger.Logger
+}
+
+func NewSagaUseCase(r repository.SagaRepositoryInterface, orders client.IOrderClient, payments client.IPaymentClient,
+	users client.IUserClient, cfg SagaConfig, l *logger.Logger) ISagaUseCase {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	s := &SagaUseCase{repo: r, orders: orders, payments: payments, users: users, config: cfg, Logger: l}
+	s.workflows = s.workflowSteps()
+	longest := 0
+	for _, steps := range s.workflows {
+		longest = max(longest, len(steps))
+	}
+	s.lease = time.Duration(2*longest)*cfg.CallTimeout + time.Minute
+	return s
+}
+
+func (s *SagaUseCase) StartCheckout(ctx context.Context, reference string, input domain.CheckoutInput, requester domain.Requester) (*domain.Saga, error) {
+	if len(input.Items) == 0 {
+		return nil, domainErrors.NewAppError(errors.New("order must contain at least one item"), domainErrors.ValidationError)
+	}
+	input.UserID, input.Role = requester.UserID, requester.Role
+	return s.start(ctx, domain.SagaCheckout, reference, requester.UserID, input, requester)
+}
+
+func (s *SagaUseCase) StartRefund(ctx context.Context, reference string, input domain.RefundInput, requester domain.Requester) (*domain.Saga, error) {
+	if input.Amount < 0 {
+		return nil, domainErrors.NewAppError(errors.New("amount must not be negative"), domainErrors.ValidationError)
+	}
+	return s.start(ctx, domain.SagaRefund, reference, 0, input, requester)
+}
+
+func (s *SagaUseCase) StartAccountDeletion(ctx context.Context, reference string, userID int, requester domain.Requester) (*domain.Saga, error) {
+	if userID != requester.UserID && !requester.CanDeleteAccounts {
+		return nil, domainErrors.NewAppError(errors.New("only the account owner may delete it"), domainErrors.NotAuthorized)
+	}
+	return s.start(ctx, domain.SagaAccountDeletion, reference, userID, domain.AccountDeletionInput{UserID: userID}, requester)
+}
+
+func (s *SagaUseCase) start(ctx context.Context, sagaType domain.SagaType, reference string, userID int, input interface{}, requester domain.Requester) (*domain.Saga, error) {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	if reference == "" {
+		reference = uuid.NewString()
+	}
+	saga, created, err := s.repo.Create(ctx, &domain.Saga{
+		Type: sagaType, Reference: reference, Status: domain.SagaRunning, Input: raw, NextAttemptAt: time.Now(),
+		UserID: userID, StartedBy: requester.UserID, Tenant: tenant.FromContext(ctx),
+	}, time.Now().Add(s.lease))
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		s.Logger.Debug("Saga already started", zap.Int("sagaID", saga.ID), zap.String("type", string(sagaType)))
+		return s.repo.GetByID(ctx, saga.ID)
+	}
+	s.Logger.Info("Starting saga", zap.Int("sagaID", saga.ID), zap.String("type", string(sagaType)), zap.Int("startedBy", requester.UserID))
+	// the saga keeps going when the caller disconnects; it is persisted and can be looked up
+	s.run(context.WithoutCancel(ctx), saga)
+	return s.repo.GetByID(ctx, saga.ID)
+}
+
+func (s *SagaUseCase) GetByID(ctx context.Context, id int, requester domain.Requester) (*domain.Saga, error) {
+	saga, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanAccess(saga) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return saga, nil
+}
+
+func (s *SagaUseCase) Search(ctx context.Context, p pagination.Params, stuck bool) (*[]domain.Saga, int64, error) {
+	var stuckBefore *time.Time
+	if stuck {
+		before := time.Now().Add(-s.config.StuckAfter)
+		stuckBefore = &before
+	}
+	return s.repo.Search(ctx, p, stuckBefore)
+}
+
+func (s *SagaUseCase) Retry(ctx context.Context, id int) (*domain.Saga, error) {
+	saga, err := s.repo.Lock(ctx, id, []domain.SagaStatus{domain.SagaFailed, domain.SagaRunning, domain.SagaCompensating}, s.lease)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Retrying saga", zap.Int("sagaID", id), zap.String("status", string(saga.Status)), zap.Int("step", saga.StepIndex))
+	if saga.Status == domain.SagaFailed {
+		saga.Status, saga.FailedDuring = saga.FailedDuring, ""
+	}
+	saga.Attempts, saga.StepDeadline, saga.NextAttemptAt = 0, nil, time.Now()
+	s.run(context.WithoutCancel(ctx), saga)
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *SagaUseCase) ResumeDue(ctx context.Context) (int, error) {
+	sagas, err := s.repo.ClaimDue(ctx, s.config.BatchSize, s.lease)
+	if err != nil {
+		return 0, err
+	}
+	finished := 0
+	for i := range *sagas {
+		saga := &(*sagas)[i]
+		s.run(ctx, saga)
+		if saga.Status.IsFinal() {
+			finished++
+		}
+	}
+	return finished, nil
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+	clientmocks "ecommerce-microservice-go/services/saga/client/mocks"
+	"ecommerce-microservice-go/services/saga/domain"
+	"ecommerce-microservice-go/services/saga/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger     = &logger.Logger{Log: zap.NewNop()}
+	testConfig     = SagaConfig{StepTimeout: time.Minute, CallTimeout: time.Second, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute, BatchSize: 10}
+	buyer          = domain.Requester{UserID: 7, Role: "customer"}
+	errUnavailable = errors.New("connection refused")
+)
+
+// sagaMocks are the dependencies of SagaUseCase
+type sagaMocks struct {
+	repo     *mocks.MockSagaRepositoryInterface
+	orders   *clientmocks.MockIOrderClient
+	payments *clientmocks.MockIPaymentClient
+	users    *clientmocks.MockIUserClient
+	// steps logs the calls the saga saved, as "step/phase"
+	steps []string
+}
+
+// newSagaUseCase stores the saga in memory, the way the repository saves it after every call
+func newSagaUseCase(t *testing.T) (ISagaUseCase, *sagaMocks) {
+	ctrl := gomock.NewController(t)
+	m := &sagaMocks{
+		repo: mocks.NewMockSagaRepositoryInterface(ctrl), orders: clientmocks.NewMockIOrderClient(ctrl),
+		payments: clientmocks.NewMockIPaymentClient(ctrl), users: clientmocks.NewMockIUserClient(ctrl),
+	}
+	var stored domain.Saga
+	m.repo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, s *domain.Saga, _ time.Time) (*domain.Saga, bool, error) {
+			s.ID, stored = 1, *s
+			return s, true, nil
+		}).AnyTimes()
+	m.repo.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s *domain.Saga, log *domain.StepLog) error {
+		stored = *s
+		if log != nil {
+			m.steps = append(m.steps, log.Step+"/"+string(log.Phase))
+		}
+		return nil
+	}).AnyTimes()
+	m.repo.EXPECT().GetByID(gomock.Any(), 1).DoAndReturn(func(context.Context, int) (*domain.Saga, error) {
+		saga := stored
+		return &saga, nil
+	}).AnyTimes()
+	return NewSagaUseCase(m.repo, m.orders, m.payments, m.users, testConfig, testLogger), m
+}
+
+func TestSagaUseCaseStartCheckout(t *testing.T) {
+	order := &domain.CheckoutOrder{ID: 12, Number: "ORD-12", Currency: "IDR", CardAmountDue: 50}
+	// ordered expects the checkout to reach the payment with the order created
+	ordered := func(m *sagaMocks) {
+		m.orders.EXPECT().ReserveStock(gomock.Any(), "saga-1", gomock.Any()).Return("res-1", nil)
+		m.orders.EXPECT().CreateOrder(gomock.Any(), "saga-1", "res-1", gomock.Any()).Return(order, nil)
+	}
+	tests := []struct {
+		name       string
+		setup      func(m *sagaMocks)
+		wantStatus domain.SagaStatus
+		wantSteps  string
+	}{
+		{
+			name: "paid order is confirmed",
+			setup: func(m *sagaMocks) {
+				ordered(m)
+				m.payments.EXPECT().Charge(gomock.Any(), domain.Charge{Reference: "saga-1", OrderID: 12, UserID: 7, Amount: 50, Currency: "IDR"}).
+					Return(&domain.Payment{UUID: "pay-1", Status: "succeeded"}, nil)
+				m.orders.EXPECT().CaptureOrder(gomock.Any(), 12, "pay-1").Return(nil)
+			},
+			wantStatus: domain.SagaCompleted,
+			wantSteps:  "reserve_stock/action create_order/action charge_payment/action confirm_order/action",
+		},
+		{
+			name: "store credit covers the order without a charge",
+			setup: func(m *sagaMocks) {
+				m.orders.EXPECT().ReserveStock(gomock.Any(), "saga-1", gomock.Any()).Return("res-1", nil)
+				m.orders.EXPECT().CreateOrder(gomock.Any(), "saga-1", "res-1", gomock.Any()).Return(&domain.CheckoutOrder{ID: 12}, nil)
+				m.orders.EXPECT().CaptureOrder(gomock.Any(), 12, "").Return(nil)
+			},
+			wantStatus: domain.SagaCompleted,
+			wantSteps:  "reserve_stock/action create_order/action charge_payment/action confirm_order/action",
+		},
+		{
+			name: "declined card rolls the checkout back",
+			setup: func(m *sagaMocks) {
+				ordered(m)
+				gomock.InOrder(
+					m.payments.EXPECT().Charge(gomock.Any(), gomock.Any()).Return(&domain.Payment{UUID: "pay-1", Status: "failed", FailureReason: "insufficient funds"}, nil),
+					m.orders.EXPECT().CancelOrder(gomock.Any(), 12, "payment_failed", gomock.Any()).Return(nil),
+					m.orders.EXPECT().CancelReservation(gomock.Any(), "res-1").Return(nil),
+				)
+			},
+			wantStatus: domain.SagaCompensated,
+			wantSteps: "reserve_stock/action create_order/action charge_payment/action charge_payment/compensation " +
+				"create_order/compensation reserve_stock/compensation",
+		},
+		{
+			name: "unreachable payment service is retried later",
+			setup: func(m *sagaMocks) {
+				ordered(m)
+				m.payments.EXPECT().Charge(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+			},
+			wantStatus: domain.SagaRunning,
+			wantSteps:  "reserve_stock/action create_order/action charge_payment/action",
+		},
+		{
+			name: "refused reservation leaves nothing to undo",
+			setup: func(m *sagaMocks) {
+				m.orders.EXPECT().ReserveStock(gomock.Any(), "saga-1", gomock.Any()).
+					Return("", domainErrors.NewAppError(errors.New("out of stock"), domainErrors.ValidationError))
+				m.orders.EXPECT().CancelReservation(gomock.Any(), "saga-1").Return(nil)
+			},
+			wantStatus: domain.SagaCompensated,
+			wantSteps:  "reserve_stock/action reserve_stock/compensation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newSagaUseCase(t)
+			tt.setup(m)
+			input := domain.CheckoutInput{UserID: 99, Role: "admin", Items: []domain.CheckoutItem{{ProductID: 3, Quantity: 1}}}
+			saga, err := uc.StartCheckout(context.Background(), "checkout-1", input, buyer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saga.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (last error %q)", saga.Status, tt.wantStatus, saga.LastError)
+			}
+			if got := strings.Join(m.steps, " "); got != tt.wantSteps {
+				t.Errorf("steps = %s\nwant %s", got, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestSagaUseCaseStartCheckoutValidation(t *testing.T) {
+	uc, _ := newSagaUseCase(t)
+	if _, err := uc.StartCheckout(context.Background(), "checkout-1", domain.CheckoutInput{}, buyer); !errors.Is(err, domainErrors.ErrValidation) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+}
+
+func TestSagaUseCaseStartAccountDeletion(t *testing.T) {
+	tests := []struct {
+		name       string
+		requester  domain.Requester
+		setup      func(m *sagaMocks)
+		wantStatus domain.SagaStatus
+		wantErr    error
+	}{
+		{
+			name:      "owner deletes the account",
+			requester: buyer,
+			setup: func(m *sagaMocks) {
+				m.orders.EXPECT().CancelUserOrders(gomock.Any(), 7).Return(2, nil)
+				m.users.EXPECT().DeleteUser(gomock.Any(), 7).Return(nil)
+			},
+			wantStatus: domain.SagaCompleted,
+		},
+		{
+			name:      "failures after the cancelled orders need an operator",
+			requester: buyer,
+			setup: func(m *sagaMocks) {
+				m.orders.EXPECT().CancelUserOrders(gomock.Any(), 7).Return(2, nil)
+				m.users.EXPECT().DeleteUser(gomock.Any(), 7).Return(domainErrors.NewAppErrorWithType(domainErrors.Conflict))
+			},
+			wantStatus: domain.SagaFailed,
+		},
+		{
+			name:      "another user's account",
+			requester: domain.Requester{UserID: 8},
+			wantErr:   domainErrors.ErrNotAuthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, m := newSagaUseCase(t)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			saga, err := uc.StartAccountDeletion(context.Background(), "", 7, tt.requester)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && saga.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", saga.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// inTenant matches a context scoped to the tenant
+type inTenant string
+
+func (m inTenant) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	return ok && tenant.FromContext(ctx) == string(m)
+}
+
+func (m inTenant) String() string { return "is a context of tenant " + string(m) }
+
+func TestSagaUseCaseResumeDueActsInTheSagasTenant(t *testing.T) {
+	uc, m := newSagaUseCase(t)
+	m.repo.EXPECT().ClaimDue(gomock.Any(), testConfig.BatchSize, gomock.Any()).Return(&[]domain.Saga{{
+		ID: 1, Type: domain.SagaAccountDeletion, Status: domain.SagaRunning, Input: []byte(`{"userId":7}`), UserID: 7, Tenant: "acme",
+	}}, nil)
+	m.orders.EXPECT().CancelUserOrders(inTenant("acme"), 7).Return(0, nil)
+	m.users.EXPECT().DeleteUser(inTenant("acme"), 7).Return(nil)
+	if finished, err := uc.ResumeDue(context.Background()); err != nil || finished != 1 {
+		t.Fatalf("ResumeDue() = %d, %v, want 1 finished", finished, err)
+	}
+}
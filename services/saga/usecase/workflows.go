@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/services/saga/domain"
+)
+
+func (s *SagaUseCase) workflowSteps() map[domain.SagaType][]step {
+	return map[domain.SagaType][]step{
+		domain.SagaCheckout:        s.checkoutSteps(),
+		domain.SagaRefund:          s.refundSteps(),
+		domain.SagaAccountDeletion: s.accountDeletionSteps(),
+	}
+}
+
+// checkoutSteps end with the pivot: a confirmed order is only undone by a refund saga
+func (s *SagaUseCase) checkoutSteps() []step {
+	return []step{
+		{name: "reserve_stock", action: s.reserveStock, compensate: s.cancelReservation},
+		{name: "create_order", action: s.createOrder, compensate: s.cancelOrder},
+		{name: "charge_payment", action: s.chargePayment, compensate: s.refundCharge},
+		{name: "confirm_order", pivot: true, action: s.confirmOrder},
+	}
+}
+
+// refundSteps start with the pivot, since money that was returned cannot be taken back
+func (s *SagaUseCase) refundSteps() []step {
+	return []step{
+		{name: "refund_payment", pivot: true, action: s.refundPayment},
+		{name: "annotate_order", action: s.annotateRefund},
+	}
+}
+
+// accountDeletionSteps start with the pivot, since cancelled orders are not reopened
+func (s *SagaUseCase) accountDeletionSteps() []step {
+	return []step{
+		{name: "cancel_orders", pivot: true, action: s.cancelUserOrders},
+		{name: "delete_account", action: s.deleteAccount},
+	}
+}
+
+// --- Checkout ---
+
+func (s *SagaUseCase) reserveStock(ctx context.Context, saga *domain.Saga) error {
+	var input domain.CheckoutInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	reservationID, err := s.orders.ReserveStock(ctx, participantReference(saga), &input)
+	if err != nil {
+		return err
+	}
+	saga.State.ReservationID = reservationID
+	return nil
+}
+
+func (s *SagaUseCase) cancelReservation(ctx context.Context, saga *domain.Saga) error {
+	reservationID := saga.State.ReservationID
+	if reservationID == "" {
+		reservationID = participantReference(saga)
+	}
+	return s.orders.CancelReservation(ctx, reservationID)
+}
+
+func (s *SagaUseCase) createOrder(ctx context.Context, saga *domain.Saga) error {
+	var input domain.CheckoutInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	order, err := s.orders.CreateOrder(ctx, participantReference(saga), saga.State.ReservationID, &input)
+	if err != nil {
+		return err
+	}
+	saga.State.OrderID, saga.State.OrderNumber = order.ID, order.Number
+	saga.State.AmountDue, saga.State.Currency = order.CardAmountDue, order.Currency
+	return nil
+}
+
+func (s *SagaUseCase) cancelOrder(ctx context.Context, saga *domain.Saga) error {
+	if saga.State.OrderID == 0 {
+		// the create call may have been applied although it timed out; repeating it with the
+		// same reference finds the order, while a refused create left nothing to cancel
+		if err := s.createOrder(ctx, saga); err != nil {
+			if isPermanent(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return s.orders.CancelOrder(ctx, saga.State.OrderID, "payment_failed", truncate("Checkout rolled back: "+saga.LastError, 500))
+}
+
+func (s *SagaUseCase) chargePayment(ctx context.Context, saga *domain.Saga) error {
+	if saga.State.AmountDue <= 0 {
+		// store credit covered the order
+		return nil
+	}
+	var input domain.CheckoutInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	p, err := s.payments.Charge(ctx, domain.Charge{
+		Reference: participantReference(saga), OrderID: saga.State.OrderID, UserID: input.UserID,
+		Amount: saga.State.AmountDue, Currency: saga.State.Currency,
+	})
+	if err != nil {
+		return err
+	}
+	switch p.Status {
+	case "succeeded":
+		saga.State.PaymentID = p.UUID
+		return nil
+	case "failed":
+		return domainErrors.NewAppError(fmt.Errorf("payment declined: %s", p.FailureReason), domainErrors.PaymentDeclined)
+	}
+	// the provider settles it later; charging the reference again picks up the outcome, and a
+	// rollback in the meantime refunds it
+	saga.State.PaymentID = p.UUID
+	return fmt.Errorf("payment %s is %s", p.UUID, p.Status)
+}
+
+func (s *SagaUseCase) refundCharge(ctx context.Context, saga *domain.Saga) error {
+	if saga.State.PaymentID == "" {
+		return nil
+	}
+	_, err := s.payments.Refund(ctx, saga.State.PaymentID, participantReference(saga)+"-rollback", 0, "checkout rolled back")
+	return err
+}
+
+func (s *SagaUseCase) confirmOrder(ctx context.Context, saga *domain.Saga) error {
+	return s.orders.CaptureOrder(ctx, saga.State.OrderID, saga.State.PaymentID)
+}
+
+// --- Refund ---
+
+func (s *SagaUseCase) refundPayment(ctx context.Context, saga *domain.Saga) error {
+	var input domain.RefundInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	refund, err := s.payments.Refund(ctx, input.PaymentID, participantReference(saga), input.Amount, input.Reason)
+	if err != nil {
+		return err
+	}
+	saga.State.RefundID, saga.State.RefundedAmount, saga.State.OrderID = refund.UUID, refund.Amount, input.OrderID
+	return nil
+}
+
+func (s *SagaUseCase) annotateRefund(ctx context.Context, saga *domain.Saga) error {
+	if saga.State.OrderID == 0 {
+		return nil
+	}
+	var input domain.RefundInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	note := fmt.Sprintf("Refunded %.2f (refund %s)", saga.State.RefundedAmount, saga.State.RefundID)
+	if input.Reason != "" {
+		note += ": " + input.Reason
+	}
+	return s.orders.AddOrderNote(ctx, saga.State.OrderID, note)
+}
+
+// --- Account deletion ---
+
+func (s *SagaUseCase) cancelUserOrders(ctx context.Context, saga *domain.Saga) error {
+	var input domain.AccountDeletionInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	n, err := s.orders.CancelUserOrders(ctx, input.UserID)
+	if err != nil {
+		return err
+	}
+	saga.State.CancelledOrders = n
+	return nil
+}
+
+func (s *SagaUseCase) deleteAccount(ctx context.Context, saga *domain.Saga) error {
+	var input domain.AccountDeletionInput
+	if err := json.Unmarshal(saga.Input, &input); err != nil {
+		return err
+	}
+	return s.users.DeleteUser(ctx, input.UserID)
+}
+
+// StepNames lists the steps of every workflow in execution order
+func StepNames() map[domain.SagaType][]string {
+	names := map[domain.SagaType][]string{}
+	for sagaType, steps := range (&SagaUseCase{}).workflowSteps() {
+		for _, st := range steps {
+			names[sagaType] = append(names[sagaType], st.name)
+		}
+	}
+	return names
+}
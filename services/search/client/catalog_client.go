@@ -0,0 +1,77 @@
+package client
+
+//go:generate mockgen -source=catalog_client.go -destination=mocks/catalog_client.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+)
+
+type ICatalogClient interface {
+	// GetCategories returns a page of categories and the number of pages
+	GetCategories(ctx context.Context, page, limit int) ([]contracts.Category, int, error)
+	// GetProducts returns a page of active products and the number of pages
+	GetProducts(ctx context.Context, page, limit int) ([]contracts.Product, int, error)
+}
+
+// CatalogClient reads the catalog service's public listings directly, bypassing the gateway.
+// The listings are those of the tenant of ctx.
+type CatalogClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewCatalogClient(baseURL string) ICatalogClient {
+	return &CatalogClient{baseURL: baseURL, http: &http.Client{Timeout: 10 * time.Second, Transport: middleware.RequestIDTransport(tenant.Transport(otel.Transport(nil)))}}
+}
+
+type page[T any] struct {
+	Data []T `json:"data"`
+	Meta struct {
+		TotalPages int `json:"totalPages"`
+	} `json:"meta"`
+}
+
+func (c *CatalogClient) GetCategories(ctx context.Context, p, limit int) ([]contracts.Category, int, error) {
+	var res page[contracts.Category]
+	if err := c.get(ctx, "/v1/category/", p, limit, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Data, res.Meta.TotalPages, nil
+}
+
+func (c *CatalogClient) GetProducts(ctx context.Context, p, limit int) ([]contracts.Product, int, error) {
+	var res page[contracts.Product]
+	if err := c.get(ctx, "/v1/product/", p, limit, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Data, res.Meta.TotalPages, nil
+}
+
+// get reads one page in id order, so pages stay stable while the catalog changes
+func (c *CatalogClient) get(ctx context.Context, path string, p, limit int, out any) error {
+	query := url.Values{"page": {strconv.Itoa(p)}, "limit": {strconv.Itoa(limit)}, "sort": {"id"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("catalog service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("catalog service returned %d for %s page %d", res.StatusCode, path, p)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,57 @@
+package client
+
+//go:generate mockgen -source=user_client.go -destination=mocks/user_client.go -package=mocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+)
+
+type IUserClient interface {
+	// GetTenants returns the ids of the active tenants
+	GetTenants(ctx context.Context) ([]string, error)
+}
+
+// UserClient calls the user service internal API directly, bypassing the gateway
+type UserClient struct {
+	baseURL       string
+	internalToken string
+	http          *http.Client
+}
+
+func NewUserClient(baseURL, internalToken string) IUserClient {
+	return &UserClient{baseURL: baseURL, internalToken: internalToken, http: &http.Client{Timeout: 5 * time.Second, Transport: middleware.RequestIDTransport(otel.Transport(nil))}}
+}
+
+func (c *UserClient) GetTenants(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/internal/tenants", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", c.internalToken)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("user service request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service returned %d listing tenants", res.StatusCode)
+	}
+	var tenants []struct {
+		ID string `json:"id"`
+	}
+	if err := controllers.DecodeData(res.Body, &tenants); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(tenants))
+	for i, t := range tenants {
+		ids[i] = t.ID
+	}
+	return ids, nil
+}
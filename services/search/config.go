@@ -0,0 +1,58 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/search/repository"
+)
+
+type Config struct {
+	Server            config.Server
+	Telemetry         otel.Config
+	Log               logger.Config
+	CORS              *config.Live[config.CORS]
+	Security          config.SecurityHeaders
+	Cache             cache.Config
+	RateLimit         *config.Live[config.RateLimit]
+	Index             repository.Config
+	BrokerURL         string
+	BrokerExchange    string
+	CatalogServiceURL string
+	UserServiceURL    string
+	InternalToken     string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8087", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Index: repository.Config{
+			URL:         src.String("SEARCH_ENGINE_URL", "http://localhost:9200"),
+			Username:    src.String("SEARCH_ENGINE_USERNAME", ""),
+			Password:    src.String("SEARCH_ENGINE_PASSWORD", ""),
+			IndexPrefix: src.String("SEARCH_INDEX_PREFIX", "ecommerce"),
+			Timeout:     src.Duration("SEARCH_ENGINE_TIMEOUT_MS", 5000, time.Millisecond),
+		},
+		BrokerURL:         src.Required("BROKER_URL"),
+		BrokerExchange:    src.String("BROKER_EXCHANGE", events.DefaultExchange),
+		CatalogServiceURL: src.String("CATALOG_SERVICE_URL", "http://localhost:9092"),
+		UserServiceURL:    src.String("USER_SERVICE_URL", "http://localhost:9091"),
+		InternalToken:     src.String("INTERNAL_API_TOKEN", ""),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "search", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+type Category struct {
+	ID   int
+	UUID string
+	Name string
+	Slug string
+}
+
+// Product is the indexed document: the catalog product with its category denormalized
+type Product struct {
+	ID            int
+	UUID          string
+	Name          string
+	Description   string
+	SKU           string
+	Price         float64
+	InStock       bool
+	CategoryID    int
+	CategoryName  string
+	CategorySlug  string
+	ImageURL      string
+	IsActive      bool
+	RatingAverage float64
+	RatingCount   int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// SortField orders the results by an indexed field; without any the best matches come first
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a product search. Nil filters are not applied.
+type Query struct {
+	Text       string
+	CategoryID *int
+	MinPrice   *float64
+	MaxPrice   *float64
+	MinRating  *float64
+	InStock    bool
+	Sort       []SortField
+	Offset     int
+	Limit      int
+}
+
+// FacetBucket counts the matching products with one value of a facet
+type FacetBucket struct {
+	Value string
+	Label string
+	Count int64
+}
+
+// RangeBucket counts the matching products in a range; a nil bound is open
+type RangeBucket struct {
+	Key   string
+	From  *float64
+	To    *float64
+	Count int64
+}
+
+// Facets are counted over the products matching the text and every filter except the
+// facet's own, so selecting a category still shows how many products the others have
+type Facets struct {
+	Categories   []FacetBucket
+	PriceRanges  []RangeBucket
+	Ratings      []RangeBucket
+	InStockCount int64
+}
+
+type SearchResult struct {
+	Products []Product
+	Total    int64
+	Facets   Facets
+}
+
+// Suggestion is a product name completing what the customer typed
+type Suggestion struct {
+	ProductID int
+	UUID      string
+	Name      string
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/services/search/domain"
+	"ecommerce-microservice-go/services/search/usecase"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxResultWindow is how deep the search engine pages by default (index.max_result_window)
+	maxResultWindow  = 10000
+	maxQueryLength   = 200
+	defaultSuggested = 5
+	maxSuggested     = 10
+)
+
+type ResponseProduct struct {
+	ID            int       `json:"id"`
+	UUID          string    `json:"uuid"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	SKU           string    `json:"sku"`
+	Price         float64   `json:"price"`
+	InStock       bool      `json:"inStock"`
+	CategoryID    int       `json:"categoryId"`
+	CategoryName  string    `json:"categoryName"`
+	CategorySlug  string    `json:"categorySlug"`
+	ImageURL      string    `json:"imageUrl"`
+	RatingAverage float64   `json:"ratingAverage"`
+	RatingCount   int       `json:"ratingCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type ResponseFacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type ResponseRangeBucket struct {
+	Key   string   `json:"key"`
+	From  *float64 `json:"from,omitempty"`
+	To    *float64 `json:"to,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// ResponseFacets count the matches per value, ignoring the facet's own filter
+type ResponseFacets struct {
+	Categories  []ResponseFacetBucket `json:"categories"`
+	PriceRanges []ResponseRangeBucket `json:"priceRanges"`
+	// Ratings count products rated at least Key stars
+	Ratings []ResponseRangeBucket `json:"ratings"`
+	InStock int64                 `json:"inStock"`
+}
+
+type ResponseSearch struct {
+	Data   []ResponseProduct `json:"data"`
+	Meta   pagination.Meta   `json:"meta"`
+	Facets ResponseFacets    `json:"facets"`
+}
+
+// searchBody is a ResponseSearch whose products may have been trimmed
+type searchBody struct {
+	Data   any             `json:"data"`
+	Meta   pagination.Meta `json:"meta"`
+	Facets ResponseFacets  `json:"facets"`
+}
+
+type ResponseSuggestion struct {
+	ProductID int    `json:"productId"`
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+}
+
+type Handler struct {
+	searchUC usecase.ISearchUseCase
+	Logger   *logger.Logger
+}
+
+func NewHandler(s usecase.ISearchUseCase, l *logger.Logger) *Handler {
+	return &Handler{searchUC: s, Logger: l}
+}
+
+// searchSpec maps the sort names clients use to indexed fields; without sort the best
+// matches come first
+var searchSpec = pagination.Spec{
+	Sortable: map[string]string{"price": "price", "rating": "ratingAverage", "name": "name.keyword", "createdAt": "createdAt"},
+}
+
+// Search godoc
+// @Summary      Search products
+// @Tags         Search
+// @Description  Full-text search over the active products with category, price, rating and stock facets.
+// @Description  Each facet is counted with every filter applied except its own.
+// @Param        q          query string false "Text to search in name, SKU, category and description"
+// @Param        categoryId query int    false "Filter by category ID"
+// @Param        minPrice   query number false "Lowest price"
+// @Param        maxPrice   query number false "Highest price"
+// @Param        minRating  query number false "Lowest average rating"
+// @Param        inStock    query bool   false "Only products in stock"
+// @Param        page       query int    false "Page number" default(1)
+// @Param        limit      query int    false "Page size" default(20)
+// @Param        sort       query string false "Comma separated fields, prefix - for descending (price, rating, name, createdAt); relevance by default"
+// @Param        fields     query string false "Comma separated fields of each product to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseSearch
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /search [get]
+func (h *Handler) Search(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, searchSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	q, err := parseQuery(ctx, p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	result, err := h.searchUC.Search(ctx.Request.Context(), q)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	products := make([]ResponseProduct, len(result.Products))
+	for i := range result.Products {
+		products[i] = productToResponse(&result.Products[i])
+	}
+	page := pagination.NewResponse(products, result.Total, p)
+	// ?fields= trims the products, not the facets next to them
+	data, err := controllers.Project[ResponseProduct](ctx, page.Data)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, searchBody{Data: data, Meta: page.Meta, Facets: facetsToResponse(result.Facets)})
+}
+
+// Suggest godoc
+// @Summary      Suggest products as the customer types
+// @Tags         Search
+// @Param        q      query string true  "What the customer typed so far"
+// @Param        limit  query int    false "Number of suggestions" default(5)
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponseSuggestion
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /search/suggest [get]
+func (h *Handler) Suggest(ctx *gin.Context) {
+	prefix := strings.TrimSpace(ctx.Query("q"))
+	if prefix == "" || len(prefix) > maxQueryLength {
+		_ = ctx.Error(domainErrors.NewAppError(fmt.Errorf("q must be between 1 and %d characters", maxQueryLength), domainErrors.ValidationError))
+		return
+	}
+	limit := defaultSuggested
+	if v := ctx.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSuggested {
+			_ = ctx.Error(domainErrors.NewAppError(fmt.Errorf("limit must be between 1 and %d", maxSuggested), domainErrors.ValidationError))
+			return
+		}
+		limit = n
+	}
+	suggestions, err := h.searchUC.Suggest(ctx.Request.Context(), prefix, limit)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseSuggestion, len(suggestions))
+	for i, s := range suggestions {
+		res[i] = ResponseSuggestion{ProductID: s.ProductID, UUID: s.UUID, Name: s.Name}
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// Reindex godoc
+// @Summary      Rebuild the index from the catalog
+// @Tags         Search
+// @Description  Internal: copies every category and active product from the catalog service in the background, of the X-Tenant-ID tenant or, without one, of every active tenant.
+// @Success      202
+// @Router       /internal/reindex [post]
+func (h *Handler) Reindex(ctx *gin.Context) {
+	// a large catalog takes longer than the server's write timeout
+	reindexCtx := context.WithoutCancel(ctx.Request.Context())
+	go func() {
+		if _, err := h.searchUC.Reindex(reindexCtx); err != nil {
+			h.Logger.Error("Reindex failed", zap.Error(err))
+		}
+	}()
+	ctx.Status(http.StatusAccepted)
+}
+
+// parseQuery reads the text and filters, reporting every invalid parameter at once
+func parseQuery(ctx *gin.Context, p pagination.Params) (domain.Query, error) {
+	q := domain.Query{Text: strings.TrimSpace(ctx.Query("q")), Offset: p.Offset(), Limit: p.Limit}
+	var details []domainErrors.FieldError
+	if len(q.Text) > maxQueryLength {
+		details = append(details, domainErrors.FieldError{Field: "q", Rule: "max", Message: fmt.Sprintf("q must be at most %d characters", maxQueryLength)})
+	}
+	if p.Offset()+p.Limit > maxResultWindow {
+		details = append(details, domainErrors.FieldError{Field: "page", Rule: "max", Message: fmt.Sprintf("only the first %d results can be paged through; narrow the search", maxResultWindow)})
+	}
+	if v := ctx.Query("categoryId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			details = append(details, domainErrors.FieldError{Field: "categoryId", Rule: "gt", Message: "categoryId must be a positive whole number"})
+		} else {
+			q.CategoryID = &id
+		}
+	}
+	number := func(name string, dest **float64) {
+		v := ctx.Query(name)
+		if v == "" {
+			return
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			details = append(details, domainErrors.FieldError{Field: name, Rule: "min", Message: name + " must be a number of at least 0"})
+			return
+		}
+		*dest = &n
+	}
+	number("minPrice", &q.MinPrice)
+	number("maxPrice", &q.MaxPrice)
+	number("minRating", &q.MinRating)
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		details = append(details, domainErrors.FieldError{Field: "maxPrice", Rule: "gtefield", Message: "maxPrice must not be lower than minPrice"})
+	}
+	if v := ctx.Query("inStock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			details = append(details, domainErrors.FieldError{Field: "inStock", Rule: "boolean", Message: "inStock must be true or false"})
+		}
+		q.InStock = inStock
+	}
+	for _, s := range p.Sort {
+		q.Sort = append(q.Sort, domain.SortField{Field: s.Column, Desc: s.Desc})
+	}
+	// browsing without text shows the newest products first
+	if len(q.Sort) == 0 && q.Text == "" {
+		q.Sort = []domain.SortField{{Field: "createdAt", Desc: true}}
+	}
+	if len(details) > 0 {
+		return domain.Query{}, domainErrors.NewValidationError(errors.New("invalid search parameters"), details...)
+	}
+	return q, nil
+}
+
+// Mappers
+func productToResponse(p *domain.Product) ResponseProduct {
+	return ResponseProduct{
+		ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, InStock: p.InStock,
+		CategoryID: p.CategoryID, CategoryName: p.CategoryName, CategorySlug: p.CategorySlug, ImageURL: p.ImageURL,
+		RatingAverage: p.RatingAverage, RatingCount: p.RatingCount, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+}
+
+func facetsToResponse(f domain.Facets) ResponseFacets {
+	res := ResponseFacets{
+		Categories:  make([]ResponseFacetBucket, len(f.Categories)),
+		PriceRanges: rangesToResponse(f.PriceRanges),
+		Ratings:     rangesToResponse(f.Ratings),
+		InStock:     f.InStockCount,
+	}
+	for i, b := range f.Categories {
+		res.Categories[i] = ResponseFacetBucket{Value: b.Value, Label: b.Label, Count: b.Count}
+	}
+	return res
+}
+
+func rangesToResponse(buckets []domain.RangeBucket) []ResponseRangeBucket {
+	res := make([]ResponseRangeBucket, len(buckets))
+	for i, b := range buckets {
+		res[i] = ResponseRangeBucket{Key: b.Key, From: b.From, To: b.To, Count: b.Count}
+	}
+	return res
+}
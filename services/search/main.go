@@ -0,0 +1,161 @@
+// @title           Search Service API
+// @version         1.0.0
+// @description     Search microservice: full-text product search with facets and suggestions, indexed from catalog events
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/contracts"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/search/client"
+	"ecommerce-microservice-go/services/search/handler"
+	"ecommerce-microservice-go/services/search/repository"
+	"ecommerce-microservice-go/services/search/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	_ "ecommerce-microservice-go/services/search/docs"
+)
+
+const service app.Service = "search"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Search Service")
+
+	if len(args) > 0 && args[0] == "reindex" {
+		err := app.RunCommand(service, log, cfg.Server, provide(cfg), func(r repository.IndexRepositoryInterface, uc usecase.ISearchUseCase) error {
+			if _, err := r.EnsureIndices(context.Background()); err != nil {
+				return err
+			}
+			_, err := uc.Reindex(context.Background())
+			return err
+		})
+		if err != nil {
+			log.Fatal("Reindex failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(ensureIndices),
+		fx.Invoke(func(lc fx.Lifecycle, subscriber events.Subscriber, uc usecase.ISearchUseCase) {
+			app.Subscribe(lc, subscriber, "search.catalog-index", contracts.Types[contracts.Descriptor](
+				contracts.CategoryUpserted, contracts.CategoryDeleted, contracts.ProductUpserted, contracts.ProductDeleted,
+			), uc.HandleCatalogEvent)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Search Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// ensureIndices creates missing indices. A new index starts from the catalog's current state,
+// reindexed in the background; events keep it up to date from there.
+func ensureIndices(lc fx.Lifecycle, r repository.IndexRepositoryInterface, uc usecase.ISearchUseCase, log *logger.Logger) error {
+	created, err := r.EnsureIndices(context.Background())
+	if err != nil {
+		return fmt.Errorf("creating search indices: %w", err)
+	}
+	if created {
+		app.Go(lc, func(ctx context.Context) {
+			if _, err := uc.Reindex(ctx); err != nil {
+				log.Error("Initial reindex failed, run \"search-service reindex\" to retry", zap.Error(err))
+			}
+		})
+	}
+	return nil
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Cache, cfg.Index),
+		fx.Provide(
+			app.Telemetry,
+			app.Cache,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Subscriber, error) {
+				return app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			func() client.ICatalogClient { return client.NewCatalogClient(cfg.CatalogServiceURL) },
+			func() client.IUserClient { return client.NewUserClient(cfg.UserServiceURL, cfg.InternalToken) },
+			repository.NewIndexRepository,
+			usecase.NewSearchUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, indexRepo repository.IndexRepositoryInterface, cacheStore cache.Store,
+	subscriber events.Subscriber, h *handler.Handler) *gin.Engine {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("search", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("search"))
+	router.Use(middleware.ErrorHandler(log))
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("search").
+		Add("search-engine", func(ctx context.Context) (any, error) { return nil, indexRepo.Ping(ctx) }).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(subscriber)).
+		RegisterRoutes(v1)
+
+	v1.GET("/search/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	search := v1.Group("/search")
+	search.Use(middleware.RateLimit(cacheStore, "search", cfg.RateLimit, middleware.KeyByIP, log))
+	{
+		search.GET("", h.Search)
+		search.GET("/suggest", h.Suggest)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+		internal.POST("/reindex", h.Reindex)
+	}
+
+	return router
+}
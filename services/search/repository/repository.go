@@ -0,0 +1,590 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/search/domain"
+
+	"go.uber.org/zap"
+)
+
+// Config points at an Elasticsearch or OpenSearch cluster; only the REST API both share is used
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	// IndexPrefix names the indices, e.g. "ecommerce" uses ecommerce-products and ecommerce-categories
+	IndexPrefix string
+	Timeout     time.Duration
+}
+
+// PriceRanges are the buckets of the price facet
+var PriceRanges = []domain.RangeBucket{
+	{Key: "0-25", To: ptr(25.0)},
+	{Key: "25-50", From: ptr(25.0), To: ptr(50.0)},
+	{Key: "50-100", From: ptr(50.0), To: ptr(100.0)},
+	{Key: "100-250", From: ptr(100.0), To: ptr(250.0)},
+	{Key: "250+", From: ptr(250.0)},
+}
+
+// RatingRanges are the buckets of the rating facet: "4 stars and up" and so on
+var RatingRanges = []domain.RangeBucket{
+	{Key: "4", From: ptr(4.0)},
+	{Key: "3", From: ptr(3.0)},
+	{Key: "2", From: ptr(2.0)},
+	{Key: "1", From: ptr(1.0)},
+}
+
+// IndexRepositoryInterface stores products and categories in the search engine. Writes take
+// an external version, the time the change happened in the catalog: a write older than the
+// stored document is ignored, so events applied out of order cannot bring back stale data.
+// Documents belong to the tenant of ctx, the default tenant when it has none, and reads only
+// see that tenant's documents.
+type IndexRepositoryInterface interface {
+	// EnsureIndices creates the indices that do not exist yet, adds fields missing from the
+	// existing ones and reports whether it did either, after which the indices need a reindex
+	EnsureIndices(ctx context.Context) (bool, error)
+	UpsertCategory(ctx context.Context, c *domain.Category, version int64) error
+	GetCategory(ctx context.Context, id int) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int, version int64) error
+	// UpdateProductsCategory copies the category's name and slug to its indexed products
+	UpdateProductsCategory(ctx context.Context, c *domain.Category) error
+	UpsertProduct(ctx context.Context, p *domain.Product, version int64) error
+	DeleteProduct(ctx context.Context, id int, version int64) error
+	Search(ctx context.Context, q domain.Query) (*domain.SearchResult, error)
+	// Suggest returns active products whose name starts with or contains words starting with prefix
+	Suggest(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+	Ping(ctx context.Context) error
+}
+
+type IndexRepository struct {
+	config Config
+	http   *http.Client
+	Logger *logger.Logger
+}
+
+func NewIndexRepository(cfg Config, l *logger.Logger) IndexRepositoryInterface {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return &IndexRepository{config: cfg, http: &http.Client{Timeout: cfg.Timeout, Transport: otel.Transport(nil)}, Logger: l}
+}
+
+type productDocument struct {
+	ID            int       `json:"id"`
+	TenantID      string    `json:"tenantId"`
+	UUID          string    `json:"uuid"`
+	Name          string    `json:"name"`
+	Suggest       string    `json:"suggest"`
+	Description   string    `json:"description"`
+	SKU           string    `json:"sku"`
+	Price         float64   `json:"price"`
+	InStock       bool      `json:"inStock"`
+	CategoryID    int       `json:"categoryId"`
+	CategoryName  string    `json:"categoryName"`
+	CategorySlug  string    `json:"categorySlug"`
+	ImageURL      string    `json:"imageUrl"`
+	IsActive      bool      `json:"isActive"`
+	RatingAverage float64   `json:"ratingAverage"`
+	RatingCount   int       `json:"ratingCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type categoryDocument struct {
+	ID       int    `json:"id"`
+	TenantID string `json:"tenantId"`
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+var productMapping = map[string]any{
+	"settings": map[string]any{
+		"analysis": map[string]any{
+			"analyzer": map[string]any{
+				"folding": map[string]any{"tokenizer": "standard", "filter": []string{"lowercase", "asciifolding"}},
+			},
+		},
+	},
+	"mappings": map[string]any{
+		"dynamic": "strict",
+		"properties": map[string]any{
+			"id":            map[string]any{"type": "integer"},
+			"tenantId":      map[string]any{"type": "keyword"},
+			"uuid":          map[string]any{"type": "keyword"},
+			"name":          map[string]any{"type": "text", "analyzer": "folding", "fields": map[string]any{"keyword": map[string]any{"type": "keyword"}}},
+			"suggest":       map[string]any{"type": "search_as_you_type", "analyzer": "folding"},
+			"description":   map[string]any{"type": "text", "analyzer": "folding"},
+			"sku":           map[string]any{"type": "keyword", "normalizer": "lowercase"},
+			"price":         map[string]any{"type": "double"},
+			"inStock":       map[string]any{"type": "boolean"},
+			"categoryId":    map[string]any{"type": "integer"},
+			"categoryName":  map[string]any{"type": "text", "analyzer": "folding", "fields": map[string]any{"keyword": map[string]any{"type": "keyword"}}},
+			"categorySlug":  map[string]any{"type": "keyword"},
+			"imageUrl":      map[string]any{"type": "keyword", "index": false},
+			"isActive":      map[string]any{"type": "boolean"},
+			"ratingAverage": map[string]any{"type": "float"},
+			"ratingCount":   map[string]any{"type": "integer"},
+			"createdAt":     map[string]any{"type": "date"},
+			"updatedAt":     map[string]any{"type": "date"},
+		},
+	},
+}
+
+var categoryMapping = map[string]any{
+	"mappings": map[string]any{
+		"dynamic": "strict",
+		"properties": map[string]any{
+			"id":       map[string]any{"type": "integer"},
+			"tenantId": map[string]any{"type": "keyword"},
+			"uuid":     map[string]any{"type": "keyword"},
+			"name":     map[string]any{"type": "keyword"},
+			"slug":     map[string]any{"type": "keyword"},
+		},
+	},
+}
+
+func (r *IndexRepository) productIndex() string  { return r.config.IndexPrefix + "-products" }
+func (r *IndexRepository) categoryIndex() string { return r.config.IndexPrefix + "-categories" }
+
+// documentTenant is the tenant the documents of ctx belong to. Changes the system made
+// without a tenant come from the default store.
+func documentTenant(ctx context.Context) string {
+	if t := tenant.FromContext(ctx); t != "" {
+		return t
+	}
+	return tenant.Default
+}
+
+// tenantFilter keeps a query to the documents of ctx's tenant
+func tenantFilter(ctx context.Context) map[string]any {
+	return map[string]any{"term": map[string]any{"tenantId": documentTenant(ctx)}}
+}
+
+func (r *IndexRepository) EnsureIndices(ctx context.Context) (bool, error) {
+	created := false
+	for index, mapping := range map[string]any{r.productIndex(): productMapping, r.categoryIndex(): categoryMapping} {
+		status, err := r.do(ctx, http.MethodHead, "/"+index, nil, nil)
+		if err != nil {
+			return false, err
+		}
+		if status == http.StatusOK {
+			added, err := r.addTenantField(ctx, index)
+			if err != nil {
+				return false, err
+			}
+			created = created || added
+			continue
+		}
+		status, err = r.do(ctx, http.MethodPut, "/"+index, mapping, nil)
+		if err != nil {
+			return false, err
+		}
+		// another replica may have created it in between
+		if status == http.StatusBadRequest {
+			continue
+		}
+		if status != http.StatusOK {
+			return false, fmt.Errorf("search engine returned %d creating index %s", status, index)
+		}
+		r.Logger.Info("Created search index", zap.String("index", index))
+		created = true
+	}
+	return created, nil
+}
+
+// addTenantField adds tenantId to an index created before documents had a tenant. Its
+// documents have none and stay out of every query until they are reindexed.
+func (r *IndexRepository) addTenantField(ctx context.Context, index string) (bool, error) {
+	var res map[string]struct {
+		Mappings map[string]any `json:"mappings"`
+	}
+	status, err := r.do(ctx, http.MethodGet, "/"+index+"/_mapping/field/tenantId", nil, &res)
+	if err != nil {
+		return false, err
+	}
+	if status != http.StatusOK {
+		return false, fmt.Errorf("search engine returned %d reading the mapping of %s", status, index)
+	}
+	for _, m := range res {
+		if len(m.Mappings) > 0 {
+			return false, nil
+		}
+	}
+	body := map[string]any{"properties": map[string]any{"tenantId": map[string]any{"type": "keyword"}}}
+	status, err = r.do(ctx, http.MethodPut, "/"+index+"/_mapping", body, nil)
+	if err != nil {
+		return false, err
+	}
+	if status != http.StatusOK {
+		return false, fmt.Errorf("search engine returned %d adding the tenant to %s", status, index)
+	}
+	r.Logger.Info("Added the tenant to search index", zap.String("index", index))
+	return true, nil
+}
+
+func (r *IndexRepository) UpsertCategory(ctx context.Context, c *domain.Category, version int64) error {
+	return r.put(ctx, r.categoryIndex(), c.ID, version, categoryDocument{ID: c.ID, TenantID: documentTenant(ctx), UUID: c.UUID, Name: c.Name, Slug: c.Slug})
+}
+
+func (r *IndexRepository) GetCategory(ctx context.Context, id int) (*domain.Category, error) {
+	var res struct {
+		Source categoryDocument `json:"_source"`
+	}
+	status, err := r.do(ctx, http.MethodGet, "/"+r.categoryIndex()+"/_doc/"+strconv.Itoa(id), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound || (status == http.StatusOK && res.Source.TenantID != documentTenant(ctx)) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("search engine returned %d reading category %d", status, id)
+	}
+	return &domain.Category{ID: res.Source.ID, UUID: res.Source.UUID, Name: res.Source.Name, Slug: res.Source.Slug}, nil
+}
+
+func (r *IndexRepository) DeleteCategory(ctx context.Context, id int, version int64) error {
+	return r.delete(ctx, r.categoryIndex(), id, version)
+}
+
+func (r *IndexRepository) UpdateProductsCategory(ctx context.Context, c *domain.Category) error {
+	body := map[string]any{
+		"query": map[string]any{"bool": map[string]any{"filter": []any{
+			map[string]any{"term": map[string]any{"categoryId": c.ID}},
+			tenantFilter(ctx),
+		}}},
+		"script": map[string]any{
+			"source": "ctx._source.categoryName = params.name; ctx._source.categorySlug = params.slug",
+			"params": map[string]any{"name": c.Name, "slug": c.Slug},
+		},
+	}
+	status, err := r.do(ctx, http.MethodPost, "/"+r.productIndex()+"/_update_by_query?conflicts=proceed", body, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("search engine returned %d updating products of category %d", status, c.ID)
+	}
+	return nil
+}
+
+func (r *IndexRepository) UpsertProduct(ctx context.Context, p *domain.Product, version int64) error {
+	return r.put(ctx, r.productIndex(), p.ID, version, productDocument{
+		ID: p.ID, TenantID: documentTenant(ctx), UUID: p.UUID, Name: p.Name, Suggest: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price,
+		InStock: p.InStock, CategoryID: p.CategoryID, CategoryName: p.CategoryName, CategorySlug: p.CategorySlug,
+		ImageURL: p.ImageURL, IsActive: p.IsActive, RatingAverage: p.RatingAverage, RatingCount: p.RatingCount,
+		CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	})
+}
+
+func (r *IndexRepository) DeleteProduct(ctx context.Context, id int, version int64) error {
+	return r.delete(ctx, r.productIndex(), id, version)
+}
+
+// put indexes the document unless the stored one has the same or a newer version
+func (r *IndexRepository) put(ctx context.Context, index string, id int, version int64, doc any) error {
+	path := fmt.Sprintf("/%s/_doc/%d?version=%d&version_type=external", index, id, version)
+	status, err := r.do(ctx, http.MethodPut, path, doc, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	case http.StatusConflict:
+		r.Logger.Debug("Skipping stale document", zap.String("index", index), zap.Int("id", id), zap.Int64("version", version))
+		return nil
+	}
+	return fmt.Errorf("search engine returned %d indexing %s/%d", status, index, id)
+}
+
+func (r *IndexRepository) delete(ctx context.Context, index string, id int, version int64) error {
+	path := fmt.Sprintf("/%s/_doc/%d?version=%d&version_type=external", index, id, version)
+	status, err := r.do(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK, http.StatusNotFound, http.StatusConflict:
+		return nil
+	}
+	return fmt.Errorf("search engine returned %d deleting %s/%d", status, index, id)
+}
+
+type rangeBucketResult struct {
+	Key      string   `json:"key"`
+	From     *float64 `json:"from"`
+	To       *float64 `json:"to"`
+	DocCount int64    `json:"doc_count"`
+}
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source productDocument `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Categories struct {
+			Values struct {
+				Buckets []struct {
+					Key      int   `json:"key"`
+					DocCount int64 `json:"doc_count"`
+					Label    struct {
+						Buckets []struct {
+							Key string `json:"key"`
+						} `json:"buckets"`
+					} `json:"label"`
+				} `json:"buckets"`
+			} `json:"values"`
+		} `json:"categories"`
+		Prices struct {
+			Values struct {
+				Buckets []rangeBucketResult `json:"buckets"`
+			} `json:"values"`
+		} `json:"prices"`
+		Ratings struct {
+			Values struct {
+				Buckets []rangeBucketResult `json:"buckets"`
+			} `json:"values"`
+		} `json:"ratings"`
+		InStock struct {
+			DocCount int64 `json:"doc_count"`
+		} `json:"inStock"`
+	} `json:"aggregations"`
+}
+
+func (r *IndexRepository) Search(ctx context.Context, q domain.Query) (*domain.SearchResult, error) {
+	var res searchResponse
+	status, err := r.do(ctx, http.MethodPost, "/"+r.productIndex()+"/_search", searchBody(q, tenantFilter(ctx)), &res)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		r.Logger.Error("Search request failed", zap.Int("status", status), zap.String("text", q.Text))
+		return nil, fmt.Errorf("search engine returned %d searching products", status)
+	}
+	result := &domain.SearchResult{Total: res.Hits.Total.Value, Products: make([]domain.Product, len(res.Hits.Hits))}
+	for i, h := range res.Hits.Hits {
+		d := h.Source
+		result.Products[i] = domain.Product{
+			ID: d.ID, UUID: d.UUID, Name: d.Name, Description: d.Description, SKU: d.SKU, Price: d.Price, InStock: d.InStock,
+			CategoryID: d.CategoryID, CategoryName: d.CategoryName, CategorySlug: d.CategorySlug, ImageURL: d.ImageURL,
+			IsActive: d.IsActive, RatingAverage: d.RatingAverage, RatingCount: d.RatingCount, CreatedAt: d.CreatedAt, UpdatedAt: d.UpdatedAt,
+		}
+	}
+	aggs := res.Aggregations
+	for _, b := range aggs.Categories.Values.Buckets {
+		bucket := domain.FacetBucket{Value: strconv.Itoa(b.Key), Count: b.DocCount}
+		if len(b.Label.Buckets) > 0 {
+			bucket.Label = b.Label.Buckets[0].Key
+		}
+		result.Facets.Categories = append(result.Facets.Categories, bucket)
+	}
+	result.Facets.PriceRanges = rangeBuckets(aggs.Prices.Values.Buckets)
+	result.Facets.Ratings = rangeBuckets(aggs.Ratings.Values.Buckets)
+	result.Facets.InStockCount = aggs.InStock.DocCount
+	return result, nil
+}
+
+func rangeBuckets(buckets []rangeBucketResult) []domain.RangeBucket {
+	result := make([]domain.RangeBucket, len(buckets))
+	for i, b := range buckets {
+		result[i] = domain.RangeBucket{Key: b.Key, From: b.From, To: b.To, Count: b.DocCount}
+	}
+	return result
+}
+
+// searchBody applies the filters as a post_filter, after the aggregations, and gives every
+// facet all filters but its own. scope keeps the query to one tenant's active products.
+func searchBody(q domain.Query, scope map[string]any) map[string]any {
+	query := map[string]any{"match_all": map[string]any{}}
+	if q.Text != "" {
+		query = map[string]any{"multi_match": map[string]any{
+			"query":     q.Text,
+			"fields":    []string{"name^3", "sku^4", "categoryName^2", "description"},
+			"fuzziness": "AUTO",
+			"operator":  "and",
+		}}
+	}
+	type facetFilter struct {
+		facet  string
+		clause any
+	}
+	var filters []facetFilter
+	if q.CategoryID != nil {
+		filters = append(filters, facetFilter{"category", map[string]any{"term": map[string]any{"categoryId": *q.CategoryID}}})
+	}
+	if q.MinPrice != nil || q.MaxPrice != nil {
+		bounds := map[string]any{}
+		if q.MinPrice != nil {
+			bounds["gte"] = *q.MinPrice
+		}
+		if q.MaxPrice != nil {
+			bounds["lte"] = *q.MaxPrice
+		}
+		filters = append(filters, facetFilter{"price", map[string]any{"range": map[string]any{"price": bounds}}})
+	}
+	if q.MinRating != nil {
+		filters = append(filters, facetFilter{"rating", map[string]any{"range": map[string]any{"ratingAverage": map[string]any{"gte": *q.MinRating}}}})
+	}
+	inStock := map[string]any{"term": map[string]any{"inStock": true}}
+	if q.InStock {
+		filters = append(filters, facetFilter{"inStock", inStock})
+	}
+	without := func(facet string) []any {
+		clauses := []any{}
+		for _, f := range filters {
+			if f.facet != facet {
+				clauses = append(clauses, f.clause)
+			}
+		}
+		return clauses
+	}
+	all := func(clauses []any) map[string]any {
+		return map[string]any{"bool": map[string]any{"filter": clauses}}
+	}
+
+	sort := []any{}
+	for _, s := range q.Sort {
+		order := "asc"
+		if s.Desc {
+			order = "desc"
+		}
+		sort = append(sort, map[string]any{s.Field: map[string]any{"order": order}})
+	}
+	sort = append(sort, "_score", map[string]any{"id": map[string]any{"order": "asc"}})
+
+	return map[string]any{
+		"from":             q.Offset,
+		"size":             q.Limit,
+		"track_total_hits": true,
+		"sort":             sort,
+		"query": map[string]any{"bool": map[string]any{
+			"must":   query,
+			"filter": []any{map[string]any{"term": map[string]any{"isActive": true}}, scope},
+		}},
+		"post_filter": all(without("")),
+		"aggs": map[string]any{
+			"categories": map[string]any{"filter": all(without("category")), "aggs": map[string]any{"values": map[string]any{
+				"terms": map[string]any{"field": "categoryId", "size": 50},
+				"aggs":  map[string]any{"label": map[string]any{"terms": map[string]any{"field": "categoryName.keyword", "size": 1}}},
+			}}},
+			"prices": map[string]any{"filter": all(without("price")), "aggs": map[string]any{"values": map[string]any{
+				"range": map[string]any{"field": "price", "ranges": rangeSpecs(PriceRanges)},
+			}}},
+			"ratings": map[string]any{"filter": all(without("rating")), "aggs": map[string]any{"values": map[string]any{
+				"range": map[string]any{"field": "ratingAverage", "ranges": rangeSpecs(RatingRanges)},
+			}}},
+			"inStock": map[string]any{"filter": all(append(without("inStock"), inStock))},
+		},
+	}
+}
+
+func rangeSpecs(buckets []domain.RangeBucket) []any {
+	specs := make([]any, len(buckets))
+	for i, b := range buckets {
+		spec := map[string]any{"key": b.Key}
+		if b.From != nil {
+			spec["from"] = *b.From
+		}
+		if b.To != nil {
+			spec["to"] = *b.To
+		}
+		specs[i] = spec
+	}
+	return specs
+}
+
+func (r *IndexRepository) Suggest(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	body := map[string]any{
+		"size":    limit,
+		"_source": []string{"id", "uuid", "name"},
+		"query": map[string]any{"bool": map[string]any{
+			"must": map[string]any{"multi_match": map[string]any{
+				"query":  prefix,
+				"type":   "bool_prefix",
+				"fields": []string{"suggest", "suggest._2gram", "suggest._3gram"},
+			}},
+			"filter": []any{map[string]any{"term": map[string]any{"isActive": true}}, tenantFilter(ctx)},
+		}},
+	}
+	var res searchResponse
+	status, err := r.do(ctx, http.MethodPost, "/"+r.productIndex()+"/_search", body, &res)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("search engine returned %d suggesting products", status)
+	}
+	suggestions := make([]domain.Suggestion, len(res.Hits.Hits))
+	for i, h := range res.Hits.Hits {
+		suggestions[i] = domain.Suggestion{ProductID: h.Source.ID, UUID: h.Source.UUID, Name: h.Source.Name}
+	}
+	return suggestions, nil
+}
+
+func (r *IndexRepository) Ping(ctx context.Context) error {
+	status, err := r.do(ctx, http.MethodGet, "/", nil, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("search engine returned %d", status)
+	}
+	return nil
+}
+
+// do sends body as JSON and decodes a 2xx answer into out. It returns the status code; an
+// error means the engine could not be reached or answered with something unreadable.
+func (r *IndexRepository) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.config.URL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.config.Username != "" {
+		req.SetBasicAuth(r.config.Username, r.config.Password)
+	}
+	res, err := r.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("search engine request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if out != nil && res.StatusCode >= 200 && res.StatusCode < 300 {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return 0, err
+		}
+	}
+	return res.StatusCode, nil
+}
+
+func ptr(v float64) *float64 { return &v }
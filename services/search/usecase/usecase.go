@@ -0,0 +1,168 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/search/client"
+	"ecommerce-microservice-go/services/search/domain"
+	"ecommerce-microservice-go/services/search/repository"
+
+	"go.uber.org/zap"
+)
+
+// reindexPageSize is the largest page the catalog listings return
+const reindexPageSize = 100
+
+type ISearchUseCase interface {
+	Search(ctx context.Context, q domain.Query) (*domain.SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+	// HandleCatalogEvent applies a product or category change to the index
+	HandleCatalogEvent(ctx context.Context, event *events.Event) error
+	// Reindex copies every category and active product from the catalog service into the index
+	// and returns the number of products indexed. Changes made meanwhile are kept, since the
+	// copy of each page is versioned at the time it was read. A ctx with a tenant reindexes
+	// that tenant's catalog, one without reindexes every active tenant's.
+	Reindex(ctx context.Context) (int, error)
+}
+
+type SearchUseCase struct {
+	repo          repository.IndexRepositoryInterface
+	catalogClient client.ICatalogClient
+	userClient    client.IUserClient
+	Logger        *logger.Logger
+}
+
+func NewSearchUseCase(r repository.IndexRepositoryInterface, catalogClient client.ICatalogClient, userClient client.IUserClient, l *logger.Logger) ISearchUseCase {
+	return &SearchUseCase{repo: r, catalogClient: catalogClient, userClient: userClient, Logger: l}
+}
+
+func (s *SearchUseCase) Search(ctx context.Context, q domain.Query) (*domain.SearchResult, error) {
+	s.Logger.Info("Searching products", zap.String("text", q.Text), zap.Int("offset", q.Offset), zap.Int("limit", q.Limit))
+	return s.repo.Search(ctx, q)
+}
+
+func (s *SearchUseCase) Suggest(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	return s.repo.Suggest(ctx, prefix, limit)
+}
+
+func (s *SearchUseCase) HandleCatalogEvent(ctx context.Context, event *events.Event) error {
+	// the catalog builds each event after locking the row it changed, so for one record
+	// OccurredAt follows the order of the changes
+	version := event.OccurredAt.UnixNano()
+	switch event.Type {
+	case contracts.CategoryUpserted.EventType(), contracts.CategoryDeleted.EventType():
+		c, err := contracts.DecodeAny(event, contracts.CategoryUpserted, contracts.CategoryDeleted)
+		if err != nil {
+			s.Logger.Error("Discarding category event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+			return nil
+		}
+		if event.Type == contracts.CategoryDeleted.EventType() {
+			s.Logger.Info("Removing category from index", zap.Int("categoryID", c.ID))
+			return s.repo.DeleteCategory(ctx, c.ID, version)
+		}
+		s.Logger.Info("Indexing category", zap.Int("categoryID", c.ID))
+		return s.indexCategory(ctx, c, version)
+	case contracts.ProductUpserted.EventType(), contracts.ProductDeleted.EventType():
+		p, err := contracts.DecodeAny(event, contracts.ProductUpserted, contracts.ProductDeleted)
+		if err != nil {
+			s.Logger.Error("Discarding product event with invalid payload", zap.String("id", event.ID), zap.Error(err))
+			return nil
+		}
+		if event.Type == contracts.ProductDeleted.EventType() {
+			s.Logger.Info("Removing product from index", zap.Int("productID", p.ID))
+			return s.repo.DeleteProduct(ctx, p.ID, version)
+		}
+		s.Logger.Info("Indexing product", zap.Int("productID", p.ID))
+		return s.indexProduct(ctx, p, version)
+	}
+	return nil
+}
+
+func (s *SearchUseCase) Reindex(ctx context.Context) (int, error) {
+	if tenant.FromContext(ctx) != "" {
+		return s.reindexTenant(ctx)
+	}
+	tenants, err := s.userClient.GetTenants(ctx)
+	if err != nil {
+		return 0, err
+	}
+	indexed := 0
+	for _, t := range tenants {
+		n, err := s.reindexTenant(tenant.ContextWithTenant(ctx, t))
+		indexed += n
+		if err != nil {
+			return indexed, err
+		}
+	}
+	return indexed, nil
+}
+
+func (s *SearchUseCase) reindexTenant(ctx context.Context) (int, error) {
+	s.Logger.Info("Reindexing catalog", zap.String("tenant", tenant.FromContext(ctx)))
+	for page, pages := 1, 1; page <= pages; page++ {
+		version := time.Now().UnixNano()
+		categories, total, err := s.catalogClient.GetCategories(ctx, page, reindexPageSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range categories {
+			if err := s.indexCategory(ctx, c, version); err != nil {
+				return 0, err
+			}
+		}
+		pages = total
+	}
+	indexed := 0
+	for page, pages := 1, 1; page <= pages; page++ {
+		version := time.Now().UnixNano()
+		products, total, err := s.catalogClient.GetProducts(ctx, page, reindexPageSize)
+		if err != nil {
+			return indexed, err
+		}
+		for _, p := range products {
+			if err := s.indexProduct(ctx, p, version); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		pages = total
+	}
+	s.Logger.Info("Catalog reindexed", zap.String("tenant", tenant.FromContext(ctx)), zap.Int("products", indexed))
+	return indexed, nil
+}
+
+func (s *SearchUseCase) indexCategory(ctx context.Context, c contracts.Category, version int64) error {
+	category := &domain.Category{ID: c.ID, UUID: c.UUID, Name: c.Name, Slug: c.Slug}
+	if err := s.repo.UpsertCategory(ctx, category, version); err != nil {
+		return err
+	}
+	return s.repo.UpdateProductsCategory(ctx, category)
+}
+
+func (s *SearchUseCase) indexProduct(ctx context.Context, p contracts.Product, version int64) error {
+	product := &domain.Product{
+		ID: p.ID, UUID: p.UUID, Name: p.Name, Description: p.Description, SKU: p.SKU, Price: p.Price, InStock: p.Stock > 0,
+		CategoryID: p.CategoryID, ImageURL: p.ImageURL, IsActive: p.IsActive, RatingAverage: p.RatingAverage,
+		RatingCount: p.RatingCount, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+	}
+	category, err := s.repo.GetCategory(ctx, p.CategoryID)
+	switch {
+	case err == nil:
+		product.CategoryName, product.CategorySlug = category.Name, category.Slug
+	case errors.Is(err, domainErrors.ErrNotFound):
+		// filled in when the category event arrives
+		s.Logger.Warn("Indexing product of unknown category", zap.Int("productID", p.ID), zap.Int("categoryID", p.CategoryID))
+	default:
+		return err
+	}
+	return s.repo.UpsertProduct(ctx, product, version)
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+	clientmocks "ecommerce-microservice-go/services/search/client/mocks"
+	"ecommerce-microservice-go/services/search/domain"
+	"ecommerce-microservice-go/services/search/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	errIndex   = errors.New("index unavailable")
+	tee        = contracts.Product{ID: 7, Name: "Basic Tee", SKU: "TEE-01", Price: 25.5, Stock: 10, CategoryID: 1, IsActive: true}
+	shirts     = contracts.Category{ID: 1, Name: "Shirts", Slug: "shirts"}
+)
+
+func newEvent[T any](t *testing.T, c contracts.Contract[T], payload T, at time.Time) *events.Event {
+	t.Helper()
+	event, err := c.NewEvent(context.Background(), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.OccurredAt = at
+	return event
+}
+
+// productNamed matches an indexed product with the category name
+type productNamed string
+
+func (c productNamed) Matches(x any) bool {
+	p, ok := x.(*domain.Product)
+	return ok && p.CategoryName == string(c)
+}
+
+func (c productNamed) String() string { return "is a product of category " + string(c) }
+
+// inTenant matches a context acting in the tenant
+type inTenant string
+
+func (t inTenant) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	return ok && tenant.FromContext(ctx) == string(t)
+}
+
+func (t inTenant) String() string { return "acts in tenant " + string(t) }
+
+func TestSearchUseCaseHandleCatalogEvent(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	version := at.UnixNano()
+	tests := []struct {
+		name    string
+		event   *events.Event
+		setup   func(repo *mocks.MockIndexRepositoryInterface)
+		wantErr error
+	}{
+		{
+			name:  "product indexed with its category at the event's version",
+			event: newEvent(t, contracts.ProductUpserted, tee, at),
+			setup: func(repo *mocks.MockIndexRepositoryInterface) {
+				repo.EXPECT().GetCategory(gomock.Any(), 1).Return(&domain.Category{ID: 1, Name: "Shirts", Slug: "shirts"}, nil)
+				repo.EXPECT().UpsertProduct(gomock.Any(), productNamed("Shirts"), version).Return(nil)
+			},
+		},
+		{
+			name:  "product of an unknown category is indexed without it",
+			event: newEvent(t, contracts.ProductUpserted, tee, at),
+			setup: func(repo *mocks.MockIndexRepositoryInterface) {
+				repo.EXPECT().GetCategory(gomock.Any(), 1).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+				repo.EXPECT().UpsertProduct(gomock.Any(), productNamed(""), version).Return(nil)
+			},
+		},
+		{
+			name:  "index failure is retried",
+			event: newEvent(t, contracts.ProductUpserted, tee, at),
+			setup: func(repo *mocks.MockIndexRepositoryInterface) {
+				repo.EXPECT().GetCategory(gomock.Any(), 1).Return(nil, errIndex)
+			},
+			wantErr: errIndex,
+		},
+		{
+			name:  "deleted product",
+			event: newEvent(t, contracts.ProductDeleted, contracts.Product{ID: 7}, at),
+			setup: func(repo *mocks.MockIndexRepositoryInterface) {
+				repo.EXPECT().DeleteProduct(gomock.Any(), 7, version).Return(nil)
+			},
+		},
+		{
+			name:  "renamed category is copied to its products",
+			event: newEvent(t, contracts.CategoryUpserted, shirts, at),
+			setup: func(repo *mocks.MockIndexRepositoryInterface) {
+				category := &domain.Category{ID: 1, Name: "Shirts", Slug: "shirts"}
+				repo.EXPECT().UpsertCategory(gomock.Any(), category, version).Return(nil)
+				repo.EXPECT().UpdateProductsCategory(gomock.Any(), category).Return(nil)
+			},
+		},
+		{
+			name:  "invalid payload is discarded",
+			event: &events.Event{ID: "1", Type: contracts.ProductUpserted.EventType(), Payload: json.RawMessage(`{"id":"seven"}`)},
+		},
+		{
+			name:  "other event types are ignored",
+			event: &events.Event{ID: "1", Type: contracts.UserDeleted.EventType()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockIndexRepositoryInterface(ctrl)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			uc := NewSearchUseCase(repo, clientmocks.NewMockICatalogClient(ctrl), clientmocks.NewMockIUserClient(ctrl), testLogger)
+			if err := uc.HandleCatalogEvent(context.Background(), tt.event); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchUseCaseReindex(t *testing.T) {
+	errCatalog := errors.New("catalog unavailable")
+	errUsers := errors.New("user service unavailable")
+	acme := tenant.ContextWithTenant(context.Background(), "acme")
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		setup       func(repo *mocks.MockIndexRepositoryInterface, catalog *clientmocks.MockICatalogClient, users *clientmocks.MockIUserClient)
+		wantIndexed int
+		wantErr     error
+	}{
+		{
+			name: "every page of the tenant is copied",
+			ctx:  acme,
+			setup: func(repo *mocks.MockIndexRepositoryInterface, catalog *clientmocks.MockICatalogClient, _ *clientmocks.MockIUserClient) {
+				catalog.EXPECT().GetCategories(inTenant("acme"), 1, reindexPageSize).Return([]contracts.Category{shirts}, 1, nil)
+				repo.EXPECT().UpsertCategory(inTenant("acme"), gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().UpdateProductsCategory(inTenant("acme"), gomock.Any()).Return(nil)
+				catalog.EXPECT().GetProducts(inTenant("acme"), 1, reindexPageSize).Return([]contracts.Product{tee, tee}, 2, nil)
+				catalog.EXPECT().GetProducts(inTenant("acme"), 2, reindexPageSize).Return([]contracts.Product{tee}, 2, nil)
+				repo.EXPECT().GetCategory(inTenant("acme"), 1).Return(&domain.Category{ID: 1, Name: "Shirts"}, nil).Times(3)
+				repo.EXPECT().UpsertProduct(inTenant("acme"), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+			},
+			wantIndexed: 3,
+		},
+		{
+			name: "catalog failure keeps what was indexed",
+			ctx:  acme,
+			setup: func(repo *mocks.MockIndexRepositoryInterface, catalog *clientmocks.MockICatalogClient, _ *clientmocks.MockIUserClient) {
+				catalog.EXPECT().GetCategories(gomock.Any(), 1, reindexPageSize).Return(nil, 1, nil)
+				catalog.EXPECT().GetProducts(gomock.Any(), 1, reindexPageSize).Return([]contracts.Product{tee}, 2, nil)
+				catalog.EXPECT().GetProducts(gomock.Any(), 2, reindexPageSize).Return(nil, 0, errCatalog)
+				repo.EXPECT().GetCategory(gomock.Any(), 1).Return(&domain.Category{ID: 1, Name: "Shirts"}, nil)
+				repo.EXPECT().UpsertProduct(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantIndexed: 1,
+			wantErr:     errCatalog,
+		},
+		{
+			name: "without a tenant every active tenant is copied",
+			ctx:  context.Background(),
+			setup: func(repo *mocks.MockIndexRepositoryInterface, catalog *clientmocks.MockICatalogClient, users *clientmocks.MockIUserClient) {
+				users.EXPECT().GetTenants(gomock.Any()).Return([]string{"default", "acme"}, nil)
+				for _, id := range []string{"default", "acme"} {
+					catalog.EXPECT().GetCategories(inTenant(id), 1, reindexPageSize).Return(nil, 1, nil)
+					catalog.EXPECT().GetProducts(inTenant(id), 1, reindexPageSize).Return([]contracts.Product{tee}, 1, nil)
+					repo.EXPECT().GetCategory(inTenant(id), 1).Return(&domain.Category{ID: 1, Name: "Shirts"}, nil)
+					repo.EXPECT().UpsertProduct(inTenant(id), gomock.Any(), gomock.Any()).Return(nil)
+				}
+			},
+			wantIndexed: 2,
+		},
+		{
+			name: "tenant listing failure indexes nothing",
+			ctx:  context.Background(),
+			setup: func(_ *mocks.MockIndexRepositoryInterface, _ *clientmocks.MockICatalogClient, users *clientmocks.MockIUserClient) {
+				users.EXPECT().GetTenants(gomock.Any()).Return(nil, errUsers)
+			},
+			wantErr: errUsers,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, catalog, users := mocks.NewMockIndexRepositoryInterface(ctrl), clientmocks.NewMockICatalogClient(ctrl), clientmocks.NewMockIUserClient(ctrl)
+			tt.setup(repo, catalog, users)
+			indexed, err := NewSearchUseCase(repo, catalog, users, testLogger).Reindex(tt.ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if indexed != tt.wantIndexed {
+				t.Errorf("indexed %d, want %d", indexed, tt.wantIndexed)
+			}
+		})
+	}
+}
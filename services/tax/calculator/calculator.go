@@ -0,0 +1,69 @@
+// Package calculator computes tax from rate tables. It is pure: the rates are passed in, so the
+// same request and rates always give the same tax.
+package calculator
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"ecommerce-microservice-go/services/tax/domain"
+)
+
+// Calculate taxes every line with the rates of its tax class in force at req.At. Each
+// jurisdiction's tax is rounded per line. With PricesIncludeTax the tax is extracted from the
+// line amount using the combined rate, and the rounding difference is left on the taxable amount.
+func Calculate(req domain.CalculationRequest, rates []domain.TaxRate) *domain.Calculation {
+	byClass := map[string][]domain.TaxRate{}
+	for _, r := range rates {
+		if r.InForceAt(req.At) {
+			byClass[r.TaxClass] = append(byClass[r.TaxClass], r)
+		}
+	}
+	for class := range byClass {
+		// country before region, so the order of the taxes is stable
+		slices.SortStableFunc(byClass[class], func(a, b domain.TaxRate) int {
+			return cmp.Or(cmp.Compare(a.Jurisdiction.Region, b.Jurisdiction.Region), cmp.Compare(a.ID, b.ID))
+		})
+	}
+
+	c := &domain.Calculation{Currency: req.Currency, At: req.At, Provider: "table", Lines: make([]domain.LineTax, len(req.Lines))}
+	for i, line := range req.Lines {
+		class := line.TaxClass
+		if class == "" {
+			class = domain.DefaultTaxClass
+		}
+		amount := round2(float64(line.Quantity)*line.UnitPrice - line.Discount)
+		classRates := byClass[class]
+		combined := 0.0
+		for _, r := range classRates {
+			combined += r.Rate
+		}
+		taxable := amount
+		if req.PricesIncludeTax && combined > 0 {
+			taxable = round2(amount / (1 + combined/100))
+		}
+		lt := domain.LineTax{Reference: line.Reference, TaxClass: class, TaxableAmount: taxable, Taxes: []domain.AppliedTax{}}
+		for _, r := range classRates {
+			tax := round2(taxable * r.Rate / 100)
+			lt.Taxes = append(lt.Taxes, domain.AppliedTax{
+				Jurisdiction: r.Jurisdiction.Code(), Name: r.Name, Rate: r.Rate, Amount: tax, RateID: r.UUID,
+			})
+			lt.TaxAmount = round2(lt.TaxAmount + tax)
+		}
+		if req.PricesIncludeTax {
+			// the customer pays the amount as given
+			lt.TaxableAmount = round2(amount - lt.TaxAmount)
+		}
+		c.Lines[i] = lt
+		c.Subtotal += lt.TaxableAmount
+		c.TaxTotal += lt.TaxAmount
+	}
+	c.Subtotal, c.TaxTotal = round2(c.Subtotal), round2(c.TaxTotal)
+	c.Total = round2(c.Subtotal + c.TaxTotal)
+	return c
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/tax/provider"
+	"ecommerce-microservice-go/services/tax/usecase"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Currency  string
+	Provider  ProviderConfig
+}
+
+// ProviderConfig selects who calculates: the rate tables or an external provider
+type ProviderConfig struct {
+	// Name is "table" or "http"
+	Name     string
+	URL      string
+	APIKey   string
+	Timeout  time.Duration
+	Fallback bool
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8092", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "tax", false),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Currency:  src.String("TAX_CURRENCY", "IDR"),
+		Provider: ProviderConfig{
+			Name:     src.OneOf("TAX_PROVIDER", "table", "table", "http"),
+			URL:      src.String("TAX_PROVIDER_URL", ""),
+			APIKey:   src.String("TAX_PROVIDER_API_KEY", ""),
+			Timeout:  src.Duration("TAX_PROVIDER_TIMEOUT_SECONDS", 10, time.Second),
+			Fallback: src.Bool("TAX_PROVIDER_FALLBACK", true),
+		},
+	}
+	if cfg.Provider.Name == "http" && cfg.Provider.URL == "" {
+		src.Fail("TAX_PROVIDER_URL", "is required when TAX_PROVIDER is http")
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "tax", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
+
+// taxConfig builds the use case settings with the configured provider; the rate tables need none
+func (c Config) taxConfig() usecase.TaxConfig {
+	tc := usecase.TaxConfig{Currency: c.Currency, Fallback: c.Provider.Fallback}
+	if c.Provider.Name == "http" {
+		tc.Provider = provider.NewHTTPProvider("http", c.Provider.URL, c.Provider.APIKey, c.Provider.Timeout)
+	}
+	return tc
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Jurisdiction is a country, or a region of one, that levies tax. A destination is taxed by its
+// country's jurisdiction and by its region's, so e.g. a federal and a provincial rate both apply.
+type Jurisdiction struct {
+	ID      int
+	UUID    string
+	Country string
+	// Region is empty for the whole country
+	Region    string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Code names the jurisdiction like "ID" or "CA-QC"
+func (j *Jurisdiction) Code() string {
+	if j.Region == "" {
+		return j.Country
+	}
+	return j.Country + "-" + j.Region
+}
+
+// DefaultTaxClass is used for lines that name none
+const DefaultTaxClass = "standard"
+
+// TaxRate is one version of the rate a jurisdiction levies on a tax class. Versions are never
+// changed once in force: a new version ends the previous one, so the rates of any past moment
+// can be looked up again.
+type TaxRate struct {
+	ID           int
+	UUID         string
+	Jurisdiction Jurisdiction
+	TaxClass     string
+	Name         string
+	// Rate is a percentage, e.g. 11 for 11%
+	Rate      float64
+	ValidFrom time.Time
+	// ValidTo is nil while the version has no successor
+	ValidTo   *time.Time
+	CreatedAt time.Time
+}
+
+// InForceAt reports whether the version applies at t
+func (r *TaxRate) InForceAt(t time.Time) bool {
+	return !t.Before(r.ValidFrom) && (r.ValidTo == nil || t.Before(*r.ValidTo))
+}
+
+// Destination is where the goods are delivered, which decides the jurisdictions
+type Destination struct {
+	Country    string
+	Region     string
+	PostalCode string
+}
+
+type CalculationRequest struct {
+	Currency    string
+	Destination Destination
+	// PricesIncludeTax means line amounts already contain the tax, which is then extracted
+	// instead of added
+	PricesIncludeTax bool
+	Lines            []Line
+	// At is the moment whose rates apply; past orders are recalculated with their own time
+	At time.Time
+}
+
+type Line struct {
+	// Reference identifies the line to the caller, e.g. a product ID
+	Reference string
+	Quantity  int
+	UnitPrice float64
+	Discount  float64
+	TaxClass  string
+}
+
+// Calculation is the tax of a request. Amounts are in the request currency.
+type Calculation struct {
+	Currency string
+	At       time.Time
+	// Provider is "table" for the rate tables or the name of the external provider
+	Provider string
+	Lines    []LineTax
+	// Subtotal is the lines' amounts without tax
+	Subtotal float64
+	TaxTotal float64
+	Total    float64
+}
+
+type LineTax struct {
+	Reference string
+	TaxClass  string
+	// TaxableAmount is the line amount without tax
+	TaxableAmount float64
+	TaxAmount     float64
+	Taxes         []AppliedTax
+}
+
+// AppliedTax is one jurisdiction's tax on one line
+type AppliedTax struct {
+	Jurisdiction string
+	Name         string
+	Rate         float64
+	Amount       float64
+	// RateID is the UUID of the rate version used; empty for external providers
+	RateID string
+}
+
+var taxClassPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// ValidTaxClass reports whether name can name a tax class, like "standard" or "reduced-food"
+func ValidTaxClass(name string) bool {
+	return taxClassPattern.MatchString(name)
+}
+
+var regionPattern = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// ValidRegion reports whether code can name a region of a country, like "CA" or "JK"
+func ValidRegion(code string) bool {
+	return code == "" || regionPattern.MatchString(code)
+}
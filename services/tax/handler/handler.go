@@ -0,0 +1,479 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/tax/domain"
+	"ecommerce-microservice-go/services/tax/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DestinationRequest struct {
+	Country    string `json:"country" binding:"required,len=2,alpha"`
+	Region     string `json:"region" binding:"max=10"`
+	PostalCode string `json:"postalCode" binding:"max=20"`
+}
+
+type LineRequest struct {
+	Reference string  `json:"reference" binding:"max=100"`
+	Quantity  int     `json:"quantity" binding:"required,gt=0,max=10000"`
+	UnitPrice float64 `json:"unitPrice" binding:"gte=0"`
+	Discount  float64 `json:"discount" binding:"gte=0"`
+	TaxClass  string  `json:"taxClass" binding:"max=50"`
+}
+
+type CalculateRequest struct {
+	Currency         string             `json:"currency" binding:"omitempty,len=3,alpha"`
+	Destination      DestinationRequest `json:"destination" binding:"required"`
+	PricesIncludeTax bool               `json:"pricesIncludeTax"`
+	// At recalculates with the rates in force then, e.g. for a past order; now when omitted
+	At    *time.Time    `json:"at"`
+	Lines []LineRequest `json:"lines" binding:"required,min=1,max=500,dive"`
+}
+
+type JurisdictionRequest struct {
+	Country string `json:"country" binding:"required,len=2,alpha"`
+	Region  string `json:"region" binding:"max=10"`
+	Name    string `json:"name" binding:"required,max=120"`
+}
+
+type RenameJurisdictionRequest struct {
+	Name string `json:"name" binding:"required,max=120"`
+}
+
+type RateRequest struct {
+	JurisdictionID string  `json:"jurisdictionId" binding:"required,uuid"`
+	TaxClass       string  `json:"taxClass" binding:"max=50"`
+	Name           string  `json:"name" binding:"required,max=120"`
+	Rate           float64 `json:"rate" binding:"gte=0,lte=100"`
+	// ValidFrom may be in the future to schedule a change; now when omitted
+	ValidFrom *time.Time `json:"validFrom"`
+}
+
+type ResponseAppliedTax struct {
+	Jurisdiction string  `json:"jurisdiction"`
+	Name         string  `json:"name"`
+	Rate         float64 `json:"rate"`
+	Amount       float64 `json:"amount"`
+	RateID       string  `json:"rateId,omitempty"`
+}
+
+type ResponseLineTax struct {
+	Reference     string               `json:"reference,omitempty"`
+	TaxClass      string               `json:"taxClass"`
+	TaxableAmount float64              `json:"taxableAmount"`
+	TaxAmount     float64              `json:"taxAmount"`
+	Taxes         []ResponseAppliedTax `json:"taxes"`
+}
+
+type ResponseCalculation struct {
+	Currency string            `json:"currency"`
+	At       time.Time         `json:"at"`
+	Provider string            `json:"provider"`
+	Subtotal float64           `json:"subtotal"`
+	TaxTotal float64           `json:"taxTotal"`
+	Total    float64           `json:"total"`
+	Lines    []ResponseLineTax `json:"lines"`
+}
+
+type ResponseJurisdiction struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Country   string    `json:"country"`
+	Region    string    `json:"region,omitempty"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ResponseRate struct {
+	ID           string               `json:"id"`
+	Jurisdiction ResponseJurisdiction `json:"jurisdiction"`
+	TaxClass     string               `json:"taxClass"`
+	Name         string               `json:"name"`
+	Rate         float64              `json:"rate"`
+	ValidFrom    time.Time            `json:"validFrom"`
+	ValidTo      *time.Time           `json:"validTo,omitempty"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
+
+type Handler struct {
+	taxUC  usecase.ITaxUseCase
+	Logger *logger.Logger
+}
+
+func NewHandler(uc usecase.ITaxUseCase, l *logger.Logger) *Handler {
+	return &Handler{taxUC: uc, Logger: l}
+}
+
+var jurisdictionSpec = pagination.Spec{
+	Sortable:    map[string]string{"country": "country", "region": "region", "name": "name", "createdAt": "created_at"},
+	Filterable:  map[string]string{"country": "country", "region": "region"},
+	DefaultSort: "country,region",
+}
+
+var rateSpec = pagination.Spec{
+	Sortable:    map[string]string{"validFrom": "valid_from", "taxClass": "tax_class", "rate": "rate"},
+	Filterable:  map[string]string{"taxClass": "tax_class"},
+	DefaultSort: "-validFrom",
+}
+
+// Calculate godoc
+// @Summary      Calculate tax
+// @Description  Taxes every line for the destination: the country's rate and its region's rate of the line's tax
+// @Description  class (standard when omitted) apply. Line amounts are quantity × unitPrice − discount. With
+// @Description  pricesIncludeTax the tax is taken out of the amounts instead of added. at recalculates with the rates
+// @Description  in force at that moment, so the tax of past orders can be reproduced.
+// @Tags         Tax
+// @Accept       json
+// @Produce      json
+// @Param        request body CalculateRequest true "Lines and destination"
+// @Success      200 {object} ResponseCalculation
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      429 {object} domainErrors.ErrorResponse
+// @Router       /tax/calculate [post]
+func (h *Handler) Calculate(ctx *gin.Context) {
+	var req CalculateRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	calculation, err := h.taxUC.Calculate(ctx.Request.Context(), calculationFromRequest(&req))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, calculationToResponse(calculation))
+}
+
+// GetJurisdictions godoc
+// @Summary      List jurisdictions
+// @Tags         Tax
+// @Security     BearerAuth
+// @Produce      json
+// @Param        country query string false "ISO 3166 country code"
+// @Param        region  query string false "Region code"
+// @Param        page    query int    false "Page number" default(1)
+// @Param        limit   query int    false "Page size" default(20)
+// @Param        sort    query string false "Comma separated fields, prefix - for descending (country, region, name, createdAt)" default(country,region)
+// @Param        fields  query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseJurisdiction]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      403 {object} domainErrors.ErrorResponse
+// @Router       /tax/jurisdictions [get]
+func (h *Handler) GetJurisdictions(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, jurisdictionSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	jurisdictions, total, err := h.taxUC.GetJurisdictions(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseJurisdiction, len(*jurisdictions))
+	for i := range *jurisdictions {
+		res[i] = jurisdictionToResponse(&(*jurisdictions)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// GetJurisdiction godoc
+// @Summary      Get a jurisdiction
+// @Tags         Tax
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path  string true  "Jurisdiction ID"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseJurisdiction
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /tax/jurisdictions/{id} [get]
+func (h *Handler) GetJurisdiction(ctx *gin.Context) {
+	id, err := pathID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	j, err := h.taxUC.GetJurisdiction(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, jurisdictionToResponse(j))
+}
+
+// NewJurisdiction godoc
+// @Summary      Create a jurisdiction
+// @Description  A country, or with region a state or province of it. Destinations are taxed by both their country's
+// @Description  and their region's jurisdiction.
+// @Tags         Tax
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body JurisdictionRequest true "Jurisdiction"
+// @Success      201 {object} ResponseJurisdiction
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse
+// @Router       /tax/jurisdictions [post]
+func (h *Handler) NewJurisdiction(ctx *gin.Context) {
+	var req JurisdictionRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	created, err := h.taxUC.CreateJurisdiction(ctx.Request.Context(), &domain.Jurisdiction{Country: req.Country, Region: req.Region, Name: req.Name})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, jurisdictionToResponse(created))
+}
+
+// RenameJurisdiction godoc
+// @Summary      Rename a jurisdiction
+// @Description  Country and region cannot change, since past calculations name them
+// @Tags         Tax
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                    true "Jurisdiction ID"
+// @Param        request body RenameJurisdictionRequest true "Name"
+// @Success      200 {object} ResponseJurisdiction
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /tax/jurisdictions/{id} [put]
+func (h *Handler) RenameJurisdiction(ctx *gin.Context) {
+	id, err := pathID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var req RenameJurisdictionRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	j, err := h.taxUC.RenameJurisdiction(ctx.Request.Context(), id, req.Name)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, jurisdictionToResponse(j))
+}
+
+// DeleteJurisdiction godoc
+// @Summary      Delete a jurisdiction
+// @Description  Only jurisdictions without rates can be deleted; retire the rates of the others
+// @Tags         Tax
+// @Security     BearerAuth
+// @Param        id path string true "Jurisdiction ID"
+// @Success      204
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse
+// @Router       /tax/jurisdictions/{id} [delete]
+func (h *Handler) DeleteJurisdiction(ctx *gin.Context) {
+	id, err := pathID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.taxUC.DeleteJurisdiction(ctx.Request.Context(), id); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetRates godoc
+// @Summary      List tax rates
+// @Description  Lists every version by default, the rate history; at limits the list to the versions in force then
+// @Tags         Tax
+// @Security     BearerAuth
+// @Produce      json
+// @Param        jurisdiction query string false "Jurisdiction ID"
+// @Param        taxClass     query string false "Tax class"
+// @Param        at           query string false "RFC 3339 time"
+// @Param        page         query int    false "Page number" default(1)
+// @Param        limit        query int    false "Page size" default(20)
+// @Param        sort         query string false "Comma separated fields, prefix - for descending (validFrom, taxClass, rate)" default(-validFrom)
+// @Param        fields       query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseRate]
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /tax/rates [get]
+func (h *Handler) GetRates(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, rateSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	jurisdiction := ctx.Query("jurisdiction")
+	if jurisdiction != "" && uuid.Validate(jurisdiction) != nil {
+		_ = ctx.Error(domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+		return
+	}
+	var at *time.Time
+	if v := ctx.Query("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			_ = ctx.Error(domainErrors.NewValidationError(err, domainErrors.FieldError{Field: "at", Rule: "datetime", Message: "at must be an RFC 3339 time"}))
+			return
+		}
+		at = &t
+	}
+	rates, total, err := h.taxUC.GetRates(ctx.Request.Context(), p, strings.ToLower(jurisdiction), at)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseRate, len(*rates))
+	for i := range *rates {
+		res[i] = rateToResponse(&(*rates)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// GetRate godoc
+// @Summary      Get a tax rate version
+// @Tags         Tax
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path  string true  "Rate ID"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} ResponseRate
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /tax/rates/{id} [get]
+func (h *Handler) GetRate(ctx *gin.Context) {
+	id, err := pathID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r, err := h.taxUC.GetRate(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondBare(ctx, http.StatusOK, rateToResponse(r))
+}
+
+// NewRate godoc
+// @Summary      Add a tax rate version
+// @Description  Rates are never edited: a new version for the same jurisdiction and tax class ends the current one
+// @Description  at validFrom. validFrom may lie in the future to schedule a change, but not in the past once the
+// @Description  class has a rate, since that would change past calculations.
+// @Tags         Tax
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body RateRequest true "Rate"
+// @Success      201 {object} ResponseRate
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /tax/rates [post]
+func (h *Handler) NewRate(ctx *gin.Context) {
+	var req RateRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	r := &domain.TaxRate{TaxClass: req.TaxClass, Name: req.Name, Rate: req.Rate}
+	if req.ValidFrom != nil {
+		r.ValidFrom = *req.ValidFrom
+	}
+	created, err := h.taxUC.CreateRate(ctx.Request.Context(), strings.ToLower(req.JurisdictionID), r)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, rateToResponse(created))
+}
+
+// RetireRate godoc
+// @Summary      Retire a tax rate version
+// @Description  Ends the version now, leaving the class untaxed until a new version starts. A version scheduled for
+// @Description  the future is removed instead and the one it would have ended continues.
+// @Tags         Tax
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "Rate ID"
+// @Success      200 {object} ResponseRate
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse
+// @Router       /tax/rates/{id} [delete]
+func (h *Handler) RetireRate(ctx *gin.Context) {
+	id, err := pathID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	r, err := h.taxUC.RetireRate(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, rateToResponse(r))
+}
+
+func pathID(ctx *gin.Context) (string, error) {
+	id := ctx.Param("id")
+	if uuid.Validate(id) != nil {
+		return "", domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return strings.ToLower(id), nil
+}
+
+// Mappers
+func calculationFromRequest(req *CalculateRequest) domain.CalculationRequest {
+	c := domain.CalculationRequest{
+		Currency: req.Currency, PricesIncludeTax: req.PricesIncludeTax, Lines: make([]domain.Line, len(req.Lines)),
+		Destination: domain.Destination{Country: req.Destination.Country, Region: req.Destination.Region, PostalCode: req.Destination.PostalCode},
+	}
+	if req.At != nil {
+		c.At = *req.At
+	}
+	for i, l := range req.Lines {
+		c.Lines[i] = domain.Line{Reference: l.Reference, Quantity: l.Quantity, UnitPrice: l.UnitPrice, Discount: l.Discount, TaxClass: l.TaxClass}
+	}
+	return c
+}
+
+func calculationToResponse(c *domain.Calculation) ResponseCalculation {
+	res := ResponseCalculation{
+		Currency: c.Currency, At: c.At, Provider: c.Provider, Subtotal: c.Subtotal, TaxTotal: c.TaxTotal, Total: c.Total,
+		Lines: make([]ResponseLineTax, len(c.Lines)),
+	}
+	for i, l := range c.Lines {
+		line := ResponseLineTax{
+			Reference: l.Reference, TaxClass: l.TaxClass, TaxableAmount: l.TaxableAmount, TaxAmount: l.TaxAmount,
+			Taxes: make([]ResponseAppliedTax, len(l.Taxes)),
+		}
+		for j, t := range l.Taxes {
+			line.Taxes[j] = ResponseAppliedTax{Jurisdiction: t.Jurisdiction, Name: t.Name, Rate: t.Rate, Amount: t.Amount, RateID: t.RateID}
+		}
+		res.Lines[i] = line
+	}
+	return res
+}
+
+func jurisdictionToResponse(j *domain.Jurisdiction) ResponseJurisdiction {
+	return ResponseJurisdiction{
+		ID: j.UUID, Code: j.Code(), Country: j.Country, Region: j.Region, Name: j.Name, CreatedAt: j.CreatedAt, UpdatedAt: j.UpdatedAt,
+	}
+}
+
+func rateToResponse(r *domain.TaxRate) ResponseRate {
+	return ResponseRate{
+		ID: r.UUID, Jurisdiction: jurisdictionToResponse(&r.Jurisdiction), TaxClass: r.TaxClass, Name: r.Name, Rate: r.Rate,
+		ValidFrom: r.ValidFrom, ValidTo: r.ValidTo, CreatedAt: r.CreatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+// @title           Tax Service API
+// @version         1.0.0
+// @description     Tax microservice: jurisdictions with versioned rate tables per tax class, or an external tax provider, and a calculation API for line items shipped to a destination
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/tax/handler"
+	"ecommerce-microservice-go/services/tax/migrations"
+	"ecommerce-microservice-go/services/tax/repository"
+	"ecommerce-microservice-go/services/tax/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/tax/docs"
+)
+
+const service app.Service = "tax"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Tax Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Tax Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			repository.NewTaxRepository,
+			func(r repository.TaxRepositoryInterface, log *logger.Logger) usecase.ITaxUseCase {
+				taxConfig := cfg.taxConfig()
+				if taxConfig.Provider != nil {
+					log.Info("Calculating tax with external provider", zap.String("url", cfg.Provider.URL), zap.Bool("fallback", taxConfig.Fallback))
+				}
+				return usecase.NewTaxUseCase(r, taxConfig, log)
+			},
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, migrator *psql.Migrator,
+	h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("tax", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("tax"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("tax").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		RegisterRoutes(v1)
+
+	v1.GET("/tax/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	tax := v1.Group("/tax")
+	// quotes are public, e.g. for a cart before sign in
+	tax.POST("/calculate", middleware.RateLimit(cacheStore, "tax", cfg.RateLimit, middleware.KeyByIP, log), h.Calculate)
+	manage := tax.Group("")
+	manage.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermTaxManage))
+	{
+		manage.GET("/jurisdictions", h.GetJurisdictions)
+		manage.POST("/jurisdictions", h.NewJurisdiction)
+		manage.GET("/jurisdictions/:id", h.GetJurisdiction)
+		manage.PUT("/jurisdictions/:id", h.RenameJurisdiction)
+		manage.DELETE("/jurisdictions/:id", h.DeleteJurisdiction)
+		manage.GET("/rates", h.GetRates)
+		manage.POST("/rates", h.NewRate)
+		manage.GET("/rates/:id", h.GetRate)
+		manage.DELETE("/rates/:id", h.RetireRate)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.POST("/tax/calculate", h.Calculate)
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
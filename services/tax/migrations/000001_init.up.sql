@@ -0,0 +1,44 @@
+CREATE TABLE jurisdictions (
+    id bigserial,
+    uuid uuid NOT NULL,
+    country text NOT NULL,
+    -- empty for the whole country; a state or province code otherwise
+    region text NOT NULL DEFAULT '',
+    name text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_jurisdictions_country CHECK (country ~ '^[A-Z]{2}$')
+);
+
+CREATE UNIQUE INDEX idx_jurisdictions_uuid ON jurisdictions (uuid);
+CREATE INDEX idx_jurisdictions_deleted_at ON jurisdictions (deleted_at);
+CREATE UNIQUE INDEX idx_jurisdictions_location ON jurisdictions (country, region) WHERE deleted_at IS NULL;
+
+-- Rates are never changed: a new rate closes the previous one of its jurisdiction and tax class
+-- by setting valid_to, so a calculation at any past time finds the rates in force then.
+CREATE TABLE tax_rates (
+    id bigserial,
+    uuid uuid NOT NULL,
+    jurisdiction_id bigint NOT NULL REFERENCES jurisdictions (id),
+    tax_class text NOT NULL,
+    name text NOT NULL,
+    rate numeric(7,4) NOT NULL,
+    valid_from timestamptz NOT NULL,
+    valid_to timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    created_by bigint,
+    updated_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_tax_rates_rate CHECK (rate >= 0 AND rate <= 100),
+    CONSTRAINT chk_tax_rates_window CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE UNIQUE INDEX idx_tax_rates_uuid ON tax_rates (uuid);
+CREATE INDEX idx_tax_rates_deleted_at ON tax_rates (deleted_at);
+CREATE INDEX idx_tax_rates_lookup ON tax_rates (jurisdiction_id, tax_class, valid_from DESC);
@@ -0,0 +1,139 @@
+package provider
+
+//go:generate mockgen -source=provider.go -destination=mocks/provider.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/tax/domain"
+)
+
+// ErrRejected means the provider refused the request, e.g. for an address it cannot place;
+// asking again will not help
+var ErrRejected = errors.New("tax provider rejected the request")
+
+// Provider calculates tax outside the rate tables, e.g. a tax compliance service that knows
+// local rates and exemptions. Adapters return lines in the order of the request.
+type Provider interface {
+	Name() string
+	Calculate(ctx context.Context, req domain.CalculationRequest) (*domain.Calculation, error)
+}
+
+// HTTPProvider posts the request to a JSON endpoint; an adapter service in front of a specific
+// provider translates from there. The request and answer have the shape of the tax service's
+// own calculation API.
+type HTTPProvider struct {
+	name   string
+	url    string
+	apiKey string
+	http   *http.Client
+}
+
+func NewHTTPProvider(name, url, apiKey string, timeout time.Duration) Provider {
+	return &HTTPProvider{name: name, url: url, apiKey: apiKey, http: &http.Client{Timeout: timeout, Transport: otel.Transport(nil)}}
+}
+
+func (p *HTTPProvider) Name() string { return p.name }
+
+type httpLine struct {
+	Reference string  `json:"reference"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unitPrice"`
+	Discount  float64 `json:"discount"`
+	TaxClass  string  `json:"taxClass"`
+}
+
+type httpRequest struct {
+	Currency    string `json:"currency"`
+	Destination struct {
+		Country    string `json:"country"`
+		Region     string `json:"region,omitempty"`
+		PostalCode string `json:"postalCode,omitempty"`
+	} `json:"destination"`
+	PricesIncludeTax bool       `json:"pricesIncludeTax"`
+	At               time.Time  `json:"at"`
+	Lines            []httpLine `json:"lines"`
+}
+
+type httpAnswer struct {
+	Lines []struct {
+		Reference     string  `json:"reference"`
+		TaxableAmount float64 `json:"taxableAmount"`
+		TaxAmount     float64 `json:"taxAmount"`
+		Taxes         []struct {
+			Jurisdiction string  `json:"jurisdiction"`
+			Name         string  `json:"name"`
+			Rate         float64 `json:"rate"`
+			Amount       float64 `json:"amount"`
+		} `json:"taxes"`
+	} `json:"lines"`
+}
+
+func (p *HTTPProvider) Calculate(ctx context.Context, req domain.CalculationRequest) (*domain.Calculation, error) {
+	body := httpRequest{Currency: req.Currency, PricesIncludeTax: req.PricesIncludeTax, At: req.At, Lines: make([]httpLine, len(req.Lines))}
+	body.Destination.Country, body.Destination.Region, body.Destination.PostalCode = req.Destination.Country, req.Destination.Region, req.Destination.PostalCode
+	for i, l := range req.Lines {
+		body.Lines[i] = httpLine{Reference: l.Reference, Quantity: l.Quantity, UnitPrice: l.UnitPrice, Discount: l.Discount, TaxClass: l.TaxClass}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	res, err := p.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("tax provider %s request failed: %w", p.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	switch {
+	case res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnprocessableEntity:
+		var payload struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&payload)
+		return nil, fmt.Errorf("%w: %s", ErrRejected, payload.Error)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("tax provider %s returned %d", p.name, res.StatusCode)
+	}
+	var answer httpAnswer
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return nil, fmt.Errorf("decoding tax provider %s answer: %w", p.name, err)
+	}
+	if len(answer.Lines) != len(req.Lines) {
+		return nil, fmt.Errorf("tax provider %s returned %d lines for %d", p.name, len(answer.Lines), len(req.Lines))
+	}
+
+	c := &domain.Calculation{Currency: req.Currency, At: req.At, Provider: p.name, Lines: make([]domain.LineTax, len(answer.Lines))}
+	for i, l := range answer.Lines {
+		class := req.Lines[i].TaxClass
+		if class == "" {
+			class = domain.DefaultTaxClass
+		}
+		lt := domain.LineTax{Reference: req.Lines[i].Reference, TaxClass: class, TaxableAmount: l.TaxableAmount, TaxAmount: l.TaxAmount,
+			Taxes: make([]domain.AppliedTax, len(l.Taxes))}
+		for j, t := range l.Taxes {
+			lt.Taxes[j] = domain.AppliedTax{Jurisdiction: t.Jurisdiction, Name: t.Name, Rate: t.Rate, Amount: t.Amount}
+		}
+		c.Lines[i] = lt
+		c.Subtotal += l.TaxableAmount
+		c.TaxTotal += l.TaxAmount
+	}
+	c.Subtotal, c.TaxTotal = math.Round(c.Subtotal*100)/100, math.Round(c.TaxTotal*100)/100
+	c.Total = math.Round((c.Subtotal+c.TaxTotal)*100) / 100
+	return c, nil
+}
@@ -0,0 +1,261 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/tax/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Jurisdiction struct {
+	ID      int    `gorm:"primaryKey"`
+	Country string `gorm:"column:country;not null"`
+	Region  string `gorm:"column:region;not null"`
+	Name    string `gorm:"column:name;not null"`
+	psql.BaseModel
+}
+
+func (Jurisdiction) TableName() string { return "jurisdictions" }
+
+type TaxRate struct {
+	ID             int          `gorm:"primaryKey"`
+	JurisdictionID int          `gorm:"column:jurisdiction_id;not null"`
+	Jurisdiction   Jurisdiction `gorm:"foreignKey:JurisdictionID"`
+	TaxClass       string       `gorm:"column:tax_class;not null"`
+	Name           string       `gorm:"column:name;not null"`
+	Rate           float64      `gorm:"column:rate;not null"`
+	ValidFrom      time.Time    `gorm:"column:valid_from;not null"`
+	ValidTo        *time.Time   `gorm:"column:valid_to"`
+	psql.BaseModel
+}
+
+func (TaxRate) TableName() string { return "tax_rates" }
+
+type TaxRepositoryInterface interface {
+	CreateJurisdiction(ctx context.Context, j *domain.Jurisdiction) (*domain.Jurisdiction, error)
+	GetJurisdiction(ctx context.Context, uuid string) (*domain.Jurisdiction, error)
+	SearchJurisdictions(ctx context.Context, p pagination.Params) (*[]domain.Jurisdiction, int64, error)
+	RenameJurisdiction(ctx context.Context, uuid, name string) (*domain.Jurisdiction, error)
+	// DeleteJurisdiction refuses jurisdictions with rates, which past calculations may have used
+	DeleteJurisdiction(ctx context.Context, uuid string) error
+
+	// RatesFor returns the rates in force at t of the country and of its region, if any
+	RatesFor(ctx context.Context, country, region string, at time.Time) ([]domain.TaxRate, error)
+	// CreateRate adds a version of the jurisdiction's rate for the tax class, ending the version
+	// in force at r.ValidFrom
+	CreateRate(ctx context.Context, jurisdictionID int, r *domain.TaxRate) (*domain.TaxRate, error)
+	GetRate(ctx context.Context, uuid string) (*domain.TaxRate, error)
+	// SearchRates lists versions, of one jurisdiction when jurisdictionID is not 0 and only those
+	// in force at *at when at is set
+	SearchRates(ctx context.Context, p pagination.Params, jurisdictionID int, at *time.Time) (*[]domain.TaxRate, int64, error)
+	// RetireRate ends a version in force at now; a version that has not started yet is removed
+	// and its predecessor continues
+	RetireRate(ctx context.Context, uuid string, now time.Time) (*domain.TaxRate, error)
+}
+
+type TaxRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewTaxRepository(db *gorm.DB, l *logger.Logger) TaxRepositoryInterface {
+	return &TaxRepository{DB: db, Logger: l}
+}
+
+func (r *TaxRepository) CreateJurisdiction(ctx context.Context, d *domain.Jurisdiction) (*domain.Jurisdiction, error) {
+	j := &Jurisdiction{Country: d.Country, Region: d.Region, Name: d.Name}
+	if err := psql.Conn(ctx, r.DB).Create(j).Error; err != nil {
+		r.Logger.Error("Error creating jurisdiction", zap.String("country", d.Country), zap.String("region", d.Region), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return jurisdictionToDomain(j), nil
+}
+
+func (r *TaxRepository) GetJurisdiction(ctx context.Context, uuid string) (*domain.Jurisdiction, error) {
+	var j Jurisdiction
+	if err := psql.Conn(ctx, r.DB).Where("uuid = ?", uuid).First(&j).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return jurisdictionToDomain(&j), nil
+}
+
+func (r *TaxRepository) SearchJurisdictions(ctx context.Context, p pagination.Params) (*[]domain.Jurisdiction, int64, error) {
+	var jurisdictions []Jurisdiction
+	total, err := pagination.Find(psql.Conn(ctx, r.DB).Model(&Jurisdiction{}), p, &jurisdictions)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := make([]domain.Jurisdiction, len(jurisdictions))
+	for i := range jurisdictions {
+		result[i] = *jurisdictionToDomain(&jurisdictions[i])
+	}
+	return &result, total, nil
+}
+
+func (r *TaxRepository) RenameJurisdiction(ctx context.Context, uuid, name string) (*domain.Jurisdiction, error) {
+	tx := psql.Conn(ctx, r.DB).Model(&Jurisdiction{}).Where("uuid = ?", uuid).Update("name", name)
+	if tx.Error != nil {
+		return nil, psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return r.GetJurisdiction(ctx, uuid)
+}
+
+func (r *TaxRepository) DeleteJurisdiction(ctx context.Context, uuid string) error {
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var j Jurisdiction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", uuid).First(&j).Error; err != nil {
+			return err
+		}
+		var rates int64
+		if err := tx.Unscoped().Model(&TaxRate{}).Where("jurisdiction_id = ?", j.ID).Count(&rates).Error; err != nil {
+			return err
+		}
+		if rates > 0 {
+			return domainErrors.NewAppError(errors.New("jurisdiction has rates; retire them instead"), domainErrors.Conflict)
+		}
+		return tx.Delete(&j).Error
+	})
+	return psql.TranslateError(err)
+}
+
+func (r *TaxRepository) RatesFor(ctx context.Context, country, region string, at time.Time) ([]domain.TaxRate, error) {
+	var rates []TaxRate
+	err := psql.Conn(ctx, r.DB).Joins("Jurisdiction").
+		Where(`"Jurisdiction".country = ? AND "Jurisdiction".region IN ?`, country, []string{"", region}).
+		Where("tax_rates.valid_from <= ? AND (tax_rates.valid_to IS NULL OR tax_rates.valid_to > ?)", at, at).
+		Order("tax_rates.id").Find(&rates).Error
+	if err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return ratesToDomain(rates), nil
+}
+
+func (r *TaxRepository) CreateRate(ctx context.Context, jurisdictionID int, d *domain.TaxRate) (*domain.TaxRate, error) {
+	rate := &TaxRate{JurisdictionID: jurisdictionID, TaxClass: d.TaxClass, Name: d.Name, Rate: d.Rate, ValidFrom: d.ValidFrom}
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		// the jurisdiction's row serializes new versions, so two cannot end the same predecessor
+		var j Jurisdiction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", jurisdictionID).First(&j).Error; err != nil {
+			return err
+		}
+		var latest TaxRate
+		err := tx.Where("jurisdiction_id = ? AND tax_class = ?", jurisdictionID, d.TaxClass).Order("valid_from DESC").First(&latest).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		case err != nil:
+			return err
+		case !d.ValidFrom.After(latest.ValidFrom):
+			return domainErrors.NewAppError(fmt.Errorf("validFrom must be after %s, when the latest version starts",
+				latest.ValidFrom.UTC().Format(time.RFC3339)), domainErrors.ValidationError)
+		case d.ValidFrom.Before(time.Now().Add(-time.Minute)):
+			// backdating would change what earlier calculations come to
+			return domainErrors.NewAppError(errors.New("validFrom must not be in the past once the tax class has a rate"), domainErrors.ValidationError)
+		case latest.ValidTo == nil || latest.ValidTo.After(d.ValidFrom):
+			if err := tx.Model(&latest).Update("valid_to", d.ValidFrom).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(rate).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error creating tax rate", zap.Int("jurisdictionId", jurisdictionID), zap.String("taxClass", d.TaxClass), zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return r.GetRate(ctx, rate.UUID)
+}
+
+func (r *TaxRepository) GetRate(ctx context.Context, uuid string) (*domain.TaxRate, error) {
+	var rate TaxRate
+	if err := psql.Conn(ctx, r.DB).Preload("Jurisdiction").Where("uuid = ?", uuid).First(&rate).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return rateToDomain(&rate), nil
+}
+
+func (r *TaxRepository) SearchRates(ctx context.Context, p pagination.Params, jurisdictionID int, at *time.Time) (*[]domain.TaxRate, int64, error) {
+	query := psql.Conn(ctx, r.DB).Model(&TaxRate{}).Preload("Jurisdiction")
+	if jurisdictionID != 0 {
+		query = query.Where("jurisdiction_id = ?", jurisdictionID)
+	}
+	if at != nil {
+		query = query.Where("valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", *at, *at)
+	}
+	var rates []TaxRate
+	total, err := pagination.Find(query, p, &rates)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := ratesToDomain(rates)
+	return &result, total, nil
+}
+
+func (r *TaxRepository) RetireRate(ctx context.Context, uuid string, now time.Time) (*domain.TaxRate, error) {
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var rate TaxRate
+		if err := tx.Where("uuid = ?", uuid).First(&rate).Error; err != nil {
+			return err
+		}
+		var j Jurisdiction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", rate.JurisdictionID).First(&j).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", rate.ID).First(&rate).Error; err != nil {
+			return err
+		}
+		if rate.ValidTo != nil && !rate.ValidTo.After(now) {
+			return domainErrors.NewAppError(errors.New("tax rate has already ended"), domainErrors.Conflict)
+		}
+		if rate.ValidFrom.After(now) {
+			// never in force, so no calculation used it; the predecessor it would have ended goes on
+			if err := tx.Model(&TaxRate{}).Where("jurisdiction_id = ? AND tax_class = ? AND valid_to = ?", rate.JurisdictionID, rate.TaxClass, rate.ValidFrom).
+				Update("valid_to", rate.ValidTo).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&rate).Error
+		}
+		return tx.Model(&rate).Update("valid_to", now).Error
+	})
+	if err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	var rate TaxRate
+	if err := psql.Conn(ctx, r.DB).Unscoped().Preload("Jurisdiction").Where("uuid = ?", uuid).First(&rate).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return rateToDomain(&rate), nil
+}
+
+// Mappers
+func jurisdictionToDomain(j *Jurisdiction) *domain.Jurisdiction {
+	return &domain.Jurisdiction{ID: j.ID, UUID: j.UUID, Country: j.Country, Region: j.Region, Name: j.Name, CreatedAt: j.CreatedAt, UpdatedAt: j.UpdatedAt}
+}
+
+func rateToDomain(r *TaxRate) *domain.TaxRate {
+	return &domain.TaxRate{
+		ID: r.ID, UUID: r.UUID, Jurisdiction: *jurisdictionToDomain(&r.Jurisdiction), TaxClass: r.TaxClass, Name: r.Name, Rate: r.Rate,
+		ValidFrom: r.ValidFrom, ValidTo: r.ValidTo, CreatedAt: r.CreatedAt,
+	}
+}
+
+func ratesToDomain(rates []TaxRate) []domain.TaxRate {
+	result := make([]domain.TaxRate, len(rates))
+	for i := range rates {
+		result[i] = *rateToDomain(&rates[i])
+	}
+	return result
+}
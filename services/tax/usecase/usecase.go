@@ -0,0 +1,222 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/services/tax/calculator"
+	"ecommerce-microservice-go/services/tax/domain"
+	"ecommerce-microservice-go/services/tax/provider"
+	"ecommerce-microservice-go/services/tax/repository"
+
+	"go.uber.org/zap"
+)
+
+type TaxConfig struct {
+	// Currency is assumed for requests that do not name one
+	Currency string
+	// Provider calculates instead of the rate tables when set
+	Provider provider.Provider
+	// Fallback calculates from the rate tables while the provider is unavailable; it does not
+	// apply to requests the provider rejects
+	Fallback bool
+}
+
+type ITaxUseCase interface {
+	CreateJurisdiction(ctx context.Context, j *domain.Jurisdiction) (*domain.Jurisdiction, error)
+	GetJurisdiction(ctx context.Context, uuid string) (*domain.Jurisdiction, error)
+	GetJurisdictions(ctx context.Context, p pagination.Params) (*[]domain.Jurisdiction, int64, error)
+	RenameJurisdiction(ctx context.Context, uuid, name string) (*domain.Jurisdiction, error)
+	DeleteJurisdiction(ctx context.Context, uuid string) error
+
+	// CreateRate adds a version of a rate; a zero ValidFrom starts it now
+	CreateRate(ctx context.Context, jurisdictionUUID string, r *domain.TaxRate) (*domain.TaxRate, error)
+	GetRate(ctx context.Context, uuid string) (*domain.TaxRate, error)
+	// GetRates lists rate versions, filtered by jurisdiction when its UUID is given and to those
+	// in force at *at when at is set
+	GetRates(ctx context.Context, p pagination.Params, jurisdictionUUID string, at *time.Time) (*[]domain.TaxRate, int64, error)
+	RetireRate(ctx context.Context, uuid string) (*domain.TaxRate, error)
+
+	// Calculate taxes the lines for the destination with the rates in force at req.At, now when
+	// zero
+	Calculate(ctx context.Context, req domain.CalculationRequest) (*domain.Calculation, error)
+}
+
+type TaxUseCase struct {
+	repo   repository.TaxRepositoryInterface
+	config TaxConfig
+	Logger *logger.Logger
+}
+
+func NewTaxUseCase(r repository.TaxRepositoryInterface, cfg TaxConfig, l *logger.Logger) ITaxUseCase {
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	return &TaxUseCase{repo: r, config: cfg, Logger: l}
+}
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+func invalid(msg string) error {
+	return domainErrors.NewAppError(errors.New(msg), domainErrors.ValidationError)
+}
+
+func (s *TaxUseCase) CreateJurisdiction(ctx context.Context, j *domain.Jurisdiction) (*domain.Jurisdiction, error) {
+	j.Country, j.Region = strings.ToUpper(j.Country), strings.ToUpper(j.Region)
+	if !countryPattern.MatchString(j.Country) {
+		return nil, invalid("country must be a two letter ISO 3166 code")
+	}
+	if !domain.ValidRegion(j.Region) {
+		return nil, invalid("region must be up to 10 letters and digits")
+	}
+	created, err := s.repo.CreateJurisdiction(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Created jurisdiction", zap.String("uuid", created.UUID), zap.String("code", created.Code()))
+	return created, nil
+}
+
+func (s *TaxUseCase) GetJurisdiction(ctx context.Context, uuid string) (*domain.Jurisdiction, error) {
+	return s.repo.GetJurisdiction(ctx, uuid)
+}
+
+func (s *TaxUseCase) GetJurisdictions(ctx context.Context, p pagination.Params) (*[]domain.Jurisdiction, int64, error) {
+	return s.repo.SearchJurisdictions(ctx, p)
+}
+
+func (s *TaxUseCase) RenameJurisdiction(ctx context.Context, uuid, name string) (*domain.Jurisdiction, error) {
+	return s.repo.RenameJurisdiction(ctx, uuid, name)
+}
+
+func (s *TaxUseCase) DeleteJurisdiction(ctx context.Context, uuid string) error {
+	if err := s.repo.DeleteJurisdiction(ctx, uuid); err != nil {
+		return err
+	}
+	s.Logger.Info("Deleted jurisdiction", zap.String("uuid", uuid))
+	return nil
+}
+
+func (s *TaxUseCase) CreateRate(ctx context.Context, jurisdictionUUID string, r *domain.TaxRate) (*domain.TaxRate, error) {
+	if r.TaxClass == "" {
+		r.TaxClass = domain.DefaultTaxClass
+	}
+	if !domain.ValidTaxClass(r.TaxClass) {
+		return nil, invalid("taxClass must be lowercase letters, digits, - and _")
+	}
+	if r.Rate < 0 || r.Rate > 100 {
+		return nil, invalid("rate must be between 0 and 100")
+	}
+	if r.ValidFrom.IsZero() {
+		r.ValidFrom = time.Now()
+	}
+	j, err := s.repo.GetJurisdiction(ctx, jurisdictionUUID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrNotFound) {
+			return nil, invalid("jurisdiction not found")
+		}
+		return nil, err
+	}
+	created, err := s.repo.CreateRate(ctx, j.ID, r)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Created tax rate", zap.String("uuid", created.UUID), zap.String("jurisdiction", j.Code()),
+		zap.String("taxClass", created.TaxClass), zap.Float64("rate", created.Rate), zap.Time("validFrom", created.ValidFrom))
+	return created, nil
+}
+
+func (s *TaxUseCase) GetRate(ctx context.Context, uuid string) (*domain.TaxRate, error) {
+	return s.repo.GetRate(ctx, uuid)
+}
+
+func (s *TaxUseCase) GetRates(ctx context.Context, p pagination.Params, jurisdictionUUID string, at *time.Time) (*[]domain.TaxRate, int64, error) {
+	jurisdictionID := 0
+	if jurisdictionUUID != "" {
+		j, err := s.repo.GetJurisdiction(ctx, jurisdictionUUID)
+		if err != nil {
+			return nil, 0, err
+		}
+		jurisdictionID = j.ID
+	}
+	return s.repo.SearchRates(ctx, p, jurisdictionID, at)
+}
+
+func (s *TaxUseCase) RetireRate(ctx context.Context, uuid string) (*domain.TaxRate, error) {
+	retired, err := s.repo.RetireRate(ctx, uuid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Info("Retired tax rate", zap.String("uuid", uuid))
+	return retired, nil
+}
+
+func (s *TaxUseCase) Calculate(ctx context.Context, req domain.CalculationRequest) (*domain.Calculation, error) {
+	if err := s.validate(&req); err != nil {
+		return nil, err
+	}
+	if s.config.Provider != nil {
+		calculation, err := s.config.Provider.Calculate(ctx, req)
+		switch {
+		case err == nil:
+			return calculation, nil
+		case errors.Is(err, provider.ErrRejected):
+			return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+		case !s.config.Fallback:
+			s.Logger.Error("Error calculating tax with provider", zap.String("provider", s.config.Provider.Name()), zap.Error(err))
+			return nil, domainErrors.NewAppError(errors.New("tax provider is unavailable"), domainErrors.UnknownError)
+		}
+		s.Logger.Warn("Tax provider failed, calculating from the rate tables", zap.String("provider", s.config.Provider.Name()), zap.Error(err))
+	}
+	rates, err := s.repo.RatesFor(ctx, req.Destination.Country, req.Destination.Region, req.At)
+	if err != nil {
+		return nil, err
+	}
+	return calculator.Calculate(req, rates), nil
+}
+
+// validate checks the request and fills in the defaults
+func (s *TaxUseCase) validate(req *domain.CalculationRequest) error {
+	req.Currency = strings.ToUpper(req.Currency)
+	if req.Currency == "" {
+		req.Currency = s.config.Currency
+	}
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+	req.Destination.Country = strings.ToUpper(req.Destination.Country)
+	req.Destination.Region = strings.ToUpper(req.Destination.Region)
+	if !countryPattern.MatchString(req.Destination.Country) {
+		return invalid("destination country must be a two letter ISO 3166 code")
+	}
+	if !domain.ValidRegion(req.Destination.Region) {
+		return invalid("destination region must be up to 10 letters and digits")
+	}
+	if len(req.Lines) == 0 {
+		return invalid("at least one line is required")
+	}
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		if line.TaxClass == "" {
+			line.TaxClass = domain.DefaultTaxClass
+		}
+		switch {
+		case line.Quantity < 1:
+			return invalid(fmt.Sprintf("line %d: quantity must be at least 1", i+1))
+		case line.UnitPrice < 0:
+			return invalid(fmt.Sprintf("line %d: unitPrice must not be negative", i+1))
+		case line.Discount < 0 || line.Discount > float64(line.Quantity)*line.UnitPrice:
+			return invalid(fmt.Sprintf("line %d: discount must be between 0 and the line amount", i+1))
+		case !domain.ValidTaxClass(line.TaxClass):
+			return invalid(fmt.Sprintf("line %d: invalid taxClass %q", i+1, line.TaxClass))
+		}
+	}
+	return nil
+}
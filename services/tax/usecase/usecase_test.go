@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/services/tax/domain"
+	"ecommerce-microservice-go/services/tax/provider"
+	providermocks "ecommerce-microservice-go/services/tax/provider/mocks"
+	"ecommerce-microservice-go/services/tax/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+func TestTaxUseCaseCalculate(t *testing.T) {
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	request := func() domain.CalculationRequest {
+		return domain.CalculationRequest{Destination: domain.Destination{Country: "id", Region: "jk"}, At: at,
+			Lines: []domain.Line{{Reference: "7", Quantity: 2, UnitPrice: 50}}}
+	}
+	vat := domain.TaxRate{UUID: "rate-1", Jurisdiction: domain.Jurisdiction{Country: "ID"}, TaxClass: domain.DefaultTaxClass, Name: "VAT",
+		Rate: 11, ValidFrom: at.AddDate(-1, 0, 0)}
+	fromProvider := &domain.Calculation{Provider: "avalara", TaxTotal: 12}
+	errUnavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		req          func() domain.CalculationRequest
+		withProvider bool
+		fallback     bool
+		setup        func(repo *mocks.MockTaxRepositoryInterface, p *providermocks.MockProvider)
+		wantTax      float64
+		wantErr      error
+	}{
+		{
+			name: "rate tables",
+			req:  request,
+			setup: func(repo *mocks.MockTaxRepositoryInterface, _ *providermocks.MockProvider) {
+				repo.EXPECT().RatesFor(gomock.Any(), "ID", "JK", at).Return([]domain.TaxRate{vat}, nil)
+			},
+			wantTax: 11,
+		},
+		{
+			name:         "provider",
+			req:          request,
+			withProvider: true,
+			setup: func(_ *mocks.MockTaxRepositoryInterface, p *providermocks.MockProvider) {
+				p.EXPECT().Calculate(gomock.Any(), gomock.Any()).Return(fromProvider, nil)
+			},
+			wantTax: 12,
+		},
+		{
+			name:         "provider down falls back to the rate tables",
+			req:          request,
+			withProvider: true,
+			fallback:     true,
+			setup: func(repo *mocks.MockTaxRepositoryInterface, p *providermocks.MockProvider) {
+				p.EXPECT().Calculate(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+				repo.EXPECT().RatesFor(gomock.Any(), "ID", "JK", at).Return([]domain.TaxRate{vat}, nil)
+			},
+			wantTax: 11,
+		},
+		{
+			name:         "provider down without fallback",
+			req:          request,
+			withProvider: true,
+			setup: func(_ *mocks.MockTaxRepositoryInterface, p *providermocks.MockProvider) {
+				p.EXPECT().Calculate(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+			},
+			wantErr: domainErrors.NewAppErrorWithType(domainErrors.UnknownError),
+		},
+		{
+			name:         "rejected request does not fall back",
+			req:          request,
+			withProvider: true,
+			fallback:     true,
+			setup: func(_ *mocks.MockTaxRepositoryInterface, p *providermocks.MockProvider) {
+				p.EXPECT().Calculate(gomock.Any(), gomock.Any()).Return(nil, provider.ErrRejected)
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "discount above the line amount",
+			req: func() domain.CalculationRequest {
+				req := request()
+				req.Lines[0].Discount = 101
+				return req
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "unknown country format",
+			req: func() domain.CalculationRequest {
+				req := request()
+				req.Destination.Country = "IDN"
+				return req
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, p := mocks.NewMockTaxRepositoryInterface(ctrl), providermocks.NewMockProvider(ctrl)
+			p.EXPECT().Name().Return("avalara").AnyTimes()
+			if tt.setup != nil {
+				tt.setup(repo, p)
+			}
+			cfg := TaxConfig{Currency: "idr", Fallback: tt.fallback}
+			if tt.withProvider {
+				cfg.Provider = p
+			}
+			got, err := NewTaxUseCase(repo, cfg, testLogger).Calculate(context.Background(), tt.req())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.TaxTotal != tt.wantTax {
+				t.Errorf("tax = %v, want %v", got.TaxTotal, tt.wantTax)
+			}
+		})
+	}
+}
+
+func TestTaxUseCaseCreateRate(t *testing.T) {
+	acme := &domain.Jurisdiction{ID: 3, UUID: "jurisdiction-1", Country: "ID"}
+	tests := []struct {
+		name      string
+		rate      domain.TaxRate
+		setup     func(repo *mocks.MockTaxRepositoryInterface)
+		wantClass string
+		wantErr   error
+	}{
+		{
+			name: "default tax class",
+			rate: domain.TaxRate{Name: "VAT", Rate: 11},
+			setup: func(repo *mocks.MockTaxRepositoryInterface) {
+				repo.EXPECT().GetJurisdiction(gomock.Any(), "jurisdiction-1").Return(acme, nil)
+				repo.EXPECT().CreateRate(gomock.Any(), 3, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ int, r *domain.TaxRate) (*domain.TaxRate, error) { return r, nil })
+			},
+			wantClass: domain.DefaultTaxClass,
+		},
+		{
+			name:    "rate above 100",
+			rate:    domain.TaxRate{Name: "VAT", Rate: 110},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name:    "invalid tax class",
+			rate:    domain.TaxRate{Name: "VAT", Rate: 11, TaxClass: "Books!"},
+			wantErr: domainErrors.ErrValidation,
+		},
+		{
+			name: "unknown jurisdiction is a validation error",
+			rate: domain.TaxRate{Name: "VAT", Rate: 11},
+			setup: func(repo *mocks.MockTaxRepositoryInterface) {
+				repo.EXPECT().GetJurisdiction(gomock.Any(), "jurisdiction-1").Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockTaxRepositoryInterface(ctrl)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			rate := tt.rate
+			got, err := NewTaxUseCase(repo, TaxConfig{}, testLogger).CreateRate(context.Background(), "jurisdiction-1", &rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.TaxClass != tt.wantClass || got.ValidFrom.IsZero()) {
+				t.Errorf("rate = %+v, want class %q starting now", got, tt.wantClass)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/idempotency"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/services/user/repository"
+)
+
+type Config struct {
+	Server      config.Server
+	Telemetry   otel.Config
+	Log         logger.Config
+	CORS        *config.Live[config.CORS]
+	Security    config.SecurityHeaders
+	Database    config.Database
+	JWT         config.JWT
+	Features    featureflags.Config
+	Fixtures    fixtures.Config
+	Backup      backup.Config
+	Retention   retention.Config
+	IDs         config.IDs
+	Cache       cache.Config
+	RateLimit   *config.Live[config.RateLimit]
+	Idempotency idempotency.Config
+	Outbox      events.OutboxRelayConfig
+	// BrokerURL is optional; without it user events are only logged
+	BrokerURL      string
+	BrokerExchange string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:      config.LoadServer(src, "8081", 15*time.Second),
+		CORS:        config.Watch(src, "CORS", config.LoadCORS),
+		Security:    config.LoadSecurityHeaders(src),
+		Database:    config.LoadDatabase(src),
+		JWT:         config.LoadJWT(src, "user", true),
+		Features:    featureflags.LoadConfig(src),
+		Fixtures:    fixtures.LoadConfig(src),
+		Backup:      backup.LoadConfig(src),
+		Retention:   retention.LoadConfig(src, repository.RetentionRules),
+		IDs:         config.LoadIDs(src),
+		Cache:       cache.LoadConfig(src),
+		RateLimit:   config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Idempotency: idempotency.LoadConfig(src),
+		Outbox: events.OutboxRelayConfig{
+			Interval:  src.Duration("OUTBOX_RELAY_INTERVAL_MS", 2000, time.Millisecond),
+			BatchSize: src.Int("OUTBOX_RELAY_BATCH_SIZE", 100),
+		},
+		BrokerURL:      src.String("BROKER_URL", ""),
+		BrokerExchange: src.String("BROKER_EXCHANGE", events.DefaultExchange),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "user", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
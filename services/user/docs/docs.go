@@ -43,19 +43,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginResponse"
+                            "$ref": "#/definitions/controllers.Envelope-handler_LoginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -89,19 +89,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginResponse"
+                            "$ref": "#/definitions/controllers.Envelope-handler_LoginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -129,19 +129,25 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.NewUserRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-generated key, max 255 characters",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseUser"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseUser"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -162,20 +168,63 @@ const docTemplate = `{
                     "User"
                 ],
                 "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, email, firstName, lastName, createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ResponseUser"
-                            }
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -212,37 +261,163 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseUser"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseUser"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/user/{id}": {
+        "/user/tenants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a single user by their ID",
+                "description": "Retrieve the stores of the deployment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tenant"
                 ],
-                "summary": "Get user by ID",
+                "summary": "List tenants",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, prefix - for descending (id, name, createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (active, suspended)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-array_handler_ResponseTenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a store and its first admin in one step. Only admins of the default tenant manage tenants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Provision a tenant",
+                "parameters": [
+                    {
+                        "description": "Tenant and admin details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NewTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ProvisionTenantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "ID or host already taken",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/tenants/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a store by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Get a tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -252,56 +427,57 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseUser"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseTenant"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user fields by ID",
+                "description": "Rename a store, change or remove its host, or suspend it: the gateway stops routing to a suspended store\nwhile its data is kept. Send a JSON merge patch or a JSON Patch of the fields of TenantPatch.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tenant"
                 ],
-                "summary": "Update a user",
+                "summary": "Update a tenant",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
+                        "type": "string",
+                        "description": "Tenant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Fields to update",
+                        "description": "Fields to change",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handler.TenantPatch"
                         }
                     }
                 ],
@@ -309,19 +485,89 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseUser"
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseTenant"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Host already taken",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a single user by their ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -342,8 +588,8 @@ const docTemplate = `{
                 "summary": "Delete a user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
+                        "type": "string",
+                        "description": "User UUID, or integer ID while ID_ALLOW_INTEGER is on",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -353,19 +599,141 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.Envelope-controllers_MessageResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/controllers.MessageResponse"
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partial update of a user by ID: send a JSON merge patch of the fields to change, or a JSON Patch\n(application/json-patch+json) of add, replace and remove operations on them. Only the fields of UserPatch can be changed.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown, read-only or invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/notifications": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opt in or out of order lifecycle emails. Users can only change their own preferences unless they are staff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID, or integer ID while ID_ALLOW_INTEGER is on",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.Envelope-handler_ResponseUser"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ErrorEnvelope"
                         }
                     }
                 }
@@ -373,6 +741,103 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "controllers.Envelope-array_handler_ResponseTenant": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseTenant"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-array_handler_ResponseUser": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ResponseUser"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-controllers_MessageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controllers.MessageResponse"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_LoginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.LoginResponse"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ProvisionTenantResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ProvisionTenantResponse"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseTenant": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseTenant"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.Envelope-handler_ResponseUser": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ResponseUser"
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
+        "controllers.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.ErrorResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/controllers.Meta"
+                }
+            }
+        },
         "controllers.MessageResponse": {
             "type": "object",
             "properties": {
@@ -381,6 +846,119 @@ const docTemplate = `{
                 }
             }
         },
+        "controllers.Meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "errors.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "NOT_FOUND",
+                "VALIDATION_FAILED",
+                "ALREADY_EXISTS",
+                "NOT_AUTHENTICATED",
+                "NOT_AUTHORIZED",
+                "EXPIRED",
+                "PAYMENT_DECLINED",
+                "CSRF_TOKEN_INVALID",
+                "VERSION_CONFLICT",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "SERVICE_TIMEOUT",
+                "PAYLOAD_TOO_LARGE",
+                "RATE_LIMITED",
+                "REQUEST_IN_PROGRESS",
+                "IDEMPOTENCY_KEY_REUSED",
+                "USER_NOT_FOUND",
+                "EMAIL_CONFLICT",
+                "SKU_CONFLICT",
+                "INVALID_TRANSITION",
+                "REVIEW_EXISTS",
+                "PURCHASE_REQUIRED",
+                "TENANT_NOT_FOUND",
+                "TENANT_MISMATCH"
+            ],
+            "x-enum-varnames": [
+                "CodeNotFound",
+                "CodeValidation",
+                "CodeAlreadyExists",
+                "CodeNotAuthenticated",
+                "CodeNotAuthorized",
+                "CodeExpired",
+                "CodePaymentDeclined",
+                "CodeCSRFTokenInvalid",
+                "CodeVersionConflict",
+                "CodeInternal",
+                "CodeServiceUnavailable",
+                "CodeServiceTimeout",
+                "CodePayloadTooLarge",
+                "CodeRateLimited",
+                "CodeRequestInProgress",
+                "CodeIdempotencyKeyReused",
+                "CodeUserNotFound",
+                "CodeEmailConflict",
+                "CodeSKUConflict",
+                "CodeInvalidTransition",
+                "CodeReviewExists",
+                "CodePurchaseRequired",
+                "CodeTenantNotFound",
+                "CodeTenantMismatch"
+            ]
+        },
+        "errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/errors.ErrorCode"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.FieldError"
+                    }
+                },
+                "errorId": {
+                    "description": "ErrorID identifies a recovered panic in the logs, for users to quote when reporting it",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                }
+            }
+        },
+        "errors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.AccessTokenRequest": {
             "type": "object",
             "required": [
@@ -410,11 +988,58 @@ const docTemplate = `{
         "handler.LoginResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/handler.UserData"
-                },
                 "security": {
                     "$ref": "#/definitions/handler.SecurityData"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserData"
+                }
+            }
+        },
+        "handler.NewTenantAdminRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "userName"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.NewTenantRequest": {
+            "type": "object",
+            "required": [
+                "admin",
+                "id",
+                "name"
+            ],
+            "properties": {
+                "admin": {
+                    "$ref": "#/definitions/handler.NewTenantAdminRequest"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
@@ -446,6 +1071,51 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.NotificationPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "orderEmails"
+            ],
+            "properties": {
+                "orderEmails": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.ProvisionTenantResponse": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "$ref": "#/definitions/handler.ResponseUser"
+                },
+                "tenant": {
+                    "$ref": "#/definitions/handler.ResponseTenant"
+                }
+            }
+        },
+        "handler.ResponseTenant": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.ResponseUser": {
             "type": "object",
             "properties": {
@@ -464,6 +1134,12 @@ const docTemplate = `{
                 "lastName": {
                     "type": "string"
                 },
+                "orderEmails": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "boolean"
                 },
@@ -472,6 +1148,9 @@ const docTemplate = `{
                 },
                 "userName": {
                     "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
                 }
             }
         },
@@ -492,6 +1171,28 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.TenantPatch": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "x-nullable": true
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended"
+                    ],
+                    "x-nullable": true
+                }
+            }
+        },
         "handler.UserData": {
             "type": "object",
             "properties": {
@@ -507,11 +1208,43 @@ const docTemplate = `{
                 "lastName": {
                     "type": "string"
                 },
+                "role": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "boolean"
                 },
                 "userName": {
                     "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UserPatch": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "firstName": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "lastName": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "status": {
+                    "type": "boolean",
+                    "x-nullable": true
+                },
+                "userName": {
+                    "type": "string",
+                    "minLength": 1,
+                    "x-nullable": true
                 }
             }
         }
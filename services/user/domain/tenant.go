@@ -0,0 +1,22 @@
+package user
+
+import "time"
+
+type TenantStatus string
+
+const (
+	TenantActive TenantStatus = "active"
+	// TenantSuspended keeps the store's data but the gateway no longer routes to it
+	TenantSuspended TenantStatus = "suspended"
+)
+
+// Tenant is a store served by the deployment. ID is the slug clients send in X-Tenant-ID;
+// Host, when set, is the domain the gateway maps to the store.
+type Tenant struct {
+	ID        string
+	Name      string
+	Host      string
+	Status    TenantStatus
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
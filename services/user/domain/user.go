@@ -4,11 +4,15 @@ import "time"
 
 type User struct {
 	ID           int
+	UUID         string
+	Tenant       string
 	UserName     string
 	Email        string
 	FirstName    string
 	LastName     string
 	Status       bool
+	Role         string
+	OrderEmails  bool
 	HashPassword string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -6,9 +6,14 @@ import (
 	"strconv"
 	"time"
 
+	"ecommerce-microservice-go/pkg/config"
 	"ecommerce-microservice-go/pkg/controllers"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
 	userDomain "ecommerce-microservice-go/services/user/domain"
 	"ecommerce-microservice-go/services/user/usecase"
 
@@ -35,13 +40,36 @@ type NewUserRequest struct {
 	Status    bool   `json:"status"`
 }
 
+// UserPatch lists the user fields a partial update may change. Passwords, roles and
+// notification preferences have their own endpoints.
+type UserPatch struct {
+	UserName  *string `json:"userName" binding:"omitnil,min=1" patch:"user_name" extensions:"x-nullable"`
+	Email     *string `json:"email" binding:"omitnil,email" patch:"email" extensions:"x-nullable"`
+	FirstName *string `json:"firstName" patch:"first_name,nullable" extensions:"x-nullable"`
+	LastName  *string `json:"lastName" patch:"last_name,nullable" extensions:"x-nullable"`
+	Status    *bool   `json:"status" patch:"status" extensions:"x-nullable"`
+}
+
+type NotificationPreferencesRequest struct {
+	OrderEmails *bool `json:"orderEmails" binding:"required"`
+}
+
+type ResponseContact struct {
+	ID          int    `json:"id"`
+	Email       string `json:"email"`
+	FirstName   string `json:"firstName"`
+	OrderEmails bool   `json:"orderEmails"`
+}
+
 type UserData struct {
 	UserName  string `json:"userName"`
 	Email     string `json:"email"`
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 	Status    bool   `json:"status"`
+	Role      string `json:"role"`
 	ID        int    `json:"id"`
+	UUID      string `json:"uuid"`
 }
 
 type SecurityData struct {
@@ -52,29 +80,34 @@ type SecurityData struct {
 }
 
 type LoginResponse struct {
-	Data     UserData     `json:"data"`
+	User     UserData     `json:"user"`
 	Security SecurityData `json:"security"`
 }
 
 type ResponseUser struct {
-	ID        int       `json:"id"`
-	UserName  string    `json:"userName"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"firstName"`
-	LastName  string    `json:"lastName"`
-	Status    bool      `json:"status"`
-	CreatedAt time.Time `json:"createdAt,omitempty"`
-	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	ID          int       `json:"id"`
+	UUID        string    `json:"uuid"`
+	UserName    string    `json:"userName"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	Status      bool      `json:"status"`
+	Role        string    `json:"role"`
+	OrderEmails bool      `json:"orderEmails"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
 }
 
 type Handler struct {
-	authUseCase usecase.IAuthUseCase
-	userUseCase usecase.IUserUseCase
-	Logger      *logger.Logger
+	authUseCase   usecase.IAuthUseCase
+	userUseCase   usecase.IUserUseCase
+	tenantUseCase usecase.ITenantUseCase
+	ids           config.IDs
+	Logger        *logger.Logger
 }
 
-func NewHandler(auth usecase.IAuthUseCase, user usecase.IUserUseCase, l *logger.Logger) *Handler {
-	return &Handler{authUseCase: auth, userUseCase: user, Logger: l}
+func NewHandler(auth usecase.IAuthUseCase, user usecase.IUserUseCase, tenants usecase.ITenantUseCase, ids config.IDs, l *logger.Logger) *Handler {
+	return &Handler{authUseCase: auth, userUseCase: user, tenantUseCase: tenants, ids: ids, Logger: l}
 }
 
 // --- Auth handlers ---
@@ -86,16 +119,17 @@ func NewHandler(auth usecase.IAuthUseCase, user usecase.IUserUseCase, l *logger.
 // @Accept       json
 // @Produce      json
 // @Param        request body NewUserRequest true "User registration details"
-// @Success      200 {object} ResponseUser
-// @Failure      400 {object} controllers.MessageResponse
+// @Param        Idempotency-Key header string false "Client-generated key, max 255 characters"
+// @Success      200 {object} controllers.Envelope[ResponseUser]
+// @Failure      400 {object} controllers.ErrorEnvelope
 // @Router       /auth/register [post]
 func (h *Handler) Register(ctx *gin.Context) {
 	var request NewUserRequest
 	if err := controllers.BindJSON(ctx, &request); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	u, err := h.userUseCase.Create(&userDomain.User{
+	u, err := h.userUseCase.Create(ctx.Request.Context(), &userDomain.User{
 		UserName: request.UserName, Email: request.Email,
 		FirstName: request.FirstName, LastName: request.LastName,
 		HashPassword: request.Password, Status: true, // Auto-active for registration
@@ -104,7 +138,7 @@ func (h *Handler) Register(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, domainToResponseUser(u))
+	controllers.Respond(ctx, http.StatusOK, domainToResponseUser(u))
 }
 
 // Login godoc
@@ -114,23 +148,23 @@ func (h *Handler) Register(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequest true "Login credentials"
-// @Success      200 {object} LoginResponse
-// @Failure      400 {object} controllers.MessageResponse
-// @Failure      401 {object} controllers.MessageResponse
+// @Success      200 {object} controllers.Envelope[LoginResponse]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      401 {object} controllers.ErrorEnvelope
 // @Router       /auth/login [post]
 func (h *Handler) Login(ctx *gin.Context) {
 	var request LoginRequest
 	if err := controllers.BindJSON(ctx, &request); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	user, tokens, err := h.authUseCase.Login(request.Email, request.Password)
+	user, tokens, err := h.authUseCase.Login(ctx.Request.Context(), request.Email, request.Password)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, LoginResponse{
-		Data:     toUserData(user),
+	controllers.Respond(ctx, http.StatusOK, LoginResponse{
+		User:     toUserData(user),
 		Security: toSecurityData(tokens),
 	})
 }
@@ -142,23 +176,23 @@ func (h *Handler) Login(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        request body AccessTokenRequest true "Refresh token"
-// @Success      200 {object} LoginResponse
-// @Failure      400 {object} controllers.MessageResponse
-// @Failure      401 {object} controllers.MessageResponse
+// @Success      200 {object} controllers.Envelope[LoginResponse]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      401 {object} controllers.ErrorEnvelope
 // @Router       /auth/access-token [post]
 func (h *Handler) GetAccessTokenByRefreshToken(ctx *gin.Context) {
 	var request AccessTokenRequest
 	if err := controllers.BindJSON(ctx, &request); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	user, tokens, err := h.authUseCase.AccessTokenByRefreshToken(request.RefreshToken)
+	user, tokens, err := h.authUseCase.AccessTokenByRefreshToken(ctx.Request.Context(), request.RefreshToken)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, LoginResponse{
-		Data:     toUserData(user),
+	controllers.Respond(ctx, http.StatusOK, LoginResponse{
+		User:     toUserData(user),
 		Security: toSecurityData(tokens),
 	})
 }
@@ -173,16 +207,17 @@ func (h *Handler) GetAccessTokenByRefreshToken(ctx *gin.Context) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        request body NewUserRequest true "User details"
-// @Success      200 {object} ResponseUser
-// @Failure      400 {object} controllers.MessageResponse
+// @Success      200 {object} controllers.Envelope[ResponseUser]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
 // @Router       /user/ [post]
 func (h *Handler) NewUser(ctx *gin.Context) {
 	var request NewUserRequest
 	if err := controllers.BindJSON(ctx, &request); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+		_ = ctx.Error(validation.BindingError(err))
 		return
 	}
-	u, err := h.userUseCase.Create(&userDomain.User{
+	u, err := h.userUseCase.Create(ctx.Request.Context(), &userDomain.User{
 		UserName: request.UserName, Email: request.Email,
 		FirstName: request.FirstName, LastName: request.LastName,
 		HashPassword: request.Password, Status: request.Status,
@@ -191,7 +226,15 @@ func (h *Handler) NewUser(ctx *gin.Context) {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, domainToResponseUser(u))
+	controllers.Respond(ctx, http.StatusOK, domainToResponseUser(u))
+}
+
+var userListSpec = pagination.Spec{
+	Sortable: map[string]string{
+		"id": "id", "email": "email", "firstName": "first_name", "lastName": "last_name", "createdAt": "created_at",
+	},
+	Filterable:  map[string]string{"role": "role"},
+	DefaultSort: "id",
 }
 
 // GetAllUsers godoc
@@ -200,16 +243,28 @@ func (h *Handler) NewUser(ctx *gin.Context) {
 // @Tags         User
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200 {array} ResponseUser
-// @Failure      500 {object} controllers.MessageResponse
+// @Param        page   query int    false "Page number" default(1)
+// @Param        limit  query int    false "Page size" default(20)
+// @Param        sort   query string false "Comma separated fields, prefix - for descending (id, email, firstName, lastName, createdAt)"
+// @Param        role   query string false "Filter by role"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} controllers.Envelope[[]ResponseUser]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      500 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
 // @Router       /user/ [get]
 func (h *Handler) GetAllUsers(ctx *gin.Context) {
-	users, err := h.userUseCase.GetAll()
+	p, err := pagination.Parse(ctx, userListSpec)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, arrayDomainToResponse(users))
+	users, total, err := h.userUseCase.GetAll(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, arrayDomainToResponse(users), total, p)
 }
 
 // GetUserByID godoc
@@ -218,55 +273,151 @@ func (h *Handler) GetAllUsers(ctx *gin.Context) {
 // @Tags         User
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id path int true "User ID"
-// @Success      200 {object} ResponseUser
-// @Failure      400 {object} controllers.MessageResponse
-// @Failure      404 {object} controllers.MessageResponse
+// @Param        id     path  string true  "User UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} controllers.Envelope[ResponseUser]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      404 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
 // @Router       /user/{id} [get]
 func (h *Handler) GetUserByID(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.userUseCase.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid user id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	u, err := h.userUseCase.GetByID(id)
+	if !middleware.IsSelf(ctx, id) {
+		if err := middleware.Authorize(ctx, security.PermUserReadAny); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+	u, err := h.userUseCase.GetByID(ctx.Request.Context(), id)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, domainToResponseUser(u))
+	controllers.Respond(ctx, http.StatusOK, domainToResponseUser(u))
 }
 
 // UpdateUser godoc
 // @Summary      Update a user
-// @Description  Update user fields by ID
+// @Description  Partial update of a user by ID: send a JSON merge patch of the fields to change, or a JSON Patch
+// @Description  (application/json-patch+json) of add, replace and remove operations on them. Only the fields of UserPatch can be changed.
 // @Tags         User
 // @Accept       json
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id path int true "User ID"
-// @Param        request body map[string]interface{} true "Fields to update"
-// @Success      200 {object} ResponseUser
-// @Failure      400 {object} controllers.MessageResponse
-// @Failure      404 {object} controllers.MessageResponse
-// @Router       /user/{id} [put]
+// @Param        id path string true "User UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body UserPatch true "Fields to change"
+// @Success      200 {object} controllers.Envelope[ResponseUser]
+// @Failure      400 {object} controllers.ErrorEnvelope "Unknown, read-only or invalid fields"
+// @Failure      404 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Router       /user/{id} [patch]
 func (h *Handler) UpdateUser(ctx *gin.Context) {
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.userUseCase.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	var patch UserPatch
+	columns, err := controllers.BindPatch(ctx, &patch)
+	if err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	updated, err := h.userUseCase.Update(ctx.Request.Context(), id, columns)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, domainToResponseUser(updated))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary      Update notification preferences
+// @Description  Opt in or out of order lifecycle emails. Users can only change their own preferences unless they are staff.
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Param        request body NotificationPreferencesRequest true "Preferences"
+// @Success      200 {object} controllers.Envelope[ResponseUser]
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Router       /user/{id}/notifications [put]
+func (h *Handler) UpdateNotificationPreferences(ctx *gin.Context) {
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.userUseCase.ResolveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !middleware.IsSelf(ctx, id) && !middleware.HasRole(ctx, security.RoleStaff, security.RoleAdmin) {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("cannot change another user's preferences"), domainErrors.NotAuthorized))
+		return
+	}
+	var request NotificationPreferencesRequest
+	if err := controllers.BindJSON(ctx, &request); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	updated, err := h.userUseCase.UpdateNotificationPreferences(ctx.Request.Context(), id, *request.OrderEmails)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, domainToResponseUser(updated))
+}
+
+// GetUserContact returns the notification contact of a user for internal services.
+// It is only reachable with the internal token and is not routed by the gateway.
+func (h *Handler) GetUserContact(ctx *gin.Context) {
+	// services address users by the integer ID carried in tokens and events
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid user id"), domainErrors.ValidationError))
 		return
 	}
-	var requestMap map[string]any
-	if err := controllers.BindJSONMap(ctx, &requestMap); err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(err, domainErrors.ValidationError))
+	u, err := h.userUseCase.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		_ = ctx.Error(err)
 		return
 	}
-	updated, err := h.userUseCase.Update(id, requestMap)
+	controllers.Respond(ctx, http.StatusOK, ResponseContact{ID: u.ID, Email: u.Email, FirstName: u.FirstName, OrderEmails: u.OrderEmails})
+}
+
+// FindUserContact returns the notification contact of the user with the email, for internal
+// services that know users by email such as the order fixture seeder
+func (h *Handler) FindUserContact(ctx *gin.Context) {
+	email := ctx.Query("email")
+	if email == "" {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("email is required"), domainErrors.ValidationError))
+		return
+	}
+	u, err := h.userUseCase.GetByEmail(ctx.Request.Context(), email)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, ResponseContact{ID: u.ID, Email: u.Email, FirstName: u.FirstName, OrderEmails: u.OrderEmails})
+}
+
+// DeleteUserInternal deletes an account for the saga orchestrator once the user's orders are
+// closed. It is only reachable with the internal token; deleting a missing user answers 404.
+func (h *Handler) DeleteUserInternal(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
+		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid user id"), domainErrors.ValidationError))
+		return
+	}
+	if err := h.userUseCase.Delete(ctx.Request.Context(), id); err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, domainToResponseUser(updated))
+	ctx.Status(http.StatusNoContent)
 }
 
 // DeleteUser godoc
@@ -275,30 +426,31 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 // @Tags         User
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id path int true "User ID"
-// @Success      200 {object} controllers.MessageResponse
-// @Failure      400 {object} controllers.MessageResponse
-// @Failure      404 {object} controllers.MessageResponse
+// @Param        id path string true "User UUID, or integer ID while ID_ALLOW_INTEGER is on"
+// @Success      200 {object} controllers.Envelope[controllers.MessageResponse]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      404 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
 // @Router       /user/{id} [delete]
 func (h *Handler) DeleteUser(ctx *gin.Context) {
-	id, err := strconv.Atoi(ctx.Param("id"))
+	id, err := controllers.ParamID(ctx, "id", h.ids, h.userUseCase.ResolveID)
 	if err != nil {
-		_ = ctx.Error(domainErrors.NewAppError(errors.New("invalid user id"), domainErrors.ValidationError))
+		_ = ctx.Error(err)
 		return
 	}
-	if err := h.userUseCase.Delete(id); err != nil {
+	if err := h.userUseCase.Delete(ctx.Request.Context(), id); err != nil {
 		_ = ctx.Error(err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"message": "resource deleted successfully"})
+	controllers.RespondMessage(ctx, http.StatusOK, "resource deleted successfully")
 }
 
 // Mappers
 func domainToResponseUser(u *userDomain.User) ResponseUser {
 	return ResponseUser{
-		ID: u.ID, UserName: u.UserName, Email: u.Email,
-		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status,
-		CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
+		ID: u.ID, UUID: u.UUID, UserName: u.UserName, Email: u.Email,
+		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status, Role: u.Role,
+		OrderEmails: u.OrderEmails, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
 	}
 }
 
@@ -311,7 +463,7 @@ func arrayDomainToResponse(users *[]userDomain.User) []ResponseUser {
 }
 
 func toUserData(u *userDomain.User) UserData {
-	return UserData{UserName: u.UserName, Email: u.Email, FirstName: u.FirstName, LastName: u.LastName, Status: u.Status, ID: u.ID}
+	return UserData{UserName: u.UserName, Email: u.Email, FirstName: u.FirstName, LastName: u.LastName, Status: u.Status, Role: u.Role, ID: u.ID, UUID: u.UUID}
 }
 
 func toSecurityData(t *usecase.AuthTokens) SecurityData {
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/validation"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NewTenantAdminRequest struct {
+	UserName  string `json:"userName" binding:"required"`
+	Email     string `json:"email" binding:"required,email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Password  string `json:"password" binding:"required"`
+}
+
+// NewTenantRequest provisions a store: ID is the slug clients send in X-Tenant-ID and Host the
+// domain the gateway maps to it
+type NewTenantRequest struct {
+	ID    string                `json:"id" binding:"required"`
+	Name  string                `json:"name" binding:"required"`
+	Host  string                `json:"host" binding:"omitempty,hostname"`
+	Admin NewTenantAdminRequest `json:"admin" binding:"required"`
+}
+
+// TenantPatch lists the tenant fields a partial update may change
+type TenantPatch struct {
+	Name   *string `json:"name" binding:"omitnil,min=1" patch:"name" extensions:"x-nullable"`
+	Host   *string `json:"host" binding:"omitnil,omitempty,hostname" patch:"host,nullable" extensions:"x-nullable"`
+	Status *string `json:"status" binding:"omitnil,oneof=active suspended" patch:"status" extensions:"x-nullable"`
+}
+
+type ResponseTenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Host      string    `json:"host"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ProvisionTenantResponse struct {
+	Tenant ResponseTenant `json:"tenant"`
+	Admin  ResponseUser   `json:"admin"`
+}
+
+// --- Tenant handlers ---
+
+// ProvisionTenant godoc
+// @Summary      Provision a tenant
+// @Description  Create a store and its first admin in one step. Only admins of the default tenant manage tenants.
+// @Tags         Tenant
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body NewTenantRequest true "Tenant and admin details"
+// @Success      201 {object} controllers.Envelope[ProvisionTenantResponse]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Failure      409 {object} controllers.ErrorEnvelope "ID or host already taken"
+// @Router       /user/tenants [post]
+func (h *Handler) ProvisionTenant(ctx *gin.Context) {
+	var request NewTenantRequest
+	if err := controllers.BindJSONWithOptions(ctx, &request, controllers.BindOptions{DisallowUnknownFields: true}); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	t, admin, err := h.tenantUseCase.Provision(ctx.Request.Context(), &userDomain.Tenant{ID: request.ID, Name: request.Name, Host: request.Host},
+		&userDomain.User{
+			UserName: request.Admin.UserName, Email: request.Admin.Email,
+			FirstName: request.Admin.FirstName, LastName: request.Admin.LastName, HashPassword: request.Admin.Password,
+		})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusCreated, ProvisionTenantResponse{Tenant: domainToResponseTenant(t), Admin: domainToResponseUser(admin)})
+}
+
+var tenantListSpec = pagination.Spec{
+	Sortable:    map[string]string{"id": "id", "name": "name", "createdAt": "created_at"},
+	Filterable:  map[string]string{"status": "status"},
+	DefaultSort: "id",
+}
+
+// GetAllTenants godoc
+// @Summary      List tenants
+// @Description  Retrieve the stores of the deployment
+// @Tags         Tenant
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page   query int    false "Page number" default(1)
+// @Param        limit  query int    false "Page size" default(20)
+// @Param        sort   query string false "Comma separated fields, prefix - for descending (id, name, createdAt)"
+// @Param        status query string false "Filter by status (active, suspended)"
+// @Success      200 {object} controllers.Envelope[[]ResponseTenant]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Router       /user/tenants [get]
+func (h *Handler) GetAllTenants(ctx *gin.Context) {
+	p, err := pagination.Parse(ctx, tenantListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	tenants, total, err := h.tenantUseCase.GetAll(ctx.Request.Context(), p)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.RespondPage(ctx, arrayDomainToResponseTenant(tenants), total, p)
+}
+
+// GetTenant godoc
+// @Summary      Get a tenant
+// @Description  Retrieve a store by its ID
+// @Tags         Tenant
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Tenant ID"
+// @Success      200 {object} controllers.Envelope[ResponseTenant]
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Failure      404 {object} controllers.ErrorEnvelope
+// @Router       /user/tenants/{id} [get]
+func (h *Handler) GetTenant(ctx *gin.Context) {
+	t, err := h.tenantUseCase.GetByID(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, domainToResponseTenant(t))
+}
+
+// UpdateTenant godoc
+// @Summary      Update a tenant
+// @Description  Rename a store, change or remove its host, or suspend it: the gateway stops routing to a suspended store
+// @Description  while its data is kept. Send a JSON merge patch or a JSON Patch of the fields of TenantPatch.
+// @Tags         Tenant
+// @Accept       json
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Tenant ID"
+// @Param        request body TenantPatch true "Fields to change"
+// @Success      200 {object} controllers.Envelope[ResponseTenant]
+// @Failure      400 {object} controllers.ErrorEnvelope
+// @Failure      403 {object} controllers.ErrorEnvelope
+// @Failure      404 {object} controllers.ErrorEnvelope
+// @Failure      409 {object} controllers.ErrorEnvelope "Host already taken"
+// @Router       /user/tenants/{id} [patch]
+func (h *Handler) UpdateTenant(ctx *gin.Context) {
+	var patch TenantPatch
+	columns, err := controllers.BindPatch(ctx, &patch)
+	if err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	updated, err := h.tenantUseCase.Update(ctx.Request.Context(), ctx.Param("id"), columns)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, domainToResponseTenant(updated))
+}
+
+// GetActiveTenants returns the tenants the gateway routes to. It is only reachable with the
+// internal token and is not routed by the gateway.
+func (h *Handler) GetActiveTenants(ctx *gin.Context) {
+	tenants, err := h.tenantUseCase.GetActive(ctx.Request.Context())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	controllers.Respond(ctx, http.StatusOK, arrayDomainToResponseTenant(tenants))
+}
+
+func domainToResponseTenant(t *userDomain.Tenant) ResponseTenant {
+	return ResponseTenant{ID: t.ID, Name: t.Name, Host: t.Host, Status: string(t.Status), CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt}
+}
+
+func arrayDomainToResponseTenant(tenants *[]userDomain.Tenant) []ResponseTenant {
+	res := make([]ResponseTenant, len(*tenants))
+	for i := range *tenants {
+		res[i] = domainToResponseTenant(&(*tenants)[i])
+	}
+	return res
+}
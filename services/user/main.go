@@ -13,123 +13,217 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"os"
-	"time"
 
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/backup"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/controllers"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/idempotency"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
 	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
 	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/retention"
+	"ecommerce-microservice-go/pkg/scheduler"
 	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
 	"ecommerce-microservice-go/services/user/handler"
+	"ecommerce-microservice-go/services/user/migrations"
 	"ecommerce-microservice-go/services/user/repository"
 	"ecommerce-microservice-go/services/user/usecase"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	_ "ecommerce-microservice-go/services/user/docs"
 )
 
+const service app.Service = "user"
+
 func main() {
-	env := getEnvOrDefault("GO_ENV", "development")
-	var log *logger.Logger
-	var err error
-	if env == "development" {
-		log, err = logger.NewDevelopmentLogger()
-	} else {
-		log, err = logger.NewLogger()
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
 	}
+	log, err := logger.New(cfg.Log)
 	if err != nil {
 		panic(fmt.Errorf("error initializing logger: %w", err))
 	}
-	defer func() { _ = log.Log.Sync() }()
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
 
 	log.Info("Starting User Service")
 
-	// Connect to database
-	db, err := psql.ConnectDB(log)
-	if err != nil {
-		log.Panic("Failed to connect to database", zap.Error(err))
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
 	}
-
-	// Auto-migrate
-	if err := psql.AutoMigrate(db, log, &repository.User{}); err != nil {
-		log.Panic("Failed to migrate database", zap.Error(err))
+	if len(args) > 0 && args[0] == "snapshot" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.SnapshotCommand(args[1:])); err != nil {
+			log.Fatal("Snapshot command failed", zap.Error(err))
+		}
+		return
 	}
-
-	// Seed initial user
-	if err := repository.SeedInitialUser(db, log); err != nil {
-		log.Warn("Failed to seed initial user", zap.Error(err))
+	if len(args) > 0 && args[0] == "seed" {
+		err := app.RunCommand(service, log, cfg.Server, fx.Options(provide(cfg), fx.Invoke(app.EnsureSchema)), func(seeder fixtures.Seeder) error {
+			return fixtures.RunSeedCommand(context.Background(), cfg.Fixtures, args[1:], seeder, os.Stdout)
+		})
+		if err != nil {
+			log.Fatal("Seed command failed", zap.Error(err))
+		}
+		return
 	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(db *gorm.DB) {
+			if err := repository.SeedInitialUser(db, log); err != nil {
+				log.Warn("Failed to seed initial user", zap.Error(err))
+			}
+		}),
+		fx.Invoke(func(seeder fixtures.Seeder) error {
+			return fixtures.SeedOnStart(context.Background(), cfg.Fixtures, seeder, log)
+		}),
+		// committed registrations, updates and deletions go to the broker for webhook subscribers
+		fx.Invoke(func(lc fx.Lifecycle, relay *events.OutboxRelay) { app.Go(lc, relay.Run) }),
+		// deleted accounts are anonymized once their retention passes
+		fx.Invoke(func(lc fx.Lifecycle, db *gorm.DB) {
+			jobs := scheduler.New(log)
+			for _, job := range retention.Jobs(db, repository.RetentionRules, cfg.Retention, log) {
+				jobs.Add(job)
+			}
+			app.Go(lc, jobs.Run)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "User Service", cfg.Server, router)
+		}),
+	), log)
+}
 
-	// Dependencies
-	userRepo := repository.NewUserRepository(db, log)
-	jwtService := security.NewJWTService()
-	authUC := usecase.NewAuthUseCase(userRepo, jwtService, log)
-	userUC := usecase.NewUserUseCase(userRepo, log)
-	h := handler.NewHandler(authUC, userUC, log)
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.JWT, cfg.IDs, cfg.Features, cfg.Outbox),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Snapshots(repository.SnapshotTables),
+			app.Cache,
+			app.Audit,
+			app.Auditor,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Publisher, error) {
+				return app.BrokerPublisher(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			events.NewOutboxRelay,
+			psql.NewTxManager,
+			security.NewJWTService,
+			featureflags.NewFromConfig,
+			repository.NewUserRepository,
+			repository.NewTenantRepository,
+			usecase.NewAuthUseCase,
+			usecase.NewUserUseCase,
+			usecase.NewTenantUseCase,
+			usecase.NewFixtureSeeder,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
 
-	// Router
-	if env != "development" {
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, migrator *psql.Migrator,
+	publisher events.Publisher, flags *featureflags.Client, auditStore audit.Store, snapshots *backup.Snapshotter, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
 		log.SetupGinWithZapLogger()
 	} else {
 		log.SetupGinWithZapLoggerInDevelopment()
 	}
 
-	router := gin.New()
-	router.Use(gin.Recovery(), cors.Default())
-	router.Use(middleware.ErrorHandler())
-	router.Use(middleware.CommonHeaders)
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(controllers.UseEnvelope(), middleware.RequestID(), middleware.Recovery("user", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("user"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
 	router.Use(log.GinZapLogger())
+	router.Use(featureflags.Middleware(flags))
 
 	v1 := router.Group("/v1")
 
 	// Health
-	v1.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "user"})
-	})
+	probes := health.NewRegistry("user").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		Add("broker-publisher", events.BrokerCheck(publisher))
+	probes.RegisterRoutes(v1)
 
 	v1.GET("/user/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
 
 	// Auth routes (public)
 	auth := v1.Group("/auth")
+	auth.Use(middleware.RateLimit(cacheStore, "user:auth", cfg.RateLimit, middleware.KeyByRoute(middleware.KeyByIP), log))
 	auth.POST("/login", h.Login)
-	auth.POST("/register", h.Register)
+	auth.POST("/register", idempotency.Middleware(idempotency.NewCacheStore(cacheStore), "user", cfg.Idempotency, log), h.Register)
 	auth.POST("/access-token", h.GetAccessTokenByRefreshToken)
 
 	// User routes (protected)
 	user := v1.Group("/user")
-	user.Use(middleware.AuthJWTMiddleware())
+	user.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RateLimit(cacheStore, "user", cfg.RateLimit, middleware.KeyByUser, log))
 	{
-		user.GET("/", h.GetAllUsers)
-		user.POST("/", h.NewUser)
+		user.GET("/", middleware.RequirePermissions(security.PermUserReadAny), h.GetAllUsers)
+		user.POST("/", middleware.RequirePermissions(security.PermUserManage), h.NewUser)
 		user.GET("/:id", h.GetUserByID)
-		user.PUT("/:id", h.UpdateUser)
-		user.DELETE("/:id", h.DeleteUser)
+		user.PATCH("/:id", middleware.RequirePermissions(security.PermUserManage), h.UpdateUser)
+		// PUT predates PATCH and takes the same merge patch
+		user.PUT("/:id", middleware.RequirePermissions(security.PermUserManage), h.UpdateUser)
+		user.DELETE("/:id", middleware.RequirePermissions(security.PermUserManage), h.DeleteUser)
+		user.PUT("/:id/notifications", h.UpdateNotificationPreferences)
+		user.GET("/audit", middleware.RequirePermissions(security.PermAuditRead), audit.NewHandler(auditStore, log).List)
+		backup.NewHandler(snapshots, cfg.Backup, log).RegisterRoutes(user.Group("/snapshot", middleware.RequirePermissions(security.PermBackupManage)))
 	}
 
-	// Start server
-	port := getEnvOrDefault("SERVER_PORT", "8081")
-	log.Info("User Service starting", zap.String("port", port))
-	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-	}
-	if err := server.ListenAndServe(); err != nil {
-		log.Panic("Server failed", zap.Error(err))
+	// Tenant provisioning (admins of the default tenant)
+	tenants := user.Group("/tenants", middleware.RequirePermissions(security.PermTenantManage))
+	{
+		tenants.GET("", h.GetAllTenants)
+		tenants.POST("", h.ProvisionTenant)
+		tenants.GET("/:id", h.GetTenant)
+		tenants.PATCH("/:id", h.UpdateTenant)
 	}
-}
 
-func getEnvOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+		internal.GET("/feature-flags/:key", flags.Handler())
+		internal.PUT("/feature-flags/:key", flags.Handler())
+		internal.GET("/users", h.FindUserContact)
+		internal.GET("/users/:id/contact", h.GetUserContact)
+		internal.DELETE("/users/:id", h.DeleteUserInternal)
+		internal.GET("/tenants", h.GetActiveTenants)
 	}
-	return def
+
+	return router, nil
 }
@@ -0,0 +1,18 @@
+-- Baseline schema, matching what GORM AutoMigrate created before versioned migrations.
+-- IF NOT EXISTS lets databases created that way adopt it without changes.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial,
+    user_name text,
+    email text,
+    first_name text,
+    last_name text,
+    status boolean,
+    role text NOT NULL DEFAULT 'customer',
+    order_emails boolean NOT NULL DEFAULT true,
+    hash_password text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN created_by bigint,
+    ADD COLUMN updated_by bigint;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+-- soft-deleted rows keep their email, so only live rows have to be unique
+ALTER TABLE users DROP CONSTRAINT uni_users_email;
+CREATE UNIQUE INDEX uni_users_email ON users (email) WHERE deleted_at IS NULL;
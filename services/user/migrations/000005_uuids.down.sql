@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_uuid;
+ALTER TABLE users DROP COLUMN IF EXISTS uuid;
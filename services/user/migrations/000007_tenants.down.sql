@@ -0,0 +1,6 @@
+DELETE FROM users WHERE tenant_id <> 'default';
+DROP INDEX IF EXISTS uni_users_email;
+CREATE UNIQUE INDEX uni_users_email ON users (email) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_users_tenant_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS tenants;
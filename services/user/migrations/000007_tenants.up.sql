@@ -0,0 +1,23 @@
+-- Stores served by the deployment. The gateway resolves a request to a tenant by its host or
+-- X-Tenant-ID header and reads the active tenants from /v1/internal/tenants. Every existing
+-- user belongs to the default tenant.
+
+CREATE TABLE tenants (
+    id text,
+    name text NOT NULL,
+    host text,
+    status text NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_tenants_host ON tenants (host) WHERE host IS NOT NULL;
+
+INSERT INTO tenants (id, name, status, created_at, updated_at) VALUES ('default', 'Default', 'active', now(), now());
+
+ALTER TABLE users ADD COLUMN tenant_id text NOT NULL DEFAULT 'default' REFERENCES tenants (id);
+CREATE INDEX idx_users_tenant_id ON users (tenant_id);
+
+-- an email may register once per store
+DROP INDEX IF EXISTS uni_users_email;
+CREATE UNIQUE INDEX uni_users_email ON users (tenant_id, email) WHERE deleted_at IS NULL;
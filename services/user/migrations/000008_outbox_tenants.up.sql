@@ -0,0 +1,4 @@
+-- Events carry the tenant of the change they describe. Events queued before the column
+-- existed relay without one, as changes made across tenants.
+
+ALTER TABLE outbox_messages ADD COLUMN tenant_id text NOT NULL DEFAULT '';
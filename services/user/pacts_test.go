@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/featureflags"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/pact"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+	"ecommerce-microservice-go/services/user/handler"
+	"ecommerce-microservice-go/services/user/usecase"
+	"ecommerce-microservice-go/services/user/usecase/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var pactUser = userDomain.User{ID: 7, UUID: "5f0c7a8e-3f5c-4d52-9b7e-0d6f3c1e2a47", UserName: "buyer", Email: "buyer@example.com",
+	FirstName: "Budi", Status: true, Role: "customer", OrderEmails: true}
+
+// pactStates put the mocked use cases in the provider states the consumers' pacts name
+var pactStates = map[string]func(auth *mocks.MockIAuthUseCase, users *mocks.MockIUserUseCase, tenants *mocks.MockITenantUseCase){
+	"user 7 exists": func(_ *mocks.MockIAuthUseCase, users *mocks.MockIUserUseCase, _ *mocks.MockITenantUseCase) {
+		serveUsers(users, pactUser)
+	},
+	"no user has the email nobody@example.com": func(_ *mocks.MockIAuthUseCase, users *mocks.MockIUserUseCase, _ *mocks.MockITenantUseCase) {
+		serveUsers(users, pactUser)
+	},
+	"tenant acme is active on acme.example.com": func(_ *mocks.MockIAuthUseCase, _ *mocks.MockIUserUseCase, tenants *mocks.MockITenantUseCase) {
+		tenants.EXPECT().GetActive(gomock.Any()).Return(&[]userDomain.Tenant{
+			{ID: "default", Name: "Default", Status: userDomain.TenantActive},
+			{ID: "acme", Name: "Acme", Host: "acme.example.com", Status: userDomain.TenantActive},
+		}, nil).AnyTimes()
+	},
+	"user 7 signs in with secret123": func(auth *mocks.MockIAuthUseCase, _ *mocks.MockIUserUseCase, _ *mocks.MockITenantUseCase) {
+		auth.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, email, password string) (*userDomain.User, *usecase.AuthTokens, error) {
+				if email != pactUser.Email || password != "secret123" {
+					return nil, nil, domainErrors.NewAppError(errors.New("email or password does not match"), domainErrors.NotAuthenticated)
+				}
+				expires := time.Now().Add(time.Hour)
+				return &pactUser, &usecase.AuthTokens{AccessToken: "access-token", RefreshToken: "refresh-token",
+					ExpirationAccessDateTime: expires, ExpirationRefreshDateTime: expires}, nil
+			}).AnyTimes()
+	},
+}
+
+// serveUsers answers lookups by ID and email from users, like the repository does
+func serveUsers(m *mocks.MockIUserUseCase, users ...userDomain.User) {
+	find := func(match func(userDomain.User) bool) (*userDomain.User, error) {
+		for _, u := range users {
+			if match(u) {
+				return &u, nil
+			}
+		}
+		return nil, domainErrors.NewAppError(errors.New("user not found"), domainErrors.NotFound).WithCode(domainErrors.CodeUserNotFound)
+	}
+	m.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int) (*userDomain.User, error) {
+		return find(func(u userDomain.User) bool { return u.ID == id })
+	}).AnyTimes()
+	m.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, email string) (*userDomain.User, error) {
+		return find(func(u userDomain.User) bool { return u.Email == email })
+	}).AnyTimes()
+}
+
+// TestPacts replays the pacts of the user service's consumers against its router, with the
+// use cases mocked; the database, migrator and audit log are left out since no pact reaches them
+func TestPacts(t *testing.T) {
+	t.Setenv("INTERNAL_API_TOKEN", pact.InternalToken)
+	log, err := logger.New(logger.Config{Level: zap.WarnLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	telemetry, err := otel.Setup(context.Background(), otel.Config{ServiceName: string(service)}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := config.NewSource()
+	cfg := Config{CORS: config.Watch(src, "CORS", config.LoadCORS), Security: config.LoadSecurityHeaders(src), IDs: config.IDs{AllowIntegers: true}}
+	store := cache.NewMemoryStore()
+	flags := featureflags.NewFromConfig(featureflags.Config{}, store, nil, log)
+	publisher := events.NewLogPublisher(log)
+
+	err = pact.VerifyProvider(string(service), func(pt *pact.T, state string) (http.Handler, error) {
+		setup, ok := pactStates[state]
+		if !ok {
+			return nil, errors.New("unknown provider state")
+		}
+		ctrl := gomock.NewController(pt)
+		auth, users, tenants := mocks.NewMockIAuthUseCase(ctrl), mocks.NewMockIUserUseCase(ctrl), mocks.NewMockITenantUseCase(ctrl)
+		setup(auth, users, tenants)
+		h := handler.NewHandler(auth, users, tenants, cfg.IDs, log)
+		return newRouter(cfg, log, telemetry, nil, store, nil, publisher, flags, audit.NewDBStore(nil, string(service), log), nil, h)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import "ecommerce-microservice-go/pkg/retention"
+
+// SnapshotTables are the tables `user snapshot` exports and restores, parents first
+var SnapshotTables = []string{"tenants", "users"}
+
+// RetentionRules strip the personal details of deleted accounts. The rows stay, so orders and
+// the audit trail still resolve the user ID.
+var RetentionRules = []retention.Rule{
+	{
+		// irreversible, so off unless configured
+		Name:    "anonymize-deleted-users",
+		Setting: "RETENTION_DELETED_USERS_DAYS",
+		Table:   "users",
+		Column:  "deleted_at",
+		Where:   "email NOT LIKE 'deleted-%@anonymized.invalid'",
+		Anonymize: map[string]string{
+			"email":         "'deleted-' || uuid || '@anonymized.invalid'",
+			"user_name":     "'deleted-' || uuid",
+			"first_name":    "''",
+			"last_name":     "''",
+			"hash_password": "''",
+		},
+	},
+}
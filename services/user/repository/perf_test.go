@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/psql"
+)
+
+// BenchmarkArrayToDomainMapper maps a page of 20 users
+func BenchmarkArrayToDomainMapper(b *testing.B) {
+	b.ReportAllocs()
+	now := time.Now()
+	orderEmails := true
+	users := make([]User, 20)
+	for i := range users {
+		users[i] = User{ID: i + 1, UserName: "buyer", Email: "buyer@example.com", FirstName: "Budi", LastName: "Santoso", Status: true,
+			Role: "customer", OrderEmails: &orderEmails, HashPassword: "$2a$10$benchmarkbenchmarkbenchmarkbenchmarkbenchmarkbenchm",
+			BaseModel: psql.BaseModel{UUID: "5f0c7a8e-3f5c-4d52-9b7e-0d6f3c1e2a47", CreatedAt: now, UpdatedAt: now}}
+	}
+	for b.Loop() {
+		arrayToDomainMapper(&users)
+	}
+}
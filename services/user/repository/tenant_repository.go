@@ -0,0 +1,136 @@
+package repository
+
+//go:generate mockgen -source=tenant_repository.go -destination=mocks/tenant_repository.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Tenant struct {
+	ID        string    `gorm:"primaryKey"`
+	Name      string    `gorm:"column:name;not null"`
+	Host      *string   `gorm:"column:host;uniqueIndex"`
+	Status    string    `gorm:"column:status;not null;default:active"`
+	CreatedAt time.Time `gorm:"autoCreateTime:mili"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:mili"`
+}
+
+func (Tenant) TableName() string { return "tenants" }
+
+type TenantRepositoryInterface interface {
+	GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.Tenant, int64, error)
+	GetByID(ctx context.Context, id string) (*userDomain.Tenant, error)
+	// GetActive returns every tenant the gateway routes to
+	GetActive(ctx context.Context) (*[]userDomain.Tenant, error)
+	Create(ctx context.Context, t *userDomain.Tenant) (*userDomain.Tenant, error)
+	Update(ctx context.Context, id string, columns map[string]interface{}) (*userDomain.Tenant, error)
+}
+
+type TenantRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewTenantRepository(db *gorm.DB, l *logger.Logger) TenantRepositoryInterface {
+	return &TenantRepository{DB: db, Logger: l}
+}
+
+func (r *TenantRepository) GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.Tenant, int64, error) {
+	var tenants []Tenant
+	total, err := pagination.Find(psql.Conn(ctx, r.DB).Model(&Tenant{}), p, &tenants)
+	if err != nil {
+		r.Logger.Error("Error getting tenants", zap.Error(err))
+		return nil, 0, psql.TranslateError(err)
+	}
+	return tenantsToDomain(tenants), total, nil
+}
+
+func (r *TenantRepository) GetByID(ctx context.Context, id string) (*userDomain.Tenant, error) {
+	var t Tenant
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tenantNotFound()
+		}
+		return nil, psql.TranslateError(err)
+	}
+	return t.toDomain(), nil
+}
+
+func (r *TenantRepository) GetActive(ctx context.Context) (*[]userDomain.Tenant, error) {
+	var tenants []Tenant
+	if err := psql.Conn(ctx, r.DB).Where("status = ?", string(userDomain.TenantActive)).Order("id").Find(&tenants).Error; err != nil {
+		r.Logger.Error("Error getting active tenants", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return tenantsToDomain(tenants), nil
+}
+
+func (r *TenantRepository) Create(ctx context.Context, d *userDomain.Tenant) (*userDomain.Tenant, error) {
+	t := tenantFromDomain(d)
+	if err := psql.Conn(ctx, r.DB).Create(t).Error; err != nil {
+		return nil, translateTenantError(err)
+	}
+	return t.toDomain(), nil
+}
+
+func (r *TenantRepository) Update(ctx context.Context, id string, columns map[string]interface{}) (*userDomain.Tenant, error) {
+	// an empty host removes the domain; NULL keeps it out of the unique index
+	if host, ok := columns["host"]; ok && host == "" {
+		columns["host"] = nil
+	}
+	tx := psql.Conn(ctx, r.DB).Model(&Tenant{ID: id}).Updates(columns)
+	if tx.Error != nil {
+		return nil, translateTenantError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, tenantNotFound()
+	}
+	return r.GetByID(ctx, id)
+}
+
+func tenantNotFound() error {
+	return domainErrors.NewAppError(errors.New("tenant not found"), domainErrors.NotFound).WithCode(domainErrors.CodeTenantNotFound)
+}
+
+// translateTenantError reports a taken ID or host as a conflict
+func translateTenantError(err error) error {
+	if psql.IsUniqueViolation(err) {
+		return domainErrors.NewAppError(errors.New("tenant ID or host is already taken"), domainErrors.ResourceAlreadyExists)
+	}
+	return psql.TranslateError(err)
+}
+
+func (t *Tenant) toDomain() *userDomain.Tenant {
+	d := &userDomain.Tenant{ID: t.ID, Name: t.Name, Status: userDomain.TenantStatus(t.Status), CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt}
+	if t.Host != nil {
+		d.Host = *t.Host
+	}
+	return d
+}
+
+func tenantFromDomain(d *userDomain.Tenant) *Tenant {
+	t := &Tenant{ID: d.ID, Name: d.Name, Status: string(d.Status)}
+	if d.Host != "" {
+		t.Host = &d.Host
+	}
+	return t
+}
+
+func tenantsToDomain(tenants []Tenant) *[]userDomain.Tenant {
+	result := make([]userDomain.Tenant, len(tenants))
+	for i := range tenants {
+		result[i] = *tenants[i].toDomain()
+	}
+	return &result
+}
@@ -1,12 +1,18 @@
 package repository
 
+//go:generate mockgen -source=user_repository.go -destination=mocks/user_repository.go -package=mocks
+
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"os"
-	"time"
 
 	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
 	userDomain "ecommerce-microservice-go/services/user/domain"
 
 	"go.uber.org/zap"
@@ -15,15 +21,17 @@ import (
 )
 
 type User struct {
-	ID           int       `gorm:"primaryKey"`
-	UserName     string    `gorm:"column:user_name"`
-	Email        string    `gorm:"column:email;unique"`
-	FirstName    string    `gorm:"column:first_name"`
-	LastName     string    `gorm:"column:last_name"`
-	Status       bool      `gorm:"column:status"`
-	HashPassword string    `gorm:"column:hash_password"`
-	CreatedAt    time.Time `gorm:"autoCreateTime:mili"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime:mili"`
+	ID           int    `gorm:"primaryKey"`
+	UserName     string `gorm:"column:user_name"`
+	Email        string `gorm:"column:email;unique"`
+	FirstName    string `gorm:"column:first_name"`
+	LastName     string `gorm:"column:last_name"`
+	Status       bool   `gorm:"column:status"`
+	Role         string `gorm:"column:role;not null;default:customer"`
+	OrderEmails  *bool  `gorm:"column:order_emails;not null;default:true"`
+	HashPassword string `gorm:"column:hash_password"`
+	psql.BaseModel
+	psql.TenantModel
 }
 
 func (User) TableName() string {
@@ -31,12 +39,14 @@ func (User) TableName() string {
 }
 
 type UserRepositoryInterface interface {
-	GetAll() (*[]userDomain.User, error)
-	GetByID(id int) (*userDomain.User, error)
-	GetByEmail(email string) (*userDomain.User, error)
-	Create(user *userDomain.User) (*userDomain.User, error)
-	Update(id int, userMap map[string]interface{}) (*userDomain.User, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.User, int64, error)
+	GetByID(ctx context.Context, id int) (*userDomain.User, error)
+	IDByUUID(ctx context.Context, uuid string) (int, error)
+	GetByEmail(ctx context.Context, email string) (*userDomain.User, error)
+	Create(ctx context.Context, user *userDomain.User) (*userDomain.User, error)
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*userDomain.User, error)
+	Delete(ctx context.Context, id int) error
+	SaveOutboxEvent(ctx context.Context, event *events.Event) error
 }
 
 type Repository struct {
@@ -48,30 +58,39 @@ func NewUserRepository(db *gorm.DB, loggerInstance *logger.Logger) UserRepositor
 	return &Repository{DB: db, Logger: loggerInstance}
 }
 
-func (r *Repository) GetAll() (*[]userDomain.User, error) {
+func (r *Repository) GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.User, int64, error) {
 	var users []User
-	if err := r.DB.Find(&users).Error; err != nil {
+	total, err := pagination.Find(r.DB.WithContext(ctx).Model(&User{}), p, &users)
+	if err != nil {
 		r.Logger.Error("Error getting all users", zap.Error(err))
-		return nil, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return nil, 0, psql.TranslateError(err)
 	}
-	return arrayToDomainMapper(&users), nil
+	return arrayToDomainMapper(&users), total, nil
 }
 
-func (r *Repository) GetByID(id int) (*userDomain.User, error) {
+func (r *Repository) GetByID(ctx context.Context, id int) (*userDomain.User, error) {
 	var u User
-	err := r.DB.Where("id = ?", id).First(&u).Error
+	err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&u).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+			return &userDomain.User{}, userNotFound()
 		}
 		return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
 	}
 	return u.toDomainMapper(), nil
 }
 
-func (r *Repository) GetByEmail(email string) (*userDomain.User, error) {
+func (r *Repository) IDByUUID(ctx context.Context, publicID string) (int, error) {
+	id, err := psql.IDByUUID(r.DB.WithContext(ctx), &User{}, publicID)
+	if errors.Is(err, domainErrors.ErrNotFound) {
+		return 0, userNotFound()
+	}
+	return id, err
+}
+
+func (r *Repository) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
 	var u User
-	err := r.DB.Where("email = ?", email).First(&u).Error
+	err := r.DB.WithContext(ctx).Where("email = ?", email).First(&u).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
@@ -81,48 +100,60 @@ func (r *Repository) GetByEmail(email string) (*userDomain.User, error) {
 	return u.toDomainMapper(), nil
 }
 
-func (r *Repository) Create(uDomain *userDomain.User) (*userDomain.User, error) {
+func (r *Repository) Create(ctx context.Context, uDomain *userDomain.User) (*userDomain.User, error) {
 	u := fromDomainMapper(uDomain)
-	txResult := r.DB.Create(u)
+	txResult := psql.Conn(ctx, r.DB).Create(u)
 	if txResult.Error != nil {
-		byteErr, _ := json.Marshal(txResult.Error)
-		var newError domainErrors.GormErr
-		if errUnmarshal := json.Unmarshal(byteErr, &newError); errUnmarshal != nil {
-			return &userDomain.User{}, errUnmarshal
-		}
-		switch newError.Number {
-		case 1062:
-			return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.ResourceAlreadyExists)
-		default:
-			return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
-		}
+		return &userDomain.User{}, translateUserError(txResult.Error)
 	}
 	return u.toDomainMapper(), nil
 }
 
-func (r *Repository) Update(id int, userMap map[string]interface{}) (*userDomain.User, error) {
+func (r *Repository) Update(ctx context.Context, id int, userMap map[string]interface{}) (*userDomain.User, error) {
 	var u User
 	u.ID = id
-	if err := r.DB.Model(&u).Updates(userMap).Error; err != nil {
-		return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+	if err := psql.Conn(ctx, r.DB).Model(&u).Updates(userMap).Error; err != nil {
+		return &userDomain.User{}, translateUserError(err)
 	}
-	if err := r.DB.Where("id = ?", id).First(&u).Error; err != nil {
-		return &userDomain.User{}, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&u).Error; err != nil {
+		return &userDomain.User{}, userNotFound()
 	}
 	return u.toDomainMapper(), nil
 }
 
-func (r *Repository) Delete(id int) error {
-	tx := r.DB.Delete(&User{}, id)
+func (r *Repository) Delete(ctx context.Context, id int) error {
+	tx := psql.Conn(ctx, r.DB).Delete(&User{}, id)
 	if tx.Error != nil {
-		return domainErrors.NewAppErrorWithType(domainErrors.UnknownError)
+		return psql.TranslateError(tx.Error)
 	}
 	if tx.RowsAffected == 0 {
-		return domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+		return userNotFound()
 	}
 	return nil
 }
 
+// SaveOutboxEvent stores the event for the relay; call it within the write transaction
+func (r *Repository) SaveOutboxEvent(ctx context.Context, event *events.Event) error {
+	if err := events.SaveToOutbox(psql.Conn(ctx, r.DB), event); err != nil {
+		r.Logger.Error("Error saving outbox event", zap.String("type", event.Type), zap.Error(err))
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func userNotFound() error {
+	return domainErrors.NewAppError(errors.New("user not found"), domainErrors.NotFound).WithCode(domainErrors.CodeUserNotFound)
+}
+
+// translateUserError reports a duplicate email, the user's only unique column, with its own code
+func translateUserError(err error) error {
+	if psql.IsUniqueViolation(err) {
+		return domainErrors.NewAppError(errors.New("email is already registered"), domainErrors.ResourceAlreadyExists).
+			WithCode(domainErrors.CodeEmailConflict)
+	}
+	return psql.TranslateError(err)
+}
+
 // SeedInitialUser seeds the initial admin user from env vars
 func SeedInitialUser(db *gorm.DB, loggerInstance *logger.Logger) error {
 	email := os.Getenv("START_USER_EMAIL")
@@ -140,14 +171,15 @@ func SeedInitialUser(db *gorm.DB, loggerInstance *logger.Logger) error {
 	if err != nil {
 		return err
 	}
-	return db.Create(&User{Email: email, HashPassword: string(hashedPassword)}).Error
+	return db.Create(&User{Email: email, Role: security.RoleAdmin, HashPassword: string(hashedPassword)}).Error
 }
 
 // Mappers
 func (u *User) toDomainMapper() *userDomain.User {
 	return &userDomain.User{
-		ID: u.ID, UserName: u.UserName, Email: u.Email,
-		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status,
+		ID: u.ID, UUID: u.UUID, Tenant: u.TenantID, UserName: u.UserName, Email: u.Email,
+		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status, Role: u.Role,
+		OrderEmails:  u.OrderEmails == nil || *u.OrderEmails,
 		HashPassword: u.HashPassword, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
 	}
 }
@@ -155,8 +187,8 @@ func (u *User) toDomainMapper() *userDomain.User {
 func fromDomainMapper(u *userDomain.User) *User {
 	return &User{
 		ID: u.ID, UserName: u.UserName, Email: u.Email,
-		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status,
-		HashPassword: u.HashPassword,
+		FirstName: u.FirstName, LastName: u.LastName, Status: u.Status, Role: u.Role,
+		OrderEmails: &u.OrderEmails, HashPassword: u.HashPassword,
 	}
 }
 
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/fixtures"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+	"ecommerce-microservice-go/services/user/repository"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// FixtureSeeder upserts the users of a fixture set by email, publishing the same events as the
+// API so subscribers see demo users like any other
+type FixtureSeeder struct {
+	userRepository repository.UserRepositoryInterface
+	txManager      psql.TxManager
+	Logger         *logger.Logger
+}
+
+func NewFixtureSeeder(repo repository.UserRepositoryInterface, txManager psql.TxManager, l *logger.Logger) fixtures.Seeder {
+	return &FixtureSeeder{userRepository: repo, txManager: txManager, Logger: l}
+}
+
+func (s *FixtureSeeder) Seed(ctx context.Context, set *fixtures.Set) (fixtures.Result, error) {
+	var result fixtures.Result
+	for _, f := range set.Users {
+		role := f.Role
+		if role == "" {
+			role = security.RoleCustomer
+		}
+		if !security.IsRole(role) {
+			return result, fmt.Errorf("user %s: unknown role %q", f.Email, role)
+		}
+		active := f.Active == nil || *f.Active
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+			existing, err := s.userRepository.GetByEmail(ctx, f.Email)
+			if errors.Is(err, domainErrors.ErrNotFound) {
+				hash, err := bcrypt.GenerateFromPassword([]byte(f.Password), bcrypt.DefaultCost)
+				if err != nil {
+					return err
+				}
+				created, err := s.userRepository.Create(ctx, &userDomain.User{
+					Email: f.Email, UserName: f.UserName, FirstName: f.FirstName, LastName: f.LastName,
+					Role: role, Status: active, OrderEmails: true, HashPassword: string(hash),
+				})
+				if err != nil {
+					return err
+				}
+				result.Created++
+				return publishUser(ctx, s.userRepository, contracts.UserRegistered, created)
+			}
+			if err != nil {
+				return err
+			}
+			changes := map[string]interface{}{}
+			if existing.UserName != f.UserName {
+				changes["user_name"] = f.UserName
+			}
+			if existing.FirstName != f.FirstName {
+				changes["first_name"] = f.FirstName
+			}
+			if existing.LastName != f.LastName {
+				changes["last_name"] = f.LastName
+			}
+			if existing.Role != role {
+				changes["role"] = role
+			}
+			if existing.Status != active {
+				changes["status"] = active
+			}
+			if len(changes) == 0 {
+				result.Unchanged++
+				return nil
+			}
+			updated, err := s.userRepository.Update(ctx, existing.ID, changes)
+			if err != nil {
+				return err
+			}
+			result.Updated++
+			return publishUser(ctx, s.userRepository, contracts.UserUpdated, updated)
+		})
+		if err != nil {
+			return result, fmt.Errorf("user %s: %w", f.Email, err)
+		}
+	}
+	s.Logger.Info("Seeded users", zap.Int("created", result.Created), zap.Int("updated", result.Updated), zap.Int("unchanged", result.Unchanged))
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package usecase
+
+//go:generate mockgen -source=tenant_usecase.go -destination=mocks/tenant_usecase.go -package=mocks
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/tenant"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+	"ecommerce-microservice-go/services/user/repository"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// --- Tenant UseCase ---
+
+const auditEntityTenant = "tenant"
+
+type ITenantUseCase interface {
+	GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.Tenant, int64, error)
+	GetByID(ctx context.Context, id string) (*userDomain.Tenant, error)
+	// GetActive returns the tenants the gateway routes to, for service-to-service calls
+	GetActive(ctx context.Context) (*[]userDomain.Tenant, error)
+	// Provision creates the tenant and its first admin, who signs in to the new store, in one
+	// transaction
+	Provision(ctx context.Context, t *userDomain.Tenant, admin *userDomain.User) (*userDomain.Tenant, *userDomain.User, error)
+	Update(ctx context.Context, id string, columns map[string]interface{}) (*userDomain.Tenant, error)
+}
+
+// TenantUseCase manages the stores of the deployment. Only the operator, i.e. admins of the
+// default tenant, may; admins of a store manage nothing beyond it.
+type TenantUseCase struct {
+	tenantRepo repository.TenantRepositoryInterface
+	userRepo   repository.UserRepositoryInterface
+	txManager  psql.TxManager
+	auditor    audit.Auditor
+	Logger     *logger.Logger
+}
+
+func NewTenantUseCase(tenantRepo repository.TenantRepositoryInterface, userRepo repository.UserRepositoryInterface, txManager psql.TxManager,
+	auditor audit.Auditor, l *logger.Logger) ITenantUseCase {
+	return &TenantUseCase{tenantRepo: tenantRepo, userRepo: userRepo, txManager: txManager, auditor: auditor, Logger: l}
+}
+
+func (s *TenantUseCase) GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.Tenant, int64, error) {
+	if err := operatorOnly(ctx); err != nil {
+		return nil, 0, err
+	}
+	return s.tenantRepo.GetAll(ctx, p)
+}
+
+func (s *TenantUseCase) GetByID(ctx context.Context, id string) (*userDomain.Tenant, error) {
+	if err := operatorOnly(ctx); err != nil {
+		return nil, err
+	}
+	return s.tenantRepo.GetByID(ctx, id)
+}
+
+func (s *TenantUseCase) GetActive(ctx context.Context) (*[]userDomain.Tenant, error) {
+	return s.tenantRepo.GetActive(ctx)
+}
+
+func (s *TenantUseCase) Provision(ctx context.Context, t *userDomain.Tenant, admin *userDomain.User) (*userDomain.Tenant, *userDomain.User, error) {
+	if err := operatorOnly(ctx); err != nil {
+		return nil, nil, err
+	}
+	if !tenant.Valid(t.ID) {
+		return nil, nil, domainErrors.NewValidationError(errors.New("invalid tenant id"),
+			domainErrors.FieldError{Field: "id", Rule: "slug", Message: "id must be a lowercase slug of letters, digits and dashes, at most 63 characters"})
+	}
+	t.Host = strings.ToLower(t.Host)
+	t.Status = userDomain.TenantActive
+	s.Logger.Info("Provisioning tenant", zap.String("tenant", t.ID), zap.String("host", t.Host), zap.String("adminEmail", admin.Email))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(admin.HashPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, err
+	}
+	admin.HashPassword = string(hash)
+	admin.Role = security.RoleAdmin
+	admin.Status = true
+	admin.OrderEmails = true
+
+	var created *userDomain.Tenant
+	var createdAdmin *userDomain.User
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if created, err = s.tenantRepo.Create(ctx, t); err != nil {
+			return err
+		}
+		// the admin is a user of the new store
+		storeCtx := tenant.ContextWithTenant(ctx, created.ID)
+		if createdAdmin, err = s.userRepo.Create(storeCtx, admin); err != nil {
+			return err
+		}
+		return publishUser(storeCtx, s.userRepo, contracts.UserRegistered, createdAdmin)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionCreate, auditEntityTenant, created.ID, nil, created)
+	return created, createdAdmin, nil
+}
+
+func (s *TenantUseCase) Update(ctx context.Context, id string, columns map[string]interface{}) (*userDomain.Tenant, error) {
+	if err := operatorOnly(ctx); err != nil {
+		return nil, err
+	}
+	if status, ok := columns["status"]; ok && id == tenant.Default && status != string(userDomain.TenantActive) {
+		return nil, domainErrors.NewAppError(errors.New("the default tenant cannot be suspended"), domainErrors.ValidationError)
+	}
+	if host, ok := columns["host"].(string); ok {
+		columns["host"] = strings.ToLower(host)
+	}
+	s.Logger.Info("Updating tenant", zap.String("tenant", id))
+	before, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.tenantRepo.Update(ctx, id, columns)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityTenant, id, before, updated)
+	return updated, nil
+}
+
+// operatorOnly rejects callers of any tenant but the default one
+func operatorOnly(ctx context.Context) error {
+	if tenant.FromContext(ctx) != tenant.Default {
+		return domainErrors.NewAppError(errors.New("tenants are managed from the default tenant"), domainErrors.NotAuthorized)
+	}
+	return nil
+}
@@ -1,73 +1,171 @@
 package usecase
 
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
 import (
+	"context"
 	"errors"
 	"time"
 
+	"ecommerce-microservice-go/pkg/audit"
+	"ecommerce-microservice-go/pkg/contracts"
 	domainErrors "ecommerce-microservice-go/pkg/errors"
 	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
 	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/tenant"
 	userDomain "ecommerce-microservice-go/services/user/domain"
 	"ecommerce-microservice-go/services/user/repository"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // --- User UseCase ---
 
+const auditEntityUser = "user"
+
 type IUserUseCase interface {
-	GetAll() (*[]userDomain.User, error)
-	GetByID(id int) (*userDomain.User, error)
-	Create(user *userDomain.User) (*userDomain.User, error)
-	Update(id int, userMap map[string]interface{}) (*userDomain.User, error)
-	Delete(id int) error
+	GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.User, int64, error)
+	GetByID(ctx context.Context, id int) (*userDomain.User, error)
+	GetByEmail(ctx context.Context, email string) (*userDomain.User, error)
+	// ResolveID returns the integer ID of the user with the UUID
+	ResolveID(ctx context.Context, uuid string) (int, error)
+	Create(ctx context.Context, user *userDomain.User) (*userDomain.User, error)
+	Update(ctx context.Context, id int, userMap map[string]interface{}) (*userDomain.User, error)
+	UpdateNotificationPreferences(ctx context.Context, id int, orderEmails bool) (*userDomain.User, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type UserUseCase struct {
 	userRepository repository.UserRepositoryInterface
+	txManager      psql.TxManager
+	auditor        audit.Auditor
 	Logger         *logger.Logger
 }
 
-func NewUserUseCase(repo repository.UserRepositoryInterface, l *logger.Logger) IUserUseCase {
-	return &UserUseCase{userRepository: repo, Logger: l}
+func NewUserUseCase(repo repository.UserRepositoryInterface, txManager psql.TxManager, auditor audit.Auditor, l *logger.Logger) IUserUseCase {
+	return &UserUseCase{userRepository: repo, txManager: txManager, auditor: auditor, Logger: l}
 }
 
-func (s *UserUseCase) GetAll() (*[]userDomain.User, error) {
-	s.Logger.Info("Getting all users")
-	return s.userRepository.GetAll()
+func (s *UserUseCase) GetAll(ctx context.Context, p pagination.Params) (*[]userDomain.User, int64, error) {
+	s.Logger.Info("Getting all users", zap.Int("page", p.Page), zap.Int("limit", p.Limit))
+	return s.userRepository.GetAll(ctx, p)
 }
 
-func (s *UserUseCase) GetByID(id int) (*userDomain.User, error) {
+func (s *UserUseCase) GetByID(ctx context.Context, id int) (*userDomain.User, error) {
 	s.Logger.Info("Getting user by ID", zap.Int("id", id))
-	return s.userRepository.GetByID(id)
+	return s.userRepository.GetByID(ctx, id)
+}
+
+func (s *UserUseCase) GetByEmail(ctx context.Context, email string) (*userDomain.User, error) {
+	return s.userRepository.GetByEmail(ctx, email)
 }
 
-func (s *UserUseCase) Create(u *userDomain.User) (*userDomain.User, error) {
+func (s *UserUseCase) ResolveID(ctx context.Context, uuid string) (int, error) {
+	return s.userRepository.IDByUUID(ctx, uuid)
+}
+
+func (s *UserUseCase) Create(ctx context.Context, u *userDomain.User) (*userDomain.User, error) {
 	s.Logger.Info("Creating new user", zap.String("email", u.Email))
 	hash, err := bcrypt.GenerateFromPassword([]byte(u.HashPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 	u.HashPassword = string(hash)
-	return s.userRepository.Create(u)
+	if u.Role == "" {
+		u.Role = security.RoleCustomer
+	}
+	u.OrderEmails = true
+	var created *userDomain.User
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if created, err = s.userRepository.Create(ctx, u); err != nil {
+			return err
+		}
+		return publishUser(ctx, s.userRepository, contracts.UserRegistered, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionCreate, auditEntityUser, created.ID, nil, created)
+	return created, nil
 }
 
-func (s *UserUseCase) Update(id int, userMap map[string]interface{}) (*userDomain.User, error) {
+func (s *UserUseCase) Update(ctx context.Context, id int, userMap map[string]interface{}) (*userDomain.User, error) {
 	s.Logger.Info("Updating user", zap.Int("id", id))
-	return s.userRepository.Update(id, userMap)
+	// roles are only assigned through seeding or direct administration
+	delete(userMap, "role")
+	return s.update(ctx, id, userMap)
+}
+
+func (s *UserUseCase) UpdateNotificationPreferences(ctx context.Context, id int, orderEmails bool) (*userDomain.User, error) {
+	s.Logger.Info("Updating notification preferences", zap.Int("id", id), zap.Bool("orderEmails", orderEmails))
+	return s.update(ctx, id, map[string]interface{}{"order_emails": orderEmails})
 }
 
-func (s *UserUseCase) Delete(id int) error {
+// update applies the changes and records them in the audit trail
+func (s *UserUseCase) update(ctx context.Context, id int, userMap map[string]interface{}) (*userDomain.User, error) {
+	before, err := s.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var updated *userDomain.User
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if updated, err = s.userRepository.Update(ctx, id, userMap); err != nil {
+			return err
+		}
+		return publishUser(ctx, s.userRepository, contracts.UserUpdated, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, audit.ActionUpdate, auditEntityUser, id, before, updated)
+	return updated, nil
+}
+
+func (s *UserUseCase) Delete(ctx context.Context, id int) error {
 	s.Logger.Info("Deleting user", zap.Int("id", id))
-	return s.userRepository.Delete(id)
+	before, err := s.userRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, _ *gorm.DB) error {
+		if err := s.userRepository.Delete(ctx, id); err != nil {
+			return err
+		}
+		event, err := contracts.UserDeleted.NewEvent(tenant.ContextWithTenant(ctx, before.Tenant), contracts.User{ID: id, UUID: before.UUID})
+		if err != nil {
+			return err
+		}
+		return s.userRepository.SaveOutboxEvent(ctx, event)
+	})
+	if err != nil {
+		return err
+	}
+	s.auditor.Record(ctx, audit.ActionDelete, auditEntityUser, id, before, nil)
+	return nil
+}
+
+// publishUser queues the user's current profile for the relay; call it within the transaction
+// that changed the user
+func publishUser(ctx context.Context, repo repository.UserRepositoryInterface, contract contracts.Contract[contracts.User], u *userDomain.User) error {
+	event, err := contract.NewEvent(tenant.ContextWithTenant(ctx, u.Tenant), contracts.User{
+		ID: u.ID, UUID: u.UUID, UserName: u.UserName, Email: u.Email, FirstName: u.FirstName, LastName: u.LastName,
+		Status: u.Status, Role: u.Role, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return repo.SaveOutboxEvent(ctx, event)
 }
 
 // --- Auth UseCase ---
 
 type IAuthUseCase interface {
-	Login(email, password string) (*userDomain.User, *AuthTokens, error)
-	AccessTokenByRefreshToken(refreshToken string) (*userDomain.User, *AuthTokens, error)
+	Login(ctx context.Context, email, password string) (*userDomain.User, *AuthTokens, error)
+	AccessTokenByRefreshToken(ctx context.Context, refreshToken string) (*userDomain.User, *AuthTokens, error)
 }
 
 type AuthUseCase struct {
@@ -87,9 +185,9 @@ type AuthTokens struct {
 	ExpirationRefreshDateTime time.Time
 }
 
-func (s *AuthUseCase) Login(email, password string) (*userDomain.User, *AuthTokens, error) {
+func (s *AuthUseCase) Login(ctx context.Context, email, password string) (*userDomain.User, *AuthTokens, error) {
 	s.Logger.Info("User login attempt", zap.String("email", email))
-	user, err := s.UserRepository.GetByEmail(email)
+	user, err := s.UserRepository.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -101,11 +199,11 @@ func (s *AuthUseCase) Login(email, password string) (*userDomain.User, *AuthToke
 		return nil, nil, domainErrors.NewAppError(errors.New("email or password does not match"), domainErrors.NotAuthenticated)
 	}
 
-	accessToken, err := s.JWTService.GenerateJWTToken(user.ID, "access")
+	accessToken, err := s.JWTService.GenerateJWTToken(user.ID, user.Role, user.Tenant, "access")
 	if err != nil {
 		return nil, nil, err
 	}
-	refreshToken, err := s.JWTService.GenerateJWTToken(user.ID, "refresh")
+	refreshToken, err := s.JWTService.GenerateJWTToken(user.ID, user.Role, user.Tenant, "refresh")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -118,19 +216,23 @@ func (s *AuthUseCase) Login(email, password string) (*userDomain.User, *AuthToke
 	}, nil
 }
 
-func (s *AuthUseCase) AccessTokenByRefreshToken(refreshToken string) (*userDomain.User, *AuthTokens, error) {
+func (s *AuthUseCase) AccessTokenByRefreshToken(ctx context.Context, refreshToken string) (*userDomain.User, *AuthTokens, error) {
 	s.Logger.Info("Refreshing access token")
 	claimsMap, err := s.JWTService.GetClaimsAndVerifyToken(refreshToken, "refresh")
 	if err != nil {
 		return nil, nil, err
 	}
+	if requested := tenant.FromContext(ctx); requested != "" && security.ClaimTenant(claimsMap) != requested {
+		return nil, nil, domainErrors.NewAppError(errors.New("refresh token belongs to another tenant"), domainErrors.NotAuthorized).
+			WithCode(domainErrors.CodeTenantMismatch)
+	}
 	userID := int(claimsMap["id"].(float64))
-	user, err := s.UserRepository.GetByID(userID)
+	user, err := s.UserRepository.GetByID(ctx, userID)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	accessToken, err := s.JWTService.GenerateJWTToken(user.ID, "access")
+	accessToken, err := s.JWTService.GenerateJWTToken(user.ID, user.Role, user.Tenant, "access")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/audit"
+	auditmocks "ecommerce-microservice-go/pkg/audit/mocks"
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	psqlmocks "ecommerce-microservice-go/pkg/psql/mocks"
+	"ecommerce-microservice-go/pkg/security"
+	securitymocks "ecommerce-microservice-go/pkg/security/mocks"
+	"ecommerce-microservice-go/pkg/tenant"
+	userDomain "ecommerce-microservice-go/services/user/domain"
+	"ecommerce-microservice-go/services/user/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var testLogger = &logger.Logger{Log: zap.NewNop()}
+
+// inTransaction runs every transaction's function, as the real manager does when it commits
+func inTransaction(tx *psqlmocks.MockTxManager) {
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, *gorm.DB) error) error { return fn(ctx, nil) }).AnyTimes()
+}
+
+func TestAuthUseCaseLogin(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer := &userDomain.User{ID: 7, Email: "buyer@example.com", Role: security.RoleCustomer, Tenant: "acme", HashPassword: string(hash)}
+	tests := []struct {
+		name     string
+		password string
+		setup    func(repo *mocks.MockUserRepositoryInterface, jwt *securitymocks.MockIJWTService)
+		wantErr  error
+	}{
+		{
+			name:     "tokens for the user's tenant",
+			password: "secret123",
+			setup: func(repo *mocks.MockUserRepositoryInterface, jwt *securitymocks.MockIJWTService) {
+				repo.EXPECT().GetByEmail(gomock.Any(), buyer.Email).Return(buyer, nil)
+				jwt.EXPECT().GenerateJWTToken(7, security.RoleCustomer, "acme", security.Access).Return(&security.AppToken{Token: "access"}, nil)
+				jwt.EXPECT().GenerateJWTToken(7, security.RoleCustomer, "acme", security.Refresh).Return(&security.AppToken{Token: "refresh"}, nil)
+			},
+		},
+		{
+			name:     "wrong password",
+			password: "wrong",
+			setup: func(repo *mocks.MockUserRepositoryInterface, _ *securitymocks.MockIJWTService) {
+				repo.EXPECT().GetByEmail(gomock.Any(), buyer.Email).Return(buyer, nil)
+			},
+			wantErr: domainErrors.ErrNotAuthenticated,
+		},
+		{
+			name:     "unknown email",
+			password: "secret123",
+			setup: func(repo *mocks.MockUserRepositoryInterface, _ *securitymocks.MockIJWTService) {
+				repo.EXPECT().GetByEmail(gomock.Any(), buyer.Email).Return(&userDomain.User{}, nil)
+			},
+			wantErr: domainErrors.ErrNotAuthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, jwt := mocks.NewMockUserRepositoryInterface(ctrl), securitymocks.NewMockIJWTService(ctrl)
+			tt.setup(repo, jwt)
+			_, tokens, err := NewAuthUseCase(repo, jwt, testLogger).Login(context.Background(), buyer.Email, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (tokens.AccessToken != "access" || tokens.RefreshToken != "refresh") {
+				t.Errorf("tokens = %+v", tokens)
+			}
+		})
+	}
+}
+
+func TestAuthUseCaseAccessTokenByRefreshToken(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name    string
+		tenant  string
+		claims  map[string]any
+		setup   func(repo *mocks.MockUserRepositoryInterface, jwt *securitymocks.MockIJWTService)
+		wantErr error
+	}{
+		{
+			name:   "token of the requested tenant",
+			tenant: "acme",
+			claims: map[string]any{"id": 7.0, "tenant": "acme", "exp": float64(exp)},
+			setup: func(repo *mocks.MockUserRepositoryInterface, jwt *securitymocks.MockIJWTService) {
+				repo.EXPECT().GetByID(gomock.Any(), 7).Return(&userDomain.User{ID: 7, Role: security.RoleCustomer, Tenant: "acme"}, nil)
+				jwt.EXPECT().GenerateJWTToken(7, security.RoleCustomer, "acme", security.Access).Return(&security.AppToken{Token: "access"}, nil)
+			},
+		},
+		{
+			name:    "token of another tenant",
+			tenant:  "acme",
+			claims:  map[string]any{"id": 7.0, "tenant": "globex", "exp": float64(exp)},
+			wantErr: domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:    "default tenant token sent to a store",
+			tenant:  "acme",
+			claims:  map[string]any{"id": 7.0, "exp": float64(exp)},
+			wantErr: domainErrors.ErrNotAuthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, jwt := mocks.NewMockUserRepositoryInterface(ctrl), securitymocks.NewMockIJWTService(ctrl)
+			jwt.EXPECT().GetClaimsAndVerifyToken("refresh-token", security.Refresh).Return(tt.claims, nil)
+			if tt.setup != nil {
+				tt.setup(repo, jwt)
+			}
+			ctx := tenant.ContextWithTenant(context.Background(), tt.tenant)
+			_, tokens, err := NewAuthUseCase(repo, jwt, testLogger).AccessTokenByRefreshToken(ctx, "refresh-token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (tokens.RefreshToken != "refresh-token" || tokens.ExpirationRefreshDateTime.Unix() != exp) {
+				t.Errorf("tokens = %+v, want the same refresh token expiring at %d", tokens, exp)
+			}
+		})
+	}
+}
+
+func TestUserUseCaseUpdate(t *testing.T) {
+	before := &userDomain.User{ID: 7, Email: "buyer@example.com", Role: security.RoleCustomer}
+	after := &userDomain.User{ID: 7, Email: "buyer@example.com", FirstName: "Budi", Role: security.RoleCustomer}
+	// updates expects the changes to be written, queued and audited
+	updates := func(changes map[string]interface{}) func(repo *mocks.MockUserRepositoryInterface, auditor *auditmocks.MockAuditor) {
+		return func(repo *mocks.MockUserRepositoryInterface, auditor *auditmocks.MockAuditor) {
+			repo.EXPECT().GetByID(gomock.Any(), 7).Return(before, nil)
+			repo.EXPECT().Update(gomock.Any(), 7, changes).Return(after, nil)
+			repo.EXPECT().SaveOutboxEvent(gomock.Any(), eventOfType(contracts.UserUpdated.EventType())).Return(nil)
+			auditor.EXPECT().Record(gomock.Any(), audit.ActionUpdate, auditEntityUser, 7, before, after)
+		}
+	}
+	tests := []struct {
+		name    string
+		changes map[string]interface{}
+		setup   func(repo *mocks.MockUserRepositoryInterface, auditor *auditmocks.MockAuditor)
+		wantErr error
+	}{
+		{
+			name:    "profile changes are queued and audited",
+			changes: map[string]interface{}{"first_name": "Budi"},
+			setup:   updates(map[string]interface{}{"first_name": "Budi"}),
+		},
+		{
+			name:    "role is never changed",
+			changes: map[string]interface{}{"first_name": "Budi", "role": security.RoleAdmin},
+			setup:   updates(map[string]interface{}{"first_name": "Budi"}),
+		},
+		{
+			name:    "missing user",
+			changes: map[string]interface{}{"first_name": "Budi"},
+			setup: func(repo *mocks.MockUserRepositoryInterface, _ *auditmocks.MockAuditor) {
+				repo.EXPECT().GetByID(gomock.Any(), 7).Return(nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound))
+			},
+			wantErr: domainErrors.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo, tx, auditor := mocks.NewMockUserRepositoryInterface(ctrl), psqlmocks.NewMockTxManager(ctrl), auditmocks.NewMockAuditor(ctrl)
+			inTransaction(tx)
+			tt.setup(repo, auditor)
+			if _, err := NewUserUseCase(repo, tx, auditor, testLogger).Update(context.Background(), 7, tt.changes); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantUseCaseUpdate(t *testing.T) {
+	operator := tenant.ContextWithTenant(context.Background(), tenant.Default)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		id      string
+		columns map[string]interface{}
+		setup   func(repo *mocks.MockTenantRepositoryInterface, auditor *auditmocks.MockAuditor)
+		wantErr error
+	}{
+		{
+			name:    "host is stored lower case",
+			ctx:     operator,
+			id:      "acme",
+			columns: map[string]interface{}{"host": "Shop.Acme.Example"},
+			setup: func(repo *mocks.MockTenantRepositoryInterface, auditor *auditmocks.MockAuditor) {
+				before := &userDomain.Tenant{ID: "acme", Host: "acme.example.com"}
+				after := &userDomain.Tenant{ID: "acme", Host: "shop.acme.example"}
+				repo.EXPECT().GetByID(gomock.Any(), "acme").Return(before, nil)
+				repo.EXPECT().Update(gomock.Any(), "acme", map[string]interface{}{"host": "shop.acme.example"}).Return(after, nil)
+				auditor.EXPECT().Record(gomock.Any(), audit.ActionUpdate, auditEntityTenant, "acme", before, after)
+			},
+		},
+		{
+			name:    "only the default tenant manages tenants",
+			ctx:     tenant.ContextWithTenant(context.Background(), "acme"),
+			id:      "acme",
+			columns: map[string]interface{}{"status": string(userDomain.TenantSuspended)},
+			wantErr: domainErrors.ErrNotAuthorized,
+		},
+		{
+			name:    "default tenant cannot be suspended",
+			ctx:     operator,
+			id:      tenant.Default,
+			columns: map[string]interface{}{"status": string(userDomain.TenantSuspended)},
+			wantErr: domainErrors.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			tenants, users, tx, auditor := mocks.NewMockTenantRepositoryInterface(ctrl), mocks.NewMockUserRepositoryInterface(ctrl),
+				psqlmocks.NewMockTxManager(ctrl), auditmocks.NewMockAuditor(ctrl)
+			if tt.setup != nil {
+				tt.setup(tenants, auditor)
+			}
+			uc := NewTenantUseCase(tenants, users, tx, auditor, testLogger)
+			if _, err := uc.Update(tt.ctx, tt.id, tt.columns); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// eventOfType matches an outbox event of the type
+type eventOfType string
+
+func (t eventOfType) Matches(x any) bool {
+	e, ok := x.(*events.Event)
+	return ok && e.Type == string(t)
+}
+
+func (t eventOfType) String() string { return "is a " + string(t) + " event" }
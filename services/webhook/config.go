@@ -0,0 +1,61 @@
+package main
+
+import (
+	"time"
+
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/config"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/services/webhook/usecase"
+)
+
+type Config struct {
+	Server    config.Server
+	Telemetry otel.Config
+	Log       logger.Config
+	CORS      *config.Live[config.CORS]
+	Security  config.SecurityHeaders
+	Database  config.Database
+	JWT       config.JWT
+	Cache     cache.Config
+	RateLimit *config.Live[config.RateLimit]
+	Webhook   usecase.WebhookConfig
+	// DeliveryInterval is how often due deliveries are sent
+	DeliveryInterval time.Duration
+	BrokerURL        string
+	BrokerExchange   string
+}
+
+// loadConfig returns the settings and the positional arguments left after the flags
+func loadConfig(args []string) (Config, []string, error) {
+	src, err := config.Load(args)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cfg := Config{
+		Server:    config.LoadServer(src, "8088", 15*time.Second),
+		CORS:      config.Watch(src, "CORS", config.LoadCORS),
+		Security:  config.LoadSecurityHeaders(src),
+		Database:  config.LoadDatabase(src),
+		JWT:       config.LoadJWT(src, "webhook", false),
+		Cache:     cache.LoadConfig(src),
+		RateLimit: config.Watch(src, "RATE_LIMIT", config.LoadRateLimit),
+		Webhook: usecase.WebhookConfig{
+			MaxAttempts:         src.Int("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseBackoff:         src.Duration("WEBHOOK_BASE_BACKOFF_SECONDS", 30, time.Second),
+			MaxBackoff:          src.Duration("WEBHOOK_MAX_BACKOFF_SECONDS", 3600, time.Second),
+			BatchSize:           src.Int("WEBHOOK_BATCH_SIZE", 50),
+			Concurrency:         src.Int("WEBHOOK_CONCURRENCY", 8),
+			Timeout:             src.Duration("WEBHOOK_TIMEOUT_SECONDS", 10, time.Second),
+			AllowPrivateTargets: src.Bool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		DeliveryInterval: src.Duration("WEBHOOK_DELIVERY_INTERVAL_SECONDS", 5, time.Second),
+		BrokerURL:        src.Required("BROKER_URL"),
+		BrokerExchange:   src.String("BROKER_EXCHANGE", events.DefaultExchange),
+	}
+	cfg.Telemetry = otel.LoadConfig(src, "webhook", cfg.Server.Env)
+	cfg.Log = logger.LoadConfig(src, cfg.Server.Env)
+	return cfg, src.Args(), src.Err()
+}
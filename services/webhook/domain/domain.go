@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+)
+
+// EventTypes are the broker events integrators can subscribe to, by source service
+var EventTypes = slices.Concat(
+	contracts.Types(contracts.Produced("order-service")...),
+	contracts.Types(contracts.Produced("catalog-service")...),
+	contracts.Types(contracts.Produced("user-service")...),
+)
+
+// Requester is the caller of a public endpoint
+type Requester struct {
+	UserID int
+	// CanManageAny sees and manages every user's subscriptions
+	CanManageAny bool
+}
+
+// CanAccess reports whether the requester may manage the subscription
+func (r Requester) CanAccess(s *Subscription) bool {
+	return r.CanManageAny || s.OwnerID == r.UserID
+}
+
+// Subscription receives signed POSTs for the listed event types
+type Subscription struct {
+	ID         int
+	OwnerID    int
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDeadLettered deliveries ran out of attempts, or their subscription was
+	// deactivated; they stay stored until redelivered
+	DeliveryDeadLettered DeliveryStatus = "dead_lettered"
+)
+
+// Delivery is one event queued for one subscription. Attempts counts the attempts since it
+// was queued or last redelivered; the attempt log keeps all of them.
+type Delivery struct {
+	ID             int
+	SubscriptionID int
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	Redeliveries   int
+	NextAttemptAt  time.Time
+	DeliveredAt    *time.Time
+	DeadLetteredAt *time.Time
+	LastError      string
+	CreatedAt      time.Time
+	AttemptLog     []Attempt
+}
+
+// Attempt records one HTTP call made for a delivery
+type Attempt struct {
+	ID         int
+	DeliveryID int
+	Attempt    int
+	StatusCode int
+	Error      string
+	DurationMs int64
+	CreatedAt  time.Time
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservice-go/pkg/controllers"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/webhook/domain"
+	"ecommerce-microservice-go/services/webhook/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NewWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	Secret     string   `json:"secret" binding:"required,max=255"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1"`
+}
+
+type ResponseWebhook struct {
+	ID         int       `json:"id"`
+	OwnerID    int       `json:"ownerId"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type ResponseAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ResponseDelivery struct {
+	ID             int               `json:"id"`
+	EventID        string            `json:"eventId"`
+	EventType      string            `json:"eventType"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	Redeliveries   int               `json:"redeliveries"`
+	NextAttemptAt  *time.Time        `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *time.Time        `json:"deliveredAt,omitempty"`
+	DeadLetteredAt *time.Time        `json:"deadLetteredAt,omitempty"`
+	LastError      string            `json:"lastError,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	Payload        json.RawMessage   `json:"payload" swaggertype:"object"`
+	AttemptLog     []ResponseAttempt `json:"attemptLog"`
+}
+
+type Handler struct {
+	webhookUC usecase.IWebhookUseCase
+	Logger    *logger.Logger
+}
+
+func NewHandler(uc usecase.IWebhookUseCase, l *logger.Logger) *Handler {
+	return &Handler{webhookUC: uc, Logger: l}
+}
+
+var deliverySpec = pagination.Spec{
+	Sortable:    map[string]string{"id": "id", "createdAt": "created_at"},
+	Filterable:  map[string]string{"status": "status", "eventType": "event_type"},
+	DefaultSort: "-id",
+}
+
+// GetEventTypes godoc
+// @Summary      List subscribable event types
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Success      200 {array} string
+// @Router       /webhooks/event-types [get]
+func (h *Handler) GetEventTypes(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, domain.EventTypes)
+}
+
+// NewWebhook godoc
+// @Summary      Subscribe a webhook
+// @Description  Events of the chosen types are POSTed to the URL as the broker envelope {id, type, source, occurredAt, payload},
+// @Description  with an X-Webhook-Signature header "t=<unix>,v1=<hex HMAC-SHA256 of '<unix>.<body>' keyed by secret>".
+// @Description  Failed deliveries are retried with exponential backoff and dead-lettered once the attempts run out.
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Param        request body NewWebhookRequest true "Subscription"
+// @Success      201 {object} ResponseWebhook
+// @Failure      400 {object} domainErrors.ErrorResponse
+// @Router       /webhooks [post]
+func (h *Handler) NewWebhook(ctx *gin.Context) {
+	var req NewWebhookRequest
+	if err := controllers.BindJSON(ctx, &req); err != nil {
+		_ = ctx.Error(validation.BindingError(err))
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	sub, err := h.webhookUC.CreateSubscription(ctx.Request.Context(), req.URL, req.Secret, req.EventTypes, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, webhookToResponse(sub))
+}
+
+// GetWebhooks godoc
+// @Summary      List webhooks
+// @Description  Staff see their own subscriptions, admins every subscription
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Param        fields query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {array} ResponseWebhook
+// @Router       /webhooks [get]
+func (h *Handler) GetWebhooks(ctx *gin.Context) {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	subs, err := h.webhookUC.GetSubscriptions(ctx.Request.Context(), requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseWebhook, len(*subs))
+	for i := range *subs {
+		res[i] = webhookToResponse(&(*subs)[i])
+	}
+	controllers.RespondBare(ctx, http.StatusOK, res)
+}
+
+// DeleteWebhook godoc
+// @Summary      Unsubscribe a webhook
+// @Description  Deactivates the subscription; pending deliveries are dead-lettered and the delivery log is kept
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Param        webhookId path int true "Webhook ID"
+// @Success      204
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /webhooks/{webhookId} [delete]
+func (h *Handler) DeleteWebhook(ctx *gin.Context) {
+	id, err := paramInt(ctx, "webhookId")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if err := h.webhookUC.DeleteSubscription(ctx.Request.Context(), id, requester); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  Deliveries with every attempt made for them, newest first. Filter by status=dead_lettered for the dead letters.
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Param        webhookId path  int    true  "Webhook ID"
+// @Param        status    query string false "pending, succeeded or dead_lettered"
+// @Param        eventType query string false "Event type"
+// @Param        page      query int    false "Page number" default(1)
+// @Param        limit     query int    false "Page size" default(20)
+// @Param        sort      query string false "Comma separated fields, prefix - for descending (id, createdAt)" default(-id)
+// @Param        fields    query string false "Comma separated fields to return, e.g. id,name; a dotted name selects within a nested object"
+// @Success      200 {object} pagination.Response[ResponseDelivery]
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Router       /webhooks/{webhookId}/deliveries [get]
+func (h *Handler) GetDeliveries(ctx *gin.Context) {
+	id, err := paramInt(ctx, "webhookId")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	p, err := pagination.Parse(ctx, deliverySpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	deliveries, total, err := h.webhookUC.GetDeliveries(ctx.Request.Context(), id, p, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	res := make([]ResponseDelivery, len(*deliveries))
+	for i := range *deliveries {
+		res[i] = deliveryToResponse(&(*deliveries)[i])
+	}
+	controllers.RespondPageBare(ctx, res, total, p)
+}
+
+// Redeliver godoc
+// @Summary      Redeliver a webhook event
+// @Description  Queues a dead-lettered or delivered event for immediate delivery with a fresh set of attempts
+// @Tags         Webhook
+// @Security     BearerAuth
+// @Param        webhookId  path int true "Webhook ID"
+// @Param        deliveryId path int true "Delivery ID"
+// @Success      202 {object} ResponseDelivery
+// @Failure      404 {object} domainErrors.ErrorResponse
+// @Failure      409 {object} domainErrors.ErrorResponse "The delivery is still pending"
+// @Router       /webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *Handler) Redeliver(ctx *gin.Context) {
+	subscriptionID, err := paramInt(ctx, "webhookId")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	deliveryID, err := paramInt(ctx, "deliveryId")
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	d, err := h.webhookUC.Redeliver(ctx.Request.Context(), subscriptionID, deliveryID, requester)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, deliveryToResponse(d))
+}
+
+func paramInt(ctx *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil || id <= 0 {
+		return 0, domainErrors.NewAppError(errors.New("invalid "+name), domainErrors.ValidationError)
+	}
+	return id, nil
+}
+
+func requesterFromContext(ctx *gin.Context) (domain.Requester, error) {
+	userIDVal, exists := ctx.Get("userId")
+	if !exists {
+		return domain.Requester{}, domainErrors.NewAppError(errors.New("user id not found in token"), domainErrors.NotAuthenticated)
+	}
+	return domain.Requester{
+		UserID:       int(userIDVal.(float64)),
+		CanManageAny: middleware.HasPermission(ctx, security.PermWebhookManageAny),
+	}, nil
+}
+
+// Mappers
+func webhookToResponse(s *domain.Subscription) ResponseWebhook {
+	return ResponseWebhook{
+		ID: s.ID, OwnerID: s.OwnerID, URL: s.URL, EventTypes: s.EventTypes, Active: s.Active,
+		CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func deliveryToResponse(d *domain.Delivery) ResponseDelivery {
+	attempts := make([]ResponseAttempt, len(d.AttemptLog))
+	for i, a := range d.AttemptLog {
+		attempts[i] = ResponseAttempt{Attempt: a.Attempt, StatusCode: a.StatusCode, Error: a.Error, DurationMs: a.DurationMs, CreatedAt: a.CreatedAt}
+	}
+	res := ResponseDelivery{
+		ID: d.ID, EventID: d.EventID, EventType: d.EventType, Status: string(d.Status), Attempts: d.Attempts,
+		Redeliveries: d.Redeliveries, DeliveredAt: d.DeliveredAt, DeadLetteredAt: d.DeadLetteredAt, LastError: d.LastError,
+		CreatedAt: d.CreatedAt, Payload: d.Payload, AttemptLog: attempts,
+	}
+	if d.Status == domain.DeliveryPending {
+		next := d.NextAttemptAt
+		res.NextAttemptAt = &next
+	}
+	return res
+}
@@ -0,0 +1,159 @@
+// @title           Webhook Service API
+// @version         1.0.0
+// @description     Webhook microservice: signed outbound deliveries of order, catalog and user events to third-party integrators
+
+// @host            localhost:9090
+// @BasePath        /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"ecommerce-microservice-go/pkg/app"
+	"ecommerce-microservice-go/pkg/cache"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/health"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/metrics"
+	"ecommerce-microservice-go/pkg/middleware"
+	"ecommerce-microservice-go/pkg/otel"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/pkg/scheduler"
+	"ecommerce-microservice-go/pkg/security"
+	"ecommerce-microservice-go/pkg/validation"
+	"ecommerce-microservice-go/services/webhook/domain"
+	"ecommerce-microservice-go/services/webhook/handler"
+	"ecommerce-microservice-go/services/webhook/migrations"
+	"ecommerce-microservice-go/services/webhook/repository"
+	"ecommerce-microservice-go/services/webhook/usecase"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	_ "ecommerce-microservice-go/services/webhook/docs"
+)
+
+const service app.Service = "webhook"
+
+func main() {
+	cfg, args, err := loadConfig(os.Args[1:])
+	if err != nil {
+		panic(fmt.Errorf("error loading configuration: %w", err))
+	}
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		panic(fmt.Errorf("error initializing logger: %w", err))
+	}
+	defer func() { _ = log.Close() }()
+	defer log.ReloadOnSIGHUP()()
+
+	log.Info("Starting Webhook Service")
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := app.RunCommand(service, log, cfg.Server, provide(cfg), app.MigrateCommand(args[1:])); err != nil {
+			log.Fatal("Migration command failed", zap.Error(err))
+		}
+		return
+	}
+	app.Run(app.New(service, log, cfg.Server, provide(cfg),
+		fx.Invoke(app.EnsureSchema),
+		fx.Invoke(func(lc fx.Lifecycle, subscriber events.Subscriber, uc usecase.IWebhookUseCase) {
+			app.Subscribe(lc, subscriber, "webhook.deliveries", domain.EventTypes, uc.HandleEvent)
+			jobs := scheduler.New(log)
+			jobs.Add(scheduler.Job{
+				Name:     "deliver-webhooks",
+				Interval: cfg.DeliveryInterval,
+				Run:      uc.DeliverDue,
+			})
+			app.Go(lc, jobs.Run)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, s fx.Shutdowner, router *gin.Engine) {
+			app.ServeHTTP(lc, s, log, "Webhook Service", cfg.Server, router)
+		}),
+	), log)
+}
+
+// provide lists the constructors of the service; fx only builds what the invocations need
+func provide(cfg Config) fx.Option {
+	return fx.Options(
+		fx.Supply(cfg, cfg.Telemetry, cfg.Database, cfg.Cache, cfg.Webhook),
+		fx.Provide(
+			app.Telemetry,
+			app.Database,
+			app.Migrations(migrations.FS),
+			app.Cache,
+			func(lc fx.Lifecycle, log *logger.Logger) (events.Subscriber, error) {
+				return app.Subscriber(lc, cfg.BrokerURL, cfg.BrokerExchange, log)
+			},
+			repository.NewWebhookRepository,
+			usecase.NewWebhookUseCase,
+			handler.NewHandler,
+			newRouter,
+		),
+	)
+}
+
+func newRouter(cfg Config, log *logger.Logger, telemetry *otel.Telemetry, db *gorm.DB, cacheStore cache.Store, subscriber events.Subscriber,
+	migrator *psql.Migrator, h *handler.Handler) (*gin.Engine, error) {
+	if !cfg.Server.IsDevelopment() {
+		log.SetupGinWithZapLogger()
+	} else {
+		log.SetupGinWithZapLoggerInDevelopment()
+	}
+
+	if err := validation.Register(); err != nil {
+		return nil, fmt.Errorf("registering validation rules: %w", err)
+	}
+
+	router := app.Router(cfg.Server)
+	router.Use(middleware.RequestID(), middleware.Recovery("webhook", log), telemetry.Middleware(), middleware.CORS(cfg.CORS))
+	router.Use(metrics.Middleware("webhook"))
+	router.Use(middleware.ErrorHandler(log), middleware.ReadYourWrites())
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.Tenant())
+	router.Use(log.GinZapLogger())
+
+	v1 := router.Group("/v1")
+
+	health.NewRegistry("webhook").
+		Add("database", psql.DatabaseCheck(db)).
+		Add("cache", cache.StoreCheck(cacheStore)).
+		Add("broker", events.BrokerCheck(subscriber)).
+		Add("migrations", psql.MigrationStateCheck(migrator)).
+		RegisterRoutes(v1)
+
+	v1.GET("/webhooks/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", metrics.Handler())
+
+	webhooks := v1.Group("/webhooks")
+	webhooks.Use(middleware.AuthJWTMiddleware(cfg.JWT), middleware.RequirePermissions(security.PermWebhookManage),
+		middleware.RateLimit(cacheStore, "webhook", cfg.RateLimit, middleware.KeyByUser, log))
+	{
+		webhooks.GET("/event-types", h.GetEventTypes)
+		webhooks.GET("", h.GetWebhooks)
+		webhooks.POST("", h.NewWebhook)
+		webhooks.DELETE("/:webhookId", h.DeleteWebhook)
+		webhooks.GET("/:webhookId/deliveries", h.GetDeliveries)
+		webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", h.Redeliver)
+	}
+
+	// Internal routes (service-to-service, not exposed by the gateway)
+	internal := v1.Group("/internal")
+	internal.Use(middleware.InternalTokenMiddleware())
+	{
+		internal.GET("/log-level", log.LevelHandler())
+		internal.PUT("/log-level", log.LevelHandler())
+		internal.POST("/config/reload", log.ReloadHandler())
+	}
+
+	return router, nil
+}
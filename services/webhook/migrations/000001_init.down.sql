@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS attempts;
+DROP TABLE IF EXISTS deliveries;
+DROP TABLE IF EXISTS subscriptions;
@@ -0,0 +1,48 @@
+CREATE TABLE subscriptions (
+    id bigserial,
+    owner_id bigint NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    event_types text NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_subscriptions_owner_id ON subscriptions (owner_id);
+
+CREATE TABLE deliveries (
+    id bigserial,
+    subscription_id bigint NOT NULL,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    redeliveries bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    delivered_at timestamptz,
+    dead_lettered_at timestamptz,
+    last_error text,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_subscriptions_deliveries FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    CONSTRAINT chk_deliveries_status CHECK (status IN ('pending', 'succeeded', 'dead_lettered'))
+);
+CREATE INDEX idx_deliveries_due ON deliveries (status, next_attempt_at);
+CREATE INDEX idx_deliveries_subscription_status ON deliveries (subscription_id, status);
+-- the broker delivers at least once; the same event is queued once per subscription
+CREATE UNIQUE INDEX idx_deliveries_sub_event ON deliveries (subscription_id, event_id);
+
+CREATE TABLE attempts (
+    id bigserial,
+    delivery_id bigint NOT NULL,
+    attempt bigint NOT NULL,
+    status_code bigint,
+    error text,
+    duration_ms bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_deliveries_attempt_log FOREIGN KEY (delivery_id) REFERENCES deliveries(id)
+);
+CREATE INDEX idx_attempts_delivery_id ON attempts (delivery_id);
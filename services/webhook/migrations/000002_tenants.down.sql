@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_deliveries_tenant_id;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,9 @@
+-- Subscriptions belong to the tenant they were created in and only receive that tenant's
+-- events; deliveries belong to the tenant of their subscription. Rows created before tenants
+-- existed belong to the default tenant.
+
+ALTER TABLE subscriptions ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_subscriptions_tenant_id ON subscriptions (tenant_id);
+
+ALTER TABLE deliveries ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+CREATE INDEX idx_deliveries_tenant_id ON deliveries (tenant_id);
@@ -0,0 +1,290 @@
+package repository
+
+//go:generate mockgen -source=repository.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/psql"
+	"ecommerce-microservice-go/services/webhook/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Subscription struct {
+	ID         int       `gorm:"primaryKey"`
+	OwnerID    int       `gorm:"column:owner_id;not null;index"`
+	URL        string    `gorm:"column:url;not null"`
+	Secret     string    `gorm:"column:secret;not null"`
+	EventTypes string    `gorm:"column:event_types;not null"`
+	Active     bool      `gorm:"column:active;not null;default:true"`
+	CreatedAt  time.Time `gorm:"autoCreateTime:mili"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime:mili"`
+	psql.TenantModel
+}
+
+func (Subscription) TableName() string { return "subscriptions" }
+
+type Delivery struct {
+	ID             int        `gorm:"primaryKey"`
+	SubscriptionID int        `gorm:"column:subscription_id;not null"`
+	EventID        string     `gorm:"column:event_id;not null"`
+	EventType      string     `gorm:"column:event_type;not null"`
+	Payload        []byte     `gorm:"column:payload;type:jsonb;not null"`
+	Status         string     `gorm:"column:status;not null"`
+	Attempts       int        `gorm:"column:attempts;not null;default:0"`
+	Redeliveries   int        `gorm:"column:redeliveries;not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at;not null"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	DeadLetteredAt *time.Time `gorm:"column:dead_lettered_at"`
+	LastError      string     `gorm:"column:last_error"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime:mili"`
+	AttemptLog     []Attempt  `gorm:"foreignKey:DeliveryID"`
+	psql.TenantModel
+}
+
+func (Delivery) TableName() string { return "deliveries" }
+
+type Attempt struct {
+	ID         int       `gorm:"primaryKey"`
+	DeliveryID int       `gorm:"column:delivery_id;not null"`
+	Attempt    int       `gorm:"column:attempt;not null"`
+	StatusCode int       `gorm:"column:status_code"`
+	Error      string    `gorm:"column:error"`
+	DurationMs int64     `gorm:"column:duration_ms"`
+	CreatedAt  time.Time `gorm:"autoCreateTime:mili"`
+}
+
+func (Attempt) TableName() string { return "attempts" }
+
+type WebhookRepositoryInterface interface {
+	CreateSubscription(ctx context.Context, s *domain.Subscription) (*domain.Subscription, error)
+	GetSubscription(ctx context.Context, id int) (*domain.Subscription, error)
+	// GetSubscriptions lists the owner's subscriptions; ownerID 0 lists all
+	GetSubscriptions(ctx context.Context, ownerID int) (*[]domain.Subscription, error)
+	DeactivateSubscription(ctx context.Context, id int) error
+	// EnqueueForEvent queues the event for every active subscription of the context's tenant to
+	// its type. Queuing the same event twice is a no-op, so events the broker redelivers are not
+	// sent twice.
+	EnqueueForEvent(ctx context.Context, event *events.Event) (int, error)
+	// ClaimDue leases pending deliveries that are due so concurrent workers skip them
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) (*[]domain.Delivery, error)
+	// RecordAttempt logs the attempt and moves the delivery to status; a dead-lettered delivery
+	// keeps the attempt's error as its LastError
+	RecordAttempt(ctx context.Context, attempt *domain.Attempt, status domain.DeliveryStatus, nextAttemptAt time.Time) error
+	GetDelivery(ctx context.Context, id int) (*domain.Delivery, error)
+	GetDeliveries(ctx context.Context, subscriptionID int, p pagination.Params) (*[]domain.Delivery, int64, error)
+	// Redeliver queues a delivery that is not pending for immediate delivery with a fresh
+	// set of attempts, failing with a Conflict when it is still pending
+	Redeliver(ctx context.Context, id int) (*domain.Delivery, error)
+}
+
+type WebhookRepository struct {
+	DB     *gorm.DB
+	Logger *logger.Logger
+}
+
+func NewWebhookRepository(db *gorm.DB, l *logger.Logger) WebhookRepositoryInterface {
+	return &WebhookRepository{DB: db, Logger: l}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, d *domain.Subscription) (*domain.Subscription, error) {
+	s := Subscription{OwnerID: d.OwnerID, URL: d.URL, Secret: d.Secret, EventTypes: strings.Join(d.EventTypes, ","), Active: true}
+	if err := psql.Conn(ctx, r.DB).Create(&s).Error; err != nil {
+		r.Logger.Error("Error creating webhook subscription", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	return subscriptionToDomain(&s), nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id int) (*domain.Subscription, error) {
+	var s Subscription
+	if err := psql.Conn(ctx, r.DB).Where("id = ?", id).First(&s).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return subscriptionToDomain(&s), nil
+}
+
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context, ownerID int) (*[]domain.Subscription, error) {
+	query := psql.Conn(ctx, r.DB).Order("id")
+	if ownerID != 0 {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	var subs []Subscription
+	if err := query.Find(&subs).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Subscription, len(subs))
+	for i := range subs {
+		result[i] = *subscriptionToDomain(&subs[i])
+	}
+	return &result, nil
+}
+
+func (r *WebhookRepository) DeactivateSubscription(ctx context.Context, id int) error {
+	if err := psql.Conn(ctx, r.DB).Model(&Subscription{}).Where("id = ?", id).Update("active", false).Error; err != nil {
+		return psql.TranslateError(err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueForEvent(ctx context.Context, event *events.Event) (int, error) {
+	var subs []Subscription
+	if err := psql.Conn(ctx, r.DB).Where("active = ? AND event_types LIKE ?", true, "%"+escapeLike(event.Type)+"%").Find(&subs).Error; err != nil {
+		return 0, err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	var deliveries []Delivery
+	now := time.Now()
+	for _, s := range subs {
+		if !containsEventType(s.EventTypes, event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			SubscriptionID: s.ID, EventID: event.ID, EventType: event.Type, Payload: payload,
+			Status: string(domain.DeliveryPending), NextAttemptAt: now, TenantModel: s.TenantModel,
+		})
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	tx := psql.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries)
+	return int(tx.RowsAffected), tx.Error
+}
+
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) (*[]domain.Delivery, error) {
+	var claimed []Delivery
+	err := psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", string(domain.DeliveryPending), time.Now()).
+			Order("next_attempt_at").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+		ids := make([]int, len(claimed))
+		for i, d := range claimed {
+			ids[i] = d.ID
+		}
+		return tx.Model(&Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+	if err != nil {
+		r.Logger.Error("Error claiming webhook deliveries", zap.Error(err))
+		return nil, psql.TranslateError(err)
+	}
+	result := make([]domain.Delivery, len(claimed))
+	for i := range claimed {
+		result[i] = *deliveryToDomain(&claimed[i])
+	}
+	return &result, nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, a *domain.Attempt, status domain.DeliveryStatus, nextAttemptAt time.Time) error {
+	return psql.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		attempt := Attempt{DeliveryID: a.DeliveryID, Attempt: a.Attempt, StatusCode: a.StatusCode, Error: a.Error, DurationMs: a.DurationMs}
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+		fields := map[string]interface{}{"status": string(status), "attempts": a.Attempt, "next_attempt_at": nextAttemptAt, "last_error": a.Error}
+		switch status {
+		case domain.DeliverySucceeded:
+			fields["delivered_at"] = time.Now()
+		case domain.DeliveryDeadLettered:
+			fields["dead_lettered_at"] = time.Now()
+		}
+		return tx.Model(&Delivery{}).Where("id = ?", a.DeliveryID).Updates(fields).Error
+	})
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int) (*domain.Delivery, error) {
+	var d Delivery
+	if err := psql.Conn(ctx, r.DB).Preload("AttemptLog", orderAttempts).Where("id = ?", id).First(&d).Error; err != nil {
+		return nil, psql.TranslateError(err)
+	}
+	return deliveryToDomain(&d), nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionID int, p pagination.Params) (*[]domain.Delivery, int64, error) {
+	var deliveries []Delivery
+	query := psql.Conn(ctx, r.DB).Model(&Delivery{}).Preload("AttemptLog", orderAttempts).Where("subscription_id = ?", subscriptionID)
+	total, err := pagination.Find(query, p, &deliveries)
+	if err != nil {
+		return nil, 0, psql.TranslateError(err)
+	}
+	result := make([]domain.Delivery, len(deliveries))
+	for i := range deliveries {
+		result[i] = *deliveryToDomain(&deliveries[i])
+	}
+	return &result, total, nil
+}
+
+func (r *WebhookRepository) Redeliver(ctx context.Context, id int) (*domain.Delivery, error) {
+	tx := psql.Conn(ctx, r.DB).Model(&Delivery{}).Where("id = ? AND status <> ?", id, string(domain.DeliveryPending)).
+		Updates(map[string]interface{}{
+			"status": string(domain.DeliveryPending), "attempts": 0, "redeliveries": gorm.Expr("redeliveries + 1"),
+			"next_attempt_at": time.Now(), "dead_lettered_at": nil,
+		})
+	if tx.Error != nil {
+		return nil, psql.TranslateError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		// either the delivery does not exist or it is already waiting for its next attempt
+		if _, err := r.GetDelivery(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domainErrors.NewAppError(errors.New("delivery is already pending"), domainErrors.Conflict)
+	}
+	return r.GetDelivery(ctx, id)
+}
+
+func orderAttempts(db *gorm.DB) *gorm.DB { return db.Order("id") }
+
+func containsEventType(list, eventType string) bool {
+	for _, t := range strings.Split(list, ",") {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func subscriptionToDomain(s *Subscription) *domain.Subscription {
+	return &domain.Subscription{
+		ID: s.ID, OwnerID: s.OwnerID, URL: s.URL, Secret: s.Secret, EventTypes: strings.Split(s.EventTypes, ","),
+		Active: s.Active, CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func deliveryToDomain(d *Delivery) *domain.Delivery {
+	attempts := make([]domain.Attempt, len(d.AttemptLog))
+	for i, a := range d.AttemptLog {
+		attempts[i] = domain.Attempt{
+			ID: a.ID, DeliveryID: a.DeliveryID, Attempt: a.Attempt, StatusCode: a.StatusCode,
+			Error: a.Error, DurationMs: a.DurationMs, CreatedAt: a.CreatedAt,
+		}
+	}
+	return &domain.Delivery{
+		ID: d.ID, SubscriptionID: d.SubscriptionID, EventID: d.EventID, EventType: d.EventType, Payload: d.Payload,
+		Status: domain.DeliveryStatus(d.Status), Attempts: d.Attempts, Redeliveries: d.Redeliveries,
+		NextAttemptAt: d.NextAttemptAt, DeliveredAt: d.DeliveredAt, DeadLetteredAt: d.DeadLetteredAt,
+		LastError: d.LastError, CreatedAt: d.CreatedAt, AttemptLog: attempts,
+	}
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errPrivateTarget refuses webhook targets inside the deployment's own network
+var errPrivateTarget = errors.New("url must not point at a loopback, private or link-local address")
+
+// publicAddress reports whether ip may receive webhooks. Link-local addresses include the
+// cloud metadata endpoints.
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// checkTarget resolves host and refuses it when any of its addresses is not public
+func (s *WebhookUseCase) checkTarget(ctx context.Context, host string) error {
+	if s.config.AllowPrivateTargets {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicAddress(ip) {
+			return errPrivateTarget
+		}
+		return nil
+	}
+	addrs, err := s.lookupIP(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return errors.New("url host does not resolve")
+	}
+	for _, a := range addrs {
+		if !publicAddress(a.IP) {
+			return errPrivateTarget
+		}
+	}
+	return nil
+}
+
+// newDeliveryClient sends webhooks without following redirects and, unless allowPrivate, checks
+// the address it connects to again, as the host may resolve elsewhere since it was subscribed
+func newDeliveryClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicAddress(ip) {
+				return errPrivateTarget
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: timeout},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,299 @@
+package usecase
+
+//go:generate mockgen -source=usecase.go -destination=mocks/usecase.go -package=mocks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/events"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/pagination"
+	"ecommerce-microservice-go/pkg/retry"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/webhook/domain"
+	"ecommerce-microservice-go/services/webhook/repository"
+
+	"go.uber.org/zap"
+)
+
+type IWebhookUseCase interface {
+	CreateSubscription(ctx context.Context, rawURL, secret string, eventTypes []string, requester domain.Requester) (*domain.Subscription, error)
+	// GetSubscriptions returns the requester's subscriptions, or every subscription for those
+	// who can manage any
+	GetSubscriptions(ctx context.Context, requester domain.Requester) (*[]domain.Subscription, error)
+	// DeleteSubscription deactivates the subscription; its delivery log stays queryable
+	DeleteSubscription(ctx context.Context, id int, requester domain.Requester) error
+	GetDeliveries(ctx context.Context, subscriptionID int, p pagination.Params, requester domain.Requester) (*[]domain.Delivery, int64, error)
+	// Redeliver sends a dead-lettered or already delivered event again, with a fresh set of attempts
+	Redeliver(ctx context.Context, subscriptionID, deliveryID int, requester domain.Requester) (*domain.Delivery, error)
+	// HandleEvent queues a broker event for the subscriptions of its tenant to its type
+	HandleEvent(ctx context.Context, event *events.Event) error
+	// DeliverDue sends due deliveries and returns how many succeeded
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type WebhookConfig struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+	Concurrency int
+	Timeout     time.Duration
+	// AllowPrivateTargets lets subscriptions reach loopback, private and link-local addresses,
+	// for local development
+	AllowPrivateTargets bool
+}
+
+type WebhookUseCase struct {
+	repo     repository.WebhookRepositoryInterface
+	client   *http.Client
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+	config   WebhookConfig
+	Logger   *logger.Logger
+}
+
+func NewWebhookUseCase(r repository.WebhookRepositoryInterface, cfg WebhookConfig, l *logger.Logger) IWebhookUseCase {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return &WebhookUseCase{repo: r, client: newDeliveryClient(cfg.Timeout, cfg.AllowPrivateTargets), lookupIP: net.DefaultResolver.LookupIPAddr,
+		config: cfg, Logger: l}
+}
+
+const (
+	signatureHeader       = "X-Webhook-Signature"
+	minSecretLength       = 16
+	maxAttemptErrorLength = 500
+)
+
+func (s *WebhookUseCase) CreateSubscription(ctx context.Context, rawURL, secret string, eventTypes []string, requester domain.Requester) (*domain.Subscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, domainErrors.NewAppError(errors.New("url must be an absolute http(s) URL"), domainErrors.ValidationError)
+	}
+	if err := s.checkTarget(ctx, u.Hostname()); err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	if len(secret) < minSecretLength {
+		return nil, domainErrors.NewAppError(fmt.Errorf("secret must be at least %d characters", minSecretLength), domainErrors.ValidationError)
+	}
+	types, err := normalizeEventTypes(eventTypes)
+	if err != nil {
+		return nil, domainErrors.NewAppError(err, domainErrors.ValidationError)
+	}
+	s.Logger.Info("Creating webhook subscription", zap.Int("ownerID", requester.UserID), zap.String("host", u.Host))
+	return s.repo.CreateSubscription(ctx, &domain.Subscription{OwnerID: requester.UserID, URL: u.String(), Secret: secret, EventTypes: types})
+}
+
+func (s *WebhookUseCase) GetSubscriptions(ctx context.Context, requester domain.Requester) (*[]domain.Subscription, error) {
+	ownerID := requester.UserID
+	if requester.CanManageAny {
+		ownerID = 0
+	}
+	return s.repo.GetSubscriptions(ctx, ownerID)
+}
+
+func (s *WebhookUseCase) DeleteSubscription(ctx context.Context, id int, requester domain.Requester) error {
+	if _, err := s.ownedSubscription(ctx, id, requester); err != nil {
+		return err
+	}
+	s.Logger.Info("Deactivating webhook subscription", zap.Int("subscriptionID", id))
+	return s.repo.DeactivateSubscription(ctx, id)
+}
+
+func (s *WebhookUseCase) GetDeliveries(ctx context.Context, subscriptionID int, p pagination.Params, requester domain.Requester) (*[]domain.Delivery, int64, error) {
+	if _, err := s.ownedSubscription(ctx, subscriptionID, requester); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.GetDeliveries(ctx, subscriptionID, p)
+}
+
+func (s *WebhookUseCase) Redeliver(ctx context.Context, subscriptionID, deliveryID int, requester domain.Requester) (*domain.Delivery, error) {
+	sub, err := s.ownedSubscription(ctx, subscriptionID, requester)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, domainErrors.NewAppError(errors.New("subscription is deactivated"), domainErrors.ValidationError)
+	}
+	d, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.SubscriptionID != subscriptionID {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	s.Logger.Info("Redelivering webhook", zap.Int("deliveryID", deliveryID), zap.String("status", string(d.Status)))
+	return s.repo.Redeliver(ctx, deliveryID)
+}
+
+// ownedSubscription hides other users' subscriptions as not found
+func (s *WebhookUseCase) ownedSubscription(ctx context.Context, id int, requester domain.Requester) (*domain.Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanAccess(sub) {
+		return nil, domainErrors.NewAppErrorWithType(domainErrors.NotFound)
+	}
+	return sub, nil
+}
+
+func (s *WebhookUseCase) HandleEvent(ctx context.Context, event *events.Event) error {
+	if tenant.FromContext(ctx) == "" {
+		// events published before they carried a tenant all come from the default store;
+		// without one every tenant's subscriptions would receive them
+		ctx = tenant.ContextWithTenant(ctx, tenant.Default)
+	}
+	n, err := s.repo.EnqueueForEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.Logger.WithContext(ctx).Debug("Queued webhook deliveries", zap.String("event", event.Type), zap.Int("deliveries", n))
+	}
+	return nil
+}
+
+func (s *WebhookUseCase) DeliverDue(ctx context.Context) (int, error) {
+	// the lease outlasts the slowest possible attempt so a delivery is never sent twice at once
+	deliveries, err := s.repo.ClaimDue(ctx, s.config.BatchSize, 2*s.config.Timeout+time.Minute)
+	if err != nil {
+		return 0, err
+	}
+	subscriptions := map[int]*domain.Subscription{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.config.Concurrency)
+	succeeded := 0
+	for i := range *deliveries {
+		d := &(*deliveries)[i]
+		sub, ok := subscriptions[d.SubscriptionID]
+		if !ok {
+			if sub, err = s.repo.GetSubscription(ctx, d.SubscriptionID); err != nil {
+				s.Logger.Error("Error loading webhook subscription", zap.Int("subscriptionID", d.SubscriptionID), zap.Error(err))
+				continue
+			}
+			subscriptions[d.SubscriptionID] = sub
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if s.deliver(ctx, sub, d) {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return succeeded, nil
+}
+
+func (s *WebhookUseCase) deliver(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) bool {
+	attempt := &domain.Attempt{DeliveryID: d.ID, Attempt: d.Attempts + 1}
+	if !sub.Active {
+		attempt.Error = "subscription deactivated"
+		s.finish(ctx, attempt, domain.DeliveryDeadLettered, time.Now())
+		return false
+	}
+
+	start := time.Now()
+	statusCode, err := s.post(ctx, sub, d)
+	attempt.StatusCode, attempt.DurationMs = statusCode, time.Since(start).Milliseconds()
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		s.finish(ctx, attempt, domain.DeliverySucceeded, time.Now())
+		return true
+	}
+	if err != nil {
+		attempt.Error = truncate(err.Error(), maxAttemptErrorLength)
+	} else {
+		attempt.Error = fmt.Sprintf("unexpected status %d", statusCode)
+	}
+	if attempt.Attempt >= s.config.MaxAttempts {
+		s.Logger.Warn("Webhook delivery dead-lettered", zap.Int("deliveryID", d.ID), zap.Int("attempts", attempt.Attempt))
+		s.finish(ctx, attempt, domain.DeliveryDeadLettered, time.Now())
+		return false
+	}
+	s.finish(ctx, attempt, domain.DeliveryPending, time.Now().Add(retry.Backoff(attempt.Attempt, s.config.BaseBackoff, s.config.MaxBackoff)))
+	return false
+}
+
+func (s *WebhookUseCase) post(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ecommerce-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(d.ID))
+	req.Header.Set(signatureHeader, "t="+timestamp+",v1="+sign(sub.Secret, timestamp, d.Payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (s *WebhookUseCase) finish(ctx context.Context, attempt *domain.Attempt, status domain.DeliveryStatus, next time.Time) {
+	// the attempt has already been made, so record it even when shutdown cancelled ctx
+	if err := s.repo.RecordAttempt(context.WithoutCancel(ctx), attempt, status, next); err != nil {
+		s.Logger.Error("Error recording webhook attempt", zap.Int("deliveryID", attempt.DeliveryID), zap.Error(err))
+	}
+}
+
+// sign is HMAC-SHA256 over "<timestamp>.<body>"; receivers recompute it to verify the sender
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	known := map[string]bool{}
+	for _, t := range domain.EventTypes {
+		known[t] = true
+	}
+	seen := map[string]bool{}
+	var result []string
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if !known[t] {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	return result, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ecommerce-microservice-go/pkg/contracts"
+	domainErrors "ecommerce-microservice-go/pkg/errors"
+	"ecommerce-microservice-go/pkg/logger"
+	"ecommerce-microservice-go/pkg/tenant"
+	"ecommerce-microservice-go/services/webhook/domain"
+	"ecommerce-microservice-go/services/webhook/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var (
+	testLogger = &logger.Logger{Log: zap.NewNop()}
+	testConfig = WebhookConfig{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour, BatchSize: 10, Concurrency: 2, Timeout: time.Second}
+	owner      = domain.Requester{UserID: 7}
+)
+
+func TestWebhookUseCaseCreateSubscription(t *testing.T) {
+	created := contracts.OrderCreated.EventType()
+	tests := []struct {
+		name      string
+		url       string
+		secret    string
+		types     []string
+		wantTypes []string
+		wantErr   error
+	}{
+		{name: "event types are deduplicated", url: "https://hooks.example.com/orders", secret: "0123456789abcdef",
+			types: []string{created, " " + created}, wantTypes: []string{created}},
+		{name: "relative url", url: "/orders", secret: "0123456789abcdef", types: []string{created}, wantErr: domainErrors.ErrValidation},
+		{name: "short secret", url: "https://hooks.example.com", secret: "secret", types: []string{created}, wantErr: domainErrors.ErrValidation},
+		{name: "unknown event type", url: "https://hooks.example.com", secret: "0123456789abcdef", types: []string{"order.lost"},
+			wantErr: domainErrors.ErrValidation},
+		{name: "no event types", url: "https://hooks.example.com", secret: "0123456789abcdef", wantErr: domainErrors.ErrValidation},
+		{name: "loopback address", url: "http://127.0.0.1:8080/hook", secret: "0123456789abcdef", types: []string{created},
+			wantErr: domainErrors.ErrValidation},
+		{name: "metadata address", url: "http://169.254.169.254/latest", secret: "0123456789abcdef", types: []string{created},
+			wantErr: domainErrors.ErrValidation},
+		{name: "host resolving to a private address", url: "https://internal.example.com/hook", secret: "0123456789abcdef",
+			types: []string{created}, wantErr: domainErrors.ErrValidation},
+		{name: "host that does not resolve", url: "https://missing.example.com/hook", secret: "0123456789abcdef",
+			types: []string{created}, wantErr: domainErrors.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			if tt.wantErr == nil {
+				repo.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, s *domain.Subscription) (*domain.Subscription, error) { return s, nil })
+			}
+			uc := NewWebhookUseCase(repo, testConfig, testLogger).(*WebhookUseCase)
+			uc.lookupIP = func(_ context.Context, host string) ([]net.IPAddr, error) {
+				switch host {
+				case "hooks.example.com":
+					return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+				case "internal.example.com":
+					return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.5")}}, nil
+				}
+				return nil, errors.New("no such host")
+			}
+			got, err := uc.CreateSubscription(context.Background(), tt.url, tt.secret, tt.types, owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.OwnerID != 7 || strings.Join(got.EventTypes, ",") != strings.Join(tt.wantTypes, ",")) {
+				t.Errorf("subscription = %+v, want %v owned by 7", got, tt.wantTypes)
+			}
+		})
+	}
+}
+
+// inTenant matches a context scoped to the tenant
+type inTenant string
+
+func (m inTenant) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	return ok && tenant.FromContext(ctx) == string(m)
+}
+
+func (m inTenant) String() string { return "is a context of tenant " + string(m) }
+
+func TestWebhookUseCaseHandleEvent(t *testing.T) {
+	order := contracts.Order{OrderID: 12, OrderNumber: "ORD-12", UserID: 7, Status: "paid"}
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantTenant string
+	}{
+		{name: "queued for the subscriptions of the event's tenant", ctx: tenant.ContextWithTenant(context.Background(), "acme"), wantTenant: "acme"},
+		{name: "event without a tenant goes to the default tenant", ctx: context.Background(), wantTenant: tenant.Default},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := contracts.OrderPaid.NewEvent(tt.ctx, order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			repo.EXPECT().EnqueueForEvent(inTenant(tt.wantTenant), event).Return(1, nil)
+			if err := NewWebhookUseCase(repo, testConfig, testLogger).HandleEvent(event.Context(context.Background()), event); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestWebhookUseCaseRedeliver(t *testing.T) {
+	tests := []struct {
+		name         string
+		requester    domain.Requester
+		subscription domain.Subscription
+		delivery     *domain.Delivery
+		wantErr      error
+	}{
+		{name: "dead-lettered delivery", requester: owner, subscription: domain.Subscription{ID: 3, OwnerID: 7, Active: true},
+			delivery: &domain.Delivery{ID: 5, SubscriptionID: 3, Status: domain.DeliveryDeadLettered}},
+		{name: "another user's subscription", requester: domain.Requester{UserID: 8}, subscription: domain.Subscription{ID: 3, OwnerID: 7, Active: true},
+			wantErr: domainErrors.ErrNotFound},
+		{name: "deactivated subscription", requester: owner, subscription: domain.Subscription{ID: 3, OwnerID: 7},
+			wantErr: domainErrors.ErrValidation},
+		{name: "delivery of another subscription", requester: owner, subscription: domain.Subscription{ID: 3, OwnerID: 7, Active: true},
+			delivery: &domain.Delivery{ID: 5, SubscriptionID: 4}, wantErr: domainErrors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			repo.EXPECT().GetSubscription(gomock.Any(), 3).Return(&tt.subscription, nil)
+			if tt.delivery != nil {
+				repo.EXPECT().GetDelivery(gomock.Any(), 5).Return(tt.delivery, nil)
+			}
+			if tt.wantErr == nil {
+				repo.EXPECT().Redeliver(gomock.Any(), 5).Return(&domain.Delivery{ID: 5, SubscriptionID: 3, Status: domain.DeliveryPending}, nil)
+			}
+			if _, err := NewWebhookUseCase(repo, testConfig, testLogger).Redeliver(context.Background(), 3, 5, tt.requester); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookUseCaseDeliverDue(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		active        bool
+		attempts      int
+		wantStatus    domain.DeliveryStatus
+		wantRetry     bool
+		wantSucceeded int
+		// refusePrivate delivers without AllowPrivateTargets, so the test server is out of reach
+		refusePrivate bool
+	}{
+		{name: "accepted", status: http.StatusNoContent, active: true, wantStatus: domain.DeliverySucceeded, wantSucceeded: 1},
+		{name: "redirect is not followed", status: http.StatusFound, active: true, wantStatus: domain.DeliveryPending, wantRetry: true},
+		{name: "private address is refused when connecting", status: http.StatusNoContent, active: true, refusePrivate: true,
+			wantStatus: domain.DeliveryPending, wantRetry: true},
+		{name: "failure is retried later", status: http.StatusInternalServerError, active: true, wantStatus: domain.DeliveryPending, wantRetry: true},
+		{name: "last attempt is dead-lettered", status: http.StatusInternalServerError, active: true, attempts: 2, wantStatus: domain.DeliveryDeadLettered},
+		{name: "deactivated subscription is dead-lettered unsent", status: http.StatusNoContent, wantStatus: domain.DeliveryDeadLettered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(`{"orderId":12}`)
+			var sent atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent.Add(1)
+				if r.Header.Get("X-Webhook-Event") != "order.created" || !strings.Contains(r.Header.Get(signatureHeader), "v1=") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepositoryInterface(ctrl)
+			repo.EXPECT().ClaimDue(gomock.Any(), 10, gomock.Any()).Return(&[]domain.Delivery{
+				{ID: 5, SubscriptionID: 3, EventType: "order.created", Payload: payload, Attempts: tt.attempts},
+			}, nil)
+			repo.EXPECT().GetSubscription(gomock.Any(), 3).Return(&domain.Subscription{ID: 3, URL: server.URL, Secret: "0123456789abcdef", Active: tt.active}, nil)
+			before := time.Now()
+			repo.EXPECT().RecordAttempt(gomock.Any(), gomock.Any(), tt.wantStatus, gomock.Any()).DoAndReturn(
+				func(_ context.Context, a *domain.Attempt, _ domain.DeliveryStatus, next time.Time) error {
+					if a.Attempt != tt.attempts+1 {
+						t.Errorf("attempt %d, want %d", a.Attempt, tt.attempts+1)
+					}
+					if retried := next.Sub(before) >= testConfig.BaseBackoff; retried != tt.wantRetry {
+						t.Errorf("next attempt at %v, retry %v", next, tt.wantRetry)
+					}
+					return nil
+				})
+
+			cfg := testConfig
+			cfg.AllowPrivateTargets = !tt.refusePrivate
+			succeeded, err := NewWebhookUseCase(repo, cfg, testLogger).DeliverDue(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if succeeded != tt.wantSucceeded {
+				t.Errorf("succeeded %d, want %d", succeeded, tt.wantSucceeded)
+			}
+			if wantSent := tt.active && !tt.refusePrivate; wantSent != (sent.Load() == 1) {
+				t.Errorf("sent %d requests, want sent %v", sent.Load(), wantSent)
+			}
+		})
+	}
+}